@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/herbertgao/gaokao_bot/internal/config"
+	"github.com/herbertgao/gaokao_bot/internal/database"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// runCommand 执行子命令（如 calendar），args[0] 为子命令名称
+// ctx 在收到退出信号时取消，用于中断正在执行的数据库查询
+func runCommand(ctx context.Context, cfg *config.Config, logger *logrus.Logger, args []string) error {
+	switch args[0] {
+	case "calendar":
+		return runCalendarCommand(ctx, cfg, logger, args[1:])
+	case "migrate":
+		return runMigrateCommand(cfg, logger, args[1:])
+	case "backup":
+		return runBackupCommand(ctx, cfg, logger, args[1:])
+	case "restore":
+		return runRestoreCommand(ctx, cfg, logger, args[1:])
+	default:
+		return fmt.Errorf("未知子命令: %s（可用: calendar, migrate, backup, restore）", args[0])
+	}
+}
+
+// openCommandDatabase 为子命令打开数据库连接，migrate 为 true 时先执行未执行的迁移
+// 返回的关闭函数需由调用方在命令结束时执行
+func openCommandDatabase(cfg *config.Config, logger *logrus.Logger, migrate bool) (*gorm.DB, func(), error) {
+	db, err := database.NewDatabase(&cfg.Database)
+	if err != nil {
+		return nil, nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取数据库实例失败: %w", err)
+	}
+	closeDB := func() {
+		if err := sqlDB.Close(); err != nil {
+			logger.Errorf("关闭数据库连接失败: %v", err)
+		}
+	}
+
+	if migrate {
+		if _, err := database.Migrate(db); err != nil {
+			closeDB()
+			return nil, nil, fmt.Errorf("数据库迁移失败: %w", err)
+		}
+	}
+
+	return db, closeDB, nil
+}
+
+// runMigrateCommand 数据库迁移
+//
+//	migrate up
+//	migrate down [steps]
+//	migrate status
+func runMigrateCommand(cfg *config.Config, logger *logrus.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: migrate up|down [steps]|status")
+	}
+
+	db, closeDB, err := openCommandDatabase(cfg, logger, false)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		migrations, err := migrator.Up()
+		printMigrations("已执行", migrations)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("回滚步数必须为正整数: %s", args[1])
+			}
+		}
+		migrations, err := migrator.Down(steps)
+		printMigrations("已回滚", migrations)
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "未执行"
+			if status.Applied() {
+				state = "已执行 " + status.AppliedAt.In(util.GetBJTLocation()).Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("未知的 migrate 子命令: %s（可用: up, down, status）", args[0])
+	}
+}
+
+// printMigrations 输出本次执行或回滚的迁移
+func printMigrations(action string, migrations []database.Migration) {
+	if len(migrations) == 0 {
+		fmt.Println("没有需要处理的迁移")
+		return
+	}
+	for _, migration := range migrations {
+		fmt.Printf("%s %04d_%s\n", action, migration.Version, migration.Name)
+	}
+}
+
+// runBackupCommand 备份全部表到 gzip 压缩的 JSON 文件
+//
+//	backup [-o file]
+func runBackupCommand(ctx context.Context, cfg *config.Config, logger *logrus.Logger, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	output := fs.String("o", "", "Output file (default: gaokao_bot_backup_<time>.json.gz)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("gaokao_bot_backup_%s.json.gz", util.NowBJT().Format("20060102_150405"))
+	}
+
+	// 备份不修改数据库结构，因此不执行迁移
+	db, closeDB, err := openCommandDatabase(cfg, logger, false)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	backup, err := database.CreateBackup(ctx, db)
+	if err != nil {
+		return fmt.Errorf("备份失败: %w", err)
+	}
+
+	f, err := os.Create(path) // #nosec G304 -- 输出路径由运维人员通过命令行指定
+	if err != nil {
+		return fmt.Errorf("创建备份文件失败: %w", err)
+	}
+	if err := database.WriteBackup(f, backup); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("写出备份失败: %w", err)
+	}
+
+	printBackupTables(backup)
+	fmt.Fprintf(os.Stderr, "已备份到 %s\n", path)
+	return nil
+}
+
+// runRestoreCommand 从备份文件恢复全部表，恢复前会清空现有数据
+//
+//	restore [-dry-run] <file>
+func runRestoreCommand(ctx context.Context, cfg *config.Config, logger *logrus.Logger, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Show the backup contents without writing to the database")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("用法: restore [-dry-run] <file>")
+	}
+	path := fs.Arg(0)
+
+	f, err := os.Open(path) // #nosec G304 -- 输入路径由运维人员通过命令行指定
+	if err != nil {
+		return fmt.Errorf("打开备份文件失败: %w", err)
+	}
+	defer f.Close()
+
+	backup, err := database.ReadBackup(f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("备份时间 %s，程序版本 %s，结构版本 %d，来源 %s\n",
+		backup.CreatedAt.In(util.GetBJTLocation()).Format("2006-01-02 15:04:05"),
+		backup.AppVersion, backup.SchemaVersion, backup.Driver)
+	printBackupTables(backup)
+	if *dryRun {
+		return nil
+	}
+
+	// 恢复前确保表结构为最新版本
+	db, closeDB, err := openCommandDatabase(cfg, logger, true)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	if err := database.RestoreBackup(ctx, db, backup); err != nil {
+		return fmt.Errorf("恢复失败: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, "恢复完成")
+	return nil
+}
+
+// printBackupTables 输出备份中各表的行数
+func printBackupTables(backup *database.Backup) {
+	for _, table := range backup.Tables {
+		fmt.Printf("%s\t%d 行\n", table.Name, len(table.Rows))
+	}
+}
+
+// runCalendarCommand 考试日历导入导出
+//
+//	calendar export [-format json|yaml|csv] [-o file]
+//	calendar import [-format json|yaml|csv] [-dry-run] <file>
+func runCalendarCommand(ctx context.Context, cfg *config.Config, logger *logrus.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: calendar export|import [参数]")
+	}
+
+	db, closeDB, err := openCommandDatabase(cfg, logger, true)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	calendarService := service.NewExamCalendarService(repository.NewExamDateRepository(db))
+
+	switch args[0] {
+	case "export":
+		return runCalendarExport(ctx, calendarService, args[1:])
+	case "import":
+		return runCalendarImport(ctx, calendarService, args[1:])
+	default:
+		return fmt.Errorf("未知的 calendar 子命令: %s（可用: export, import）", args[0])
+	}
+}
+
+// runCalendarExport 导出考试日历到文件或标准输出
+func runCalendarExport(ctx context.Context, calendarService *service.ExamCalendarService, args []string) error {
+	fs := flag.NewFlagSet("calendar export", flag.ContinueOnError)
+	formatName := fs.String("format", "", "Output format: json, yaml, csv (default: inferred from -o, or json)")
+	output := fs.String("o", "", "Output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := resolveCalendarFormat(*formatName, *output)
+	if err != nil {
+		return err
+	}
+
+	cal, err := calendarService.Export(ctx)
+	if err != nil {
+		return fmt.Errorf("导出考试日历失败: %w", err)
+	}
+
+	if *output == "" {
+		return service.EncodeExamCalendar(os.Stdout, cal, format)
+	}
+
+	f, err := os.Create(*output) // #nosec G304 -- 输出路径由运维人员通过命令行指定
+	if err != nil {
+		return fmt.Errorf("创建输出文件失败: %w", err)
+	}
+	if err := service.EncodeExamCalendar(f, cal, format); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("写出考试日历失败: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("写出考试日历失败: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "已导出 %d 条考试到 %s\n", len(cal.Exams), *output)
+	return nil
+}
+
+// runCalendarImport 从文件导入考试日历
+func runCalendarImport(ctx context.Context, calendarService *service.ExamCalendarService, args []string) error {
+	fs := flag.NewFlagSet("calendar import", flag.ContinueOnError)
+	formatName := fs.String("format", "", "Input format: json, yaml, csv (default: inferred from file extension)")
+	dryRun := fs.Bool("dry-run", false, "Show differences without writing to the database")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("用法: calendar import [-format json|yaml|csv] [-dry-run] <file>")
+	}
+	path := fs.Arg(0)
+
+	format, err := resolveCalendarFormat(*formatName, path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path) // #nosec G304 -- 输入路径由运维人员通过命令行指定
+	if err != nil {
+		return fmt.Errorf("打开日历文件失败: %w", err)
+	}
+	defer f.Close()
+
+	cal, err := service.DecodeExamCalendar(f, format)
+	if err != nil {
+		return err
+	}
+
+	diff, err := calendarService.Import(ctx, cal, *dryRun)
+	if err != nil {
+		return fmt.Errorf("导入考试日历失败: %w", err)
+	}
+
+	fmt.Println(diff.String())
+	return nil
+}
+
+// resolveCalendarFormat 优先使用显式指定的格式，否则按文件扩展名推断，均未提供时默认 JSON
+func resolveCalendarFormat(name, path string) (service.CalendarFormat, error) {
+	if name != "" {
+		return service.ParseCalendarFormat(name)
+	}
+	if path != "" {
+		return service.CalendarFormatFromPath(path)
+	}
+	return service.CalendarFormatJSON, nil
+}
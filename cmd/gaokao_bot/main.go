@@ -14,7 +14,6 @@ import (
 	"github.com/herbertgao/gaokao_bot/internal/bot"
 	"github.com/herbertgao/gaokao_bot/internal/config"
 	"github.com/herbertgao/gaokao_bot/internal/database"
-	"github.com/herbertgao/gaokao_bot/internal/repository"
 	"github.com/herbertgao/gaokao_bot/internal/service"
 	"github.com/herbertgao/gaokao_bot/internal/task"
 	"github.com/herbertgao/gaokao_bot/internal/updater"
@@ -22,6 +21,7 @@ import (
 	"github.com/herbertgao/gaokao_bot/internal/version"
 	"github.com/mymmrac/telego"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -29,6 +29,7 @@ func main() {
 	env := flag.String("env", "dev", "Environment: dev, prod")
 	showVersion := flag.Bool("version", false, "Show version information")
 	doUpdate := flag.Bool("update", false, "Check for updates and update if available")
+	storage := flag.String("storage", storageDatabase, "Storage backend: database, memory (seeded in-memory data for demos)")
 	flag.Parse()
 
 	// 显示版本信息
@@ -59,53 +60,120 @@ func main() {
 	// 初始化日志
 	logger := initLogger(cfg.Log)
 
+	// 执行子命令（如 calendar import/export），执行完毕后直接退出
+	if flag.NArg() > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		err := runCommand(ctx, cfg, logger, flag.Args())
+		stop()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 记录 CORS 配置（用于调试）
 	logger.Infof("CORS 允许来源 (%d): %v", len(cfg.CORS.AllowedOrigins), cfg.CORS.AllowedOrigins)
 
-	// 初始化数据库（带重试逻辑，适用于容器化环境）
-	// 生产环境：重试 10 次（最长等待约 60 秒）
-	// 非生产环境：重试 5 次（快速失败）
-	maxRetries := 5
-	if cfg.App.Env == "prod" {
-		maxRetries = 10
-	}
-	db, err := database.NewDatabaseWithRetry(&cfg.Database, maxRetries)
-	if err != nil {
-		logger.Fatalf("连接数据库失败: %v", err)
-	}
+	// 初始化仓储（内存存储时 db 为 nil）
+	var db *gorm.DB
+	var repos repositories
+	switch *storage {
+	case storageMemory:
+		// 内存存储：无需数据库，使用内置初始数据，适用于演示和快速测试
+		logger.Warn("使用内存存储，数据将在进程退出后丢失")
+		repos = newMemoryRepositories()
+	case storageDatabase:
+		// 初始化数据库（带重试逻辑，适用于容器化环境）
+		// 生产环境：重试 10 次（最长等待约 60 秒）
+		// 非生产环境：重试 5 次（快速失败）
+		maxRetries := 5
+		if cfg.App.Env == "prod" {
+			maxRetries = 10
+		}
+		logger.Infof("数据库驱动: %s", cfg.Database.Driver)
+		db, err = database.NewDatabaseWithRetry(&cfg.Database, maxRetries)
+		if err != nil {
+			logger.Fatalf("连接数据库失败: %v", err)
+		}
 
-	// 确保数据库连接在程序退出时关闭
-	sqlDB, err := db.DB()
-	if err != nil {
-		logger.Fatalf("获取数据库实例失败: %v", err)
-	}
-	defer func() {
-		if err := sqlDB.Close(); err != nil {
-			logger.Errorf("关闭数据库连接失败: %v", err)
+		// 确保数据库连接在程序退出时关闭
+		sqlDB, err := db.DB()
+		if err != nil {
+			logger.Fatalf("获取数据库实例失败: %v", err)
+		}
+		defer func() {
+			if err := sqlDB.Close(); err != nil {
+				logger.Errorf("关闭数据库连接失败: %v", err)
+			}
+		}()
+
+		// 执行未执行的数据库迁移（可通过 DB_AUTO_MIGRATE=false 关闭，改用 migrate 子命令）
+		if cfg.Database.AutoMigrate {
+			logger.Info("正在执行数据库迁移...")
+			migrations, err := database.Migrate(db)
+			if err != nil {
+				logger.Fatalf("数据库迁移失败: %v", err)
+			}
+			logger.Infof("数据库迁移完成（本次执行 %d 个）", len(migrations))
 		}
-	}()
 
-	// 自动迁移数据库表结构
-	logger.Info("正在同步数据库表结构...")
-	if err := database.AutoMigrateSchema(db); err != nil {
-		logger.Fatalf("数据库表结构同步失败: %v", err)
+		// 连接只读副本（可选），副本不可用时自动回退到主库
+		var replicas *database.ReplicaSet
+		if len(cfg.Database.ReplicaDSNs) > 0 {
+			replicas, err = database.OpenReplicas(db, &cfg.Database)
+			if err != nil {
+				logger.Fatalf("连接只读副本失败: %v", err)
+			}
+			replicas.StartHealthCheck(cfg.Database.ReplicaCheckInterval)
+			defer func() {
+				if err := replicas.Close(); err != nil {
+					logger.Errorf("关闭只读副本连接失败: %v", err)
+				}
+			}()
+			healthy, total := replicas.Healthy()
+			logger.Infof("只读副本: %d/%d 可用", healthy, total)
+		}
+		repos = newDatabaseRepositories(db, replicas)
+	default:
+		logger.Fatalf("未知的存储后端: %s（可用: database, memory）", *storage)
 	}
-	logger.Info("数据库表结构同步完成")
 
 	// 初始化 Snowflake
 	if err := util.InitSnowflake(cfg.Snowflake.DatacenterID, cfg.Snowflake.MachineID); err != nil {
 		logger.Fatalf("初始化 Snowflake 失败: %v", err)
 	}
 
-	// 初始化仓储
-	examDateRepo := repository.NewExamDateRepository(db)
-	userTemplateRepo := repository.NewUserTemplateRepository(db)
-	sendChatRepo := repository.NewSendChatRepository(db)
+	// 考试日期和模板读多写少，加一层进程内缓存；所有服务共用同一实例，写入时自动失效
+	cachedExamDates := service.NewCachedExamDateRepository(repos.examDate, cfg.App.CacheTTL)
+	cachedUserTemplates := service.NewCachedUserTemplateRepository(repos.userTemplate, cfg.App.CacheTTL)
+	repos.examDate = cachedExamDates
+	repos.userTemplate = cachedUserTemplates
 
 	// 初始化服务
-	examDateService := service.NewExamDateService(examDateRepo)
-	userTemplateService := service.NewUserTemplateService(userTemplateRepo)
-	sendChatService := service.NewSendChatService(sendChatRepo)
+	examDateService := service.NewExamDateService(repos.examDate)
+	examEventService := service.NewExamEventService(repos.examEvent, repos.examDate)
+	examCalendarService := service.NewExamCalendarService(repos.examDate)
+	userVariableService := service.NewUserVariableService(repos.variable)
+	userTemplateService := service.NewUserTemplateService(repos.userTemplate).
+		WithRevisions(repos.revision).
+		WithVariables(userVariableService)
+	if cfg.Moderation.Enabled {
+		pipeline, err := newModerationPipeline(&cfg.Moderation, cfg.Telegram.Bot.Username)
+		if err != nil {
+			logger.Fatalf("初始化内容审核失败: %v", err)
+		}
+		userTemplateService.WithModeration(pipeline, repos.moderation)
+		userVariableService.WithModeration(pipeline)
+	}
+	moderationService := service.NewTemplateModerationService(repos.moderation, userTemplateService)
+	galleryService := service.NewGalleryService(repos.gallery, userTemplateService)
+	officialTemplateService := service.NewOfficialTemplateService(repos.official)
+	sendChatService := service.NewSendChatService(repos.sendChat).
+		WithTemplatePool(officialTemplateService, userTemplateService)
+	quoteService := service.NewQuoteService(repos.quote, cfg.Quote.RepeatWindow)
+	userTargetService := service.NewUserTargetService(repos.userTarget)
+	calendarFeedService := service.NewCalendarFeedService(repos.examDate, repos.userTarget, cfg.Telegram.Bot.Token, cfg.App.PublicURL)
 
 	// 初始化 Telegram Bot
 	var telegramBot *telego.Bot
@@ -122,11 +190,23 @@ func main() {
 	}
 
 	// 初始化消息和内联查询服务
-	messageService := service.NewMessageService(examDateService, userTemplateService, logger)
-	inlineQueryService := service.NewInlineQueryService(examDateService, userTemplateService, logger)
+	messageService := service.NewMessageService(examDateService, examEventService, userTemplateService, logger).
+		WithUserVariables(userVariableService).
+		WithQuotes(quoteService)
+	inlineQueryService := service.NewInlineQueryService(examDateService, examEventService, userTemplateService, logger).
+		WithGallery(galleryService).
+		WithOfficialTemplates(officialTemplateService).
+		WithUserVariables(userVariableService).
+		WithQuotes(quoteService)
 
 	// 初始化 Bot 服务
-	botService := service.NewBotService(telegramBot, messageService, inlineQueryService, logger, cfg.Telegram.MiniApp.URL)
+	templatePreviewService := service.NewTemplatePreviewService(examDateService).
+		WithUserVariables(userVariableService).
+		WithQuotes(quoteService)
+	templateShareService := service.NewTemplateShareService(userTemplateService, cfg.Telegram.Bot.Username)
+	templateChatService := service.NewTemplateChatService(userTemplateService).
+		WithSharing(templateShareService, templatePreviewService)
+	botService := service.NewBotService(telegramBot, messageService, inlineQueryService, calendarFeedService, templateChatService, logger, cfg.Telegram.MiniApp.URL)
 
 	// 初始化高考倒计时 Bot
 	gaokaoBot, err := bot.NewGaokaoBot(telegramBot, &cfg.Telegram, botService, logger)
@@ -137,7 +217,17 @@ func main() {
 	// 初始化定时任务
 	var dailyTask *task.DailySendTask
 	if cfg.Task.DailySend.Enabled {
-		dailyTask = task.NewDailySendTask(telegramBot, examDateService, userTemplateService, sendChatService, logger)
+		// 未开启考后事件推送时不传入事件服务
+		var dailyEventService *service.ExamEventService
+		if cfg.Task.DailySend.Events {
+			dailyEventService = examEventService
+		}
+		dailyTask = task.NewDailySendTask(telegramBot, examDateService, dailyEventService, userTemplateService, sendChatService, logger, cfg.Task.DailySend.Timeout).
+			WithOfficialTemplates(officialTemplateService).
+			WithUserVariables(userVariableService).
+			WithQuotes(quoteService, cfg.Task.DailySend.Quote).
+			WithCalendar(cfg.Task.DailySend.Calendar).
+			WithTemplateRotation()
 		if err := dailyTask.Start(cfg.Task.DailySend.Cron); err != nil {
 			logger.Fatalf("启动定时任务失败: %v", err)
 		}
@@ -148,7 +238,29 @@ func main() {
 	skipValidation := cfg.App.Env != "prod"
 	// 仅在 debug 日志级别下启用 GIN 访问日志
 	enableGinLogger := cfg.Log.Level == "debug"
-	router, rateLimiter := api.NewRouter(db, cfg.Telegram.Bot.Token, userTemplateService, skipValidation, enableGinLogger, cfg.CORS.AllowedOrigins)
+	router, rateLimiter := api.NewRouter(db, api.Options{
+		BotToken:       cfg.Telegram.Bot.Token,
+		SkipValidation: skipValidation,
+		EnableLogger:   enableGinLogger,
+		AllowedOrigins: cfg.CORS.AllowedOrigins,
+		AdminUserIDs:   cfg.Admin.UserIDs,
+		RequestTimeout: cfg.App.RequestTimeout,
+	}, api.Services{
+		UserTemplate:    userTemplateService,
+		TemplatePreview: templatePreviewService,
+		TemplateShare:   templateShareService,
+		Gallery:         galleryService,
+		Moderation:      moderationService,
+		Official:        officialTemplateService,
+		SendChat:        sendChatService,
+		ExamCalendar:    examCalendarService,
+		UserTarget:      userTargetService,
+		CalendarFeed:    calendarFeedService,
+		ExamEvent:       examEventService,
+		Variable:        userVariableService,
+		Quote:           quoteService,
+		Caches:          []service.Cache{cachedExamDates, cachedUserTemplates},
+	})
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.App.Port),
 		Handler: router,
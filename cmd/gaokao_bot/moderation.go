@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/herbertgao/gaokao_bot/internal/config"
+	"github.com/herbertgao/gaokao_bot/internal/moderation"
+)
+
+// newModerationPipeline 按配置创建模板内容审核流水线，Bot 自身的用户名始终允许提及
+func newModerationPipeline(cfg *config.ModerationConfig, botUsername string) (*moderation.Pipeline, error) {
+	bannedWords := append([]string(nil), cfg.BannedWords...)
+	if cfg.BannedWordsFile != "" {
+		file, err := os.Open(cfg.BannedWordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("打开违禁词文件失败: %w", err)
+		}
+		defer file.Close()
+
+		entries, err := moderation.ParseWordList(file)
+		if err != nil {
+			return nil, fmt.Errorf("读取违禁词文件失败: %w", err)
+		}
+		bannedWords = append(bannedWords, entries...)
+	}
+
+	variants, err := moderation.ParseVariants(cfg.Variants)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedMentions := append([]string(nil), cfg.AllowedMentions...)
+	if botUsername != "" {
+		allowedMentions = append(allowedMentions, botUsername)
+	}
+
+	return moderation.New(moderation.Options{
+		BannedWords:     bannedWords,
+		Variants:        variants,
+		URLPolicy:       cfg.URLPolicy,
+		AllowedDomains:  cfg.AllowedDomains,
+		MentionPolicy:   cfg.MentionPolicy,
+		AllowedMentions: allowedMentions,
+	}), nil
+}
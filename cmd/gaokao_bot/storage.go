@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/herbertgao/gaokao_bot/internal/database"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/repository/memory"
+	"gorm.io/gorm"
+)
+
+// 存储后端（-storage 参数）
+const (
+	storageDatabase = "database"
+	storageMemory   = "memory"
+)
+
+// repositories 服务层依赖的仓储集合
+type repositories struct {
+	examDate     repository.ExamDateRepository
+	examEvent    repository.ExamEventRepository
+	gallery      repository.GalleryRepository
+	moderation   repository.ModerationRecordRepository
+	official     repository.OfficialTemplateRepository
+	quote        repository.QuoteRepository
+	sendChat     repository.SendChatRepository
+	revision     repository.TemplateRevisionRepository
+	userTarget   repository.UserTargetRepository
+	userTemplate repository.UserTemplateRepository
+	variable     repository.UserVariableRepository
+}
+
+// newDatabaseRepositories 创建基于数据库的仓储
+// replicas 不为 nil 时，考试查询和模板、目标、模板广场、发送对话列表优先读取只读副本
+func newDatabaseRepositories(db *gorm.DB, replicas *database.ReplicaSet) repositories {
+	var reads repository.ReadRouter
+	if replicas != nil {
+		reads = replicas
+	}
+
+	return repositories{
+		examDate:     repository.NewExamDateRepository(db).WithReadReplicas(reads),
+		examEvent:    repository.NewExamEventRepository(db),
+		gallery:      repository.NewGalleryRepository(db).WithReadReplicas(reads),
+		moderation:   repository.NewModerationRecordRepository(db),
+		official:     repository.NewOfficialTemplateRepository(db),
+		quote:        repository.NewQuoteRepository(db),
+		sendChat:     repository.NewSendChatRepository(db).WithReadReplicas(reads),
+		revision:     repository.NewTemplateRevisionRepository(db),
+		userTarget:   repository.NewUserTargetRepository(db).WithReadReplicas(reads),
+		userTemplate: repository.NewUserTemplateRepository(db).WithReadReplicas(reads),
+		variable:     repository.NewUserVariableRepository(db),
+	}
+}
+
+// newMemoryRepositories 创建带初始数据的内存仓储，数据在进程退出后丢失
+func newMemoryRepositories() repositories {
+	store := memory.NewSeededStore()
+	return repositories{
+		examDate:     store.ExamDates,
+		examEvent:    store.ExamEvents,
+		gallery:      store.Gallery,
+		moderation:   store.ModerationRecords,
+		official:     store.OfficialTemplates,
+		quote:        store.Quotes,
+		sendChat:     store.SendChats,
+		revision:     store.TemplateRevisions,
+		userTarget:   store.UserTargets,
+		userTemplate: store.UserTemplates,
+		variable:     store.UserVariables,
+	}
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/herbertgao/gaokao_bot/internal/handler"
 	"github.com/herbertgao/gaokao_bot/internal/middleware"
@@ -8,19 +10,48 @@ import (
 	"gorm.io/gorm"
 )
 
+// Options 路由器配置
+type Options struct {
+	// BotToken 用于校验 Telegram Mini App initData
+	BotToken string
+	// SkipValidation 跳过 initData 签名校验（仅用于开发环境）
+	SkipValidation bool
+	// EnableLogger 是否启用 GIN 访问日志
+	EnableLogger bool
+	// AllowedOrigins CORS 允许的来源
+	AllowedOrigins []string
+	// AdminUserIDs 允许访问管理 API 的用户 ID
+	AdminUserIDs []int64
+	// RequestTimeout 单个请求（含数据库查询）的超时，0 表示不限
+	RequestTimeout time.Duration
+}
+
+// Services 路由器依赖的业务服务
+type Services struct {
+	UserTemplate *service.UserTemplateService
+	// TemplatePreview 模板预览，与 Bot 使用相同的渲染逻辑
+	TemplatePreview *service.TemplatePreviewService
+	TemplateShare   *service.TemplateShareService
+	Gallery         *service.GalleryService
+	Moderation      *service.TemplateModerationService
+	Official        *service.OfficialTemplateService
+	SendChat        *service.SendChatService
+	ExamCalendar    *service.ExamCalendarService
+	UserTarget      *service.UserTargetService
+	CalendarFeed    *service.CalendarFeedService
+	ExamEvent       *service.ExamEventService
+	Variable        *service.UserVariableService
+	Quote           *service.QuoteService
+	// Caches 进程内缓存，用于管理 API 查看统计和手动清空
+	Caches []service.Cache
+}
+
 // NewRouter 创建路由器
-// 返回路由器和 RateLimiter 实例（用于生命周期管理）
-func NewRouter(
-	db *gorm.DB,
-	botToken string,
-	templateService *service.UserTemplateService,
-	skipValidation bool,
-	enableLogger bool,
-	allowedOrigins []string,
-) (*gin.Engine, *middleware.RateLimiter) {
+// 返回路由器和 RateLimiter 实例（用于生命周期管理）；db 为 nil 表示使用内存存储
+func NewRouter(db *gorm.DB, opts Options, services Services) (*gin.Engine, *middleware.RateLimiter) {
 	// 根据是否启用日志来创建路由器
 	var router *gin.Engine
-	if enableLogger {
+	if opts.EnableLogger {
 		// Debug 模式：使用 Default (包含 Logger 和 Recovery)
 		router = gin.Default()
 	} else {
@@ -30,10 +61,25 @@ func NewRouter(
 	}
 
 	// 添加 CORS 中间件（从配置读取允许的域名）
-	router.Use(middleware.CORSMiddleware(allowedOrigins))
+	router.Use(middleware.CORSMiddleware(opts.AllowedOrigins))
+
+	// 请求超时中间件：超时后取消该请求触发的数据库查询
+	router.Use(middleware.RequestTimeoutMiddleware(opts.RequestTimeout))
 
 	// 创建处理器
-	templateHandler := handler.NewTemplateHandler(templateService)
+	templateHandler := handler.NewTemplateHandler(services.UserTemplate)
+	templatePreviewHandler := handler.NewTemplatePreviewHandler(services.TemplatePreview)
+	templateShareHandler := handler.NewTemplateShareHandler(services.UserTemplate, services.TemplateShare)
+	galleryHandler := handler.NewGalleryHandler(services.Gallery, services.UserTemplate)
+	moderationHandler := handler.NewModerationHandler(services.Moderation)
+	officialTemplateHandler := handler.NewOfficialTemplateHandler(services.Official, services.SendChat)
+	examCalendarHandler := handler.NewExamCalendarHandler(services.ExamCalendar)
+	targetHandler := handler.NewTargetHandler(services.UserTarget)
+	variableHandler := handler.NewVariableHandler(services.Variable)
+	quoteHandler := handler.NewQuoteHandler(services.Quote)
+	calendarFeedHandler := handler.NewCalendarFeedHandler(services.CalendarFeed)
+	examEventHandler := handler.NewExamEventHandler(services.ExamEvent)
+	cacheHandler := handler.NewCacheHandler(services.Caches)
 
 	// 创建速率限制中间件
 	rateLimitHandler, rateLimiter := middleware.RateLimitMiddleware(10, 20) // 每秒10个请求，突发20个
@@ -43,18 +89,116 @@ func NewRouter(
 	{
 		// 模板相关 API（需要认证和速率限制）
 		templates := api.Group("/templates")
-		templates.Use(middleware.TelegramAuthMiddleware(botToken, skipValidation))
+		templates.Use(middleware.TelegramAuthMiddleware(opts.BotToken, opts.SkipValidation))
 		templates.Use(rateLimitHandler)
 		{
 			templates.GET("", templateHandler.GetTemplates)
 			templates.POST("", templateHandler.CreateTemplate)
+			templates.POST("/preview", templatePreviewHandler.PreviewTemplate)
+			templates.PUT("/order", templateHandler.ReorderTemplates)
+			templates.DELETE("/default", templateHandler.ClearDefaultTemplate)
+			templates.GET("/export", templateHandler.ExportTemplates)
+			templates.POST("/import", templateHandler.ImportTemplates)
 			templates.PUT("/:id", templateHandler.UpdateTemplate)
 			templates.DELETE("/:id", templateHandler.DeleteTemplate)
+			templates.POST("/:id/share", templateShareHandler.ShareTemplate)
+			templates.DELETE("/:id/share", templateShareHandler.RevokeShare)
+			templates.POST("/:id/favorite", templateHandler.FavoriteTemplate)
+			templates.DELETE("/:id/favorite", templateHandler.UnfavoriteTemplate)
+			templates.PUT("/:id/default", templateHandler.SetDefaultTemplate)
+			templates.GET("/:id/revisions", templateHandler.GetTemplateRevisions)
+			templates.POST("/:id/revisions/:revision_id/restore", templateHandler.RestoreTemplateRevision)
+		}
+
+		// 模板广场 API（需要认证和速率限制）
+		gallery := api.Group("/gallery")
+		gallery.Use(middleware.TelegramAuthMiddleware(opts.BotToken, opts.SkipValidation))
+		gallery.Use(rateLimitHandler)
+		{
+			gallery.GET("", galleryHandler.GetGallery)
+			gallery.GET("/mine", galleryHandler.GetMyGallery)
+			gallery.POST("", galleryHandler.PublishTemplate)
+			gallery.DELETE("/:id", galleryHandler.UnpublishTemplate)
+			gallery.POST("/:id/like", galleryHandler.LikeTemplate)
+			gallery.DELETE("/:id/like", galleryHandler.UnlikeTemplate)
+			gallery.POST("/:id/copy", galleryHandler.CopyTemplate)
+		}
+
+		// 自定义目标 API（需要认证和速率限制）
+		targets := api.Group("/targets")
+		targets.Use(middleware.TelegramAuthMiddleware(opts.BotToken, opts.SkipValidation))
+		targets.Use(rateLimitHandler)
+		{
+			targets.GET("", targetHandler.GetTargets)
+			targets.POST("", targetHandler.CreateTarget)
+			targets.DELETE("/:id", targetHandler.DeleteTarget)
+		}
+
+		// 自定义变量 API（需要认证和速率限制）
+		variables := api.Group("/variables")
+		variables.Use(middleware.TelegramAuthMiddleware(opts.BotToken, opts.SkipValidation))
+		variables.Use(rateLimitHandler)
+		{
+			variables.GET("", variableHandler.GetVariables)
+			variables.PUT("/:name", variableHandler.SetVariable)
+			variables.DELETE("/:name", variableHandler.DeleteVariable)
+		}
+
+		// iCalendar 订阅（日历客户端无法携带 initData，个人订阅通过签名链接鉴权）
+		api.GET("/calendar.ics", rateLimitHandler, calendarFeedHandler.GetPublicFeed)
+		api.GET("/calendar/user/:file", rateLimitHandler, calendarFeedHandler.GetUserFeed)
+		api.GET("/calendar/link",
+			middleware.TelegramAuthMiddleware(opts.BotToken, opts.SkipValidation),
+			rateLimitHandler,
+			calendarFeedHandler.GetFeedLink,
+		)
+
+		// 管理 API（需要认证、管理员权限和速率限制）
+		admin := api.Group("/admin")
+		admin.Use(middleware.TelegramAuthMiddleware(opts.BotToken, opts.SkipValidation))
+		admin.Use(middleware.AdminAuthMiddleware(opts.AdminUserIDs))
+		admin.Use(rateLimitHandler)
+		{
+			admin.GET("/exams/export", examCalendarHandler.ExportCalendar)
+			admin.POST("/exams/import", examCalendarHandler.ImportCalendar)
+			admin.GET("/events", examEventHandler.GetEvents)
+			admin.POST("/events", examEventHandler.CreateEvent)
+			admin.DELETE("/events/:id", examEventHandler.DeleteEvent)
+			admin.GET("/cache", cacheHandler.GetStats)
+			admin.DELETE("/cache", cacheHandler.InvalidateAll)
+			admin.GET("/gallery", galleryHandler.GetModerationQueue)
+			admin.POST("/gallery/:id/approve", galleryHandler.ApproveTemplate)
+			admin.POST("/gallery/:id/hide", galleryHandler.HideTemplate)
+			admin.GET("/moderation", moderationHandler.GetRecords)
+			admin.POST("/moderation/:id/approve", moderationHandler.ApproveRecord)
+			admin.POST("/moderation/:id/dismiss", moderationHandler.DismissRecord)
+			admin.GET("/official-templates", officialTemplateHandler.GetTemplates)
+			admin.POST("/official-templates", officialTemplateHandler.CreateTemplate)
+			admin.PUT("/official-templates/:id", officialTemplateHandler.UpdateTemplate)
+			admin.DELETE("/official-templates/:id", officialTemplateHandler.DeleteTemplate)
+			admin.GET("/chats", officialTemplateHandler.GetChats)
+			admin.PUT("/chats/:id/template", officialTemplateHandler.SetChatTemplate)
+			admin.GET("/chats/:id/rotation", officialTemplateHandler.GetChatRotation)
+			admin.PUT("/chats/:id/rotation", officialTemplateHandler.SetChatRotation)
+			admin.GET("/quotes", quoteHandler.GetQuotes)
+			admin.POST("/quotes", quoteHandler.CreateQuote)
+			admin.POST("/quotes/import", quoteHandler.ImportQuotes)
+			admin.PUT("/quotes/:id", quoteHandler.UpdateQuote)
+			admin.DELETE("/quotes/:id", quoteHandler.DeleteQuote)
 		}
 	}
 
 	// 健康检查（包含数据库连接检查）
 	router.GET("/health", func(c *gin.Context) {
+		// 内存存储模式下没有数据库连接
+		if db == nil {
+			c.JSON(200, gin.H{
+				"status":  "ok",
+				"storage": "memory",
+			})
+			return
+		}
+
 		// 检查数据库连接
 		sqlDB, err := db.DB()
 		if err != nil {
@@ -66,7 +210,7 @@ func NewRouter(
 		}
 
 		// Ping 数据库
-		if err := sqlDB.Ping(); err != nil {
+		if err := sqlDB.PingContext(c.Request.Context()); err != nil {
 			c.JSON(503, gin.H{
 				"status": "unhealthy",
 				"error":  "数据库连接失败",
@@ -3,11 +3,15 @@ package api
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/herbertgao/gaokao_bot/internal/middleware"
+	"github.com/herbertgao/gaokao_bot/internal/model"
 	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/repository/memory"
 	"github.com/herbertgao/gaokao_bot/internal/service"
-	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/util"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -29,19 +33,50 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&model.UserTemplate{}); err != nil {
+	if err := db.AutoMigrate(&model.UserTemplate{}, &model.ExamDate{}, &model.UserTarget{}, &model.ExamEvent{}, &model.GalleryTemplate{}, &model.GalleryLike{}, &model.ModerationRecord{}, &model.OfficialTemplate{}, &model.SendChat{}, &model.SendChatTemplate{}, &model.UserVariable{}, &model.Quote{}); err != nil {
 		t.Fatalf("Failed to migrate: %v", err)
 	}
 
 	return db
 }
 
+func newTestServices(db *gorm.DB) Services {
+	return Services{
+		UserTemplate:    service.NewUserTemplateService(repository.NewUserTemplateRepository(db)),
+		TemplatePreview: service.NewTemplatePreviewService(service.NewExamDateService(repository.NewExamDateRepository(db))),
+		TemplateShare:   service.NewTemplateShareService(service.NewUserTemplateService(repository.NewUserTemplateRepository(db)), "gaokao_bot"),
+		ExamCalendar:    service.NewExamCalendarService(repository.NewExamDateRepository(db)),
+		UserTarget:      service.NewUserTargetService(repository.NewUserTargetRepository(db)),
+		CalendarFeed: service.NewCalendarFeedService(
+			repository.NewExamDateRepository(db),
+			repository.NewUserTargetRepository(db),
+			testBotToken,
+			"",
+		),
+		ExamEvent: service.NewExamEventService(
+			repository.NewExamEventRepository(db),
+			repository.NewExamDateRepository(db),
+		),
+		Gallery: service.NewGalleryService(
+			repository.NewGalleryRepository(db),
+			service.NewUserTemplateService(repository.NewUserTemplateRepository(db)),
+		),
+		Moderation: service.NewTemplateModerationService(
+			repository.NewModerationRecordRepository(db),
+			service.NewUserTemplateService(repository.NewUserTemplateRepository(db)),
+		),
+		Official: service.NewOfficialTemplateService(repository.NewOfficialTemplateRepository(db)),
+		SendChat: service.NewSendChatService(repository.NewSendChatRepository(db)),
+		Variable: service.NewUserVariableService(repository.NewUserVariableRepository(db)),
+		Quote:    service.NewQuoteService(repository.NewQuoteRepository(db), 30),
+	}
+}
+
 func TestNewRouter(t *testing.T) {
 	db := setupTestDB(t)
-	repo := repository.NewUserTemplateRepository(db)
-	templateService := service.NewUserTemplateService(repo)
+	services := newTestServices(db)
 
-	router, rateLimiter := NewRouter(db, testBotToken, templateService, true, false, testAllowedOrigins)
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, EnableLogger: false, AllowedOrigins: testAllowedOrigins}, services)
 	defer rateLimiter.Stop()
 
 	if router == nil {
@@ -55,10 +90,9 @@ func TestNewRouter(t *testing.T) {
 
 func TestHealthCheck(t *testing.T) {
 	db := setupTestDB(t)
-	repo := repository.NewUserTemplateRepository(db)
-	templateService := service.NewUserTemplateService(repo)
+	services := newTestServices(db)
 
-	router, rateLimiter := NewRouter(db, testBotToken, templateService, true, false, testAllowedOrigins)
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, EnableLogger: false, AllowedOrigins: testAllowedOrigins}, services)
 	defer rateLimiter.Stop()
 
 	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
@@ -72,10 +106,9 @@ func TestHealthCheck(t *testing.T) {
 
 func TestHealthCheck_ResponseFormat(t *testing.T) {
 	db := setupTestDB(t)
-	repo := repository.NewUserTemplateRepository(db)
-	templateService := service.NewUserTemplateService(repo)
+	services := newTestServices(db)
 
-	router, rateLimiter := NewRouter(db, testBotToken, templateService, true, false, testAllowedOrigins)
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, EnableLogger: false, AllowedOrigins: testAllowedOrigins}, services)
 	defer rateLimiter.Stop()
 
 	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
@@ -93,13 +126,43 @@ func TestHealthCheck_ResponseFormat(t *testing.T) {
 	}
 }
 
+func TestHealthCheck_MemoryStorage(t *testing.T) {
+	store := memory.NewSeededStore()
+	services := Services{
+		UserTemplate: service.NewUserTemplateService(store.UserTemplates),
+		ExamCalendar: service.NewExamCalendarService(store.ExamDates),
+		UserTarget:   service.NewUserTargetService(store.UserTargets),
+		CalendarFeed: service.NewCalendarFeedService(store.ExamDates, store.UserTargets, testBotToken, ""),
+		ExamEvent:    service.NewExamEventService(store.ExamEvents, store.ExamDates),
+	}
+
+	router, rateLimiter := NewRouter(nil, Options{BotToken: testBotToken, SkipValidation: true, AllowedOrigins: testAllowedOrigins}, services)
+	defer rateLimiter.Stop()
+
+	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"storage":"memory"`) {
+		t.Errorf("Health check = %d %s, want memory storage", w.Code, w.Body.String())
+	}
+
+	// 公共日历订阅直接读取内存中的初始考试数据
+	req, _ = http.NewRequest(http.MethodGet, "/api/calendar.ics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "BEGIN:VEVENT") {
+		t.Errorf("Calendar feed = %d %s, want events", w.Code, w.Body.String())
+	}
+}
+
 func TestRouter_WithLogger(t *testing.T) {
 	db := setupTestDB(t)
-	repo := repository.NewUserTemplateRepository(db)
-	templateService := service.NewUserTemplateService(repo)
+	services := newTestServices(db)
 
 	// 测试启用日志
-	router, rateLimiter := NewRouter(db, testBotToken, templateService, true, true, testAllowedOrigins)
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, EnableLogger: true, AllowedOrigins: testAllowedOrigins}, services)
 	defer rateLimiter.Stop()
 
 	if router == nil {
@@ -109,14 +172,257 @@ func TestRouter_WithLogger(t *testing.T) {
 
 func TestRouter_WithoutLogger(t *testing.T) {
 	db := setupTestDB(t)
-	repo := repository.NewUserTemplateRepository(db)
-	templateService := service.NewUserTemplateService(repo)
+	services := newTestServices(db)
 
 	// 测试禁用日志
-	router, rateLimiter := NewRouter(db, testBotToken, templateService, true, false, testAllowedOrigins)
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, EnableLogger: false, AllowedOrigins: testAllowedOrigins}, services)
 	defer rateLimiter.Stop()
 
 	if router == nil {
 		t.Error("Expected router without logger, got nil")
 	}
 }
+
+func TestAdminRoutes_RequireAdmin(t *testing.T) {
+	db := setupTestDB(t)
+	services := newTestServices(db)
+
+	// 开发模式下无 initData 时使用测试用户 ID
+	opts := Options{BotToken: testBotToken, SkipValidation: true, AllowedOrigins: testAllowedOrigins}
+
+	router, rateLimiter := NewRouter(db, opts, services)
+	defer rateLimiter.Stop()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/exams/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Status = %d, want %d for non-admin", w.Code, http.StatusForbidden)
+	}
+
+	opts.AdminUserIDs = []int64{middleware.DefaultTestUserID}
+	adminRouter, adminRateLimiter := NewRouter(db, opts, services)
+	defer adminRateLimiter.Stop()
+
+	w = httptest.NewRecorder()
+	adminRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d for admin. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
+
+func TestCalendarFeedRoute_Public(t *testing.T) {
+	db := setupTestDB(t)
+	services := newTestServices(db)
+
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, AllowedOrigins: testAllowedOrigins}, services)
+	defer rateLimiter.Stop()
+
+	// 公共订阅无需认证
+	req, _ := http.NewRequest(http.MethodGet, "/api/calendar.ics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	// 订阅链接接口需要认证
+	req, _ = http.NewRequest(http.MethodGet, "/api/calendar/link", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestTemplatePreviewRoute(t *testing.T) {
+	db := setupTestDB(t)
+	services := newTestServices(db)
+
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, AllowedOrigins: testAllowedOrigins}, services)
+	defer rateLimiter.Stop()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/templates/preview", strings.NewReader(`{"template_content":"距离{exam}还有{time}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"valid":true`) {
+		t.Errorf("Preview = %d %s, want valid preview", w.Code, w.Body.String())
+	}
+}
+
+func TestGalleryRoutes(t *testing.T) {
+	db := setupTestDB(t)
+	services := newTestServices(db)
+
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, AllowedOrigins: testAllowedOrigins}, services)
+	defer rateLimiter.Stop()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/gallery?sort=latest", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"total":0`) {
+		t.Errorf("Gallery = %d %s, want empty page", w.Code, w.Body.String())
+	}
+
+	// 审核队列仅管理员可访问
+	req, _ = http.NewRequest(http.MethodGet, "/api/admin/gallery", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Moderation queue = %d, want %d for non-admin", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestTemplateOrderRoutes(t *testing.T) {
+	db := setupTestDB(t)
+	services := newTestServices(db)
+
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, AllowedOrigins: testAllowedOrigins}, services)
+	defer rateLimiter.Stop()
+
+	// 静态路径不应被 /:id 路由匹配
+	req, _ := http.NewRequest(http.MethodPut, "/api/templates/order", strings.NewReader(`{"template_ids":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Reorder = %d %s, want %d", w.Code, w.Body.String(), http.StatusOK)
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/templates/default", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Clear default = %d %s, want %d", w.Code, w.Body.String(), http.StatusOK)
+	}
+}
+
+func TestModerationRoutes(t *testing.T) {
+	db := setupTestDB(t)
+	services := newTestServices(db)
+	opts := Options{BotToken: testBotToken, SkipValidation: true, AllowedOrigins: testAllowedOrigins}
+
+	router, rateLimiter := NewRouter(db, opts, services)
+	defer rateLimiter.Stop()
+
+	// 复核列表仅管理员可访问
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/moderation", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Moderation records = %d, want %d for non-admin", w.Code, http.StatusForbidden)
+	}
+
+	opts.AdminUserIDs = []int64{middleware.DefaultTestUserID}
+	adminRouter, adminRateLimiter := NewRouter(db, opts, services)
+	defer adminRateLimiter.Stop()
+
+	w = httptest.NewRecorder()
+	adminRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"total":0`) {
+		t.Errorf("Moderation records = %d %s, want empty page", w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/admin/moderation/1/dismiss", nil)
+	w = httptest.NewRecorder()
+	adminRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Dismiss = %d %s, want %d", w.Code, w.Body.String(), http.StatusNotFound)
+	}
+}
+
+func TestOfficialTemplateRoutes(t *testing.T) {
+	db := setupTestDB(t)
+	services := newTestServices(db)
+	opts := Options{BotToken: testBotToken, SkipValidation: true, AllowedOrigins: testAllowedOrigins}
+
+	router, rateLimiter := NewRouter(db, opts, services)
+	defer rateLimiter.Stop()
+
+	// 官方模板、推送对话、模板池和语录仅管理员可管理
+	for _, path := range []string{"/api/admin/official-templates", "/api/admin/chats", "/api/admin/chats/1/rotation", "/api/admin/quotes"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("GET %s = %d, want %d for non-admin", path, w.Code, http.StatusForbidden)
+		}
+	}
+
+	opts.AdminUserIDs = []int64{middleware.DefaultTestUserID}
+	adminRouter, adminRateLimiter := NewRouter(db, opts, services)
+	defer adminRateLimiter.Stop()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/official-templates", nil)
+	w := httptest.NewRecorder()
+	adminRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"success":true`) {
+		t.Errorf("Official templates = %d %s, want success", w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/admin/quotes", nil)
+	w = httptest.NewRecorder()
+	adminRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"success":true`) {
+		t.Errorf("Quotes = %d %s, want success", w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodPut, "/api/admin/chats/1/template", strings.NewReader(`{"template_id":"0"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	adminRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Set chat template = %d %s, want %d", w.Code, w.Body.String(), http.StatusNotFound)
+	}
+}
+
+func TestVariableRoutes(t *testing.T) {
+	_ = util.InitSnowflake(0, 1)
+	db := setupTestDB(t)
+	services := newTestServices(db)
+
+	router, rateLimiter := NewRouter(db, Options{BotToken: testBotToken, SkipValidation: true, AllowedOrigins: testAllowedOrigins}, services)
+	defer rateLimiter.Stop()
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/variables/name", strings.NewReader(`{"var_value":"小明"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Set variable = %d %s, want %d", w.Code, w.Body.String(), http.StatusOK)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/variables", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"var_value":"小明"`) {
+		t.Errorf("Get variables = %d %s, want saved variable", w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/variables/name", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Delete variable = %d %s, want %d", w.Code, w.Body.String(), http.StatusOK)
+	}
+}
@@ -120,7 +120,9 @@ func (b *GaokaoBot) registerHandlers() {
 					update.Message.Chat.ID,
 					update.Message.Text)
 			}
-			b.service.HandleMessage(ctx.Bot(), update.Message)
+			msgCtx, cancel := withTimeout(ctx, b.config.MessageTimeout)
+			defer cancel()
+			b.service.HandleMessage(msgCtx, ctx.Bot(), update.Message)
 		}
 		return nil
 	}, telegohandler.AnyMessage())
@@ -144,7 +146,9 @@ func (b *GaokaoBot) registerHandlers() {
 					userID,
 					update.InlineQuery.Query)
 			}
-			b.service.HandleInlineQuery(ctx.Bot(), update.InlineQuery)
+			queryCtx, cancel := withTimeout(ctx, b.config.InlineQueryTimeout)
+			defer cancel()
+			b.service.HandleInlineQuery(queryCtx, ctx.Bot(), update.InlineQuery)
 		}
 		return nil
 	}, telegohandler.AnyInlineQuery())
@@ -165,11 +169,22 @@ func (b *GaokaoBot) registerHandlers() {
 				message.GuestQueryID,
 				message.Text)
 		}
-		b.service.HandleGuestMessage(ctx.Bot(), &message)
+		msgCtx, cancel := withTimeout(ctx, b.config.MessageTimeout)
+		defer cancel()
+		b.service.HandleGuestMessage(msgCtx, ctx.Bot(), &message)
 		return nil
 	})
 }
 
+// withTimeout 为单个更新的处理创建带超时的 context
+// ctx 来自 telegohandler，Bot 停止时会被取消；timeout 不大于 0 时不设置超时
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // Stop 停止Bot
 func (b *GaokaoBot) Stop() {
 	b.logger.Info("Stopping bot...")
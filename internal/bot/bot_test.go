@@ -78,6 +78,28 @@ func TestNewGaokaoBot(t *testing.T) {
 }
 
 // TestStop_BeforeStart 测试在 Start() 之前调用 Stop() 不会 panic
+func TestWithTimeout(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+
+	ctx, cancel := withTimeout(parent, time.Second)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+		t.Errorf("Deadline = %v, %v, want within 1s", deadline, ok)
+	}
+
+	noTimeout, cancelNoTimeout := withTimeout(parent, 0)
+	defer cancelNoTimeout()
+	if _, ok := noTimeout.Deadline(); ok {
+		t.Error("Expected no deadline when timeout is 0")
+	}
+
+	// Bot 停止时 telegohandler 取消父 context，处理中的查询随之取消
+	cancelParent()
+	if ctx.Err() == nil || noTimeout.Err() == nil {
+		t.Error("Expected derived contexts to be canceled with parent")
+	}
+}
+
 func TestStop_BeforeStart(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
@@ -170,7 +192,7 @@ func TestRegisterHandlers_GuestMessageRouted(t *testing.T) {
 
 	examDateService := service.NewExamDateService(repository.NewExamDateRepository(db))
 	userTemplateService := service.NewUserTemplateService(repository.NewUserTemplateRepository(db))
-	messageService := service.NewMessageService(examDateService, userTemplateService, logger)
+	messageService := service.NewMessageService(examDateService, nil, userTemplateService, logger)
 
 	caller := &guestSpyCaller{called: make(chan struct{}, 1)}
 	tgBot, err := telego.NewBot(
@@ -182,7 +204,7 @@ func TestRegisterHandlers_GuestMessageRouted(t *testing.T) {
 		t.Fatalf("NewBot() error = %v", err)
 	}
 
-	botService := service.NewBotService(tgBot, messageService, nil, logger, "")
+	botService := service.NewBotService(tgBot, messageService, nil, nil, nil, logger, "")
 
 	cfg := &config.TelegramConfig{
 		Bot:     config.BotConfig{Username: "gaokao_bot", Token: "test_token"},
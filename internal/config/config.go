@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/robfig/cron/v3"
@@ -12,13 +13,16 @@ import (
 
 // Config 应用配置
 type Config struct {
-	App       AppConfig
-	Telegram  TelegramConfig
-	Database  DatabaseConfig
-	Snowflake SnowflakeConfig
-	Log       LogConfig
-	Task      TaskConfig
-	CORS      CORSConfig
+	App        AppConfig
+	Telegram   TelegramConfig
+	Database   DatabaseConfig
+	Snowflake  SnowflakeConfig
+	Log        LogConfig
+	Task       TaskConfig
+	CORS       CORSConfig
+	Admin      AdminConfig
+	Moderation ModerationConfig
+	Quote      QuoteConfig
 }
 
 // AppConfig 应用配置
@@ -26,12 +30,22 @@ type AppConfig struct {
 	Name string
 	Env  string
 	Port int
+	// PublicURL 对外访问的 API 基础地址（用于生成日历订阅链接），为空时不提供订阅链接
+	PublicURL string
+	// RequestTimeout Mini App API 单个请求（含数据库查询）的超时
+	RequestTimeout time.Duration
+	// CacheTTL 考试日期和模板的进程内缓存有效期，0 表示不缓存
+	CacheTTL time.Duration
 }
 
 // TelegramConfig Telegram 配置
 type TelegramConfig struct {
 	Bot     BotConfig
 	MiniApp MiniAppConfig
+	// MessageTimeout 处理单条消息（命令、Guest 消息）的超时，包含数据库查询和回复
+	MessageTimeout time.Duration
+	// InlineQueryTimeout 处理单个内联查询的超时，应短于 Telegram 的应答时限
+	InlineQueryTimeout time.Duration
 }
 
 // BotConfig Bot 配置
@@ -45,8 +59,23 @@ type MiniAppConfig struct {
 	URL string
 }
 
+// 数据库驱动
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
+	// Driver 数据库驱动：mysql、postgres、sqlite
+	Driver string
+	// Path SQLite 数据库文件路径，:memory: 表示内存数据库
+	Path string
+	// SSLMode PostgreSQL 的 sslmode 参数
+	SSLMode string
+	// AutoMigrate 启动时是否自动执行未执行的数据库迁移
+	AutoMigrate     bool
 	Host            string
 	Port            int
 	Name            string
@@ -58,6 +87,10 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime int
+	// ReplicaDSNs 只读副本的 DSN（与主库使用相同驱动），为空时所有查询使用主库
+	ReplicaDSNs []string
+	// ReplicaCheckInterval 只读副本的健康检查间隔
+	ReplicaCheckInterval time.Duration
 }
 
 // SnowflakeConfig Snowflake ID 配置
@@ -82,6 +115,20 @@ type TaskConfig struct {
 type DailySendConfig struct {
 	Enabled bool
 	Cron    string
+	// Events 是否在每天 9:00 推送当天的考后事件（成绩公布、志愿填报等）
+	Events bool
+	// Timeout 单次执行（查询和全部推送）的超时
+	Timeout time.Duration
+	// Quote 是否在每日推送末尾附带当天的励志语录
+	Quote bool
+	// Calendar 是否在每日推送开头附带当天的农历日期、星期和节气
+	Calendar bool
+}
+
+// QuoteConfig 励志语录配置
+type QuoteConfig struct {
+	// RepeatWindow 同一对话（或用户）在多少天内不重复出现同一条语录，0 表示不限制
+	RepeatWindow int
 }
 
 // CORSConfig CORS 配置
@@ -89,6 +136,38 @@ type CORSConfig struct {
 	AllowedOrigins []string
 }
 
+// AdminConfig 管理员配置
+type AdminConfig struct {
+	// UserIDs 允许访问管理 API 的 Telegram 用户 ID
+	UserIDs []int64
+}
+
+// 内容审核策略
+const (
+	ModerationPolicyAllow  = "allow"
+	ModerationPolicyReject = "reject"
+)
+
+// ModerationConfig 用户模板内容审核配置
+type ModerationConfig struct {
+	// Enabled 是否在创建和修改模板时进行内容审核
+	Enabled bool
+	// BannedWords 违禁词条，可用 | 附带其他写法，如「傻瓜|shagua」，拼音不会自动生成
+	BannedWords []string
+	// BannedWordsFile 违禁词文件路径（每行一个词条），与 BannedWords 合并使用
+	BannedWordsFile string
+	// Variants 变体字映射，每项格式为「变体=标准字」
+	Variants []string
+	// URLPolicy 链接策略：allow、reject
+	URLPolicy string
+	// AllowedDomains 拒绝链接时仍允许的域名（含子域名）
+	AllowedDomains []string
+	// MentionPolicy 提及用户策略：allow、reject
+	MentionPolicy string
+	// AllowedMentions 拒绝提及时仍允许提及的用户名，Bot 自身始终允许
+	AllowedMentions []string
+}
+
 // Load 加载配置
 func Load(env string) (*Config, error) {
 	// 尝试加载环境特定的 .env 文件
@@ -101,11 +180,16 @@ func Load(env string) (*Config, error) {
 	// 加载默认 .env 文件（可选）
 	_ = godotenv.Load() // 默认配置是可选的
 
+	dbDriver := strings.ToLower(getEnv("DB_DRIVER", DriverMySQL))
+
 	cfg := &Config{
 		App: AppConfig{
-			Name: getEnv("APP_NAME", "gaokao_bot"),
-			Env:  getEnv("APP_ENV", env),
-			Port: getEnvAsInt("APP_PORT", 8080),
+			Name:           getEnv("APP_NAME", "gaokao_bot"),
+			Env:            getEnv("APP_ENV", env),
+			Port:           getEnvAsInt("APP_PORT", 8080),
+			PublicURL:      getEnv("APP_PUBLIC_URL", ""),
+			RequestTimeout: getEnvAsDuration("APP_REQUEST_TIMEOUT", 10*time.Second),
+			CacheTTL:       getEnvAsDuration("APP_CACHE_TTL", 5*time.Minute),
 		},
 		Telegram: TelegramConfig{
 			Bot: BotConfig{
@@ -115,19 +199,27 @@ func Load(env string) (*Config, error) {
 			MiniApp: MiniAppConfig{
 				URL: getEnv("TELEGRAM_MINIAPP_URL", ""),
 			},
+			MessageTimeout:     getEnvAsDuration("TELEGRAM_MESSAGE_TIMEOUT", 10*time.Second),
+			InlineQueryTimeout: getEnvAsDuration("TELEGRAM_INLINE_QUERY_TIMEOUT", 5*time.Second),
 		},
 		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "127.0.0.1"),
-			Port:            getEnvAsInt("DB_PORT", 3306),
-			Name:            getEnv("DB_NAME", "gaokao"),
-			Username:        getEnv("DB_USERNAME", "root"),
-			Password:        getEnv("DB_PASSWORD", ""),
-			Charset:         getEnv("DB_CHARSET", "utf8mb4"),
-			ParseTime:       getEnvAsBool("DB_PARSE_TIME", true),
-			Loc:             getEnv("DB_LOC", "Asia/Shanghai"),
-			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
-			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 20),
-			ConnMaxLifetime: getEnvAsInt("DB_CONN_MAX_LIFETIME", 900),
+			Driver:               dbDriver,
+			Path:                 getEnv("DB_PATH", "gaokao.db"),
+			SSLMode:              getEnv("DB_SSL_MODE", "disable"),
+			AutoMigrate:          getEnvAsBool("DB_AUTO_MIGRATE", true),
+			Host:                 getEnv("DB_HOST", "127.0.0.1"),
+			Port:                 getEnvAsInt("DB_PORT", defaultDBPort(dbDriver)),
+			Name:                 getEnv("DB_NAME", "gaokao"),
+			Username:             getEnv("DB_USERNAME", "root"),
+			Password:             getEnv("DB_PASSWORD", ""),
+			Charset:              getEnv("DB_CHARSET", "utf8mb4"),
+			ParseTime:            getEnvAsBool("DB_PARSE_TIME", true),
+			Loc:                  getEnv("DB_LOC", "Asia/Shanghai"),
+			MaxIdleConns:         getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
+			MaxOpenConns:         getEnvAsInt("DB_MAX_OPEN_CONNS", 20),
+			ConnMaxLifetime:      getEnvAsInt("DB_CONN_MAX_LIFETIME", 900),
+			ReplicaDSNs:          getEnvAsSlice("DB_REPLICA_DSNS", nil),
+			ReplicaCheckInterval: getEnvAsDuration("DB_REPLICA_CHECK_INTERVAL", 10*time.Second),
 		},
 		Snowflake: SnowflakeConfig{
 			DatacenterID: getEnvAsInt64("SNOWFLAKE_DATACENTER_ID", 1),
@@ -140,8 +232,12 @@ func Load(env string) (*Config, error) {
 		},
 		Task: TaskConfig{
 			DailySend: DailySendConfig{
-				Enabled: getEnvAsBool("TASK_DAILY_SEND_ENABLED", true),
-				Cron:    getEnv("TASK_DAILY_SEND_CRON", "0 0 * * * *"),
+				Enabled:  getEnvAsBool("TASK_DAILY_SEND_ENABLED", true),
+				Cron:     getEnv("TASK_DAILY_SEND_CRON", "0 0 * * * *"),
+				Events:   getEnvAsBool("TASK_DAILY_SEND_EVENTS", false),
+				Timeout:  getEnvAsDuration("TASK_DAILY_SEND_TIMEOUT", 2*time.Minute),
+				Quote:    getEnvAsBool("TASK_DAILY_SEND_QUOTE", false),
+				Calendar: getEnvAsBool("TASK_DAILY_SEND_CALENDAR", false),
 			},
 		},
 		CORS: CORSConfig{
@@ -153,6 +249,22 @@ func Load(env string) (*Config, error) {
 				"http://127.0.0.1:3000",
 			}),
 		},
+		Admin: AdminConfig{
+			UserIDs: getEnvAsInt64Slice("ADMIN_USER_IDS"),
+		},
+		Moderation: ModerationConfig{
+			Enabled:         getEnvAsBool("MODERATION_ENABLED", true),
+			BannedWords:     getEnvAsSlice("MODERATION_BANNED_WORDS", nil),
+			BannedWordsFile: getEnv("MODERATION_BANNED_WORDS_FILE", ""),
+			Variants:        getEnvAsSlice("MODERATION_VARIANTS", nil),
+			URLPolicy:       strings.ToLower(getEnv("MODERATION_URL_POLICY", ModerationPolicyReject)),
+			AllowedDomains:  getEnvAsSlice("MODERATION_ALLOWED_DOMAINS", nil),
+			MentionPolicy:   strings.ToLower(getEnv("MODERATION_MENTION_POLICY", ModerationPolicyReject)),
+			AllowedMentions: getEnvAsSlice("MODERATION_ALLOWED_MENTIONS", nil),
+		},
+		Quote: QuoteConfig{
+			RepeatWindow: getEnvAsInt("QUOTE_REPEAT_WINDOW", 30),
+		},
 	}
 
 	// 验证关键配置
@@ -171,22 +283,23 @@ func (c *Config) Validate() error {
 	}
 
 	// 验证数据库配置
-	if c.Database.Host == "" {
-		return fmt.Errorf("必须配置数据库主机 (DB_HOST)")
-	}
-	if c.Database.Name == "" {
-		return fmt.Errorf("必须配置数据库名称 (DB_NAME)")
-	}
-	if c.Database.Username == "" {
-		return fmt.Errorf("必须配置数据库用户名 (DB_USERNAME)")
+	if err := c.Database.validate(); err != nil {
+		return err
 	}
 
 	// 验证端口范围
 	if c.App.Port < 1 || c.App.Port > 65535 {
 		return fmt.Errorf("应用端口必须在 1-65535 范围内，当前值: %d", c.App.Port)
 	}
-	if c.Database.Port < 1 || c.Database.Port > 65535 {
-		return fmt.Errorf("数据库端口必须在 1-65535 范围内，当前值: %d", c.Database.Port)
+
+	// 验证超时配置
+	if err := c.validateTimeouts(); err != nil {
+		return err
+	}
+
+	// 验证缓存有效期
+	if c.App.CacheTTL < 0 {
+		return fmt.Errorf("缓存有效期不能为负数 (APP_CACHE_TTL)，当前值: %s", c.App.CacheTTL)
 	}
 
 	// 验证 Cron 表达式（如果定时任务已启用）
@@ -213,9 +326,100 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// 验证内容审核配置
+	if err := c.Moderation.validate(); err != nil {
+		return err
+	}
+
+	// 验证语录不重复天数
+	if c.Quote.RepeatWindow < 0 {
+		return fmt.Errorf("语录不重复天数不能为负数 (QUOTE_REPEAT_WINDOW)，当前值: %d", c.Quote.RepeatWindow)
+	}
+
 	return nil
 }
 
+// validate 验证内容审核策略，未设置时按拒绝处理
+func (m *ModerationConfig) validate() error {
+	policies := []struct {
+		key   string
+		value string
+	}{
+		{"MODERATION_URL_POLICY", m.URLPolicy},
+		{"MODERATION_MENTION_POLICY", m.MentionPolicy},
+	}
+	for _, policy := range policies {
+		switch policy.value {
+		case "", ModerationPolicyAllow, ModerationPolicyReject:
+		default:
+			return fmt.Errorf("不支持的内容审核策略 '%s'，可选值: allow、reject (%s)", policy.value, policy.key)
+		}
+	}
+	return nil
+}
+
+// validate 验证数据库配置，SQLite 只需要文件路径
+func (d *DatabaseConfig) validate() error {
+	switch d.Driver {
+	case DriverSQLite:
+		if d.Path == "" {
+			return fmt.Errorf("使用 SQLite 时必须配置数据库文件路径 (DB_PATH)")
+		}
+		if len(d.ReplicaDSNs) > 0 {
+			return fmt.Errorf("SQLite 不支持只读副本 (DB_REPLICA_DSNS)")
+		}
+		return nil
+	case "", DriverMySQL, DriverPostgres:
+		// 未设置驱动时按 MySQL 处理
+	default:
+		return fmt.Errorf("不支持的数据库驱动 '%s'，可选值: mysql、postgres、sqlite (DB_DRIVER)", d.Driver)
+	}
+
+	if d.Host == "" {
+		return fmt.Errorf("必须配置数据库主机 (DB_HOST)")
+	}
+	if d.Name == "" {
+		return fmt.Errorf("必须配置数据库名称 (DB_NAME)")
+	}
+	if d.Username == "" {
+		return fmt.Errorf("必须配置数据库用户名 (DB_USERNAME)")
+	}
+	if d.Port < 1 || d.Port > 65535 {
+		return fmt.Errorf("数据库端口必须在 1-65535 范围内，当前值: %d", d.Port)
+	}
+	if len(d.ReplicaDSNs) > 0 && d.ReplicaCheckInterval <= 0 {
+		return fmt.Errorf("配置只读副本时健康检查间隔必须大于 0 (DB_REPLICA_CHECK_INTERVAL)，当前值: %s", d.ReplicaCheckInterval)
+	}
+	return nil
+}
+
+// validateTimeouts 验证各操作的超时不为负数，0 表示不限
+func (c *Config) validateTimeouts() error {
+	timeouts := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"APP_REQUEST_TIMEOUT", c.App.RequestTimeout},
+		{"TELEGRAM_MESSAGE_TIMEOUT", c.Telegram.MessageTimeout},
+		{"TELEGRAM_INLINE_QUERY_TIMEOUT", c.Telegram.InlineQueryTimeout},
+		{"TASK_DAILY_SEND_TIMEOUT", c.Task.DailySend.Timeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			return fmt.Errorf("超时时间不能为负数 (%s)，当前值: %s", timeout.key, timeout.value)
+		}
+	}
+	return nil
+}
+
+// defaultDBPort 返回数据库驱动的默认端口
+func defaultDBPort(driver string) int {
+	if driver == DriverPostgres {
+		return 5432
+	}
+	return 3306
+}
+
 // getEnv 获取环境变量，如果不存在则返回默认值
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -254,6 +458,22 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvAsDuration 获取环境变量并转换为时长，支持 10s、1m30s 等格式，纯数字按秒处理
+// 不存在或转换失败时返回默认值
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return duration
+	}
+	return defaultValue
+}
+
 // getEnvAsSlice 获取环境变量并转换为字符串切片（以逗号分隔），如果不存在则返回默认值
 // 自动标准化 origins：去除首尾空格和尾部斜杠
 func getEnvAsSlice(key string, defaultValue []string) []string {
@@ -275,3 +495,20 @@ func getEnvAsSlice(key string, defaultValue []string) []string {
 	}
 	return defaultValue
 }
+
+// getEnvAsInt64Slice 获取环境变量并转换为int64切片（以逗号分隔），忽略无法解析的项
+func getEnvAsInt64Slice(key string) []int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parts := strings.Split(value, ",")
+	result := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		if intValue, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64); err == nil {
+			result = append(result, intValue)
+		}
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad(t *testing.T) {
@@ -580,3 +581,326 @@ func TestGetEnvAsSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEnvAsInt64Slice(t *testing.T) {
+	tests := []struct {
+		name     string
+		envKey   string
+		envValue string
+		want     []int64
+	}{
+		{
+			name:     "Valid values",
+			envKey:   "TEST_INT64_SLICE",
+			envValue: "123, 456,789",
+			want:     []int64{123, 456, 789},
+		},
+		{
+			name:     "Invalid parts are skipped",
+			envKey:   "TEST_INT64_SLICE_INVALID",
+			envValue: "123,abc,,456",
+			want:     []int64{123, 456},
+		},
+		{
+			name:     "Empty value",
+			envKey:   "TEST_INT64_SLICE_EMPTY",
+			envValue: "",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envValue != "" {
+				_ = os.Setenv(tt.envKey, tt.envValue)
+				defer func() {
+					_ = os.Unsetenv(tt.envKey)
+				}()
+			}
+
+			got := getEnvAsInt64Slice(tt.envKey)
+			if len(got) != len(tt.want) {
+				t.Errorf("getEnvAsInt64Slice() length = %d, want %d", len(got), len(tt.want))
+				return
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("getEnvAsInt64Slice()[%d] = %d, want %d", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_DatabaseDriver(t *testing.T) {
+	base := func(db DatabaseConfig) *Config {
+		return &Config{
+			App:      AppConfig{Env: "dev", Port: 8080},
+			Telegram: TelegramConfig{Bot: BotConfig{Token: "test_token"}},
+			Database: db,
+			CORS:     CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		db      DatabaseConfig
+		wantErr bool
+	}{
+		{"sqlite file", DatabaseConfig{Driver: DriverSQLite, Path: "gaokao.db"}, false},
+		{"sqlite memory", DatabaseConfig{Driver: DriverSQLite, Path: ":memory:"}, false},
+		{"sqlite missing path", DatabaseConfig{Driver: DriverSQLite}, true},
+		{"postgres", DatabaseConfig{Driver: DriverPostgres, Host: "localhost", Port: 5432, Name: "gaokao", Username: "postgres"}, false},
+		{"postgres missing host", DatabaseConfig{Driver: DriverPostgres, Port: 5432, Name: "gaokao", Username: "postgres"}, true},
+		{"unknown driver", DatabaseConfig{Driver: "oracle", Host: "localhost", Port: 1521, Name: "gaokao", Username: "root"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := base(tt.db).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_DatabaseDriver(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test_token")
+	t.Setenv("DB_DRIVER", "Postgres")
+	t.Setenv("DB_PORT", "")
+
+	cfg, err := Load("dev")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Database.Driver != DriverPostgres {
+		t.Errorf("Driver = %q, want %q", cfg.Database.Driver, DriverPostgres)
+	}
+	if cfg.Database.Port != 5432 {
+		t.Errorf("Port = %d, want 5432", cfg.Database.Port)
+	}
+}
+
+func TestGetEnvAsDuration(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        string
+		defaultValue time.Duration
+		want         time.Duration
+	}{
+		{"empty uses default", "", 10 * time.Second, 10 * time.Second},
+		{"duration string", "1m30s", time.Second, 90 * time.Second},
+		{"milliseconds", "500ms", time.Second, 500 * time.Millisecond},
+		{"plain number as seconds", "15", time.Second, 15 * time.Second},
+		{"zero disables", "0", time.Second, 0},
+		{"invalid uses default", "soon", 5 * time.Second, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_DURATION", tt.value)
+			if got := getEnvAsDuration("TEST_DURATION", tt.defaultValue); got != tt.want {
+				t.Errorf("getEnvAsDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Timeouts(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test_token")
+	t.Setenv("TELEGRAM_INLINE_QUERY_TIMEOUT", "3s")
+
+	cfg, err := Load("dev")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Telegram.InlineQueryTimeout != 3*time.Second {
+		t.Errorf("InlineQueryTimeout = %v, want 3s", cfg.Telegram.InlineQueryTimeout)
+	}
+	if cfg.Telegram.MessageTimeout != 10*time.Second {
+		t.Errorf("MessageTimeout = %v, want 10s", cfg.Telegram.MessageTimeout)
+	}
+	if cfg.App.RequestTimeout != 10*time.Second {
+		t.Errorf("RequestTimeout = %v, want 10s", cfg.App.RequestTimeout)
+	}
+	if cfg.Task.DailySend.Timeout != 2*time.Minute {
+		t.Errorf("DailySend.Timeout = %v, want 2m", cfg.Task.DailySend.Timeout)
+	}
+	if cfg.App.CacheTTL != 5*time.Minute {
+		t.Errorf("CacheTTL = %v, want 5m", cfg.App.CacheTTL)
+	}
+}
+
+func TestValidate_NegativeTimeout(t *testing.T) {
+	cfg := &Config{
+		App:      AppConfig{Env: "dev", Port: 8080, RequestTimeout: -time.Second},
+		Telegram: TelegramConfig{Bot: BotConfig{Token: "test_token"}},
+		Database: DatabaseConfig{Driver: DriverSQLite, Path: ":memory:"},
+		CORS:     CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should return error for negative timeout")
+	}
+}
+
+func TestValidate_NegativeCacheTTL(t *testing.T) {
+	cfg := &Config{
+		App:      AppConfig{Env: "dev", Port: 8080, CacheTTL: -time.Second},
+		Telegram: TelegramConfig{Bot: BotConfig{Token: "test_token"}},
+		Database: DatabaseConfig{Driver: DriverSQLite, Path: ":memory:"},
+		CORS:     CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should return error for negative cache TTL")
+	}
+}
+
+func TestLoad_ReplicaDSNs(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test_token")
+	t.Setenv("DB_REPLICA_DSNS", "reader:pw@tcp(10.0.0.2:3306)/gaokao, reader:pw@tcp(10.0.0.3:3306)/gaokao")
+
+	cfg, err := Load("dev")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Database.ReplicaDSNs) != 2 || cfg.Database.ReplicaDSNs[1] != "reader:pw@tcp(10.0.0.3:3306)/gaokao" {
+		t.Errorf("ReplicaDSNs = %v", cfg.Database.ReplicaDSNs)
+	}
+	if cfg.Database.ReplicaCheckInterval != 10*time.Second {
+		t.Errorf("ReplicaCheckInterval = %v, want 10s", cfg.Database.ReplicaCheckInterval)
+	}
+}
+
+func TestDatabaseConfig_ValidateReplicas(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     DatabaseConfig
+		wantErr bool
+	}{
+		{
+			name:    "sqlite does not support replicas",
+			cfg:     DatabaseConfig{Driver: DriverSQLite, Path: "gaokao.db", ReplicaDSNs: []string{"replica.db"}},
+			wantErr: true,
+		},
+		{
+			name: "mysql with replicas",
+			cfg: DatabaseConfig{Driver: DriverMySQL, Host: "localhost", Port: 3306, Name: "gaokao", Username: "root",
+				ReplicaDSNs: []string{"reader@tcp(replica:3306)/gaokao"}, ReplicaCheckInterval: 10 * time.Second},
+		},
+		{
+			name: "replicas require a check interval",
+			cfg: DatabaseConfig{Driver: DriverMySQL, Host: "localhost", Port: 3306, Name: "gaokao", Username: "root",
+				ReplicaDSNs: []string{"reader@tcp(replica:3306)/gaokao"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_Moderation(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test_token")
+	t.Setenv("MODERATION_BANNED_WORDS", "傻瓜|shagua, 坏蛋")
+	t.Setenv("MODERATION_URL_POLICY", "Allow")
+
+	cfg, err := Load("dev")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Moderation.Enabled {
+		t.Error("Moderation.Enabled should default to true")
+	}
+	if len(cfg.Moderation.BannedWords) != 2 || cfg.Moderation.BannedWords[0] != "傻瓜|shagua" {
+		t.Errorf("BannedWords = %q", cfg.Moderation.BannedWords)
+	}
+	if cfg.Moderation.URLPolicy != ModerationPolicyAllow {
+		t.Errorf("URLPolicy = %q, want allow", cfg.Moderation.URLPolicy)
+	}
+	if cfg.Moderation.MentionPolicy != ModerationPolicyReject {
+		t.Errorf("MentionPolicy = %q, want reject", cfg.Moderation.MentionPolicy)
+	}
+}
+
+func TestValidate_ModerationPolicy(t *testing.T) {
+	cfg := &Config{
+		App:        AppConfig{Env: "dev", Port: 8080},
+		Telegram:   TelegramConfig{Bot: BotConfig{Token: "test_token"}},
+		Database:   DatabaseConfig{Driver: DriverSQLite, Path: ":memory:"},
+		CORS:       CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+		Moderation: ModerationConfig{MentionPolicy: "block"},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should return error for unknown moderation policy")
+	}
+
+	cfg.Moderation.MentionPolicy = ModerationPolicyAllow
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestLoad_Quote(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test_token")
+
+	cfg, err := Load("dev")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Quote.RepeatWindow != 30 || cfg.Task.DailySend.Quote {
+		t.Errorf("Quote defaults = %+v, DailySend.Quote = %v", cfg.Quote, cfg.Task.DailySend.Quote)
+	}
+
+	t.Setenv("QUOTE_REPEAT_WINDOW", "7")
+	t.Setenv("TASK_DAILY_SEND_QUOTE", "true")
+	if cfg, err = Load("dev"); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Quote.RepeatWindow != 7 || !cfg.Task.DailySend.Quote {
+		t.Errorf("Quote = %+v, DailySend.Quote = %v", cfg.Quote, cfg.Task.DailySend.Quote)
+	}
+}
+
+func TestLoad_DailySendCalendar(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test_token")
+
+	cfg, err := Load("dev")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Task.DailySend.Calendar {
+		t.Error("DailySend.Calendar should default to false")
+	}
+
+	t.Setenv("TASK_DAILY_SEND_CALENDAR", "true")
+	if cfg, err = Load("dev"); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Task.DailySend.Calendar {
+		t.Error("DailySend.Calendar should be true")
+	}
+}
+
+func TestValidate_NegativeQuoteRepeatWindow(t *testing.T) {
+	cfg := &Config{
+		App:      AppConfig{Env: "dev", Port: 8080},
+		Telegram: TelegramConfig{Bot: BotConfig{Token: "test_token"}},
+		Database: DatabaseConfig{Driver: DriverSQLite, Path: ":memory:"},
+		CORS:     CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+		Quote:    QuoteConfig{RepeatWindow: -1},
+	}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should return error for negative quote repeat window")
+	}
+}
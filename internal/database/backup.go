@@ -0,0 +1,282 @@
+package database
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/version"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// BackupFormatVersion 备份文件格式版本，格式不兼容时递增
+const BackupFormatVersion = 1
+
+// restoreBatchSize 恢复时每批插入的行数
+const restoreBatchSize = 500
+
+// backupModels 需要备份的表，按恢复顺序排列
+// 新增表时需在此登记，TestBackup_CoversAllTables 会检查遗漏
+var backupModels = []interface{}{
+	&model.ExamDate{},
+	&model.ExamEvent{},
+	&model.SendChat{},
+	&model.UserTemplate{},
+	&model.UserTarget{},
+	&model.GalleryTemplate{},
+	&model.GalleryLike{},
+	&model.TemplateRevision{},
+	&model.ModerationRecord{},
+	&model.OfficialTemplate{},
+	&model.UserVariable{},
+	&model.Quote{},
+	&model.SendChatTemplate{},
+}
+
+// Backup 数据备份
+// 各行以列名为键保存，与数据库驱动无关，可恢复到任意支持的数据库
+type Backup struct {
+	FormatVersion int    `json:"format_version"`
+	SchemaVersion int64  `json:"schema_version"`
+	AppVersion    string `json:"app_version"`
+	// Driver 备份来源的数据库驱动，仅供参考
+	Driver    string        `json:"driver"`
+	CreatedAt time.Time     `json:"created_at"`
+	Tables    []BackupTable `json:"tables"`
+}
+
+// BackupTable 一张表的全部数据
+type BackupTable struct {
+	Name string                       `json:"name"`
+	Rows []map[string]json.RawMessage `json:"rows"`
+}
+
+// CreateBackup 在同一事务中导出全部表，保证各表数据一致
+func CreateBackup(ctx context.Context, db *gorm.DB) (*Backup, error) {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+	schemaVersion, err := migrator.CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &Backup{
+		FormatVersion: BackupFormatVersion,
+		SchemaVersion: schemaVersion,
+		AppVersion:    version.GetVersion(),
+		Driver:        db.Dialector.Name(),
+		CreatedAt:     time.Now(),
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, m := range backupModels {
+			table, err := dumpTable(ctx, tx, m)
+			if err != nil {
+				return err
+			}
+			backup.Tables = append(backup.Tables, *table)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// dumpTable 按主键顺序导出一张表
+func dumpTable(ctx context.Context, tx *gorm.DB, m interface{}) (*BackupTable, error) {
+	s, err := parseBackupSchema(tx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	// 联合主键的表按全部主键列排序
+	query := tx
+	for _, field := range s.PrimaryFields {
+		query = query.Order(field.DBName)
+	}
+
+	records := reflect.New(reflect.SliceOf(s.ModelType))
+	if err := query.Find(records.Interface()).Error; err != nil {
+		return nil, fmt.Errorf("读取表 %s 失败: %w", s.Table, err)
+	}
+
+	table := &BackupTable{Name: s.Table, Rows: make([]map[string]json.RawMessage, 0, records.Elem().Len())}
+	for i := 0; i < records.Elem().Len(); i++ {
+		record := records.Elem().Index(i)
+		row := make(map[string]json.RawMessage, len(s.DBNames))
+		for _, field := range s.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			value, err := json.Marshal(field.ReflectValueOf(ctx, record).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("序列化 %s.%s 失败: %w", s.Table, field.DBName, err)
+			}
+			row[field.DBName] = value
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// WriteBackup 将备份写为 gzip 压缩的 JSON
+func WriteBackup(w io.Writer, backup *Backup) error {
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(backup); err != nil {
+		_ = gz.Close()
+		return fmt.Errorf("写出备份失败: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("写出备份失败: %w", err)
+	}
+	return nil
+}
+
+// ReadBackup 读取 gzip 压缩的 JSON 备份并检查格式版本
+func ReadBackup(r io.Reader) (*Backup, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取备份失败（不是有效的 gzip 文件）: %w", err)
+	}
+	defer gz.Close()
+
+	var backup Backup
+	if err := json.NewDecoder(gz).Decode(&backup); err != nil {
+		return nil, fmt.Errorf("解析备份失败: %w", err)
+	}
+	if backup.FormatVersion < 1 || backup.FormatVersion > BackupFormatVersion {
+		return nil, fmt.Errorf("不支持的备份格式版本: %d（当前支持 1-%d）", backup.FormatVersion, BackupFormatVersion)
+	}
+	return &backup, nil
+}
+
+// RestoreBackup 在事务中清空全部表并写入备份数据，任一步骤失败时整体回滚
+// 数据库结构需已迁移且不低于备份时的版本；备份中缺少的表恢复后为空
+func RestoreBackup(ctx context.Context, db *gorm.DB, backup *Backup) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	schemaVersion, err := migrator.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if backup.SchemaVersion > schemaVersion {
+		return fmt.Errorf("备份的数据库结构版本 (%d) 高于当前数据库 (%d)，请先升级程序并执行迁移", backup.SchemaVersion, schemaVersion)
+	}
+
+	schemas := make([]*schema.Schema, 0, len(backupModels))
+	known := make(map[string]bool, len(backupModels))
+	for _, m := range backupModels {
+		s, err := parseBackupSchema(db, m)
+		if err != nil {
+			return err
+		}
+		schemas = append(schemas, s)
+		known[s.Table] = true
+	}
+
+	tables := make(map[string]BackupTable, len(backup.Tables))
+	for _, table := range backup.Tables {
+		if !known[table.Name] {
+			return fmt.Errorf("备份包含未知的表: %s", table.Name)
+		}
+		tables[table.Name] = table
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 按登记顺序的逆序清空，避免后续引入外键时违反约束
+		for i := len(backupModels) - 1; i >= 0; i-- {
+			if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(backupModels[i]).Error; err != nil {
+				return fmt.Errorf("清空表 %s 失败: %w", schemas[i].Table, err)
+			}
+		}
+
+		for _, s := range schemas {
+			if err := restoreTable(ctx, tx, s, tables[s.Table]); err != nil {
+				return err
+			}
+			if err := resetSequence(tx, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// restoreTable 将备份行按模型字段类型解析后批量插入
+// 以列名写入而非模型写入，避免自动填充创建时间、忽略带默认值的零值字段
+func restoreTable(ctx context.Context, tx *gorm.DB, s *schema.Schema, table BackupTable) error {
+	if len(table.Rows) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]interface{}, 0, len(table.Rows))
+	for i, row := range table.Rows {
+		record := reflect.New(s.ModelType).Elem()
+		values := make(map[string]interface{}, len(row))
+		for column, raw := range row {
+			field := s.LookUpField(column)
+			if field == nil || field.DBName == "" {
+				return fmt.Errorf("表 %s 不存在列 %s", s.Table, column)
+			}
+			value := field.ReflectValueOf(ctx, record)
+			if err := json.Unmarshal(raw, value.Addr().Interface()); err != nil {
+				return fmt.Errorf("解析 %s.%s 失败（第 %d 行）: %w", s.Table, column, i+1, err)
+			}
+			values[field.DBName] = columnValue(field, value)
+		}
+		rows = append(rows, values)
+	}
+
+	if err := tx.Table(s.Table).CreateInBatches(rows, restoreBatchSize).Error; err != nil {
+		return fmt.Errorf("写入表 %s 失败: %w", s.Table, err)
+	}
+	return nil
+}
+
+// columnValue 返回写入数据库的列值，可为空的时间列零值还原为 NULL
+func columnValue(field *schema.Field, value reflect.Value) interface{} {
+	if t, ok := value.Interface().(time.Time); ok && t.IsZero() && !field.NotNull {
+		return nil
+	}
+	return value.Interface()
+}
+
+// resetSequence 显式写入自增 ID 后同步 PostgreSQL 的序列，MySQL 与 SQLite 会自动处理
+func resetSequence(tx *gorm.DB, s *schema.Schema) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	pk := s.PrioritizedPrimaryField
+	if pk == nil || !pk.AutoIncrement {
+		return nil
+	}
+
+	sql := fmt.Sprintf("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(%s), 0) + 1, false) FROM %s",
+		tx.Statement.Quote(pk.DBName), tx.Statement.Quote(s.Table))
+	if err := tx.Exec(sql, s.Table, pk.DBName).Error; err != nil {
+		return fmt.Errorf("同步表 %s 的自增序列失败: %w", s.Table, err)
+	}
+	return nil
+}
+
+// parseBackupSchema 解析模型的表结构
+func parseBackupSchema(db *gorm.DB, m interface{}) (*schema.Schema, error) {
+	s, err := schema.Parse(m, &sync.Map{}, db.NamingStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("解析表结构失败: %w", err)
+	}
+	return s, nil
+}
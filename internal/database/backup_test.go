@@ -0,0 +1,203 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func setupBackupTestDB(t *testing.T) *Backup {
+	t.Helper()
+	db := setupMigrateTestDB(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	eventDate := time.Date(2026, 6, 25, 10, 0, 0, 0, time.UTC)
+	db.Create(&model.ExamEvent{ExamYear: 2026, ExamKind: "gaokao", EventType: "score", EventName: "成绩公布", EventDate: eventDate})
+	db.Create(&model.ExamEvent{ExamYear: 2025, ExamKind: "gaokao", EventType: "score", EventName: "已删除", EventDate: eventDate, IsDelete: true})
+	db.Create(&model.SendChat{ChatID: "-1001234567890", TemplateID: 1982374650123456794})
+	db.Create(&model.UserTemplate{ID: 1982374650123456789, UserID: 123456789, TemplateName: "我的模板", TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.UserTarget{ID: 1982374650123456790, UserID: 123456789, TargetName: "期末考试", TargetDate: eventDate})
+	db.Create(&model.GalleryTemplate{ID: 1982374650123456791, TemplateID: 1982374650123456789, UserID: 123456789, TemplateContent: "距离{exam}还有{time}", Status: model.GalleryStatusApproved, LikeCount: 1})
+	db.Create(&model.GalleryLike{GalleryID: 1982374650123456791, UserID: 987654321})
+	db.Create(&model.TemplateRevision{ID: 1982374650123456792, TemplateID: 1982374650123456789, UserID: 123456789, TemplateContent: "还有{time}就到{exam}了"})
+	db.Create(&model.ModerationRecord{ID: 1982374650123456793, UserID: 123456789, Action: model.ModerationActionCreate, TemplateContent: "加群 t.me/xxx 距离{exam}还有{time}", Status: model.ModerationStatusPending})
+	db.Create(&model.OfficialTemplate{ID: 1982374650123456794, TemplateName: "百日冲刺", TemplateContent: "{exam}百日冲刺，还有{time}", Active: true, DaysBeforeExam: 100})
+	db.Create(&model.UserVariable{ID: 1982374650123456795, UserID: 123456789, VarName: "name", VarValue: "小明"})
+	db.Create(&model.Quote{ID: 1982374650123456796, Content: "宝剑锋从磨砺出，梅花香自苦寒来", Source: "《警世贤文》", Tags: "坚持"})
+	db.Create(&model.SendChatTemplate{ID: 1982374650123456797, SendChatID: 1, Source: model.ChatTemplateSourceOfficial, TemplateID: 1982374650123456794})
+
+	backup, err := CreateBackup(context.Background(), db)
+	if err != nil {
+		t.Fatalf("CreateBackup() error = %v", err)
+	}
+	return backup
+}
+
+func backupRowCount(backup *Backup, table string) int {
+	for _, t := range backup.Tables {
+		if t.Name == table {
+			return len(t.Rows)
+		}
+	}
+	return -1
+}
+
+func TestBackup_RoundTrip(t *testing.T) {
+	backup := setupBackupTestDB(t)
+
+	if backup.FormatVersion != BackupFormatVersion || backup.SchemaVersion == 0 || backup.Driver != "sqlite" {
+		t.Errorf("Unexpected backup header: %+v", backup)
+	}
+	// 初始数据 84 条考试 + 1 个默认模板
+	for table, want := range map[string]int{"exam_date": 84, "exam_event": 2, "send_chat": 1, "user_template": 2, "user_target": 1, "gallery_template": 1, "gallery_like": 1, "template_revision": 1, "moderation_record": 1, "official_template": 1, "user_variable": 1, "quote": 1, "send_chat_template": 1} {
+		if got := backupRowCount(backup, table); got != want {
+			t.Errorf("%s rows = %d, want %d", table, got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBackup(&buf, backup); err != nil {
+		t.Fatalf("WriteBackup() error = %v", err)
+	}
+	decoded, err := ReadBackup(&buf)
+	if err != nil {
+		t.Fatalf("ReadBackup() error = %v", err)
+	}
+
+	// 恢复到新的数据库（已有初始数据会被替换）
+	target := setupMigrateTestDB(t)
+	if _, err := Migrate(target); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	target.Create(&model.SendChat{ChatID: "-100999"})
+	if err := RestoreBackup(context.Background(), target, decoded); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+
+	var chats []model.SendChat
+	target.Find(&chats)
+	if len(chats) != 1 || chats[0].ChatID != "-1001234567890" || chats[0].TemplateID != 1982374650123456794 {
+		t.Errorf("send_chat after restore = %+v", chats)
+	}
+
+	var template model.UserTemplate
+	if err := target.First(&template, int64(1982374650123456789)).Error; err != nil {
+		t.Fatalf("restored template not found: %v", err)
+	}
+	if template.UserID != 123456789 || template.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("restored template = %+v", template)
+	}
+
+	// json:"-" 的字段同样需要保留
+	var deleted model.ExamEvent
+	if err := target.Where("event_name = ?", "已删除").First(&deleted).Error; err != nil || !deleted.IsDelete {
+		t.Errorf("restored deleted event = %+v, %v", deleted, err)
+	}
+
+	// 再次备份应与原备份一致
+	again, err := CreateBackup(context.Background(), target)
+	if err != nil {
+		t.Fatalf("CreateBackup() error = %v", err)
+	}
+	want, _ := json.Marshal(backup.Tables)
+	got, _ := json.Marshal(again.Tables)
+	if !bytes.Equal(want, got) {
+		t.Error("backup of restored database differs from original")
+	}
+}
+
+func TestRestoreBackup_Rollback(t *testing.T) {
+	backup := setupBackupTestDB(t)
+	for i := range backup.Tables {
+		if backup.Tables[i].Name == "user_target" {
+			backup.Tables[i].Rows[0]["unknown_column"] = json.RawMessage(`1`)
+		}
+	}
+
+	target := setupMigrateTestDB(t)
+	if _, err := Migrate(target); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	err := RestoreBackup(context.Background(), target, backup)
+	if err == nil || !strings.Contains(err.Error(), "unknown_column") {
+		t.Fatalf("RestoreBackup() error = %v, want unknown column error", err)
+	}
+
+	// 失败时不应留下部分数据，初始数据保持不变
+	var count int64
+	target.Model(&model.ExamEvent{}).Count(&count)
+	if count != 0 {
+		t.Errorf("exam_event rows = %d after failed restore, want 0", count)
+	}
+	target.Model(&model.ExamDate{}).Count(&count)
+	if count != 84 {
+		t.Errorf("exam_date rows = %d after failed restore, want 84", count)
+	}
+}
+
+func TestRestoreBackup_Validation(t *testing.T) {
+	db := setupMigrateTestDB(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	if err := RestoreBackup(context.Background(), db, &Backup{FormatVersion: 1, SchemaVersion: 9999}); err == nil {
+		t.Error("RestoreBackup() should reject backups from a newer schema")
+	}
+	if err := RestoreBackup(context.Background(), db, &Backup{FormatVersion: 1, Tables: []BackupTable{{Name: "unknown"}}}); err == nil {
+		t.Error("RestoreBackup() should reject unknown tables")
+	}
+}
+
+func TestReadBackup_Invalid(t *testing.T) {
+	if _, err := ReadBackup(strings.NewReader("not gzip")); err == nil {
+		t.Error("ReadBackup() should reject non-gzip input")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBackup(&buf, &Backup{FormatVersion: BackupFormatVersion + 1}); err != nil {
+		t.Fatalf("WriteBackup() error = %v", err)
+	}
+	if _, err := ReadBackup(&buf); err == nil {
+		t.Error("ReadBackup() should reject unsupported format versions")
+	}
+}
+
+// TestBackup_CoversAllTables 新增的表必须登记到 backupModels
+func TestBackup_CoversAllTables(t *testing.T) {
+	db := setupMigrateTestDB(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		t.Fatalf("GetTables() error = %v", err)
+	}
+
+	registered := make(map[string]bool)
+	for _, m := range backupModels {
+		s, err := parseBackupSchema(db, m)
+		if err != nil {
+			t.Fatalf("parseBackupSchema() error = %v", err)
+		}
+		registered[s.Table] = true
+	}
+
+	for _, table := range tables {
+		// 跳过迁移记录表和 SQLite 内部表
+		if table == (SchemaMigration{}).TableName() || strings.HasPrefix(table, "sqlite_") {
+			continue
+		}
+		if !registered[table] {
+			t.Errorf("table %s is not included in backups", table)
+		}
+	}
+}
@@ -4,21 +4,73 @@ import (
 	"fmt"
 	"log"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"github.com/herbertgao/gaokao_bot/internal/config"
-	"github.com/herbertgao/gaokao_bot/internal/model"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// sqliteMemoryPath SQLite 内存数据库路径
+const sqliteMemoryPath = ":memory:"
+
 // NewDatabase 创建数据库连接
+// 根据 cfg.Driver 选择 MySQL（默认）、PostgreSQL 或 SQLite（纯 Go 实现，无需 CGO）
 func NewDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	dialector, err := newDialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	// 设置连接池
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	// SQLite 内存数据库的数据随连接存在，只能使用一个永不过期的连接
+	if cfg.Driver == config.DriverSQLite && cfg.Path == sqliteMemoryPath {
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
+
+	return db, nil
+}
+
+// newDialector 根据驱动创建 GORM 方言
+func newDialector(cfg *config.DatabaseConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case "", config.DriverMySQL:
+		return mysql.Open(buildMySQLDSN(cfg)), nil
+	case config.DriverPostgres:
+		return postgres.Open(buildPostgresDSN(cfg)), nil
+	case config.DriverSQLite:
+		return sqlite.Open(buildSQLiteDSN(cfg)), nil
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", cfg.Driver)
+	}
+}
+
+// buildMySQLDSN 构建 MySQL DSN
+func buildMySQLDSN(cfg *config.DatabaseConfig) string {
 	// 对时区和密码进行URL编码
 	loc := url.QueryEscape(cfg.Loc)
 	password := url.QueryEscape(cfg.Password)
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%t&loc=%s",
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%t&loc=%s",
 		cfg.Username,
 		password,
 		cfg.Host,
@@ -28,23 +80,40 @@ func NewDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 		cfg.ParseTime,
 		loc,
 	)
+}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
-	if err != nil {
-		return nil, err
+// buildPostgresDSN 构建 PostgreSQL 连接 URL，会话时区与 MySQL 的 loc 保持一致
+func buildPostgresDSN(cfg *config.DatabaseConfig) string {
+	query := url.Values{}
+	if cfg.SSLMode != "" {
+		query.Set("sslmode", cfg.SSLMode)
 	}
-
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, err
+	if cfg.Loc != "" {
+		query.Set("TimeZone", cfg.Loc)
 	}
 
-	// 设置连接池
-	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
-	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
-	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Path:     "/" + cfg.Name,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
 
-	return db, nil
+// buildSQLiteDSN 构建 SQLite DSN
+// 事务以 IMMEDIATE 方式开启，保证 CreateWithLimit 等「先统计后写入」的事务串行执行
+func buildSQLiteDSN(cfg *config.DatabaseConfig) string {
+	params := []string{
+		"_pragma=busy_timeout(5000)",
+		"_pragma=foreign_keys(1)",
+		"_txlock=immediate",
+	}
+	if cfg.Path != sqliteMemoryPath {
+		params = append(params, "_pragma=journal_mode(WAL)")
+	}
+	return cfg.Path + "?" + strings.Join(params, "&")
 }
 
 // NewDatabaseWithRetry 创建数据库连接（带重试逻辑）
@@ -89,13 +158,3 @@ func NewDatabaseWithRetry(cfg *config.DatabaseConfig, maxRetries int) (*gorm.DB,
 
 	return nil, fmt.Errorf("数据库连接失败（已重试 %d 次）: %w", maxRetries, err)
 }
-
-// AutoMigrateSchema 自动迁移数据库表结构
-// GORM 的 AutoMigrate 是幂等的，可以安全地多次执行
-func AutoMigrateSchema(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&model.ExamDate{},
-		&model.SendChat{},
-		&model.UserTemplate{},
-	)
-}
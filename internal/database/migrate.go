@@ -0,0 +1,432 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var migrationFS embed.FS
+
+// migrationFileRegex 迁移文件名：0001_init.up.sql、0001_init.down.sql
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// baselineVersion 初始表结构的迁移版本，执行时需补齐旧表结构缺少的列与索引
+const baselineVersion = 1
+
+// legacyColumn 旧表结构（sql/init.sql 或早期 AutoMigrate）可能缺少的列或索引
+type legacyColumn struct {
+	model interface{}
+	name  string
+}
+
+// legacyColumns 需补齐的列（按模型字段名）
+var legacyColumns = []legacyColumn{
+	{&model.ExamDate{}, "ExamKind"},
+	{&model.ExamDate{}, "Region"},
+	{&model.UserTemplate{}, "CreatedAt"},
+	{&model.UserTemplate{}, "UpdatedAt"},
+}
+
+// legacyIndexes 需补齐的索引（按索引名）
+var legacyIndexes = []legacyColumn{
+	{&model.ExamDate{}, "idx_exam_date_exam_year"},
+	{&model.ExamDate{}, "idx_exam_date_exam_kind"},
+	{&model.ExamDate{}, "idx_exam_date_region"},
+	{&model.UserTemplate{}, "idx_user_template_user_id"},
+	{&model.ExamDate{}, "idx_exam_date_natural_key"},
+}
+
+// legacyRegionalExams 旧初始数据中未标注地区的地方考试（简称 -> 地区）
+// 接入前需补上地区，否则会与同年的全国统一考试重复
+var legacyRegionalExams = map[string]string{
+	"2022年上海高考": "上海",
+}
+
+// Migration 一个版本的迁移脚本
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus 迁移的执行状态
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Applied 是否已执行
+func (s MigrationStatus) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+// SchemaMigration 已执行迁移的记录
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator 版本化数据库迁移
+// 迁移脚本按驱动存放在 migrations/<driver>/ 目录下并嵌入二进制
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator 根据数据库方言加载对应的迁移脚本
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrate 执行全部未执行的迁移（启动时调用）
+func Migrate(db *gorm.DB) ([]Migration, error) {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+	return m.Up()
+}
+
+// LoadMigrations 加载指定方言的迁移脚本，按版本号升序排列
+// 每个版本必须同时提供 up 与 down 脚本
+func LoadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("不支持迁移的数据库方言: %s", dialect)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		m := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("无效的迁移文件名: %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("迁移版本 %d 存在不同名称: %s、%s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("迁移版本 %d 缺少 up 或 down 脚本", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up 按版本升序执行全部未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if err := m.apply(migration, true); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down 按版本降序回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if err := m.apply(migration, false); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Status 返回全部迁移的执行状态，按版本升序排列
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// CurrentVersion 返回已执行的最高迁移版本，未执行任何迁移时返回 0
+func (m *Migrator) CurrentVersion() (int64, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current, nil
+}
+
+// appliedVersions 读取已执行的迁移记录，记录表不存在时自动创建
+func (m *Migrator) appliedVersions() (map[int64]SchemaMigration, error) {
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		if err := m.db.Migrator().CreateTable(&SchemaMigration{}); err != nil {
+			return nil, fmt.Errorf("创建迁移记录表失败: %w", err)
+		}
+	}
+
+	var records []SchemaMigration
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("读取迁移记录失败: %w", err)
+	}
+
+	applied := make(map[int64]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// apply 在事务中执行迁移脚本并更新迁移记录
+// 注意：MySQL 的 DDL 语句会隐式提交事务，失败时需人工检查表结构
+func (m *Migrator) apply(migration Migration, up bool) error {
+	script, action := migration.Up, "执行"
+	if !up {
+		script, action = migration.Down, "回滚"
+	}
+
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		adopt := up && migration.Version == baselineVersion
+		if adopt {
+			if err := adoptLegacyColumns(tx); err != nil {
+				return err
+			}
+			if err := dedupeLegacyExams(tx); err != nil {
+				return err
+			}
+		}
+
+		for _, statement := range splitStatements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		if adopt {
+			if err := adoptLegacyIndexes(tx); err != nil {
+				return err
+			}
+		}
+
+		if up {
+			return tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		}
+		return tx.Delete(&SchemaMigration{}, migration.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s迁移 %04d_%s 失败: %w", action, migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// adoptLegacyColumns 为已存在的旧表补齐缺少的列
+// 初始迁移使用 CREATE TABLE IF NOT EXISTS，已存在的旧表不会被重建；
+// 需在建索引语句之前补齐列，不存在的表由初始迁移创建
+func adoptLegacyColumns(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	for _, column := range legacyColumns {
+		if !migrator.HasTable(column.model) || migrator.HasColumn(column.model, column.name) {
+			continue
+		}
+		if err := migrator.AddColumn(column.model, column.name); err != nil {
+			return fmt.Errorf("补齐列 %s 失败: %w", column.name, err)
+		}
+	}
+	return nil
+}
+
+// legacyExamKey 去重时读取的考试字段，旧表的列可能为 NULL
+type legacyExamKey struct {
+	ID       uint
+	ExamYear *int
+	ExamKind string
+	Region   string
+	IsDelete *bool
+}
+
+// dedupeLegacyExams 在创建（年份、考试类型、地区）唯一索引前清理旧表中的重复考试
+// 先为已知的地方考试补上地区；其余重复记录保留 ID 最小的未删除记录（均已删除时保留 ID 最小的），删除其他记录
+func dedupeLegacyExams(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&model.ExamDate{}) {
+		return nil
+	}
+
+	for shortDesc, region := range legacyRegionalExams {
+		err := tx.Model(&model.ExamDate{}).
+			Where("short_desc = ? AND region = ?", shortDesc, "").
+			Update("region", region).Error
+		if err != nil {
+			return fmt.Errorf("补齐考试地区失败: %w", err)
+		}
+	}
+
+	var exams []legacyExamKey
+	err := tx.Model(&model.ExamDate{}).
+		Select("id", "exam_year", "exam_kind", "region", "is_delete").
+		Order("id ASC").
+		Find(&exams).Error
+	if err != nil {
+		return fmt.Errorf("读取考试失败: %w", err)
+	}
+
+	type naturalKey struct {
+		year   int
+		kind   string
+		region string
+	}
+	kept := make(map[naturalKey]legacyExamKey, len(exams))
+	var duplicates []uint
+	for _, exam := range exams {
+		if exam.ExamYear == nil {
+			continue
+		}
+		key := naturalKey{year: *exam.ExamYear, kind: exam.ExamKind, region: exam.Region}
+		current, ok := kept[key]
+		switch {
+		case !ok:
+			kept[key] = exam
+		case isLegacyExamDeleted(current) && !isLegacyExamDeleted(exam):
+			duplicates = append(duplicates, current.ID)
+			kept[key] = exam
+		default:
+			duplicates = append(duplicates, exam.ID)
+		}
+	}
+
+	if len(duplicates) == 0 {
+		return nil
+	}
+	if err := tx.Delete(&model.ExamDate{}, duplicates).Error; err != nil {
+		return fmt.Errorf("删除重复考试失败: %w", err)
+	}
+	return nil
+}
+
+// isLegacyExamDeleted 旧表记录是否已标记删除，NULL 视为未删除
+func isLegacyExamDeleted(exam legacyExamKey) bool {
+	return exam.IsDelete != nil && *exam.IsDelete
+}
+
+// adoptLegacyIndexes 为旧表补齐缺少的索引
+func adoptLegacyIndexes(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	for _, index := range legacyIndexes {
+		if migrator.HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := migrator.CreateIndex(index.model, index.name); err != nil {
+			return fmt.Errorf("补齐索引 %s 失败: %w", index.name, err)
+		}
+	}
+	return nil
+}
+
+// splitStatements 按行尾分号拆分脚本中的 SQL 语句并去除注释行
+// 各驱动对单次执行多条语句的支持不一致，因此逐条执行
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,339 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/config"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func setupMigrateTestDB(t *testing.T) *gorm.DB {
+	db, err := NewDatabase(&config.DatabaseConfig{Driver: config.DriverSQLite, Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("NewDatabase() error = %v", err)
+	}
+	return db
+}
+
+func TestLoadMigrations(t *testing.T) {
+	var want []string
+	for _, dialect := range []string{config.DriverMySQL, config.DriverPostgres, config.DriverSQLite} {
+		migrations, err := LoadMigrations(dialect)
+		if err != nil {
+			t.Fatalf("LoadMigrations(%s) error = %v", dialect, err)
+		}
+
+		var got []string
+		for i, migration := range migrations {
+			if i > 0 && migration.Version <= migrations[i-1].Version {
+				t.Errorf("%s: migrations not sorted by version", dialect)
+			}
+			got = append(got, migration.Name)
+		}
+
+		// 各方言的迁移版本必须保持一致
+		if want == nil {
+			want = got
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s migrations = %v, want %v", dialect, got, want)
+		}
+	}
+
+	if _, err := LoadMigrations("oracle"); err == nil {
+		t.Error("Expected error for unsupported dialect")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := "-- 注释\nCREATE TABLE a (\n  id int\n);\n\nINSERT INTO a VALUES (1);\nSELECT 1"
+	got := splitStatements(script)
+	want := []string{"CREATE TABLE a (\n  id int\n)", "INSERT INTO a VALUES (1)", "SELECT 1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitStatements() = %q, want %q", got, want)
+	}
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	db := setupMigrateTestDB(t)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(applied) != len(migrator.migrations) {
+		t.Errorf("Up() applied %d migrations, want %d", len(applied), len(migrator.migrations))
+	}
+
+	// 再次执行不应重复迁移
+	if again, err := migrator.Up(); err != nil || len(again) != 0 {
+		t.Errorf("second Up() = %v, %v, want nothing", again, err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied() {
+			t.Errorf("migration %04d_%s should be applied", status.Version, status.Name)
+		}
+	}
+	latest := migrator.migrations[len(migrator.migrations)-1].Version
+	if current, err := migrator.CurrentVersion(); err != nil || current != latest {
+		t.Errorf("CurrentVersion() = %d, %v, want %d", current, err, latest)
+	}
+
+	// 初始数据：2018-2100 年高考与默认模板，时间按北京时间解析
+	exams, err := repository.NewExamDateRepository(db).GetExamByYear(context.Background(), 2026)
+	if err != nil || len(exams) != 1 {
+		t.Fatalf("GetExamByYear(2026) = %v, %v", exams, err)
+	}
+	if want := time.Date(2026, 6, 7, 9, 0, 0, 0, util.GetBJTLocation()); !exams[0].ExamBeginDate.Equal(want) {
+		t.Errorf("ExamBeginDate = %v, want %v", exams[0].ExamBeginDate, want)
+	}
+	if exams[0].ExamKind != "gaokao" || exams[0].IsDelete {
+		t.Errorf("Unexpected seeded exam: %+v", exams[0])
+	}
+	template, err := repository.NewUserTemplateRepository(db).GetByID(context.Background(), 1)
+	if err != nil || template == nil || template.UserID != 0 {
+		t.Errorf("Default template = %+v, %v", template, err)
+	}
+
+	// 回滚到初始数据之前（初始数据之后的迁移一并回滚）
+	steps := len(migrator.migrations) - 1
+	rolledBack, err := migrator.Down(steps)
+	if err != nil || len(rolledBack) != steps || rolledBack[steps-1].Name != "seed" {
+		t.Fatalf("Down(%d) = %v, %v", steps, rolledBack, err)
+	}
+	// 初始数据回滚不删除任何行
+	var count int64
+	db.Model(&model.ExamDate{}).Count(&count)
+	if count == 0 {
+		t.Error("Expected seeded exams kept after seed rollback")
+	}
+
+	// 全部回滚后表应被删除
+	if _, err := migrator.Down(10); err != nil {
+		t.Fatalf("Down(10) error = %v", err)
+	}
+	if db.Migrator().HasTable(&model.ExamDate{}) {
+		t.Error("Expected exam_date table dropped")
+	}
+	statuses, _ = migrator.Status()
+	for _, status := range statuses {
+		if status.Applied() {
+			t.Errorf("migration %04d_%s should be rolled back", status.Version, status.Name)
+		}
+	}
+	if current, err := migrator.CurrentVersion(); err != nil || current != 0 {
+		t.Errorf("CurrentVersion() after rollback = %d, %v, want 0", current, err)
+	}
+}
+
+// TestMigrate_MatchesModels 迁移生成的表结构必须覆盖模型的全部字段与索引
+func TestMigrate_MatchesModels(t *testing.T) {
+	db := setupMigrateTestDB(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	models := []interface{}{
+		&model.ExamDate{},
+		&model.SendChat{},
+		&model.UserTemplate{},
+		&model.UserTarget{},
+		&model.ExamEvent{},
+		&model.GalleryTemplate{},
+		&model.GalleryLike{},
+		&model.TemplateRevision{},
+		&model.ModerationRecord{},
+		&model.OfficialTemplate{},
+		&model.UserVariable{},
+		&model.Quote{},
+		&model.SendChatTemplate{},
+	}
+
+	for _, m := range models {
+		s, err := schema.Parse(m, &sync.Map{}, db.NamingStrategy)
+		if err != nil {
+			t.Fatalf("schema.Parse() error = %v", err)
+		}
+		if !db.Migrator().HasTable(m) {
+			t.Errorf("missing table %s", s.Table)
+			continue
+		}
+		for _, field := range s.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(m, field.DBName) {
+				t.Errorf("table %s missing column %s", s.Table, field.DBName)
+			}
+		}
+		for _, index := range s.ParseIndexes() {
+			if !db.Migrator().HasIndex(m, index.Name) {
+				t.Errorf("table %s missing index %s", s.Table, index.Name)
+			}
+		}
+	}
+}
+
+// legacyUserTemplate 引入迁移之前的模板表结构，后续迁移新增的列由迁移补齐
+type legacyUserTemplate struct {
+	ID              int64  `gorm:"primaryKey"`
+	UserID          int64  `gorm:"not null;index:idx_user_template_user_id"`
+	TemplateName    string `gorm:"type:varchar(40)"`
+	TemplateContent string `gorm:"type:varchar(160)"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// TableName 指定表名
+func (legacyUserTemplate) TableName() string {
+	return "user_template"
+}
+
+// legacySendChat 引入迁移之前的推送对话表结构
+type legacySendChat struct {
+	ID     int64  `gorm:"primaryKey;autoIncrement"`
+	ChatID string `gorm:"type:varchar(64);not null"`
+}
+
+// TableName 指定表名
+func (legacySendChat) TableName() string {
+	return "send_chat"
+}
+
+// TestMigrate_AdoptsExistingSchema 已由 AutoMigrate 建表的数据库可直接接入迁移
+func TestMigrate_AdoptsExistingSchema(t *testing.T) {
+	db := setupMigrateTestDB(t)
+	if err := db.AutoMigrate(&model.ExamDate{}, &legacySendChat{}, &legacyUserTemplate{}, &model.UserTarget{}, &model.ExamEvent{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	db.Create(&legacyUserTemplate{ID: 1, UserID: 0, TemplateContent: "自定义默认模板"})
+
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	// 已有数据不应被初始数据覆盖
+	template, _ := repository.NewUserTemplateRepository(db).GetByID(context.Background(), 1)
+	if template == nil || template.TemplateContent != "自定义默认模板" {
+		t.Errorf("Existing template overwritten: %+v", template)
+	}
+}
+
+// baselineInitSQL 引入迁移之前 sql/init.sql 的表结构（按 SQLite 语法改写）
+var baselineInitSQL = []string{
+	`CREATE TABLE exam_date (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  exam_year integer DEFAULT NULL,
+  exam_desc varchar(255) DEFAULT NULL,
+  short_desc varchar(32) DEFAULT NULL,
+  exam_begin_date datetime DEFAULT NULL,
+  exam_end_date datetime DEFAULT NULL,
+  exam_year_begin_date datetime DEFAULT NULL,
+  exam_year_end_date datetime DEFAULT NULL,
+  is_delete numeric DEFAULT 0
+)`,
+	`CREATE TABLE send_chat (
+  id bigint NOT NULL PRIMARY KEY,
+  chat_id varchar(64) DEFAULT NULL
+)`,
+	`CREATE TABLE user_template (
+  id bigint NOT NULL PRIMARY KEY,
+  user_id bigint NOT NULL,
+  template_name varchar(40) DEFAULT NULL,
+  template_content varchar(160) DEFAULT NULL
+)`,
+	`INSERT INTO exam_date (id, exam_year, exam_desc, short_desc, exam_begin_date, exam_end_date, exam_year_begin_date, exam_year_end_date, is_delete)
+VALUES (9, 2026, '运维维护的 2026 年高考', '2026年高考', '2026-06-07 09:00:00', '2026-06-10 17:00:00', '2025-06-10 17:00:00', '2026-06-10 17:00:00', 0),
+(5, 2022, '2022年普通高等学校招生全国统一考试', '2022年高考', '2022-06-07 09:00:00', '2022-06-10 17:00:00', '2021-06-08 17:00:00', '2022-06-10 17:00:00', 0),
+(84, 2022, '2022年普通高等学校招生全国统一考试上海考试', '2022年上海高考', '2022-07-07 09:00:00', '2022-07-09 17:00:00', '2022-05-07 09:00:00', '2022-07-09 17:00:00', 0),
+(13, 2030, '已删除的 2030 年高考', '2030年高考', '2030-06-07 09:00:00', '2030-06-10 17:00:00', '2029-06-10 17:00:00', '2030-06-10 17:00:00', 1),
+(90, 2030, '重复的 2030 年高考', '2030年高考', '2030-06-07 09:00:00', '2030-06-10 17:00:00', '2029-06-10 17:00:00', '2030-06-10 17:00:00', 0),
+(91, 2030, '再次重复的 2030 年高考', '2030年高考', '2030-06-07 09:00:00', '2030-06-10 17:00:00', '2029-06-10 17:00:00', '2030-06-10 17:00:00', 0)`,
+	`INSERT INTO user_template (id, user_id, template_name, template_content) VALUES (1, 0, '', '自定义默认模板')`,
+}
+
+// TestMigrate_AdoptsBaselineInitSQL 由 sql/init.sql 建表的数据库接入迁移后补齐缺少的列与索引
+func TestMigrate_AdoptsBaselineInitSQL(t *testing.T) {
+	db := setupMigrateTestDB(t)
+	for _, statement := range baselineInitSQL {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("Exec(%q) error = %v", statement, err)
+		}
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	for _, column := range legacyColumns {
+		if !db.Migrator().HasColumn(column.model, column.name) {
+			t.Errorf("missing column %s", column.name)
+		}
+	}
+	for _, index := range legacyIndexes {
+		if !db.Migrator().HasIndex(index.model, index.name) {
+			t.Errorf("missing index %s", index.name)
+		}
+	}
+
+	// 按考试类型过滤与排序的查询可正常执行，已有行使用默认考试类型
+	ctx := context.Background()
+	exams, err := repository.NewExamDateRepository(db).GetExamByYear(ctx, 2026)
+	if err != nil || len(exams) != 1 {
+		t.Fatalf("GetExamByYear(2026) = %v, %v", exams, err)
+	}
+	if exams[0].ExamKind != "gaokao" || exams[0].ExamDesc != "运维维护的 2026 年高考" {
+		t.Errorf("Unexpected adopted exam: %+v", exams[0])
+	}
+	if found, err := repository.NewExamDateRepository(db).Find(ctx, repository.ExamQuery{Kind: "gaokao", Year: 2026}); err != nil || len(found) != 1 {
+		t.Errorf("Find(gaokao) = %v, %v", found, err)
+	}
+
+	// 重复考试已清理：地方考试补上地区，其余重复保留 ID 最小的未删除记录
+	var shanghai model.ExamDate
+	db.First(&shanghai, 84)
+	if shanghai.Region != "上海" {
+		t.Errorf("Legacy Shanghai exam region = %q, want 上海", shanghai.Region)
+	}
+	var ids []uint
+	db.Model(&model.ExamDate{}).Where("exam_year = ?", 2030).Pluck("id", &ids)
+	if !reflect.DeepEqual(ids, []uint{90}) {
+		t.Errorf("2030 exams = %v, want [90]", ids)
+	}
+	// 唯一索引生效后不能再写入重复考试
+	duplicate := model.ExamDate{ExamYear: 2030, ExamKind: "gaokao", ExamBeginDate: time.Now(), ExamEndDate: time.Now(), ExamYearBeginDate: time.Now(), ExamYearEndDate: time.Now()}
+	if err := db.Create(&duplicate).Error; err == nil {
+		t.Error("Expected unique index to reject duplicate exam")
+	}
+
+	// 回滚初始数据不应删除已有的考试日期与默认模板
+	steps := len(migrator.migrations) - 1
+	if _, err := migrator.Down(steps); err != nil {
+		t.Fatalf("Down(%d) error = %v", steps, err)
+	}
+	exams, _ = repository.NewExamDateRepository(db).GetExamByYear(ctx, 2026)
+	if len(exams) != 1 || exams[0].ExamDesc != "运维维护的 2026 年高考" {
+		t.Errorf("Existing exam removed by seed rollback: %+v", exams)
+	}
+	template, _ := repository.NewUserTemplateRepository(db).GetByID(ctx, 1)
+	if template == nil || template.TemplateContent != "自定义默认模板" {
+		t.Errorf("Existing template removed by seed rollback: %+v", template)
+	}
+}
@@ -0,0 +1,66 @@
+-- 初始表结构
+-- 使用 IF NOT EXISTS，以便接管此前由 sql/init.sql 或 AutoMigrate 创建的数据库
+-- 旧表缺少的列与索引（如 exam_date.exam_kind、region）由迁移器在本版本执行时补齐，创建唯一索引前先清理重复考试
+
+CREATE TABLE IF NOT EXISTS `exam_date` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `exam_year` int NOT NULL COMMENT '考试年',
+  `exam_kind` varchar(32) NOT NULL DEFAULT 'gaokao' COMMENT '考试类型',
+  `region` varchar(32) NOT NULL DEFAULT '' COMMENT '考试地区（空为全国）',
+  `exam_desc` varchar(255) DEFAULT NULL COMMENT '考试描述',
+  `short_desc` varchar(32) DEFAULT NULL COMMENT '考试描述（短）',
+  `exam_begin_date` datetime NOT NULL COMMENT '考试开始时间',
+  `exam_end_date` datetime NOT NULL COMMENT '考试结束时间',
+  `exam_year_begin_date` datetime NOT NULL COMMENT '考试年开始时间',
+  `exam_year_end_date` datetime NOT NULL COMMENT '考试年结束时间',
+  `is_delete` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否删除',
+  PRIMARY KEY (`id`),
+  KEY `idx_exam_date_exam_year` (`exam_year`),
+  KEY `idx_exam_date_exam_kind` (`exam_kind`),
+  KEY `idx_exam_date_region` (`region`),
+  UNIQUE KEY `idx_exam_date_natural_key` (`exam_year`, `exam_kind`, `region`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='考试日期';
+
+CREATE TABLE IF NOT EXISTS `send_chat` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `chat_id` varchar(64) NOT NULL COMMENT '对话ID',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='发送对话';
+
+CREATE TABLE IF NOT EXISTS `user_template` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `template_name` varchar(40) DEFAULT NULL COMMENT '模板名称',
+  `template_content` varchar(160) DEFAULT NULL COMMENT '模板内容',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_template_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户模板';
+
+CREATE TABLE IF NOT EXISTS `user_target` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `target_name` varchar(40) NOT NULL COMMENT '目标名称',
+  `target_date` datetime NOT NULL COMMENT '目标时间',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_target_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户自定义倒计时目标';
+
+CREATE TABLE IF NOT EXISTS `exam_event` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `exam_year` int NOT NULL COMMENT '考试年',
+  `exam_kind` varchar(32) NOT NULL DEFAULT 'gaokao' COMMENT '考试类型',
+  `region` varchar(32) NOT NULL DEFAULT '' COMMENT '地区（空为全国）',
+  `event_type` varchar(32) NOT NULL COMMENT '事件类型',
+  `event_name` varchar(64) NOT NULL COMMENT '事件名称',
+  `event_date` datetime NOT NULL COMMENT '事件时间',
+  `is_delete` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否删除',
+  PRIMARY KEY (`id`),
+  KEY `idx_exam_event_exam_year` (`exam_year`),
+  KEY `idx_exam_event_exam_kind` (`exam_kind`),
+  KEY `idx_exam_event_region` (`region`),
+  KEY `idx_exam_event_event_date` (`event_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='考后时间线事件';
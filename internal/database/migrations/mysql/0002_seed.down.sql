@@ -0,0 +1,2 @@
+-- 初始数据回滚不删除任何行
+-- 接管已有数据库时初始数据会跳过已存在的 ID，按 ID 删除会误删运维维护的考试日期与默认模板
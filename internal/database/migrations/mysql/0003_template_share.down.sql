@@ -0,0 +1,3 @@
+ALTER TABLE `user_template`
+  DROP INDEX `idx_user_template_share_code`,
+  DROP COLUMN `share_code`;
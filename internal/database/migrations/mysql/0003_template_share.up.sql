@@ -0,0 +1,5 @@
+-- 模板分享码
+
+ALTER TABLE `user_template`
+  ADD COLUMN `share_code` varchar(16) DEFAULT NULL COMMENT '分享码' AFTER `template_content`,
+  ADD UNIQUE KEY `idx_user_template_share_code` (`share_code`);
@@ -0,0 +1,25 @@
+-- 模板广场
+
+CREATE TABLE IF NOT EXISTS `gallery_template` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `template_id` bigint NOT NULL COMMENT '来源模板ID',
+  `user_id` bigint NOT NULL COMMENT '发布者用户ID',
+  `template_name` varchar(40) DEFAULT NULL COMMENT '模板名称',
+  `template_content` varchar(160) NOT NULL COMMENT '模板内容',
+  `status` varchar(16) NOT NULL DEFAULT 'pending' COMMENT '审核状态',
+  `like_count` bigint NOT NULL DEFAULT 0 COMMENT '点赞数',
+  `copy_count` bigint NOT NULL DEFAULT 0 COMMENT '复制数',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_gallery_template_template_id` (`template_id`),
+  KEY `idx_gallery_template_user_id` (`user_id`),
+  KEY `idx_gallery_template_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='模板广场';
+
+CREATE TABLE IF NOT EXISTS `gallery_like` (
+  `gallery_id` bigint NOT NULL COMMENT '广场条目ID',
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`gallery_id`, `user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='模板广场点赞';
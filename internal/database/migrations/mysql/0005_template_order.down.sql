@@ -0,0 +1,4 @@
+ALTER TABLE `user_template`
+  DROP COLUMN `is_default`,
+  DROP COLUMN `is_favorite`,
+  DROP COLUMN `sort_order`;
@@ -0,0 +1,6 @@
+-- 模板排序、收藏与个人默认模板
+
+ALTER TABLE `user_template`
+  ADD COLUMN `sort_order` int NOT NULL DEFAULT 0 COMMENT '排序位置' AFTER `share_code`,
+  ADD COLUMN `is_favorite` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否收藏' AFTER `sort_order`,
+  ADD COLUMN `is_default` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否为个人默认模板' AFTER `is_favorite`;
@@ -0,0 +1,12 @@
+-- 模板修订历史
+
+CREATE TABLE IF NOT EXISTS `template_revision` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `template_id` bigint NOT NULL COMMENT '模板ID',
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `template_name` varchar(40) DEFAULT NULL COMMENT '修改前的模板名称',
+  `template_content` varchar(160) DEFAULT NULL COMMENT '修改前的模板内容',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_template_revision_template_id` (`template_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='模板修订历史';
@@ -0,0 +1,17 @@
+-- 模板内容审核记录
+
+CREATE TABLE IF NOT EXISTS `moderation_record` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `template_id` bigint NOT NULL DEFAULT 0 COMMENT '修改的模板ID，创建模板时为0',
+  `action` varchar(16) NOT NULL COMMENT '操作：create/update',
+  `template_name` varchar(40) DEFAULT NULL COMMENT '提交的模板名称',
+  `template_content` varchar(160) NOT NULL COMMENT '提交的模板内容',
+  `violations` varchar(1024) DEFAULT NULL COMMENT '违规项',
+  `status` varchar(16) NOT NULL DEFAULT 'pending' COMMENT '状态：pending/approved/dismissed',
+  `reviewed_at` datetime(3) DEFAULT NULL COMMENT '复核时间',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '提交时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_moderation_record_user_id` (`user_id`),
+  KEY `idx_moderation_record_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='模板内容审核记录';
@@ -0,0 +1,3 @@
+ALTER TABLE `send_chat`
+  DROP COLUMN `template_id`;
+DROP TABLE IF EXISTS `official_template`;
@@ -0,0 +1,18 @@
+-- 官方模板，推送对话可选用官方模板
+
+CREATE TABLE IF NOT EXISTS `official_template` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `template_name` varchar(40) DEFAULT NULL COMMENT '模板名称',
+  `template_content` varchar(160) NOT NULL COMMENT '模板内容',
+  `sort_order` int NOT NULL DEFAULT 0 COMMENT '排序位置',
+  `active` tinyint(1) NOT NULL COMMENT '是否启用',
+  `start_at` datetime(3) DEFAULT NULL COMMENT '开始生效时间',
+  `end_at` datetime(3) DEFAULT NULL COMMENT '停止生效时间',
+  `days_before_exam` int NOT NULL DEFAULT 0 COMMENT '距考试开始不超过该天数时生效，0表示不限',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='官方模板';
+
+ALTER TABLE `send_chat`
+  ADD COLUMN `template_id` bigint NOT NULL DEFAULT 0 COMMENT '选用的官方模板ID，0表示默认模板' AFTER `chat_id`;
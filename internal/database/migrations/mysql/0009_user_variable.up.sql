@@ -0,0 +1,12 @@
+-- 用户自定义模板变量
+
+CREATE TABLE IF NOT EXISTS `user_variable` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `var_name` varchar(20) NOT NULL COMMENT '变量名称',
+  `var_value` varchar(60) NOT NULL COMMENT '变量值',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_variable_user_name` (`user_id`, `var_name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户自定义模板变量';
@@ -0,0 +1,11 @@
+-- 励志语录库，供模板 {quote} 和每日推送使用
+
+CREATE TABLE IF NOT EXISTS `quote` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `content` varchar(200) NOT NULL COMMENT '语录内容',
+  `source` varchar(60) DEFAULT NULL COMMENT '出处',
+  `tags` varchar(100) DEFAULT NULL COMMENT '标签，逗号分隔',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='励志语录';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS `send_chat_template`;
+ALTER TABLE `send_chat`
+  DROP COLUMN `last_rotated_at`,
+  DROP COLUMN `last_entry_id`,
+  DROP COLUMN `rotation_strategy`,
+  DROP COLUMN `admin_user_id`;
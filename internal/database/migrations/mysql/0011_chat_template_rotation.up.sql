@@ -0,0 +1,17 @@
+-- 推送对话模板池与轮换策略
+
+ALTER TABLE `send_chat`
+  ADD COLUMN `admin_user_id` bigint NOT NULL DEFAULT 0 COMMENT '管理该对话推送的用户ID，其个人模板可加入模板池' AFTER `template_id`,
+  ADD COLUMN `rotation_strategy` varchar(16) NOT NULL DEFAULT '' COMMENT '模板轮换策略：round_robin、random、weekday，为空表示不轮换' AFTER `admin_user_id`,
+  ADD COLUMN `last_entry_id` bigint NOT NULL DEFAULT 0 COMMENT '上次使用的模板池条目ID' AFTER `rotation_strategy`,
+  ADD COLUMN `last_rotated_at` datetime(3) DEFAULT NULL COMMENT '上次轮换时间' AFTER `last_entry_id`;
+
+CREATE TABLE IF NOT EXISTS `send_chat_template` (
+  `id` bigint NOT NULL COMMENT 'ID',
+  `send_chat_id` bigint NOT NULL COMMENT '推送对话ID',
+  `source` varchar(16) NOT NULL COMMENT '模板来源：official、user',
+  `template_id` bigint NOT NULL COMMENT '模板ID',
+  `sort_order` int NOT NULL DEFAULT 0 COMMENT '排序位置',
+  PRIMARY KEY (`id`),
+  KEY `idx_send_chat_template_send_chat_id` (`send_chat_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='推送对话模板池';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS exam_event;
+DROP TABLE IF EXISTS user_target;
+DROP TABLE IF EXISTS user_template;
+DROP TABLE IF EXISTS send_chat;
+DROP TABLE IF EXISTS exam_date;
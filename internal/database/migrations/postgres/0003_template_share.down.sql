@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_template_share_code;
+ALTER TABLE user_template DROP COLUMN share_code;
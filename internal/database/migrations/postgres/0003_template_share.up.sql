@@ -0,0 +1,4 @@
+-- 模板分享码
+
+ALTER TABLE user_template ADD COLUMN share_code varchar(16);
+CREATE UNIQUE INDEX idx_user_template_share_code ON user_template (share_code);
@@ -0,0 +1,3 @@
+ALTER TABLE user_template DROP COLUMN is_default;
+ALTER TABLE user_template DROP COLUMN is_favorite;
+ALTER TABLE user_template DROP COLUMN sort_order;
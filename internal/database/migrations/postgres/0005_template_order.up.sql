@@ -0,0 +1,5 @@
+-- 模板排序、收藏与个人默认模板
+
+ALTER TABLE user_template ADD COLUMN sort_order integer NOT NULL DEFAULT 0;
+ALTER TABLE user_template ADD COLUMN is_favorite boolean NOT NULL DEFAULT false;
+ALTER TABLE user_template ADD COLUMN is_default boolean NOT NULL DEFAULT false;
@@ -0,0 +1,11 @@
+-- 模板修订历史
+
+CREATE TABLE IF NOT EXISTS template_revision (
+  id bigint PRIMARY KEY,
+  template_id bigint NOT NULL,
+  user_id bigint NOT NULL,
+  template_name varchar(40),
+  template_content varchar(160),
+  created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_template_revision_template_id ON template_revision (template_id);
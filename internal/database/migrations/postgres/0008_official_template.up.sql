@@ -0,0 +1,16 @@
+-- 官方模板，推送对话可选用官方模板
+
+CREATE TABLE IF NOT EXISTS official_template (
+  id bigint PRIMARY KEY,
+  template_name varchar(40),
+  template_content varchar(160) NOT NULL,
+  sort_order integer NOT NULL DEFAULT 0,
+  active boolean NOT NULL,
+  start_at timestamptz,
+  end_at timestamptz,
+  days_before_exam integer NOT NULL DEFAULT 0,
+  created_at timestamptz,
+  updated_at timestamptz
+);
+
+ALTER TABLE send_chat ADD COLUMN template_id bigint NOT NULL DEFAULT 0;
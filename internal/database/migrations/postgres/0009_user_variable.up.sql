@@ -0,0 +1,11 @@
+-- 用户自定义模板变量
+
+CREATE TABLE IF NOT EXISTS user_variable (
+  id bigint PRIMARY KEY,
+  user_id bigint NOT NULL,
+  var_name varchar(20) NOT NULL,
+  var_value varchar(60) NOT NULL,
+  created_at timestamptz,
+  updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_variable_user_name ON user_variable (user_id, var_name);
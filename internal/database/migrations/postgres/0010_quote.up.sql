@@ -0,0 +1,10 @@
+-- 励志语录库，供模板 {quote} 和每日推送使用
+
+CREATE TABLE IF NOT EXISTS quote (
+  id bigint PRIMARY KEY,
+  content varchar(200) NOT NULL,
+  source varchar(60),
+  tags varchar(100),
+  created_at timestamptz,
+  updated_at timestamptz
+);
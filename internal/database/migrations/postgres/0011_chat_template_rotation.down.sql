@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS send_chat_template;
+ALTER TABLE send_chat DROP COLUMN last_rotated_at;
+ALTER TABLE send_chat DROP COLUMN last_entry_id;
+ALTER TABLE send_chat DROP COLUMN rotation_strategy;
+ALTER TABLE send_chat DROP COLUMN admin_user_id;
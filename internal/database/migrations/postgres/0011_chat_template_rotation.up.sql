@@ -0,0 +1,15 @@
+-- 推送对话模板池与轮换策略
+
+ALTER TABLE send_chat ADD COLUMN admin_user_id bigint NOT NULL DEFAULT 0;
+ALTER TABLE send_chat ADD COLUMN rotation_strategy varchar(16) NOT NULL DEFAULT '';
+ALTER TABLE send_chat ADD COLUMN last_entry_id bigint NOT NULL DEFAULT 0;
+ALTER TABLE send_chat ADD COLUMN last_rotated_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS send_chat_template (
+  id bigint PRIMARY KEY,
+  send_chat_id bigint NOT NULL,
+  source varchar(16) NOT NULL,
+  template_id bigint NOT NULL,
+  sort_order integer NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_send_chat_template_send_chat_id ON send_chat_template (send_chat_id);
@@ -0,0 +1,61 @@
+-- 初始表结构
+-- 使用 IF NOT EXISTS，以便接管此前由 AutoMigrate 创建的数据库
+-- 旧表缺少的列与索引（如 exam_date.exam_kind、region）由迁移器在本版本执行时补齐，创建唯一索引前先清理重复考试
+
+CREATE TABLE IF NOT EXISTS exam_date (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  exam_year integer NOT NULL,
+  exam_kind varchar(32) NOT NULL DEFAULT 'gaokao',
+  region varchar(32) NOT NULL DEFAULT '',
+  exam_desc varchar(255),
+  short_desc varchar(32),
+  exam_begin_date datetime NOT NULL,
+  exam_end_date datetime NOT NULL,
+  exam_year_begin_date datetime NOT NULL,
+  exam_year_end_date datetime NOT NULL,
+  is_delete numeric NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_exam_date_exam_year ON exam_date (exam_year);
+CREATE INDEX IF NOT EXISTS idx_exam_date_exam_kind ON exam_date (exam_kind);
+CREATE INDEX IF NOT EXISTS idx_exam_date_region ON exam_date (region);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exam_date_natural_key ON exam_date (exam_year, exam_kind, region);
+
+CREATE TABLE IF NOT EXISTS send_chat (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  chat_id varchar(64) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_template (
+  id integer PRIMARY KEY,
+  user_id bigint NOT NULL,
+  template_name varchar(40),
+  template_content varchar(160),
+  created_at datetime,
+  updated_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_user_template_user_id ON user_template (user_id);
+
+CREATE TABLE IF NOT EXISTS user_target (
+  id integer PRIMARY KEY,
+  user_id bigint NOT NULL,
+  target_name varchar(40) NOT NULL,
+  target_date datetime NOT NULL,
+  created_at datetime,
+  updated_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_user_target_user_id ON user_target (user_id);
+
+CREATE TABLE IF NOT EXISTS exam_event (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  exam_year integer NOT NULL,
+  exam_kind varchar(32) NOT NULL DEFAULT 'gaokao',
+  region varchar(32) NOT NULL DEFAULT '',
+  event_type varchar(32) NOT NULL,
+  event_name varchar(64) NOT NULL,
+  event_date datetime NOT NULL,
+  is_delete numeric NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_exam_event_exam_year ON exam_event (exam_year);
+CREATE INDEX IF NOT EXISTS idx_exam_event_exam_kind ON exam_event (exam_kind);
+CREATE INDEX IF NOT EXISTS idx_exam_event_region ON exam_event (region);
+CREATE INDEX IF NOT EXISTS idx_exam_event_event_date ON exam_event (event_date);
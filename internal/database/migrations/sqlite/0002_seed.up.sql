@@ -0,0 +1,94 @@
+-- 初始数据：2018-2100 年高考日期与默认模板
+-- 时间均显式携带北京时间偏移，与驱动写入的格式保持一致
+
+INSERT OR IGNORE INTO exam_date (id, exam_year, exam_desc, short_desc, exam_begin_date, exam_end_date, exam_year_begin_date, exam_year_end_date, is_delete) VALUES
+(1, 2018, '2018年普通高等学校招生全国统一考试', '2018年高考', '2018-06-07 09:00:00+08:00', '2018-06-09 17:00:00+08:00', '2017-06-09 00:00:00+08:00', '2018-06-09 17:00:00+08:00', 0),
+(2, 2019, '2019年普通高等学校招生全国统一考试', '2019年高考', '2019-06-07 09:00:00+08:00', '2019-06-09 17:00:00+08:00', '2018-06-09 00:00:00+08:00', '2019-06-09 17:00:00+08:00', 0),
+(3, 2020, '2020年普通高等学校招生全国统一考试', '2020年高考', '2020-07-07 09:00:00+08:00', '2020-07-10 17:00:00+08:00', '2019-06-08 17:00:00+08:00', '2020-07-10 17:00:00+08:00', 0),
+(4, 2021, '2021年普通高等学校招生全国统一考试', '2021年高考', '2021-06-07 09:00:00+08:00', '2021-06-10 17:00:00+08:00', '2020-06-08 17:00:00+08:00', '2021-06-10 17:00:00+08:00', 0),
+(5, 2022, '2022年普通高等学校招生全国统一考试', '2022年高考', '2022-06-07 09:00:00+08:00', '2022-06-10 17:00:00+08:00', '2021-06-08 17:00:00+08:00', '2022-06-10 17:00:00+08:00', 0),
+(6, 2023, '2023年普通高等学校招生全国统一考试', '2023年高考', '2023-06-07 09:00:00+08:00', '2023-06-10 17:00:00+08:00', '2022-07-09 17:00:00+08:00', '2023-06-10 17:00:00+08:00', 0),
+(7, 2024, '2024年普通高等学校招生全国统一考试', '2024年高考', '2024-06-07 09:00:00+08:00', '2024-06-10 17:00:00+08:00', '2023-06-10 17:00:00+08:00', '2024-06-10 17:00:00+08:00', 0),
+(8, 2025, '2025年普通高等学校招生全国统一考试', '2025年高考', '2025-06-07 09:00:00+08:00', '2025-06-10 17:00:00+08:00', '2024-06-10 17:00:00+08:00', '2025-06-10 17:00:00+08:00', 0),
+(9, 2026, '2026年普通高等学校招生全国统一考试', '2026年高考', '2026-06-07 09:00:00+08:00', '2026-06-10 17:00:00+08:00', '2025-06-10 17:00:00+08:00', '2026-06-10 17:00:00+08:00', 0),
+(10, 2027, '2027年普通高等学校招生全国统一考试', '2027年高考', '2027-06-07 09:00:00+08:00', '2027-06-10 17:00:00+08:00', '2026-06-10 17:00:00+08:00', '2027-06-10 17:00:00+08:00', 0),
+(11, 2028, '2028年普通高等学校招生全国统一考试', '2028年高考', '2028-06-07 09:00:00+08:00', '2028-06-10 17:00:00+08:00', '2027-06-10 17:00:00+08:00', '2028-06-10 17:00:00+08:00', 0),
+(12, 2029, '2029年普通高等学校招生全国统一考试', '2029年高考', '2029-06-07 09:00:00+08:00', '2029-06-10 17:00:00+08:00', '2028-06-10 17:00:00+08:00', '2029-06-10 17:00:00+08:00', 0),
+(13, 2030, '2030年普通高等学校招生全国统一考试', '2030年高考', '2030-06-07 09:00:00+08:00', '2030-06-10 17:00:00+08:00', '2029-06-10 17:00:00+08:00', '2030-06-10 17:00:00+08:00', 0),
+(14, 2031, '2031年普通高等学校招生全国统一考试', '2031年高考', '2031-06-07 09:00:00+08:00', '2031-06-10 17:00:00+08:00', '2030-06-10 17:00:00+08:00', '2031-06-10 17:00:00+08:00', 0),
+(15, 2032, '2032年普通高等学校招生全国统一考试', '2032年高考', '2032-06-07 09:00:00+08:00', '2032-06-10 17:00:00+08:00', '2031-06-10 17:00:00+08:00', '2032-06-10 17:00:00+08:00', 0),
+(16, 2033, '2033年普通高等学校招生全国统一考试', '2033年高考', '2033-06-07 09:00:00+08:00', '2033-06-10 17:00:00+08:00', '2032-06-10 17:00:00+08:00', '2033-06-10 17:00:00+08:00', 0),
+(17, 2034, '2034年普通高等学校招生全国统一考试', '2034年高考', '2034-06-07 09:00:00+08:00', '2034-06-10 17:00:00+08:00', '2033-06-10 17:00:00+08:00', '2034-06-10 17:00:00+08:00', 0),
+(18, 2035, '2035年普通高等学校招生全国统一考试', '2035年高考', '2035-06-07 09:00:00+08:00', '2035-06-10 17:00:00+08:00', '2034-06-10 17:00:00+08:00', '2035-06-10 17:00:00+08:00', 0),
+(19, 2036, '2036年普通高等学校招生全国统一考试', '2036年高考', '2036-06-07 09:00:00+08:00', '2036-06-10 17:00:00+08:00', '2035-06-10 17:00:00+08:00', '2036-06-10 17:00:00+08:00', 0),
+(20, 2037, '2037年普通高等学校招生全国统一考试', '2037年高考', '2037-06-07 09:00:00+08:00', '2037-06-10 17:00:00+08:00', '2036-06-10 17:00:00+08:00', '2037-06-10 17:00:00+08:00', 0),
+(21, 2038, '2038年普通高等学校招生全国统一考试', '2038年高考', '2038-06-07 09:00:00+08:00', '2038-06-10 17:00:00+08:00', '2037-06-10 17:00:00+08:00', '2038-06-10 17:00:00+08:00', 0),
+(22, 2039, '2039年普通高等学校招生全国统一考试', '2039年高考', '2039-06-07 09:00:00+08:00', '2039-06-10 17:00:00+08:00', '2038-06-10 17:00:00+08:00', '2039-06-10 17:00:00+08:00', 0),
+(23, 2040, '2040年普通高等学校招生全国统一考试', '2040年高考', '2040-06-07 09:00:00+08:00', '2040-06-10 17:00:00+08:00', '2039-06-10 17:00:00+08:00', '2040-06-10 17:00:00+08:00', 0),
+(24, 2041, '2041年普通高等学校招生全国统一考试', '2041年高考', '2041-06-07 09:00:00+08:00', '2041-06-10 17:00:00+08:00', '2040-06-10 17:00:00+08:00', '2041-06-10 17:00:00+08:00', 0),
+(25, 2042, '2042年普通高等学校招生全国统一考试', '2042年高考', '2042-06-07 09:00:00+08:00', '2042-06-10 17:00:00+08:00', '2041-06-10 17:00:00+08:00', '2042-06-10 17:00:00+08:00', 0),
+(26, 2043, '2043年普通高等学校招生全国统一考试', '2043年高考', '2043-06-07 09:00:00+08:00', '2043-06-10 17:00:00+08:00', '2042-06-10 17:00:00+08:00', '2043-06-10 17:00:00+08:00', 0),
+(27, 2044, '2044年普通高等学校招生全国统一考试', '2044年高考', '2044-06-07 09:00:00+08:00', '2044-06-10 17:00:00+08:00', '2043-06-10 17:00:00+08:00', '2044-06-10 17:00:00+08:00', 0),
+(28, 2045, '2045年普通高等学校招生全国统一考试', '2045年高考', '2045-06-07 09:00:00+08:00', '2045-06-10 17:00:00+08:00', '2044-06-10 17:00:00+08:00', '2045-06-10 17:00:00+08:00', 0),
+(29, 2046, '2046年普通高等学校招生全国统一考试', '2046年高考', '2046-06-07 09:00:00+08:00', '2046-06-10 17:00:00+08:00', '2045-06-10 17:00:00+08:00', '2046-06-10 17:00:00+08:00', 0),
+(30, 2047, '2047年普通高等学校招生全国统一考试', '2047年高考', '2047-06-07 09:00:00+08:00', '2047-06-10 17:00:00+08:00', '2046-06-10 17:00:00+08:00', '2047-06-10 17:00:00+08:00', 0),
+(31, 2048, '2048年普通高等学校招生全国统一考试', '2048年高考', '2048-06-07 09:00:00+08:00', '2048-06-10 17:00:00+08:00', '2047-06-10 17:00:00+08:00', '2048-06-10 17:00:00+08:00', 0),
+(32, 2049, '2049年普通高等学校招生全国统一考试', '2049年高考', '2049-06-07 09:00:00+08:00', '2049-06-10 17:00:00+08:00', '2048-06-10 17:00:00+08:00', '2049-06-10 17:00:00+08:00', 0),
+(33, 2050, '2050年普通高等学校招生全国统一考试', '2050年高考', '2050-06-07 09:00:00+08:00', '2050-06-10 17:00:00+08:00', '2049-06-10 17:00:00+08:00', '2050-06-10 17:00:00+08:00', 0),
+(34, 2051, '2051年普通高等学校招生全国统一考试', '2051年高考', '2051-06-07 09:00:00+08:00', '2051-06-10 17:00:00+08:00', '2050-06-10 17:00:00+08:00', '2051-06-10 17:00:00+08:00', 0),
+(35, 2052, '2052年普通高等学校招生全国统一考试', '2052年高考', '2052-06-07 09:00:00+08:00', '2052-06-10 17:00:00+08:00', '2051-06-10 17:00:00+08:00', '2052-06-10 17:00:00+08:00', 0),
+(36, 2053, '2053年普通高等学校招生全国统一考试', '2053年高考', '2053-06-07 09:00:00+08:00', '2053-06-10 17:00:00+08:00', '2052-06-10 17:00:00+08:00', '2053-06-10 17:00:00+08:00', 0),
+(37, 2054, '2054年普通高等学校招生全国统一考试', '2054年高考', '2054-06-07 09:00:00+08:00', '2054-06-10 17:00:00+08:00', '2053-06-10 17:00:00+08:00', '2054-06-10 17:00:00+08:00', 0),
+(38, 2055, '2055年普通高等学校招生全国统一考试', '2055年高考', '2055-06-07 09:00:00+08:00', '2055-06-10 17:00:00+08:00', '2054-06-10 17:00:00+08:00', '2055-06-10 17:00:00+08:00', 0),
+(39, 2056, '2056年普通高等学校招生全国统一考试', '2056年高考', '2056-06-07 09:00:00+08:00', '2056-06-10 17:00:00+08:00', '2055-06-10 17:00:00+08:00', '2056-06-10 17:00:00+08:00', 0),
+(40, 2057, '2057年普通高等学校招生全国统一考试', '2057年高考', '2057-06-07 09:00:00+08:00', '2057-06-10 17:00:00+08:00', '2056-06-10 17:00:00+08:00', '2057-06-10 17:00:00+08:00', 0),
+(41, 2058, '2058年普通高等学校招生全国统一考试', '2058年高考', '2058-06-07 09:00:00+08:00', '2058-06-10 17:00:00+08:00', '2057-06-10 17:00:00+08:00', '2058-06-10 17:00:00+08:00', 0),
+(42, 2059, '2059年普通高等学校招生全国统一考试', '2059年高考', '2059-06-07 09:00:00+08:00', '2059-06-10 17:00:00+08:00', '2058-06-10 17:00:00+08:00', '2059-06-10 17:00:00+08:00', 0),
+(43, 2060, '2060年普通高等学校招生全国统一考试', '2060年高考', '2060-06-07 09:00:00+08:00', '2060-06-10 17:00:00+08:00', '2059-06-10 17:00:00+08:00', '2060-06-10 17:00:00+08:00', 0),
+(44, 2061, '2061年普通高等学校招生全国统一考试', '2061年高考', '2061-06-07 09:00:00+08:00', '2061-06-10 17:00:00+08:00', '2060-06-10 17:00:00+08:00', '2061-06-10 17:00:00+08:00', 0),
+(45, 2062, '2062年普通高等学校招生全国统一考试', '2062年高考', '2062-06-07 09:00:00+08:00', '2062-06-10 17:00:00+08:00', '2061-06-10 17:00:00+08:00', '2062-06-10 17:00:00+08:00', 0),
+(46, 2063, '2063年普通高等学校招生全国统一考试', '2063年高考', '2063-06-07 09:00:00+08:00', '2063-06-10 17:00:00+08:00', '2062-06-10 17:00:00+08:00', '2063-06-10 17:00:00+08:00', 0),
+(47, 2064, '2064年普通高等学校招生全国统一考试', '2064年高考', '2064-06-07 09:00:00+08:00', '2064-06-10 17:00:00+08:00', '2063-06-10 17:00:00+08:00', '2064-06-10 17:00:00+08:00', 0),
+(48, 2065, '2065年普通高等学校招生全国统一考试', '2065年高考', '2065-06-07 09:00:00+08:00', '2065-06-10 17:00:00+08:00', '2064-06-10 17:00:00+08:00', '2065-06-10 17:00:00+08:00', 0),
+(49, 2066, '2066年普通高等学校招生全国统一考试', '2066年高考', '2066-06-07 09:00:00+08:00', '2066-06-10 17:00:00+08:00', '2065-06-10 17:00:00+08:00', '2066-06-10 17:00:00+08:00', 0),
+(50, 2067, '2067年普通高等学校招生全国统一考试', '2067年高考', '2067-06-07 09:00:00+08:00', '2067-06-10 17:00:00+08:00', '2066-06-10 17:00:00+08:00', '2067-06-10 17:00:00+08:00', 0),
+(51, 2068, '2068年普通高等学校招生全国统一考试', '2068年高考', '2068-06-07 09:00:00+08:00', '2068-06-10 17:00:00+08:00', '2067-06-10 17:00:00+08:00', '2068-06-10 17:00:00+08:00', 0),
+(52, 2069, '2069年普通高等学校招生全国统一考试', '2069年高考', '2069-06-07 09:00:00+08:00', '2069-06-10 17:00:00+08:00', '2068-06-10 17:00:00+08:00', '2069-06-10 17:00:00+08:00', 0),
+(53, 2070, '2070年普通高等学校招生全国统一考试', '2070年高考', '2070-06-07 09:00:00+08:00', '2070-06-10 17:00:00+08:00', '2069-06-10 17:00:00+08:00', '2070-06-10 17:00:00+08:00', 0),
+(54, 2071, '2071年普通高等学校招生全国统一考试', '2071年高考', '2071-06-07 09:00:00+08:00', '2071-06-10 17:00:00+08:00', '2070-06-10 17:00:00+08:00', '2071-06-10 17:00:00+08:00', 0),
+(55, 2072, '2072年普通高等学校招生全国统一考试', '2072年高考', '2072-06-07 09:00:00+08:00', '2072-06-10 17:00:00+08:00', '2071-06-10 17:00:00+08:00', '2072-06-10 17:00:00+08:00', 0),
+(56, 2073, '2073年普通高等学校招生全国统一考试', '2073年高考', '2073-06-07 09:00:00+08:00', '2073-06-10 17:00:00+08:00', '2072-06-10 17:00:00+08:00', '2073-06-10 17:00:00+08:00', 0),
+(57, 2074, '2074年普通高等学校招生全国统一考试', '2074年高考', '2074-06-07 09:00:00+08:00', '2074-06-10 17:00:00+08:00', '2073-06-10 17:00:00+08:00', '2074-06-10 17:00:00+08:00', 0),
+(58, 2075, '2075年普通高等学校招生全国统一考试', '2075年高考', '2075-06-07 09:00:00+08:00', '2075-06-10 17:00:00+08:00', '2074-06-10 17:00:00+08:00', '2075-06-10 17:00:00+08:00', 0),
+(59, 2076, '2076年普通高等学校招生全国统一考试', '2076年高考', '2076-06-07 09:00:00+08:00', '2076-06-10 17:00:00+08:00', '2075-06-10 17:00:00+08:00', '2076-06-10 17:00:00+08:00', 0),
+(60, 2077, '2077年普通高等学校招生全国统一考试', '2077年高考', '2077-06-07 09:00:00+08:00', '2077-06-10 17:00:00+08:00', '2076-06-10 17:00:00+08:00', '2077-06-10 17:00:00+08:00', 0),
+(61, 2078, '2078年普通高等学校招生全国统一考试', '2078年高考', '2078-06-07 09:00:00+08:00', '2078-06-10 17:00:00+08:00', '2077-06-10 17:00:00+08:00', '2078-06-10 17:00:00+08:00', 0),
+(62, 2079, '2079年普通高等学校招生全国统一考试', '2079年高考', '2079-06-07 09:00:00+08:00', '2079-06-10 17:00:00+08:00', '2078-06-10 17:00:00+08:00', '2079-06-10 17:00:00+08:00', 0),
+(63, 2080, '2080年普通高等学校招生全国统一考试', '2080年高考', '2080-06-07 09:00:00+08:00', '2080-06-10 17:00:00+08:00', '2079-06-10 17:00:00+08:00', '2080-06-10 17:00:00+08:00', 0),
+(64, 2081, '2081年普通高等学校招生全国统一考试', '2081年高考', '2081-06-07 09:00:00+08:00', '2081-06-10 17:00:00+08:00', '2080-06-10 17:00:00+08:00', '2081-06-10 17:00:00+08:00', 0),
+(65, 2082, '2082年普通高等学校招生全国统一考试', '2082年高考', '2082-06-07 09:00:00+08:00', '2082-06-10 17:00:00+08:00', '2081-06-10 17:00:00+08:00', '2082-06-10 17:00:00+08:00', 0),
+(66, 2083, '2083年普通高等学校招生全国统一考试', '2083年高考', '2083-06-07 09:00:00+08:00', '2083-06-10 17:00:00+08:00', '2082-06-10 17:00:00+08:00', '2083-06-10 17:00:00+08:00', 0),
+(67, 2084, '2084年普通高等学校招生全国统一考试', '2084年高考', '2084-06-07 09:00:00+08:00', '2084-06-10 17:00:00+08:00', '2083-06-10 17:00:00+08:00', '2084-06-10 17:00:00+08:00', 0),
+(68, 2085, '2085年普通高等学校招生全国统一考试', '2085年高考', '2085-06-07 09:00:00+08:00', '2085-06-10 17:00:00+08:00', '2084-06-10 17:00:00+08:00', '2085-06-10 17:00:00+08:00', 0),
+(69, 2086, '2086年普通高等学校招生全国统一考试', '2086年高考', '2086-06-07 09:00:00+08:00', '2086-06-10 17:00:00+08:00', '2085-06-10 17:00:00+08:00', '2086-06-10 17:00:00+08:00', 0),
+(70, 2087, '2087年普通高等学校招生全国统一考试', '2087年高考', '2087-06-07 09:00:00+08:00', '2087-06-10 17:00:00+08:00', '2086-06-10 17:00:00+08:00', '2087-06-10 17:00:00+08:00', 0),
+(71, 2088, '2088年普通高等学校招生全国统一考试', '2088年高考', '2088-06-07 09:00:00+08:00', '2088-06-10 17:00:00+08:00', '2087-06-10 17:00:00+08:00', '2088-06-10 17:00:00+08:00', 0),
+(72, 2089, '2089年普通高等学校招生全国统一考试', '2089年高考', '2089-06-07 09:00:00+08:00', '2089-06-10 17:00:00+08:00', '2088-06-10 17:00:00+08:00', '2089-06-10 17:00:00+08:00', 0),
+(73, 2090, '2090年普通高等学校招生全国统一考试', '2090年高考', '2090-06-07 09:00:00+08:00', '2090-06-10 17:00:00+08:00', '2089-06-10 17:00:00+08:00', '2090-06-10 17:00:00+08:00', 0),
+(74, 2091, '2091年普通高等学校招生全国统一考试', '2091年高考', '2091-06-07 09:00:00+08:00', '2091-06-10 17:00:00+08:00', '2090-06-10 17:00:00+08:00', '2091-06-10 17:00:00+08:00', 0),
+(75, 2092, '2092年普通高等学校招生全国统一考试', '2092年高考', '2092-06-07 09:00:00+08:00', '2092-06-10 17:00:00+08:00', '2091-06-10 17:00:00+08:00', '2092-06-10 17:00:00+08:00', 0),
+(76, 2093, '2093年普通高等学校招生全国统一考试', '2093年高考', '2093-06-07 09:00:00+08:00', '2093-06-10 17:00:00+08:00', '2092-06-10 17:00:00+08:00', '2093-06-10 17:00:00+08:00', 0),
+(77, 2094, '2094年普通高等学校招生全国统一考试', '2094年高考', '2094-06-07 09:00:00+08:00', '2094-06-10 17:00:00+08:00', '2093-06-10 17:00:00+08:00', '2094-06-10 17:00:00+08:00', 0),
+(78, 2095, '2095年普通高等学校招生全国统一考试', '2095年高考', '2095-06-07 09:00:00+08:00', '2095-06-10 17:00:00+08:00', '2094-06-10 17:00:00+08:00', '2095-06-10 17:00:00+08:00', 0),
+(79, 2096, '2096年普通高等学校招生全国统一考试', '2096年高考', '2096-06-07 09:00:00+08:00', '2096-06-10 17:00:00+08:00', '2095-06-10 17:00:00+08:00', '2096-06-10 17:00:00+08:00', 0),
+(80, 2097, '2097年普通高等学校招生全国统一考试', '2097年高考', '2097-06-07 09:00:00+08:00', '2097-06-10 17:00:00+08:00', '2096-06-10 17:00:00+08:00', '2097-06-10 17:00:00+08:00', 0),
+(81, 2098, '2098年普通高等学校招生全国统一考试', '2098年高考', '2098-06-07 09:00:00+08:00', '2098-06-10 17:00:00+08:00', '2097-06-10 17:00:00+08:00', '2098-06-10 17:00:00+08:00', 0),
+(82, 2099, '2099年普通高等学校招生全国统一考试', '2099年高考', '2099-06-07 09:00:00+08:00', '2099-06-10 17:00:00+08:00', '2098-06-10 17:00:00+08:00', '2099-06-10 17:00:00+08:00', 0),
+(83, 2100, '2100年普通高等学校招生全国统一考试', '2100年高考', '2100-06-07 09:00:00+08:00', '2100-06-10 17:00:00+08:00', '2099-06-10 17:00:00+08:00', '2100-06-10 17:00:00+08:00', 0);
+
+-- 地区考试需写入地区，避免与同年全国统一考试冲突
+INSERT OR IGNORE INTO exam_date (id, exam_year, exam_desc, short_desc, region, exam_begin_date, exam_end_date, exam_year_begin_date, exam_year_end_date, is_delete) VALUES
+(84, 2022, '2022年普通高等学校招生全国统一考试上海考试', '2022年上海高考', '上海', '2022-07-07 09:00:00+08:00', '2022-07-09 17:00:00+08:00', '2022-05-07 09:00:00+08:00', '2022-07-09 17:00:00+08:00', 0);
+
+INSERT OR IGNORE INTO user_template (id, user_id, template_name, template_content) VALUES
+(1, 0, '', '现在距离{exam}还有{time}');
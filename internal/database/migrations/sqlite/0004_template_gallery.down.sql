@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS gallery_like;
+DROP TABLE IF EXISTS gallery_template;
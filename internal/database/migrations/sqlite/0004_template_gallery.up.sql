@@ -0,0 +1,24 @@
+-- 模板广场
+
+CREATE TABLE IF NOT EXISTS gallery_template (
+  id integer PRIMARY KEY,
+  template_id bigint NOT NULL,
+  user_id bigint NOT NULL,
+  template_name varchar(40),
+  template_content varchar(160) NOT NULL,
+  status varchar(16) NOT NULL DEFAULT 'pending',
+  like_count bigint NOT NULL DEFAULT 0,
+  copy_count bigint NOT NULL DEFAULT 0,
+  created_at datetime,
+  updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gallery_template_template_id ON gallery_template (template_id);
+CREATE INDEX IF NOT EXISTS idx_gallery_template_user_id ON gallery_template (user_id);
+CREATE INDEX IF NOT EXISTS idx_gallery_template_status ON gallery_template (status);
+
+CREATE TABLE IF NOT EXISTS gallery_like (
+  gallery_id bigint NOT NULL,
+  user_id bigint NOT NULL,
+  created_at datetime,
+  PRIMARY KEY (gallery_id, user_id)
+);
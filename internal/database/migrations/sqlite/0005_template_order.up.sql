@@ -0,0 +1,5 @@
+-- 模板排序、收藏与个人默认模板
+
+ALTER TABLE user_template ADD COLUMN sort_order integer NOT NULL DEFAULT 0;
+ALTER TABLE user_template ADD COLUMN is_favorite numeric NOT NULL DEFAULT 0;
+ALTER TABLE user_template ADD COLUMN is_default numeric NOT NULL DEFAULT 0;
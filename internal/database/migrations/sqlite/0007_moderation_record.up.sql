@@ -0,0 +1,16 @@
+-- 模板内容审核记录
+
+CREATE TABLE IF NOT EXISTS moderation_record (
+  id integer PRIMARY KEY,
+  user_id bigint NOT NULL,
+  template_id bigint NOT NULL DEFAULT 0,
+  action varchar(16) NOT NULL,
+  template_name varchar(40),
+  template_content varchar(160) NOT NULL,
+  violations varchar(1024),
+  status varchar(16) NOT NULL DEFAULT 'pending',
+  reviewed_at datetime,
+  created_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_moderation_record_user_id ON moderation_record (user_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_record_status ON moderation_record (status);
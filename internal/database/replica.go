@@ -0,0 +1,225 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/config"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// replicaPingTimeout 单次健康检查的超时
+const replicaPingTimeout = 3 * time.Second
+
+// ReplicaSet 主库与只读副本
+// 只读查询按轮询分配到健康的副本；副本查询失败时标记为不可用并回退到主库重试，
+// 由后台健康检查在副本恢复后重新启用。全部副本不可用时所有查询使用主库
+type ReplicaSet struct {
+	primary  *gorm.DB
+	replicas []*replica
+	next     atomic.Uint64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// replica 一个只读副本
+type replica struct {
+	index   int
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// NewReplicaSet 创建副本集合，副本初始状态为可用
+func NewReplicaSet(primary *gorm.DB, replicas ...*gorm.DB) *ReplicaSet {
+	s := &ReplicaSet{primary: primary}
+	for i, db := range replicas {
+		r := &replica{index: i + 1, db: db}
+		r.healthy.Store(true)
+		s.replicas = append(s.replicas, r)
+	}
+	return s
+}
+
+// OpenReplicas 按配置连接只读副本并执行一次健康检查
+// 启动时不可用的副本不会导致失败，恢复后由健康检查重新启用
+func OpenReplicas(primary *gorm.DB, cfg *config.DatabaseConfig) (*ReplicaSet, error) {
+	replicas := make([]*gorm.DB, 0, len(cfg.ReplicaDSNs))
+	for i, dsn := range cfg.ReplicaDSNs {
+		dialector, err := replicaDialector(cfg.Driver, dsn)
+		if err != nil {
+			closeAll(replicas)
+			return nil, err
+		}
+
+		// 关闭自动 Ping，副本不可用时不阻塞启动
+		db, err := gorm.Open(dialector, &gorm.Config{DisableAutomaticPing: true})
+		if err != nil {
+			closeAll(replicas)
+			return nil, fmt.Errorf("连接只读副本 #%d 失败: %w", i+1, err)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			closeAll(replicas)
+			return nil, fmt.Errorf("获取只读副本 #%d 实例失败: %w", i+1, err)
+		}
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+		replicas = append(replicas, db)
+	}
+
+	s := NewReplicaSet(primary, replicas...)
+	s.CheckHealth(context.Background())
+	return s, nil
+}
+
+// replicaDialector 根据驱动和 DSN 创建副本的 GORM 方言
+func replicaDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "", config.DriverMySQL:
+		return mysql.Open(dsn), nil
+	case config.DriverPostgres:
+		return postgres.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("数据库驱动 %s 不支持只读副本", driver)
+	}
+}
+
+// Read 在副本上执行只读查询，失败时回退到主库重试
+// fn 可能被调用两次，不得包含写操作或其他副作用
+func (s *ReplicaSet) Read(ctx context.Context, fn func(db *gorm.DB) error) error {
+	r := s.pick()
+	if r == nil {
+		return fn(s.primary.WithContext(ctx))
+	}
+
+	err := fn(r.db.WithContext(ctx))
+	if err == nil || errors.Is(err, gorm.ErrRecordNotFound) || ctx.Err() != nil {
+		return err
+	}
+
+	if r.healthy.CompareAndSwap(true, false) {
+		log.Printf("只读副本 #%d 查询失败，暂停使用并回退到主库: %v", r.index, err)
+	}
+	return fn(s.primary.WithContext(ctx))
+}
+
+// pick 按轮询选择一个健康的副本，全部不可用时返回 nil
+func (s *ReplicaSet) pick() *replica {
+	n := uint64(len(s.replicas))
+	if n == 0 {
+		return nil
+	}
+
+	start := s.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		r := s.replicas[(start+i)%n]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// CheckHealth 检查全部副本的连接，更新可用状态
+func (s *ReplicaSet) CheckHealth(ctx context.Context) {
+	for _, r := range s.replicas {
+		err := pingReplica(ctx, r.db)
+		healthy := err == nil
+		if r.healthy.Swap(healthy) == healthy {
+			continue
+		}
+		if healthy {
+			log.Printf("只读副本 #%d 已恢复", r.index)
+		} else {
+			log.Printf("只读副本 #%d 不可用，回退到主库: %v", r.index, err)
+		}
+	}
+}
+
+// pingReplica 在超时内 Ping 副本
+func pingReplica(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// Healthy 返回可用副本数和副本总数
+func (s *ReplicaSet) Healthy() (healthy, total int) {
+	for _, r := range s.replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy, len(s.replicas)
+}
+
+// StartHealthCheck 在后台按间隔执行健康检查，未配置副本时不启动
+func (s *ReplicaSet) StartHealthCheck(interval time.Duration) {
+	if len(s.replicas) == 0 || interval <= 0 || s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.CheckHealth(context.Background())
+			}
+		}
+	}()
+}
+
+// Close 停止健康检查并关闭副本连接，主库连接由调用方关闭
+func (s *ReplicaSet) Close() error {
+	s.stopOnce.Do(func() {
+		if s.stop != nil {
+			close(s.stop)
+			<-s.done
+		}
+	})
+
+	dbs := make([]*gorm.DB, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		dbs = append(dbs, r.db)
+	}
+	return closeAll(dbs)
+}
+
+// closeAll 关闭全部连接，返回遇到的第一个错误
+func closeAll(dbs []*gorm.DB) error {
+	var firstErr error
+	for _, db := range dbs {
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/config"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+// setupReplicaTestDB 创建只包含一个发送群组的数据库，用于区分查询落在哪个库
+func setupReplicaTestDB(t *testing.T, chatID string) *gorm.DB {
+	t.Helper()
+	db := setupMigrateTestDB(t)
+	if err := db.AutoMigrate(&model.SendChat{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	db.Create(&model.SendChat{ChatID: chatID})
+	return db
+}
+
+// readChatID 通过副本集合读取群组 ID
+func readChatID(t *testing.T, s *ReplicaSet) string {
+	t.Helper()
+	var chat model.SendChat
+	if err := s.Read(context.Background(), func(db *gorm.DB) error {
+		return db.First(&chat).Error
+	}); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	return chat.ChatID
+}
+
+func TestReplicaSet_RoundRobin(t *testing.T) {
+	s := NewReplicaSet(
+		setupReplicaTestDB(t, "primary"),
+		setupReplicaTestDB(t, "replica1"),
+		setupReplicaTestDB(t, "replica2"),
+	)
+
+	seen := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		seen[readChatID(t, s)]++
+	}
+	if seen["replica1"] != 2 || seen["replica2"] != 2 {
+		t.Errorf("reads = %v, want 2 per replica", seen)
+	}
+}
+
+func TestReplicaSet_NoReplicas(t *testing.T) {
+	s := NewReplicaSet(setupReplicaTestDB(t, "primary"))
+	if got := readChatID(t, s); got != "primary" {
+		t.Errorf("Read() used %s, want primary", got)
+	}
+	if healthy, total := s.Healthy(); healthy != 0 || total != 0 {
+		t.Errorf("Healthy() = %d/%d, want 0/0", healthy, total)
+	}
+}
+
+func TestReplicaSet_FallbackToPrimary(t *testing.T) {
+	// 副本缺少表，查询失败
+	broken := setupMigrateTestDB(t)
+	s := NewReplicaSet(setupReplicaTestDB(t, "primary"), broken)
+
+	if got := readChatID(t, s); got != "primary" {
+		t.Errorf("Read() used %s, want fallback to primary", got)
+	}
+	if healthy, _ := s.Healthy(); healthy != 0 {
+		t.Errorf("healthy replicas = %d, want 0 after failure", healthy)
+	}
+
+	// 不可用期间直接使用主库；健康检查通过后重新启用
+	if got := readChatID(t, s); got != "primary" {
+		t.Errorf("Read() used %s, want primary", got)
+	}
+	broken.AutoMigrate(&model.SendChat{})
+	broken.Create(&model.SendChat{ChatID: "replica"})
+	s.CheckHealth(context.Background())
+	if got := readChatID(t, s); got != "replica" {
+		t.Errorf("Read() used %s, want replica after recovery", got)
+	}
+}
+
+func TestReplicaSet_NotFoundIsNotFailure(t *testing.T) {
+	s := NewReplicaSet(setupReplicaTestDB(t, "primary"), setupReplicaTestDB(t, "replica"))
+
+	var chat model.SendChat
+	err := s.Read(context.Background(), func(db *gorm.DB) error {
+		return db.Where("chat_id = ?", "missing").First(&chat).Error
+	})
+	if err != gorm.ErrRecordNotFound {
+		t.Errorf("Read() error = %v, want ErrRecordNotFound", err)
+	}
+	if healthy, _ := s.Healthy(); healthy != 1 {
+		t.Errorf("healthy replicas = %d, want 1", healthy)
+	}
+}
+
+func TestReplicaSet_HealthCheck(t *testing.T) {
+	replica := setupReplicaTestDB(t, "replica")
+	s := NewReplicaSet(setupReplicaTestDB(t, "primary"), replica)
+	s.StartHealthCheck(10 * time.Millisecond)
+
+	// 副本连接关闭后健康检查将其标记为不可用
+	sqlDB, _ := replica.DB()
+	sqlDB.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if healthy, _ := s.Healthy(); healthy == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("replica should be marked unhealthy by the health check")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := readChatID(t, s); got != "primary" {
+		t.Errorf("Read() used %s, want primary", got)
+	}
+
+	s.Close()
+	// 重复关闭不应阻塞
+	s.Close()
+}
+
+func TestOpenReplicas_UnsupportedDriver(t *testing.T) {
+	cfg := &config.DatabaseConfig{Driver: config.DriverSQLite, ReplicaDSNs: []string{"replica.db"}}
+	if _, err := OpenReplicas(setupMigrateTestDB(t), cfg); err == nil {
+		t.Error("OpenReplicas() should reject SQLite replicas")
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+// CacheHandler 进程内缓存处理器（管理 API）
+type CacheHandler struct {
+	caches []service.Cache
+}
+
+// NewCacheHandler 创建进程内缓存处理器
+func NewCacheHandler(caches []service.Cache) *CacheHandler {
+	return &CacheHandler{
+		caches: caches,
+	}
+}
+
+// GetStats 获取各缓存的命中统计
+func (h *CacheHandler) GetStats(c *gin.Context) {
+	stats := make([]service.CacheStats, 0, len(h.caches))
+	for _, cache := range h.caches {
+		stats = append(stats, cache.Stats()...)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// InvalidateAll 清空全部缓存
+// 直接修改数据库（如执行迁移或手工 SQL）后调用，无需等待缓存过期
+func (h *CacheHandler) InvalidateAll(c *gin.Context) {
+	for _, cache := range h.caches {
+		cache.Invalidate()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
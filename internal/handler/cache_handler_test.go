@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/repository/memory"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+func setupCacheTestRouter(t *testing.T) (*gin.Engine, *service.CachedUserTemplateRepository) {
+	store := memory.NewSeededStore()
+	templates := service.NewCachedUserTemplateRepository(store.UserTemplates, time.Minute)
+	exams := service.NewCachedExamDateRepository(store.ExamDates, time.Minute)
+
+	handler := NewCacheHandler([]service.Cache{exams, templates})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/cache", handler.GetStats)
+	router.DELETE("/cache", handler.InvalidateAll)
+
+	return router, templates
+}
+
+func getCacheStats(t *testing.T, router *gin.Engine) []service.CacheStats {
+	req := httptest.NewRequest(http.MethodGet, "/cache", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp struct {
+		Success bool                 `json:"success"`
+		Data    []service.CacheStats `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !resp.Success {
+		t.Fatal("success = false, want true")
+	}
+	return resp.Data
+}
+
+func TestCacheHandler(t *testing.T) {
+	router, templates := setupCacheTestRouter(t)
+
+	templates.GetDefaultTemplate(context.Background())
+	templates.GetDefaultTemplate(context.Background())
+
+	stats := getCacheStats(t, router)
+	if len(stats) != 3 {
+		t.Fatalf("len(stats) = %d, want 3", len(stats))
+	}
+	byName := make(map[string]service.CacheStats)
+	for _, s := range stats {
+		byName[s.Name] = s
+	}
+	if s := byName["default_template"]; s.Hits != 1 || s.Misses != 1 || s.Entries != 1 {
+		t.Errorf("default_template stats = %+v, want hits=1 misses=1 entries=1", s)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/cache", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	for _, s := range getCacheStats(t, router) {
+		if s.Entries != 0 {
+			t.Errorf("%s entries = %d after invalidation, want 0", s.Name, s.Entries)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+// icsContentType iCalendar 响应类型
+const icsContentType = "text/calendar; charset=utf-8"
+
+// CalendarFeedHandler iCalendar 订阅处理器
+type CalendarFeedHandler struct {
+	feedService *service.CalendarFeedService
+}
+
+// NewCalendarFeedHandler 创建 iCalendar 订阅处理器
+func NewCalendarFeedHandler(feedService *service.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		feedService: feedService,
+	}
+}
+
+// GetPublicFeed 公共订阅（可选 province 参数附加该省考试）
+func (h *CalendarFeedHandler) GetPublicFeed(c *gin.Context) {
+	feed, err := h.feedService.BuildPublicFeed(c.Request.Context(), c.Query("province"), util.NowBJT())
+	if err != nil {
+		h.respondFeedError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, icsContentType, []byte(feed))
+}
+
+// GetUserFeed 个人订阅，路径参数为 <user_id>-<signature>.ics
+// 日历客户端无法携带 initData，因此以签名链接代替登录认证
+func (h *CalendarFeedHandler) GetUserFeed(c *gin.Context) {
+	userID, err := h.feedService.ParseUserFeedFile(c.Param("file"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "订阅不存在",
+		})
+		return
+	}
+
+	feed, err := h.feedService.BuildUserFeed(c.Request.Context(), userID, c.Query("province"), util.NowBJT())
+	if err != nil {
+		h.respondFeedError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, icsContentType, []byte(feed))
+}
+
+// GetFeedLink 获取当前用户的订阅链接（需要认证）
+func (h *CalendarFeedHandler) GetFeedLink(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	province := c.Query("province")
+
+	publicURL, err := h.feedService.PublicFeedURL(province)
+	if err != nil {
+		h.respondFeedError(c, err)
+		return
+	}
+	personalURL, err := h.feedService.UserFeedURL(userID, province)
+	if err != nil {
+		h.respondFeedError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"public_url":   publicURL,
+			"personal_url": personalURL,
+		},
+	})
+}
+
+// respondFeedError 将订阅服务错误转换为响应
+func (h *CalendarFeedHandler) respondFeedError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidProvince):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "省份名称无效",
+		})
+	case errors.Is(err, service.ErrCalendarFeedNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   "日历订阅未启用",
+		})
+	default:
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "生成日历失败，请稍后重试",
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupCalendarFeedTestRouter(t *testing.T, publicURL string) (*gin.Engine, *service.CalendarFeedService, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ExamDate{}, &model.UserTarget{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	feedService := service.NewCalendarFeedService(
+		repository.NewExamDateRepository(db),
+		repository.NewUserTargetRepository(db),
+		"test_bot_token",
+		publicURL,
+	)
+	handler := NewCalendarFeedHandler(feedService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/calendar.ics", handler.GetPublicFeed)
+	router.GET("/api/calendar/user/:file", handler.GetUserFeed)
+	router.GET("/api/calendar/link", func(c *gin.Context) {
+		c.Set("user_id", int64(123))
+		c.Next()
+	}, handler.GetFeedLink)
+
+	return router, feedService, db
+}
+
+func TestGetPublicFeed(t *testing.T) {
+	router, _, db := setupCalendarFeedTestRouter(t, "")
+
+	begin := time.Now().AddDate(0, 3, 0)
+	db.Create(&model.ExamDate{
+		ID: 1, ExamYear: begin.Year(), ExamKind: "gaokao", ShortDesc: "高考",
+		ExamBeginDate: begin, ExamEndDate: begin.AddDate(0, 0, 3),
+		ExamYearBeginDate: begin.AddDate(-1, 0, 0), ExamYearEndDate: begin.AddDate(0, 0, 3),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar.ics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/calendar") {
+		t.Errorf("Content-Type = %s, want text/calendar", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "SUMMARY:高考") {
+		t.Errorf("Expected exam event, got %s", w.Body.String())
+	}
+}
+
+func TestGetPublicFeed_InvalidProvince(t *testing.T) {
+	router, _, _ := setupCalendarFeedTestRouter(t, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar.ics?province="+url.QueryEscape("火星"), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetUserFeed(t *testing.T) {
+	router, feedService, db := setupCalendarFeedTestRouter(t, "")
+
+	db.Create(&model.UserTarget{ID: 1, UserID: 123, TargetName: "一模", TargetDate: time.Now().AddDate(0, 1, 0)})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/user/123-"+feedService.Sign(123)+".ics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), "SUMMARY:一模") {
+		t.Errorf("Expected user target, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/calendar/user/123-forged.ics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Forged signature status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetFeedLink(t *testing.T) {
+	router, feedService, _ := setupCalendarFeedTestRouter(t, "https://example.com")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/link", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var response struct {
+		Success bool `json:"success"`
+		Data    struct {
+			PublicURL   string `json:"public_url"`
+			PersonalURL string `json:"personal_url"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Data.PublicURL != "https://example.com/api/calendar.ics" {
+		t.Errorf("PublicURL = %s", response.Data.PublicURL)
+	}
+	if !strings.Contains(response.Data.PersonalURL, "/api/calendar/user/123-"+feedService.Sign(123)+".ics") {
+		t.Errorf("PersonalURL = %s", response.Data.PersonalURL)
+	}
+}
+
+func TestGetFeedLink_NotConfigured(t *testing.T) {
+	router, _, _ := setupCalendarFeedTestRouter(t, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/link", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+// MaxCalendarImportSize 考试日历导入请求体最大字节数
+const MaxCalendarImportSize = 1 << 20 // 1MB
+
+// ExamCalendarHandler 考试日历导入导出处理器（管理 API）
+type ExamCalendarHandler struct {
+	calendarService *service.ExamCalendarService
+}
+
+// NewExamCalendarHandler 创建考试日历导入导出处理器
+func NewExamCalendarHandler(calendarService *service.ExamCalendarService) *ExamCalendarHandler {
+	return &ExamCalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// ExportCalendar 导出考试日历
+// 通过 format 查询参数选择格式（json / yaml / csv），默认 json
+func (h *ExamCalendarHandler) ExportCalendar(c *gin.Context) {
+	format, err := service.ParseCalendarFormat(c.DefaultQuery("format", string(service.CalendarFormatJSON)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	cal, err := h.calendarService.Export(c.Request.Context())
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "导出考试日历失败，请稍后重试",
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := service.EncodeExamCalendar(&buf, cal, format); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "导出考试日历失败，请稍后重试",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="exam_calendar.%s"`, format))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// ImportCalendar 导入考试日历
+// 请求体为日历文件原文，format 查询参数指定格式（默认 json），
+// dry_run=true 时仅返回与数据库的差异而不写入
+func (h *ExamCalendarHandler) ImportCalendar(c *gin.Context) {
+	format, err := service.ParseCalendarFormat(c.DefaultQuery("format", string(service.CalendarFormatJSON)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "dry_run 参数无效",
+		})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, MaxCalendarImportSize)
+	cal, err := service.DecodeExamCalendar(body, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := cal.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	diff, err := h.calendarService.Import(c.Request.Context(), cal, dryRun)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "导入考试日历失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    diff,
+	})
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const testCalendarYAML = `version: 1
+exams:
+  - year: 2026
+    kind: gaokao
+    desc: 2026年普通高等学校招生全国统一考试
+    short_desc: 2026年高考
+    begin_date: "2026-06-07 09:00:00"
+    end_date: "2026-06-10 17:00:00"
+    year_begin_date: "2025-06-10 00:00:00"
+    year_end_date: "2026-06-10 17:00:00"
+`
+
+func setupCalendarTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ExamDate{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	handler := NewExamCalendarHandler(service.NewExamCalendarService(repository.NewExamDateRepository(db)))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/exams/export", handler.ExportCalendar)
+	router.POST("/exams/import", handler.ImportCalendar)
+
+	return router, db
+}
+
+func TestImportCalendar_DryRunThenApply(t *testing.T) {
+	router, db := setupCalendarTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/exams/import?format=yaml&dry_run=true", strings.NewReader(testCalendarYAML))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var response struct {
+		Success bool                     `json:"success"`
+		Data    service.ExamCalendarDiff `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !response.Success || response.Data.Created != 1 || response.Data.Applied {
+		t.Errorf("Unexpected dry run response: %+v", response)
+	}
+
+	var count int64
+	db.Model(&model.ExamDate{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("Dry run should not write, got %d rows", count)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/exams/import?format=yaml", strings.NewReader(testCalendarYAML))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	db.Model(&model.ExamDate{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 row after import, got %d", count)
+	}
+}
+
+func TestImportCalendar_InvalidRequests(t *testing.T) {
+	router, _ := setupCalendarTestRouter(t)
+
+	tests := []struct {
+		name string
+		url  string
+		body string
+	}{
+		{"unsupported format", "/exams/import?format=xml", testCalendarYAML},
+		{"invalid dry_run", "/exams/import?format=yaml&dry_run=maybe", testCalendarYAML},
+		{"malformed body", "/exams/import?format=json", "{"},
+		{"validation error", "/exams/import?format=yaml", strings.Replace(testCalendarYAML, "2026-06-07 09:00:00", "bad", 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestExportCalendar(t *testing.T) {
+	router, _ := setupCalendarTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/exams/import?format=yaml", strings.NewReader(testCalendarYAML))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/exams/export?format=csv", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Errorf("Content-Type = %s, want text/csv", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), "exam_calendar.csv") {
+		t.Errorf("Content-Disposition = %s", w.Header().Get("Content-Disposition"))
+	}
+	if !strings.Contains(w.Body.String(), "2026,gaokao") {
+		t.Errorf("Expected exported row, got %s", w.Body.String())
+	}
+}
+
+func TestExportCalendar_InvalidFormat(t *testing.T) {
+	router, _ := setupCalendarTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/exams/export?format=xml", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
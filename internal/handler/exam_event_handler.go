@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// MaxEventNameLength 考后事件名称最大长度（字符数）
+const MaxEventNameLength = 32
+
+// ExamEventHandler 考后时间线事件处理器（管理 API）
+type ExamEventHandler struct {
+	eventService *service.ExamEventService
+}
+
+// NewExamEventHandler 创建考后时间线事件处理器
+func NewExamEventHandler(eventService *service.ExamEventService) *ExamEventHandler {
+	return &ExamEventHandler{
+		eventService: eventService,
+	}
+}
+
+// CreateEventRequest 创建考后事件请求
+type CreateEventRequest struct {
+	ExamYear  int       `json:"exam_year" binding:"required"`
+	ExamKind  string    `json:"exam_kind"`
+	Region    string    `json:"region"`
+	EventType string    `json:"event_type" binding:"required"`
+	EventName string    `json:"event_name"`
+	EventDate time.Time `json:"event_date" binding:"required"`
+}
+
+// GetEvents 按年份获取考后事件
+func (h *ExamEventHandler) GetEvents(c *gin.Context) {
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil || year < constant.MinExamYear || year > constant.MaxExamYear {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "年份无效",
+		})
+		return
+	}
+
+	events, err := h.eventService.GetByYear(c.Request.Context(), year)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取考后事件失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    events,
+	})
+}
+
+// CreateEvent 创建考后事件
+// event_name 为空时使用事件类型的默认名称
+func (h *ExamEventHandler) CreateEvent(c *gin.Context) {
+	var req CreateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	event, err := newExamEvent(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.eventService.Create(c.Request.Context(), event); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "创建考后事件失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
+
+// DeleteEvent 删除考后事件
+func (h *ExamEventHandler) DeleteEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "事件ID无效",
+		})
+		return
+	}
+
+	event, err := h.eventService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取考后事件失败，请稍后重试",
+		})
+		return
+	}
+
+	if event == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "事件不存在",
+		})
+		return
+	}
+
+	if err := h.eventService.Delete(c.Request.Context(), event.ID); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "删除考后事件失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// newExamEvent 校验请求并构造事件
+func newExamEvent(req *CreateEventRequest) (*model.ExamEvent, error) {
+	if req.ExamYear < constant.MinExamYear || req.ExamYear > constant.MaxExamYear {
+		return nil, fmt.Errorf("年份必须在 %d-%d 之间", constant.MinExamYear, constant.MaxExamYear)
+	}
+
+	defaultName, ok := constant.ExamEventNames[req.EventType]
+	if !ok {
+		return nil, fmt.Errorf("未知的事件类型: %s", req.EventType)
+	}
+
+	if req.Region != "" && !constant.IsValidProvince(req.Region) {
+		return nil, fmt.Errorf("无效的地区: %s", req.Region)
+	}
+
+	name := strings.TrimSpace(req.EventName)
+	if name == "" {
+		name = defaultName
+	}
+	if charCount := utf8.RuneCountInString(name); charCount > MaxEventNameLength {
+		return nil, fmt.Errorf("事件名称不能超过 %d 字符（当前 %d 字符）", MaxEventNameLength, charCount)
+	}
+
+	kind := req.ExamKind
+	if kind == "" {
+		kind = constant.ExamKindGaokao
+	}
+
+	return &model.ExamEvent{
+		ExamYear:  req.ExamYear,
+		ExamKind:  kind,
+		Region:    req.Region,
+		EventType: req.EventType,
+		EventName: name,
+		EventDate: req.EventDate.In(util.GetBJTLocation()),
+	}, nil
+}
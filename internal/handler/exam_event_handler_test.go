@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupExamEventTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ExamDate{}, &model.ExamEvent{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	handler := NewExamEventHandler(service.NewExamEventService(
+		repository.NewExamEventRepository(db),
+		repository.NewExamDateRepository(db),
+	))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/events", handler.GetEvents)
+	router.POST("/events", handler.CreateEvent)
+	router.DELETE("/events/:id", handler.DeleteEvent)
+
+	return router, db
+}
+
+func postEvent(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateEvent(t *testing.T) {
+	router, _ := setupExamEventTestRouter(t)
+
+	w := postEvent(router, `{"exam_year":2026,"event_type":"score_release","event_date":"2026-06-25T12:00:00+08:00"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var response struct {
+		Success bool            `json:"success"`
+		Data    model.ExamEvent `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Data.EventName != "成绩公布" || response.Data.ExamKind != "gaokao" {
+		t.Errorf("Unexpected event: %+v", response.Data)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/events?year=2026", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var list struct {
+		Data []model.ExamEvent `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 {
+		t.Errorf("Expected 1 event, got %d", len(list.Data))
+	}
+}
+
+func TestCreateEvent_Invalid(t *testing.T) {
+	router, _ := setupExamEventTestRouter(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing fields", `{"exam_year":2026}`},
+		{"invalid year", `{"exam_year":1990,"event_type":"score_release","event_date":"2026-06-25T12:00:00+08:00"}`},
+		{"unknown type", `{"exam_year":2026,"event_type":"party","event_date":"2026-06-25T12:00:00+08:00"}`},
+		{"invalid region", `{"exam_year":2026,"region":"火星","event_type":"score_release","event_date":"2026-06-25T12:00:00+08:00"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postEvent(router, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetEvents_InvalidYear(t *testing.T) {
+	router, _ := setupExamEventTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?year=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDeleteEvent(t *testing.T) {
+	router, db := setupExamEventTestRouter(t)
+
+	postEvent(router, `{"exam_year":2026,"event_type":"score_release","event_date":"2026-06-25T12:00:00+08:00"}`)
+
+	tests := []struct {
+		name string
+		id   string
+		want int
+	}{
+		{"invalid id", "abc", http.StatusBadRequest},
+		{"not found", "999", http.StatusNotFound},
+		{"existing", "1", http.StatusOK},
+		{"already deleted", "1", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodDelete, "/events/"+tt.id, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: Status = %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+
+	var count int64
+	db.Model(&model.ExamEvent{}).Where("is_delete = ?", true).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected event to be soft deleted, got %d deleted rows", count)
+	}
+}
@@ -0,0 +1,406 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+// GalleryHandler 模板广场处理器
+type GalleryHandler struct {
+	galleryService  *service.GalleryService
+	templateService *service.UserTemplateService
+}
+
+// NewGalleryHandler 创建模板广场处理器
+func NewGalleryHandler(galleryService *service.GalleryService, templateService *service.UserTemplateService) *GalleryHandler {
+	return &GalleryHandler{
+		galleryService:  galleryService,
+		templateService: templateService,
+	}
+}
+
+// PublishTemplateRequest 发布模板到广场请求
+type PublishTemplateRequest struct {
+	TemplateID int64 `json:"template_id,string" binding:"required"`
+}
+
+// GetGallery 分页浏览已公开的模板
+// 查询参数：q 关键词，sort 排序（popular 热度 / latest 最新），page 页码，page_size 每页条数
+func (h *GalleryHandler) GetGallery(c *gin.Context) {
+	sort := c.DefaultQuery("sort", repository.GallerySortPopular)
+	if sort != repository.GallerySortPopular && sort != repository.GallerySortLatest {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "排序方式无效",
+		})
+		return
+	}
+
+	page, pageSize, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.galleryService.Browse(c.Request.Context(), c.Query("q"), sort, page, pageSize, c.GetInt64("user_id"))
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取模板广场失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// GetMyGallery 获取当前用户发布的条目及审核状态
+func (h *GalleryHandler) GetMyGallery(c *gin.Context) {
+	items, err := h.galleryService.ListByUser(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取发布记录失败，请稍后重试",
+		})
+		return
+	}
+
+	if items == nil {
+		items = []model.GalleryTemplate{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    items,
+	})
+}
+
+// PublishTemplate 将自己的模板发布到广场，审核通过后公开
+func (h *GalleryHandler) PublishTemplate(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	var req PublishTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	template, err := h.templateService.GetByID(c.Request.Context(), req.TemplateID)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取模板失败，请稍后重试",
+		})
+		return
+	}
+
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "模板不存在",
+		})
+		return
+	}
+
+	if template.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "无权限访问此模板",
+		})
+		return
+	}
+
+	// 发布前再次校验，避免历史数据绕过当前规则
+	err = service.ValidateTemplateContent(template.TemplateContent)
+	if err == nil {
+		err = service.ValidateTemplateName(template.TemplateName)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	item, err := h.galleryService.Publish(c.Request.Context(), template)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "发布模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    item,
+	})
+}
+
+// UnpublishTemplate 从广场撤下自己发布的条目
+func (h *GalleryHandler) UnpublishTemplate(c *gin.Context) {
+	id, ok := parseGalleryID(c)
+	if !ok {
+		return
+	}
+
+	item, err := h.galleryService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取条目失败，请稍后重试",
+		})
+		return
+	}
+
+	if item == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "条目不存在",
+		})
+		return
+	}
+
+	if item.UserID != c.GetInt64("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "无权限访问此条目",
+		})
+		return
+	}
+
+	if err := h.galleryService.Unpublish(c.Request.Context(), id); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "撤下条目失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// LikeTemplate 点赞条目，重复点赞不计数
+func (h *GalleryHandler) LikeTemplate(c *gin.Context) {
+	h.updateLike(c, h.galleryService.Like)
+}
+
+// UnlikeTemplate 取消点赞
+func (h *GalleryHandler) UnlikeTemplate(c *gin.Context) {
+	h.updateLike(c, h.galleryService.Unlike)
+}
+
+// CopyTemplate 将条目复制到自己的模板
+func (h *GalleryHandler) CopyTemplate(c *gin.Context) {
+	id, ok := parseGalleryID(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.galleryService.Copy(c.Request.Context(), id, c.GetInt64("user_id"))
+	if err != nil {
+		var moderationErr *service.ModerationError
+		var variableErr *service.UndefinedVariableError
+		switch {
+		case errors.Is(err, service.ErrGalleryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "条目不存在",
+			})
+		case errors.Is(err, service.ErrCopyOwnTemplate):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "不能复制自己发布的模板",
+			})
+		case errors.Is(err, repository.ErrTemplateLimitExceeded):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("模板数量已达上限（最多 %d 个）", MaxTemplatesPerUser),
+			})
+		case errors.As(err, &moderationErr):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   moderationErr.Error(),
+			})
+		case errors.As(err, &variableErr):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   variableErr.Error(),
+			})
+		default:
+			// 不暴露内部错误详情
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "复制模板失败，请稍后重试",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// GetModerationQueue 按审核状态分页查询条目（管理 API），默认返回待审核条目
+func (h *GalleryHandler) GetModerationQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", model.GalleryStatusPending)
+	switch status {
+	case model.GalleryStatusPending, model.GalleryStatusApproved, model.GalleryStatusHidden:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "审核状态无效",
+		})
+		return
+	}
+
+	page, pageSize, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.galleryService.ListByStatus(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取审核队列失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ApproveTemplate 审核通过条目（管理 API）
+func (h *GalleryHandler) ApproveTemplate(c *gin.Context) {
+	h.moderate(c, h.galleryService.Approve)
+}
+
+// HideTemplate 隐藏条目（管理 API）
+func (h *GalleryHandler) HideTemplate(c *gin.Context) {
+	h.moderate(c, h.galleryService.Hide)
+}
+
+// updateLike 执行点赞或取消点赞，返回最新的条目
+func (h *GalleryHandler) updateLike(c *gin.Context, update func(ctx context.Context, id, userID int64) (*model.GalleryTemplate, error)) {
+	id, ok := parseGalleryID(c)
+	if !ok {
+		return
+	}
+
+	item, err := update(c.Request.Context(), id, c.GetInt64("user_id"))
+	if errors.Is(err, service.ErrGalleryNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "条目不存在",
+		})
+		return
+	}
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "操作失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    item,
+	})
+}
+
+// moderate 执行审核操作，返回更新后的条目
+func (h *GalleryHandler) moderate(c *gin.Context, action func(ctx context.Context, id int64) (*model.GalleryTemplate, error)) {
+	id, ok := parseGalleryID(c)
+	if !ok {
+		return
+	}
+
+	item, err := action(c.Request.Context(), id)
+	if errors.Is(err, service.ErrGalleryNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "条目不存在",
+		})
+		return
+	}
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "审核操作失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    item,
+	})
+}
+
+// parseGalleryID 解析路径中的条目 ID，失败时已写入响应
+func parseGalleryID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "条目ID无效",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// parsePagination 解析分页参数，省略时使用默认值，失败时已写入响应
+func parsePagination(c *gin.Context) (page, pageSize int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "页码无效",
+		})
+		return 0, 0, false
+	}
+
+	pageSize, err = strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(service.DefaultGalleryPageSize)))
+	if err != nil || pageSize < 1 || pageSize > service.MaxGalleryPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("每页条数必须在 1-%d 之间", service.MaxGalleryPageSize),
+		})
+		return 0, 0, false
+	}
+
+	return page, pageSize, true
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupGalleryTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.GalleryTemplate{}, &model.GalleryLike{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	templateService := service.NewUserTemplateService(repository.NewUserTemplateRepository(db))
+	handler := NewGalleryHandler(service.NewGalleryService(repository.NewGalleryRepository(db), templateService), templateService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	// 通过请求头切换用户，便于测试多用户交互
+	router.Use(func(c *gin.Context) {
+		userID := int64(123)
+		if c.GetHeader("X-Test-User") == "456" {
+			userID = 456
+		}
+		c.Set("user_id", userID)
+		c.Next()
+	})
+
+	router.GET("/gallery", handler.GetGallery)
+	router.GET("/gallery/mine", handler.GetMyGallery)
+	router.POST("/gallery", handler.PublishTemplate)
+	router.DELETE("/gallery/:id", handler.UnpublishTemplate)
+	router.POST("/gallery/:id/like", handler.LikeTemplate)
+	router.DELETE("/gallery/:id/like", handler.UnlikeTemplate)
+	router.POST("/gallery/:id/copy", handler.CopyTemplate)
+	router.GET("/admin/gallery", handler.GetModerationQueue)
+	router.POST("/admin/gallery/:id/approve", handler.ApproveTemplate)
+	router.POST("/admin/gallery/:id/hide", handler.HideTemplate)
+	return router, db
+}
+
+func doGalleryRequest(router *gin.Engine, method, path, body, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGalleryHandler_Flow(t *testing.T) {
+	router, db := setupGalleryTestRouter(t)
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateName: "加油", TemplateContent: "距离{exam}还有{time}"})
+
+	// 发布后进入审核队列
+	w := doGalleryRequest(router, http.MethodPost, "/gallery", `{"template_id":"1"}`, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("publish status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var published struct {
+		Data model.GalleryTemplate `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &published); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if published.Data.Status != model.GalleryStatusPending {
+		t.Errorf("published status = %s, want pending", published.Data.Status)
+	}
+	id := published.Data.ID
+	idPath := "/gallery/" + strconv.FormatInt(id, 10)
+
+	// 审核前不公开
+	var page struct {
+		Data service.GalleryPage `json:"data"`
+	}
+	w = doGalleryRequest(router, http.MethodGet, "/gallery", "", "456")
+	_ = json.Unmarshal(w.Body.Bytes(), &page)
+	if page.Data.Total != 0 {
+		t.Errorf("gallery total before approval = %d, want 0", page.Data.Total)
+	}
+	if w := doGalleryRequest(router, http.MethodPost, idPath+"/like", "", "456"); w.Code != http.StatusNotFound {
+		t.Errorf("like pending status = %d, want 404", w.Code)
+	}
+
+	w = doGalleryRequest(router, http.MethodGet, "/admin/gallery", "", "")
+	_ = json.Unmarshal(w.Body.Bytes(), &page)
+	if page.Data.Total != 1 || page.Data.Items[0].ID != id {
+		t.Errorf("moderation queue = %s", w.Body.String())
+	}
+
+	if w := doGalleryRequest(router, http.MethodPost, "/admin"+idPath+"/approve", "", ""); w.Code != http.StatusOK {
+		t.Fatalf("approve status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	// 点赞与浏览
+	if w := doGalleryRequest(router, http.MethodPost, idPath+"/like", "", "456"); w.Code != http.StatusOK {
+		t.Fatalf("like status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	w = doGalleryRequest(router, http.MethodGet, "/gallery?q=加油&sort=latest", "", "456")
+	_ = json.Unmarshal(w.Body.Bytes(), &page)
+	if page.Data.Total != 1 || !page.Data.Items[0].Liked || page.Data.Items[0].LikeCount != 1 {
+		t.Errorf("gallery = %s", w.Body.String())
+	}
+
+	// 复制到其他用户
+	w = doGalleryRequest(router, http.MethodPost, idPath+"/copy", "", "456")
+	if w.Code != http.StatusOK {
+		t.Fatalf("copy status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var count int64
+	db.Model(&model.UserTemplate{}).Where("user_id = ?", 456).Count(&count)
+	if count != 1 {
+		t.Errorf("copied templates = %d, want 1", count)
+	}
+	if w := doGalleryRequest(router, http.MethodPost, idPath+"/copy", "", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("copy own status = %d, want 400", w.Code)
+	}
+
+	// 只有发布者可以撤下
+	if w := doGalleryRequest(router, http.MethodDelete, idPath, "", "456"); w.Code != http.StatusForbidden {
+		t.Errorf("unpublish by other status = %d, want 403", w.Code)
+	}
+	if w := doGalleryRequest(router, http.MethodDelete, idPath, "", ""); w.Code != http.StatusOK {
+		t.Errorf("unpublish status = %d, want 200", w.Code)
+	}
+	w = doGalleryRequest(router, http.MethodGet, "/gallery/mine", "", "")
+	if w.Code != http.StatusOK || w.Body.String() != `{"data":[],"success":true}` {
+		t.Errorf("mine after unpublish = %s", w.Body.String())
+	}
+}
+
+func TestGalleryHandler_Errors(t *testing.T) {
+	router, db := setupGalleryTestRouter(t)
+	db.Create(&model.UserTemplate{ID: 2, UserID: 456, TemplateContent: "距离{exam}还有{time}"})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"invalid sort", http.MethodGet, "/gallery?sort=random", "", http.StatusBadRequest},
+		{"invalid page", http.MethodGet, "/gallery?page=0", "", http.StatusBadRequest},
+		{"page size too large", http.MethodGet, "/gallery?page_size=1000", "", http.StatusBadRequest},
+		{"invalid body", http.MethodPost, "/gallery", `{}`, http.StatusBadRequest},
+		{"template not found", http.MethodPost, "/gallery", `{"template_id":"999"}`, http.StatusNotFound},
+		{"template forbidden", http.MethodPost, "/gallery", `{"template_id":"2"}`, http.StatusForbidden},
+		{"invalid id", http.MethodPost, "/gallery/abc/like", "", http.StatusBadRequest},
+		{"copy not found", http.MethodPost, "/gallery/999/copy", "", http.StatusNotFound},
+		{"unpublish not found", http.MethodDelete, "/gallery/999", "", http.StatusNotFound},
+		{"invalid status", http.MethodGet, "/admin/gallery?status=deleted", "", http.StatusBadRequest},
+		{"approve not found", http.MethodPost, "/admin/gallery/999/approve", "", http.StatusNotFound},
+		{"hide not found", http.MethodPost, "/admin/gallery/999/hide", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doGalleryRequest(router, tt.method, tt.path, tt.body, "")
+			if w.Code != tt.want {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+// ModerationHandler 模板内容审核复核处理器（管理 API）
+type ModerationHandler struct {
+	moderationService *service.TemplateModerationService
+}
+
+// NewModerationHandler 创建模板内容审核复核处理器
+func NewModerationHandler(moderationService *service.TemplateModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// GetRecords 按状态分页查询未通过审核的提交，默认返回待复核的记录
+// 查询参数：status 状态（pending / approved / dismissed），page 页码，page_size 每页条数
+func (h *ModerationHandler) GetRecords(c *gin.Context) {
+	status := c.DefaultQuery("status", model.ModerationStatusPending)
+	switch status {
+	case model.ModerationStatusPending, model.ModerationStatusApproved, model.ModerationStatusDismissed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "审核状态无效",
+		})
+		return
+	}
+
+	page, pageSize, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.moderationService.ListByStatus(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取审核记录失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ApproveRecord 复核通过，按用户提交的内容创建或修改模板
+func (h *ModerationHandler) ApproveRecord(c *gin.Context) {
+	h.review(c, h.moderationService.Approve)
+}
+
+// DismissRecord 驳回提交
+func (h *ModerationHandler) DismissRecord(c *gin.Context) {
+	h.review(c, h.moderationService.Dismiss)
+}
+
+// review 执行复核操作，返回更新后的记录
+func (h *ModerationHandler) review(c *gin.Context, action func(ctx context.Context, id int64) (*model.ModerationRecord, error)) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "记录ID无效",
+		})
+		return
+	}
+
+	record, err := action(c.Request.Context(), id)
+	if err != nil {
+		var variableErr *service.UndefinedVariableError
+		switch {
+		case errors.Is(err, service.ErrModerationRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "记录不存在",
+			})
+		case errors.Is(err, service.ErrModerationRecordReviewed):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "记录已复核",
+			})
+		case errors.Is(err, service.ErrModerationTemplateGone):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "模板已被删除，无法应用修改",
+			})
+		case errors.Is(err, repository.ErrTemplateLimitExceeded):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("该用户的模板数量已达上限（最多 %d 个）", MaxTemplatesPerUser),
+			})
+		case errors.As(err, &variableErr):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   variableErr.Error(),
+			})
+		default:
+			// 不暴露内部错误详情
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "复核操作失败，请稍后重试",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    record,
+	})
+}
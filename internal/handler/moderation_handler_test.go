@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/moderation"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupModerationTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.ModerationRecord{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	records := repository.NewModerationRecordRepository(db)
+	templateService := service.NewUserTemplateService(repository.NewUserTemplateRepository(db)).
+		WithModeration(moderation.New(moderation.Options{BannedWords: []string{"傻瓜"}}), records)
+	templateHandler := NewTemplateHandler(templateService)
+	handler := NewModerationHandler(service.NewTemplateModerationService(records, templateService))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(123))
+		c.Next()
+	})
+
+	router.POST("/templates", templateHandler.CreateTemplate)
+	router.PUT("/templates/:id", templateHandler.UpdateTemplate)
+	router.GET("/admin/moderation", handler.GetRecords)
+	router.POST("/admin/moderation/:id/approve", handler.ApproveRecord)
+	router.POST("/admin/moderation/:id/dismiss", handler.DismissRecord)
+	return router, db
+}
+
+func doModerationRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTemplateHandler_Moderation(t *testing.T) {
+	router, db := setupModerationTestRouter(t)
+
+	// 创建时拒绝违规内容，不暴露命中的词语
+	w := doModerationRequest(router, http.MethodPost, "/templates", `{"template_content":"傻瓜 距离{exam}还有{time}"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "未通过审核") || strings.Contains(w.Body.String(), "傻瓜") {
+		t.Errorf("unexpected response: %s", w.Body.String())
+	}
+
+	// 通过时保存清理后的内容
+	w = doModerationRequest(router, http.MethodPost, "/templates", `{"template_name":"简\u200b洁","template_content":"距离{exam}\u202e还有{time}"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var template model.UserTemplate
+	if err := db.Where("user_id = ?", 123).First(&template).Error; err != nil {
+		t.Fatalf("template not created: %v", err)
+	}
+	if template.TemplateName != "简洁" || template.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("created template = %+v", template)
+	}
+
+	// 修改时同样审核，并记录修改的模板
+	w = doModerationRequest(router, http.MethodPut, "/templates/"+strconv.FormatInt(template.ID, 10), `{"template_content":"见 www.evil.com {exam}{time}"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var records []model.ModerationRecord
+	db.Order("id").Find(&records)
+	if len(records) != 2 || records[0].Action != model.ModerationActionCreate || records[1].TemplateID != template.ID {
+		t.Errorf("moderation records = %+v", records)
+	}
+}
+
+func TestModerationHandler_Review(t *testing.T) {
+	router, db := setupModerationTestRouter(t)
+
+	doModerationRequest(router, http.MethodPost, "/templates", `{"template_content":"傻瓜 距离{exam}还有{time}"}`)
+	doModerationRequest(router, http.MethodPost, "/templates", `{"template_content":"傻瓜 {exam}{time}"}`)
+
+	w := doModerationRequest(router, http.MethodGet, "/admin/moderation", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data service.ModerationPage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Data.Total != 2 || len(resp.Data.Items) != 2 || !strings.Contains(resp.Data.Items[0].Violations, "傻瓜") {
+		t.Fatalf("GetRecords() = %+v", resp.Data)
+	}
+
+	approveID := strconv.FormatInt(resp.Data.Items[0].ID, 10)
+	dismissID := strconv.FormatInt(resp.Data.Items[1].ID, 10)
+
+	if w := doModerationRequest(router, http.MethodPost, "/admin/moderation/"+approveID+"/approve", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var template model.UserTemplate
+	if err := db.Where("user_id = ?", 123).First(&template).Error; err != nil || template.TemplateContent != "傻瓜 {exam}{time}" {
+		t.Errorf("template after approve = %+v, %v", template, err)
+	}
+
+	if w := doModerationRequest(router, http.MethodPost, "/admin/moderation/"+dismissID+"/dismiss", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := doModerationRequest(router, http.MethodPost, "/admin/moderation/"+dismissID+"/approve", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for reviewed record, got %d", w.Code)
+	}
+
+	w = doModerationRequest(router, http.MethodGet, "/admin/moderation?status=dismissed", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Data.Total != 1 {
+		t.Errorf("GetRecords() dismissed = %s", w.Body.String())
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/admin/moderation?status=unknown", http.StatusBadRequest},
+		{http.MethodGet, "/admin/moderation?page=0", http.StatusBadRequest},
+		{http.MethodPost, "/admin/moderation/abc/approve", http.StatusBadRequest},
+		{http.MethodPost, "/admin/moderation/999/dismiss", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if w := doModerationRequest(router, tt.method, tt.path, ""); w.Code != tt.want {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+// OfficialTemplateHandler 官方模板与推送对话模板选择处理器（管理 API）
+type OfficialTemplateHandler struct {
+	officialService *service.OfficialTemplateService
+	sendChatService *service.SendChatService
+}
+
+// NewOfficialTemplateHandler 创建官方模板处理器
+func NewOfficialTemplateHandler(officialService *service.OfficialTemplateService, sendChatService *service.SendChatService) *OfficialTemplateHandler {
+	return &OfficialTemplateHandler{
+		officialService: officialService,
+		sendChatService: sendChatService,
+	}
+}
+
+// OfficialTemplateRequest 创建或修改官方模板请求
+type OfficialTemplateRequest struct {
+	TemplateName    string     `json:"template_name"`
+	TemplateContent string     `json:"template_content" binding:"required"`
+	SortOrder       int        `json:"sort_order"`
+	Active          bool       `json:"active"`
+	StartAt         *time.Time `json:"start_at"`
+	EndAt           *time.Time `json:"end_at"`
+	DaysBeforeExam  int        `json:"days_before_exam"`
+}
+
+// SetChatTemplateRequest 设置推送对话模板请求，template_id 为 "0" 时恢复默认模板
+type SetChatTemplateRequest struct {
+	TemplateID int64 `json:"template_id,string"`
+}
+
+// SetChatRotationRequest 设置推送对话模板池请求，strategy 为空时停止轮换
+// admin_user_id 为对话管理员，模板池只能包含官方模板和管理员的个人模板
+type SetChatRotationRequest struct {
+	AdminUserID int64                     `json:"admin_user_id,string"`
+	Strategy    string                    `json:"strategy"`
+	Templates   []service.ChatTemplateRef `json:"templates"`
+}
+
+// GetTemplates 获取全部官方模板（包含未生效的）
+func (h *OfficialTemplateHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.officialService.List(c.Request.Context())
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取官方模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    templates,
+	})
+}
+
+// CreateTemplate 创建官方模板
+func (h *OfficialTemplateHandler) CreateTemplate(c *gin.Context) {
+	template, ok := bindOfficialTemplate(c)
+	if !ok {
+		return
+	}
+
+	if err := h.officialService.Create(c.Request.Context(), template); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "创建官方模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// UpdateTemplate 修改官方模板
+func (h *OfficialTemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, ok := parseOfficialTemplateID(c)
+	if !ok {
+		return
+	}
+
+	template, ok := bindOfficialTemplate(c)
+	if !ok {
+		return
+	}
+	template.ID = id
+
+	if err := h.officialService.Update(c.Request.Context(), template); err != nil {
+		if errors.Is(err, service.ErrOfficialTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "官方模板不存在",
+			})
+			return
+		}
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "修改官方模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// DeleteTemplate 删除官方模板，选用该模板的推送对话回退到默认模板
+func (h *OfficialTemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, ok := parseOfficialTemplateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.officialService.Delete(c.Request.Context(), id); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "删除官方模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// GetChats 获取全部推送对话及其选用的官方模板
+func (h *OfficialTemplateHandler) GetChats(c *gin.Context) {
+	chats, err := h.sendChatService.GetAll(c.Request.Context())
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取推送对话失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    chats,
+	})
+}
+
+// SetChatTemplate 设置推送对话选用的官方模板
+func (h *OfficialTemplateHandler) SetChatTemplate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "对话ID无效",
+		})
+		return
+	}
+
+	var req SetChatTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if req.TemplateID != 0 {
+		template, err := h.officialService.GetByID(ctx, req.TemplateID)
+		if err != nil {
+			// 不暴露内部错误详情
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "获取官方模板失败，请稍后重试",
+			})
+			return
+		}
+		if template == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "官方模板不存在",
+			})
+			return
+		}
+	}
+
+	if err := h.sendChatService.SetTemplate(ctx, id, req.TemplateID); err != nil {
+		if errors.Is(err, service.ErrSendChatNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "推送对话不存在",
+			})
+			return
+		}
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "设置推送模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// GetChatRotation 获取推送对话的模板池和轮换设置
+func (h *OfficialTemplateHandler) GetChatRotation(c *gin.Context) {
+	id, ok := parseSendChatID(c)
+	if !ok {
+		return
+	}
+
+	rotation, err := h.sendChatService.GetRotation(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrSendChatNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "推送对话不存在",
+			})
+			return
+		}
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取模板池失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rotation,
+	})
+}
+
+// SetChatRotation 替换推送对话的模板池并设置轮换策略
+func (h *OfficialTemplateHandler) SetChatRotation(c *gin.Context) {
+	id, ok := parseSendChatID(c)
+	if !ok {
+		return
+	}
+
+	var req SetChatRotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	rotation, err := h.sendChatService.SetRotation(c.Request.Context(), id, req.AdminUserID, strings.TrimSpace(req.Strategy), req.Templates)
+	if err != nil {
+		var rotationErr *service.ChatRotationError
+		if errors.As(err, &rotationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   rotationErr.Message,
+			})
+			return
+		}
+		if errors.Is(err, service.ErrSendChatNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "推送对话不存在",
+			})
+			return
+		}
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "设置模板池失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rotation,
+	})
+}
+
+// bindOfficialTemplate 解析并校验请求，失败时写入响应并返回 false
+func bindOfficialTemplate(c *gin.Context) (*model.OfficialTemplate, bool) {
+	var req OfficialTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return nil, false
+	}
+
+	template := &model.OfficialTemplate{
+		TemplateName:    strings.TrimSpace(req.TemplateName),
+		TemplateContent: req.TemplateContent,
+		SortOrder:       req.SortOrder,
+		Active:          req.Active,
+		StartAt:         req.StartAt,
+		EndAt:           req.EndAt,
+		DaysBeforeExam:  req.DaysBeforeExam,
+	}
+	if err := service.ValidateOfficialTemplate(template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return nil, false
+	}
+	return template, true
+}
+
+// parseOfficialTemplateID 解析路径中的模板 ID，失败时写入响应并返回 false
+func parseOfficialTemplateID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "模板ID无效",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// parseSendChatID 解析路径中的推送对话 ID，失败时写入响应并返回 false
+func parseSendChatID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "对话ID无效",
+		})
+		return 0, false
+	}
+	return id, true
+}
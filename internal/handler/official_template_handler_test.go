@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupOfficialTemplateTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.OfficialTemplate{}, &model.SendChat{}, &model.SendChatTemplate{}, &model.UserTemplate{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	officialService := service.NewOfficialTemplateService(repository.NewOfficialTemplateRepository(db))
+	sendChatService := service.NewSendChatService(repository.NewSendChatRepository(db)).WithTemplatePool(
+		officialService,
+		service.NewUserTemplateService(repository.NewUserTemplateRepository(db)),
+	)
+	handler := NewOfficialTemplateHandler(officialService, sendChatService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/official-templates", handler.GetTemplates)
+	router.POST("/admin/official-templates", handler.CreateTemplate)
+	router.PUT("/admin/official-templates/:id", handler.UpdateTemplate)
+	router.DELETE("/admin/official-templates/:id", handler.DeleteTemplate)
+	router.GET("/admin/chats", handler.GetChats)
+	router.PUT("/admin/chats/:id/template", handler.SetChatTemplate)
+	router.GET("/admin/chats/:id/rotation", handler.GetChatRotation)
+	router.PUT("/admin/chats/:id/rotation", handler.SetChatRotation)
+	return router, db
+}
+
+func doOfficialTemplateRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOfficialTemplateHandler_CRUD(t *testing.T) {
+	router, db := setupOfficialTemplateTestRouter(t)
+
+	w := doOfficialTemplateRequest(router, http.MethodPost, "/admin/official-templates",
+		`{"template_name":" 百日冲刺 ","template_content":"{exam}百日冲刺，还有{time}","sort_order":1,"active":true,"days_before_exam":100}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data model.OfficialTemplate `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if created.Data.ID == 0 || created.Data.TemplateName != "百日冲刺" || !created.Data.Active || created.Data.DaysBeforeExam != 100 {
+		t.Errorf("created template = %+v", created.Data)
+	}
+	path := "/admin/official-templates/" + strconv.FormatInt(created.Data.ID, 10)
+
+	// 校验失败
+	for _, body := range []string{
+		`{"template_content":"缺少变量"}`,
+		`{"template_content":"{exam}{time}","start_at":"2026-03-01T00:00:00+08:00","end_at":"2026-02-01T00:00:00+08:00"}`,
+		`{"template_content":"{exam}{time}","days_before_exam":-1}`,
+	} {
+		if w := doOfficialTemplateRequest(router, http.MethodPut, path, body); w.Code != http.StatusBadRequest {
+			t.Errorf("PUT %s: expected status 400, got %d", body, w.Code)
+		}
+	}
+
+	w = doOfficialTemplateRequest(router, http.MethodPut, path, `{"template_name":"寒假","template_content":"{exam}：{time}","start_at":"2026-01-15T00:00:00+08:00"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var template model.OfficialTemplate
+	db.First(&template, created.Data.ID)
+	if template.TemplateName != "寒假" || template.Active || template.StartAt == nil || template.DaysBeforeExam != 0 {
+		t.Errorf("updated template = %+v", template)
+	}
+
+	if w := doOfficialTemplateRequest(router, http.MethodPut, "/admin/official-templates/999", `{"template_content":"{exam}{time}"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for missing template, got %d", w.Code)
+	}
+	if w := doOfficialTemplateRequest(router, http.MethodPut, "/admin/official-templates/abc", `{"template_content":"{exam}{time}"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid ID, got %d", w.Code)
+	}
+
+	w = doOfficialTemplateRequest(router, http.MethodGet, "/admin/official-templates", "")
+	var list struct {
+		Data []model.OfficialTemplate `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 1 {
+		t.Errorf("GET list = %s, %v", w.Body.String(), err)
+	}
+
+	if w := doOfficialTemplateRequest(router, http.MethodDelete, path, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for delete, got %d", w.Code)
+	}
+	var count int64
+	db.Model(&model.OfficialTemplate{}).Count(&count)
+	if count != 0 {
+		t.Errorf("templates after delete = %d, want 0", count)
+	}
+}
+
+func TestOfficialTemplateHandler_SetChatTemplate(t *testing.T) {
+	router, db := setupOfficialTemplateTestRouter(t)
+	db.Create(&model.SendChat{ID: 1, ChatID: "-100123"})
+	db.Create(&model.OfficialTemplate{ID: 1982374650123456794, TemplateContent: "{exam}{time}", Active: true})
+
+	w := doOfficialTemplateRequest(router, http.MethodPut, "/admin/chats/1/template", `{"template_id":"1982374650123456794"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var chat model.SendChat
+	db.First(&chat, 1)
+	if chat.TemplateID != 1982374650123456794 {
+		t.Errorf("chat template = %d", chat.TemplateID)
+	}
+
+	w = doOfficialTemplateRequest(router, http.MethodGet, "/admin/chats", "")
+	var list struct {
+		Data []model.SendChat `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 1 || list.Data[0].TemplateID != 1982374650123456794 {
+		t.Errorf("GET chats = %s, %v", w.Body.String(), err)
+	}
+
+	// 恢复默认模板
+	if w := doOfficialTemplateRequest(router, http.MethodPut, "/admin/chats/1/template", `{"template_id":"0"}`); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for reset, got %d", w.Code)
+	}
+	db.First(&chat, 1)
+	if chat.TemplateID != 0 {
+		t.Errorf("chat template after reset = %d", chat.TemplateID)
+	}
+
+	if w := doOfficialTemplateRequest(router, http.MethodPut, "/admin/chats/1/template", `{"template_id":"42"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for missing template, got %d", w.Code)
+	}
+	if w := doOfficialTemplateRequest(router, http.MethodPut, "/admin/chats/9/template", `{"template_id":"0"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for missing chat, got %d", w.Code)
+	}
+	if w := doOfficialTemplateRequest(router, http.MethodPut, "/admin/chats/x/template", `{"template_id":"0"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid chat ID, got %d", w.Code)
+	}
+}
+
+func TestOfficialTemplateHandler_ChatRotation(t *testing.T) {
+	router, db := setupOfficialTemplateTestRouter(t)
+	db.Create(&model.SendChat{ID: 1, ChatID: "-100123"})
+	db.Create(&model.OfficialTemplate{ID: 10, TemplateContent: "{exam}{time}", Active: true})
+	db.Create(&model.UserTemplate{ID: 20, UserID: 7, TemplateContent: "{exam}{time}"})
+
+	w := doOfficialTemplateRequest(router, http.MethodPut, "/admin/chats/1/rotation",
+		`{"admin_user_id":"7","strategy":"weekday","templates":[{"source":"official","template_id":"10"},{"source":"user","template_id":"20"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doOfficialTemplateRequest(router, http.MethodGet, "/admin/chats/1/rotation", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var got struct {
+		Data service.ChatRotation `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if got.Data.AdminUserID != 7 || got.Data.Strategy != model.RotationWeekday || len(got.Data.Templates) != 2 {
+		t.Errorf("rotation = %+v", got.Data)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"invalid strategy", "/admin/chats/1/rotation", `{"strategy":"daily","templates":[{"source":"official","template_id":"10"}]}`, http.StatusBadRequest},
+		{"other user's template", "/admin/chats/1/rotation", `{"admin_user_id":"8","strategy":"random","templates":[{"source":"user","template_id":"20"}]}`, http.StatusBadRequest},
+		{"invalid body", "/admin/chats/1/rotation", `{"templates":"x"}`, http.StatusBadRequest},
+		{"invalid id", "/admin/chats/abc/rotation", `{}`, http.StatusBadRequest},
+		{"missing chat", "/admin/chats/999/rotation", `{}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := doOfficialTemplateRequest(router, http.MethodPut, tt.path, tt.body); w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if w := doOfficialTemplateRequest(router, http.MethodGet, "/admin/chats/999/rotation", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET missing chat: expected status 404, got %d", w.Code)
+	}
+}
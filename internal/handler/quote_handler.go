@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+// MaxQuoteImportSize 语录导入文件的最大大小
+const MaxQuoteImportSize = 256 << 10 // 256KB
+
+// QuoteHandler 励志语录处理器（管理 API）
+type QuoteHandler struct {
+	quoteService *service.QuoteService
+}
+
+// NewQuoteHandler 创建励志语录处理器
+func NewQuoteHandler(quoteService *service.QuoteService) *QuoteHandler {
+	return &QuoteHandler{quoteService: quoteService}
+}
+
+// QuoteRequest 创建或修改语录请求，tags 为逗号分隔的标签
+type QuoteRequest struct {
+	Content string `json:"content" binding:"required"`
+	Source  string `json:"source"`
+	Tags    string `json:"tags"`
+}
+
+// GetQuotes 获取语录，支持 tag 参数按标签过滤
+func (h *QuoteHandler) GetQuotes(c *gin.Context) {
+	quotes, err := h.quoteService.List(c.Request.Context(), strings.TrimSpace(c.Query("tag")))
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取语录失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    quotes,
+	})
+}
+
+// CreateQuote 创建语录
+func (h *QuoteHandler) CreateQuote(c *gin.Context) {
+	quote, ok := bindQuote(c)
+	if !ok {
+		return
+	}
+
+	if err := h.quoteService.Create(c.Request.Context(), quote); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "创建语录失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    quote,
+	})
+}
+
+// UpdateQuote 修改语录
+func (h *QuoteHandler) UpdateQuote(c *gin.Context) {
+	id, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	quote, ok := bindQuote(c)
+	if !ok {
+		return
+	}
+	quote.ID = id
+
+	if err := h.quoteService.Update(c.Request.Context(), quote); err != nil {
+		if errors.Is(err, service.ErrQuoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "语录不存在",
+			})
+			return
+		}
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "修改语录失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    quote,
+	})
+}
+
+// DeleteQuote 删除语录
+func (h *QuoteHandler) DeleteQuote(c *gin.Context) {
+	id, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.quoteService.Delete(c.Request.Context(), id); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "删除语录失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// ImportQuotes 从文本文件导入语录，请求体为纯文本，每行一条「内容|出处|标签1,标签2」
+// 任一行无效时不导入并返回逐行错误；与已有语录内容相同的行会被跳过
+func (h *QuoteHandler) ImportQuotes(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxQuoteImportSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("读取文件失败（最大 %dKB）", MaxQuoteImportSize>>10),
+		})
+		return
+	}
+
+	result, err := h.quoteService.Import(c.Request.Context(), string(body))
+	if err != nil {
+		if errors.Is(err, service.ErrQuoteImportInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "部分语录未通过校验，未导入任何语录",
+				"data":    result,
+			})
+			return
+		}
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "导入语录失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// bindQuote 解析并校验请求，失败时写入响应并返回 false
+func bindQuote(c *gin.Context) (*model.Quote, bool) {
+	var req QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return nil, false
+	}
+
+	quote := &model.Quote{
+		Content: strings.TrimSpace(req.Content),
+		Source:  strings.TrimSpace(req.Source),
+		Tags:    service.NormalizeQuoteTags(req.Tags),
+	}
+	if err := service.ValidateQuote(quote); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return nil, false
+	}
+	return quote, true
+}
+
+// parseQuoteID 解析路径中的语录 ID，失败时写入响应并返回 false
+func parseQuoteID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "语录ID无效",
+		})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupQuoteTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.Quote{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	handler := NewQuoteHandler(service.NewQuoteService(repository.NewQuoteRepository(db), 30))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/quotes", handler.GetQuotes)
+	router.POST("/admin/quotes", handler.CreateQuote)
+	router.POST("/admin/quotes/import", handler.ImportQuotes)
+	router.PUT("/admin/quotes/:id", handler.UpdateQuote)
+	router.DELETE("/admin/quotes/:id", handler.DeleteQuote)
+	return router, db
+}
+
+func doQuoteRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestQuoteHandler_CRUD(t *testing.T) {
+	router, db := setupQuoteTestRouter(t)
+
+	w := doQuoteRequest(router, http.MethodPost, "/admin/quotes", `{"content":" 不积跬步，无以至千里 ","source":"荀子","tags":"坚持， 积累,坚持"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data model.Quote `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if created.Data.ID == 0 || created.Data.Content != "不积跬步，无以至千里" || created.Data.Tags != "坚持,积累" {
+		t.Errorf("created quote = %+v", created.Data)
+	}
+	path := "/admin/quotes/" + strconv.FormatInt(created.Data.ID, 10)
+
+	// 校验失败
+	for _, body := range []string{
+		`{"content":"   "}`,
+		`{"content":"` + strings.Repeat("长", service.MaxQuoteContentLength+1) + `"}`,
+		`{"content":"天道酬勤","source":"` + strings.Repeat("长", service.MaxQuoteSourceLength+1) + `"}`,
+	} {
+		if w := doQuoteRequest(router, http.MethodPut, path, body); w.Code != http.StatusBadRequest {
+			t.Errorf("PUT %s: expected status 400, got %d", body, w.Code)
+		}
+	}
+
+	if w := doQuoteRequest(router, http.MethodPut, path, `{"content":"天道酬勤","tags":"勤奋"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var quote model.Quote
+	db.First(&quote, created.Data.ID)
+	if quote.Content != "天道酬勤" || quote.Source != "" || quote.Tags != "勤奋" {
+		t.Errorf("updated quote = %+v", quote)
+	}
+
+	if w := doQuoteRequest(router, http.MethodPut, "/admin/quotes/999", `{"content":"天道酬勤"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for missing quote, got %d", w.Code)
+	}
+	if w := doQuoteRequest(router, http.MethodDelete, "/admin/quotes/abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid ID, got %d", w.Code)
+	}
+
+	// 按标签过滤
+	var list struct {
+		Data []model.Quote `json:"data"`
+	}
+	w = doQuoteRequest(router, http.MethodGet, "/admin/quotes?tag=勤奋", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 1 {
+		t.Errorf("GET tag=勤奋 = %s, %v", w.Body.String(), err)
+	}
+	w = doQuoteRequest(router, http.MethodGet, "/admin/quotes?tag=坚持", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 0 {
+		t.Errorf("GET tag=坚持 = %s, %v", w.Body.String(), err)
+	}
+
+	if w := doQuoteRequest(router, http.MethodDelete, path, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for delete, got %d", w.Code)
+	}
+	var count int64
+	db.Model(&model.Quote{}).Count(&count)
+	if count != 0 {
+		t.Errorf("quotes after delete = %d, want 0", count)
+	}
+}
+
+func TestQuoteHandler_ImportQuotes(t *testing.T) {
+	router, db := setupQuoteTestRouter(t)
+	db.Create(&model.Quote{ID: 1, Content: "天道酬勤"})
+
+	// 任一行无效时不导入任何语录
+	w := doQuoteRequest(router, http.MethodPost, "/admin/quotes/import", "不积跬步，无以至千里|荀子\n"+strings.Repeat("长", service.MaxQuoteContentLength+1))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"line":2`) {
+		t.Errorf("Import invalid = %d %s", w.Code, w.Body.String())
+	}
+
+	body := "# 励志语录\n不积跬步，无以至千里|荀子|坚持\n\n天道酬勤\n宝剑锋从磨砺出，梅花香自苦寒来|《警世贤文》\n"
+	w = doQuoteRequest(router, http.MethodPost, "/admin/quotes/import", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result struct {
+		Data service.QuoteImportResult `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil || result.Data.Imported != 2 || result.Data.Skipped != 1 {
+		t.Errorf("Import result = %s, %v", w.Body.String(), err)
+	}
+
+	var count int64
+	db.Model(&model.Quote{}).Count(&count)
+	if count != 3 {
+		t.Errorf("quotes after import = %d, want 3", count)
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+const (
+	// MaxTargetsPerUser 每个用户最多可创建的自定义目标数量
+	MaxTargetsPerUser = 10
+
+	// MaxTargetNameLength 目标名称最大长度（字符数）
+	MaxTargetNameLength = 20
+)
+
+// TargetHandler 自定义目标处理器
+type TargetHandler struct {
+	targetService *service.UserTargetService
+}
+
+// NewTargetHandler 创建自定义目标处理器
+func NewTargetHandler(targetService *service.UserTargetService) *TargetHandler {
+	return &TargetHandler{
+		targetService: targetService,
+	}
+}
+
+// CreateTargetRequest 创建目标请求
+type CreateTargetRequest struct {
+	TargetName string    `json:"target_name" binding:"required"`
+	TargetDate time.Time `json:"target_date" binding:"required"`
+}
+
+// GetTargets 获取目标列表
+func (h *TargetHandler) GetTargets(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	targets, err := h.targetService.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取目标列表失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    targets,
+	})
+}
+
+// CreateTarget 创建目标
+func (h *TargetHandler) CreateTarget(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	var req CreateTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	req.TargetName = strings.TrimSpace(req.TargetName)
+	if err := validateTargetName(req.TargetName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if !req.TargetDate.After(util.NowBJT()) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "目标时间必须晚于当前时间",
+		})
+		return
+	}
+
+	id, err := util.GenerateID()
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "创建目标失败，请稍后重试",
+		})
+		return
+	}
+
+	target := &model.UserTarget{
+		ID:         id,
+		UserID:     userID,
+		TargetName: req.TargetName,
+		TargetDate: req.TargetDate.In(util.GetBJTLocation()),
+	}
+
+	// 使用原子操作创建目标，防止并发超过限制
+	if err := h.targetService.CreateWithLimit(c.Request.Context(), target, MaxTargetsPerUser); err != nil {
+		if errors.Is(err, repository.ErrTargetLimitExceeded) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("目标数量已达上限（最多 %d 个）", MaxTargetsPerUser),
+			})
+			return
+		}
+		// 其他错误不暴露内部详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "创建目标失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    target,
+	})
+}
+
+// DeleteTarget 删除目标
+func (h *TargetHandler) DeleteTarget(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "目标ID无效",
+		})
+		return
+	}
+
+	// 检查目标是否存在且属于当前用户
+	target, err := h.targetService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取目标失败，请稍后重试",
+		})
+		return
+	}
+
+	if target == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "目标不存在",
+		})
+		return
+	}
+
+	if target.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "无权限访问此目标",
+		})
+		return
+	}
+
+	if err := h.targetService.Delete(c.Request.Context(), id); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "删除目标失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// validateTargetName 验证目标名称
+func validateTargetName(name string) error {
+	if name == "" {
+		return fmt.Errorf("目标名称不能为空")
+	}
+
+	charCount := utf8.RuneCountInString(name)
+	if charCount > MaxTargetNameLength {
+		return fmt.Errorf("目标名称不能超过 %d 字符（当前 %d 字符）", MaxTargetNameLength, charCount)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTargetTestRouter(t *testing.T, userID int64) (*gin.Engine, *gorm.DB) {
+	_ = util.InitSnowflake(0, 1)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.UserTarget{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	handler := NewTargetHandler(service.NewUserTargetService(repository.NewUserTargetRepository(db)))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.GET("/targets", handler.GetTargets)
+	router.POST("/targets", handler.CreateTarget)
+	router.DELETE("/targets/:id", handler.DeleteTarget)
+
+	return router, db
+}
+
+func postTarget(router *gin.Engine, name string, date time.Time) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]interface{}{
+		"target_name": name,
+		"target_date": date,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/targets", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateTarget(t *testing.T) {
+	router, db := setupTargetTestRouter(t, 123)
+
+	w := postTarget(router, "一模", time.Now().AddDate(0, 1, 0))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var count int64
+	db.Model(&model.UserTarget{}).Where("user_id = ?", 123).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 target, got %d", count)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/targets", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "一模") {
+		t.Errorf("GetTargets() status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateTarget_Invalid(t *testing.T) {
+	router, _ := setupTargetTestRouter(t, 123)
+
+	tests := []struct {
+		name       string
+		targetName string
+		date       time.Time
+	}{
+		{"empty name", "  ", time.Now().AddDate(0, 1, 0)},
+		{"name too long", strings.Repeat("长", MaxTargetNameLength+1), time.Now().AddDate(0, 1, 0)},
+		{"past date", "一模", time.Now().AddDate(0, -1, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postTarget(router, tt.targetName, tt.date)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateTarget_LimitExceeded(t *testing.T) {
+	router, db := setupTargetTestRouter(t, 123)
+
+	for i := 0; i < MaxTargetsPerUser; i++ {
+		db.Create(&model.UserTarget{ID: int64(i + 1), UserID: 123, TargetName: "目标", TargetDate: time.Now().AddDate(0, 1, 0)})
+	}
+
+	w := postTarget(router, "一模", time.Now().AddDate(0, 1, 0))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDeleteTarget(t *testing.T) {
+	router, db := setupTargetTestRouter(t, 123)
+
+	db.Create(&model.UserTarget{ID: 1, UserID: 123, TargetName: "一模", TargetDate: time.Now().AddDate(0, 1, 0)})
+	db.Create(&model.UserTarget{ID: 2, UserID: 456, TargetName: "二模", TargetDate: time.Now().AddDate(0, 1, 0)})
+
+	tests := []struct {
+		name string
+		id   string
+		want int
+	}{
+		{"invalid id", "abc", http.StatusBadRequest},
+		{"not found", "999", http.StatusNotFound},
+		{"other user", "2", http.StatusForbidden},
+		{"own target", "1", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/targets/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("Status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
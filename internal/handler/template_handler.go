@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
-	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/herbertgao/gaokao_bot/internal/model"
@@ -17,19 +15,16 @@ import (
 
 const (
 	// MaxTemplatesPerUser 每个用户最多可创建的模板数量
-	// 限制为 10 个以防止滥用和控制数据库存储
-	MaxTemplatesPerUser = 10
+	MaxTemplatesPerUser = service.MaxTemplatesPerUser
 
 	// MaxTemplateContentLength 模板内容最大长度（字符数）
-	// 限制为 140 字符以确保：
-	// 1. 适合 Telegram 消息显示
-	// 2. 防止过长文本影响用户体验
-	// 3. 控制数据库字段大小
-	MaxTemplateContentLength = 140
+	MaxTemplateContentLength = service.MaxTemplateContentLength
 
 	// MaxTemplateNameLength 模板名称最大长度（字符数）
-	// 限制为 20 字符以保持名称简洁易读
-	MaxTemplateNameLength = 20
+	MaxTemplateNameLength = service.MaxTemplateNameLength
+
+	// MaxTemplateImportSize 模板导入请求体最大字节数
+	MaxTemplateImportSize = 64 << 10 // 64KB
 )
 
 // TemplateHandler 模板处理器
@@ -56,11 +51,17 @@ type UpdateTemplateRequest struct {
 	TemplateContent string `json:"template_content" binding:"required"`
 }
 
-// GetTemplates 获取模板列表
+// ReorderTemplatesRequest 调整模板顺序请求，需包含用户的全部模板
+// 模板 ID 以字符串传递，避免前端数值精度丢失
+type ReorderTemplatesRequest struct {
+	TemplateIDs []string `json:"template_ids" binding:"required"`
+}
+
+// GetTemplates 获取模板列表（收藏的模板在前，其余按排序位置）
 func (h *TemplateHandler) GetTemplates(c *gin.Context) {
 	userID := c.GetInt64("user_id")
 
-	templates, err := h.templateService.GetByUserID(userID)
+	templates, err := h.templateService.GetByUserID(c.Request.Context(), userID)
 	if err != nil {
 		// 不暴露内部错误详情
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -109,6 +110,17 @@ func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
 		}
 	}
 
+	// 检查引用的自定义变量
+	if !h.checkTemplateVariables(c, userID, req.TemplateContent) {
+		return
+	}
+
+	// 内容审核
+	name, content, ok := h.moderateTemplateInput(c, userID, 0, req.TemplateName, req.TemplateContent)
+	if !ok {
+		return
+	}
+
 	// 生成 ID
 	id, err := util.GenerateID()
 	if err != nil {
@@ -123,12 +135,12 @@ func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
 	template := &model.UserTemplate{
 		ID:              id,
 		UserID:          userID,
-		TemplateName:    req.TemplateName,
-		TemplateContent: req.TemplateContent,
+		TemplateName:    name,
+		TemplateContent: content,
 	}
 
 	// 使用原子操作创建模板，防止并发超过限制（TOCTOU 防护）
-	if err := h.templateService.CreateWithLimit(template, MaxTemplatesPerUser); err != nil {
+	if err := h.templateService.CreateWithLimit(c.Request.Context(), template, MaxTemplatesPerUser); err != nil {
 		// 检查是否是超过限制错误
 		if errors.Is(err, repository.ErrTemplateLimitExceeded) {
 			c.JSON(http.StatusBadRequest, gin.H{
@@ -195,7 +207,7 @@ func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
 	}
 
 	// 检查模板是否存在且属于当前用户
-	existingTemplate, err := h.templateService.GetByID(id)
+	existingTemplate, err := h.templateService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		// 不暴露内部错误详情
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -221,11 +233,22 @@ func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
 		return
 	}
 
+	// 检查引用的自定义变量
+	if !h.checkTemplateVariables(c, userID, req.TemplateContent) {
+		return
+	}
+
+	// 内容审核
+	name, content, ok := h.moderateTemplateInput(c, userID, existingTemplate.ID, req.TemplateName, req.TemplateContent)
+	if !ok {
+		return
+	}
+
 	// 更新模板
-	existingTemplate.TemplateName = req.TemplateName
-	existingTemplate.TemplateContent = req.TemplateContent
+	existingTemplate.TemplateName = name
+	existingTemplate.TemplateContent = content
 
-	if err := h.templateService.Update(existingTemplate); err != nil {
+	if err := h.templateService.Update(c.Request.Context(), existingTemplate); err != nil {
 		// 不暴露内部错误详情
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -255,7 +278,7 @@ func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
 	}
 
 	// 检查模板是否存在且属于当前用户
-	template, err := h.templateService.GetByID(id)
+	template, err := h.templateService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		// 不暴露内部错误详情
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -281,7 +304,7 @@ func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
 		return
 	}
 
-	if err := h.templateService.Delete(id); err != nil {
+	if err := h.templateService.Delete(c.Request.Context(), id); err != nil {
 		// 不暴露内部错误详情
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -295,37 +318,364 @@ func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
 	})
 }
 
-// validateTemplateContent 验证模板内容
-func validateTemplateContent(content string) error {
-	if content == "" {
-		return fmt.Errorf("模板内容不能为空")
+// ReorderTemplates 按给定顺序调整当前用户的模板排序
+func (h *TemplateHandler) ReorderTemplates(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	var req ReorderTemplatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	ids := make([]int64, len(req.TemplateIDs))
+	for i, idStr := range req.TemplateIDs {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "模板ID无效",
+			})
+			return
+		}
+		ids[i] = id
+	}
+
+	if err := h.templateService.Reorder(c.Request.Context(), userID, ids); err != nil {
+		if errors.Is(err, service.ErrTemplateOrderMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "模板列表与当前模板不一致，请刷新后重试",
+			})
+			return
+		}
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "调整模板顺序失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// FavoriteTemplate 收藏模板
+func (h *TemplateHandler) FavoriteTemplate(c *gin.Context) {
+	h.setFavorite(c, true)
+}
+
+// UnfavoriteTemplate 取消收藏模板
+func (h *TemplateHandler) UnfavoriteTemplate(c *gin.Context) {
+	h.setFavorite(c, false)
+}
+
+// SetDefaultTemplate 将模板设为个人默认模板，替代全局默认模板用于 /d 和非命令消息回复
+func (h *TemplateHandler) SetDefaultTemplate(c *gin.Context) {
+	template, ok := h.getOwnedTemplate(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.SetUserDefault(c.Request.Context(), template.UserID, template.ID); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "设置默认模板失败，请稍后重试",
+		})
+		return
+	}
+
+	template.IsDefault = true
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// ClearDefaultTemplate 取消个人默认模板，恢复使用全局默认模板
+func (h *TemplateHandler) ClearDefaultTemplate(c *gin.Context) {
+	if err := h.templateService.SetUserDefault(c.Request.Context(), c.GetInt64("user_id"), 0); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "取消默认模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// GetTemplateRevisions 获取模板的修订历史，最新的在前
+func (h *TemplateHandler) GetTemplateRevisions(c *gin.Context) {
+	template, ok := h.getOwnedTemplate(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.templateService.GetRevisions(c.Request.Context(), template.ID)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取修订历史失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    revisions,
+	})
+}
+
+// RestoreTemplateRevision 将模板恢复为指定的修订版本，恢复前的版本会记入修订历史
+func (h *TemplateHandler) RestoreTemplateRevision(c *gin.Context) {
+	template, ok := h.getOwnedTemplate(c)
+	if !ok {
+		return
+	}
+
+	revisionID, err := strconv.ParseInt(c.Param("revision_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "修订记录ID无效",
+		})
+		return
+	}
+
+	if err := h.templateService.RestoreRevision(c.Request.Context(), template, revisionID); err != nil {
+		if errors.Is(err, service.ErrRevisionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "修订记录不存在",
+			})
+			return
+		}
+		var moderationErr *service.ModerationError
+		if errors.As(err, &moderationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   moderationErr.Error(),
+			})
+			return
+		}
+		var variableErr *service.UndefinedVariableError
+		if errors.As(err, &variableErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   variableErr.Error(),
+			})
+			return
+		}
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "恢复模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// ExportTemplates 导出当前用户的全部模板，返回可导入到其他环境的 JSON 文件
+func (h *TemplateHandler) ExportTemplates(c *gin.Context) {
+	export, err := h.templateService.Export(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "导出模板失败，请稍后重试",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="templates.json"`)
+	c.JSON(http.StatusOK, export)
+}
+
+// ImportTemplates 导入 ExportTemplates 导出的模板文件
+// 逐条校验模板，任一模板无效时不导入并返回逐条错误；与已有模板相同的条目会被跳过
+func (h *TemplateHandler) ImportTemplates(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxTemplateImportSize)
+
+	var export service.TemplateExport
+	if err := c.ShouldBindJSON(&export); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	if err := export.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.templateService.Import(c.Request.Context(), c.GetInt64("user_id"), &export)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTemplateImportInvalid):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "部分模板未通过校验，未导入任何模板",
+				"data":    result,
+			})
+		case errors.Is(err, repository.ErrTemplateLimitExceeded):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("导入后模板数量将超过上限（最多 %d 个）", MaxTemplatesPerUser),
+			})
+		default:
+			// 不暴露内部错误详情
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "导入模板失败，请稍后重试",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// setFavorite 收藏或取消收藏模板，返回更新后的模板
+func (h *TemplateHandler) setFavorite(c *gin.Context, favorite bool) {
+	template, ok := h.getOwnedTemplate(c)
+	if !ok {
+		return
 	}
 
-	// 使用 utf8.RuneCountInString 正确计算字符数（而不是字节数）
-	// 对于中文字符，每个字符占 3 字节，使用 len() 会导致错误计数
-	charCount := utf8.RuneCountInString(content)
-	if charCount > MaxTemplateContentLength {
-		return fmt.Errorf("模板内容不能超过 %d 字符（当前 %d 字符）", MaxTemplateContentLength, charCount)
+	if err := h.templateService.SetFavorite(c.Request.Context(), template.ID, favorite); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "更新收藏状态失败，请稍后重试",
+		})
+		return
+	}
+
+	template.IsFavorite = favorite
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// getOwnedTemplate 获取路径中指定且属于当前用户的模板，失败时已写入响应
+func (h *TemplateHandler) getOwnedTemplate(c *gin.Context) (*model.UserTemplate, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "模板ID无效",
+		})
+		return nil, false
+	}
+
+	template, err := h.templateService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取模板失败，请稍后重试",
+		})
+		return nil, false
+	}
+
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "模板不存在",
+		})
+		return nil, false
+	}
+
+	if template.UserID != c.GetInt64("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "无权限访问此模板",
+		})
+		return nil, false
+	}
+
+	return template, true
+}
+
+// moderateTemplateInput 审核模板名称和内容，返回清理后的名称和内容，失败时已写入响应
+func (h *TemplateHandler) moderateTemplateInput(c *gin.Context, userID, templateID int64, name, content string) (string, string, bool) {
+	name, content, err := h.templateService.ModerateInput(c.Request.Context(), userID, templateID, name, content)
+	if err == nil {
+		return name, content, true
+	}
+
+	var moderationErr *service.ModerationError
+	if errors.As(err, &moderationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   moderationErr.Error(),
+		})
+		return "", "", false
 	}
 
-	// 必须包含 {exam} 和 {time}
-	if !strings.Contains(content, "{exam}") {
-		return fmt.Errorf("模板必须包含 {exam} 变量")
+	// 不暴露内部错误详情
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error":   "保存模板失败，请稍后重试",
+	})
+	return "", "", false
+}
+
+// checkTemplateVariables 检查模板引用的自定义变量均已定义，失败时已写入响应
+func (h *TemplateHandler) checkTemplateVariables(c *gin.Context, userID int64, content string) bool {
+	err := h.templateService.CheckVariables(c.Request.Context(), userID, content)
+	if err == nil {
+		return true
 	}
 
-	if !strings.Contains(content, "{time}") {
-		return fmt.Errorf("模板必须包含 {time} 变量")
+	var variableErr *service.UndefinedVariableError
+	if errors.As(err, &variableErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   variableErr.Error(),
+		})
+		return false
 	}
 
-	return nil
+	// 不暴露内部错误详情
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error":   "保存模板失败，请稍后重试",
+	})
+	return false
+}
+
+// validateTemplateContent 验证模板内容
+func validateTemplateContent(content string) error {
+	return service.ValidateTemplateContent(content)
 }
 
 // validateTemplateName 验证模板名称
 func validateTemplateName(name string) error {
-	// 使用 utf8.RuneCountInString 正确计算字符数（而不是字节数）
-	charCount := utf8.RuneCountInString(name)
-	if charCount > MaxTemplateNameLength {
-		return fmt.Errorf("模板标题不能超过 %d 字符（当前 %d 字符）", MaxTemplateNameLength, charCount)
-	}
-	return nil
+	return service.ValidateTemplateName(name)
 }
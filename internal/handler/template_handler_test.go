@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 
@@ -66,6 +67,15 @@ func setupTestRouter(handler *TemplateHandler, userID int64) *gin.Engine {
 	router.POST("/templates", handler.CreateTemplate)
 	router.PUT("/templates/:id", handler.UpdateTemplate)
 	router.DELETE("/templates/:id", handler.DeleteTemplate)
+	router.PUT("/templates/order", handler.ReorderTemplates)
+	router.POST("/templates/:id/favorite", handler.FavoriteTemplate)
+	router.DELETE("/templates/:id/favorite", handler.UnfavoriteTemplate)
+	router.PUT("/templates/:id/default", handler.SetDefaultTemplate)
+	router.DELETE("/templates/default", handler.ClearDefaultTemplate)
+	router.GET("/templates/export", handler.ExportTemplates)
+	router.POST("/templates/import", handler.ImportTemplates)
+	router.GET("/templates/:id/revisions", handler.GetTemplateRevisions)
+	router.POST("/templates/:id/revisions/:revision_id/restore", handler.RestoreTemplateRevision)
 
 	return router
 }
@@ -504,3 +514,234 @@ func TestValidateTemplateName(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateOrderFavoriteDefault(t *testing.T) {
+	handler, db := setupTestHandler(t)
+	userID := int64(123)
+	for i := int64(1); i <= 3; i++ {
+		db.Create(&model.UserTemplate{ID: i, UserID: userID, TemplateContent: "距离{exam}还有{time}"})
+	}
+	db.Create(&model.UserTemplate{ID: 9, UserID: 456, TemplateContent: "距离{exam}还有{time}"})
+
+	router := setupTestRouter(handler, userID)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	listIDs := func() []int64 {
+		var response struct {
+			Data []model.UserTemplate `json:"data"`
+		}
+		_ = json.Unmarshal(do(http.MethodGet, "/templates", "").Body.Bytes(), &response)
+		ids := make([]int64, len(response.Data))
+		for i, template := range response.Data {
+			ids[i] = template.ID
+		}
+		return ids
+	}
+
+	if w := do(http.MethodPut, "/templates/order", `{"template_ids":["3","1","2"]}`); w.Code != http.StatusOK {
+		t.Fatalf("reorder status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if got := fmt.Sprint(listIDs()); got != "[3 1 2]" {
+		t.Errorf("order after reorder = %s, want [3 1 2]", got)
+	}
+
+	// 收藏的模板排在最前
+	if w := do(http.MethodPost, "/templates/2/favorite", ""); w.Code != http.StatusOK {
+		t.Fatalf("favorite status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if got := fmt.Sprint(listIDs()); got != "[2 3 1]" {
+		t.Errorf("order after favorite = %s, want [2 3 1]", got)
+	}
+	if w := do(http.MethodDelete, "/templates/2/favorite", ""); w.Code != http.StatusOK {
+		t.Errorf("unfavorite status = %d", w.Code)
+	}
+
+	if w := do(http.MethodPut, "/templates/1/default", ""); w.Code != http.StatusOK {
+		t.Fatalf("set default status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var template model.UserTemplate
+	db.First(&template, 1)
+	if !template.IsDefault {
+		t.Error("template 1 should be the personal default")
+	}
+	if w := do(http.MethodDelete, "/templates/default", ""); w.Code != http.StatusOK {
+		t.Errorf("clear default status = %d", w.Code)
+	}
+	db.First(&template, 1)
+	if template.IsDefault {
+		t.Error("personal default should be cleared")
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"reorder missing template", http.MethodPut, "/templates/order", `{"template_ids":["1","2"]}`, http.StatusBadRequest},
+		{"reorder foreign template", http.MethodPut, "/templates/order", `{"template_ids":["1","2","9"]}`, http.StatusBadRequest},
+		{"reorder invalid id", http.MethodPut, "/templates/order", `{"template_ids":["abc"]}`, http.StatusBadRequest},
+		{"reorder invalid body", http.MethodPut, "/templates/order", `{}`, http.StatusBadRequest},
+		{"favorite invalid id", http.MethodPost, "/templates/abc/favorite", "", http.StatusBadRequest},
+		{"favorite not found", http.MethodPost, "/templates/999/favorite", "", http.StatusNotFound},
+		{"favorite forbidden", http.MethodPost, "/templates/9/favorite", "", http.StatusForbidden},
+		{"default not found", http.MethodPut, "/templates/999/default", "", http.StatusNotFound},
+		{"default forbidden", http.MethodPut, "/templates/9/default", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.method, tt.path, tt.body); w.Code != tt.want {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTemplateRevisions(t *testing.T) {
+	_ = util.InitSnowflake(0, 1)
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.TemplateRevision{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	templateService := service.NewUserTemplateService(repository.NewUserTemplateRepository(db)).
+		WithRevisions(repository.NewTemplateRevisionRepository(db))
+	router := setupTestRouter(NewTemplateHandler(templateService), 123)
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateName: "原始", TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: 456, TemplateContent: "距离{exam}还有{time}"})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodPut, "/templates/1", `{"template_name":"修改","template_content":"还有{time}就到{exam}了"}`); w.Code != http.StatusOK {
+		t.Fatalf("update status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data []model.TemplateRevision `json:"data"`
+	}
+	w := do(http.MethodGet, "/templates/1/revisions", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].TemplateName != "原始" || response.Data[0].TemplateContent != "距离{exam}还有{time}" {
+		t.Fatalf("revisions = %s", w.Body.String())
+	}
+
+	restorePath := fmt.Sprintf("/templates/1/revisions/%d/restore", response.Data[0].ID)
+	if w := do(http.MethodPost, restorePath, ""); w.Code != http.StatusOK {
+		t.Fatalf("restore status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var template model.UserTemplate
+	db.First(&template, 1)
+	if template.TemplateName != "原始" || template.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("template after restore = %+v", template)
+	}
+
+	// 恢复前的版本同样记入历史
+	_ = json.Unmarshal(do(http.MethodGet, "/templates/1/revisions", "").Body.Bytes(), &response)
+	if len(response.Data) != 2 || response.Data[0].TemplateContent != "还有{time}就到{exam}了" {
+		t.Errorf("revisions after restore = %+v", response.Data)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"revisions not found", http.MethodGet, "/templates/999/revisions", http.StatusNotFound},
+		{"revisions forbidden", http.MethodGet, "/templates/2/revisions", http.StatusForbidden},
+		{"restore invalid revision", http.MethodPost, "/templates/1/revisions/abc/restore", http.StatusBadRequest},
+		{"restore missing revision", http.MethodPost, "/templates/1/revisions/999/restore", http.StatusNotFound},
+		{"restore forbidden", http.MethodPost, "/templates/2/revisions/1/restore", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.method, tt.path, ""); w.Code != tt.want {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTemplateExportImport(t *testing.T) {
+	handler, db := setupTestHandler(t)
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateName: "简洁", TemplateContent: "距离{exam}还有{time}"})
+
+	req, _ := http.NewRequest(http.MethodGet, "/templates/export", nil)
+	w := httptest.NewRecorder()
+	setupTestRouter(handler, 123).ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Disposition") == "" {
+		t.Fatalf("export status = %d, headers = %v", w.Code, w.Header())
+	}
+	exported := w.Body.String()
+
+	importAs := func(userID int64, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/templates/import", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupTestRouter(handler, userID).ServeHTTP(w, req)
+		return w
+	}
+
+	w = importAs(456, exported)
+	if w.Code != http.StatusOK {
+		t.Fatalf("import status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var imported model.UserTemplate
+	if err := db.Where("user_id = ?", 456).First(&imported).Error; err != nil || imported.TemplateName != "简洁" || imported.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("imported template = %+v, %v", imported, err)
+	}
+
+	// 逐条返回校验错误
+	w = importAs(789, `{"version":1,"templates":[{"content":"距离{exam}还有{time}"},{"content":"缺少变量"}]}`)
+	var response struct {
+		Data service.TemplateImportResult `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || len(response.Data.Errors) != 1 || response.Data.Errors[0].Index != 1 {
+		t.Errorf("invalid import = %d %s", w.Code, w.Body.String())
+	}
+
+	entries := make([]string, MaxTemplatesPerUser+1)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(`{"name":"%d","content":"{exam}{time}"}`, i)
+	}
+	overLimit := `{"version":1,"templates":[` + strings.Join(entries, ",") + `]}`
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid json", `{`},
+		{"unsupported version", `{"version":2,"templates":[]}`},
+		{"too large", `{"version":1,"templates":[{"content":"` + strings.Repeat("a", MaxTemplateImportSize) + `"}]}`},
+		{"over limit", overLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := importAs(789, tt.body); w.Code != http.StatusBadRequest {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+		})
+	}
+	var count int64
+	db.Model(&model.UserTemplate{}).Where("user_id = ?", 789).Count(&count)
+	if count != 0 {
+		t.Errorf("templates after rejected imports = %d, want 0", count)
+	}
+}
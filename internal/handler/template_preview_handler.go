@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// TemplatePreviewHandler 模板预览处理器
+type TemplatePreviewHandler struct {
+	previewService *service.TemplatePreviewService
+}
+
+// NewTemplatePreviewHandler 创建模板预览处理器
+func NewTemplatePreviewHandler(previewService *service.TemplatePreviewService) *TemplatePreviewHandler {
+	return &TemplatePreviewHandler{
+		previewService: previewService,
+	}
+}
+
+// PreviewTemplateRequest 模板预览请求
+type PreviewTemplateRequest struct {
+	TemplateName    string `json:"template_name"`
+	TemplateContent string `json:"template_content"`
+	// ExamYear 预览使用的考试年份，为空时使用下一场高考
+	ExamYear int `json:"exam_year"`
+}
+
+// PreviewTemplate 预览模板
+// 模板无效时仍返回 200 和渲染结果，由 data.valid 与 data.errors 说明原因
+func (h *TemplatePreviewHandler) PreviewTemplate(c *gin.Context) {
+	var req PreviewTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	if req.ExamYear != 0 && (req.ExamYear < constant.MinExamYear || req.ExamYear > constant.MaxExamYear) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "年份无效",
+		})
+		return
+	}
+
+	preview, err := h.previewService.PreviewForUser(c.Request.Context(), c.GetInt64("user_id"), req.TemplateName, req.TemplateContent, req.ExamYear, util.NowBJT())
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "预览模板失败，请稍后重试",
+		})
+		return
+	}
+
+	if preview == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("未找到 %d 年的考试", req.ExamYear),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preview,
+	})
+}
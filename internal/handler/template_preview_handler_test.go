@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTemplatePreviewTestRouter(t *testing.T) *gin.Engine {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ExamDate{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	handler := NewTemplatePreviewHandler(service.NewTemplatePreviewService(
+		service.NewExamDateService(repository.NewExamDateRepository(db)),
+	))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/templates/preview", handler.PreviewTemplate)
+	return router
+}
+
+func postPreview(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/templates/preview", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPreviewTemplate(t *testing.T) {
+	router := setupTemplatePreviewTestRouter(t)
+
+	w := postPreview(router, `{"template_content":"距离{exam}还有{time}"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var response struct {
+		Success bool                    `json:"success"`
+		Data    service.TemplatePreview `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !response.Success || !response.Data.Valid {
+		t.Errorf("expected valid preview, got %s", w.Body.String())
+	}
+	// 没有考试数据时使用示例考试
+	if !response.Data.Exam.Sample || response.Data.States.InProgress == "" || response.Data.States.Ended == "" {
+		t.Errorf("unexpected preview: %s", w.Body.String())
+	}
+}
+
+func TestPreviewTemplate_Invalid(t *testing.T) {
+	router := setupTemplatePreviewTestRouter(t)
+
+	// 模板无效时仍返回渲染结果和全部错误
+	w := postPreview(router, `{"template_content":"还有{days}"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var response struct {
+		Data service.TemplatePreview `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Data.Valid || len(response.Data.Errors) != 2 || len(response.Data.Warnings) != 1 {
+		t.Errorf("unexpected validation result: %s", w.Body.String())
+	}
+}
+
+func TestPreviewTemplate_BadRequest(t *testing.T) {
+	router := setupTemplatePreviewTestRouter(t)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"invalid json", `{`, http.StatusBadRequest},
+		{"invalid year", `{"template_content":"{exam}{time}","exam_year":1900}`, http.StatusBadRequest},
+		{"year not found", `{"template_content":"{exam}{time}","exam_year":2030}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postPreview(router, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+// TemplateShareHandler 模板分享处理器
+type TemplateShareHandler struct {
+	templateService *service.UserTemplateService
+	shareService    *service.TemplateShareService
+}
+
+// NewTemplateShareHandler 创建模板分享处理器
+func NewTemplateShareHandler(templateService *service.UserTemplateService, shareService *service.TemplateShareService) *TemplateShareHandler {
+	return &TemplateShareHandler{
+		templateService: templateService,
+		shareService:    shareService,
+	}
+}
+
+// ShareTemplate 生成模板分享码，已分享时返回现有分享码
+func (h *TemplateShareHandler) ShareTemplate(c *gin.Context) {
+	template, ok := h.getOwnTemplate(c)
+	if !ok {
+		return
+	}
+
+	code, err := h.shareService.Share(c.Request.Context(), template)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "生成分享码失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"share_code": code,
+			"share_link": h.shareService.Link(code),
+		},
+	})
+}
+
+// RevokeShare 撤销模板分享码，已分享的链接随即失效
+func (h *TemplateShareHandler) RevokeShare(c *gin.Context) {
+	template, ok := h.getOwnTemplate(c)
+	if !ok {
+		return
+	}
+
+	if err := h.shareService.Revoke(c.Request.Context(), template); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "撤销分享失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// getOwnTemplate 获取路径参数中属于当前用户的模板，失败时已写入响应
+func (h *TemplateShareHandler) getOwnTemplate(c *gin.Context) (*model.UserTemplate, bool) {
+	userID := c.GetInt64("user_id")
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "模板ID无效",
+		})
+		return nil, false
+	}
+
+	template, err := h.templateService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取模板失败，请稍后重试",
+		})
+		return nil, false
+	}
+
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "模板不存在",
+		})
+		return nil, false
+	}
+
+	if template.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "无权限访问此模板",
+		})
+		return nil, false
+	}
+
+	return template, true
+}
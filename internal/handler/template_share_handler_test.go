@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"gorm.io/gorm"
+)
+
+func setupTemplateShareTestRouter(t *testing.T, userID int64) (*gin.Engine, *gorm.DB) {
+	db := setupTestDB(t)
+	templateService := service.NewUserTemplateService(repository.NewUserTemplateRepository(db))
+	handler := NewTemplateShareHandler(templateService, service.NewTemplateShareService(templateService, "gaokao_bot"))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	// 设置用户ID中间件
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+
+	router.POST("/templates/:id/share", handler.ShareTemplate)
+	router.DELETE("/templates/:id/share", handler.RevokeShare)
+	return router, db
+}
+
+func TestShareTemplate(t *testing.T) {
+	router, db := setupTemplateShareTestRouter(t, 123)
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateContent: "距离{exam}还有{time}"})
+
+	req := httptest.NewRequest(http.MethodPost, "/templates/1/share", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var response struct {
+		Success bool `json:"success"`
+		Data    struct {
+			ShareCode string `json:"share_code"`
+			ShareLink string `json:"share_link"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !response.Success || !service.IsValidShareCode(response.Data.ShareCode) {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+	if want := "https://t.me/gaokao_bot?start=tpl_" + response.Data.ShareCode; response.Data.ShareLink != want {
+		t.Errorf("share_link = %q, want %q", response.Data.ShareLink, want)
+	}
+
+	var template model.UserTemplate
+	db.First(&template, 1)
+	if template.ShareCode == nil || *template.ShareCode != response.Data.ShareCode {
+		t.Errorf("share code not saved: %+v", template.ShareCode)
+	}
+
+	// 撤销分享
+	req = httptest.NewRequest(http.MethodDelete, "/templates/1/share", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	db.First(&template, 1)
+	if template.ShareCode != nil {
+		t.Errorf("share code not revoked: %q", *template.ShareCode)
+	}
+}
+
+func TestShareTemplate_Errors(t *testing.T) {
+	router, db := setupTemplateShareTestRouter(t, 123)
+	db.Create(&model.UserTemplate{ID: 2, UserID: 456, TemplateContent: "距离{exam}还有{time}"})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"invalid id", http.MethodPost, "/templates/abc/share", http.StatusBadRequest},
+		{"not found", http.MethodPost, "/templates/999/share", http.StatusNotFound},
+		{"forbidden", http.MethodPost, "/templates/2/share", http.StatusForbidden},
+		{"revoke forbidden", http.MethodDelete, "/templates/2/share", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("Status = %d, want %d. Body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
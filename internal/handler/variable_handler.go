@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+)
+
+// VariableHandler 用户自定义模板变量处理器
+type VariableHandler struct {
+	variableService *service.UserVariableService
+}
+
+// NewVariableHandler 创建用户自定义模板变量处理器
+func NewVariableHandler(variableService *service.UserVariableService) *VariableHandler {
+	return &VariableHandler{
+		variableService: variableService,
+	}
+}
+
+// SetVariableRequest 新增或修改变量请求
+type SetVariableRequest struct {
+	VarValue string `json:"var_value" binding:"required"`
+}
+
+// GetVariables 获取当前用户的变量列表
+func (h *VariableHandler) GetVariables(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	variables, err := h.variableService.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "获取变量列表失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    variables,
+	})
+}
+
+// SetVariable 新增或修改变量，路径参数为变量名称
+func (h *VariableHandler) SetVariable(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	name := c.Param("name")
+	if err := service.ValidateVariableName(name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var req SetVariableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("请求参数无效: %v", err),
+		})
+		return
+	}
+
+	value := service.SanitizeVariableValue(req.VarValue)
+	if err := service.ValidateVariableValue(value); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	variable, err := h.variableService.Set(c.Request.Context(), userID, name, value)
+	if err != nil {
+		if errors.Is(err, repository.ErrVariableLimitExceeded) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("变量数量已达上限（最多 %d 个）", service.MaxVariablesPerUser),
+			})
+			return
+		}
+		var moderationErr *service.ModerationError
+		if errors.As(err, &moderationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   moderationErr.Error(),
+			})
+			return
+		}
+		// 其他错误不暴露内部详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "保存变量失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    variable,
+	})
+}
+
+// DeleteVariable 删除变量，引用该变量的模板渲染时原样显示
+func (h *VariableHandler) DeleteVariable(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	if err := h.variableService.Delete(c.Request.Context(), userID, c.Param("name")); err != nil {
+		// 不暴露内部错误详情
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "删除变量失败，请稍后重试",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/service"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupVariableTestRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.UserVariable{}, &model.ExamDate{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	variableService := service.NewUserVariableService(repository.NewUserVariableRepository(db))
+	templateHandler := NewTemplateHandler(service.NewUserTemplateService(repository.NewUserTemplateRepository(db)).
+		WithVariables(variableService))
+	previewHandler := NewTemplatePreviewHandler(service.NewTemplatePreviewService(service.NewExamDateService(repository.NewExamDateRepository(db))).
+		WithUserVariables(variableService))
+	handler := NewVariableHandler(variableService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(123))
+		c.Next()
+	})
+	router.POST("/templates", templateHandler.CreateTemplate)
+	router.PUT("/templates/:id", templateHandler.UpdateTemplate)
+	router.POST("/templates/preview", previewHandler.PreviewTemplate)
+	router.GET("/variables", handler.GetVariables)
+	router.PUT("/variables/:name", handler.SetVariable)
+	router.DELETE("/variables/:name", handler.DeleteVariable)
+	return router, db
+}
+
+func doVariableRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestVariableHandler_CRUD(t *testing.T) {
+	router, db := setupVariableTestRouter(t)
+
+	w := doVariableRequest(router, http.MethodPut, "/variables/name", `{"var_value":" 小明 "}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	// 修改已有变量
+	if w := doVariableRequest(router, http.MethodPut, "/variables/name", `{"var_value":"小刚"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for update, got %d: %s", w.Code, w.Body.String())
+	}
+	var variables []model.UserVariable
+	db.Find(&variables)
+	if len(variables) != 1 || variables[0].UserID != 123 || variables[0].VarValue != "小刚" {
+		t.Errorf("variables = %+v", variables)
+	}
+
+	// 校验失败
+	for path, body := range map[string]string{
+		"/variables/my%20name":                  `{"var_value":"值"}`,
+		"/variables/a:b":                        `{"var_value":"值"}`,
+		"/variables/" + strings.Repeat("长", 21): `{"var_value":"值"}`,
+		"/variables/goal":                       `{"var_value":"   "}`,
+		"/variables/school":                     fmt.Sprintf(`{"var_value":"%s"}`, strings.Repeat("长", service.MaxVariableValueLength+1)),
+	} {
+		if w := doVariableRequest(router, http.MethodPut, path, body); w.Code != http.StatusBadRequest {
+			t.Errorf("PUT %s: expected status 400, got %d", path, w.Code)
+		}
+	}
+
+	// 数量上限
+	for i := 1; i < service.MaxVariablesPerUser; i++ {
+		doVariableRequest(router, http.MethodPut, fmt.Sprintf("/variables/v%d", i), `{"var_value":"值"}`)
+	}
+	w = doVariableRequest(router, http.MethodPut, "/variables/extra", `{"var_value":"值"}`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "上限") {
+		t.Errorf("Expected limit error, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doVariableRequest(router, http.MethodGet, "/variables", "")
+	var list struct {
+		Data []model.UserVariable `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != service.MaxVariablesPerUser || list.Data[0].VarName != "name" {
+		t.Errorf("GET variables = %d items, %v", len(list.Data), err)
+	}
+
+	if w := doVariableRequest(router, http.MethodDelete, "/variables/name", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for delete, got %d", w.Code)
+	}
+	var count int64
+	db.Model(&model.UserVariable{}).Where("var_name = ?", "name").Count(&count)
+	if count != 0 {
+		t.Error("variable should be deleted")
+	}
+}
+
+func TestVariableHandler_TemplateReferences(t *testing.T) {
+	router, db := setupVariableTestRouter(t)
+	content := `{var:name}，距离{exam}还有{time}，目标{var:goal}`
+
+	// 引用未定义的变量时拒绝保存
+	w := doVariableRequest(router, http.MethodPost, "/templates", fmt.Sprintf(`{"template_content":%q}`, content))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "{var:name}") {
+		t.Fatalf("Expected undefined variable error, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doVariableRequest(router, http.MethodPost, "/templates/preview", fmt.Sprintf(`{"template_content":%q}`, content))
+	if !strings.Contains(w.Body.String(), `"valid":false`) || !strings.Contains(w.Body.String(), service.TemplateIssueUndefinedVariable) {
+		t.Errorf("preview with undefined variables = %s", w.Body.String())
+	}
+
+	doVariableRequest(router, http.MethodPut, "/variables/name", `{"var_value":"小明"}`)
+	doVariableRequest(router, http.MethodPut, "/variables/goal", `{"var_value":"清华"}`)
+
+	if w := doVariableRequest(router, http.MethodPost, "/templates", fmt.Sprintf(`{"template_content":%q}`, content)); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var template model.UserTemplate
+	if err := db.Where("user_id = ?", 123).First(&template).Error; err != nil || template.TemplateContent != content {
+		t.Fatalf("template = %+v, %v", template, err)
+	}
+
+	// 修改时同样检查
+	w = doVariableRequest(router, http.MethodPut, fmt.Sprintf("/templates/%d", template.ID), `{"template_content":"{var:school}{exam}{time}"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for update, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doVariableRequest(router, http.MethodPost, "/templates/preview", fmt.Sprintf(`{"template_content":%q}`, content))
+	var preview struct {
+		Data service.TemplatePreview `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !preview.Data.Valid || len(preview.Data.Warnings) != 0 || !strings.HasPrefix(preview.Data.States.Upcoming, "小明，距离") || !strings.HasSuffix(preview.Data.States.Upcoming, "目标清华") {
+		t.Errorf("preview = %+v", preview.Data)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuthMiddleware 管理员权限中间件
+// 必须放在 TelegramAuthMiddleware 之后，依赖上下文中的 user_id
+// 未配置任何管理员时拒绝所有请求
+func AdminAuthMiddleware(adminUserIDs []int64) gin.HandlerFunc {
+	admins := make(map[int64]struct{}, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		userID := c.GetInt64("user_id")
+		if _, ok := admins[userID]; !ok || userID == 0 {
+			c.JSON(403, gin.H{
+				"success": false,
+				"error":   "无管理员权限",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
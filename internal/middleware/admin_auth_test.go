@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupAdminTestRouter(userID int64, adminUserIDs []int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.Use(AdminAuthMiddleware(adminUserIDs))
+	router.GET("/admin", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+
+	return router
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		admins   []int64
+		wantCode int
+	}{
+		{"admin allowed", 100, []int64{100, 200}, http.StatusOK},
+		{"non-admin forbidden", 300, []int64{100, 200}, http.StatusForbidden},
+		{"no admins configured", 100, nil, http.StatusForbidden},
+		{"missing user id", 0, []int64{0}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupAdminTestRouter(tt.userID, tt.admins)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Status = %d, want %d", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{"timeout configured", time.Second, true},
+		{"timeout disabled", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+
+			router := gin.New()
+			router.Use(RequestTimeoutMiddleware(tt.timeout))
+			router.GET("/", func(c *gin.Context) {
+				deadline, hasDeadline = c.Request.Context().Deadline()
+				c.Status(http.StatusOK)
+			})
+
+			start := time.Now()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+			end := time.Now()
+
+			if hasDeadline != tt.wantDeadline {
+				t.Fatalf("Deadline set = %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+			if hasDeadline && (deadline.Before(start.Add(tt.timeout)) || deadline.After(end.Add(tt.timeout))) {
+				t.Errorf("Deadline = %v, want about %v after request", deadline, tt.timeout)
+			}
+		})
+	}
+}
+
+func TestRequestTimeoutMiddleware_Expired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var ctxErr error
+	router := gin.New()
+	router.Use(RequestTimeoutMiddleware(time.Millisecond))
+	router.GET("/", func(c *gin.Context) {
+		// 模拟慢查询：等待 context 到期
+		<-c.Request.Context().Done()
+		ctxErr = c.Request.Context().Err()
+		c.Status(http.StatusServiceUnavailable)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if ctxErr == nil {
+		t.Error("Expected request context to expire")
+	}
+}
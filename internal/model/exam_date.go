@@ -3,9 +3,12 @@ package model
 import "time"
 
 // ExamDate 考试日期实体
+// 同一年份、考试类型和地区的考试唯一（idx_exam_date_natural_key）
 type ExamDate struct {
 	ID                uint      `gorm:"primaryKey;autoIncrement"`
-	ExamYear          int       `gorm:"not null;index"`
+	ExamYear          int       `gorm:"not null;index;uniqueIndex:idx_exam_date_natural_key,priority:1"`
+	ExamKind          string    `gorm:"type:varchar(32);not null;default:gaokao;index;uniqueIndex:idx_exam_date_natural_key,priority:2"`
+	Region            string    `gorm:"type:varchar(32);not null;default:'';index;uniqueIndex:idx_exam_date_natural_key,priority:3"`
 	ExamDesc          string    `gorm:"type:varchar(255)"`
 	ShortDesc         string    `gorm:"type:varchar(32)"`
 	ExamBeginDate     time.Time `gorm:"not null"`
@@ -18,4 +21,4 @@ type ExamDate struct {
 // TableName 指定表名
 func (ExamDate) TableName() string {
 	return "exam_date"
-}
\ No newline at end of file
+}
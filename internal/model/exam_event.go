@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ExamEvent 考后时间线事件实体（成绩公布、志愿填报、录取查询等）
+// 通过年份 + 考试类型 + 地区关联到对应的考试
+type ExamEvent struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ExamYear  int       `gorm:"not null;index" json:"exam_year"`
+	ExamKind  string    `gorm:"type:varchar(32);not null;default:gaokao;index" json:"exam_kind"`
+	Region    string    `gorm:"type:varchar(32);not null;default:'';index" json:"region"`
+	EventType string    `gorm:"type:varchar(32);not null" json:"event_type"`
+	EventName string    `gorm:"type:varchar(64);not null" json:"event_name"`
+	EventDate time.Time `gorm:"not null;index" json:"event_date"`
+	IsDelete  bool      `gorm:"default:false" json:"-"`
+}
+
+// TableName 指定表名
+func (ExamEvent) TableName() string {
+	return "exam_event"
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// 模板广场条目状态
+const (
+	// GalleryStatusPending 待审核
+	GalleryStatusPending = "pending"
+	// GalleryStatusApproved 已通过，公开展示
+	GalleryStatusApproved = "approved"
+	// GalleryStatusHidden 已隐藏
+	GalleryStatusHidden = "hidden"
+)
+
+// GalleryTemplate 模板广场条目实体
+// 发布时保存模板名称和内容的快照，之后修改原模板不会影响已审核的内容，重新发布需再次审核
+type GalleryTemplate struct {
+	ID              int64     `gorm:"primaryKey" json:"id,string"`
+	TemplateID      int64     `gorm:"not null;uniqueIndex" json:"template_id,string"`
+	UserID          int64     `gorm:"not null;index" json:"-"`
+	TemplateName    string    `gorm:"type:varchar(40)" json:"template_name"`
+	TemplateContent string    `gorm:"type:varchar(160);not null" json:"template_content"`
+	Status          string    `gorm:"type:varchar(16);not null;default:pending;index" json:"status"`
+	LikeCount       int64     `gorm:"not null;default:0" json:"like_count"`
+	CopyCount       int64     `gorm:"not null;default:0" json:"copy_count"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (GalleryTemplate) TableName() string {
+	return "gallery_template"
+}
+
+// GalleryLike 模板广场点赞记录，每个用户对每个条目最多一条
+type GalleryLike struct {
+	GalleryID int64     `gorm:"primaryKey;autoIncrement:false" json:"gallery_id,string"`
+	UserID    int64     `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (GalleryLike) TableName() string {
+	return "gallery_like"
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// 内容审核记录状态
+const (
+	// ModerationStatusPending 待复核
+	ModerationStatusPending = "pending"
+	// ModerationStatusApproved 复核通过，已按用户提交的内容创建或修改模板
+	ModerationStatusApproved = "approved"
+	// ModerationStatusDismissed 确认拒绝
+	ModerationStatusDismissed = "dismissed"
+)
+
+// 内容审核记录对应的操作
+const (
+	// ModerationActionCreate 创建模板
+	ModerationActionCreate = "create"
+	// ModerationActionUpdate 修改模板
+	ModerationActionUpdate = "update"
+)
+
+// ModerationRecord 模板内容审核记录实体
+// 用户提交的模板未通过内容审核时保存提交的内容和违规原因，供管理员复核误判
+type ModerationRecord struct {
+	ID     int64 `gorm:"primaryKey" json:"id,string"`
+	UserID int64 `gorm:"not null;index" json:"user_id"`
+	// TemplateID 修改的模板 ID，创建模板时为 0
+	TemplateID      int64  `gorm:"not null;default:0" json:"template_id,string"`
+	Action          string `gorm:"type:varchar(16);not null" json:"action"`
+	TemplateName    string `gorm:"type:varchar(40)" json:"template_name"`
+	TemplateContent string `gorm:"type:varchar(160);not null" json:"template_content"`
+	// Violations 违规项，每行一条，格式为「规则: 命中内容」
+	Violations string     `gorm:"type:varchar(1024)" json:"violations"`
+	Status     string     `gorm:"type:varchar(16);not null;default:pending;index" json:"status"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ModerationRecord) TableName() string {
+	return "moderation_record"
+}
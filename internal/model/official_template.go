@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// OfficialTemplate 官方模板实体，由管理员维护
+// 生效的官方模板会出现在 Inline Query 结果中，并可供推送对话选用；
+// 可限定生效时间范围，或仅在距考试开始不超过指定天数时生效（如百日冲刺模板）
+type OfficialTemplate struct {
+	ID              int64  `gorm:"primaryKey" json:"id,string"`
+	TemplateName    string `gorm:"type:varchar(40)" json:"template_name"`
+	TemplateContent string `gorm:"type:varchar(160);not null" json:"template_content"`
+	SortOrder       int    `gorm:"not null;default:0" json:"sort_order"`
+	Active          bool   `gorm:"not null" json:"active"`
+	// StartAt 开始生效时间，为空表示不限
+	StartAt *time.Time `json:"start_at"`
+	// EndAt 停止生效时间（不含），为空表示不限
+	EndAt *time.Time `json:"end_at"`
+	// DaysBeforeExam 仅在距考试开始不超过该天数时生效，0 表示不限
+	DaysBeforeExam int       `gorm:"not null;default:0" json:"days_before_exam"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (OfficialTemplate) TableName() string {
+	return "official_template"
+}
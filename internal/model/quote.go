@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Quote 励志语录实体，由管理员维护
+// 模板中的 {quote} 和每日推送附带的语录从语录库中按日期和对话轮换选取
+type Quote struct {
+	ID      int64  `gorm:"primaryKey" json:"id,string"`
+	Content string `gorm:"type:varchar(200);not null" json:"content"`
+	// Source 出处，如人名、书名，可为空
+	Source string `gorm:"type:varchar(60)" json:"source"`
+	// Tags 标签，逗号分隔
+	Tags      string    `gorm:"type:varchar(100)" json:"tags"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Quote) TableName() string {
+	return "quote"
+}
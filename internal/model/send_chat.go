@@ -1,12 +1,57 @@
 package model
 
+import "time"
+
+// 推送对话的模板轮换策略
+const (
+	// RotationRoundRobin 按模板池顺序依次轮换
+	RotationRoundRobin = "round_robin"
+	// RotationRandom 随机不重复：每轮打乱顺序，模板池中的模板各使用一次后再开始下一轮
+	RotationRandom = "random"
+	// RotationWeekday 按星期选取，周一使用第一个模板，模板数量少于 7 个时循环使用
+	RotationWeekday = "weekday"
+)
+
 // SendChat 发送对话实体
 type SendChat struct {
-	ID     int64  `gorm:"primaryKey;autoIncrement"`
-	ChatID string `gorm:"type:varchar(64);not null"`
+	ID     int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ChatID string `gorm:"type:varchar(64);not null" json:"chat_id"`
+	// TemplateID 选用的官方模板 ID，0 或模板未生效时使用默认模板
+	TemplateID int64 `gorm:"not null;default:0" json:"template_id,string"`
+	// AdminUserID 管理该对话推送的用户 ID，其个人模板可加入模板池，0 表示未设置
+	AdminUserID int64 `gorm:"not null;default:0" json:"admin_user_id,string"`
+	// RotationStrategy 模板池的轮换策略，为空时不轮换（使用 TemplateID 选用的模板）
+	RotationStrategy string `gorm:"type:varchar(16);not null;default:''" json:"rotation_strategy"`
+	// LastEntryID 上次使用的模板池条目 ID
+	LastEntryID int64 `gorm:"not null;default:0" json:"last_entry_id,string"`
+	// LastRotatedAt 上次轮换的时间，同一天（北京时间）内不再轮换
+	LastRotatedAt *time.Time `json:"last_rotated_at"`
 }
 
 // TableName 指定表名
 func (SendChat) TableName() string {
 	return "send_chat"
-}
\ No newline at end of file
+}
+
+// 模板池条目的模板来源
+const (
+	// ChatTemplateSourceOfficial 官方模板
+	ChatTemplateSourceOfficial = "official"
+	// ChatTemplateSourceUser 对话管理员的个人模板
+	ChatTemplateSourceUser = "user"
+)
+
+// SendChatTemplate 推送对话模板池中的模板
+type SendChatTemplate struct {
+	ID         int64 `gorm:"primaryKey" json:"id,string"`
+	SendChatID int64 `gorm:"not null;index" json:"send_chat_id"`
+	// Source 模板来源：official 或 user
+	Source     string `gorm:"type:varchar(16);not null" json:"source"`
+	TemplateID int64  `gorm:"not null" json:"template_id,string"`
+	SortOrder  int    `gorm:"not null;default:0" json:"sort_order"`
+}
+
+// TableName 指定表名
+func (SendChatTemplate) TableName() string {
+	return "send_chat_template"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// TemplateRevision 用户模板修订记录实体
+// 每次修改模板前保存修改前的名称和内容，用于查看历史和恢复误改的模板
+type TemplateRevision struct {
+	ID              int64     `gorm:"primaryKey" json:"id,string"`
+	TemplateID      int64     `gorm:"not null;index" json:"template_id,string"`
+	UserID          int64     `gorm:"not null" json:"-"`
+	TemplateName    string    `gorm:"type:varchar(40)" json:"template_name"`
+	TemplateContent string    `gorm:"type:varchar(160)" json:"template_content"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (TemplateRevision) TableName() string {
+	return "template_revision"
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// UserTarget 用户自定义倒计时目标实体（如模拟考、学校活动）
+type UserTarget struct {
+	ID         int64     `gorm:"primaryKey" json:"id,string"`
+	UserID     int64     `gorm:"not null;index" json:"user_id,string"`
+	TargetName string    `gorm:"type:varchar(40);not null" json:"target_name"`
+	TargetDate time.Time `gorm:"not null" json:"target_date"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserTarget) TableName() string {
+	return "user_target"
+}
@@ -3,11 +3,16 @@ package model
 import "time"
 
 // UserTemplate 用户模板实体
+// 用户模板按收藏优先、排序位置、ID 的顺序排列；每个用户最多一个个人默认模板（IsDefault）
 type UserTemplate struct {
 	ID              int64     `gorm:"primaryKey" json:"id,string"`
 	UserID          int64     `gorm:"not null;index" json:"user_id,string"`
 	TemplateName    string    `gorm:"type:varchar(40)" json:"template_name"`
 	TemplateContent string    `gorm:"type:varchar(160)" json:"template_content"`
+	ShareCode       *string   `gorm:"type:varchar(16);uniqueIndex" json:"share_code,omitempty"`
+	SortOrder       int       `gorm:"not null;default:0" json:"sort_order"`
+	IsFavorite      bool      `gorm:"not null;default:false" json:"is_favorite"`
+	IsDefault       bool      `gorm:"not null;default:false" json:"is_default"`
 	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// UserVariable 用户自定义模板变量实体，模板中以 {var:名称} 引用
+type UserVariable struct {
+	ID        int64     `gorm:"primaryKey" json:"id,string"`
+	UserID    int64     `gorm:"not null;uniqueIndex:idx_user_variable_user_name,priority:1" json:"user_id,string"`
+	VarName   string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_user_variable_user_name,priority:2" json:"var_name"`
+	VarValue  string    `gorm:"type:varchar(60);not null" json:"var_value"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserVariable) TableName() string {
+	return "user_variable"
+}
@@ -0,0 +1,146 @@
+// Package moderation 提供用户输入文本（如模板）的内容审核流水线
+// 流水线先清理不可见字符，再依次执行各审核规则（违禁词、链接、提及等），规则可按需组合
+package moderation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 审核规则名称
+const (
+	// RuleBannedWord 违禁词
+	RuleBannedWord = "banned_word"
+	// RuleURL 链接
+	RuleURL = "url"
+	// RuleMention 提及用户
+	RuleMention = "mention"
+)
+
+// 规则策略
+const (
+	// PolicyAllow 允许
+	PolicyAllow = "allow"
+	// PolicyReject 拒绝
+	PolicyReject = "reject"
+)
+
+// Violation 违规项
+type Violation struct {
+	// Rule 触发的规则名称
+	Rule string `json:"rule"`
+	// Reason 面向用户的原因，不包含命中的具体内容
+	Reason string `json:"reason"`
+	// Detail 命中的内容，仅供管理员复核
+	Detail string `json:"detail"`
+}
+
+// Rule 审核规则
+type Rule interface {
+	// Check 检查已清理的文本，通过时返回 nil
+	Check(text string) []Violation
+}
+
+// Result 审核结果
+type Result struct {
+	// Text 清理不可见字符后的文本
+	Text string
+	// Violations 违规项，为空表示通过
+	Violations []Violation
+}
+
+// Passed 是否通过审核
+func (r Result) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// Pipeline 内容审核流水线
+type Pipeline struct {
+	rules []Rule
+}
+
+// NewPipeline 创建内容审核流水线，按顺序执行 rules
+func NewPipeline(rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules}
+}
+
+// Options 内容审核配置
+type Options struct {
+	// BannedWords 违禁词条，格式见 BannedWordRule
+	BannedWords []string
+	// Variants 变体字到标准字的映射
+	Variants map[rune]rune
+	// URLPolicy 链接策略：PolicyReject 拒绝（默认）或 PolicyAllow 允许
+	URLPolicy string
+	// AllowedDomains 拒绝链接时仍允许的域名
+	AllowedDomains []string
+	// MentionPolicy 提及用户策略：PolicyReject 拒绝（默认）或 PolicyAllow 允许
+	MentionPolicy string
+	// AllowedMentions 拒绝提及时仍允许提及的用户名
+	AllowedMentions []string
+}
+
+// New 按配置创建内容审核流水线
+func New(opts Options) *Pipeline {
+	var rules []Rule
+	if len(opts.BannedWords) > 0 {
+		rules = append(rules, NewBannedWordRule(opts.BannedWords, opts.Variants))
+	}
+	if opts.URLPolicy != PolicyAllow {
+		rules = append(rules, NewURLRule(opts.AllowedDomains))
+	}
+	if opts.MentionPolicy != PolicyAllow {
+		rules = append(rules, NewMentionRule(opts.AllowedMentions))
+	}
+	return NewPipeline(rules...)
+}
+
+// Moderate 清理文本并执行全部规则
+func (p *Pipeline) Moderate(text string) Result {
+	result := Result{Text: Sanitize(text)}
+	for _, rule := range p.rules {
+		result.Violations = append(result.Violations, rule.Check(result.Text)...)
+	}
+	return result
+}
+
+// Sanitize 移除零宽字符、双向文本控制符和除换行外的控制字符
+// 零宽连接符（U+200D）位于两个符号之间时保留，以免拆开组合 emoji
+func Sanitize(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for i, r := range runes {
+		if r == '\u200d' && i > 0 && i < len(runes)-1 && isEmojiPart(runes[i-1]) && isEmojiPart(runes[i+1]) {
+			b.WriteRune(r)
+			continue
+		}
+		if isInvisible(r) || (unicode.IsControl(r) && r != '\n') {
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// isInvisible 是否为零宽字符或格式控制字符
+func isInvisible(r rune) bool {
+	switch {
+	case r >= '\u200b' && r <= '\u200f', // 零宽空格、零宽（非）连接符、方向标记
+		r >= '\u202a' && r <= '\u202e', // 双向文本嵌入与覆盖
+		r >= '\u2060' && r <= '\u2064', // 单词连接符、不可见运算符
+		r >= '\u2066' && r <= '\u2069', // 双向文本隔离
+		r == '\u00ad',                  // 软连字符
+		r == '\u180e',                  // 蒙古文元音分隔符
+		r == '\ufeff':                  // 零宽不换行空格（BOM）
+		return true
+	}
+	return false
+}
+
+// isEmojiPart 是否可能为组合 emoji 的组成部分（符号或变体选择符）
+func isEmojiPart(r rune) bool {
+	return unicode.Is(unicode.So, r) || r == '\ufe0f'
+}
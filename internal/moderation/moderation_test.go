@@ -0,0 +1,60 @@
+package moderation
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "距离{exam}还有{time}", "距离{exam}还有{time}"},
+		{"zero width", "距\u200b离{exam}\u200c还有\ufeff{time}", "距离{exam}还有{time}"},
+		{"bidi override", "\u202e距离{exam}\u202c", "距离{exam}"},
+		{"control", "a\x00b\x07c\r", "abc"},
+		{"keep newline", "第一行\n第二行", "第一行\n第二行"},
+		{"soft hyphen", "s\u00adb", "sb"},
+		{"emoji zwj", "👨\u200d👩\u200d👧", "👨\u200d👩\u200d👧"},
+		{"zwj between letters", "a\u200db", "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.input); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeline_Moderate(t *testing.T) {
+	pipeline := New(Options{
+		BannedWords:     []string{"坏词|huaici"},
+		AllowedDomains:  []string{"gaokao.app"},
+		AllowedMentions: []string{"gaokao_bot"},
+	})
+
+	result := pipeline.Moderate("坏\u200b词 见 https://example.com 和 @someone_else")
+	if result.Passed() {
+		t.Fatal("Moderate() should reject")
+	}
+	if result.Text != "坏词 见 https://example.com 和 @someone_else" {
+		t.Errorf("Text = %q", result.Text)
+	}
+	rules := map[string]bool{}
+	for _, v := range result.Violations {
+		rules[v.Rule] = true
+	}
+	if !rules[RuleBannedWord] || !rules[RuleURL] || !rules[RuleMention] || len(result.Violations) != 3 {
+		t.Errorf("Violations = %+v", result.Violations)
+	}
+
+	if result := pipeline.Moderate("关注 @gaokao_bot，访问 https://www.gaokao.app/x"); !result.Passed() {
+		t.Errorf("Moderate() allowlisted = %+v", result.Violations)
+	}
+
+	// 允许链接和提及时只检查违禁词
+	permissive := New(Options{URLPolicy: PolicyAllow, MentionPolicy: PolicyAllow})
+	if result := permissive.Moderate("https://example.com @someone"); !result.Passed() {
+		t.Errorf("Moderate() permissive = %+v", result.Violations)
+	}
+}
@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// urlPattern 匹配带协议或 www. 前缀的链接，以及以常见顶级域名结尾的裸域名（如 t.me/xxx）
+	urlPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s]+|\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.(?:com|net|org|cn|io|me|xyz|top|cc|info|app|dev|co|ly|gg|link|site|online|club|vip|shop|tk)\b(?:/[^\s]*)?`)
+	// urlHostPattern 从链接中提取主机名
+	urlHostPattern = regexp.MustCompile(`(?i)^(?:https?://)?([^/:?#\s]+)`)
+	// mentionPattern 匹配 Telegram 用户名提及（5-32 位，以字母开头），不匹配邮箱地址
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z]\w{4,31})\b`)
+)
+
+// URLRule 链接规则：拒绝除白名单域名（及其子域名）外的链接
+type URLRule struct {
+	allowedDomains []string
+}
+
+// NewURLRule 创建链接规则，allowedDomains 中的域名及其子域名不受限制
+func NewURLRule(allowedDomains []string) *URLRule {
+	domains := make([]string, 0, len(allowedDomains))
+	for _, domain := range allowedDomains {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return &URLRule{allowedDomains: domains}
+}
+
+// Check 检查文本中的链接，全角字符按半角处理
+func (r *URLRule) Check(text string) []Violation {
+	var violations []Violation
+	for _, link := range urlPattern.FindAllString(norm.NFKC.String(text), -1) {
+		if r.allowed(link) {
+			continue
+		}
+		violations = append(violations, Violation{
+			Rule:   RuleURL,
+			Reason: "不允许包含链接",
+			Detail: link,
+		})
+	}
+	return violations
+}
+
+// allowed 链接的主机名是否属于白名单域名
+func (r *URLRule) allowed(link string) bool {
+	match := urlHostPattern.FindStringSubmatch(link)
+	if match == nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(match[1]), "www.")
+	for _, domain := range r.allowedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// MentionRule 提及规则：拒绝 @ 白名单以外的用户
+type MentionRule struct {
+	allowed map[string]bool
+}
+
+// NewMentionRule 创建提及规则，allowedUsernames 中的用户名（不区分大小写，可带 @）不受限制
+func NewMentionRule(allowedUsernames []string) *MentionRule {
+	allowed := make(map[string]bool, len(allowedUsernames))
+	for _, username := range allowedUsernames {
+		if username = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@")); username != "" {
+			allowed[username] = true
+		}
+	}
+	return &MentionRule{allowed: allowed}
+}
+
+// Check 检查文本中的用户名提及，全角字符按半角处理
+func (r *MentionRule) Check(text string) []Violation {
+	var violations []Violation
+	for _, match := range mentionPattern.FindAllStringSubmatch(norm.NFKC.String(text), -1) {
+		if r.allowed[strings.ToLower(match[1])] {
+			continue
+		}
+		violations = append(violations, Violation{
+			Rule:   RuleMention,
+			Reason: "不允许提及其他用户",
+			Detail: "@" + match[1],
+		})
+	}
+	return violations
+}
@@ -0,0 +1,51 @@
+package moderation
+
+import "testing"
+
+func TestURLRule(t *testing.T) {
+	rule := NewURLRule([]string{"gaokao.app", " Example.ORG "})
+
+	tests := []struct {
+		text   string
+		reject bool
+	}{
+		{"访问 https://evil.com/path", true},
+		{"访问 www.evil.net", true},
+		{"加群 t.me/somegroup", true},
+		{"全角 ｈｔｔｐｓ：／／ｅｖｉｌ．ｃｏｍ", true},
+		{"官网 https://gaokao.app", false},
+		{"子域名 https://m.gaokao.app/a", false},
+		{"大小写 HTTP://WWW.EXAMPLE.ORG", false},
+		{"仿冒 https://gaokao.app.evil.com", true},
+		{"距离{exam}还有{time}", false},
+		{"版本 v1.2", false},
+	}
+	for _, tt := range tests {
+		if got := len(rule.Check(tt.text)) > 0; got != tt.reject {
+			t.Errorf("Check(%q) reject = %v, want %v", tt.text, got, tt.reject)
+		}
+	}
+}
+
+func TestMentionRule(t *testing.T) {
+	rule := NewMentionRule([]string{"@GaoKao_Bot"})
+
+	tests := []struct {
+		text   string
+		reject bool
+	}{
+		{"找 @someone 玩", true},
+		{"@someone", true},
+		{"全角 ＠someone", true},
+		{"@gaokao_bot", false},
+		{"邮箱 user@example.com", false},
+		{"太短 @abc", false},
+		{"距离{exam}还有{time}", false},
+	}
+	for _, tt := range tests {
+		violations := rule.Check(tt.text)
+		if got := len(violations) > 0; got != tt.reject {
+			t.Errorf("Check(%q) = %+v, reject want %v", tt.text, violations, tt.reject)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package moderation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// leetVariants 拉丁字母中常见的数字替代写法，仅在包含字母的单词中替换（如 5b -> sb）
+var leetVariants = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+}
+
+// bannedWord 违禁词条
+type bannedWord struct {
+	// word 词条的主写法，用于向管理员展示
+	word string
+	// normalized 归一化后的写法
+	normalized string
+}
+
+// BannedWordRule 违禁词规则
+// 每个词条可以用 | 附带其他写法，如「傻瓜|shagua|sg」。匹配前统一全角半角、大小写和变体字；
+// 含中文的写法忽略夹杂的空格和符号后按子串匹配，纯拉丁字母的写法按单词匹配，
+// 可跨越空格、连字符等分隔符（sha-gua、s g），但不会匹配其他单词的一部分。
+// 不做汉字与拼音之间的转换，只匹配词条中列出的写法：仅配置「傻瓜」时不会命中 shagua，
+// 拼音、缩写等写法需要逐一列出
+type BannedWordRule struct {
+	cjk      []bannedWord
+	latin    []bannedWord
+	variants map[rune]rune
+}
+
+// NewBannedWordRule 创建违禁词规则
+// variants 为变体字到标准字的映射（如繁体字、异体字），可为 nil
+func NewBannedWordRule(entries []string, variants map[rune]rune) *BannedWordRule {
+	rule := &BannedWordRule{variants: variants}
+
+	for _, entry := range entries {
+		forms := strings.Split(entry, "|")
+		word := strings.TrimSpace(forms[0])
+		for _, form := range forms {
+			normalized := rule.strip(rule.normalize(form))
+			if normalized == "" {
+				continue
+			}
+			if isASCII(normalized) {
+				rule.latin = append(rule.latin, bannedWord{word: word, normalized: replaceLeet(normalized)})
+			} else {
+				rule.cjk = append(rule.cjk, bannedWord{word: word, normalized: normalized})
+			}
+		}
+	}
+
+	return rule
+}
+
+// Check 检查文本是否包含违禁词，每个词条最多报告一次
+func (r *BannedWordRule) Check(text string) []Violation {
+	normalized := r.normalize(text)
+	stripped := r.strip(normalized)
+	tokens := latinTokens(normalized)
+
+	var violations []Violation
+	reported := make(map[string]bool)
+	report := func(word string) {
+		if reported[word] {
+			return
+		}
+		reported[word] = true
+		violations = append(violations, Violation{
+			Rule:   RuleBannedWord,
+			Reason: "包含不允许的词语",
+			Detail: word,
+		})
+	}
+
+	for _, w := range r.cjk {
+		if strings.Contains(stripped, w.normalized) {
+			report(w.word)
+		}
+	}
+	for _, w := range r.latin {
+		if matchTokens(tokens, w.normalized) {
+			report(w.word)
+		}
+	}
+
+	return violations
+}
+
+// normalize 统一全角半角（NFKC）、大小写和变体字
+func (r *BannedWordRule) normalize(text string) string {
+	text = strings.ToLower(norm.NFKC.String(text))
+	if len(r.variants) == 0 {
+		return text
+	}
+	return strings.Map(func(c rune) rune {
+		if v, ok := r.variants[c]; ok {
+			return v
+		}
+		return c
+	}, text)
+}
+
+// strip 仅保留字母和数字，去除夹杂的空格和符号
+func (r *BannedWordRule) strip(text string) string {
+	return strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) || unicode.IsNumber(c) {
+			return c
+		}
+		return -1
+	}, text)
+}
+
+// latinTokens 将文本拆分为由 ASCII 字母和数字组成的单词，并替换数字写法
+func latinTokens(text string) []string {
+	fields := strings.FieldsFunc(text, func(c rune) bool {
+		return !(c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c)))
+	})
+
+	for i, field := range fields {
+		fields[i] = replaceLeet(field)
+	}
+	return fields
+}
+
+// replaceLeet 将包含字母的单词中的数字替换为对应字母，纯数字保持不变
+func replaceLeet(word string) string {
+	if strings.IndexFunc(word, unicode.IsLetter) < 0 {
+		return word
+	}
+	return strings.Map(func(c rune) rune {
+		if v, ok := leetVariants[c]; ok {
+			return v
+		}
+		return c
+	}, word)
+}
+
+// matchTokens 是否存在连续的若干单词恰好拼接为 word
+func matchTokens(tokens []string, word string) bool {
+	for i := range tokens {
+		joined := ""
+		for j := i; j < len(tokens) && len(joined) < len(word); j++ {
+			joined += tokens[j]
+			if joined == word {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseWordList 解析违禁词文件：每行一个词条，忽略空行和以 # 开头的注释
+func ParseWordList(reader io.Reader) ([]string, error) {
+	var entries []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParseVariants 解析变体字映射，每项格式为「变体=标准字」，如「妳=你」
+func ParseVariants(pairs []string) (map[rune]rune, error) {
+	variants := make(map[rune]rune, len(pairs))
+	for _, pair := range pairs {
+		from, to, ok := strings.Cut(pair, "=")
+		fromRunes, toRunes := []rune(strings.TrimSpace(from)), []rune(strings.TrimSpace(to))
+		if !ok || len(fromRunes) != 1 || len(toRunes) != 1 {
+			return nil, fmt.Errorf("无效的变体字映射 %q，格式应为「变体=标准字」", pair)
+		}
+		variants[unicode.ToLower(fromRunes[0])] = unicode.ToLower(toRunes[0])
+	}
+	return variants, nil
+}
+
+// isASCII 是否仅包含 ASCII 字符
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBannedWordRule(t *testing.T) {
+	rule := NewBannedWordRule([]string{"傻瓜|shagua|sg", "坏蛋"}, map[rune]rune{'壞': '坏'})
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"exact", "你是傻瓜", "傻瓜"},
+		{"separated", "傻 * 瓜", "傻瓜"},
+		{"variant", "壞蛋", "坏蛋"},
+		{"listed pinyin", "sha gua", "傻瓜"},
+		{"listed pinyin hyphen", "Sha-Gua!", "傻瓜"},
+		{"unlisted pinyin", "huai dan", ""},
+		{"full width", "ＳＨＡＧＵＡ", "傻瓜"},
+		{"initials", "s g", "傻瓜"},
+		{"leet", "5g", "傻瓜"},
+		{"clean", "距离{exam}还有{time}", ""},
+		{"part of word", "things go", ""},
+		{"digits only", "2026 年", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := rule.Check(tt.text)
+			if tt.want == "" {
+				if len(violations) != 0 {
+					t.Errorf("Check(%q) = %+v, want none", tt.text, violations)
+				}
+				return
+			}
+			if len(violations) != 1 || violations[0].Detail != tt.want || violations[0].Rule != RuleBannedWord {
+				t.Errorf("Check(%q) = %+v, want %s", tt.text, violations, tt.want)
+			}
+		})
+	}
+
+	// 同一词条的多种写法只报告一次
+	if violations := rule.Check("傻瓜 shagua"); len(violations) != 1 {
+		t.Errorf("Check() = %+v, want 1 violation", violations)
+	}
+}
+
+func TestParseWordList(t *testing.T) {
+	entries, err := ParseWordList(strings.NewReader("# 注释\n傻瓜|shagua\n\n  坏蛋  \n"))
+	if err != nil {
+		t.Fatalf("ParseWordList() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0] != "傻瓜|shagua" || entries[1] != "坏蛋" {
+		t.Errorf("ParseWordList() = %q", entries)
+	}
+}
+
+func TestParseVariants(t *testing.T) {
+	variants, err := ParseVariants([]string{"壞=坏", " 妳 = 你 "})
+	if err != nil {
+		t.Fatalf("ParseVariants() error = %v", err)
+	}
+	if variants['壞'] != '坏' || variants['妳'] != '你' {
+		t.Errorf("ParseVariants() = %v", variants)
+	}
+
+	for _, invalid := range []string{"壞", "壞=", "壞蛋=坏蛋"} {
+		if _, err := ParseVariants([]string{invalid}); err == nil {
+			t.Errorf("ParseVariants(%q) should fail", invalid)
+		}
+	}
+}
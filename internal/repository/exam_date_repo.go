@@ -1,39 +1,236 @@
 package repository
 
 import (
+	"context"
+	"sort"
 	"time"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// ExamDateRepository 考试日期仓储
-type ExamDateRepository struct {
-	db *gorm.DB
+// GormExamDateRepository 基于 GORM 的考试日期仓储
+type GormExamDateRepository struct {
+	db    *gorm.DB
+	reads ReadRouter
 }
 
 // NewExamDateRepository 创建考试日期仓储
-func NewExamDateRepository(db *gorm.DB) *ExamDateRepository {
-	return &ExamDateRepository{db: db}
+func NewExamDateRepository(db *gorm.DB) *GormExamDateRepository {
+	return &GormExamDateRepository{db: db}
 }
 
-// GetExamsInRange 获取时间范围内的考试
-func (r *ExamDateRepository) GetExamsInRange(now time.Time) ([]model.ExamDate, error) {
+// WithReadReplicas 将考试查询路由到只读副本
+// 导入前的差异比对（GetAll 包含已删除记录）仍使用主库，避免基于延迟数据写入
+func (r *GormExamDateRepository) WithReadReplicas(router ReadRouter) *GormExamDateRepository {
+	r.reads = router
+	return r
+}
+
+// GetExamsInRange 获取时间范围内的考试，按开始时间排序
+func (r *GormExamDateRepository) GetExamsInRange(ctx context.Context, now time.Time) ([]model.ExamDate, error) {
+	return r.Find(ctx, ExamQuery{ActiveAt: now})
+}
+
+// GetExamByYear 按年份获取考试，按开始时间排序
+func (r *GormExamDateRepository) GetExamByYear(ctx context.Context, year int) ([]model.ExamDate, error) {
+	return r.Find(ctx, ExamQuery{Year: year})
+}
+
+// GetAll 获取全部考试，按年份和类型排序
+// includeDeleted 为 true 时同时返回已标记删除的记录（用于导入时的差异比对）
+func (r *GormExamDateRepository) GetAll(ctx context.Context, includeDeleted bool) ([]model.ExamDate, error) {
 	var exams []model.ExamDate
 
-	err := r.db.Where("exam_year_begin_date <= ? AND exam_year_end_date >= ? AND is_delete = ?",
-		now, now, false).
-		Find(&exams).Error
+	if includeDeleted {
+		err := r.db.WithContext(ctx).Order("exam_year ASC").Order("exam_kind ASC").Find(&exams).Error
+		return exams, err
+	}
+
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		return db.Where("is_delete = ?", false).Order("exam_year ASC").Order("exam_kind ASC").Find(&exams).Error
+	})
 
 	return exams, err
 }
 
-// GetExamByYear 按年份获取考试
-func (r *ExamDateRepository) GetExamByYear(year int) ([]model.ExamDate, error) {
+// examNaturalKeyUpsert 按（年份、考试类型、地区）唯一索引执行插入或更新
+// MySQL 生成 ON DUPLICATE KEY UPDATE，PostgreSQL 与 SQLite 生成 ON CONFLICT ... DO UPDATE
+var examNaturalKeyUpsert = clause.OnConflict{
+	Columns: []clause.Column{{Name: "exam_year"}, {Name: "exam_kind"}, {Name: "region"}},
+	DoUpdates: clause.AssignmentColumns([]string{
+		"exam_desc", "short_desc", "exam_begin_date", "exam_end_date",
+		"exam_year_begin_date", "exam_year_end_date", "is_delete",
+	}),
+}
+
+// SaveAll 在事务中批量保存考试
+// ID 为 0 的记录按（年份、考试类型、地区）插入或更新已有记录，避免并发保存产生重复考试；
+// 其余记录按主键整行更新，任一失败时整体回滚
+func (r *GormExamDateRepository) SaveAll(ctx context.Context, exams []model.ExamDate) error {
+	if len(exams) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range exams {
+			exam := &exams[i]
+			if exam.ID == 0 {
+				if exam.ExamKind == "" {
+					exam.ExamKind = constant.ExamKindGaokao
+				}
+				if err := tx.Clauses(examNaturalKeyUpsert).Create(exam).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Save(exam).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetEndingAfter 获取结束时间不早于 since 的考试，按开始时间排序
+func (r *GormExamDateRepository) GetEndingAfter(ctx context.Context, since time.Time) ([]model.ExamDate, error) {
 	var exams []model.ExamDate
 
-	err := r.db.Where("exam_year = ? AND is_delete = ?", year, false).
-		Find(&exams).Error
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		return db.Where("exam_end_date >= ? AND is_delete = ?", since, false).
+			Order("exam_begin_date ASC").
+			Find(&exams).Error
+	})
 
 	return exams, err
-}
\ No newline at end of file
+}
+
+// ExamQuery 考试查询条件，零值字段不参与过滤
+type ExamQuery struct {
+	// Year 考试年份
+	Year int
+	// Kind 考试类型，如 gaokao
+	Kind string
+	// Region 考试地区；nil 表示不限地区，指向空字符串表示仅全国统一考试
+	Region *string
+	// ActiveAt 考试年时间范围需包含该时刻
+	ActiveAt time.Time
+	// BeginAfter 开始时间晚于该时刻
+	BeginAfter time.Time
+	// BeginNotAfter 开始时间不晚于该时刻
+	BeginNotAfter time.Time
+	// EndAfter 结束时间晚于该时刻
+	EndAfter time.Time
+	// EndNotAfter 结束时间不晚于该时刻
+	EndNotAfter time.Time
+	// OrderByEndDesc 为 true 时按结束时间倒序，否则按开始时间正序
+	OrderByEndDesc bool
+	// Limit 最多返回的记录数，0 表示不限
+	Limit int
+}
+
+// Find 按条件查询未删除的考试
+// 默认按开始时间、ID 正序排列，保证结果顺序稳定
+func (r *GormExamDateRepository) Find(ctx context.Context, q ExamQuery) ([]model.ExamDate, error) {
+	var exams []model.ExamDate
+
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		query := db.Where("is_delete = ?", false)
+		if q.Year != 0 {
+			query = query.Where("exam_year = ?", q.Year)
+		}
+		if q.Kind != "" {
+			query = query.Where("exam_kind = ?", q.Kind)
+		}
+		if q.Region != nil {
+			query = query.Where("region = ?", *q.Region)
+		}
+		if !q.ActiveAt.IsZero() {
+			query = query.Where("exam_year_begin_date <= ? AND exam_year_end_date >= ?", q.ActiveAt, q.ActiveAt)
+		}
+		if !q.BeginAfter.IsZero() {
+			query = query.Where("exam_begin_date > ?", q.BeginAfter)
+		}
+		if !q.BeginNotAfter.IsZero() {
+			query = query.Where("exam_begin_date <= ?", q.BeginNotAfter)
+		}
+		if !q.EndAfter.IsZero() {
+			query = query.Where("exam_end_date > ?", q.EndAfter)
+		}
+		if !q.EndNotAfter.IsZero() {
+			query = query.Where("exam_end_date <= ?", q.EndNotAfter)
+		}
+
+		if q.OrderByEndDesc {
+			query = query.Order("exam_end_date DESC").Order("id DESC")
+		} else {
+			query = query.Order("exam_begin_date ASC").Order("id ASC")
+		}
+		if q.Limit > 0 {
+			query = query.Limit(q.Limit)
+		}
+
+		return query.Find(&exams).Error
+	})
+
+	return exams, err
+}
+
+// Match 判断考试是否满足查询条件，已删除的考试不满足；条件与 Find 的 SQL 一致
+func (q ExamQuery) Match(exam *model.ExamDate) bool {
+	switch {
+	case exam.IsDelete:
+		return false
+	case q.Year != 0 && exam.ExamYear != q.Year:
+		return false
+	case q.Kind != "" && exam.ExamKind != q.Kind:
+		return false
+	case q.Region != nil && exam.Region != *q.Region:
+		return false
+	case !q.ActiveAt.IsZero() && (exam.ExamYearBeginDate.After(q.ActiveAt) || exam.ExamYearEndDate.Before(q.ActiveAt)):
+		return false
+	case !q.BeginAfter.IsZero() && !exam.ExamBeginDate.After(q.BeginAfter):
+		return false
+	case !q.BeginNotAfter.IsZero() && exam.ExamBeginDate.After(q.BeginNotAfter):
+		return false
+	case !q.EndAfter.IsZero() && !exam.ExamEndDate.After(q.EndAfter):
+		return false
+	case !q.EndNotAfter.IsZero() && exam.ExamEndDate.After(q.EndNotAfter):
+		return false
+	}
+	return true
+}
+
+// Apply 在内存中对考试列表执行查询（过滤、排序、截取），结果与 Find 一致
+// 返回新的切片，不修改传入的 exams
+func (q ExamQuery) Apply(exams []model.ExamDate) []model.ExamDate {
+	var result []model.ExamDate
+	for i := range exams {
+		if q.Match(&exams[i]) {
+			result = append(result, exams[i])
+		}
+	}
+
+	if q.OrderByEndDesc {
+		sort.Slice(result, func(i, j int) bool {
+			if !result[i].ExamEndDate.Equal(result[j].ExamEndDate) {
+				return result[i].ExamEndDate.After(result[j].ExamEndDate)
+			}
+			return result[i].ID > result[j].ID
+		})
+	} else {
+		sort.Slice(result, func(i, j int) bool {
+			if !result[i].ExamBeginDate.Equal(result[j].ExamBeginDate) {
+				return result[i].ExamBeginDate.Before(result[j].ExamBeginDate)
+			}
+			return result[i].ID < result[j].ID
+		})
+	}
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -70,7 +72,7 @@ func TestExamDateRepository_GetExamsInRange(t *testing.T) {
 		IsDelete:          true,
 	})
 
-	result, err := repo.GetExamsInRange(now)
+	result, err := repo.GetExamsInRange(context.Background(), now)
 	if err != nil {
 		t.Errorf("GetExamsInRange() error = %v", err)
 	}
@@ -105,7 +107,7 @@ func TestExamDateRepository_GetExamsInRange_ExamBeginBoundary(t *testing.T) {
 	db.Create(exam)
 
 	now := time.Date(2026, 6, 7, 9, 0, 0, 0, bjtZone)
-	result, err := repo.GetExamsInRange(now)
+	result, err := repo.GetExamsInRange(context.Background(), now)
 	if err != nil {
 		t.Fatalf("GetExamsInRange() error = %v", err)
 	}
@@ -168,7 +170,7 @@ func TestExamDateRepository_GetExamByYear(t *testing.T) {
 		IsDelete:          true,
 	})
 
-	result, err := repo.GetExamByYear(year)
+	result, err := repo.GetExamByYear(context.Background(), year)
 	if err != nil {
 		t.Errorf("GetExamByYear() error = %v", err)
 	}
@@ -192,7 +194,7 @@ func TestExamDateRepository_GetExamByYear_NotFound(t *testing.T) {
 	db := setupExamDateTestDB(t)
 	repo := NewExamDateRepository(db)
 
-	result, err := repo.GetExamByYear(2099)
+	result, err := repo.GetExamByYear(context.Background(), 2099)
 	if err != nil {
 		t.Errorf("GetExamByYear() error = %v", err)
 	}
@@ -201,3 +203,249 @@ func TestExamDateRepository_GetExamByYear_NotFound(t *testing.T) {
 		t.Errorf("Expected 0 exams, got %d", len(result))
 	}
 }
+
+func TestExamDateRepository_GetAll(t *testing.T) {
+	db := setupExamDateTestDB(t)
+	repo := NewExamDateRepository(db)
+
+	base := time.Date(2026, 6, 7, 9, 0, 0, 0, util.GetBJTLocation())
+	db.Create(&model.ExamDate{ID: 1, ExamYear: 2027, ExamKind: "gaokao", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base})
+	db.Create(&model.ExamDate{ID: 2, ExamYear: 2026, ExamKind: "gaokao", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base})
+	db.Create(&model.ExamDate{ID: 3, ExamYear: 2025, ExamKind: "gaokao", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base, IsDelete: true})
+
+	result, err := repo.GetAll(context.Background(), false)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 exams, got %d", len(result))
+	}
+	if result[0].ExamYear != 2026 || result[1].ExamYear != 2027 {
+		t.Errorf("Expected exams ordered by year, got %d, %d", result[0].ExamYear, result[1].ExamYear)
+	}
+
+	result, err = repo.GetAll(context.Background(), true)
+	if err != nil {
+		t.Fatalf("GetAll(true) error = %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("Expected 3 exams including deleted, got %d", len(result))
+	}
+}
+
+func TestExamDateRepository_SaveAll(t *testing.T) {
+	db := setupExamDateTestDB(t)
+	repo := NewExamDateRepository(db)
+
+	base := time.Date(2026, 6, 7, 9, 0, 0, 0, util.GetBJTLocation())
+	db.Create(&model.ExamDate{ID: 1, ExamYear: 2026, ExamKind: "gaokao", ExamDesc: "旧描述", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base, IsDelete: true})
+
+	exams := []model.ExamDate{
+		{ID: 1, ExamYear: 2026, ExamKind: "gaokao", ExamDesc: "新描述", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base},
+		{ExamYear: 2027, ExamKind: "gaokao", ExamDesc: "2027年高考", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base},
+	}
+	if err := repo.SaveAll(context.Background(), exams); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+
+	if exams[1].ID == 0 {
+		t.Error("Expected ID to be assigned for created exam")
+	}
+
+	var updated model.ExamDate
+	db.First(&updated, 1)
+	if updated.ExamDesc != "新描述" {
+		t.Errorf("ExamDesc = %s, want 新描述", updated.ExamDesc)
+	}
+	if updated.IsDelete {
+		t.Error("Expected updated exam to be restored (IsDelete = false)")
+	}
+
+	var count int64
+	db.Model(&model.ExamDate{}).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected 2 exams, got %d", count)
+	}
+}
+
+func TestExamDateRepository_SaveAll_UpsertsByNaturalKey(t *testing.T) {
+	db := setupExamDateTestDB(t)
+	repo := NewExamDateRepository(db)
+
+	base := time.Date(2026, 6, 7, 9, 0, 0, 0, util.GetBJTLocation())
+	db.Create(&model.ExamDate{ID: 1, ExamYear: 2026, ExamKind: "gaokao", ExamDesc: "旧描述", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base, IsDelete: true})
+
+	// 未指定 ID 但年份、类型和地区与已有记录相同时更新已有记录，不产生重复考试
+	exams := []model.ExamDate{
+		{ExamYear: 2026, ExamKind: "gaokao", ExamDesc: "新描述", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base},
+		{ExamYear: 2026, ExamKind: "gaokao", Region: "北京", ExamDesc: "北京", ExamBeginDate: base, ExamEndDate: base, ExamYearBeginDate: base, ExamYearEndDate: base},
+	}
+	if err := repo.SaveAll(context.Background(), exams); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+
+	var all []model.ExamDate
+	db.Order("id").Find(&all)
+	if len(all) != 2 {
+		t.Fatalf("Expected 2 exams, got %d", len(all))
+	}
+	if all[0].ID != 1 || all[0].ExamDesc != "新描述" || all[0].IsDelete {
+		t.Errorf("Upserted exam = %+v, want id 1 updated and restored", all[0])
+	}
+}
+
+func TestExamDateRepository_SaveAll_Empty(t *testing.T) {
+	db := setupExamDateTestDB(t)
+	repo := NewExamDateRepository(db)
+
+	if err := repo.SaveAll(context.Background(), nil); err != nil {
+		t.Errorf("SaveAll(nil) error = %v", err)
+	}
+}
+
+func TestExamDateRepository_GetEndingAfter(t *testing.T) {
+	db := setupExamDateTestDB(t)
+	repo := NewExamDateRepository(db)
+
+	now := time.Now()
+	create := func(id uint, begin time.Time, deleted bool) {
+		db.Create(&model.ExamDate{
+			ID:                id,
+			ExamYear:          begin.Year(),
+			ExamDesc:          "考试",
+			ShortDesc:         "考试",
+			ExamBeginDate:     begin,
+			ExamEndDate:       begin.AddDate(0, 0, 3),
+			ExamYearBeginDate: begin.AddDate(-1, 0, 0),
+			ExamYearEndDate:   begin.AddDate(0, 0, 3),
+			IsDelete:          deleted,
+		})
+	}
+	create(1, now.AddDate(2, 0, 0), false)
+	create(2, now.AddDate(1, 0, 0), false)
+	create(3, now.AddDate(-2, 0, 0), false)
+	create(4, now.AddDate(1, 0, 0), true)
+
+	result, err := repo.GetEndingAfter(context.Background(), now)
+	if err != nil {
+		t.Fatalf("GetEndingAfter() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 exams, got %d", len(result))
+	}
+	if result[0].ID != 2 || result[1].ID != 1 {
+		t.Errorf("Expected exams ordered by begin date [2 1], got [%d %d]", result[0].ID, result[1].ID)
+	}
+}
+
+func TestExamDateRepository_Find(t *testing.T) {
+	db := setupExamDateTestDB(t)
+	repo := NewExamDateRepository(db)
+
+	loc := util.GetBJTLocation()
+	create := func(id uint, kind, region string, begin time.Time) {
+		db.Create(&model.ExamDate{
+			ID:                id,
+			ExamYear:          begin.Year(),
+			ExamKind:          kind,
+			Region:            region,
+			ExamDesc:          "考试",
+			ShortDesc:         "考试",
+			ExamBeginDate:     begin,
+			ExamEndDate:       begin.AddDate(0, 0, 3),
+			ExamYearBeginDate: begin.AddDate(-1, 0, 0),
+			ExamYearEndDate:   begin.AddDate(0, 0, 3),
+		})
+	}
+	// 故意乱序插入，验证结果按开始时间排序
+	create(1, "gaokao", "", time.Date(2027, 6, 7, 9, 0, 0, 0, loc))
+	create(2, "zhongkao", "北京", time.Date(2026, 6, 24, 9, 0, 0, 0, loc))
+	create(3, "gaokao", "", time.Date(2026, 6, 7, 9, 0, 0, 0, loc))
+	create(4, "gaokao", "北京", time.Date(2026, 6, 7, 9, 0, 0, 0, loc))
+
+	nationwide := ""
+	beijing := "北京"
+	now := time.Date(2026, 6, 20, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		name  string
+		query ExamQuery
+		want  []uint
+	}{
+		{"all", ExamQuery{}, []uint{3, 4, 2, 1}},
+		{"year", ExamQuery{Year: 2026}, []uint{3, 4, 2}},
+		{"kind", ExamQuery{Kind: "gaokao"}, []uint{3, 4, 1}},
+		{"nationwide", ExamQuery{Region: &nationwide}, []uint{3, 1}},
+		{"region", ExamQuery{Region: &beijing}, []uint{4, 2}},
+		{"upcoming", ExamQuery{BeginAfter: now, Limit: 1}, []uint{2}},
+		{"ended", ExamQuery{EndNotAfter: now, OrderByEndDesc: true}, []uint{4, 3}},
+		{"running", ExamQuery{BeginNotAfter: now.AddDate(0, 0, 5), EndAfter: now.AddDate(0, 0, 5)}, []uint{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.Find(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			got := make([]uint, len(result))
+			for i := range result {
+				got[i] = result[i].ID
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Find() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Find() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestExamDateRepository_Find_ContextCanceled(t *testing.T) {
+	db := setupExamDateTestDB(t)
+	repo := NewExamDateRepository(db)
+
+	// 已取消的 context 不应再执行查询
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repo.Find(ctx, ExamQuery{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Find() error = %v, want %v", err, context.Canceled)
+	}
+	if err := repo.SaveAll(ctx, []model.ExamDate{{ExamYear: 2026}}); err == nil {
+		t.Error("SaveAll() should fail with canceled context")
+	}
+}
+
+func TestExamQuery_Apply(t *testing.T) {
+	loc := util.GetBJTLocation()
+	day := func(d int) time.Time { return time.Date(2026, 6, d, 9, 0, 0, 0, loc) }
+
+	exams := []model.ExamDate{
+		{ID: 3, ExamYear: 2026, ExamKind: "gaokao", ExamBeginDate: day(7), ExamEndDate: day(10)},
+		{ID: 1, ExamYear: 2026, ExamKind: "zhongkao", ExamBeginDate: day(20), ExamEndDate: day(22)},
+		{ID: 2, ExamYear: 2026, ExamKind: "gaokao", ExamBeginDate: day(7), ExamEndDate: day(9), Region: "北京"},
+		{ID: 4, ExamYear: 2026, ExamKind: "gaokao", ExamBeginDate: day(1), ExamEndDate: day(2), IsDelete: true},
+	}
+
+	// 默认按开始时间、ID 正序，排除已删除
+	got := ExamQuery{}.Apply(exams)
+	if len(got) != 3 || got[0].ID != 2 || got[1].ID != 3 || got[2].ID != 1 {
+		t.Errorf("Apply() = %+v, want IDs [2 3 1]", got)
+	}
+
+	// 按结束时间倒序并截取
+	got = ExamQuery{Kind: "gaokao", OrderByEndDesc: true, Limit: 1}.Apply(exams)
+	if len(got) != 1 || got[0].ID != 3 {
+		t.Errorf("Apply(OrderByEndDesc) = %+v, want ID 3", got)
+	}
+
+	// 不修改传入的切片
+	if exams[0].ID != 3 || exams[1].ID != 1 {
+		t.Error("Apply() should not reorder input")
+	}
+}
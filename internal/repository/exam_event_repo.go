@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+// GormExamEventRepository 基于 GORM 的考后时间线事件仓储
+type GormExamEventRepository struct {
+	db *gorm.DB
+}
+
+// NewExamEventRepository 创建考后时间线事件仓储
+func NewExamEventRepository(db *gorm.DB) *GormExamEventRepository {
+	return &GormExamEventRepository{db: db}
+}
+
+// GetByExam 获取指定考试（年份 + 类型 + 地区）的全部事件，按时间排序
+func (r *GormExamEventRepository) GetByExam(ctx context.Context, year int, kind, region string) ([]model.ExamEvent, error) {
+	var events []model.ExamEvent
+
+	err := r.db.WithContext(ctx).Where("exam_year = ? AND exam_kind = ? AND region = ? AND is_delete = ?",
+		year, kind, region, false).
+		Order("event_date ASC").
+		Find(&events).Error
+
+	return events, err
+}
+
+// GetByYear 获取指定年份的全部事件，按时间排序
+func (r *GormExamEventRepository) GetByYear(ctx context.Context, year int) ([]model.ExamEvent, error) {
+	var events []model.ExamEvent
+
+	err := r.db.WithContext(ctx).Where("exam_year = ? AND is_delete = ?", year, false).
+		Order("event_date ASC").
+		Find(&events).Error
+
+	return events, err
+}
+
+// GetBetween 获取时间范围 [from, to) 内的事件，按时间排序
+func (r *GormExamEventRepository) GetBetween(ctx context.Context, from, to time.Time) ([]model.ExamEvent, error) {
+	var events []model.ExamEvent
+
+	err := r.db.WithContext(ctx).Where("event_date >= ? AND event_date < ? AND is_delete = ?", from, to, false).
+		Order("event_date ASC").
+		Find(&events).Error
+
+	return events, err
+}
+
+// GetByID 根据ID获取事件
+func (r *GormExamEventRepository) GetByID(ctx context.Context, id uint) (*model.ExamEvent, error) {
+	var event model.ExamEvent
+
+	err := r.db.WithContext(ctx).Where("id = ? AND is_delete = ?", id, false).First(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// Create 创建事件
+func (r *GormExamEventRepository) Create(ctx context.Context, event *model.ExamEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// Delete 标记删除事件
+func (r *GormExamEventRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&model.ExamEvent{}).Where("id = ?", id).Update("is_delete", true).Error
+}
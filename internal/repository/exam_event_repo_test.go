@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupExamEventTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&model.ExamEvent{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+func seedExamEvents(db *gorm.DB) {
+	loc := util.GetBJTLocation()
+	events := []model.ExamEvent{
+		{ID: 1, ExamYear: 2026, ExamKind: "gaokao", EventType: "application_start", EventName: "志愿填报开始", EventDate: time.Date(2026, 6, 27, 9, 0, 0, 0, loc)},
+		{ID: 2, ExamYear: 2026, ExamKind: "gaokao", EventType: "score_release", EventName: "成绩公布", EventDate: time.Date(2026, 6, 25, 12, 0, 0, 0, loc)},
+		{ID: 3, ExamYear: 2026, ExamKind: "gaokao", Region: "北京", EventType: "score_release", EventName: "北京成绩公布", EventDate: time.Date(2026, 6, 25, 10, 0, 0, 0, loc)},
+		{ID: 4, ExamYear: 2025, ExamKind: "gaokao", EventType: "score_release", EventName: "成绩公布", EventDate: time.Date(2025, 6, 25, 12, 0, 0, 0, loc)},
+		{ID: 5, ExamYear: 2026, ExamKind: "gaokao", EventType: "admission_query", EventName: "录取查询", EventDate: time.Date(2026, 7, 10, 9, 0, 0, 0, loc), IsDelete: true},
+	}
+	for i := range events {
+		db.Create(&events[i])
+	}
+}
+
+func TestExamEventRepository_GetByExam(t *testing.T) {
+	db := setupExamEventTestDB(t)
+	repo := NewExamEventRepository(db)
+	seedExamEvents(db)
+
+	events, err := repo.GetByExam(context.Background(), 2026, "gaokao", "")
+	if err != nil {
+		t.Fatalf("GetByExam() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].ID != 2 || events[1].ID != 1 {
+		t.Errorf("Expected events ordered by date [2 1], got [%d %d]", events[0].ID, events[1].ID)
+	}
+}
+
+func TestExamEventRepository_GetByYear(t *testing.T) {
+	db := setupExamEventTestDB(t)
+	repo := NewExamEventRepository(db)
+	seedExamEvents(db)
+
+	events, err := repo.GetByYear(context.Background(), 2026)
+	if err != nil {
+		t.Fatalf("GetByYear() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("Expected 3 events, got %d", len(events))
+	}
+}
+
+func TestExamEventRepository_GetBetween(t *testing.T) {
+	db := setupExamEventTestDB(t)
+	repo := NewExamEventRepository(db)
+	seedExamEvents(db)
+
+	loc := util.GetBJTLocation()
+	events, err := repo.GetBetween(context.Background(), time.Date(2026, 6, 25, 0, 0, 0, 0, loc), time.Date(2026, 6, 26, 0, 0, 0, 0, loc))
+	if err != nil {
+		t.Fatalf("GetBetween() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].ID != 3 {
+		t.Errorf("Expected first event ID 3, got %d", events[0].ID)
+	}
+}
+
+func TestExamEventRepository_CreateGetDelete(t *testing.T) {
+	db := setupExamEventTestDB(t)
+	repo := NewExamEventRepository(db)
+
+	event := &model.ExamEvent{ExamYear: 2026, ExamKind: "gaokao", EventType: "score_release", EventName: "成绩公布", EventDate: time.Now()}
+	if err := repo.Create(context.Background(), event); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	found, err := repo.GetByID(context.Background(), event.ID)
+	if err != nil || found == nil {
+		t.Fatalf("GetByID() = %+v, %v", found, err)
+	}
+
+	if err := repo.Delete(context.Background(), event.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	found, err = repo.GetByID(context.Background(), event.ID)
+	if err != nil || found != nil {
+		t.Errorf("GetByID() after delete = %+v, %v, want nil, nil", found, err)
+	}
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 模板广场排序方式
+const (
+	// GallerySortPopular 按热度（点赞数、复制数）排序
+	GallerySortPopular = "popular"
+	// GallerySortLatest 按发布时间倒序
+	GallerySortLatest = "latest"
+)
+
+// GalleryQuery 模板广场查询条件，零值字段不参与过滤
+type GalleryQuery struct {
+	// Status 审核状态，如 model.GalleryStatusApproved
+	Status string
+	// UserID 发布者用户 ID
+	UserID int64
+	// Keyword 在模板名称和内容中搜索的关键词
+	Keyword string
+	// Sort 排序方式，默认按热度
+	Sort string
+	// Offset 跳过的记录数
+	Offset int
+	// Limit 最多返回的记录数，0 表示不限
+	Limit int
+}
+
+// GormGalleryRepository 基于 GORM 的模板广场仓储
+type GormGalleryRepository struct {
+	db    *gorm.DB
+	reads ReadRouter
+}
+
+// NewGalleryRepository 创建模板广场仓储
+func NewGalleryRepository(db *gorm.DB) *GormGalleryRepository {
+	return &GormGalleryRepository{db: db}
+}
+
+// WithReadReplicas 将列表查询路由到只读副本
+func (r *GormGalleryRepository) WithReadReplicas(router ReadRouter) *GormGalleryRepository {
+	r.reads = router
+	return r
+}
+
+// Find 按条件分页查询条目
+func (r *GormGalleryRepository) Find(ctx context.Context, q GalleryQuery) ([]model.GalleryTemplate, error) {
+	var items []model.GalleryTemplate
+
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		query := applyGalleryFilter(db.Model(&model.GalleryTemplate{}), q)
+		if q.Sort == GallerySortLatest {
+			query = query.Order("created_at DESC")
+		} else {
+			query = query.Order("like_count DESC").Order("copy_count DESC").Order("created_at DESC")
+		}
+		query = query.Order("id DESC")
+		if q.Offset > 0 {
+			query = query.Offset(q.Offset)
+		}
+		if q.Limit > 0 {
+			query = query.Limit(q.Limit)
+		}
+		return query.Find(&items).Error
+	})
+
+	return items, err
+}
+
+// Count 统计满足条件的条目数量，忽略分页和排序
+func (r *GormGalleryRepository) Count(ctx context.Context, q GalleryQuery) (int64, error) {
+	var count int64
+
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		return applyGalleryFilter(db.Model(&model.GalleryTemplate{}), q).Count(&count).Error
+	})
+
+	return count, err
+}
+
+// GetByID 根据ID获取条目
+func (r *GormGalleryRepository) GetByID(ctx context.Context, id int64) (*model.GalleryTemplate, error) {
+	var item model.GalleryTemplate
+
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// GetByTemplateID 根据来源模板ID获取条目
+func (r *GormGalleryRepository) GetByTemplateID(ctx context.Context, templateID int64) (*model.GalleryTemplate, error) {
+	var item model.GalleryTemplate
+
+	err := r.db.WithContext(ctx).Where("template_id = ?", templateID).First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// Create 创建条目
+func (r *GormGalleryRepository) Create(ctx context.Context, item *model.GalleryTemplate) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// UpdateContent 更新条目的名称、内容和审核状态（重新发布），不影响点赞数和复制数
+func (r *GormGalleryRepository) UpdateContent(ctx context.Context, item *model.GalleryTemplate) error {
+	return r.db.WithContext(ctx).Model(&model.GalleryTemplate{ID: item.ID}).Updates(map[string]interface{}{
+		"template_name":    item.TemplateName,
+		"template_content": item.TemplateContent,
+		"status":           item.Status,
+	}).Error
+}
+
+// UpdateStatus 更新条目的审核状态
+func (r *GormGalleryRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	return r.db.WithContext(ctx).Model(&model.GalleryTemplate{ID: id}).Update("status", status).Error
+}
+
+// Delete 删除条目及其点赞记录
+func (r *GormGalleryRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("gallery_id = ?", id).Delete(&model.GalleryLike{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.GalleryTemplate{}, id).Error
+	})
+}
+
+// Like 点赞条目，已点赞时不重复计数
+// 返回是否新增了点赞
+func (r *GormGalleryRepository) Like(ctx context.Context, galleryID, userID int64) (bool, error) {
+	var added bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.GalleryLike{GalleryID: galleryID, UserID: userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		added = true
+		return tx.Model(&model.GalleryTemplate{ID: galleryID}).
+			UpdateColumn("like_count", gorm.Expr("like_count + ?", 1)).Error
+	})
+	return added, err
+}
+
+// Unlike 取消点赞，未点赞时不做处理
+// 返回是否移除了点赞
+func (r *GormGalleryRepository) Unlike(ctx context.Context, galleryID, userID int64) (bool, error) {
+	var removed bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("gallery_id = ? AND user_id = ?", galleryID, userID).Delete(&model.GalleryLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		removed = true
+		return tx.Model(&model.GalleryTemplate{ID: galleryID}).
+			UpdateColumn("like_count", gorm.Expr("like_count - ?", 1)).Error
+	})
+	return removed, err
+}
+
+// GetLikedIDs 返回 ids 中用户已点赞的条目
+func (r *GormGalleryRepository) GetLikedIDs(ctx context.Context, userID int64, ids []int64) (map[int64]bool, error) {
+	liked := make(map[int64]bool)
+	if len(ids) == 0 {
+		return liked, nil
+	}
+
+	var likedIDs []int64
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		return db.Model(&model.GalleryLike{}).
+			Where("user_id = ? AND gallery_id IN ?", userID, ids).
+			Pluck("gallery_id", &likedIDs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range likedIDs {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// IncrementCopyCount 复制数加一
+func (r *GormGalleryRepository) IncrementCopyCount(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Model(&model.GalleryTemplate{ID: id}).
+		UpdateColumn("copy_count", gorm.Expr("copy_count + ?", 1)).Error
+}
+
+// applyGalleryFilter 应用过滤条件
+func applyGalleryFilter(query *gorm.DB, q GalleryQuery) *gorm.DB {
+	if q.Status != "" {
+		query = query.Where("status = ?", q.Status)
+	}
+	if q.UserID != 0 {
+		query = query.Where("user_id = ?", q.UserID)
+	}
+	if keyword := strings.TrimSpace(q.Keyword); keyword != "" {
+		pattern := "%" + escapeLike(keyword) + "%"
+		query = query.Where("(template_name LIKE ? ESCAPE '!' OR template_content LIKE ? ESCAPE '!')", pattern, pattern)
+	}
+	return query
+}
+
+// escapeLike 转义 LIKE 通配符，配合 ESCAPE '!' 使用（三种数据库均支持）
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupGalleryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&model.GalleryTemplate{}, &model.GalleryLike{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+func seedGallery(db *gorm.DB) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	items := []model.GalleryTemplate{
+		{ID: 1, TemplateID: 11, UserID: 100, TemplateName: "简洁", TemplateContent: "{exam}还有{time}", Status: model.GalleryStatusApproved, LikeCount: 5, CreatedAt: base},
+		{ID: 2, TemplateID: 12, UserID: 100, TemplateName: "加油", TemplateContent: "加油！距离{exam}还有{time}", Status: model.GalleryStatusApproved, LikeCount: 9, CreatedAt: base.Add(time.Hour)},
+		{ID: 3, TemplateID: 13, UserID: 200, TemplateName: "100%冲刺", TemplateContent: "冲刺{exam}：{time}", Status: model.GalleryStatusApproved, LikeCount: 5, CopyCount: 3, CreatedAt: base.Add(2 * time.Hour)},
+		{ID: 4, TemplateID: 14, UserID: 200, TemplateName: "待审核", TemplateContent: "{exam}{time}", Status: model.GalleryStatusPending, CreatedAt: base.Add(3 * time.Hour)},
+		{ID: 5, TemplateID: 15, UserID: 300, TemplateName: "已隐藏", TemplateContent: "{exam}{time}", Status: model.GalleryStatusHidden, LikeCount: 99, CreatedAt: base.Add(4 * time.Hour)},
+	}
+	for i := range items {
+		db.Create(&items[i])
+	}
+}
+
+func galleryIDs(items []model.GalleryTemplate) []int64 {
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestGalleryRepository_Find(t *testing.T) {
+	db := setupGalleryTestDB(t)
+	seedGallery(db)
+	repo := NewGalleryRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query GalleryQuery
+		want  []int64
+		total int64
+	}{
+		{"popular", GalleryQuery{Status: model.GalleryStatusApproved}, []int64{2, 3, 1}, 3},
+		{"latest", GalleryQuery{Status: model.GalleryStatusApproved, Sort: GallerySortLatest}, []int64{3, 2, 1}, 3},
+		{"paged", GalleryQuery{Status: model.GalleryStatusApproved, Offset: 1, Limit: 1}, []int64{3}, 3},
+		{"keyword in content", GalleryQuery{Status: model.GalleryStatusApproved, Keyword: "加油"}, []int64{2}, 1},
+		{"keyword with wildcard", GalleryQuery{Keyword: "%"}, []int64{3}, 1},
+		{"by user", GalleryQuery{UserID: 200}, []int64{3, 4}, 2},
+		{"pending", GalleryQuery{Status: model.GalleryStatusPending}, []int64{4}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := repo.Find(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			got := galleryIDs(items)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Find() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Find() = %v, want %v", got, tt.want)
+				}
+			}
+
+			total, err := repo.Count(ctx, tt.query)
+			if err != nil || total != tt.total {
+				t.Errorf("Count() = %d, %v, want %d", total, err, tt.total)
+			}
+		})
+	}
+}
+
+func TestGalleryRepository_LikeAndCopy(t *testing.T) {
+	db := setupGalleryTestDB(t)
+	seedGallery(db)
+	repo := NewGalleryRepository(db)
+	ctx := context.Background()
+
+	if added, err := repo.Like(ctx, 1, 42); err != nil || !added {
+		t.Fatalf("Like() = %v, %v, want true", added, err)
+	}
+	// 重复点赞不计数
+	if added, err := repo.Like(ctx, 1, 42); err != nil || added {
+		t.Fatalf("Like() again = %v, %v, want false", added, err)
+	}
+	item, _ := repo.GetByID(ctx, 1)
+	if item.LikeCount != 6 {
+		t.Errorf("LikeCount = %d, want 6", item.LikeCount)
+	}
+
+	liked, err := repo.GetLikedIDs(ctx, 42, []int64{1, 2})
+	if err != nil || !liked[1] || liked[2] {
+		t.Errorf("GetLikedIDs() = %v, %v", liked, err)
+	}
+
+	if removed, err := repo.Unlike(ctx, 1, 42); err != nil || !removed {
+		t.Fatalf("Unlike() = %v, %v, want true", removed, err)
+	}
+	if removed, err := repo.Unlike(ctx, 1, 42); err != nil || removed {
+		t.Fatalf("Unlike() again = %v, %v, want false", removed, err)
+	}
+	item, _ = repo.GetByID(ctx, 1)
+	if item.LikeCount != 5 {
+		t.Errorf("LikeCount = %d, want 5", item.LikeCount)
+	}
+
+	if err := repo.IncrementCopyCount(ctx, 1); err != nil {
+		t.Fatalf("IncrementCopyCount() error = %v", err)
+	}
+	item, _ = repo.GetByID(ctx, 1)
+	if item.CopyCount != 1 {
+		t.Errorf("CopyCount = %d, want 1", item.CopyCount)
+	}
+}
+
+func TestGalleryRepository_UpdateAndDelete(t *testing.T) {
+	db := setupGalleryTestDB(t)
+	seedGallery(db)
+	repo := NewGalleryRepository(db)
+	ctx := context.Background()
+
+	item, err := repo.GetByTemplateID(ctx, 11)
+	if err != nil || item == nil || item.ID != 1 {
+		t.Fatalf("GetByTemplateID() = %+v, %v", item, err)
+	}
+	if missing, err := repo.GetByTemplateID(ctx, 999); err != nil || missing != nil {
+		t.Errorf("GetByTemplateID() missing = %+v, %v, want nil", missing, err)
+	}
+
+	item.TemplateContent = "新内容{exam}{time}"
+	item.Status = model.GalleryStatusPending
+	if err := repo.UpdateContent(ctx, item); err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
+	}
+	item, _ = repo.GetByID(ctx, 1)
+	if item.TemplateContent != "新内容{exam}{time}" || item.Status != model.GalleryStatusPending || item.LikeCount != 5 {
+		t.Errorf("UpdateContent() result = %+v", item)
+	}
+
+	if err := repo.UpdateStatus(ctx, 1, model.GalleryStatusHidden); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+	item, _ = repo.GetByID(ctx, 1)
+	if item.Status != model.GalleryStatusHidden {
+		t.Errorf("Status = %s, want hidden", item.Status)
+	}
+
+	_, _ = repo.Like(ctx, 1, 42)
+	if err := repo.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if item, _ := repo.GetByID(ctx, 1); item != nil {
+		t.Errorf("GetByID() after delete = %+v, want nil", item)
+	}
+	var likes int64
+	db.Model(&model.GalleryLike{}).Count(&likes)
+	if likes != 0 {
+		t.Errorf("likes after delete = %d, want 0", likes)
+	}
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockUserScope 在事务中锁定某用户在指定表中的记录范围，
+// 保证 CreateWithLimit「先统计后写入」在并发下不会超出数量限制
+//
+//   - PostgreSQL：READ COMMITTED 下行锁无法阻止新行插入，使用事务级 advisory lock
+//   - MySQL：REPEATABLE READ 下 SELECT ... FOR UPDATE 会对 user_id 索引加间隙锁
+//   - SQLite：写事务以 IMMEDIATE 方式开启，天然串行，无需额外加锁
+func lockUserScope(tx *gorm.DB, table string, userID int64) error {
+	switch tx.Dialector.Name() {
+	case "postgres":
+		key := fmt.Sprintf("%s:%d", table, userID)
+		return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error
+	case "mysql":
+		var ids []int64
+		return tx.Table(table).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).
+			Pluck("id", &ids).Error
+	default:
+		return nil
+	}
+}
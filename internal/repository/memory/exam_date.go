@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// ExamDateRepository 内存考试日期仓储
+type ExamDateRepository struct {
+	mu     sync.RWMutex
+	exams  map[uint]model.ExamDate
+	nextID uint
+}
+
+// NewExamDateRepository 创建内存考试日期仓储
+func NewExamDateRepository() *ExamDateRepository {
+	return &ExamDateRepository{
+		exams:  make(map[uint]model.ExamDate),
+		nextID: 1,
+	}
+}
+
+// GetExamsInRange 获取时间范围内的考试，按开始时间排序
+func (r *ExamDateRepository) GetExamsInRange(ctx context.Context, now time.Time) ([]model.ExamDate, error) {
+	return r.Find(ctx, repository.ExamQuery{ActiveAt: now})
+}
+
+// GetExamByYear 按年份获取考试，按开始时间排序
+func (r *ExamDateRepository) GetExamByYear(ctx context.Context, year int) ([]model.ExamDate, error) {
+	return r.Find(ctx, repository.ExamQuery{Year: year})
+}
+
+// GetAll 获取全部考试，按年份和类型排序
+func (r *ExamDateRepository) GetAll(ctx context.Context, includeDeleted bool) ([]model.ExamDate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exams := make([]model.ExamDate, 0, len(r.exams))
+	for _, exam := range r.exams {
+		if exam.IsDelete && !includeDeleted {
+			continue
+		}
+		exams = append(exams, exam)
+	}
+	sort.Slice(exams, func(i, j int) bool {
+		if exams[i].ExamYear != exams[j].ExamYear {
+			return exams[i].ExamYear < exams[j].ExamYear
+		}
+		if exams[i].ExamKind != exams[j].ExamKind {
+			return exams[i].ExamKind < exams[j].ExamKind
+		}
+		return exams[i].ID < exams[j].ID
+	})
+	return exams, nil
+}
+
+// SaveAll 批量保存考试
+// ID 为 0 的记录按（年份、考试类型、地区）更新已有记录，否则分配新 ID，ID 均回写
+func (r *ExamDateRepository) SaveAll(ctx context.Context, exams []model.ExamDate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range exams {
+		exam := &exams[i]
+		if exam.ExamKind == "" {
+			exam.ExamKind = constant.ExamKindGaokao
+		}
+		if exam.ID == 0 {
+			exam.ID = r.nextID
+			for id, existing := range r.exams {
+				if existing.ExamYear == exam.ExamYear && existing.ExamKind == exam.ExamKind && existing.Region == exam.Region {
+					exam.ID = id
+					break
+				}
+			}
+		}
+		if exam.ID >= r.nextID {
+			r.nextID = exam.ID + 1
+		}
+		r.exams[exam.ID] = *exam
+	}
+	return nil
+}
+
+// GetEndingAfter 获取结束时间不早于 since 的考试，按开始时间排序
+func (r *ExamDateRepository) GetEndingAfter(ctx context.Context, since time.Time) ([]model.ExamDate, error) {
+	exams, err := r.Find(ctx, repository.ExamQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := exams[:0]
+	for _, exam := range exams {
+		if !exam.ExamEndDate.Before(since) {
+			result = append(result, exam)
+		}
+	}
+	return result, nil
+}
+
+// Find 按条件查询未删除的考试，语义与 GORM 实现一致
+func (r *ExamDateRepository) Find(ctx context.Context, q repository.ExamQuery) ([]model.ExamDate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exams := make([]model.ExamDate, 0, len(r.exams))
+	for _, exam := range r.exams {
+		exams = append(exams, exam)
+	}
+	return q.Apply(exams), nil
+}
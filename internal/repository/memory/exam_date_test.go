@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newTestExams 构造测试考试，故意乱序以验证排序
+func newTestExams() []model.ExamDate {
+	loc := util.GetBJTLocation()
+	create := func(id uint, kind, region string, begin time.Time, deleted bool) model.ExamDate {
+		return model.ExamDate{
+			ID:                id,
+			ExamYear:          begin.Year(),
+			ExamKind:          kind,
+			Region:            region,
+			ExamDesc:          "考试",
+			ShortDesc:         "考试",
+			ExamBeginDate:     begin,
+			ExamEndDate:       begin.AddDate(0, 0, 3),
+			ExamYearBeginDate: begin.AddDate(-1, 0, 0),
+			ExamYearEndDate:   begin.AddDate(0, 0, 3),
+			IsDelete:          deleted,
+		}
+	}
+	return []model.ExamDate{
+		create(1, "gaokao", "", time.Date(2027, 6, 7, 9, 0, 0, 0, loc), false),
+		create(2, "zhongkao", "北京", time.Date(2026, 6, 24, 9, 0, 0, 0, loc), false),
+		create(3, "gaokao", "", time.Date(2026, 6, 7, 9, 0, 0, 0, loc), false),
+		create(4, "gaokao", "北京", time.Date(2026, 6, 7, 9, 0, 0, 0, loc), false),
+		create(5, "gaokao", "", time.Date(2025, 6, 8, 9, 0, 0, 0, loc), true),
+	}
+}
+
+// TestExamDateRepository_MatchesGorm 相同数据下内存实现与 GORM 实现的查询结果一致
+func TestExamDateRepository_MatchesGorm(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ExamDate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	gormRepo := repository.NewExamDateRepository(db)
+	memRepo := NewExamDateRepository()
+	if err := gormRepo.SaveAll(context.Background(), newTestExams()); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+	if err := memRepo.SaveAll(context.Background(), newTestExams()); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 6, 20, 0, 0, 0, 0, loc)
+	nationwide := ""
+	beijing := "北京"
+
+	queries := map[string]func(r repository.ExamDateRepository) ([]model.ExamDate, error){
+		"in range": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.GetExamsInRange(context.Background(), now)
+		},
+		"by year": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.GetExamByYear(context.Background(), 2026)
+		},
+		"all": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.GetAll(context.Background(), false)
+		},
+		"all deleted": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.GetAll(context.Background(), true)
+		},
+		"ending after": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.GetEndingAfter(context.Background(), now)
+		},
+		"kind": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.Find(context.Background(), repository.ExamQuery{Kind: "gaokao"})
+		},
+		"nationwide": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.Find(context.Background(), repository.ExamQuery{Region: &nationwide})
+		},
+		"region": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.Find(context.Background(), repository.ExamQuery{Region: &beijing})
+		},
+		"upcoming": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.Find(context.Background(), repository.ExamQuery{BeginAfter: now, Limit: 1})
+		},
+		"ended": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			return r.Find(context.Background(), repository.ExamQuery{EndNotAfter: now, OrderByEndDesc: true})
+		},
+		"running": func(r repository.ExamDateRepository) ([]model.ExamDate, error) {
+			at := now.AddDate(0, 0, 5)
+			return r.Find(context.Background(), repository.ExamQuery{BeginNotAfter: at, EndAfter: at})
+		},
+	}
+
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			want, err := query(gormRepo)
+			if err != nil {
+				t.Fatalf("GORM query error = %v", err)
+			}
+			got, err := query(memRepo)
+			if err != nil {
+				t.Fatalf("memory query error = %v", err)
+			}
+			if ids(got) != ids(want) {
+				t.Errorf("memory = %s, GORM = %s", ids(got), ids(want))
+			}
+		})
+	}
+}
+
+func TestExamDateRepository_SaveAllAssignsID(t *testing.T) {
+	repo := NewExamDateRepository()
+	if err := repo.SaveAll(context.Background(), newTestExams()); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+
+	exams := []model.ExamDate{{ExamYear: 2030}}
+	if err := repo.SaveAll(context.Background(), exams); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+	if exams[0].ID != 6 || exams[0].ExamKind != "gaokao" {
+		t.Errorf("Inserted exam = %+v, want ID 6 and default kind", exams[0])
+	}
+}
+
+func TestExamDateRepository_SaveAllUpsertsByNaturalKey(t *testing.T) {
+	repo := NewExamDateRepository()
+	if err := repo.SaveAll(context.Background(), newTestExams()); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+
+	// 同一年份、类型和地区的考试更新已有记录
+	exams := []model.ExamDate{{ExamYear: 2026, ExamKind: "gaokao", Region: "北京", ExamDesc: "更新"}}
+	if err := repo.SaveAll(context.Background(), exams); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+	if exams[0].ID != 4 {
+		t.Errorf("Upserted exam ID = %d, want 4", exams[0].ID)
+	}
+	all, _ := repo.GetAll(context.Background(), true)
+	if len(all) != 5 {
+		t.Errorf("GetAll() = %d exams, want 5", len(all))
+	}
+}
+
+// ids 将考试 ID 格式化为字符串，便于比较
+func ids(exams []model.ExamDate) string {
+	result := make([]uint, len(exams))
+	for i := range exams {
+		result[i] = exams[i].ID
+	}
+	return fmt.Sprint(result)
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// ExamEventRepository 内存考后时间线事件仓储
+type ExamEventRepository struct {
+	mu     sync.RWMutex
+	events map[uint]model.ExamEvent
+	nextID uint
+}
+
+// NewExamEventRepository 创建内存考后时间线事件仓储
+func NewExamEventRepository() *ExamEventRepository {
+	return &ExamEventRepository{
+		events: make(map[uint]model.ExamEvent),
+		nextID: 1,
+	}
+}
+
+// GetByExam 获取指定考试（年份 + 类型 + 地区）的全部事件，按时间排序
+func (r *ExamEventRepository) GetByExam(ctx context.Context, year int, kind, region string) ([]model.ExamEvent, error) {
+	return r.filter(func(e *model.ExamEvent) bool {
+		return e.ExamYear == year && e.ExamKind == kind && e.Region == region
+	}), nil
+}
+
+// GetByYear 获取指定年份的全部事件，按时间排序
+func (r *ExamEventRepository) GetByYear(ctx context.Context, year int) ([]model.ExamEvent, error) {
+	return r.filter(func(e *model.ExamEvent) bool {
+		return e.ExamYear == year
+	}), nil
+}
+
+// GetBetween 获取时间范围 [from, to) 内的事件，按时间排序
+func (r *ExamEventRepository) GetBetween(ctx context.Context, from, to time.Time) ([]model.ExamEvent, error) {
+	return r.filter(func(e *model.ExamEvent) bool {
+		return !e.EventDate.Before(from) && e.EventDate.Before(to)
+	}), nil
+}
+
+// GetByID 根据ID获取事件
+func (r *ExamEventRepository) GetByID(ctx context.Context, id uint) (*model.ExamEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	event, ok := r.events[id]
+	if !ok || event.IsDelete {
+		return nil, nil
+	}
+	return &event, nil
+}
+
+// Create 创建事件，分配新 ID 并回写
+func (r *ExamEventRepository) Create(ctx context.Context, event *model.ExamEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.ID == 0 {
+		event.ID = r.nextID
+	}
+	if event.ExamKind == "" {
+		event.ExamKind = constant.ExamKindGaokao
+	}
+	if event.ID >= r.nextID {
+		r.nextID = event.ID + 1
+	}
+	r.events[event.ID] = *event
+	return nil
+}
+
+// Delete 标记删除事件
+func (r *ExamEventRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event, ok := r.events[id]; ok {
+		event.IsDelete = true
+		r.events[id] = event
+	}
+	return nil
+}
+
+// filter 返回满足条件的未删除事件，按时间排序
+func (r *ExamEventRepository) filter(match func(*model.ExamEvent) bool) []model.ExamEvent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []model.ExamEvent
+	for _, event := range r.events {
+		if !event.IsDelete && match(&event) {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].EventDate.Equal(events[j].EventDate) {
+			return events[i].EventDate.Before(events[j].EventDate)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+func TestExamEventRepository(t *testing.T) {
+	repo := NewExamEventRepository()
+	loc := util.GetBJTLocation()
+
+	events := []*model.ExamEvent{
+		{ExamYear: 2026, EventType: "application_end", EventDate: time.Date(2026, 7, 5, 0, 0, 0, 0, loc)},
+		{ExamYear: 2026, EventType: "score_release", EventDate: time.Date(2026, 6, 25, 12, 0, 0, 0, loc)},
+		{ExamYear: 2026, Region: "北京", EventType: "score_release", EventDate: time.Date(2026, 6, 25, 10, 0, 0, 0, loc)},
+		{ExamYear: 2027, EventType: "score_release", EventDate: time.Date(2027, 6, 25, 12, 0, 0, 0, loc)},
+	}
+	for _, event := range events {
+		if err := repo.Create(context.Background(), event); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if events[0].ID != 1 || events[3].ID != 4 || events[0].ExamKind != "gaokao" {
+		t.Errorf("Create() should assign IDs and default kind, got %+v", events[0])
+	}
+
+	byExam, _ := repo.GetByExam(context.Background(), 2026, "gaokao", "")
+	if len(byExam) != 2 || byExam[0].ID != 2 || byExam[1].ID != 1 {
+		t.Errorf("GetByExam() = %+v, want events [2 1]", byExam)
+	}
+
+	byYear, _ := repo.GetByYear(context.Background(), 2026)
+	if len(byYear) != 3 || byYear[0].ID != 3 {
+		t.Errorf("GetByYear() = %+v, want 3 events starting with 3", byYear)
+	}
+
+	between, _ := repo.GetBetween(context.Background(), time.Date(2026, 6, 25, 0, 0, 0, 0, loc), time.Date(2026, 6, 26, 0, 0, 0, 0, loc))
+	if len(between) != 2 {
+		t.Errorf("GetBetween() = %+v, want 2 events", between)
+	}
+
+	if err := repo.Delete(context.Background(), 2); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if event, _ := repo.GetByID(context.Background(), 2); event != nil {
+		t.Errorf("GetByID() after delete = %+v, want nil", event)
+	}
+	if byExam, _ := repo.GetByExam(context.Background(), 2026, "gaokao", ""); len(byExam) != 1 {
+		t.Errorf("GetByExam() after delete = %+v, want 1 event", byExam)
+	}
+}
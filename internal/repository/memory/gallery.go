@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+// galleryLikeKey 点赞记录的键
+type galleryLikeKey struct {
+	galleryID int64
+	userID    int64
+}
+
+// GalleryRepository 内存模板广场仓储
+type GalleryRepository struct {
+	mu    sync.RWMutex
+	items map[int64]model.GalleryTemplate
+	likes map[galleryLikeKey]struct{}
+}
+
+// NewGalleryRepository 创建内存模板广场仓储
+func NewGalleryRepository() *GalleryRepository {
+	return &GalleryRepository{
+		items: make(map[int64]model.GalleryTemplate),
+		likes: make(map[galleryLikeKey]struct{}),
+	}
+}
+
+// Find 按条件分页查询条目
+func (r *GalleryRepository) Find(ctx context.Context, q repository.GalleryQuery) ([]model.GalleryTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := r.filter(q)
+	sort.Slice(items, func(i, j int) bool {
+		a, b := &items[i], &items[j]
+		if q.Sort != repository.GallerySortLatest {
+			if a.LikeCount != b.LikeCount {
+				return a.LikeCount > b.LikeCount
+			}
+			if a.CopyCount != b.CopyCount {
+				return a.CopyCount > b.CopyCount
+			}
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	if q.Offset >= len(items) {
+		return nil, nil
+	}
+	items = items[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(items) {
+		items = items[:q.Limit]
+	}
+	return items, nil
+}
+
+// Count 统计满足条件的条目数量
+func (r *GalleryRepository) Count(ctx context.Context, q repository.GalleryQuery) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.filter(q))), nil
+}
+
+// GetByID 根据ID获取条目
+func (r *GalleryRepository) GetByID(ctx context.Context, id int64) (*model.GalleryTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, nil
+	}
+	return &item, nil
+}
+
+// GetByTemplateID 根据来源模板ID获取条目
+func (r *GalleryRepository) GetByTemplateID(ctx context.Context, templateID int64) (*model.GalleryTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, item := range r.items {
+		if item.TemplateID == templateID {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+
+// Create 创建条目
+func (r *GalleryRepository) Create(ctx context.Context, item *model.GalleryTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = now
+	}
+	item.UpdatedAt = now
+	if item.Status == "" {
+		item.Status = model.GalleryStatusPending
+	}
+	r.items[item.ID] = *item
+	return nil
+}
+
+// UpdateContent 更新条目的名称、内容和审核状态，不影响点赞数和复制数
+func (r *GalleryRepository) UpdateContent(ctx context.Context, item *model.GalleryTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.items[item.ID]; ok {
+		stored.TemplateName = item.TemplateName
+		stored.TemplateContent = item.TemplateContent
+		stored.Status = item.Status
+		stored.UpdatedAt = time.Now()
+		r.items[item.ID] = stored
+	}
+	return nil
+}
+
+// UpdateStatus 更新条目的审核状态
+func (r *GalleryRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item, ok := r.items[id]; ok {
+		item.Status = status
+		item.UpdatedAt = time.Now()
+		r.items[id] = item
+	}
+	return nil
+}
+
+// Delete 删除条目及其点赞记录
+func (r *GalleryRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.items, id)
+	for key := range r.likes {
+		if key.galleryID == id {
+			delete(r.likes, key)
+		}
+	}
+	return nil
+}
+
+// Like 点赞条目，返回是否新增了点赞
+func (r *GalleryRepository) Like(ctx context.Context, galleryID, userID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := galleryLikeKey{galleryID: galleryID, userID: userID}
+	if _, ok := r.likes[key]; ok {
+		return false, nil
+	}
+	r.likes[key] = struct{}{}
+	r.addLikeCount(galleryID, 1)
+	return true, nil
+}
+
+// Unlike 取消点赞，返回是否移除了点赞
+func (r *GalleryRepository) Unlike(ctx context.Context, galleryID, userID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := galleryLikeKey{galleryID: galleryID, userID: userID}
+	if _, ok := r.likes[key]; !ok {
+		return false, nil
+	}
+	delete(r.likes, key)
+	r.addLikeCount(galleryID, -1)
+	return true, nil
+}
+
+// GetLikedIDs 返回 ids 中用户已点赞的条目
+func (r *GalleryRepository) GetLikedIDs(ctx context.Context, userID int64, ids []int64) (map[int64]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	liked := make(map[int64]bool)
+	for _, id := range ids {
+		if _, ok := r.likes[galleryLikeKey{galleryID: id, userID: userID}]; ok {
+			liked[id] = true
+		}
+	}
+	return liked, nil
+}
+
+// IncrementCopyCount 复制数加一
+func (r *GalleryRepository) IncrementCopyCount(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item, ok := r.items[id]; ok {
+		item.CopyCount++
+		r.items[id] = item
+	}
+	return nil
+}
+
+// addLikeCount 调整点赞数，调用方需持有写锁
+func (r *GalleryRepository) addLikeCount(id int64, delta int64) {
+	if item, ok := r.items[id]; ok {
+		item.LikeCount += delta
+		r.items[id] = item
+	}
+}
+
+// filter 返回满足过滤条件的条目（未排序），调用方需持有读锁
+// 关键词匹配不区分大小写，与 MySQL、SQLite 的默认行为一致
+func (r *GalleryRepository) filter(q repository.GalleryQuery) []model.GalleryTemplate {
+	keyword := strings.ToLower(strings.TrimSpace(q.Keyword))
+
+	var items []model.GalleryTemplate
+	for _, item := range r.items {
+		if q.Status != "" && item.Status != q.Status {
+			continue
+		}
+		if q.UserID != 0 && item.UserID != q.UserID {
+			continue
+		}
+		if keyword != "" &&
+			!strings.Contains(strings.ToLower(item.TemplateName), keyword) &&
+			!strings.Contains(strings.ToLower(item.TemplateContent), keyword) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
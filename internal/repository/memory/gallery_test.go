@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+func TestGalleryRepository(t *testing.T) {
+	repo := NewGalleryRepository()
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	items := []*model.GalleryTemplate{
+		{ID: 1, TemplateID: 11, UserID: 100, TemplateName: "简洁", TemplateContent: "{exam}还有{time}", Status: model.GalleryStatusApproved, CreatedAt: base},
+		{ID: 2, TemplateID: 12, UserID: 100, TemplateName: "Go", TemplateContent: "加油{exam}{time}", Status: model.GalleryStatusApproved, CreatedAt: base.Add(time.Hour)},
+		{ID: 3, TemplateID: 13, UserID: 200, TemplateContent: "{exam}{time}", CreatedAt: base.Add(2 * time.Hour)},
+	}
+	for _, item := range items {
+		if err := repo.Create(ctx, item); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if items[2].Status != model.GalleryStatusPending {
+		t.Errorf("Create() should default to pending, got %s", items[2].Status)
+	}
+
+	if added, _ := repo.Like(ctx, 1, 42); !added {
+		t.Error("Like() should add a like")
+	}
+	if added, _ := repo.Like(ctx, 1, 42); added {
+		t.Error("Like() again should not add a like")
+	}
+	_ = repo.IncrementCopyCount(ctx, 2)
+
+	approved := repository.GalleryQuery{Status: model.GalleryStatusApproved}
+	popular, _ := repo.Find(ctx, approved)
+	if len(popular) != 2 || popular[0].ID != 1 || popular[0].LikeCount != 1 {
+		t.Errorf("Find(popular) = %+v", popular)
+	}
+
+	approved.Sort = repository.GallerySortLatest
+	latest, _ := repo.Find(ctx, approved)
+	if len(latest) != 2 || latest[0].ID != 2 {
+		t.Errorf("Find(latest) = %+v", latest)
+	}
+
+	approved.Offset, approved.Limit = 1, 5
+	if paged, _ := repo.Find(ctx, approved); len(paged) != 1 || paged[0].ID != 1 {
+		t.Errorf("Find(paged) = %+v", paged)
+	}
+	approved.Offset = 5
+	if paged, _ := repo.Find(ctx, approved); len(paged) != 0 {
+		t.Errorf("Find(out of range) = %+v, want empty", paged)
+	}
+
+	if found, _ := repo.Find(ctx, repository.GalleryQuery{Keyword: "go"}); len(found) != 1 || found[0].ID != 2 {
+		t.Errorf("Find(keyword) = %+v", found)
+	}
+	if count, _ := repo.Count(ctx, repository.GalleryQuery{UserID: 100}); count != 2 {
+		t.Errorf("Count(user) = %d, want 2", count)
+	}
+
+	liked, _ := repo.GetLikedIDs(ctx, 42, []int64{1, 2})
+	if !liked[1] || liked[2] {
+		t.Errorf("GetLikedIDs() = %v", liked)
+	}
+
+	if item, _ := repo.GetByTemplateID(ctx, 13); item == nil || item.ID != 3 {
+		t.Errorf("GetByTemplateID() = %+v", item)
+	}
+	_ = repo.UpdateStatus(ctx, 3, model.GalleryStatusHidden)
+	_ = repo.UpdateContent(ctx, &model.GalleryTemplate{ID: 1, TemplateContent: "新{exam}{time}", Status: model.GalleryStatusPending})
+	if item, _ := repo.GetByID(ctx, 1); item.TemplateContent != "新{exam}{time}" || item.LikeCount != 1 || item.Status != model.GalleryStatusPending {
+		t.Errorf("UpdateContent() result = %+v", item)
+	}
+	if item, _ := repo.GetByID(ctx, 3); item.Status != model.GalleryStatusHidden {
+		t.Errorf("UpdateStatus() result = %+v", item)
+	}
+
+	if removed, _ := repo.Unlike(ctx, 1, 42); !removed {
+		t.Error("Unlike() should remove the like")
+	}
+	_, _ = repo.Like(ctx, 1, 43)
+	_ = repo.Delete(ctx, 1)
+	if item, _ := repo.GetByID(ctx, 1); item != nil {
+		t.Errorf("GetByID() after delete = %+v", item)
+	}
+	if liked, _ := repo.GetLikedIDs(ctx, 43, []int64{1}); liked[1] {
+		t.Error("Delete() should remove likes")
+	}
+}
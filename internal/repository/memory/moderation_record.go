@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+// ModerationRecordRepository 内存模板内容审核记录仓储
+type ModerationRecordRepository struct {
+	mu      sync.RWMutex
+	records map[int64]model.ModerationRecord
+}
+
+// NewModerationRecordRepository 创建内存模板内容审核记录仓储
+func NewModerationRecordRepository() *ModerationRecordRepository {
+	return &ModerationRecordRepository{records: make(map[int64]model.ModerationRecord)}
+}
+
+// Find 按状态分页查询记录，按 ID 倒序排列
+func (r *ModerationRecordRepository) Find(ctx context.Context, status string, offset, limit int) ([]model.ModerationRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := r.filter(status)
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID > records[j].ID
+	})
+
+	if offset >= len(records) {
+		return nil, nil
+	}
+	records = records[offset:]
+	if limit > 0 && limit < len(records) {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// Count 统计指定状态的记录数量
+func (r *ModerationRecordRepository) Count(ctx context.Context, status string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.filter(status))), nil
+}
+
+// GetByID 根据ID获取记录
+func (r *ModerationRecordRepository) GetByID(ctx context.Context, id int64) (*model.ModerationRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.records[id]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// Create 创建记录
+func (r *ModerationRecordRepository) Create(ctx context.Context, record *model.ModerationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if record.Status == "" {
+		record.Status = model.ModerationStatusPending
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	r.records[record.ID] = *record
+	return nil
+}
+
+// UpdateStatus 更新记录的状态和复核时间，记录不存在时不做处理
+func (r *ModerationRecordRepository) UpdateStatus(ctx context.Context, id int64, status string, reviewedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[id]
+	if !ok {
+		return nil
+	}
+	record.Status = status
+	record.ReviewedAt = &reviewedAt
+	r.records[id] = record
+	return nil
+}
+
+// filter 返回指定状态的记录，status 为空时返回全部，调用方需持有锁
+func (r *ModerationRecordRepository) filter(status string) []model.ModerationRecord {
+	var records []model.ModerationRecord
+	for _, record := range r.records {
+		if status == "" || record.Status == status {
+			records = append(records, record)
+		}
+	}
+	return records
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestModerationRecordRepository(t *testing.T) {
+	repo := NewModerationRecordRepository()
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		_ = repo.Create(ctx, &model.ModerationRecord{ID: i, UserID: 123, TemplateContent: "内容"})
+	}
+	_ = repo.UpdateStatus(ctx, 2, model.ModerationStatusApproved, time.Now())
+
+	records, _ := repo.Find(ctx, model.ModerationStatusPending, 0, 10)
+	if len(records) != 2 || records[0].ID != 3 || records[1].ID != 1 || records[0].CreatedAt.IsZero() {
+		t.Errorf("Find() = %+v, want records 3, 1", records)
+	}
+	if records, _ := repo.Find(ctx, "", 1, 1); len(records) != 1 || records[0].ID != 2 {
+		t.Errorf("Find() paged = %+v, want record 2", records)
+	}
+	if records, _ := repo.Find(ctx, "", 5, 1); len(records) != 0 {
+		t.Errorf("Find() past end = %+v", records)
+	}
+	if count, _ := repo.Count(ctx, model.ModerationStatusPending); count != 2 {
+		t.Errorf("Count() = %d, want 2", count)
+	}
+
+	if record, _ := repo.GetByID(ctx, 2); record == nil || record.Status != model.ModerationStatusApproved || record.ReviewedAt == nil {
+		t.Errorf("GetByID() = %+v, want approved with reviewed_at", record)
+	}
+	if record, _ := repo.GetByID(ctx, 99); record != nil {
+		t.Errorf("GetByID() missing = %+v, want nil", record)
+	}
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+// OfficialTemplateRepository 内存官方模板仓储
+type OfficialTemplateRepository struct {
+	mu        sync.RWMutex
+	templates map[int64]model.OfficialTemplate
+}
+
+// NewOfficialTemplateRepository 创建内存官方模板仓储
+func NewOfficialTemplateRepository() *OfficialTemplateRepository {
+	return &OfficialTemplateRepository{templates: make(map[int64]model.OfficialTemplate)}
+}
+
+// GetAll 获取全部官方模板，按排序位置和 ID 排列
+func (r *OfficialTemplateRepository) GetAll(ctx context.Context) ([]model.OfficialTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]model.OfficialTemplate, 0, len(r.templates))
+	for _, template := range r.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].SortOrder != templates[j].SortOrder {
+			return templates[i].SortOrder < templates[j].SortOrder
+		}
+		return templates[i].ID < templates[j].ID
+	})
+	return templates, nil
+}
+
+// GetByID 根据ID获取官方模板
+func (r *OfficialTemplateRepository) GetByID(ctx context.Context, id int64) (*model.OfficialTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, ok := r.templates[id]
+	if !ok {
+		return nil, nil
+	}
+	return &template, nil
+}
+
+// Create 创建官方模板
+func (r *OfficialTemplateRepository) Create(ctx context.Context, template *model.OfficialTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	r.templates[template.ID] = *template
+	return nil
+}
+
+// Update 更新官方模板，模板不存在时不做处理
+func (r *OfficialTemplateRepository) Update(ctx context.Context, template *model.OfficialTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.templates[template.ID]; !ok {
+		return nil
+	}
+	template.UpdatedAt = time.Now()
+	r.templates[template.ID] = *template
+	return nil
+}
+
+// Delete 删除官方模板
+func (r *OfficialTemplateRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.templates, id)
+	return nil
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestOfficialTemplateRepository(t *testing.T) {
+	repo := NewOfficialTemplateRepository()
+	ctx := context.Background()
+
+	_ = repo.Create(ctx, &model.OfficialTemplate{ID: 1, TemplateContent: "常规", SortOrder: 2})
+	_ = repo.Create(ctx, &model.OfficialTemplate{ID: 2, TemplateContent: "冲刺", SortOrder: 1})
+	_ = repo.Create(ctx, &model.OfficialTemplate{ID: 3, TemplateContent: "寒假", SortOrder: 2})
+
+	templates, _ := repo.GetAll(ctx)
+	if len(templates) != 3 || templates[0].ID != 2 || templates[1].ID != 1 || templates[2].ID != 3 || templates[0].CreatedAt.IsZero() {
+		t.Errorf("GetAll() = %+v, want templates 2, 1, 3", templates)
+	}
+
+	_ = repo.Update(ctx, &model.OfficialTemplate{ID: 1, TemplateContent: "常规（新）", Active: true})
+	if template, _ := repo.GetByID(ctx, 1); template == nil || template.TemplateContent != "常规（新）" || !template.Active {
+		t.Errorf("GetByID(1) after update = %+v", template)
+	}
+	// 更新不存在的模板不应创建
+	_ = repo.Update(ctx, &model.OfficialTemplate{ID: 9})
+	if template, _ := repo.GetByID(ctx, 9); template != nil {
+		t.Errorf("Update() created missing template: %+v", template)
+	}
+
+	_ = repo.Delete(ctx, 2)
+	if templates, _ := repo.GetAll(ctx); len(templates) != 2 {
+		t.Errorf("GetAll() after delete = %+v", templates)
+	}
+}
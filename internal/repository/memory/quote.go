@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+// QuoteRepository 内存励志语录仓储
+type QuoteRepository struct {
+	mu     sync.RWMutex
+	quotes map[int64]model.Quote
+}
+
+// NewQuoteRepository 创建内存励志语录仓储
+func NewQuoteRepository() *QuoteRepository {
+	return &QuoteRepository{quotes: make(map[int64]model.Quote)}
+}
+
+// GetAll 获取全部语录，按 ID 排列
+func (r *QuoteRepository) GetAll(ctx context.Context) ([]model.Quote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	quotes := make([]model.Quote, 0, len(r.quotes))
+	for _, quote := range r.quotes {
+		quotes = append(quotes, quote)
+	}
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].ID < quotes[j].ID
+	})
+	return quotes, nil
+}
+
+// GetByID 根据ID获取语录
+func (r *QuoteRepository) GetByID(ctx context.Context, id int64) (*model.Quote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	quote, ok := r.quotes[id]
+	if !ok {
+		return nil, nil
+	}
+	return &quote, nil
+}
+
+// Create 创建语录
+func (r *QuoteRepository) Create(ctx context.Context, quote *model.Quote) error {
+	return r.CreateBatch(ctx, []*model.Quote{quote})
+}
+
+// CreateBatch 创建多条语录，ID 已存在时全部不创建
+func (r *QuoteRepository) CreateBatch(ctx context.Context, quotes []*model.Quote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, quote := range quotes {
+		if _, ok := r.quotes[quote.ID]; ok {
+			return fmt.Errorf("quote %d already exists", quote.ID)
+		}
+	}
+
+	now := time.Now()
+	for _, quote := range quotes {
+		quote.CreatedAt = now
+		quote.UpdatedAt = now
+		r.quotes[quote.ID] = *quote
+	}
+	return nil
+}
+
+// Update 更新语录，语录不存在时不做处理
+func (r *QuoteRepository) Update(ctx context.Context, quote *model.Quote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.quotes[quote.ID]; !ok {
+		return nil
+	}
+	quote.UpdatedAt = time.Now()
+	r.quotes[quote.ID] = *quote
+	return nil
+}
+
+// Delete 删除语录
+func (r *QuoteRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.quotes, id)
+	return nil
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestQuoteRepository(t *testing.T) {
+	repo := NewQuoteRepository()
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &model.Quote{ID: 2, Content: "天道酬勤"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.CreateBatch(ctx, []*model.Quote{{ID: 1, Content: "不积跬步，无以至千里"}}); err != nil {
+		t.Fatalf("CreateBatch() error = %v", err)
+	}
+
+	// ID 已存在时全部不创建
+	if err := repo.CreateBatch(ctx, []*model.Quote{{ID: 3, Content: "新语录"}, {ID: 2, Content: "重复ID"}}); err == nil {
+		t.Error("CreateBatch() with duplicate ID should fail")
+	}
+
+	all, _ := repo.GetAll(ctx)
+	if len(all) != 2 || all[0].ID != 1 || all[1].ID != 2 {
+		t.Errorf("GetAll() = %+v, want quotes 1, 2", all)
+	}
+
+	quote, _ := repo.GetByID(ctx, 2)
+	quote.Source = "谚语"
+	_ = repo.Update(ctx, quote)
+	if quote, _ := repo.GetByID(ctx, 2); quote == nil || quote.Source != "谚语" {
+		t.Errorf("GetByID(2) after update = %+v", quote)
+	}
+
+	_ = repo.Delete(ctx, 2)
+	if quote, _ := repo.GetByID(ctx, 2); quote != nil {
+		t.Errorf("GetByID(2) after delete = %+v, want nil", quote)
+	}
+}
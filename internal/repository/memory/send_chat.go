@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+// SendChatRepository 内存发送对话仓储
+type SendChatRepository struct {
+	mu     sync.RWMutex
+	chats  map[int64]model.SendChat
+	pools  map[int64][]model.SendChatTemplate
+	nextID int64
+}
+
+// NewSendChatRepository 创建内存发送对话仓储
+func NewSendChatRepository() *SendChatRepository {
+	return &SendChatRepository{
+		chats:  make(map[int64]model.SendChat),
+		pools:  make(map[int64][]model.SendChatTemplate),
+		nextID: 1,
+	}
+}
+
+// GetAll 获取所有发送对话，按 ID 排序
+func (r *SendChatRepository) GetAll(ctx context.Context) ([]model.SendChat, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chats := make([]model.SendChat, 0, len(r.chats))
+	for _, chat := range r.chats {
+		chats = append(chats, chat)
+	}
+	sort.Slice(chats, func(i, j int) bool {
+		return chats[i].ID < chats[j].ID
+	})
+	return chats, nil
+}
+
+// Create 创建发送对话，ID 为 0 时分配新 ID 并回写
+func (r *SendChatRepository) Create(ctx context.Context, chat *model.SendChat) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if chat.ID == 0 {
+		chat.ID = r.nextID
+	}
+	if chat.ID >= r.nextID {
+		r.nextID = chat.ID + 1
+	}
+	r.chats[chat.ID] = *chat
+	return nil
+}
+
+// Delete 删除发送对话及其模板池
+func (r *SendChatRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.chats, id)
+	delete(r.pools, id)
+	return nil
+}
+
+// GetByID 根据ID获取发送对话
+func (r *SendChatRepository) GetByID(ctx context.Context, id int64) (*model.SendChat, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chat, ok := r.chats[id]
+	if !ok {
+		return nil, nil
+	}
+	return &chat, nil
+}
+
+// UpdateTemplate 更新发送对话选用的官方模板，对话不存在时不做处理
+func (r *SendChatRepository) UpdateTemplate(ctx context.Context, id, templateID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chat, ok := r.chats[id]
+	if !ok {
+		return nil
+	}
+	chat.TemplateID = templateID
+	r.chats[id] = chat
+	return nil
+}
+
+// GetTemplatePool 获取发送对话的模板池，按排序位置排列
+func (r *SendChatRepository) GetTemplatePool(ctx context.Context, id int64) ([]model.SendChatTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]model.SendChatTemplate(nil), r.pools[id]...), nil
+}
+
+// GetAllTemplatePools 获取全部发送对话的模板池，按对话和排序位置排列
+func (r *SendChatRepository) GetAllTemplatePools(ctx context.Context) ([]model.SendChatTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var pool []model.SendChatTemplate
+	for _, entries := range r.pools {
+		pool = append(pool, entries...)
+	}
+	sort.SliceStable(pool, func(i, j int) bool {
+		return pool[i].SendChatID < pool[j].SendChatID
+	})
+	return pool, nil
+}
+
+// UpdateRotation 替换模板池并更新管理员和轮换策略，同时清空上次使用的条目；对话不存在时不做处理
+func (r *SendChatRepository) UpdateRotation(ctx context.Context, id, adminUserID int64, strategy string, pool []*model.SendChatTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chat, ok := r.chats[id]
+	if !ok {
+		return nil
+	}
+
+	entries := make([]model.SendChatTemplate, 0, len(pool))
+	for _, entry := range pool {
+		entries = append(entries, *entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].SortOrder < entries[j].SortOrder
+	})
+	r.pools[id] = entries
+
+	chat.AdminUserID = adminUserID
+	chat.RotationStrategy = strategy
+	chat.LastEntryID = 0
+	chat.LastRotatedAt = nil
+	r.chats[id] = chat
+	return nil
+}
+
+// UpdateLastEntry 记录本次轮换使用的模板池条目和时间，对话不存在时不做处理
+func (r *SendChatRepository) UpdateLastEntry(ctx context.Context, id, entryID int64, rotatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chat, ok := r.chats[id]
+	if !ok {
+		return nil
+	}
+	chat.LastEntryID = entryID
+	chat.LastRotatedAt = &rotatedAt
+	r.chats[id] = chat
+	return nil
+}
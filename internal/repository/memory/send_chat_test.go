@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestSendChatRepository(t *testing.T) {
+	repo := NewSendChatRepository()
+
+	first := &model.SendChat{ChatID: "-100"}
+	second := &model.SendChat{ChatID: "-200"}
+	_ = repo.Create(context.Background(), second)
+	_ = repo.Create(context.Background(), first)
+	if second.ID != 1 || first.ID != 2 {
+		t.Errorf("Create() IDs = %d, %d, want 1, 2", second.ID, first.ID)
+	}
+
+	chats, _ := repo.GetAll(context.Background())
+	if len(chats) != 2 || chats[0].ChatID != "-200" {
+		t.Errorf("GetAll() = %+v", chats)
+	}
+
+	_ = repo.Delete(context.Background(), 1)
+	if chats, _ := repo.GetAll(context.Background()); len(chats) != 1 || chats[0].ChatID != "-100" {
+		t.Errorf("GetAll() after delete = %+v", chats)
+	}
+}
+
+func TestSendChatRepository_UpdateTemplate(t *testing.T) {
+	repo := NewSendChatRepository()
+	chat := &model.SendChat{ChatID: "-100"}
+	_ = repo.Create(context.Background(), chat)
+
+	_ = repo.UpdateTemplate(context.Background(), chat.ID, 42)
+	if got, _ := repo.GetByID(context.Background(), chat.ID); got == nil || got.TemplateID != 42 {
+		t.Errorf("GetByID() after UpdateTemplate = %+v", got)
+	}
+	// 对话不存在时不做处理
+	_ = repo.UpdateTemplate(context.Background(), 99, 42)
+	if got, _ := repo.GetByID(context.Background(), 99); got != nil {
+		t.Errorf("GetByID(99) = %+v, want nil", got)
+	}
+}
+
+func TestSendChatRepository_Rotation(t *testing.T) {
+	repo := NewSendChatRepository()
+	ctx := context.Background()
+	chat := &model.SendChat{ChatID: "-100"}
+	_ = repo.Create(ctx, chat)
+
+	_ = repo.UpdateRotation(ctx, chat.ID, 123, model.RotationWeekday, []*model.SendChatTemplate{
+		{ID: 2, SendChatID: chat.ID, Source: model.ChatTemplateSourceUser, TemplateID: 5, SortOrder: 1},
+		{ID: 1, SendChatID: chat.ID, Source: model.ChatTemplateSourceOfficial, TemplateID: 1, SortOrder: 0},
+	})
+	if pool, _ := repo.GetTemplatePool(ctx, chat.ID); len(pool) != 2 || pool[0].ID != 1 {
+		t.Errorf("GetTemplatePool() = %+v", pool)
+	}
+
+	_ = repo.UpdateLastEntry(ctx, chat.ID, 1, time.Now())
+	if got, _ := repo.GetByID(ctx, chat.ID); got.AdminUserID != 123 || got.RotationStrategy != model.RotationWeekday || got.LastEntryID != 1 || got.LastRotatedAt == nil {
+		t.Errorf("GetByID() after UpdateLastEntry = %+v", got)
+	}
+
+	// 删除对话时一并删除模板池
+	_ = repo.Delete(ctx, chat.ID)
+	if pool, _ := repo.GetAllTemplatePools(ctx); len(pool) != 0 {
+		t.Errorf("GetAllTemplatePools() after delete = %+v", pool)
+	}
+}
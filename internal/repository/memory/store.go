@@ -0,0 +1,99 @@
+// Package memory 提供仓储接口的内存实现，用于演示（-storage=memory）和快速测试
+// 数据仅保存在进程内，重启后丢失；内存操作不会阻塞，各方法忽略 ctx
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// 编译期检查内存实现满足仓储接口
+var (
+	_ repository.ExamDateRepository         = (*ExamDateRepository)(nil)
+	_ repository.ExamEventRepository        = (*ExamEventRepository)(nil)
+	_ repository.GalleryRepository          = (*GalleryRepository)(nil)
+	_ repository.ModerationRecordRepository = (*ModerationRecordRepository)(nil)
+	_ repository.OfficialTemplateRepository = (*OfficialTemplateRepository)(nil)
+	_ repository.QuoteRepository            = (*QuoteRepository)(nil)
+	_ repository.SendChatRepository         = (*SendChatRepository)(nil)
+	_ repository.TemplateRevisionRepository = (*TemplateRevisionRepository)(nil)
+	_ repository.UserTargetRepository       = (*UserTargetRepository)(nil)
+	_ repository.UserTemplateRepository     = (*UserTemplateRepository)(nil)
+	_ repository.UserVariableRepository     = (*UserVariableRepository)(nil)
+)
+
+// DefaultTemplateContent 初始数据中默认模板的内容
+const DefaultTemplateContent = "现在距离{exam}还有{time}"
+
+// Store 内存仓储集合
+type Store struct {
+	ExamDates         *ExamDateRepository
+	ExamEvents        *ExamEventRepository
+	Gallery           *GalleryRepository
+	ModerationRecords *ModerationRecordRepository
+	OfficialTemplates *OfficialTemplateRepository
+	Quotes            *QuoteRepository
+	SendChats         *SendChatRepository
+	TemplateRevisions *TemplateRevisionRepository
+	UserTargets       *UserTargetRepository
+	UserTemplates     *UserTemplateRepository
+	UserVariables     *UserVariableRepository
+}
+
+// NewStore 创建空的内存仓储集合
+func NewStore() *Store {
+	return &Store{
+		ExamDates:         NewExamDateRepository(),
+		ExamEvents:        NewExamEventRepository(),
+		Gallery:           NewGalleryRepository(),
+		ModerationRecords: NewModerationRecordRepository(),
+		OfficialTemplates: NewOfficialTemplateRepository(),
+		Quotes:            NewQuoteRepository(),
+		SendChats:         NewSendChatRepository(),
+		TemplateRevisions: NewTemplateRevisionRepository(),
+		UserTargets:       NewUserTargetRepository(),
+		UserTemplates:     NewUserTemplateRepository(),
+		UserVariables:     NewUserVariableRepository(),
+	}
+}
+
+// NewSeededStore 创建带初始数据的内存仓储集合：默认模板和 MinExamYear-MaxExamYear 年的高考
+func NewSeededStore() *Store {
+	s := NewStore()
+	s.Seed()
+	return s
+}
+
+// Seed 写入初始数据
+// 高考统一按 6 月 7 日 9:00 至 6 月 10 日 17:00 生成，考试年从上一年考试结束开始，
+// 不包含数据库初始数据中的历史特例（如 2020 年延期）
+func (s *Store) Seed() {
+	_ = s.UserTemplates.Create(context.Background(), &model.UserTemplate{
+		ID:              1,
+		UserID:          0,
+		TemplateContent: DefaultTemplateContent,
+	})
+
+	loc := util.GetBJTLocation()
+	exams := make([]model.ExamDate, 0, constant.MaxExamYear-constant.MinExamYear+1)
+	for year := constant.MinExamYear; year <= constant.MaxExamYear; year++ {
+		end := time.Date(year, 6, 10, 17, 0, 0, 0, loc)
+		exams = append(exams, model.ExamDate{
+			ExamYear:          year,
+			ExamKind:          constant.ExamKindGaokao,
+			ExamDesc:          fmt.Sprintf("%d年普通高等学校招生全国统一考试", year),
+			ShortDesc:         fmt.Sprintf("%d年高考", year),
+			ExamBeginDate:     time.Date(year, 6, 7, 9, 0, 0, 0, loc),
+			ExamEndDate:       end,
+			ExamYearBeginDate: end.AddDate(-1, 0, 0),
+			ExamYearEndDate:   end,
+		})
+	}
+	_ = s.ExamDates.SaveAll(context.Background(), exams)
+}
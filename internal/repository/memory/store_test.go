@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+func TestNewSeededStore(t *testing.T) {
+	store := NewSeededStore()
+
+	template, err := store.UserTemplates.GetDefaultTemplate(context.Background())
+	if err != nil || template == nil || template.TemplateContent != DefaultTemplateContent {
+		t.Errorf("GetDefaultTemplate() = %+v, %v", template, err)
+	}
+
+	all, _ := store.ExamDates.GetAll(context.Background(), false)
+	if want := constant.MaxExamYear - constant.MinExamYear + 1; len(all) != want {
+		t.Errorf("seeded %d exams, want %d", len(all), want)
+	}
+
+	// 任意时刻都恰好落在一个考试年内
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, loc)
+	exams, _ := store.ExamDates.GetExamsInRange(context.Background(), now)
+	if len(exams) != 1 || exams[0].ExamYear != 2026 || exams[0].ShortDesc != "2026年高考" {
+		t.Errorf("GetExamsInRange() = %+v", exams)
+	}
+
+	if chats, _ := store.SendChats.GetAll(context.Background()); len(chats) != 0 {
+		t.Errorf("seeded send chats = %+v, want none", chats)
+	}
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+// TemplateRevisionRepository 内存模板修订记录仓储
+type TemplateRevisionRepository struct {
+	mu        sync.RWMutex
+	revisions map[int64]model.TemplateRevision
+}
+
+// NewTemplateRevisionRepository 创建内存模板修订记录仓储
+func NewTemplateRevisionRepository() *TemplateRevisionRepository {
+	return &TemplateRevisionRepository{revisions: make(map[int64]model.TemplateRevision)}
+}
+
+// GetByTemplateID 获取模板的修订记录，最新的在前
+func (r *TemplateRevisionRepository) GetByTemplateID(ctx context.Context, templateID int64) ([]model.TemplateRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.byTemplateID(templateID), nil
+}
+
+// GetByID 根据ID获取修订记录
+func (r *TemplateRevisionRepository) GetByID(ctx context.Context, id int64) (*model.TemplateRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revision, ok := r.revisions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &revision, nil
+}
+
+// Create 创建修订记录，并删除超出 maxPerTemplate 条的最早记录
+func (r *TemplateRevisionRepository) Create(ctx context.Context, revision *model.TemplateRevision, maxPerTemplate int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+	r.revisions[revision.ID] = *revision
+
+	revisions := r.byTemplateID(revision.TemplateID)
+	for i := maxPerTemplate; i < len(revisions); i++ {
+		delete(r.revisions, revisions[i].ID)
+	}
+	return nil
+}
+
+// DeleteByTemplateID 删除模板的全部修订记录
+func (r *TemplateRevisionRepository) DeleteByTemplateID(ctx context.Context, templateID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, revision := range r.revisions {
+		if revision.TemplateID == templateID {
+			delete(r.revisions, id)
+		}
+	}
+	return nil
+}
+
+// byTemplateID 返回模板的修订记录，按 ID 倒序排列，调用方需持有锁
+func (r *TemplateRevisionRepository) byTemplateID(templateID int64) []model.TemplateRevision {
+	var revisions []model.TemplateRevision
+	for _, revision := range r.revisions {
+		if revision.TemplateID == templateID {
+			revisions = append(revisions, revision)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].ID > revisions[j].ID
+	})
+	return revisions
+}
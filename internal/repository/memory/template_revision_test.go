@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestTemplateRevisionRepository(t *testing.T) {
+	repo := NewTemplateRevisionRepository()
+	ctx := context.Background()
+
+	for i := int64(1); i <= 4; i++ {
+		_ = repo.Create(ctx, &model.TemplateRevision{ID: i, TemplateID: 10, TemplateContent: "内容"}, 3)
+	}
+	_ = repo.Create(ctx, &model.TemplateRevision{ID: 5, TemplateID: 20, TemplateContent: "内容"}, 3)
+
+	revisions, _ := repo.GetByTemplateID(ctx, 10)
+	if len(revisions) != 3 || revisions[0].ID != 4 || revisions[2].ID != 2 || revisions[0].CreatedAt.IsZero() {
+		t.Errorf("GetByTemplateID() = %+v, want revisions 4, 3, 2", revisions)
+	}
+	if revision, _ := repo.GetByID(ctx, 1); revision != nil {
+		t.Errorf("GetByID() pruned = %+v, want nil", revision)
+	}
+
+	_ = repo.DeleteByTemplateID(ctx, 10)
+	if revisions, _ := repo.GetByTemplateID(ctx, 10); len(revisions) != 0 {
+		t.Errorf("GetByTemplateID() after delete = %+v", revisions)
+	}
+	if revision, _ := repo.GetByID(ctx, 5); revision == nil {
+		t.Error("DeleteByTemplateID() should not affect other templates")
+	}
+}
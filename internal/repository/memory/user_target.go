@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+// UserTargetRepository 内存用户自定义目标仓储
+type UserTargetRepository struct {
+	mu      sync.RWMutex
+	targets map[int64]model.UserTarget
+}
+
+// NewUserTargetRepository 创建内存用户自定义目标仓储
+func NewUserTargetRepository() *UserTargetRepository {
+	return &UserTargetRepository{
+		targets: make(map[int64]model.UserTarget),
+	}
+}
+
+// GetByUserID 根据用户ID获取目标列表，按目标时间排序
+func (r *UserTargetRepository) GetByUserID(ctx context.Context, userID int64) ([]model.UserTarget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var targets []model.UserTarget
+	for _, target := range r.targets {
+		if target.UserID == userID {
+			targets = append(targets, target)
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if !targets[i].TargetDate.Equal(targets[j].TargetDate) {
+			return targets[i].TargetDate.Before(targets[j].TargetDate)
+		}
+		return targets[i].ID < targets[j].ID
+	})
+	return targets, nil
+}
+
+// GetByID 根据ID获取目标
+func (r *UserTargetRepository) GetByID(ctx context.Context, id int64) (*model.UserTarget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	target, ok := r.targets[id]
+	if !ok {
+		return nil, nil
+	}
+	return &target, nil
+}
+
+// Delete 删除目标
+func (r *UserTargetRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.targets, id)
+	return nil
+}
+
+// CreateWithLimit 在锁内原子地检查数量限制并创建目标
+func (r *UserTargetRepository) CreateWithLimit(ctx context.Context, target *model.UserTarget, maxLimit int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, t := range r.targets {
+		if t.UserID == target.UserID {
+			count++
+		}
+	}
+	if count >= maxLimit {
+		return repository.ErrTargetLimitExceeded
+	}
+
+	now := time.Now()
+	target.CreatedAt = now
+	target.UpdatedAt = now
+	r.targets[target.ID] = *target
+	return nil
+}
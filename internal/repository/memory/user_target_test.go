@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+func TestUserTargetRepository(t *testing.T) {
+	repo := NewUserTargetRepository()
+	now := time.Now()
+
+	for i, days := range []int{10, 5} {
+		target := &model.UserTarget{ID: int64(i + 1), UserID: 1, TargetName: "目标", TargetDate: now.AddDate(0, 0, days)}
+		if err := repo.CreateWithLimit(context.Background(), target, 2); err != nil {
+			t.Fatalf("CreateWithLimit() error = %v", err)
+		}
+		if target.CreatedAt.IsZero() {
+			t.Error("CreateWithLimit() should set CreatedAt")
+		}
+	}
+
+	err := repo.CreateWithLimit(context.Background(), &model.UserTarget{ID: 3, UserID: 1, TargetDate: now}, 2)
+	if !errors.Is(err, repository.ErrTargetLimitExceeded) {
+		t.Errorf("CreateWithLimit() error = %v, want ErrTargetLimitExceeded", err)
+	}
+
+	targets, _ := repo.GetByUserID(context.Background(), 1)
+	if len(targets) != 2 || targets[0].ID != 2 {
+		t.Errorf("GetByUserID() = %+v, want ordered by target date", targets)
+	}
+
+	_ = repo.Delete(context.Background(), 2)
+	if target, _ := repo.GetByID(context.Background(), 2); target != nil {
+		t.Errorf("GetByID() after delete = %+v, want nil", target)
+	}
+	if target, _ := repo.GetByID(context.Background(), 1); target == nil {
+		t.Error("GetByID(1) = nil, want target")
+	}
+}
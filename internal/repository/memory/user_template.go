@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+// UserTemplateRepository 内存用户模板仓储
+type UserTemplateRepository struct {
+	mu        sync.RWMutex
+	templates map[int64]model.UserTemplate
+}
+
+// NewUserTemplateRepository 创建内存用户模板仓储
+func NewUserTemplateRepository() *UserTemplateRepository {
+	return &UserTemplateRepository{
+		templates: make(map[int64]model.UserTemplate),
+	}
+}
+
+// GetByUserID 根据用户ID获取模板列表，按收藏优先、排序位置、ID 排序
+func (r *UserTemplateRepository) GetByUserID(ctx context.Context, userID int64) ([]model.UserTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.byUserID(userID), nil
+}
+
+// GetDefaultTemplate 获取默认模板（user_id 为 0 且 ID 最小的模板）
+func (r *UserTemplateRepository) GetDefaultTemplate(ctx context.Context) (*model.UserTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := r.byUserID(0)
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	return &templates[0], nil
+}
+
+// Create 创建模板
+func (r *UserTemplateRepository) Create(ctx context.Context, template *model.UserTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.insert(template)
+	return nil
+}
+
+// Update 更新模板
+func (r *UserTemplateRepository) Update(ctx context.Context, template *model.UserTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	template.UpdatedAt = time.Now()
+	r.templates[template.ID] = *template
+	return nil
+}
+
+// Delete 删除模板
+func (r *UserTemplateRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.templates, id)
+	return nil
+}
+
+// GetByID 根据ID获取模板
+func (r *UserTemplateRepository) GetByID(ctx context.Context, id int64) (*model.UserTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, ok := r.templates[id]
+	if !ok {
+		return nil, nil
+	}
+	return &template, nil
+}
+
+// GetByShareCode 根据分享码获取模板
+func (r *UserTemplateRepository) GetByShareCode(ctx context.Context, code string) (*model.UserTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, template := range r.templates {
+		if template.ShareCode != nil && *template.ShareCode == code {
+			return &template, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdateShareCode 仅更新模板的分享码
+func (r *UserTemplateRepository) UpdateShareCode(ctx context.Context, id int64, code *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	template, ok := r.templates[id]
+	if !ok {
+		return nil
+	}
+	template.ShareCode = code
+	template.UpdatedAt = time.Now()
+	r.templates[id] = template
+	return nil
+}
+
+// UpdateSortOrders 按 ids 的顺序设置排序位置，仅更新属于该用户的模板
+func (r *UserTemplateRepository) UpdateSortOrders(ctx context.Context, userID int64, ids []int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, id := range ids {
+		if template, ok := r.templates[id]; ok && template.UserID == userID {
+			template.SortOrder = i + 1
+			r.templates[id] = template
+		}
+	}
+	return nil
+}
+
+// UpdateFavorite 仅更新模板的收藏状态
+func (r *UserTemplateRepository) UpdateFavorite(ctx context.Context, id int64, favorite bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if template, ok := r.templates[id]; ok {
+		template.IsFavorite = favorite
+		r.templates[id] = template
+	}
+	return nil
+}
+
+// SetUserDefault 设置用户的个人默认模板，id 为 0 时仅取消
+func (r *UserTemplateRepository) SetUserDefault(ctx context.Context, userID, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for templateID, template := range r.templates {
+		if template.UserID != userID {
+			continue
+		}
+		isDefault := templateID == id
+		if template.IsDefault != isDefault {
+			template.IsDefault = isDefault
+			r.templates[templateID] = template
+		}
+	}
+	return nil
+}
+
+// CountByUserID 统计用户的模板数量
+func (r *UserTemplateRepository) CountByUserID(ctx context.Context, userID int64) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.byUserID(userID))), nil
+}
+
+// CreateWithLimit 在锁内原子地检查数量限制并创建模板
+func (r *UserTemplateRepository) CreateWithLimit(ctx context.Context, template *model.UserTemplate, maxLimit int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if int64(len(r.byUserID(template.UserID))) >= maxLimit {
+		return repository.ErrTemplateLimitExceeded
+	}
+
+	r.insert(template)
+	return nil
+}
+
+// CreateBatchWithLimit 在锁内原子地检查数量限制并批量创建用户的模板
+func (r *UserTemplateRepository) CreateBatchWithLimit(ctx context.Context, userID int64, templates []*model.UserTemplate, maxLimit int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if int64(len(r.byUserID(userID))+len(templates)) > maxLimit {
+		return repository.ErrTemplateLimitExceeded
+	}
+
+	for _, template := range templates {
+		r.insert(template)
+	}
+	return nil
+}
+
+// insert 写入模板并设置创建、更新时间，调用方需持有写锁
+func (r *UserTemplateRepository) insert(template *model.UserTemplate) {
+	now := time.Now()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	r.templates[template.ID] = *template
+}
+
+// byUserID 返回用户的模板，按收藏优先、排序位置、ID 排序，调用方需持有锁
+func (r *UserTemplateRepository) byUserID(userID int64) []model.UserTemplate {
+	var templates []model.UserTemplate
+	for _, template := range r.templates {
+		if template.UserID == userID {
+			templates = append(templates, template)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		a, b := &templates[i], &templates[j]
+		if a.IsFavorite != b.IsFavorite {
+			return a.IsFavorite
+		}
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder < b.SortOrder
+		}
+		return a.ID < b.ID
+	})
+	return templates
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+func TestUserTemplateRepository(t *testing.T) {
+	repo := NewUserTemplateRepository()
+
+	if template, _ := repo.GetDefaultTemplate(context.Background()); template != nil {
+		t.Errorf("GetDefaultTemplate() on empty repo = %+v, want nil", template)
+	}
+
+	_ = repo.Create(context.Background(), &model.UserTemplate{ID: 20, UserID: 0, TemplateContent: "后建的默认模板"})
+	_ = repo.Create(context.Background(), &model.UserTemplate{ID: 10, UserID: 0, TemplateContent: "默认模板"})
+	_ = repo.Create(context.Background(), &model.UserTemplate{ID: 30, UserID: 1, TemplateContent: "用户模板"})
+
+	if template, _ := repo.GetDefaultTemplate(context.Background()); template == nil || template.ID != 10 {
+		t.Errorf("GetDefaultTemplate() = %+v, want template 10", template)
+	}
+
+	template, _ := repo.GetByID(context.Background(), 30)
+	template.TemplateContent = "修改后"
+	_ = repo.Update(context.Background(), template)
+	if got, _ := repo.GetByID(context.Background(), 30); got.TemplateContent != "修改后" {
+		t.Errorf("Update() not applied: %+v", got)
+	}
+
+	if count, _ := repo.CountByUserID(context.Background(), 0); count != 2 {
+		t.Errorf("CountByUserID(0) = %d, want 2", count)
+	}
+
+	_ = repo.Delete(context.Background(), 30)
+	if templates, _ := repo.GetByUserID(context.Background(), 1); len(templates) != 0 {
+		t.Errorf("GetByUserID() after delete = %+v", templates)
+	}
+}
+
+func TestUserTemplateRepository_CreateWithLimitConcurrent(t *testing.T) {
+	repo := NewUserTemplateRepository()
+
+	const limit = 3
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	exceeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			err := repo.CreateWithLimit(context.Background(), &model.UserTemplate{ID: id, UserID: 1}, limit)
+			if errors.Is(err, repository.ErrTemplateLimitExceeded) {
+				mu.Lock()
+				exceeded++
+				mu.Unlock()
+			}
+		}(int64(i + 1))
+	}
+	wg.Wait()
+
+	if count, _ := repo.CountByUserID(context.Background(), 1); count != limit || exceeded != 10-limit {
+		t.Errorf("count = %d, exceeded = %d, want %d and %d", count, exceeded, limit, 10-limit)
+	}
+}
+
+func TestUserTemplateRepository_ShareCode(t *testing.T) {
+	repo := NewUserTemplateRepository()
+	ctx := context.Background()
+	_ = repo.Create(ctx, &model.UserTemplate{ID: 1, UserID: 1, TemplateContent: "用户模板"})
+
+	code := "abcdefgh"
+	if err := repo.UpdateShareCode(ctx, 1, &code); err != nil {
+		t.Fatalf("UpdateShareCode() error = %v", err)
+	}
+	if template, _ := repo.GetByShareCode(ctx, code); template == nil || template.ID != 1 {
+		t.Errorf("GetByShareCode() = %+v, want template 1", template)
+	}
+
+	_ = repo.UpdateShareCode(ctx, 1, nil)
+	if template, _ := repo.GetByShareCode(ctx, code); template != nil {
+		t.Errorf("GetByShareCode() after revoke = %+v, want nil", template)
+	}
+}
+
+func TestUserTemplateRepository_OrderFavoriteDefault(t *testing.T) {
+	repo := NewUserTemplateRepository()
+	ctx := context.Background()
+	for i := int64(1); i <= 3; i++ {
+		_ = repo.Create(ctx, &model.UserTemplate{ID: i, UserID: 1, TemplateContent: "用户模板"})
+	}
+
+	_ = repo.UpdateSortOrders(ctx, 1, []int64{3, 1, 2})
+	_ = repo.UpdateFavorite(ctx, 2, true)
+	templates, _ := repo.GetByUserID(ctx, 1)
+	if len(templates) != 3 || templates[0].ID != 2 || templates[1].ID != 3 || templates[2].ID != 1 {
+		t.Errorf("GetByUserID() = %+v, want order [2 3 1]", templates)
+	}
+
+	_ = repo.SetUserDefault(ctx, 1, 1)
+	_ = repo.SetUserDefault(ctx, 1, 2)
+	if template, _ := repo.GetByID(ctx, 1); template.IsDefault {
+		t.Error("SetUserDefault() should replace the previous default")
+	}
+	if template, _ := repo.GetByID(ctx, 2); !template.IsDefault {
+		t.Error("SetUserDefault() should mark template 2 as default")
+	}
+	_ = repo.SetUserDefault(ctx, 1, 0)
+	if template, _ := repo.GetByID(ctx, 2); template.IsDefault {
+		t.Error("SetUserDefault(0) should clear the personal default")
+	}
+}
+
+func TestUserTemplateRepository_CreateBatchWithLimit(t *testing.T) {
+	repo := NewUserTemplateRepository()
+	ctx := context.Background()
+	_ = repo.Create(ctx, &model.UserTemplate{ID: 1, UserID: 1, TemplateContent: "用户模板"})
+
+	batch := []*model.UserTemplate{
+		{ID: 2, UserID: 1, TemplateContent: "a"},
+		{ID: 3, UserID: 1, TemplateContent: "b"},
+	}
+	if err := repo.CreateBatchWithLimit(ctx, 1, batch, 2); !errors.Is(err, repository.ErrTemplateLimitExceeded) {
+		t.Errorf("CreateBatchWithLimit() error = %v, want ErrTemplateLimitExceeded", err)
+	}
+	if err := repo.CreateBatchWithLimit(ctx, 1, batch, 3); err != nil {
+		t.Fatalf("CreateBatchWithLimit() error = %v", err)
+	}
+	if count, _ := repo.CountByUserID(ctx, 1); count != 3 {
+		t.Errorf("CountByUserID() = %d, want 3", count)
+	}
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+// UserVariableRepository 内存用户自定义模板变量仓储
+type UserVariableRepository struct {
+	mu        sync.RWMutex
+	variables map[int64]model.UserVariable
+}
+
+// NewUserVariableRepository 创建内存用户自定义模板变量仓储
+func NewUserVariableRepository() *UserVariableRepository {
+	return &UserVariableRepository{
+		variables: make(map[int64]model.UserVariable),
+	}
+}
+
+// GetByUserID 根据用户ID获取变量列表，按名称排序
+func (r *UserVariableRepository) GetByUserID(ctx context.Context, userID int64) ([]model.UserVariable, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var variables []model.UserVariable
+	for _, variable := range r.variables {
+		if variable.UserID == userID {
+			variables = append(variables, variable)
+		}
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].VarName < variables[j].VarName
+	})
+	return variables, nil
+}
+
+// SaveWithLimit 在锁内新增或更新变量，新增时原子地检查数量限制
+func (r *UserVariableRepository) SaveWithLimit(ctx context.Context, variable *model.UserVariable, maxLimit int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var count int64
+	for id, existing := range r.variables {
+		if existing.UserID != variable.UserID {
+			continue
+		}
+		if existing.VarName == variable.VarName {
+			variable.ID = id
+			variable.CreatedAt = existing.CreatedAt
+			variable.UpdatedAt = now
+			r.variables[id] = *variable
+			return nil
+		}
+		count++
+	}
+
+	if count >= maxLimit {
+		return repository.ErrVariableLimitExceeded
+	}
+
+	variable.CreatedAt = now
+	variable.UpdatedAt = now
+	r.variables[variable.ID] = *variable
+	return nil
+}
+
+// Delete 删除用户的指定变量
+func (r *UserVariableRepository) Delete(ctx context.Context, userID int64, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, variable := range r.variables {
+		if variable.UserID == userID && variable.VarName == name {
+			delete(r.variables, id)
+		}
+	}
+	return nil
+}
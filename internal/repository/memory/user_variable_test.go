@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+func TestUserVariableRepository(t *testing.T) {
+	repo := NewUserVariableRepository()
+	ctx := context.Background()
+
+	_ = repo.SaveWithLimit(ctx, &model.UserVariable{ID: 1, UserID: 123, VarName: "name", VarValue: "小明"}, 2)
+	_ = repo.SaveWithLimit(ctx, &model.UserVariable{ID: 2, UserID: 123, VarName: "goal", VarValue: "985"}, 2)
+	_ = repo.SaveWithLimit(ctx, &model.UserVariable{ID: 3, UserID: 456, VarName: "name", VarValue: "小红"}, 2)
+
+	if err := repo.SaveWithLimit(ctx, &model.UserVariable{ID: 4, UserID: 123, VarName: "school", VarValue: "一中"}, 2); !errors.Is(err, repository.ErrVariableLimitExceeded) {
+		t.Errorf("SaveWithLimit() over limit error = %v, want ErrVariableLimitExceeded", err)
+	}
+
+	// 更新已有变量时回写 ID
+	updated := &model.UserVariable{ID: 5, UserID: 123, VarName: "name", VarValue: "小刚"}
+	if err := repo.SaveWithLimit(ctx, updated, 2); err != nil || updated.ID != 1 || updated.CreatedAt.IsZero() {
+		t.Errorf("SaveWithLimit() update = %+v, %v", updated, err)
+	}
+
+	variables, _ := repo.GetByUserID(ctx, 123)
+	if len(variables) != 2 || variables[0].VarName != "goal" || variables[1].VarValue != "小刚" {
+		t.Errorf("GetByUserID() = %+v", variables)
+	}
+
+	_ = repo.Delete(ctx, 123, "name")
+	if variables, _ := repo.GetByUserID(ctx, 123); len(variables) != 1 || variables[0].VarName != "goal" {
+		t.Errorf("GetByUserID() after delete = %+v", variables)
+	}
+	if variables, _ := repo.GetByUserID(ctx, 456); len(variables) != 1 {
+		t.Errorf("Delete() removed another user's variable: %+v", variables)
+	}
+}
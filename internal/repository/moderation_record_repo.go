@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+// GormModerationRecordRepository 基于 GORM 的模板内容审核记录仓储
+type GormModerationRecordRepository struct {
+	db *gorm.DB
+}
+
+// NewModerationRecordRepository 创建模板内容审核记录仓储
+func NewModerationRecordRepository(db *gorm.DB) *GormModerationRecordRepository {
+	return &GormModerationRecordRepository{db: db}
+}
+
+// Find 按状态分页查询记录，最新的在前
+func (r *GormModerationRecordRepository) Find(ctx context.Context, status string, offset, limit int) ([]model.ModerationRecord, error) {
+	var records []model.ModerationRecord
+
+	// 雪花 ID 随时间递增，按 ID 倒序即按提交时间倒序
+	query := r.filter(ctx, status).Order("id DESC")
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&records).Error
+
+	return records, err
+}
+
+// Count 统计指定状态的记录数量
+func (r *GormModerationRecordRepository) Count(ctx context.Context, status string) (int64, error) {
+	var count int64
+	err := r.filter(ctx, status).Count(&count).Error
+	return count, err
+}
+
+// GetByID 根据ID获取记录
+func (r *GormModerationRecordRepository) GetByID(ctx context.Context, id int64) (*model.ModerationRecord, error) {
+	var record model.ModerationRecord
+
+	err := r.db.WithContext(ctx).First(&record, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create 创建记录
+func (r *GormModerationRecordRepository) Create(ctx context.Context, record *model.ModerationRecord) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+// UpdateStatus 更新记录的状态和复核时间
+func (r *GormModerationRecordRepository) UpdateStatus(ctx context.Context, id int64, status string, reviewedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.ModerationRecord{ID: id}).Updates(map[string]interface{}{
+		"status":      status,
+		"reviewed_at": reviewedAt,
+	}).Error
+}
+
+// filter 按状态过滤记录，status 为空时不过滤
+func (r *GormModerationRecordRepository) filter(ctx context.Context, status string) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&model.ModerationRecord{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return query
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestModerationRecordRepository(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.ModerationRecord{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	repo := NewModerationRecordRepository(db)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		record := &model.ModerationRecord{ID: i, UserID: 123, Action: model.ModerationActionCreate, TemplateContent: "内容", Status: model.ModerationStatusPending}
+		if err := repo.Create(ctx, record); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	reviewedAt := time.Now()
+	if err := repo.UpdateStatus(ctx, 2, model.ModerationStatusDismissed, reviewedAt); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	records, err := repo.Find(ctx, model.ModerationStatusPending, 0, 10)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(records) != 2 || records[0].ID != 3 || records[1].ID != 1 {
+		t.Errorf("Find() = %+v, want records 3, 1", records)
+	}
+	if records, _ := repo.Find(ctx, "", 1, 1); len(records) != 1 || records[0].ID != 2 {
+		t.Errorf("Find() paged = %+v, want record 2", records)
+	}
+
+	if count, err := repo.Count(ctx, model.ModerationStatusPending); err != nil || count != 2 {
+		t.Errorf("Count() = %d, %v, want 2", count, err)
+	}
+	if count, _ := repo.Count(ctx, ""); count != 3 {
+		t.Errorf("Count() all = %d, want 3", count)
+	}
+
+	record, err := repo.GetByID(ctx, 2)
+	if err != nil || record == nil {
+		t.Fatalf("GetByID() = %+v, %v", record, err)
+	}
+	if record.Status != model.ModerationStatusDismissed || record.ReviewedAt == nil {
+		t.Errorf("GetByID() = %+v, want dismissed with reviewed_at", record)
+	}
+	if record, err := repo.GetByID(ctx, 99); err != nil || record != nil {
+		t.Errorf("GetByID() missing = %+v, %v, want nil", record, err)
+	}
+}
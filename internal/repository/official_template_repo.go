@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+// GormOfficialTemplateRepository 基于 GORM 的官方模板仓储
+type GormOfficialTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewOfficialTemplateRepository 创建官方模板仓储
+func NewOfficialTemplateRepository(db *gorm.DB) *GormOfficialTemplateRepository {
+	return &GormOfficialTemplateRepository{db: db}
+}
+
+// GetAll 获取全部官方模板，按排序位置和 ID 排列
+func (r *GormOfficialTemplateRepository) GetAll(ctx context.Context) ([]model.OfficialTemplate, error) {
+	var templates []model.OfficialTemplate
+
+	err := r.db.WithContext(ctx).
+		Order("sort_order ASC").
+		Order("id ASC").
+		Find(&templates).Error
+
+	return templates, err
+}
+
+// GetByID 根据ID获取官方模板
+func (r *GormOfficialTemplateRepository) GetByID(ctx context.Context, id int64) (*model.OfficialTemplate, error) {
+	var template model.OfficialTemplate
+
+	err := r.db.WithContext(ctx).First(&template, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// Create 创建官方模板
+func (r *GormOfficialTemplateRepository) Create(ctx context.Context, template *model.OfficialTemplate) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+// Update 更新官方模板（包括置空生效时间和停用）
+func (r *GormOfficialTemplateRepository) Update(ctx context.Context, template *model.OfficialTemplate) error {
+	return r.db.WithContext(ctx).Save(template).Error
+}
+
+// Delete 删除官方模板
+func (r *GormOfficialTemplateRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.OfficialTemplate{}, id).Error
+}
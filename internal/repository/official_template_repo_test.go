@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestOfficialTemplateRepository(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.OfficialTemplate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	repo := NewOfficialTemplateRepository(db)
+	ctx := context.Background()
+
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	templates := []*model.OfficialTemplate{
+		{ID: 1, TemplateName: "常规", TemplateContent: "距离{exam}还有{time}", SortOrder: 2, Active: true},
+		{ID: 2, TemplateName: "百日冲刺", TemplateContent: "{exam}百日冲刺，还有{time}", SortOrder: 1, Active: true, DaysBeforeExam: 100},
+		{ID: 3, TemplateName: "寒假", TemplateContent: "寒假也别忘了{exam}", SortOrder: 2, StartAt: &start},
+	}
+	for _, template := range templates {
+		if err := repo.Create(ctx, template); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	all, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(all) != 3 || all[0].ID != 2 || all[1].ID != 1 || all[2].ID != 3 {
+		t.Errorf("GetAll() = %+v, want templates 2, 1, 3", all)
+	}
+
+	// 更新时应能停用模板并清空生效时间
+	template, err := repo.GetByID(ctx, 3)
+	if err != nil || template == nil || template.StartAt == nil || template.Active {
+		t.Fatalf("GetByID(3) = %+v, %v", template, err)
+	}
+	template.StartAt = nil
+	template.Active = true
+	if err := repo.Update(ctx, template); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if template, _ := repo.GetByID(ctx, 3); template == nil || template.StartAt != nil || !template.Active {
+		t.Errorf("GetByID(3) after update = %+v", template)
+	}
+	template.Active = false
+	_ = repo.Update(ctx, template)
+	if template, _ := repo.GetByID(ctx, 3); template == nil || template.Active {
+		t.Errorf("GetByID(3) after deactivate = %+v", template)
+	}
+
+	if err := repo.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if template, err := repo.GetByID(ctx, 1); err != nil || template != nil {
+		t.Errorf("GetByID(1) after delete = %+v, %v, want nil", template, err)
+	}
+}
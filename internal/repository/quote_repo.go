@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+// GormQuoteRepository 基于 GORM 的励志语录仓储
+type GormQuoteRepository struct {
+	db *gorm.DB
+}
+
+// NewQuoteRepository 创建励志语录仓储
+func NewQuoteRepository(db *gorm.DB) *GormQuoteRepository {
+	return &GormQuoteRepository{db: db}
+}
+
+// GetAll 获取全部语录，按 ID 排列
+func (r *GormQuoteRepository) GetAll(ctx context.Context) ([]model.Quote, error) {
+	var quotes []model.Quote
+
+	err := r.db.WithContext(ctx).
+		Order("id ASC").
+		Find(&quotes).Error
+
+	return quotes, err
+}
+
+// GetByID 根据ID获取语录
+func (r *GormQuoteRepository) GetByID(ctx context.Context, id int64) (*model.Quote, error) {
+	var quote model.Quote
+
+	err := r.db.WithContext(ctx).First(&quote, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+// Create 创建语录
+func (r *GormQuoteRepository) Create(ctx context.Context, quote *model.Quote) error {
+	return r.db.WithContext(ctx).Create(quote).Error
+}
+
+// CreateBatch 在同一事务中创建多条语录，任一失败时全部不创建
+func (r *GormQuoteRepository) CreateBatch(ctx context.Context, quotes []*model.Quote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(quotes).Error
+	})
+}
+
+// Update 更新语录（包括清空出处和标签）
+func (r *GormQuoteRepository) Update(ctx context.Context, quote *model.Quote) error {
+	return r.db.WithContext(ctx).Save(quote).Error
+}
+
+// Delete 删除语录
+func (r *GormQuoteRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.Quote{}, id).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestQuoteRepository(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.Quote{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	repo := NewQuoteRepository(db)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &model.Quote{ID: 3, Content: "天道酬勤", Tags: "坚持"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.CreateBatch(ctx, []*model.Quote{
+		{ID: 2, Content: "宝剑锋从磨砺出，梅花香自苦寒来", Source: "《警世贤文》"},
+		{ID: 1, Content: "不积跬步，无以至千里", Source: "荀子"},
+	}); err != nil {
+		t.Fatalf("CreateBatch() error = %v", err)
+	}
+	if err := repo.CreateBatch(ctx, nil); err != nil {
+		t.Errorf("CreateBatch(nil) error = %v", err)
+	}
+
+	all, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(all) != 3 || all[0].ID != 1 || all[1].ID != 2 || all[2].ID != 3 {
+		t.Errorf("GetAll() = %+v, want quotes 1, 2, 3", all)
+	}
+
+	// 批量创建中任一失败时全部不创建
+	if err := repo.CreateBatch(ctx, []*model.Quote{{ID: 4, Content: "新语录"}, {ID: 1, Content: "重复ID"}}); err == nil {
+		t.Error("CreateBatch() with duplicate ID should fail")
+	}
+	if quote, _ := repo.GetByID(ctx, 4); quote != nil {
+		t.Errorf("GetByID(4) = %+v, want nil after rollback", quote)
+	}
+
+	// 更新时应能清空出处
+	quote, err := repo.GetByID(ctx, 2)
+	if err != nil || quote == nil || quote.Source == "" {
+		t.Fatalf("GetByID(2) = %+v, %v", quote, err)
+	}
+	quote.Source = ""
+	if err := repo.Update(ctx, quote); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if quote, _ := repo.GetByID(ctx, 2); quote == nil || quote.Source != "" {
+		t.Errorf("GetByID(2) after update = %+v", quote)
+	}
+
+	if err := repo.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if quote, err := repo.GetByID(ctx, 1); err != nil || quote != nil {
+		t.Errorf("GetByID(1) after delete = %+v, %v", quote, err)
+	}
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ReadRouter 将只读查询路由到只读副本（实现见 database.ReplicaSet）
+// fn 可能在副本失败后改用主库再次调用，不得包含写操作或其他副作用
+type ReadRouter interface {
+	Read(ctx context.Context, fn func(db *gorm.DB) error) error
+}
+
+// primaryKey 标记只读查询使用主库的 context 键
+type primaryKey struct{}
+
+// WithPrimary 返回要求只读查询使用主库的 context
+// 副本存在复制延迟，写后立即读取的场景（如刚修改模板后重新加载列表）应使用主库
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// usePrimary ctx 是否要求只读查询使用主库
+func usePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// readOnly 执行只读查询，未配置 ReadRouter 或 ctx 要求使用主库时直接使用主库
+// 副本存在复制延迟，刚写入的数据可能暂时读不到，写后立即读取的场景应通过 WithPrimary 使用主库
+func readOnly(ctx context.Context, db *gorm.DB, router ReadRouter, fn func(db *gorm.DB) error) error {
+	if router == nil || usePrimary(ctx) {
+		return fn(db.WithContext(ctx))
+	}
+	return router.Read(ctx, fn)
+}
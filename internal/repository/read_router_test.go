@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// replicaRouter 将只读查询全部路由到指定数据库，并统计次数
+type replicaRouter struct {
+	db    *gorm.DB
+	reads int
+}
+
+func (r *replicaRouter) Read(ctx context.Context, fn func(db *gorm.DB) error) error {
+	r.reads++
+	return fn(r.db.WithContext(ctx))
+}
+
+func setupReadRouterTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ExamDate{}, &model.UserTemplate{}, &model.UserTarget{}, &model.SendChat{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return db
+}
+
+func TestReadReplicas(t *testing.T) {
+	ctx := context.Background()
+	primary := setupReadRouterTestDB(t)
+	replica := setupReadRouterTestDB(t)
+	router := &replicaRouter{db: replica}
+
+	now := time.Now()
+	exam := model.ExamDate{
+		ID:                1,
+		ExamYear:          now.Year(),
+		ExamBeginDate:     now.AddDate(0, 1, 0),
+		ExamEndDate:       now.AddDate(0, 1, 2),
+		ExamYearBeginDate: now.AddDate(-1, 0, 0),
+		ExamYearEndDate:   now.AddDate(0, 1, 2),
+	}
+	replica.Create(&exam)
+	replica.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateContent: "副本默认模板"})
+	replica.Create(&model.UserTemplate{ID: 2, UserID: 100, TemplateContent: "副本模板"})
+	replica.Create(&model.UserTarget{ID: 3, UserID: 100, TargetName: "副本目标", TargetDate: now})
+	replica.Create(&model.SendChat{ID: 4, ChatID: "副本对话"})
+
+	exams := NewExamDateRepository(primary).WithReadReplicas(router)
+	if result, err := exams.GetExamsInRange(ctx, now); err != nil || len(result) != 1 {
+		t.Errorf("GetExamsInRange() = %v, %v, want replica data", result, err)
+	}
+	if result, err := exams.GetAll(ctx, false); err != nil || len(result) != 1 {
+		t.Errorf("GetAll(false) = %v, %v, want replica data", result, err)
+	}
+	if result, err := exams.GetEndingAfter(ctx, now); err != nil || len(result) != 1 {
+		t.Errorf("GetEndingAfter() = %v, %v, want replica data", result, err)
+	}
+	// 导入前的比对读取主库
+	if result, err := exams.GetAll(ctx, true); err != nil || len(result) != 0 {
+		t.Errorf("GetAll(true) = %v, %v, want primary data", result, err)
+	}
+
+	templates := NewUserTemplateRepository(primary).WithReadReplicas(router)
+	if result, err := templates.GetByUserID(ctx, 100); err != nil || len(result) != 1 {
+		t.Errorf("GetByUserID() = %v, %v, want replica data", result, err)
+	}
+	if result, err := templates.GetDefaultTemplate(ctx); err != nil || result == nil {
+		t.Errorf("GetDefaultTemplate() = %v, %v, want replica data", result, err)
+	}
+	// 权限校验与限额统计读取主库
+	if result, err := templates.GetByID(ctx, 2); err != nil || result != nil {
+		t.Errorf("GetByID() = %v, %v, want primary data", result, err)
+	}
+
+	targets := NewUserTargetRepository(primary).WithReadReplicas(router)
+	if result, err := targets.GetByUserID(ctx, 100); err != nil || len(result) != 1 {
+		t.Errorf("GetByUserID() = %v, %v, want replica data", result, err)
+	}
+
+	chats := NewSendChatRepository(primary).WithReadReplicas(router)
+	if result, err := chats.GetAll(ctx); err != nil || len(result) != 1 {
+		t.Errorf("GetAll() = %v, %v, want replica data", result, err)
+	}
+	// 按 ID 查询读取主库
+	if result, err := chats.GetByID(ctx, 4); err != nil || result != nil {
+		t.Errorf("GetByID() = %v, %v, want primary data", result, err)
+	}
+
+	if router.reads != 7 {
+		t.Errorf("replica reads = %d, want 7", router.reads)
+	}
+}
+
+func TestReadReplicas_Disabled(t *testing.T) {
+	primary := setupReadRouterTestDB(t)
+	primary.Create(&model.UserTemplate{ID: 1, UserID: 100})
+
+	// 未配置副本时使用主库
+	templates := NewUserTemplateRepository(primary).WithReadReplicas(nil)
+	if result, err := templates.GetByUserID(context.Background(), 100); err != nil || len(result) != 1 {
+		t.Errorf("GetByUserID() = %v, %v, want primary data", result, err)
+	}
+}
+
+func TestReadReplicas_WithPrimary(t *testing.T) {
+	primary := setupReadRouterTestDB(t)
+	replica := setupReadRouterTestDB(t)
+	router := &replicaRouter{db: replica}
+	primary.Create(&model.UserTemplate{ID: 1, UserID: 100})
+
+	// 写后立即读取时跳过副本
+	templates := NewUserTemplateRepository(primary).WithReadReplicas(router)
+	if result, err := templates.GetByUserID(WithPrimary(context.Background()), 100); err != nil || len(result) != 1 {
+		t.Errorf("GetByUserID() = %v, %v, want primary data", result, err)
+	}
+	if router.reads != 0 {
+		t.Errorf("replica reads = %d, want 0", router.reads)
+	}
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+// 仓储接口由服务层依赖，GORM 实现位于本包，内存实现位于 repository/memory 包
+// 约定：按 ID 查询不到记录时返回 (nil, nil)；考试与事件的删除均为逻辑删除；
+// 所有方法的 ctx 用于取消查询和控制超时，GORM 实现通过 db.WithContext 传递给数据库驱动
+
+// ExamDateRepository 考试日期仓储
+type ExamDateRepository interface {
+	// GetExamsInRange 获取考试年范围包含 now 的考试，按开始时间排序
+	GetExamsInRange(ctx context.Context, now time.Time) ([]model.ExamDate, error)
+	// GetExamByYear 按年份获取考试，按开始时间排序
+	GetExamByYear(ctx context.Context, year int) ([]model.ExamDate, error)
+	// GetAll 获取全部考试，按年份和类型排序；includeDeleted 为 true 时包含已删除记录
+	GetAll(ctx context.Context, includeDeleted bool) ([]model.ExamDate, error)
+	// SaveAll 批量保存考试，ID 为 0 时插入，任一失败时整体回滚
+	SaveAll(ctx context.Context, exams []model.ExamDate) error
+	// GetEndingAfter 获取结束时间不早于 since 的考试，按开始时间排序
+	GetEndingAfter(ctx context.Context, since time.Time) ([]model.ExamDate, error)
+	// Find 按条件查询考试
+	Find(ctx context.Context, q ExamQuery) ([]model.ExamDate, error)
+}
+
+// ExamEventRepository 考后时间线事件仓储
+type ExamEventRepository interface {
+	// GetByExam 获取指定考试的事件，按时间排序
+	GetByExam(ctx context.Context, year int, kind, region string) ([]model.ExamEvent, error)
+	// GetByYear 获取指定年份的全部事件，按时间排序
+	GetByYear(ctx context.Context, year int) ([]model.ExamEvent, error)
+	// GetBetween 获取时间在 [from, to) 内的事件，按时间排序
+	GetBetween(ctx context.Context, from, to time.Time) ([]model.ExamEvent, error)
+	// GetByID 根据 ID 获取未删除的事件
+	GetByID(ctx context.Context, id uint) (*model.ExamEvent, error)
+	// Create 创建事件
+	Create(ctx context.Context, event *model.ExamEvent) error
+	// Delete 逻辑删除事件
+	Delete(ctx context.Context, id uint) error
+}
+
+// SendChatRepository 发送对话仓储
+type SendChatRepository interface {
+	// GetAll 获取全部推送对话
+	GetAll(ctx context.Context) ([]model.SendChat, error)
+	// Create 创建推送对话
+	Create(ctx context.Context, chat *model.SendChat) error
+	// Delete 删除推送对话及其模板池
+	Delete(ctx context.Context, id int64) error
+	// GetByID 根据 ID 获取推送对话
+	GetByID(ctx context.Context, id int64) (*model.SendChat, error)
+	// UpdateTemplate 更新推送对话选用的官方模板，templateID 为 0 表示使用默认模板
+	UpdateTemplate(ctx context.Context, id, templateID int64) error
+	// GetTemplatePool 获取推送对话的模板池，按排序位置排列
+	GetTemplatePool(ctx context.Context, id int64) ([]model.SendChatTemplate, error)
+	// GetAllTemplatePools 获取全部推送对话的模板池，按对话和排序位置排列
+	GetAllTemplatePools(ctx context.Context) ([]model.SendChatTemplate, error)
+	// UpdateRotation 在同一事务中替换模板池并更新管理员和轮换策略，同时清空上次使用的条目
+	UpdateRotation(ctx context.Context, id, adminUserID int64, strategy string, pool []*model.SendChatTemplate) error
+	// UpdateLastEntry 记录本次轮换使用的模板池条目和时间
+	UpdateLastEntry(ctx context.Context, id, entryID int64, rotatedAt time.Time) error
+}
+
+// UserTargetRepository 用户自定义目标仓储
+type UserTargetRepository interface {
+	// GetByUserID 获取用户的全部目标，按目标时间排序
+	GetByUserID(ctx context.Context, userID int64) ([]model.UserTarget, error)
+	// GetByID 根据 ID 获取目标
+	GetByID(ctx context.Context, id int64) (*model.UserTarget, error)
+	// Delete 删除目标
+	Delete(ctx context.Context, id int64) error
+	// CreateWithLimit 原子地检查数量限制并创建目标，超出时返回 ErrTargetLimitExceeded
+	CreateWithLimit(ctx context.Context, target *model.UserTarget, maxLimit int64) error
+}
+
+// UserVariableRepository 用户自定义模板变量仓储
+type UserVariableRepository interface {
+	// GetByUserID 获取用户的全部变量，按名称排序
+	GetByUserID(ctx context.Context, userID int64) ([]model.UserVariable, error)
+	// SaveWithLimit 按用户和名称新增或更新变量，新增时原子地检查数量限制，超出时返回 ErrVariableLimitExceeded
+	// 更新已有变量时回写其 ID 和创建时间
+	SaveWithLimit(ctx context.Context, variable *model.UserVariable, maxLimit int64) error
+	// Delete 删除用户的指定变量
+	Delete(ctx context.Context, userID int64, name string) error
+}
+
+// UserTemplateRepository 用户模板仓储
+type UserTemplateRepository interface {
+	// GetByUserID 获取用户的全部模板，按收藏优先、排序位置、ID 排序
+	GetByUserID(ctx context.Context, userID int64) ([]model.UserTemplate, error)
+	// GetDefaultTemplate 获取默认模板（user_id 为 0）
+	GetDefaultTemplate(ctx context.Context) (*model.UserTemplate, error)
+	// Create 创建模板
+	Create(ctx context.Context, template *model.UserTemplate) error
+	// Update 更新模板
+	Update(ctx context.Context, template *model.UserTemplate) error
+	// Delete 删除模板
+	Delete(ctx context.Context, id int64) error
+	// GetByID 根据 ID 获取模板
+	GetByID(ctx context.Context, id int64) (*model.UserTemplate, error)
+	// CountByUserID 统计用户的模板数量
+	CountByUserID(ctx context.Context, userID int64) (int64, error)
+	// CreateWithLimit 原子地检查数量限制并创建模板，超出时返回 ErrTemplateLimitExceeded
+	CreateWithLimit(ctx context.Context, template *model.UserTemplate, maxLimit int64) error
+	// CreateBatchWithLimit 原子地检查数量限制并批量创建用户的模板，全部创建或全部不创建，超出时返回 ErrTemplateLimitExceeded
+	CreateBatchWithLimit(ctx context.Context, userID int64, templates []*model.UserTemplate, maxLimit int64) error
+	// GetByShareCode 根据分享码获取模板
+	GetByShareCode(ctx context.Context, code string) (*model.UserTemplate, error)
+	// UpdateShareCode 仅更新模板的分享码，code 为 nil 时取消分享
+	UpdateShareCode(ctx context.Context, id int64, code *string) error
+	// UpdateSortOrders 按 ids 的顺序设置用户模板的排序位置（从 1 开始）
+	UpdateSortOrders(ctx context.Context, userID int64, ids []int64) error
+	// UpdateFavorite 仅更新模板的收藏状态
+	UpdateFavorite(ctx context.Context, id int64, favorite bool) error
+	// SetUserDefault 将模板设为用户的个人默认模板并取消其他模板的默认状态，id 为 0 时仅取消
+	SetUserDefault(ctx context.Context, userID, id int64) error
+}
+
+// GalleryRepository 模板广场仓储
+type GalleryRepository interface {
+	// Find 按条件分页查询条目
+	Find(ctx context.Context, q GalleryQuery) ([]model.GalleryTemplate, error)
+	// Count 统计满足条件的条目数量
+	Count(ctx context.Context, q GalleryQuery) (int64, error)
+	// GetByID 根据 ID 获取条目
+	GetByID(ctx context.Context, id int64) (*model.GalleryTemplate, error)
+	// GetByTemplateID 根据来源模板 ID 获取条目
+	GetByTemplateID(ctx context.Context, templateID int64) (*model.GalleryTemplate, error)
+	// Create 创建条目
+	Create(ctx context.Context, item *model.GalleryTemplate) error
+	// UpdateContent 更新条目的名称、内容和审核状态
+	UpdateContent(ctx context.Context, item *model.GalleryTemplate) error
+	// UpdateStatus 更新条目的审核状态
+	UpdateStatus(ctx context.Context, id int64, status string) error
+	// Delete 删除条目及其点赞记录
+	Delete(ctx context.Context, id int64) error
+	// Like 点赞条目，返回是否新增了点赞
+	Like(ctx context.Context, galleryID, userID int64) (bool, error)
+	// Unlike 取消点赞，返回是否移除了点赞
+	Unlike(ctx context.Context, galleryID, userID int64) (bool, error)
+	// GetLikedIDs 返回 ids 中用户已点赞的条目
+	GetLikedIDs(ctx context.Context, userID int64, ids []int64) (map[int64]bool, error)
+	// IncrementCopyCount 复制数加一
+	IncrementCopyCount(ctx context.Context, id int64) error
+}
+
+// TemplateRevisionRepository 模板修订记录仓储
+type TemplateRevisionRepository interface {
+	// GetByTemplateID 获取模板的修订记录，最新的在前
+	GetByTemplateID(ctx context.Context, templateID int64) ([]model.TemplateRevision, error)
+	// GetByID 根据 ID 获取修订记录
+	GetByID(ctx context.Context, id int64) (*model.TemplateRevision, error)
+	// Create 创建修订记录，每个模板最多保留 maxPerTemplate 条，超出时删除最早的记录
+	Create(ctx context.Context, revision *model.TemplateRevision, maxPerTemplate int) error
+	// DeleteByTemplateID 删除模板的全部修订记录
+	DeleteByTemplateID(ctx context.Context, templateID int64) error
+}
+
+// OfficialTemplateRepository 官方模板仓储
+type OfficialTemplateRepository interface {
+	// GetAll 获取全部官方模板（包含未启用的），按排序位置和 ID 排列
+	GetAll(ctx context.Context) ([]model.OfficialTemplate, error)
+	// GetByID 根据 ID 获取官方模板
+	GetByID(ctx context.Context, id int64) (*model.OfficialTemplate, error)
+	// Create 创建官方模板
+	Create(ctx context.Context, template *model.OfficialTemplate) error
+	// Update 更新官方模板
+	Update(ctx context.Context, template *model.OfficialTemplate) error
+	// Delete 删除官方模板
+	Delete(ctx context.Context, id int64) error
+}
+
+// ModerationRecordRepository 模板内容审核记录仓储
+type ModerationRecordRepository interface {
+	// Find 按状态分页查询记录，最新的在前；status 为空时不过滤
+	Find(ctx context.Context, status string, offset, limit int) ([]model.ModerationRecord, error)
+	// Count 统计指定状态的记录数量，status 为空时统计全部
+	Count(ctx context.Context, status string) (int64, error)
+	// GetByID 根据 ID 获取记录
+	GetByID(ctx context.Context, id int64) (*model.ModerationRecord, error)
+	// Create 创建记录
+	Create(ctx context.Context, record *model.ModerationRecord) error
+	// UpdateStatus 更新记录的状态和复核时间
+	UpdateStatus(ctx context.Context, id int64, status string, reviewedAt time.Time) error
+}
+
+// QuoteRepository 励志语录仓储
+type QuoteRepository interface {
+	// GetAll 获取全部语录，按 ID 排列
+	GetAll(ctx context.Context) ([]model.Quote, error)
+	// GetByID 根据 ID 获取语录
+	GetByID(ctx context.Context, id int64) (*model.Quote, error)
+	// Create 创建语录
+	Create(ctx context.Context, quote *model.Quote) error
+	// CreateBatch 在同一事务中创建多条语录
+	CreateBatch(ctx context.Context, quotes []*model.Quote) error
+	// Update 更新语录
+	Update(ctx context.Context, quote *model.Quote) error
+	// Delete 删除语录
+	Delete(ctx context.Context, id int64) error
+}
+
+// 编译期检查 GORM 实现满足仓储接口
+var (
+	_ ExamDateRepository         = (*GormExamDateRepository)(nil)
+	_ ExamEventRepository        = (*GormExamEventRepository)(nil)
+	_ GalleryRepository          = (*GormGalleryRepository)(nil)
+	_ ModerationRecordRepository = (*GormModerationRecordRepository)(nil)
+	_ OfficialTemplateRepository = (*GormOfficialTemplateRepository)(nil)
+	_ QuoteRepository            = (*GormQuoteRepository)(nil)
+	_ SendChatRepository         = (*GormSendChatRepository)(nil)
+	_ TemplateRevisionRepository = (*GormTemplateRevisionRepository)(nil)
+	_ UserTargetRepository       = (*GormUserTargetRepository)(nil)
+	_ UserTemplateRepository     = (*GormUserTemplateRepository)(nil)
+	_ UserVariableRepository     = (*GormUserVariableRepository)(nil)
+)
@@ -1,35 +1,127 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/herbertgao/gaokao_bot/internal/model"
 	"gorm.io/gorm"
 )
 
-// SendChatRepository 发送对话仓储
-type SendChatRepository struct {
-	db *gorm.DB
+// GormSendChatRepository 基于 GORM 的发送对话仓储
+type GormSendChatRepository struct {
+	db    *gorm.DB
+	reads ReadRouter
 }
 
 // NewSendChatRepository 创建发送对话仓储
-func NewSendChatRepository(db *gorm.DB) *SendChatRepository {
-	return &SendChatRepository{db: db}
+func NewSendChatRepository(db *gorm.DB) *GormSendChatRepository {
+	return &GormSendChatRepository{db: db}
+}
+
+// WithReadReplicas 将发送对话列表查询路由到只读副本
+func (r *GormSendChatRepository) WithReadReplicas(router ReadRouter) *GormSendChatRepository {
+	r.reads = router
+	return r
 }
 
 // GetAll 获取所有发送对话
-func (r *SendChatRepository) GetAll() ([]model.SendChat, error) {
+func (r *GormSendChatRepository) GetAll(ctx context.Context) ([]model.SendChat, error) {
 	var chats []model.SendChat
 
-	err := r.db.Find(&chats).Error
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		return db.Find(&chats).Error
+	})
 
 	return chats, err
 }
 
 // Create 创建发送对话
-func (r *SendChatRepository) Create(chat *model.SendChat) error {
-	return r.db.Create(chat).Error
+func (r *GormSendChatRepository) Create(ctx context.Context, chat *model.SendChat) error {
+	return r.db.WithContext(ctx).Create(chat).Error
+}
+
+// Delete 删除发送对话及其模板池
+func (r *GormSendChatRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("send_chat_id = ?", id).Delete(&model.SendChatTemplate{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.SendChat{}, id).Error
+	})
+}
+
+// GetByID 根据ID获取发送对话
+func (r *GormSendChatRepository) GetByID(ctx context.Context, id int64) (*model.SendChat, error) {
+	var chat model.SendChat
+
+	err := r.db.WithContext(ctx).First(&chat, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &chat, nil
+}
+
+// UpdateTemplate 更新发送对话选用的官方模板，templateID 为 0 时恢复使用默认模板
+func (r *GormSendChatRepository) UpdateTemplate(ctx context.Context, id, templateID int64) error {
+	return r.db.WithContext(ctx).Model(&model.SendChat{ID: id}).Update("template_id", templateID).Error
 }
 
-// Delete 删除发送对话
-func (r *SendChatRepository) Delete(id int64) error {
-	return r.db.Delete(&model.SendChat{}, id).Error
-}
\ No newline at end of file
+// GetTemplatePool 获取发送对话的模板池，按排序位置排列
+func (r *GormSendChatRepository) GetTemplatePool(ctx context.Context, id int64) ([]model.SendChatTemplate, error) {
+	var pool []model.SendChatTemplate
+
+	err := r.db.WithContext(ctx).
+		Where("send_chat_id = ?", id).
+		Order("sort_order ASC").
+		Order("id ASC").
+		Find(&pool).Error
+
+	return pool, err
+}
+
+// GetAllTemplatePools 获取全部发送对话的模板池，按对话和排序位置排列
+func (r *GormSendChatRepository) GetAllTemplatePools(ctx context.Context) ([]model.SendChatTemplate, error) {
+	var pool []model.SendChatTemplate
+
+	err := r.db.WithContext(ctx).
+		Order("send_chat_id ASC").
+		Order("sort_order ASC").
+		Order("id ASC").
+		Find(&pool).Error
+
+	return pool, err
+}
+
+// UpdateRotation 在事务中替换模板池并更新管理员和轮换策略，同时清空上次使用的条目
+func (r *GormSendChatRepository) UpdateRotation(ctx context.Context, id, adminUserID int64, strategy string, pool []*model.SendChatTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("send_chat_id = ?", id).Delete(&model.SendChatTemplate{}).Error; err != nil {
+			return err
+		}
+		if len(pool) > 0 {
+			if err := tx.Create(pool).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.SendChat{ID: id}).Updates(map[string]interface{}{
+			"admin_user_id":     adminUserID,
+			"rotation_strategy": strategy,
+			"last_entry_id":     0,
+			"last_rotated_at":   nil,
+		}).Error
+	})
+}
+
+// UpdateLastEntry 记录本次轮换使用的模板池条目和时间
+func (r *GormSendChatRepository) UpdateLastEntry(ctx context.Context, id, entryID int64, rotatedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.SendChat{ID: id}).Updates(map[string]interface{}{
+		"last_entry_id":   entryID,
+		"last_rotated_at": rotatedAt,
+	}).Error
+}
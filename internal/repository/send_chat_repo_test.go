@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
 	"gorm.io/driver/sqlite"
@@ -15,7 +17,7 @@ func setupSendChatTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&model.SendChat{}); err != nil {
+	if err := db.AutoMigrate(&model.SendChat{}, &model.SendChatTemplate{}); err != nil {
 		t.Fatalf("Failed to migrate: %v", err)
 	}
 
@@ -31,7 +33,7 @@ func TestSendChatRepository_Create(t *testing.T) {
 		ChatID: "123456789",
 	}
 
-	err := repo.Create(chat)
+	err := repo.Create(context.Background(), chat)
 	if err != nil {
 		t.Errorf("Create() error = %v", err)
 	}
@@ -53,7 +55,7 @@ func TestSendChatRepository_GetAll(t *testing.T) {
 	db.Create(&model.SendChat{ID: 2, ChatID: "222"})
 	db.Create(&model.SendChat{ID: 3, ChatID: "333"})
 
-	result, err := repo.GetAll()
+	result, err := repo.GetAll(context.Background())
 	if err != nil {
 		t.Errorf("GetAll() error = %v", err)
 	}
@@ -67,7 +69,7 @@ func TestSendChatRepository_GetAll_Empty(t *testing.T) {
 	db := setupSendChatTestDB(t)
 	repo := NewSendChatRepository(db)
 
-	result, err := repo.GetAll()
+	result, err := repo.GetAll(context.Background())
 	if err != nil {
 		t.Errorf("GetAll() error = %v", err)
 	}
@@ -84,7 +86,7 @@ func TestSendChatRepository_Delete(t *testing.T) {
 	// 插入测试数据
 	db.Create(&model.SendChat{ID: 1, ChatID: "123"})
 
-	err := repo.Delete(1)
+	err := repo.Delete(context.Background(), 1)
 	if err != nil {
 		t.Errorf("Delete() error = %v", err)
 	}
@@ -102,7 +104,7 @@ func TestSendChatRepository_Delete_NotFound(t *testing.T) {
 	repo := NewSendChatRepository(db)
 
 	// 删除不存在的记录不应该报错
-	err := repo.Delete(999)
+	err := repo.Delete(context.Background(), 999)
 	if err != nil {
 		t.Errorf("Delete() should not error for not found, got %v", err)
 	}
@@ -118,15 +120,98 @@ func TestSendChatRepository_CreateMultiple(t *testing.T) {
 			ID:     int64(i),
 			ChatID: fmt.Sprintf("%d", 100+i),
 		}
-		err := repo.Create(chat)
+		err := repo.Create(context.Background(), chat)
 		if err != nil {
 			t.Errorf("Create() error = %v for chat %d", err, i)
 		}
 	}
 
 	// 验证总数
-	result, _ := repo.GetAll()
+	result, _ := repo.GetAll(context.Background())
 	if len(result) != 5 {
 		t.Errorf("Expected 5 chats, got %d", len(result))
 	}
 }
+
+func TestSendChatRepository_GetByIDAndUpdateTemplate(t *testing.T) {
+	db := setupSendChatTestDB(t)
+	repo := NewSendChatRepository(db)
+
+	db.Create(&model.SendChat{ID: 1, ChatID: "123"})
+
+	if err := repo.UpdateTemplate(context.Background(), 1, 42); err != nil {
+		t.Fatalf("UpdateTemplate() error = %v", err)
+	}
+	chat, err := repo.GetByID(context.Background(), 1)
+	if err != nil || chat == nil || chat.ChatID != "123" || chat.TemplateID != 42 {
+		t.Errorf("GetByID(1) = %+v, %v", chat, err)
+	}
+
+	// 恢复默认模板
+	_ = repo.UpdateTemplate(context.Background(), 1, 0)
+	if chat, _ := repo.GetByID(context.Background(), 1); chat == nil || chat.TemplateID != 0 {
+		t.Errorf("GetByID(1) after reset = %+v", chat)
+	}
+
+	if chat, err := repo.GetByID(context.Background(), 999); err != nil || chat != nil {
+		t.Errorf("GetByID(999) = %+v, %v, want nil", chat, err)
+	}
+}
+
+func TestSendChatRepository_Rotation(t *testing.T) {
+	db := setupSendChatTestDB(t)
+	repo := NewSendChatRepository(db)
+	ctx := context.Background()
+
+	chat := &model.SendChat{ChatID: "-100123"}
+	other := &model.SendChat{ChatID: "-100456"}
+	_ = repo.Create(ctx, chat)
+	_ = repo.Create(ctx, other)
+
+	if err := repo.UpdateRotation(ctx, other.ID, 0, model.RotationRandom, []*model.SendChatTemplate{
+		{ID: 10, SendChatID: other.ID, Source: model.ChatTemplateSourceOfficial, TemplateID: 1},
+	}); err != nil {
+		t.Fatalf("UpdateRotation() error = %v", err)
+	}
+	if err := repo.UpdateRotation(ctx, chat.ID, 123, model.RotationRoundRobin, []*model.SendChatTemplate{
+		{ID: 2, SendChatID: chat.ID, Source: model.ChatTemplateSourceUser, TemplateID: 5, SortOrder: 1},
+		{ID: 1, SendChatID: chat.ID, Source: model.ChatTemplateSourceOfficial, TemplateID: 1, SortOrder: 0},
+	}); err != nil {
+		t.Fatalf("UpdateRotation() error = %v", err)
+	}
+
+	pool, err := repo.GetTemplatePool(ctx, chat.ID)
+	if err != nil || len(pool) != 2 || pool[0].ID != 1 || pool[1].ID != 2 {
+		t.Fatalf("GetTemplatePool() = %+v, %v", pool, err)
+	}
+	if all, _ := repo.GetAllTemplatePools(ctx); len(all) != 3 || all[0].SendChatID != chat.ID || all[2].SendChatID != other.ID {
+		t.Errorf("GetAllTemplatePools() = %+v", all)
+	}
+
+	rotatedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := repo.UpdateLastEntry(ctx, chat.ID, 2, rotatedAt); err != nil {
+		t.Fatalf("UpdateLastEntry() error = %v", err)
+	}
+	got, _ := repo.GetByID(ctx, chat.ID)
+	if got.AdminUserID != 123 || got.RotationStrategy != model.RotationRoundRobin || got.LastEntryID != 2 || got.LastRotatedAt == nil || !got.LastRotatedAt.Equal(rotatedAt) {
+		t.Errorf("GetByID() after UpdateLastEntry = %+v", got)
+	}
+
+	// 替换模板池时清空上次使用的条目
+	if err := repo.UpdateRotation(ctx, chat.ID, 0, "", nil); err != nil {
+		t.Fatalf("UpdateRotation() error = %v", err)
+	}
+	got, _ = repo.GetByID(ctx, chat.ID)
+	if got.AdminUserID != 0 || got.RotationStrategy != "" || got.LastEntryID != 0 || got.LastRotatedAt != nil {
+		t.Errorf("GetByID() after reset = %+v", got)
+	}
+	if pool, _ := repo.GetTemplatePool(ctx, chat.ID); len(pool) != 0 {
+		t.Errorf("GetTemplatePool() after reset = %+v", pool)
+	}
+
+	// 删除对话时一并删除模板池
+	_ = repo.Delete(ctx, other.ID)
+	if all, _ := repo.GetAllTemplatePools(ctx); len(all) != 0 {
+		t.Errorf("GetAllTemplatePools() after delete = %+v", all)
+	}
+}
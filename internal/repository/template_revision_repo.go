@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+// GormTemplateRevisionRepository 基于 GORM 的模板修订记录仓储
+type GormTemplateRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewTemplateRevisionRepository 创建模板修订记录仓储
+func NewTemplateRevisionRepository(db *gorm.DB) *GormTemplateRevisionRepository {
+	return &GormTemplateRevisionRepository{db: db}
+}
+
+// GetByTemplateID 获取模板的修订记录，最新的在前
+func (r *GormTemplateRevisionRepository) GetByTemplateID(ctx context.Context, templateID int64) ([]model.TemplateRevision, error) {
+	var revisions []model.TemplateRevision
+
+	// 雪花 ID 随时间递增，按 ID 倒序即按修改时间倒序
+	err := r.db.WithContext(ctx).
+		Where("template_id = ?", templateID).
+		Order("id DESC").
+		Find(&revisions).Error
+
+	return revisions, err
+}
+
+// GetByID 根据ID获取修订记录
+func (r *GormTemplateRevisionRepository) GetByID(ctx context.Context, id int64) (*model.TemplateRevision, error) {
+	var revision model.TemplateRevision
+
+	err := r.db.WithContext(ctx).First(&revision, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// Create 创建修订记录，并删除超出 maxPerTemplate 条的最早记录
+func (r *GormTemplateRevisionRepository) Create(ctx context.Context, revision *model.TemplateRevision, maxPerTemplate int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+
+		var stale []int64
+		if err := tx.Model(&model.TemplateRevision{}).
+			Where("template_id = ?", revision.TemplateID).
+			Order("id DESC").
+			Offset(maxPerTemplate).
+			Pluck("id", &stale).Error; err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		return tx.Where("id IN ?", stale).Delete(&model.TemplateRevision{}).Error
+	})
+}
+
+// DeleteByTemplateID 删除模板的全部修订记录
+func (r *GormTemplateRevisionRepository) DeleteByTemplateID(ctx context.Context, templateID int64) error {
+	return r.db.WithContext(ctx).Where("template_id = ?", templateID).Delete(&model.TemplateRevision{}).Error
+}
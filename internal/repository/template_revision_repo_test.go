@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestTemplateRevisionRepository(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.TemplateRevision{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	repo := NewTemplateRevisionRepository(db)
+	ctx := context.Background()
+
+	// 超出上限时删除最早的记录，不影响其他模板
+	for i := int64(1); i <= 4; i++ {
+		if err := repo.Create(ctx, &model.TemplateRevision{ID: i, TemplateID: 10, UserID: 123, TemplateContent: "内容"}, 3); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	_ = repo.Create(ctx, &model.TemplateRevision{ID: 5, TemplateID: 20, UserID: 123, TemplateContent: "内容"}, 3)
+
+	revisions, err := repo.GetByTemplateID(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetByTemplateID() error = %v", err)
+	}
+	if len(revisions) != 3 || revisions[0].ID != 4 || revisions[2].ID != 2 {
+		t.Errorf("GetByTemplateID() = %+v, want revisions 4, 3, 2", revisions)
+	}
+
+	if revision, err := repo.GetByID(ctx, 1); err != nil || revision != nil {
+		t.Errorf("GetByID() pruned = %+v, %v, want nil", revision, err)
+	}
+	if revision, _ := repo.GetByID(ctx, 5); revision == nil || revision.TemplateID != 20 {
+		t.Errorf("GetByID() = %+v, want revision 5", revision)
+	}
+
+	if err := repo.DeleteByTemplateID(ctx, 10); err != nil {
+		t.Fatalf("DeleteByTemplateID() error = %v", err)
+	}
+	if revisions, _ := repo.GetByTemplateID(ctx, 10); len(revisions) != 0 {
+		t.Errorf("GetByTemplateID() after delete = %+v", revisions)
+	}
+	if revisions, _ := repo.GetByTemplateID(ctx, 20); len(revisions) != 1 {
+		t.Errorf("DeleteByTemplateID() should not affect other templates, got %+v", revisions)
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+// ErrTargetLimitExceeded 自定义目标数量超过限制错误
+var ErrTargetLimitExceeded = errors.New("target limit exceeded")
+
+// GormUserTargetRepository 基于 GORM 的用户自定义目标仓储
+type GormUserTargetRepository struct {
+	db    *gorm.DB
+	reads ReadRouter
+}
+
+// NewUserTargetRepository 创建用户自定义目标仓储
+func NewUserTargetRepository(db *gorm.DB) *GormUserTargetRepository {
+	return &GormUserTargetRepository{db: db}
+}
+
+// WithReadReplicas 将列表查询路由到只读副本
+func (r *GormUserTargetRepository) WithReadReplicas(router ReadRouter) *GormUserTargetRepository {
+	r.reads = router
+	return r
+}
+
+// GetByUserID 根据用户ID获取目标列表，按目标时间排序
+func (r *GormUserTargetRepository) GetByUserID(ctx context.Context, userID int64) ([]model.UserTarget, error) {
+	var targets []model.UserTarget
+
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		return db.Where("user_id = ?", userID).Order("target_date ASC").Find(&targets).Error
+	})
+
+	return targets, err
+}
+
+// GetByID 根据ID获取目标
+func (r *GormUserTargetRepository) GetByID(ctx context.Context, id int64) (*model.UserTarget, error) {
+	var target model.UserTarget
+
+	err := r.db.WithContext(ctx).First(&target, id).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+
+	return &target, err
+}
+
+// Delete 删除目标
+func (r *GormUserTargetRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.UserTarget{}, id).Error
+}
+
+// CreateWithLimit 在事务中原子地检查数量限制并创建目标
+func (r *GormUserTargetRepository) CreateWithLimit(ctx context.Context, target *model.UserTarget, maxLimit int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockUserScope(tx, model.UserTarget{}.TableName(), target.UserID); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&model.UserTarget{}).
+			Where("user_id = ?", target.UserID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count >= maxLimit {
+			return ErrTargetLimitExceeded
+		}
+
+		return tx.Create(target).Error
+	})
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupUserTargetTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&model.UserTarget{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+func TestUserTargetRepository_GetByUserID(t *testing.T) {
+	db := setupUserTargetTestDB(t)
+	repo := NewUserTargetRepository(db)
+
+	now := time.Now()
+	db.Create(&model.UserTarget{ID: 1, UserID: 123, TargetName: "二模", TargetDate: now.AddDate(0, 2, 0)})
+	db.Create(&model.UserTarget{ID: 2, UserID: 123, TargetName: "一模", TargetDate: now.AddDate(0, 1, 0)})
+	db.Create(&model.UserTarget{ID: 3, UserID: 456, TargetName: "其他", TargetDate: now})
+
+	targets, err := repo.GetByUserID(context.Background(), 123)
+	if err != nil {
+		t.Fatalf("GetByUserID() error = %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("Expected 2 targets, got %d", len(targets))
+	}
+	if targets[0].TargetName != "一模" {
+		t.Errorf("Expected targets ordered by date, first = %s", targets[0].TargetName)
+	}
+}
+
+func TestUserTargetRepository_GetByID(t *testing.T) {
+	db := setupUserTargetTestDB(t)
+	repo := NewUserTargetRepository(db)
+
+	db.Create(&model.UserTarget{ID: 1, UserID: 123, TargetName: "一模", TargetDate: time.Now()})
+
+	target, err := repo.GetByID(context.Background(), 1)
+	if err != nil || target == nil || target.TargetName != "一模" {
+		t.Errorf("GetByID(1) = %+v, %v", target, err)
+	}
+
+	target, err = repo.GetByID(context.Background(), 999)
+	if err != nil || target != nil {
+		t.Errorf("GetByID(999) = %+v, %v, want nil, nil", target, err)
+	}
+}
+
+func TestUserTargetRepository_Delete(t *testing.T) {
+	db := setupUserTargetTestDB(t)
+	repo := NewUserTargetRepository(db)
+
+	db.Create(&model.UserTarget{ID: 1, UserID: 123, TargetName: "一模", TargetDate: time.Now()})
+
+	if err := repo.Delete(context.Background(), 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	var count int64
+	db.Model(&model.UserTarget{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected 0 targets after delete, got %d", count)
+	}
+}
+
+func TestUserTargetRepository_CreateWithLimit(t *testing.T) {
+	db := setupUserTargetTestDB(t)
+	repo := NewUserTargetRepository(db)
+
+	for i := int64(1); i <= 2; i++ {
+		target := &model.UserTarget{ID: i, UserID: 123, TargetName: "目标", TargetDate: time.Now()}
+		if err := repo.CreateWithLimit(context.Background(), target, 2); err != nil {
+			t.Fatalf("CreateWithLimit() #%d error = %v", i, err)
+		}
+	}
+
+	err := repo.CreateWithLimit(context.Background(), &model.UserTarget{ID: 3, UserID: 123, TargetName: "目标", TargetDate: time.Now()}, 2)
+	if !errors.Is(err, ErrTargetLimitExceeded) {
+		t.Errorf("Expected ErrTargetLimitExceeded, got %v", err)
+	}
+
+	// 其他用户不受影响
+	if err := repo.CreateWithLimit(context.Background(), &model.UserTarget{ID: 4, UserID: 456, TargetName: "目标", TargetDate: time.Now()}, 2); err != nil {
+		t.Errorf("CreateWithLimit() for other user error = %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
@@ -10,30 +11,45 @@ import (
 // ErrTemplateLimitExceeded 模板数量超过限制错误
 var ErrTemplateLimitExceeded = errors.New("template limit exceeded")
 
-// UserTemplateRepository 用户模板仓储
-type UserTemplateRepository struct {
-	db *gorm.DB
+// GormUserTemplateRepository 基于 GORM 的用户模板仓储
+type GormUserTemplateRepository struct {
+	db    *gorm.DB
+	reads ReadRouter
 }
 
 // NewUserTemplateRepository 创建用户模板仓储
-func NewUserTemplateRepository(db *gorm.DB) *UserTemplateRepository {
-	return &UserTemplateRepository{db: db}
+func NewUserTemplateRepository(db *gorm.DB) *GormUserTemplateRepository {
+	return &GormUserTemplateRepository{db: db}
 }
 
-// GetByUserID 根据用户ID获取模板列表
-func (r *UserTemplateRepository) GetByUserID(userID int64) ([]model.UserTemplate, error) {
+// WithReadReplicas 将列表查询路由到只读副本
+func (r *GormUserTemplateRepository) WithReadReplicas(router ReadRouter) *GormUserTemplateRepository {
+	r.reads = router
+	return r
+}
+
+// GetByUserID 根据用户ID获取模板列表，按收藏优先、排序位置、ID 排序
+func (r *GormUserTemplateRepository) GetByUserID(ctx context.Context, userID int64) ([]model.UserTemplate, error) {
 	var templates []model.UserTemplate
 
-	err := r.db.Where("user_id = ?", userID).Find(&templates).Error
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		return db.Where("user_id = ?", userID).
+			Order("is_favorite DESC").
+			Order("sort_order ASC").
+			Order("id ASC").
+			Find(&templates).Error
+	})
 
 	return templates, err
 }
 
 // GetDefaultTemplate 获取默认模板
-func (r *UserTemplateRepository) GetDefaultTemplate() (*model.UserTemplate, error) {
+func (r *GormUserTemplateRepository) GetDefaultTemplate(ctx context.Context) (*model.UserTemplate, error) {
 	var template model.UserTemplate
 
-	err := r.db.Where("user_id = ?", 0).First(&template).Error
+	err := readOnly(ctx, r.db, r.reads, func(db *gorm.DB) error {
+		return db.Where("user_id = ?", 0).First(&template).Error
+	})
 
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
@@ -43,25 +59,25 @@ func (r *UserTemplateRepository) GetDefaultTemplate() (*model.UserTemplate, erro
 }
 
 // Create 创建模板
-func (r *UserTemplateRepository) Create(template *model.UserTemplate) error {
-	return r.db.Create(template).Error
+func (r *GormUserTemplateRepository) Create(ctx context.Context, template *model.UserTemplate) error {
+	return r.db.WithContext(ctx).Create(template).Error
 }
 
 // Update 更新模板
-func (r *UserTemplateRepository) Update(template *model.UserTemplate) error {
-	return r.db.Save(template).Error
+func (r *GormUserTemplateRepository) Update(ctx context.Context, template *model.UserTemplate) error {
+	return r.db.WithContext(ctx).Save(template).Error
 }
 
 // Delete 删除模板
-func (r *UserTemplateRepository) Delete(id int64) error {
-	return r.db.Delete(&model.UserTemplate{}, id).Error
+func (r *GormUserTemplateRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.UserTemplate{}, id).Error
 }
 
 // GetByID 根据ID获取模板
-func (r *UserTemplateRepository) GetByID(id int64) (*model.UserTemplate, error) {
+func (r *GormUserTemplateRepository) GetByID(ctx context.Context, id int64) (*model.UserTemplate, error) {
 	var template model.UserTemplate
 
-	err := r.db.First(&template, id).Error
+	err := r.db.WithContext(ctx).First(&template, id).Error
 
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
@@ -70,19 +86,77 @@ func (r *UserTemplateRepository) GetByID(id int64) (*model.UserTemplate, error)
 	return &template, err
 }
 
+// GetByShareCode 根据分享码获取模板
+func (r *GormUserTemplateRepository) GetByShareCode(ctx context.Context, code string) (*model.UserTemplate, error) {
+	var template model.UserTemplate
+
+	err := r.db.WithContext(ctx).Where("share_code = ?", code).First(&template).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+
+	return &template, err
+}
+
+// UpdateShareCode 仅更新模板的分享码，code 为 nil 时写入 NULL
+func (r *GormUserTemplateRepository) UpdateShareCode(ctx context.Context, id int64, code *string) error {
+	return r.db.WithContext(ctx).Model(&model.UserTemplate{ID: id}).Update("share_code", code).Error
+}
+
+// UpdateSortOrders 在事务中按 ids 的顺序设置排序位置，仅更新属于该用户的模板
+func (r *GormUserTemplateRepository) UpdateSortOrders(ctx context.Context, userID int64, ids []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&model.UserTemplate{}).
+				Where("id = ? AND user_id = ?", id, userID).
+				Update("sort_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateFavorite 仅更新模板的收藏状态
+func (r *GormUserTemplateRepository) UpdateFavorite(ctx context.Context, id int64, favorite bool) error {
+	return r.db.WithContext(ctx).Model(&model.UserTemplate{ID: id}).Update("is_favorite", favorite).Error
+}
+
+// SetUserDefault 在事务中设置用户的个人默认模板，保证每个用户最多一个
+func (r *GormUserTemplateRepository) SetUserDefault(ctx context.Context, userID, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.UserTemplate{}).
+			Where("user_id = ? AND is_default = ?", userID, true).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		if id == 0 {
+			return nil
+		}
+		return tx.Model(&model.UserTemplate{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("is_default", true).Error
+	})
+}
+
 // CountByUserID 统计用户的模板数量
-func (r *UserTemplateRepository) CountByUserID(userID int64) (int64, error) {
+func (r *GormUserTemplateRepository) CountByUserID(ctx context.Context, userID int64) (int64, error) {
 	var count int64
-	err := r.db.Model(&model.UserTemplate{}).Where("user_id = ?", userID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.UserTemplate{}).Where("user_id = ?", userID).Count(&count).Error
 	return count, err
 }
 
 // CreateWithLimit 在事务中原子地检查数量限制并创建模板
-// 使用数据库事务确保并发安全，防止 TOCTOU 竞态条件
-func (r *UserTemplateRepository) CreateWithLimit(template *model.UserTemplate, maxLimit int64) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+// 使用数据库事务并按驱动加锁确保并发安全，防止 TOCTOU 竞态条件
+func (r *GormUserTemplateRepository) CreateWithLimit(ctx context.Context, template *model.UserTemplate, maxLimit int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定该用户的模板范围，防止并发创建绕过数量限制
+		if err := lockUserScope(tx, model.UserTemplate{}.TableName(), template.UserID); err != nil {
+			return err
+		}
+
 		// 在事务中统计该用户的模板数量
-		// 事务隔离级别确保并发操作的一致性
 		var count int64
 		if err := tx.Model(&model.UserTemplate{}).
 			Where("user_id = ?", template.UserID).
@@ -96,9 +170,34 @@ func (r *UserTemplateRepository) CreateWithLimit(template *model.UserTemplate, m
 		}
 
 		// 在限制内，创建模板
-		// 注意：依赖数据库的事务隔离来防止并发问题
-		// 对于 PostgreSQL/MySQL，可以考虑添加 FOR UPDATE 锁
-		// 但为了兼容 SQLite，这里使用标准的事务方式
 		return tx.Create(template).Error
 	})
+}
+
+// CreateBatchWithLimit 在事务中原子地检查数量限制并批量创建用户的模板
+// 创建后的总数超过限制时不创建任何模板，返回 ErrTemplateLimitExceeded
+func (r *GormUserTemplateRepository) CreateBatchWithLimit(ctx context.Context, userID int64, templates []*model.UserTemplate, maxLimit int64) error {
+	if len(templates) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 与 CreateWithLimit 使用同一把锁，防止并发创建绕过数量限制
+		if err := lockUserScope(tx, model.UserTemplate{}.TableName(), userID); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&model.UserTemplate{}).
+			Where("user_id = ?", userID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count+int64(len(templates)) > maxLimit {
+			return ErrTemplateLimitExceeded
+		}
+
+		return tx.Create(templates).Error
+	})
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"testing"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
@@ -33,7 +34,7 @@ func TestUserTemplateRepository_Create(t *testing.T) {
 		TemplateContent: "距离{exam}还有{time}",
 	}
 
-	err := repo.Create(template)
+	err := repo.Create(context.Background(), template)
 	if err != nil {
 		t.Errorf("Create() error = %v", err)
 	}
@@ -60,7 +61,7 @@ func TestUserTemplateRepository_GetByID(t *testing.T) {
 	db.Create(template)
 
 	// 测试获取
-	result, err := repo.GetByID(1)
+	result, err := repo.GetByID(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetByID() error = %v", err)
 	}
@@ -79,7 +80,7 @@ func TestUserTemplateRepository_GetByID_NotFound(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewUserTemplateRepository(db)
 
-	result, err := repo.GetByID(999)
+	result, err := repo.GetByID(context.Background(), 999)
 	if err != nil {
 		t.Errorf("GetByID() should not return error for not found, got %v", err)
 	}
@@ -115,7 +116,7 @@ func TestUserTemplateRepository_GetByUserID(t *testing.T) {
 		TemplateContent: "距离{exam}还有{time}",
 	})
 
-	result, err := repo.GetByUserID(userID)
+	result, err := repo.GetByUserID(context.Background(), userID)
 	if err != nil {
 		t.Errorf("GetByUserID() error = %v", err)
 	}
@@ -142,7 +143,7 @@ func TestUserTemplateRepository_Update(t *testing.T) {
 	template.TemplateName = "新模板"
 	template.TemplateContent = "{exam}倒计时{time}"
 
-	err := repo.Update(template)
+	err := repo.Update(context.Background(), template)
 	if err != nil {
 		t.Errorf("Update() error = %v", err)
 	}
@@ -167,7 +168,7 @@ func TestUserTemplateRepository_Delete(t *testing.T) {
 		TemplateContent: "距离{exam}还有{time}",
 	})
 
-	err := repo.Delete(1)
+	err := repo.Delete(context.Background(), 1)
 	if err != nil {
 		t.Errorf("Delete() error = %v", err)
 	}
@@ -196,7 +197,7 @@ func TestUserTemplateRepository_CountByUserID(t *testing.T) {
 		})
 	}
 
-	count, err := repo.CountByUserID(userID)
+	count, err := repo.CountByUserID(context.Background(), userID)
 	if err != nil {
 		t.Errorf("CountByUserID() error = %v", err)
 	}
@@ -218,7 +219,7 @@ func TestUserTemplateRepository_GetDefaultTemplate(t *testing.T) {
 		TemplateContent: "距离{exam}还有{time}",
 	})
 
-	result, err := repo.GetDefaultTemplate()
+	result, err := repo.GetDefaultTemplate(context.Background())
 	if err != nil {
 		t.Errorf("GetDefaultTemplate() error = %v", err)
 	}
@@ -232,3 +233,129 @@ func TestUserTemplateRepository_GetDefaultTemplate(t *testing.T) {
 		t.Errorf("Expected UserID = 0, got %d", result.UserID)
 	}
 }
+
+func TestUserTemplateRepository_ShareCode(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserTemplateRepository(db)
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: 123, TemplateContent: "{exam}{time}"})
+
+	if template, err := repo.GetByShareCode(ctx, "abcdefgh"); err != nil || template != nil {
+		t.Errorf("GetByShareCode() before share = %+v, %v, want nil", template, err)
+	}
+
+	code := "abcdefgh"
+	if err := repo.UpdateShareCode(ctx, 1, &code); err != nil {
+		t.Fatalf("UpdateShareCode() error = %v", err)
+	}
+	template, err := repo.GetByShareCode(ctx, code)
+	if err != nil || template == nil || template.ID != 1 {
+		t.Fatalf("GetByShareCode() = %+v, %v, want template 1", template, err)
+	}
+
+	// 分享码唯一
+	if err := repo.UpdateShareCode(ctx, 2, &code); err == nil {
+		t.Error("UpdateShareCode() with duplicate code should fail")
+	}
+
+	// 多个未分享的模板（NULL）不违反唯一约束
+	if err := repo.UpdateShareCode(ctx, 1, nil); err != nil {
+		t.Fatalf("UpdateShareCode(nil) error = %v", err)
+	}
+	if template, _ := repo.GetByShareCode(ctx, code); template != nil {
+		t.Errorf("GetByShareCode() after revoke = %+v, want nil", template)
+	}
+	if template, _ := repo.GetByID(ctx, 1); template.ShareCode != nil {
+		t.Errorf("ShareCode after revoke = %v, want nil", *template.ShareCode)
+	}
+}
+
+func TestUserTemplateRepository_OrderFavoriteDefault(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserTemplateRepository(db)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		db.Create(&model.UserTemplate{ID: i, UserID: 123, TemplateContent: "距离{exam}还有{time}"})
+	}
+	db.Create(&model.UserTemplate{ID: 9, UserID: 456, TemplateContent: "{exam}{time}"})
+
+	ids := func() []int64 {
+		templates, err := repo.GetByUserID(ctx, 123)
+		if err != nil {
+			t.Fatalf("GetByUserID() error = %v", err)
+		}
+		result := make([]int64, len(templates))
+		for i, template := range templates {
+			result[i] = template.ID
+		}
+		return result
+	}
+
+	if err := repo.UpdateSortOrders(ctx, 123, []int64{3, 1, 2}); err != nil {
+		t.Fatalf("UpdateSortOrders() error = %v", err)
+	}
+	if got := ids(); got[0] != 3 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("GetByUserID() order = %v, want [3 1 2]", got)
+	}
+
+	// 收藏的模板排在最前
+	if err := repo.UpdateFavorite(ctx, 2, true); err != nil {
+		t.Fatalf("UpdateFavorite() error = %v", err)
+	}
+	if got := ids(); got[0] != 2 || got[1] != 3 || got[2] != 1 {
+		t.Errorf("GetByUserID() order after favorite = %v, want [2 3 1]", got)
+	}
+
+	// 每个用户只能有一个默认模板，其他用户不受影响
+	_ = repo.SetUserDefault(ctx, 456, 9)
+	_ = repo.SetUserDefault(ctx, 123, 1)
+	if err := repo.SetUserDefault(ctx, 123, 3); err != nil {
+		t.Fatalf("SetUserDefault() error = %v", err)
+	}
+	var defaults []model.UserTemplate
+	db.Where("is_default = ?", true).Order("id").Find(&defaults)
+	if len(defaults) != 2 || defaults[0].ID != 3 || defaults[1].ID != 9 {
+		t.Errorf("defaults = %+v, want templates 3 and 9", defaults)
+	}
+
+	if err := repo.SetUserDefault(ctx, 123, 0); err != nil {
+		t.Fatalf("SetUserDefault(0) error = %v", err)
+	}
+	if template, _ := repo.GetByID(ctx, 3); template.IsDefault {
+		t.Error("SetUserDefault(0) should clear the personal default")
+	}
+}
+
+func TestUserTemplateRepository_CreateBatchWithLimit(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserTemplateRepository(db)
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateContent: "{exam}{time}"})
+
+	// 超出限制时全部不创建
+	batch := []*model.UserTemplate{
+		{ID: 2, UserID: 123, TemplateContent: "a{exam}{time}"},
+		{ID: 3, UserID: 123, TemplateContent: "b{exam}{time}"},
+	}
+	if err := repo.CreateBatchWithLimit(ctx, 123, batch, 2); err != ErrTemplateLimitExceeded {
+		t.Errorf("CreateBatchWithLimit() error = %v, want ErrTemplateLimitExceeded", err)
+	}
+	if count, _ := repo.CountByUserID(ctx, 123); count != 1 {
+		t.Errorf("CountByUserID() after rejected batch = %d, want 1", count)
+	}
+
+	if err := repo.CreateBatchWithLimit(ctx, 123, batch, 3); err != nil {
+		t.Fatalf("CreateBatchWithLimit() error = %v", err)
+	}
+	if count, _ := repo.CountByUserID(ctx, 123); count != 3 {
+		t.Errorf("CountByUserID() = %d, want 3", count)
+	}
+
+	if err := repo.CreateBatchWithLimit(ctx, 123, nil, 3); err != nil {
+		t.Errorf("CreateBatchWithLimit() empty batch error = %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"gorm.io/gorm"
+)
+
+// ErrVariableLimitExceeded 自定义变量数量超过限制错误
+var ErrVariableLimitExceeded = errors.New("variable limit exceeded")
+
+// GormUserVariableRepository 基于 GORM 的用户自定义模板变量仓储
+type GormUserVariableRepository struct {
+	db *gorm.DB
+}
+
+// NewUserVariableRepository 创建用户自定义模板变量仓储
+func NewUserVariableRepository(db *gorm.DB) *GormUserVariableRepository {
+	return &GormUserVariableRepository{db: db}
+}
+
+// GetByUserID 根据用户ID获取变量列表，按名称排序
+func (r *GormUserVariableRepository) GetByUserID(ctx context.Context, userID int64) ([]model.UserVariable, error) {
+	var variables []model.UserVariable
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("var_name ASC").
+		Find(&variables).Error
+
+	return variables, err
+}
+
+// SaveWithLimit 在事务中新增或更新变量，新增时原子地检查数量限制
+func (r *GormUserVariableRepository) SaveWithLimit(ctx context.Context, variable *model.UserVariable, maxLimit int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockUserScope(tx, model.UserVariable{}.TableName(), variable.UserID); err != nil {
+			return err
+		}
+
+		var existing model.UserVariable
+		err := tx.Where("user_id = ? AND var_name = ?", variable.UserID, variable.VarName).First(&existing).Error
+		if err == nil {
+			variable.ID = existing.ID
+			variable.CreatedAt = existing.CreatedAt
+			return tx.Model(variable).Update("var_value", variable.VarValue).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&model.UserVariable{}).
+			Where("user_id = ?", variable.UserID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count >= maxLimit {
+			return ErrVariableLimitExceeded
+		}
+
+		return tx.Create(variable).Error
+	})
+}
+
+// Delete 删除用户的指定变量
+func (r *GormUserVariableRepository) Delete(ctx context.Context, userID int64, name string) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND var_name = ?", userID, name).
+		Delete(&model.UserVariable{}).Error
+}
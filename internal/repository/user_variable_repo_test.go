@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestUserVariableRepository(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.UserVariable{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	repo := NewUserVariableRepository(db)
+	ctx := context.Background()
+
+	for i, name := range []string{"goal", "name"} {
+		variable := &model.UserVariable{ID: int64(i + 1), UserID: 123, VarName: name, VarValue: "值"}
+		if err := repo.SaveWithLimit(ctx, variable, 2); err != nil {
+			t.Fatalf("SaveWithLimit(%s) error = %v", name, err)
+		}
+	}
+
+	// 其他用户的变量不受影响
+	if err := repo.SaveWithLimit(ctx, &model.UserVariable{ID: 3, UserID: 456, VarName: "name", VarValue: "小红"}, 2); err != nil {
+		t.Fatalf("SaveWithLimit() for another user error = %v", err)
+	}
+
+	// 达到上限后不能新增，但可以更新已有变量
+	if err := repo.SaveWithLimit(ctx, &model.UserVariable{ID: 4, UserID: 123, VarName: "school", VarValue: "一中"}, 2); !errors.Is(err, ErrVariableLimitExceeded) {
+		t.Errorf("SaveWithLimit() over limit error = %v, want ErrVariableLimitExceeded", err)
+	}
+	updated := &model.UserVariable{ID: 5, UserID: 123, VarName: "name", VarValue: "小明"}
+	if err := repo.SaveWithLimit(ctx, updated, 2); err != nil {
+		t.Fatalf("SaveWithLimit() update error = %v", err)
+	}
+	if updated.ID != 2 || updated.CreatedAt.IsZero() {
+		t.Errorf("SaveWithLimit() update should write back existing ID, got %+v", updated)
+	}
+
+	variables, err := repo.GetByUserID(ctx, 123)
+	if err != nil {
+		t.Fatalf("GetByUserID() error = %v", err)
+	}
+	if len(variables) != 2 || variables[0].VarName != "goal" || variables[1].VarValue != "小明" {
+		t.Errorf("GetByUserID() = %+v", variables)
+	}
+
+	if err := repo.Delete(ctx, 123, "goal"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if variables, _ := repo.GetByUserID(ctx, 123); len(variables) != 1 {
+		t.Errorf("GetByUserID() after delete = %+v", variables)
+	}
+	if variables, _ := repo.GetByUserID(ctx, 456); len(variables) != 1 {
+		t.Errorf("Delete() removed another user's variable: %+v", variables)
+	}
+}
@@ -2,9 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
-	"time"
 	"unicode/utf8"
 
 	"github.com/herbertgao/gaokao_bot/internal/util"
@@ -15,9 +16,6 @@ import (
 )
 
 const (
-	// DefaultContextTimeout 默认上下文超时时间
-	DefaultContextTimeout = 10 * time.Second
-
 	// InlineQueryCacheTime inline query 结果缓存时间（秒）
 	// 设置为 1 秒以确保倒计时始终显示最新时间
 	InlineQueryCacheTime = 1
@@ -25,11 +23,13 @@ const (
 
 // BotService Bot业务服务
 type BotService struct {
-	bot                *telego.Bot
-	messageService     *MessageService
-	inlineQueryService *InlineQueryService
-	logger             *logrus.Logger
-	miniAppURL         string
+	bot                 *telego.Bot
+	messageService      *MessageService
+	inlineQueryService  *InlineQueryService
+	calendarFeedService *CalendarFeedService
+	templateChatService *TemplateChatService
+	logger              *logrus.Logger
+	miniAppURL          string
 }
 
 // NewBotService 创建Bot业务服务
@@ -37,20 +37,25 @@ func NewBotService(
 	bot *telego.Bot,
 	messageService *MessageService,
 	inlineQueryService *InlineQueryService,
+	calendarFeedService *CalendarFeedService,
+	templateChatService *TemplateChatService,
 	logger *logrus.Logger,
 	miniAppURL string,
 ) *BotService {
 	return &BotService{
-		bot:                bot,
-		messageService:     messageService,
-		inlineQueryService: inlineQueryService,
-		logger:             logger,
-		miniAppURL:         miniAppURL,
+		bot:                 bot,
+		messageService:      messageService,
+		inlineQueryService:  inlineQueryService,
+		calendarFeedService: calendarFeedService,
+		templateChatService: templateChatService,
+		logger:              logger,
+		miniAppURL:          miniAppURL,
 	}
 }
 
 // HandleMessage 处理消息
-func (s *BotService) HandleMessage(bot *telego.Bot, msg *telego.Message) {
+// ctx 控制查询与回复的超时，由 Bot 按配置的消息处理超时创建，关闭时被取消
+func (s *BotService) HandleMessage(ctx context.Context, bot *telego.Bot, msg *telego.Message) {
 	if msg == nil {
 		return
 	}
@@ -62,21 +67,36 @@ func (s *BotService) HandleMessage(bot *telego.Bot, msg *telego.Message) {
 
 	// 处理命令（检查是否以 / 开头）
 	if strings.HasPrefix(msg.Text, "/") {
-		s.handleCommand(msg)
+		s.handleCommand(ctx, msg)
 		return
 	}
+
+	// 私聊中正在创建或编辑模板时，普通消息作为模板内容
+	if s.templateChatService != nil && util.IsUserChat(&msg.Chat) {
+		response, handled, err := s.templateChatService.HandleText(ctx, msg.Chat.ID, msg.Text)
+		if !handled {
+			return
+		}
+		if err != nil {
+			s.logger.Errorf("处理模板消息错误: %v", err)
+			response = "处理消息时出错，请稍后重试"
+		}
+		s.reply(ctx, msg, response)
+	}
 }
 
 // HandleInlineQuery 处理内联查询
-func (s *BotService) HandleInlineQuery(bot *telego.Bot, query *telego.InlineQuery) {
+// ctx 需早于 Telegram 的应答时限到期，超时后放弃查询，不再回复已失效的查询
+func (s *BotService) HandleInlineQuery(ctx context.Context, bot *telego.Bot, query *telego.InlineQuery) {
 	if query == nil {
 		return
 	}
 
-	results := s.inlineQueryService.GetInlineQueryResults(query)
-
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
-	defer cancel()
+	results := s.inlineQueryService.GetInlineQueryResults(ctx, query)
+	if ctx.Err() != nil {
+		s.logger.Warnf("内联查询 (ID: %s) 处理%s，放弃回复", query.ID, getContextErrorMessage(ctx.Err()))
+		return
+	}
 
 	err := s.bot.AnswerInlineQuery(ctx, &telego.AnswerInlineQueryParams{
 		InlineQueryID: query.ID,
@@ -95,14 +115,14 @@ func (s *BotService) HandleInlineQuery(bot *telego.Bot, query *telego.InlineQuer
 }
 
 // HandleGuestMessage 处理 Guest 模式消息
-// Bot 在非成员聊天中被 @提及或被回复时收到，仅以默认模板的倒计时应答一次。
-func (s *BotService) HandleGuestMessage(bot *telego.Bot, msg *telego.Message) {
+// Bot 在非成员聊天中被 @提及或被回复时收到，仅以默认模板（发送者的个人默认模板优先）的倒计时应答一次。
+func (s *BotService) HandleGuestMessage(ctx context.Context, bot *telego.Bot, msg *telego.Message) {
 	if msg == nil || msg.GuestQueryID == "" {
 		return
 	}
 
 	arg := util.GetGuestMessageArg(msg)
-	text, err := s.messageService.BuildCountdownText(arg, util.NowBJT())
+	text, err := s.messageService.BuildCountdownTextForUser(ctx, messageUserID(msg), arg, util.NowBJT())
 	if err != nil {
 		s.logger.Errorf("生成 Guest 倒计时失败: %v", err)
 		text = "处理请求时出错，请稍后重试"
@@ -117,9 +137,6 @@ func (s *BotService) HandleGuestMessage(bot *telego.Bot, msg *telego.Message) {
 		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
-	defer cancel()
-
 	_, err = s.bot.AnswerGuestQuery(ctx, &telego.AnswerGuestQueryParams{
 		GuestQueryID: msg.GuestQueryID,
 		Result:       result,
@@ -134,7 +151,7 @@ func (s *BotService) HandleGuestMessage(bot *telego.Bot, msg *telego.Message) {
 }
 
 // handleCommand 处理命令
-func (s *BotService) handleCommand(msg *telego.Message) {
+func (s *BotService) handleCommand(ctx context.Context, msg *telego.Message) {
 	// 提取命令
 	parts := strings.Fields(msg.Text)
 	if len(parts) == 0 {
@@ -152,16 +169,23 @@ func (s *BotService) handleCommand(msg *telego.Message) {
 
 	switch cmd {
 	case constant.CountdownCommand:
-		response, err = s.messageService.GetCountDownMessage(msg)
+		response, err = s.messageService.GetCountDownMessage(ctx, msg)
 	case constant.DebugCommand:
-		s.handleDebugCommand(msg)
+		s.handleDebugCommand(ctx, msg)
 		return
 	case constant.TemplateCommand:
-		s.handleTemplateCommand(msg)
+		s.handleTemplateCommand(ctx, msg)
 		return
+	case constant.CalendarCommand:
+		response, err = s.getCalendarMessage(msg, parts[1:])
 	default:
-		// 未知命令，忽略
-		return
+		if !isTemplateChatCommand(cmd) {
+			// 未知命令，忽略
+			return
+		}
+		// 命令后的全部文本（保留换行）作为模板内容
+		input := strings.TrimSpace(strings.TrimPrefix(msg.Text, parts[0]))
+		response, err = s.getTemplateChatMessage(ctx, msg, cmd, input)
 	}
 
 	if err != nil {
@@ -169,10 +193,11 @@ func (s *BotService) handleCommand(msg *telego.Message) {
 		response = "处理命令时出错，请稍后重试"
 	}
 
-	// 发送回复
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
-	defer cancel()
+	s.reply(ctx, msg, response)
+}
 
+// reply 回复消息
+func (s *BotService) reply(ctx context.Context, msg *telego.Message, response string) {
 	sentMsg, err := s.bot.SendMessage(ctx, &telego.SendMessageParams{
 		ChatID: telegoutil.ID(msg.Chat.ID),
 		Text:   response,
@@ -192,15 +217,62 @@ func (s *BotService) handleCommand(msg *telego.Message) {
 	}
 }
 
-// handleDebugCommand 处理 debug 命令
-func (s *BotService) handleDebugCommand(msg *telego.Message) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
-	defer cancel()
+// isTemplateChatCommand 是否为私聊模板管理命令
+func isTemplateChatCommand(cmd string) bool {
+	switch cmd {
+	case constant.StartCommand, constant.NewTemplateCommand, constant.TemplatesCommand, constant.CancelCommand:
+		return true
+	}
+	return strings.HasPrefix(cmd, constant.DeleteTemplateCommandPrefix) ||
+		strings.HasPrefix(cmd, constant.EditTemplateCommandPrefix) ||
+		strings.HasPrefix(cmd, constant.CopyTemplateCommandPrefix)
+}
 
+// getTemplateChatMessage 生成私聊模板管理命令的回复，群组中提示私聊使用
+func (s *BotService) getTemplateChatMessage(ctx context.Context, msg *telego.Message, cmd, input string) (string, error) {
+	if !util.IsUserChat(&msg.Chat) {
+		return fmt.Sprintf("此命令仅支持在私聊中使用，请点击 @%s 私聊 bot 后使用 /%s 命令", s.getBotUsername(ctx), cmd), nil
+	}
+	if s.templateChatService == nil {
+		return "模板管理暂未开放。", nil
+	}
+
+	userID := msg.Chat.ID
+	switch cmd {
+	case constant.StartCommand:
+		return s.templateChatService.Start(ctx, userID, input)
+	case constant.NewTemplateCommand:
+		return s.templateChatService.NewTemplate(ctx, userID, input)
+	case constant.TemplatesCommand:
+		return s.templateChatService.ListTemplates(ctx, userID)
+	case constant.CancelCommand:
+		return s.templateChatService.Cancel(userID), nil
+	}
+
+	if code, ok := strings.CutPrefix(cmd, constant.CopyTemplateCommandPrefix); ok {
+		return s.templateChatService.CopySharedTemplate(ctx, userID, code)
+	}
+	if id := util.ExtractTemplateID("/" + cmd); id != "" {
+		templateID, err := strconv.ParseInt(id, 10, 64)
+		if err == nil {
+			return s.templateChatService.DeleteTemplate(ctx, userID, templateID)
+		}
+	}
+	if id := util.ExtractEditTemplateID("/" + cmd); id != "" {
+		templateID, err := strconv.ParseInt(id, 10, 64)
+		if err == nil {
+			return s.templateChatService.EditTemplate(ctx, userID, templateID, input)
+		}
+	}
+	return "模板不存在，发送 /templates 查看你的模板。", nil
+}
+
+// handleDebugCommand 处理 debug 命令
+func (s *BotService) handleDebugCommand(ctx context.Context, msg *telego.Message) {
 	// 检查是否为私聊
 	if !util.IsUserChat(&msg.Chat) {
 		// 群组中，提示用户私聊 bot
-		botUsername := s.getBotUsername()
+		botUsername := s.getBotUsername(ctx)
 		text := fmt.Sprintf("此命令仅支持在私聊中使用，请点击 @%s 私聊 bot 后使用 /debug 命令", botUsername)
 
 		sentMsg, err := s.bot.SendMessage(ctx, &telego.SendMessageParams{
@@ -257,14 +329,11 @@ func (s *BotService) handleDebugCommand(msg *telego.Message) {
 }
 
 // handleTemplateCommand 处理 template 命令
-func (s *BotService) handleTemplateCommand(msg *telego.Message) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
-	defer cancel()
-
+func (s *BotService) handleTemplateCommand(ctx context.Context, msg *telego.Message) {
 	// 检查是否为私聊
 	if !util.IsUserChat(&msg.Chat) {
 		// 群组中，提示用户私聊 bot
-		botUsername := s.getBotUsername()
+		botUsername := s.getBotUsername(ctx)
 		text := fmt.Sprintf("此命令仅支持在私聊中使用，请点击 @%s 私聊 bot 后使用 /template 命令", botUsername)
 
 		sentMsg, err := s.bot.SendMessage(ctx, &telego.SendMessageParams{
@@ -317,6 +386,41 @@ func (s *BotService) handleTemplateCommand(msg *telego.Message) {
 	}
 }
 
+// getCalendarMessage 生成 calendar 命令回复
+// 私聊返回包含自定义目标的个人订阅链接，群组返回公共订阅链接；可选参数为省份名称
+func (s *BotService) getCalendarMessage(msg *telego.Message, args []string) (string, error) {
+	province := ""
+	if len(args) > 0 {
+		province = args[0]
+		if !constant.IsValidProvince(province) {
+			return fmt.Sprintf("无法识别的省份：%s。用法：/calendar [省份]，如 /calendar 北京", province), nil
+		}
+	}
+
+	if s.calendarFeedService == nil {
+		return "日历订阅暂未开放。", nil
+	}
+
+	publicURL, err := s.calendarFeedService.PublicFeedURL(province)
+	if errors.Is(err, ErrCalendarFeedNotConfigured) {
+		return "日历订阅暂未开放。", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if !util.IsUserChat(&msg.Chat) {
+		return fmt.Sprintf("考试日历订阅链接（可添加到系统日历）：\n%s\n\n私聊 bot 发送 /calendar 可获取包含自定义目标的个人订阅。", publicURL), nil
+	}
+
+	personalURL, err := s.calendarFeedService.UserFeedURL(msg.Chat.ID, province)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("你的个人考试日历订阅链接（包含自定义目标，请勿分享）：\n%s\n\n公共订阅链接：\n%s", personalURL, publicURL), nil
+}
+
 // truncateString 截断字符串到指定长度
 func truncateString(s string, maxLen int) string {
 	// 使用 rune 数量而非字节数量，正确处理多字节 UTF-8 字符（如中文）
@@ -346,10 +450,7 @@ func getContextErrorMessage(err error) string {
 }
 
 // getBotUsername 获取 bot 的用户名
-func (s *BotService) getBotUsername() string {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
-	defer cancel()
-
+func (s *BotService) getBotUsername(ctx context.Context) string {
 	me, err := s.bot.GetMe(ctx)
 	if err != nil {
 		s.logger.Errorf("获取 bot 信息失败: %s", getContextErrorMessage(err))
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,7 +28,7 @@ func TestNewBotService(t *testing.T) {
 	inlineQueryService := &InlineQueryService{}
 	miniAppURL := "https://example.com"
 
-	service := NewBotService(nil, messageService, inlineQueryService, logger, miniAppURL)
+	service := NewBotService(nil, messageService, inlineQueryService, nil, nil, logger, miniAppURL)
 
 	if service == nil {
 		t.Fatal("NewBotService() returned nil")
@@ -46,10 +47,10 @@ func TestHandleMessage_NilMessage(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := NewBotService(nil, nil, nil, logger, "")
+	service := NewBotService(nil, nil, nil, nil, nil, logger, "")
 
 	// 测试 nil 消息不应该导致 panic
-	service.HandleMessage(nil, nil)
+	service.HandleMessage(context.Background(), nil, nil)
 	// 如果没有 panic，测试通过
 }
 
@@ -57,7 +58,7 @@ func TestHandleMessage_EmptyText(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := NewBotService(nil, nil, nil, logger, "")
+	service := NewBotService(nil, nil, nil, nil, nil, logger, "")
 
 	msg := &telego.Message{
 		Text: "",
@@ -65,7 +66,7 @@ func TestHandleMessage_EmptyText(t *testing.T) {
 	}
 
 	// 测试空文本消息不应该处理
-	service.HandleMessage(nil, msg)
+	service.HandleMessage(context.Background(), nil, msg)
 	// 如果没有 panic，测试通过
 }
 
@@ -73,7 +74,7 @@ func TestHandleMessage_NonCommandText(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := NewBotService(nil, nil, nil, logger, "")
+	service := NewBotService(nil, nil, nil, nil, nil, logger, "")
 
 	msg := &telego.Message{
 		Text: "Hello, this is not a command",
@@ -81,7 +82,7 @@ func TestHandleMessage_NonCommandText(t *testing.T) {
 	}
 
 	// 测试非命令消息不应该被处理
-	service.HandleMessage(nil, msg)
+	service.HandleMessage(context.Background(), nil, msg)
 	// 如果没有 panic，测试通过
 }
 
@@ -89,10 +90,10 @@ func TestHandleInlineQuery_NilQuery(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := NewBotService(nil, nil, nil, logger, "")
+	service := NewBotService(nil, nil, nil, nil, nil, logger, "")
 
 	// 测试 nil 查询不应该导致 panic
-	service.HandleInlineQuery(nil, nil)
+	service.HandleInlineQuery(context.Background(), nil, nil)
 	// 如果没有 panic，测试通过
 }
 
@@ -263,7 +264,7 @@ func setupGuestTestService(t *testing.T, caller telegoapi.Caller) (*BotService,
 	logger.SetLevel(logrus.ErrorLevel)
 
 	bot := newGuestTestBot(t, caller)
-	service := NewBotService(bot, messageService, nil, logger, "")
+	service := NewBotService(bot, messageService, nil, nil, nil, logger, "")
 	return service, db
 }
 
@@ -279,19 +280,19 @@ func okGuestCaller() *mockGuestCaller {
 func TestHandleGuestMessage_NilMessage(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	service := NewBotService(nil, nil, nil, logger, "")
+	service := NewBotService(nil, nil, nil, nil, nil, logger, "")
 
 	// nil 消息不应该 panic
-	service.HandleGuestMessage(nil, nil)
+	service.HandleGuestMessage(context.Background(), nil, nil)
 }
 
 func TestHandleGuestMessage_EmptyQueryID(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	service := NewBotService(nil, nil, nil, logger, "")
+	service := NewBotService(nil, nil, nil, nil, nil, logger, "")
 
 	// 缺少 GuestQueryID 时应提前返回，不调用 API、不 panic
-	service.HandleGuestMessage(nil, &telego.Message{Text: "@gaokao_bot"})
+	service.HandleGuestMessage(context.Background(), nil, &telego.Message{Text: "@gaokao_bot"})
 }
 
 func TestHandleGuestMessage_NoArg(t *testing.T) {
@@ -312,7 +313,7 @@ func TestHandleGuestMessage_NoArg(t *testing.T) {
 		IsDelete:          false,
 	})
 
-	service.HandleGuestMessage(service.bot, &telego.Message{
+	service.HandleGuestMessage(context.Background(), service.bot, &telego.Message{
 		Text:         "@gaokao_bot",
 		GuestQueryID: "q-noarg",
 	})
@@ -339,7 +340,7 @@ func TestHandleGuestMessage_WithYear(t *testing.T) {
 		IsDelete:          false,
 	})
 
-	service.HandleGuestMessage(service.bot, &telego.Message{
+	service.HandleGuestMessage(context.Background(), service.bot, &telego.Message{
 		Text:         "@gaokao_bot 2026",
 		GuestQueryID: "q-year",
 	})
@@ -364,7 +365,7 @@ func TestHandleGuestMessage_InvalidArg(t *testing.T) {
 	caller := okGuestCaller()
 	service, _ := setupGuestTestService(t, caller)
 
-	service.HandleGuestMessage(service.bot, &telego.Message{
+	service.HandleGuestMessage(context.Background(), service.bot, &telego.Message{
 		Text:         "@gaokao_bot hello",
 		GuestQueryID: "q-invalid",
 	})
@@ -389,7 +390,7 @@ func TestHandleGuestMessage_NoData(t *testing.T) {
 	service, _ := setupGuestTestService(t, caller)
 
 	// 数据库无考试数据时仍应答提示文本
-	service.HandleGuestMessage(service.bot, &telego.Message{
+	service.HandleGuestMessage(context.Background(), service.bot, &telego.Message{
 		Text:         "@gaokao_bot",
 		GuestQueryID: "q-nodata",
 	})
@@ -410,7 +411,7 @@ func TestHandleGuestMessage_AnswerFails(t *testing.T) {
 	service, _ := setupGuestTestService(t, caller)
 
 	// API 调用失败时应记录日志、不重试、不 panic
-	service.HandleGuestMessage(service.bot, &telego.Message{
+	service.HandleGuestMessage(context.Background(), service.bot, &telego.Message{
 		Text:         "@gaokao_bot",
 		GuestQueryID: "q-fail",
 	})
@@ -419,3 +420,56 @@ func TestHandleGuestMessage_AnswerFails(t *testing.T) {
 		t.Errorf("expected 1 API call, got %d", caller.calls)
 	}
 }
+
+func TestGetCalendarMessage(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	feedService, _ := setupCalendarFeedTestService(t, "https://example.com")
+	service := NewBotService(nil, nil, nil, feedService, nil, logger, "")
+
+	privateMsg := &telego.Message{Chat: telego.Chat{ID: 123, Type: telego.ChatTypePrivate}}
+	groupMsg := &telego.Message{Chat: telego.Chat{ID: -100, Type: telego.ChatTypeSupergroup}}
+
+	text, err := service.getCalendarMessage(privateMsg, nil)
+	if err != nil {
+		t.Fatalf("getCalendarMessage() error = %v", err)
+	}
+	if !strings.Contains(text, "/api/calendar/user/123-"+feedService.Sign(123)+".ics") {
+		t.Errorf("Expected personal feed URL in private chat, got %s", text)
+	}
+
+	text, err = service.getCalendarMessage(groupMsg, []string{"北京"})
+	if err != nil {
+		t.Fatalf("getCalendarMessage() error = %v", err)
+	}
+	if strings.Contains(text, "/api/calendar/user/") {
+		t.Errorf("Expected no personal feed URL in group chat, got %s", text)
+	}
+	if !strings.Contains(text, "https://example.com/api/calendar.ics?province=") {
+		t.Errorf("Expected public feed URL with province, got %s", text)
+	}
+
+	text, _ = service.getCalendarMessage(privateMsg, []string{"火星"})
+	if !strings.Contains(text, "无法识别的省份") {
+		t.Errorf("Expected invalid province hint, got %s", text)
+	}
+}
+
+func TestGetCalendarMessage_NotConfigured(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	feedService, _ := setupCalendarFeedTestService(t, "")
+	msg := &telego.Message{Chat: telego.Chat{ID: 123, Type: telego.ChatTypePrivate}}
+
+	for _, service := range []*BotService{
+		NewBotService(nil, nil, nil, feedService, nil, logger, ""),
+		NewBotService(nil, nil, nil, nil, nil, logger, ""),
+	} {
+		text, err := service.getCalendarMessage(msg, nil)
+		if err != nil || text != "日历订阅暂未开放。" {
+			t.Errorf("getCalendarMessage() = %q, %v", text, err)
+		}
+	}
+}
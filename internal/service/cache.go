@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+// DefaultCacheMaxEntries 单个缓存的最大条目数，超出时先清理过期条目，仍超出则清空
+const DefaultCacheMaxEntries = 10000
+
+// CacheStats 缓存命中统计
+type CacheStats struct {
+	Name    string  `json:"name"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	Entries int     `json:"entries"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// Cache 可查看统计、可手动清空的缓存
+type Cache interface {
+	// Stats 返回缓存的命中统计
+	Stats() []CacheStats
+	// Invalidate 清空缓存
+	Invalidate()
+}
+
+// ttlCacheEntry 缓存条目
+type ttlCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// ttlCache 带过期时间的进程内缓存，并发安全
+// 失效时递增版本号，失效前开始的加载结果不会写回缓存，避免写入过期数据
+type ttlCache[K comparable, V any] struct {
+	name       string
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu         sync.Mutex
+	entries    map[K]ttlCacheEntry[V]
+	generation uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// newTTLCache 创建缓存，ttl 不大于 0 时不缓存（仍统计未命中次数）
+func newTTLCache[K comparable, V any](name string, ttl time.Duration) *ttlCache[K, V] {
+	return &ttlCache[K, V]{
+		name:       name,
+		ttl:        ttl,
+		maxEntries: DefaultCacheMaxEntries,
+		now:        time.Now,
+		entries:    make(map[K]ttlCacheEntry[V]),
+	}
+}
+
+// get 读取未过期的缓存条目
+func (c *ttlCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok && c.now().Before(entry.expiresAt) {
+		c.hits.Add(1)
+		return entry.value, true
+	}
+	if ok {
+		delete(c.entries, key)
+	}
+
+	c.misses.Add(1)
+	var zero V
+	return zero, false
+}
+
+// getOrLoad 读取缓存，未命中时调用 load 加载并写入缓存
+// load 返回错误时不写入缓存
+func (c *ttlCache[K, V]) getOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	if value, ok := c.get(key); ok {
+		return value, nil
+	}
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	c.set(key, value, generation)
+	return value, nil
+}
+
+// set 写入缓存条目；generation 与当前版本不一致时说明加载期间发生过失效，放弃写入
+func (c *ttlCache[K, V]) set(key K, value V, generation uint64) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evictLocked()
+	}
+	c.entries[key] = ttlCacheEntry[V]{value: value, expiresAt: c.now().Add(c.ttl)}
+}
+
+// evictLocked 清理过期条目，仍超出容量时清空，调用方需持有锁
+func (c *ttlCache[K, V]) evictLocked() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.entries = make(map[K]ttlCacheEntry[V])
+	}
+}
+
+// invalidate 删除指定条目
+func (c *ttlCache[K, V]) invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	delete(c.entries, key)
+}
+
+// invalidateAll 清空缓存
+func (c *ttlCache[K, V]) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[K]ttlCacheEntry[V])
+}
+
+// stats 返回命中统计
+func (c *ttlCache[K, V]) stats() CacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	stats := CacheStats{
+		Name:    c.name,
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// primaryReadWindow 写入后读取主库的时长，覆盖只读副本的复制延迟
+const primaryReadWindow = 10 * time.Second
+
+// recentWrites 记录最近写入的键，窗口内的加载改为读取主库
+// 否则失效后的首次加载可能从延迟的副本读到旧数据，并在整个 TTL 内缓存
+type recentWrites[K comparable] struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	written map[K]time.Time
+}
+
+// newRecentWrites 创建写入记录
+func newRecentWrites[K comparable](window time.Duration) *recentWrites[K] {
+	return &recentWrites[K]{
+		window:  window,
+		now:     time.Now,
+		written: make(map[K]time.Time),
+	}
+}
+
+// touch 记录键的写入时间，并清理窗口外的记录
+func (w *recentWrites[K]) touch(key K) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	for k, at := range w.written {
+		if now.Sub(at) >= w.window {
+			delete(w.written, k)
+		}
+	}
+	w.written[key] = now
+}
+
+// context 键在窗口内写入过时返回要求读取主库的 context
+func (w *recentWrites[K]) context(ctx context.Context, key K) context.Context {
+	w.mu.Lock()
+	at, ok := w.written[key]
+	w.mu.Unlock()
+
+	if ok && w.now().Sub(at) < w.window {
+		return repository.WithPrimary(ctx)
+	}
+	return ctx
+}
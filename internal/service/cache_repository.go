@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+// 编译期检查缓存仓储满足仓储接口
+var (
+	_ repository.ExamDateRepository     = (*CachedExamDateRepository)(nil)
+	_ repository.UserTemplateRepository = (*CachedUserTemplateRepository)(nil)
+	_ Cache                             = (*CachedExamDateRepository)(nil)
+	_ Cache                             = (*CachedUserTemplateRepository)(nil)
+)
+
+// CachedExamDateRepository 带缓存的考试日期仓储
+// 缓存全部未删除考试的快照，查询在内存中完成；通过本仓储写入时清空缓存。
+// 多个服务需共用同一实例，才能在导入考试后立即看到新数据。
+// 写入后的短时间内从主库重新加载，避免缓存只读副本上的旧数据。
+type CachedExamDateRepository struct {
+	repo   repository.ExamDateRepository
+	exams  *ttlCache[struct{}, []model.ExamDate]
+	writes *recentWrites[struct{}]
+}
+
+// NewCachedExamDateRepository 创建带缓存的考试日期仓储，ttl 不大于 0 时不缓存
+func NewCachedExamDateRepository(repo repository.ExamDateRepository, ttl time.Duration) *CachedExamDateRepository {
+	return &CachedExamDateRepository{
+		repo:   repo,
+		exams:  newTTLCache[struct{}, []model.ExamDate]("exam_dates", ttl),
+		writes: newRecentWrites[struct{}](primaryReadWindow),
+	}
+}
+
+// snapshot 获取全部未删除考试（按开始时间排序），调用方不得修改返回的切片
+func (r *CachedExamDateRepository) snapshot(ctx context.Context) ([]model.ExamDate, error) {
+	return r.exams.getOrLoad(ctx, struct{}{}, func(ctx context.Context) ([]model.ExamDate, error) {
+		return r.repo.Find(r.writes.context(ctx, struct{}{}), repository.ExamQuery{})
+	})
+}
+
+// Find 按条件查询未删除的考试
+func (r *CachedExamDateRepository) Find(ctx context.Context, q repository.ExamQuery) ([]model.ExamDate, error) {
+	exams, err := r.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return q.Apply(exams), nil
+}
+
+// GetExamsInRange 获取时间范围内的考试，按开始时间排序
+func (r *CachedExamDateRepository) GetExamsInRange(ctx context.Context, now time.Time) ([]model.ExamDate, error) {
+	return r.Find(ctx, repository.ExamQuery{ActiveAt: now})
+}
+
+// GetExamByYear 按年份获取考试，按开始时间排序
+func (r *CachedExamDateRepository) GetExamByYear(ctx context.Context, year int) ([]model.ExamDate, error) {
+	return r.Find(ctx, repository.ExamQuery{Year: year})
+}
+
+// GetEndingAfter 获取结束时间不早于 since 的考试，按开始时间排序
+func (r *CachedExamDateRepository) GetEndingAfter(ctx context.Context, since time.Time) ([]model.ExamDate, error) {
+	exams, err := r.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.ExamDate
+	for _, exam := range exams {
+		if !exam.ExamEndDate.Before(since) {
+			result = append(result, exam)
+		}
+	}
+	return result, nil
+}
+
+// GetAll 获取全部考试（导入导出使用，不经过缓存）
+func (r *CachedExamDateRepository) GetAll(ctx context.Context, includeDeleted bool) ([]model.ExamDate, error) {
+	return r.repo.GetAll(ctx, includeDeleted)
+}
+
+// SaveAll 批量保存考试并清空缓存
+func (r *CachedExamDateRepository) SaveAll(ctx context.Context, exams []model.ExamDate) error {
+	// 写入失败时事务可能已部分提交（如 MySQL DDL），同样清空缓存
+	defer r.Invalidate()
+	defer r.writes.touch(struct{}{})
+	return r.repo.SaveAll(ctx, exams)
+}
+
+// Invalidate 清空缓存
+func (r *CachedExamDateRepository) Invalidate() {
+	r.exams.invalidateAll()
+}
+
+// Stats 返回缓存的命中统计
+func (r *CachedExamDateRepository) Stats() []CacheStats {
+	return []CacheStats{r.exams.stats()}
+}
+
+// CachedUserTemplateRepository 带缓存的用户模板仓储
+// 缓存默认模板和各用户的模板列表；通过本仓储写入时清空对应用户的缓存。
+// 数量统计和按 ID 查询用于权限与限额校验，不经过缓存。
+// 用户写入后的短时间内从主库重新加载其模板，避免缓存只读副本上的旧列表。
+type CachedUserTemplateRepository struct {
+	repo            repository.UserTemplateRepository
+	defaultTemplate *ttlCache[struct{}, *model.UserTemplate]
+	userTemplates   *ttlCache[int64, []model.UserTemplate]
+	writes          *recentWrites[int64]
+}
+
+// NewCachedUserTemplateRepository 创建带缓存的用户模板仓储，ttl 不大于 0 时不缓存
+func NewCachedUserTemplateRepository(repo repository.UserTemplateRepository, ttl time.Duration) *CachedUserTemplateRepository {
+	return &CachedUserTemplateRepository{
+		repo:            repo,
+		defaultTemplate: newTTLCache[struct{}, *model.UserTemplate]("default_template", ttl),
+		userTemplates:   newTTLCache[int64, []model.UserTemplate]("user_templates", ttl),
+		writes:          newRecentWrites[int64](primaryReadWindow),
+	}
+}
+
+// GetDefaultTemplate 获取默认模板，返回副本
+func (r *CachedUserTemplateRepository) GetDefaultTemplate(ctx context.Context) (*model.UserTemplate, error) {
+	template, err := r.defaultTemplate.getOrLoad(ctx, struct{}{}, func(ctx context.Context) (*model.UserTemplate, error) {
+		return r.repo.GetDefaultTemplate(r.writes.context(ctx, 0))
+	})
+	if err != nil || template == nil {
+		return nil, err
+	}
+	copied := *template
+	return &copied, nil
+}
+
+// GetByUserID 根据用户ID获取模板列表，返回副本
+func (r *CachedUserTemplateRepository) GetByUserID(ctx context.Context, userID int64) ([]model.UserTemplate, error) {
+	templates, err := r.userTemplates.getOrLoad(ctx, userID, func(ctx context.Context) ([]model.UserTemplate, error) {
+		return r.repo.GetByUserID(r.writes.context(ctx, userID), userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]model.UserTemplate(nil), templates...), nil
+}
+
+// GetByID 根据ID获取模板（不经过缓存）
+func (r *CachedUserTemplateRepository) GetByID(ctx context.Context, id int64) (*model.UserTemplate, error) {
+	return r.repo.GetByID(ctx, id)
+}
+
+// CountByUserID 统计用户的模板数量（不经过缓存）
+func (r *CachedUserTemplateRepository) CountByUserID(ctx context.Context, userID int64) (int64, error) {
+	return r.repo.CountByUserID(ctx, userID)
+}
+
+// Create 创建模板并清空该用户的缓存
+func (r *CachedUserTemplateRepository) Create(ctx context.Context, template *model.UserTemplate) error {
+	defer r.invalidateUser(template.UserID)
+	return r.repo.Create(ctx, template)
+}
+
+// CreateWithLimit 原子地检查数量限制并创建模板，清空该用户的缓存
+func (r *CachedUserTemplateRepository) CreateWithLimit(ctx context.Context, template *model.UserTemplate, maxLimit int64) error {
+	defer r.invalidateUser(template.UserID)
+	return r.repo.CreateWithLimit(ctx, template, maxLimit)
+}
+
+// CreateBatchWithLimit 原子地检查数量限制并批量创建模板，清空该用户的缓存
+func (r *CachedUserTemplateRepository) CreateBatchWithLimit(ctx context.Context, userID int64, templates []*model.UserTemplate, maxLimit int64) error {
+	defer r.invalidateUser(userID)
+	return r.repo.CreateBatchWithLimit(ctx, userID, templates, maxLimit)
+}
+
+// Update 更新模板并清空该用户的缓存
+func (r *CachedUserTemplateRepository) Update(ctx context.Context, template *model.UserTemplate) error {
+	defer r.invalidateUser(template.UserID)
+	return r.repo.Update(ctx, template)
+}
+
+// Delete 删除模板并清空所属用户的缓存
+func (r *CachedUserTemplateRepository) Delete(ctx context.Context, id int64) error {
+	template, err := r.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if template != nil {
+		defer r.invalidateUser(template.UserID)
+	}
+	return r.repo.Delete(ctx, id)
+}
+
+// GetByShareCode 根据分享码获取模板（不经过缓存）
+func (r *CachedUserTemplateRepository) GetByShareCode(ctx context.Context, code string) (*model.UserTemplate, error) {
+	return r.repo.GetByShareCode(ctx, code)
+}
+
+// UpdateShareCode 更新分享码并清空所属用户的缓存
+func (r *CachedUserTemplateRepository) UpdateShareCode(ctx context.Context, id int64, code *string) error {
+	template, err := r.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if template != nil {
+		defer r.invalidateUser(template.UserID)
+	}
+	return r.repo.UpdateShareCode(ctx, id, code)
+}
+
+// UpdateSortOrders 更新排序位置并清空该用户的缓存
+func (r *CachedUserTemplateRepository) UpdateSortOrders(ctx context.Context, userID int64, ids []int64) error {
+	defer r.invalidateUser(userID)
+	return r.repo.UpdateSortOrders(ctx, userID, ids)
+}
+
+// UpdateFavorite 更新收藏状态并清空所属用户的缓存
+func (r *CachedUserTemplateRepository) UpdateFavorite(ctx context.Context, id int64, favorite bool) error {
+	template, err := r.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if template != nil {
+		defer r.invalidateUser(template.UserID)
+	}
+	return r.repo.UpdateFavorite(ctx, id, favorite)
+}
+
+// SetUserDefault 设置个人默认模板并清空该用户的缓存
+func (r *CachedUserTemplateRepository) SetUserDefault(ctx context.Context, userID, id int64) error {
+	defer r.invalidateUser(userID)
+	return r.repo.SetUserDefault(ctx, userID, id)
+}
+
+// invalidateUser 清空用户的模板缓存并记录写入，默认模板（user_id 为 0）同时清空默认模板缓存
+func (r *CachedUserTemplateRepository) invalidateUser(userID int64) {
+	r.writes.touch(userID)
+	r.userTemplates.invalidate(userID)
+	if userID == 0 {
+		r.defaultTemplate.invalidateAll()
+	}
+}
+
+// Invalidate 清空缓存
+func (r *CachedUserTemplateRepository) Invalidate() {
+	r.defaultTemplate.invalidateAll()
+	r.userTemplates.invalidateAll()
+}
+
+// Stats 返回缓存的命中统计
+func (r *CachedUserTemplateRepository) Stats() []CacheStats {
+	return []CacheStats{r.defaultTemplate.stats(), r.userTemplates.stats()}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/repository/memory"
+	"gorm.io/gorm"
+)
+
+// countingExamDateRepository 统计查询次数的考试日期仓储
+type countingExamDateRepository struct {
+	repository.ExamDateRepository
+	finds int
+}
+
+func (r *countingExamDateRepository) Find(ctx context.Context, q repository.ExamQuery) ([]model.ExamDate, error) {
+	r.finds++
+	return r.ExamDateRepository.Find(ctx, q)
+}
+
+// countingUserTemplateRepository 统计查询次数的用户模板仓储
+type countingUserTemplateRepository struct {
+	repository.UserTemplateRepository
+	defaultLoads int
+	userLoads    int
+}
+
+func (r *countingUserTemplateRepository) GetDefaultTemplate(ctx context.Context) (*model.UserTemplate, error) {
+	r.defaultLoads++
+	return r.UserTemplateRepository.GetDefaultTemplate(ctx)
+}
+
+func (r *countingUserTemplateRepository) GetByUserID(ctx context.Context, userID int64) ([]model.UserTemplate, error) {
+	r.userLoads++
+	return r.UserTemplateRepository.GetByUserID(ctx, userID)
+}
+
+func newCacheTestExam(id uint, year int, kind string) model.ExamDate {
+	begin := time.Date(year, 6, 7, 9, 0, 0, 0, time.UTC)
+	return model.ExamDate{
+		ID:                id,
+		ExamYear:          year,
+		ExamKind:          kind,
+		ExamDesc:          "测试考试",
+		ShortDesc:         "测试",
+		ExamBeginDate:     begin,
+		ExamEndDate:       begin.AddDate(0, 0, 2),
+		ExamYearBeginDate: begin.AddDate(-1, 0, 0),
+		ExamYearEndDate:   begin.AddDate(0, 0, 2),
+	}
+}
+
+func TestCachedExamDateRepository(t *testing.T) {
+	ctx := context.Background()
+	base := &countingExamDateRepository{ExamDateRepository: memory.NewExamDateRepository()}
+	if err := base.SaveAll(ctx, []model.ExamDate{
+		newCacheTestExam(1, 2026, "gaokao"),
+		newCacheTestExam(2, 2027, "gaokao"),
+		newCacheTestExam(3, 2027, "zhongkao"),
+	}); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+	repo := NewCachedExamDateRepository(base, time.Minute)
+
+	exams, err := repo.GetExamByYear(ctx, 2027)
+	if err != nil {
+		t.Fatalf("GetExamByYear() error = %v", err)
+	}
+	if len(exams) != 2 {
+		t.Errorf("GetExamByYear(2027) = %d exams, want 2", len(exams))
+	}
+
+	exams, _ = repo.Find(ctx, repository.ExamQuery{Kind: "gaokao", OrderByEndDesc: true, Limit: 1})
+	if len(exams) != 1 || exams[0].ID != 2 {
+		t.Errorf("Find() = %+v, want latest gaokao (id 2)", exams)
+	}
+
+	exams, _ = repo.GetEndingAfter(ctx, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(exams) != 2 {
+		t.Errorf("GetEndingAfter() = %d exams, want 2", len(exams))
+	}
+
+	if base.finds != 1 {
+		t.Errorf("underlying Find called %d times, want 1", base.finds)
+	}
+
+	// 通过缓存仓储写入后立即可见
+	if err := repo.SaveAll(ctx, []model.ExamDate{newCacheTestExam(4, 2028, "gaokao")}); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+	exams, _ = repo.GetExamByYear(ctx, 2028)
+	if len(exams) != 1 {
+		t.Errorf("GetExamByYear(2028) after SaveAll = %d exams, want 1", len(exams))
+	}
+	if base.finds != 2 {
+		t.Errorf("underlying Find called %d times, want 2", base.finds)
+	}
+
+	stats := repo.Stats()
+	if len(stats) != 1 || stats[0].Hits != 2 || stats[0].Misses != 2 {
+		t.Errorf("Stats() = %+v, want hits=2 misses=2", stats)
+	}
+}
+
+func TestCachedExamDateRepository_ResultIsolated(t *testing.T) {
+	ctx := context.Background()
+	base := memory.NewExamDateRepository()
+	base.SaveAll(ctx, []model.ExamDate{newCacheTestExam(1, 2026, "gaokao")})
+	repo := NewCachedExamDateRepository(base, time.Minute)
+
+	exams, _ := repo.Find(ctx, repository.ExamQuery{})
+	exams[0].ExamDesc = "已修改"
+
+	exams, _ = repo.Find(ctx, repository.ExamQuery{})
+	if exams[0].ExamDesc != "测试考试" {
+		t.Errorf("ExamDesc = %q, modifying results should not affect the cache", exams[0].ExamDesc)
+	}
+}
+
+func TestCachedUserTemplateRepository(t *testing.T) {
+	ctx := context.Background()
+	base := &countingUserTemplateRepository{UserTemplateRepository: memory.NewUserTemplateRepository()}
+	base.Create(ctx, &model.UserTemplate{ID: 1, UserID: 0, TemplateName: "默认", TemplateContent: "默认内容"})
+	repo := NewCachedUserTemplateRepository(base, time.Minute)
+
+	// 默认模板命中缓存，返回副本
+	template, err := repo.GetDefaultTemplate(ctx)
+	if err != nil || template == nil {
+		t.Fatalf("GetDefaultTemplate() = %v, %v", template, err)
+	}
+	template.TemplateContent = "已修改"
+	template, _ = repo.GetDefaultTemplate(ctx)
+	if template.TemplateContent != "默认内容" {
+		t.Errorf("TemplateContent = %q, want 默认内容", template.TemplateContent)
+	}
+	if base.defaultLoads != 1 {
+		t.Errorf("underlying GetDefaultTemplate called %d times, want 1", base.defaultLoads)
+	}
+
+	// 创建后对应用户的缓存失效
+	repo.GetByUserID(ctx, 100)
+	if err := repo.CreateWithLimit(ctx, &model.UserTemplate{ID: 2, UserID: 100, TemplateName: "a", TemplateContent: "a"}, 5); err != nil {
+		t.Fatalf("CreateWithLimit() error = %v", err)
+	}
+	templates, _ := repo.GetByUserID(ctx, 100)
+	if len(templates) != 1 {
+		t.Errorf("GetByUserID() after create = %d templates, want 1", len(templates))
+	}
+
+	// 更新后可见
+	updated := templates[0]
+	updated.TemplateContent = "b"
+	if err := repo.Update(ctx, &updated); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	templates, _ = repo.GetByUserID(ctx, 100)
+	if len(templates) != 1 || templates[0].TemplateContent != "b" {
+		t.Errorf("GetByUserID() after update = %+v, want content b", templates)
+	}
+
+	// 分享码变更后可见
+	code := "abcdefgh"
+	if err := repo.UpdateShareCode(ctx, 2, &code); err != nil {
+		t.Fatalf("UpdateShareCode() error = %v", err)
+	}
+	templates, _ = repo.GetByUserID(ctx, 100)
+	if len(templates) != 1 || templates[0].ShareCode == nil || *templates[0].ShareCode != code {
+		t.Errorf("GetByUserID() after share = %+v, want share code", templates)
+	}
+
+	// 删除后可见
+	if err := repo.Delete(ctx, 2); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	templates, _ = repo.GetByUserID(ctx, 100)
+	if len(templates) != 0 {
+		t.Errorf("GetByUserID() after delete = %d templates, want 0", len(templates))
+	}
+	if base.userLoads != 5 {
+		t.Errorf("underlying GetByUserID called %d times, want 5", base.userLoads)
+	}
+
+	// 修改默认模板时默认模板缓存失效
+	if err := repo.Update(ctx, &model.UserTemplate{ID: 1, UserID: 0, TemplateName: "默认", TemplateContent: "新默认"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	template, _ = repo.GetDefaultTemplate(ctx)
+	if template.TemplateContent != "新默认" {
+		t.Errorf("TemplateContent = %q, want 新默认", template.TemplateContent)
+	}
+
+	if stats := repo.Stats(); len(stats) != 2 {
+		t.Errorf("Stats() = %d entries, want 2", len(stats))
+	}
+
+	repo.Invalidate()
+	repo.GetDefaultTemplate(ctx)
+	if base.defaultLoads != 3 {
+		t.Errorf("underlying GetDefaultTemplate called %d times, want 3", base.defaultLoads)
+	}
+}
+
+func TestCachedUserTemplateRepository_NoDefault(t *testing.T) {
+	ctx := context.Background()
+	base := &countingUserTemplateRepository{UserTemplateRepository: memory.NewUserTemplateRepository()}
+	repo := NewCachedUserTemplateRepository(base, time.Minute)
+
+	// 没有默认模板时同样缓存结果
+	for i := 0; i < 2; i++ {
+		template, err := repo.GetDefaultTemplate(ctx)
+		if err != nil || template != nil {
+			t.Errorf("GetDefaultTemplate() = %v, %v, want nil, nil", template, err)
+		}
+	}
+	if base.defaultLoads != 1 {
+		t.Errorf("underlying GetDefaultTemplate called %d times, want 1", base.defaultLoads)
+	}
+}
+
+func TestCachedUserTemplateRepository_OrderFavoriteDefault(t *testing.T) {
+	ctx := context.Background()
+	base := memory.NewUserTemplateRepository()
+	base.Create(ctx, &model.UserTemplate{ID: 1, UserID: 100, TemplateContent: "a"})
+	base.Create(ctx, &model.UserTemplate{ID: 2, UserID: 100, TemplateContent: "b"})
+	repo := NewCachedUserTemplateRepository(base, time.Minute)
+
+	repo.GetByUserID(ctx, 100)
+	if err := repo.UpdateSortOrders(ctx, 100, []int64{2, 1}); err != nil {
+		t.Fatalf("UpdateSortOrders() error = %v", err)
+	}
+	if templates, _ := repo.GetByUserID(ctx, 100); templates[0].ID != 2 {
+		t.Errorf("GetByUserID() after reorder = %+v, want template 2 first", templates)
+	}
+
+	if err := repo.UpdateFavorite(ctx, 1, true); err != nil {
+		t.Fatalf("UpdateFavorite() error = %v", err)
+	}
+	if templates, _ := repo.GetByUserID(ctx, 100); templates[0].ID != 1 || !templates[0].IsFavorite {
+		t.Errorf("GetByUserID() after favorite = %+v, want template 1 first", templates)
+	}
+
+	if err := repo.SetUserDefault(ctx, 100, 2); err != nil {
+		t.Fatalf("SetUserDefault() error = %v", err)
+	}
+	if templates, _ := repo.GetByUserID(ctx, 100); !templates[1].IsDefault {
+		t.Errorf("GetByUserID() after set default = %+v, want template 2 default", templates)
+	}
+
+	if err := repo.CreateBatchWithLimit(ctx, 100, []*model.UserTemplate{{ID: 3, UserID: 100, TemplateContent: "c"}}, 5); err != nil {
+		t.Fatalf("CreateBatchWithLimit() error = %v", err)
+	}
+	if templates, _ := repo.GetByUserID(ctx, 100); len(templates) != 3 {
+		t.Errorf("GetByUserID() after batch create = %d templates, want 3", len(templates))
+	}
+}
+
+// laggingReplica 始终读取指定数据库的只读副本，模拟复制延迟
+type laggingReplica struct {
+	db *gorm.DB
+}
+
+func (r laggingReplica) Read(ctx context.Context, fn func(db *gorm.DB) error) error {
+	return fn(r.db.WithContext(ctx))
+}
+
+func TestCachedUserTemplateRepository_ReadsPrimaryAfterWrite(t *testing.T) {
+	ctx := context.Background()
+	primary := setupTestDB(t)
+	replica := setupTestDB(t)
+	base := repository.NewUserTemplateRepository(primary).WithReadReplicas(laggingReplica{db: replica})
+	repo := NewCachedUserTemplateRepository(base, time.Minute)
+	now := time.Now()
+	repo.writes.now = func() time.Time { return now }
+
+	// 写入后副本尚未同步，列表仍应包含新模板
+	if err := repo.CreateWithLimit(ctx, &model.UserTemplate{ID: 1, UserID: 100, TemplateContent: "新模板"}, 5); err != nil {
+		t.Fatalf("CreateWithLimit() error = %v", err)
+	}
+	if templates, err := repo.GetByUserID(ctx, 100); err != nil || len(templates) != 1 {
+		t.Errorf("GetByUserID() after create = %v, %v, want primary data", templates, err)
+	}
+
+	// 其他用户不受影响，仍读取副本
+	primary.Create(&model.UserTemplate{ID: 2, UserID: 200, TemplateContent: "其他用户"})
+	if templates, _ := repo.GetByUserID(ctx, 200); len(templates) != 0 {
+		t.Errorf("GetByUserID(200) = %v, want replica data", templates)
+	}
+
+	// 超过窗口后恢复读取副本
+	now = now.Add(primaryReadWindow)
+	repo.Invalidate()
+	if templates, _ := repo.GetByUserID(ctx, 100); len(templates) != 0 {
+		t.Errorf("GetByUserID() after window = %v, want replica data", templates)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestTTLCache 创建使用可控时钟的缓存
+func newTestTTLCache(ttl time.Duration) (*ttlCache[string, int], *time.Time) {
+	now := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	cache := newTTLCache[string, int]("test", ttl)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestTTLCache_GetOrLoad(t *testing.T) {
+	cache, now := newTestTTLCache(time.Minute)
+	loads := 0
+	load := func(context.Context) (int, error) {
+		loads++
+		return loads, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := cache.getOrLoad(context.Background(), "k", load)
+		if err != nil {
+			t.Fatalf("getOrLoad() error = %v", err)
+		}
+		if value != 1 {
+			t.Errorf("getOrLoad() = %d, want 1", value)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("load called %d times, want 1", loads)
+	}
+
+	// 过期后重新加载
+	*now = now.Add(time.Minute)
+	value, _ := cache.getOrLoad(context.Background(), "k", load)
+	if value != 2 {
+		t.Errorf("getOrLoad() after expiry = %d, want 2", value)
+	}
+
+	stats := cache.stats()
+	if stats.Name != "test" || stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 1 {
+		t.Errorf("stats() = %+v, want hits=2 misses=2 entries=1", stats)
+	}
+	if stats.HitRate != 0.5 {
+		t.Errorf("HitRate = %v, want 0.5", stats.HitRate)
+	}
+}
+
+func TestTTLCache_LoadError(t *testing.T) {
+	cache, _ := newTestTTLCache(time.Minute)
+	wantErr := errors.New("boom")
+
+	_, err := cache.getOrLoad(context.Background(), "k", func(context.Context) (int, error) {
+		return 0, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("getOrLoad() error = %v, want %v", err, wantErr)
+	}
+	if stats := cache.stats(); stats.Entries != 0 {
+		t.Errorf("Entries = %d, want 0 (errors are not cached)", stats.Entries)
+	}
+}
+
+func TestTTLCache_Invalidate(t *testing.T) {
+	cache, _ := newTestTTLCache(time.Minute)
+	load := func(value int) func(context.Context) (int, error) {
+		return func(context.Context) (int, error) { return value, nil }
+	}
+
+	cache.getOrLoad(context.Background(), "a", load(1))
+	cache.getOrLoad(context.Background(), "b", load(2))
+
+	cache.invalidate("a")
+	if _, ok := cache.get("a"); ok {
+		t.Error("get(a) should miss after invalidate")
+	}
+	if value, ok := cache.get("b"); !ok || value != 2 {
+		t.Errorf("get(b) = %d, %v, want 2, true", value, ok)
+	}
+
+	cache.invalidateAll()
+	if _, ok := cache.get("b"); ok {
+		t.Error("get(b) should miss after invalidateAll")
+	}
+}
+
+func TestTTLCache_InvalidateDuringLoad(t *testing.T) {
+	cache, _ := newTestTTLCache(time.Minute)
+
+	// 加载期间发生失效，加载到的旧数据不应写入缓存
+	value, err := cache.getOrLoad(context.Background(), "k", func(context.Context) (int, error) {
+		cache.invalidateAll()
+		return 1, nil
+	})
+	if err != nil || value != 1 {
+		t.Fatalf("getOrLoad() = %d, %v, want 1, nil", value, err)
+	}
+	if _, ok := cache.get("k"); ok {
+		t.Error("stale value loaded before invalidation should not be cached")
+	}
+}
+
+func TestTTLCache_Disabled(t *testing.T) {
+	cache, _ := newTestTTLCache(0)
+	loads := 0
+	load := func(context.Context) (int, error) {
+		loads++
+		return loads, nil
+	}
+
+	cache.getOrLoad(context.Background(), "k", load)
+	cache.getOrLoad(context.Background(), "k", load)
+	if loads != 2 {
+		t.Errorf("load called %d times, want 2 when ttl is 0", loads)
+	}
+	if stats := cache.stats(); stats.Misses != 2 || stats.Entries != 0 {
+		t.Errorf("stats() = %+v, want misses=2 entries=0", stats)
+	}
+}
+
+func TestTTLCache_MaxEntries(t *testing.T) {
+	cache, now := newTestTTLCache(time.Minute)
+	cache.maxEntries = 2
+	load := func(context.Context) (int, error) { return 1, nil }
+
+	cache.getOrLoad(context.Background(), "a", load)
+	*now = now.Add(time.Minute)
+	cache.getOrLoad(context.Background(), "b", load)
+
+	// a 已过期，写入 c 时清理 a，保留 b
+	cache.getOrLoad(context.Background(), "c", load)
+	if _, ok := cache.get("b"); !ok {
+		t.Error("get(b) should hit after evicting expired entries")
+	}
+	if stats := cache.stats(); stats.Entries != 2 {
+		t.Errorf("Entries = %d, want 2", stats.Entries)
+	}
+
+	// 均未过期且已满，写入 d 时清空
+	cache.getOrLoad(context.Background(), "d", load)
+	if stats := cache.stats(); stats.Entries != 1 {
+		t.Errorf("Entries = %d, want 1 after reset", stats.Entries)
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+const (
+	// calendarFeedName 日历名称
+	calendarFeedName = "高考倒计时"
+
+	// calendarFeedHistory 订阅中保留已结束考试的时长
+	calendarFeedHistory = 365 * 24 * time.Hour
+
+	// calendarUIDDomain 事件 UID 后缀
+	calendarUIDDomain = "gaokao_bot"
+
+	// CalendarFeedPath 公共订阅路径
+	CalendarFeedPath = "/api/calendar.ics"
+
+	// CalendarUserFeedPathPrefix 个人订阅路径前缀，后接 <user_id>-<signature>.ics
+	CalendarUserFeedPathPrefix = "/api/calendar/user/"
+)
+
+var (
+	// ErrCalendarFeedNotConfigured 未配置对外访问地址
+	ErrCalendarFeedNotConfigured = errors.New("calendar feed public url not configured")
+
+	// ErrInvalidProvince 省份名称无效
+	ErrInvalidProvince = errors.New("invalid province")
+
+	// ErrInvalidFeedSignature 订阅签名无效
+	ErrInvalidFeedSignature = errors.New("invalid calendar feed signature")
+)
+
+// examAlarms 考试事件提醒：提前 7 天、1 天
+var examAlarms = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour}
+
+// targetAlarms 自定义目标事件提醒：提前 1 天
+var targetAlarms = []time.Duration{24 * time.Hour}
+
+// CalendarFeedService iCalendar 订阅服务
+type CalendarFeedService struct {
+	examRepo   repository.ExamDateRepository
+	targetRepo repository.UserTargetRepository
+	signingKey []byte
+	publicURL  string
+}
+
+// NewCalendarFeedService 创建 iCalendar 订阅服务
+// 个人订阅链接的签名密钥由 Bot Token 派生，publicURL 为空时不生成订阅链接
+func NewCalendarFeedService(
+	examRepo repository.ExamDateRepository,
+	targetRepo repository.UserTargetRepository,
+	botToken string,
+	publicURL string,
+) *CalendarFeedService {
+	mac := hmac.New(sha256.New, []byte("CalendarFeed"))
+	mac.Write([]byte(botToken))
+
+	return &CalendarFeedService{
+		examRepo:   examRepo,
+		targetRepo: targetRepo,
+		signingKey: mac.Sum(nil),
+		publicURL:  strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// BuildPublicFeed 生成公共订阅：全国统一考试，指定省份时附加该省考试
+func (s *CalendarFeedService) BuildPublicFeed(ctx context.Context, province string, now time.Time) (string, error) {
+	events, err := s.examEvents(ctx, province, now)
+	if err != nil {
+		return "", err
+	}
+
+	return util.BuildICalendar(feedName(province), events, now), nil
+}
+
+// BuildUserFeed 生成个人订阅：公共订阅内容加上用户的自定义目标
+func (s *CalendarFeedService) BuildUserFeed(ctx context.Context, userID int64, province string, now time.Time) (string, error) {
+	events, err := s.examEvents(ctx, province, now)
+	if err != nil {
+		return "", err
+	}
+
+	targets, err := s.targetRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	for i := range targets {
+		events = append(events, newTargetEvent(&targets[i]))
+	}
+
+	return util.BuildICalendar(feedName(province), events, now), nil
+}
+
+// Sign 计算用户订阅签名
+func (s *CalendarFeedService) Sign(userID int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(strconv.FormatInt(userID, 10)))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// ParseUserFeedFile 解析个人订阅文件名（<user_id>-<signature>.ics）并校验签名
+func (s *CalendarFeedService) ParseUserFeedFile(file string) (int64, error) {
+	name := strings.TrimSuffix(file, ".ics")
+	idPart, signature, ok := strings.Cut(name, "-")
+	if !ok {
+		return 0, ErrInvalidFeedSignature
+	}
+
+	userID, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidFeedSignature
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.Sign(userID))) {
+		return 0, ErrInvalidFeedSignature
+	}
+
+	return userID, nil
+}
+
+// PublicFeedURL 公共订阅链接
+func (s *CalendarFeedService) PublicFeedURL(province string) (string, error) {
+	if s.publicURL == "" {
+		return "", ErrCalendarFeedNotConfigured
+	}
+
+	return s.publicURL + CalendarFeedPath + provinceQuery(province), nil
+}
+
+// UserFeedURL 个人订阅链接
+func (s *CalendarFeedService) UserFeedURL(userID int64, province string) (string, error) {
+	if s.publicURL == "" {
+		return "", ErrCalendarFeedNotConfigured
+	}
+
+	return fmt.Sprintf("%s%s%d-%s.ics%s",
+		s.publicURL, CalendarUserFeedPathPrefix, userID, s.Sign(userID), provinceQuery(province)), nil
+}
+
+// examEvents 查询订阅范围内的考试并转换为日历事件
+func (s *CalendarFeedService) examEvents(ctx context.Context, province string, now time.Time) ([]util.ICalEvent, error) {
+	if province != "" && !constant.IsValidProvince(province) {
+		return nil, ErrInvalidProvince
+	}
+
+	exams, err := s.examRepo.GetEndingAfter(ctx, now.Add(-calendarFeedHistory))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]util.ICalEvent, 0, len(exams))
+	for i := range exams {
+		exam := &exams[i]
+		if exam.Region != "" && exam.Region != province {
+			continue
+		}
+		events = append(events, newExamEvent(exam))
+	}
+
+	return events, nil
+}
+
+// newExamEvent 考试转换为日历事件
+func newExamEvent(exam *model.ExamDate) util.ICalEvent {
+	return util.ICalEvent{
+		UID:         fmt.Sprintf("exam-%d@%s", exam.ID, calendarUIDDomain),
+		Summary:     exam.ShortDesc,
+		Description: exam.ExamDesc,
+		Start:       exam.ExamBeginDate,
+		End:         exam.ExamEndDate,
+		Alarms:      examAlarms,
+	}
+}
+
+// newTargetEvent 自定义目标转换为日历事件
+// 目标只有时间点，事件时长按 1 小时处理
+func newTargetEvent(target *model.UserTarget) util.ICalEvent {
+	return util.ICalEvent{
+		UID:     fmt.Sprintf("target-%d@%s", target.ID, calendarUIDDomain),
+		Summary: target.TargetName,
+		Start:   target.TargetDate,
+		End:     target.TargetDate.Add(time.Hour),
+		Alarms:  targetAlarms,
+	}
+}
+
+// feedName 日历名称，指定省份时附加省份
+func feedName(province string) string {
+	if province == "" {
+		return calendarFeedName
+	}
+	return calendarFeedName + "（" + province + "）"
+}
+
+// provinceQuery 省份查询参数
+func provinceQuery(province string) string {
+	if province == "" {
+		return ""
+	}
+	return "?province=" + url.QueryEscape(province)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupCalendarFeedTestService(t *testing.T, publicURL string) (*CalendarFeedService, *gorm.DB) {
+	db := setupExamDateTestDB(t)
+	if err := db.AutoMigrate(&model.UserTarget{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	service := NewCalendarFeedService(
+		repository.NewExamDateRepository(db),
+		repository.NewUserTargetRepository(db),
+		"test_bot_token",
+		publicURL,
+	)
+	return service, db
+}
+
+func seedCalendarFeedExams(db *gorm.DB, now time.Time) {
+	begin := now.AddDate(0, 3, 0)
+	exams := []model.ExamDate{
+		{ID: 1, ExamYear: begin.Year(), ExamKind: "gaokao", ShortDesc: "全国高考", ExamDesc: "普通高等学校招生全国统一考试"},
+		{ID: 2, ExamYear: begin.Year(), ExamKind: "xuekao", Region: "北京", ShortDesc: "北京学考"},
+		{ID: 3, ExamYear: begin.Year(), ExamKind: "xuekao", Region: "上海", ShortDesc: "上海学考"},
+		{ID: 4, ExamYear: begin.Year() - 3, ExamKind: "gaokao", ShortDesc: "旧高考"},
+	}
+	for i := range exams {
+		exam := &exams[i]
+		start := begin
+		if exam.ID == 4 {
+			start = now.AddDate(-3, 0, 0)
+		}
+		exam.ExamBeginDate = start
+		exam.ExamEndDate = start.AddDate(0, 0, 3)
+		exam.ExamYearBeginDate = start.AddDate(-1, 0, 0)
+		exam.ExamYearEndDate = start.AddDate(0, 0, 3)
+		db.Create(exam)
+	}
+}
+
+func TestCalendarFeedService_BuildPublicFeed(t *testing.T) {
+	service, db := setupCalendarFeedTestService(t, "")
+	now := util.NowBJT()
+	seedCalendarFeedExams(db, now)
+
+	feed, err := service.BuildPublicFeed(context.Background(), "", now)
+	if err != nil {
+		t.Fatalf("BuildPublicFeed() error = %v", err)
+	}
+	if !strings.Contains(feed, "SUMMARY:全国高考") {
+		t.Error("Expected nationwide exam in public feed")
+	}
+	if strings.Contains(feed, "北京学考") || strings.Contains(feed, "上海学考") {
+		t.Error("Expected no provincial exams without province")
+	}
+	if strings.Contains(feed, "旧高考") {
+		t.Error("Expected exams ended long ago to be excluded")
+	}
+	if !strings.Contains(feed, "TRIGGER:-P7D") || !strings.Contains(feed, "TRIGGER:-P1D") {
+		t.Error("Expected exam alarms")
+	}
+
+	feed, err = service.BuildPublicFeed(context.Background(), "北京", now)
+	if err != nil {
+		t.Fatalf("BuildPublicFeed() error = %v", err)
+	}
+	if !strings.Contains(feed, "SUMMARY:北京学考") || strings.Contains(feed, "上海学考") {
+		t.Error("Expected only Beijing provincial exams")
+	}
+	if !strings.Contains(feed, "X-WR-CALNAME:高考倒计时（北京）") {
+		t.Error("Expected province in calendar name")
+	}
+}
+
+func TestCalendarFeedService_BuildPublicFeed_InvalidProvince(t *testing.T) {
+	service, _ := setupCalendarFeedTestService(t, "")
+
+	_, err := service.BuildPublicFeed(context.Background(), "火星", util.NowBJT())
+	if !errors.Is(err, ErrInvalidProvince) {
+		t.Errorf("BuildPublicFeed() error = %v, want ErrInvalidProvince", err)
+	}
+}
+
+func TestCalendarFeedService_BuildUserFeed(t *testing.T) {
+	service, db := setupCalendarFeedTestService(t, "")
+	now := util.NowBJT()
+	seedCalendarFeedExams(db, now)
+	db.Create(&model.UserTarget{ID: 10, UserID: 123, TargetName: "一模", TargetDate: now.AddDate(0, 1, 0)})
+	db.Create(&model.UserTarget{ID: 11, UserID: 456, TargetName: "别人的目标", TargetDate: now.AddDate(0, 1, 0)})
+
+	feed, err := service.BuildUserFeed(context.Background(), 123, "", now)
+	if err != nil {
+		t.Fatalf("BuildUserFeed() error = %v", err)
+	}
+	if !strings.Contains(feed, "SUMMARY:一模") || !strings.Contains(feed, "UID:target-10@gaokao_bot") {
+		t.Error("Expected user target in personal feed")
+	}
+	if strings.Contains(feed, "别人的目标") {
+		t.Error("Expected other users' targets to be excluded")
+	}
+	if !strings.Contains(feed, "SUMMARY:全国高考") {
+		t.Error("Expected nationwide exam in personal feed")
+	}
+}
+
+func TestCalendarFeedService_SignAndParse(t *testing.T) {
+	service, _ := setupCalendarFeedTestService(t, "")
+
+	signature := service.Sign(123)
+	userID, err := service.ParseUserFeedFile("123-" + signature + ".ics")
+	if err != nil || userID != 123 {
+		t.Errorf("ParseUserFeedFile() = %d, %v; want 123, nil", userID, err)
+	}
+
+	for _, file := range []string{
+		"124-" + signature + ".ics",
+		"123-deadbeef.ics",
+		"123.ics",
+		"abc-" + signature + ".ics",
+	} {
+		if _, err := service.ParseUserFeedFile(file); !errors.Is(err, ErrInvalidFeedSignature) {
+			t.Errorf("ParseUserFeedFile(%q) error = %v, want ErrInvalidFeedSignature", file, err)
+		}
+	}
+
+	other := NewCalendarFeedService(nil, nil, "another_token", "")
+	if other.Sign(123) == signature {
+		t.Error("Expected signatures to depend on bot token")
+	}
+}
+
+func TestCalendarFeedService_URLs(t *testing.T) {
+	service, _ := setupCalendarFeedTestService(t, "https://example.com/")
+
+	publicURL, err := service.PublicFeedURL("")
+	if err != nil || publicURL != "https://example.com/api/calendar.ics" {
+		t.Errorf("PublicFeedURL() = %s, %v", publicURL, err)
+	}
+
+	userURL, err := service.UserFeedURL(123, "北京")
+	if err != nil {
+		t.Fatalf("UserFeedURL() error = %v", err)
+	}
+	want := "https://example.com/api/calendar/user/123-" + service.Sign(123) + ".ics?province=%E5%8C%97%E4%BA%AC"
+	if userURL != want {
+		t.Errorf("UserFeedURL() = %s, want %s", userURL, want)
+	}
+
+	unconfigured, _ := setupCalendarFeedTestService(t, "")
+	if _, err := unconfigured.PublicFeedURL(""); !errors.Is(err, ErrCalendarFeedNotConfigured) {
+		t.Errorf("PublicFeedURL() error = %v, want ErrCalendarFeedNotConfigured", err)
+	}
+	if _, err := unconfigured.UserFeedURL(123, ""); !errors.Is(err, ErrCalendarFeedNotConfigured) {
+		t.Errorf("UserFeedURL() error = %v, want ErrCalendarFeedNotConfigured", err)
+	}
+}
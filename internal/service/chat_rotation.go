@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+// MaxChatTemplatePoolSize 推送对话模板池的模板数量上限
+const MaxChatTemplatePoolSize = 20
+
+// ChatRotationError 模板池设置未通过校验，Message 为面向管理员的提示
+type ChatRotationError struct {
+	Message string
+}
+
+func (e *ChatRotationError) Error() string {
+	return e.Message
+}
+
+// ChatTemplateRef 模板池中的模板引用
+type ChatTemplateRef struct {
+	Source     string `json:"source"`
+	TemplateID int64  `json:"template_id,string"`
+}
+
+// ChatRotation 推送对话的模板池和轮换设置
+type ChatRotation struct {
+	AdminUserID int64                    `json:"admin_user_id,string"`
+	Strategy    string                   `json:"strategy"`
+	Templates   []model.SendChatTemplate `json:"templates"`
+	// LastEntryID 上次使用的模板池条目 ID
+	LastEntryID   int64      `json:"last_entry_id,string"`
+	LastRotatedAt *time.Time `json:"last_rotated_at"`
+}
+
+// WithTemplatePool 启用模板池：设置模板池时检查官方模板和管理员的个人模板是否存在
+func (s *SendChatService) WithTemplatePool(officialService *OfficialTemplateService, userTemplateService *UserTemplateService) *SendChatService {
+	s.officialService = officialService
+	s.userTemplateService = userTemplateService
+	return s
+}
+
+// GetRotation 获取推送对话的模板池和轮换设置，对话不存在时返回 ErrSendChatNotFound
+func (s *SendChatService) GetRotation(ctx context.Context, id int64) (*ChatRotation, error) {
+	chat, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		return nil, ErrSendChatNotFound
+	}
+
+	pool, err := s.repo.GetTemplatePool(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		pool = []model.SendChatTemplate{}
+	}
+	return &ChatRotation{
+		AdminUserID:   chat.AdminUserID,
+		Strategy:      chat.RotationStrategy,
+		Templates:     pool,
+		LastEntryID:   chat.LastEntryID,
+		LastRotatedAt: chat.LastRotatedAt,
+	}, nil
+}
+
+// SetRotation 替换推送对话的模板池并设置管理员和轮换策略，strategy 为空时停止轮换
+// 模板池可包含官方模板和管理员（adminUserID）的个人模板，按引用顺序排列；
+// 对话不存在时返回 ErrSendChatNotFound，未通过校验时返回 *ChatRotationError
+func (s *SendChatService) SetRotation(ctx context.Context, id, adminUserID int64, strategy string, refs []ChatTemplateRef) (*ChatRotation, error) {
+	chat, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		return nil, ErrSendChatNotFound
+	}
+
+	if err := s.validateRotation(ctx, adminUserID, strategy, refs); err != nil {
+		return nil, err
+	}
+
+	pool := make([]*model.SendChatTemplate, 0, len(refs))
+	for i, ref := range refs {
+		entryID, err := util.GenerateID()
+		if err != nil {
+			return nil, err
+		}
+		pool = append(pool, &model.SendChatTemplate{
+			ID:         entryID,
+			SendChatID: id,
+			Source:     ref.Source,
+			TemplateID: ref.TemplateID,
+			SortOrder:  i,
+		})
+	}
+
+	if err := s.repo.UpdateRotation(ctx, id, adminUserID, strategy, pool); err != nil {
+		return nil, err
+	}
+	return s.GetRotation(ctx, id)
+}
+
+// validateRotation 校验轮换策略和模板池，模板不存在或不属于管理员时返回 *ChatRotationError
+func (s *SendChatService) validateRotation(ctx context.Context, adminUserID int64, strategy string, refs []ChatTemplateRef) error {
+	switch strategy {
+	case "", model.RotationRoundRobin, model.RotationRandom, model.RotationWeekday:
+	default:
+		return &ChatRotationError{Message: fmt.Sprintf("不支持的轮换策略 '%s'，可选值: round_robin、random、weekday", strategy)}
+	}
+	if strategy != "" && len(refs) == 0 {
+		return &ChatRotationError{Message: "启用轮换时模板池不能为空"}
+	}
+	if len(refs) > MaxChatTemplatePoolSize {
+		return &ChatRotationError{Message: fmt.Sprintf("模板池最多包含 %d 个模板", MaxChatTemplatePoolSize)}
+	}
+
+	seen := make(map[ChatTemplateRef]bool, len(refs))
+	for _, ref := range refs {
+		if seen[ref] {
+			return &ChatRotationError{Message: fmt.Sprintf("模板 %d 重复", ref.TemplateID)}
+		}
+		seen[ref] = true
+
+		switch ref.Source {
+		case model.ChatTemplateSourceOfficial:
+			if s.officialService == nil {
+				return &ChatRotationError{Message: "未启用官方模板"}
+			}
+			template, err := s.officialService.GetByID(ctx, ref.TemplateID)
+			if err != nil {
+				return err
+			}
+			if template == nil {
+				return &ChatRotationError{Message: fmt.Sprintf("官方模板 %d 不存在", ref.TemplateID)}
+			}
+		case model.ChatTemplateSourceUser:
+			if adminUserID == 0 {
+				return &ChatRotationError{Message: "使用个人模板前需设置对话管理员"}
+			}
+			if s.userTemplateService == nil {
+				return &ChatRotationError{Message: "未启用个人模板"}
+			}
+			template, err := s.userTemplateService.GetByID(ctx, ref.TemplateID)
+			if err != nil {
+				return err
+			}
+			if template == nil || template.UserID != adminUserID {
+				return &ChatRotationError{Message: fmt.Sprintf("个人模板 %d 不存在或不属于对话管理员", ref.TemplateID)}
+			}
+		default:
+			return &ChatRotationError{Message: fmt.Sprintf("不支持的模板来源 '%s'，可选值: official、user", ref.Source)}
+		}
+	}
+	return nil
+}
+
+// GetAllTemplatePools 获取全部推送对话的模板池，按对话 ID 分组
+func (s *SendChatService) GetAllTemplatePools(ctx context.Context) (map[int64][]model.SendChatTemplate, error) {
+	entries, err := s.repo.GetAllTemplatePools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pools := make(map[int64][]model.SendChatTemplate)
+	for _, entry := range entries {
+		pools[entry.SendChatID] = append(pools[entry.SendChatID], entry)
+	}
+	return pools, nil
+}
+
+// RotateTemplate 按对话的轮换策略选取 now 所在日期（北京时间）使用的模板池条目
+// 同一天内已轮换过时沿用上次的条目；否则选取新条目并记录到对话（同时更新 chat），
+// 记录失败时仍返回选中的条目。未启用轮换或模板池为空时返回 nil
+func (s *SendChatService) RotateTemplate(ctx context.Context, chat *model.SendChat, pool []model.SendChatTemplate, now time.Time) (*model.SendChatTemplate, error) {
+	if chat.RotationStrategy == "" || len(pool) == 0 {
+		return nil, nil
+	}
+
+	if chat.LastRotatedAt != nil && sameBJTDay(*chat.LastRotatedAt, now) {
+		for i := range pool {
+			if pool[i].ID == chat.LastEntryID {
+				return &pool[i], nil
+			}
+		}
+	}
+
+	entry := PickPoolEntry(chat.RotationStrategy, chat.ID, pool, chat.LastEntryID, now)
+	if entry == nil {
+		return nil, nil
+	}
+	chat.LastEntryID = entry.ID
+	chat.LastRotatedAt = &now
+	return entry, s.repo.UpdateLastEntry(ctx, chat.ID, entry.ID, now)
+}
+
+// PickPoolEntry 按轮换策略从模板池中选取条目，lastEntryID 为上次使用的条目
+//   - round_robin：上次条目的下一个，上次条目不在池中时从第一个开始
+//   - random：按对话（chatID）打乱顺序的随机不重复轮换，见 pickShuffledEntry
+//   - weekday：按星期（北京时间）选取，周一为第一个，模板数量少于 7 个时循环使用
+func PickPoolEntry(strategy string, chatID int64, pool []model.SendChatTemplate, lastEntryID int64, now time.Time) *model.SendChatTemplate {
+	if len(pool) == 0 {
+		return nil
+	}
+
+	switch strategy {
+	case model.RotationRoundRobin:
+		for i := range pool {
+			if pool[i].ID == lastEntryID {
+				return &pool[(i+1)%len(pool)]
+			}
+		}
+		return &pool[0]
+	case model.RotationRandom:
+		return &pool[pickShuffledEntry(len(pool), chatID, quoteDay(now))]
+	case model.RotationWeekday:
+		weekday := (int(now.In(util.GetBJTLocation()).Weekday()) + 6) % 7 // 周一为 0
+		return &pool[weekday%len(pool)]
+	}
+	return nil
+}
+
+// pickShuffledEntry 随机不重复轮换：返回 day（北京时间日期序号）使用的模板池下标
+// 每个对话以 n 天（n 为模板数量）为一轮，每轮按对话和轮次打乱顺序，轮内每个模板使用一次。
+// 为避免相邻两轮的交界处重复，模板多于两个时若本轮第一个与上一轮最后一个相同，则与第二个交换
+// （不影响本轮最后一个）；只有两个模板时各轮使用相同顺序，即交替使用
+func pickShuffledEntry(n int, chatID int64, day int64) int {
+	if n == 1 {
+		return 0
+	}
+
+	cycle, pos := day/int64(n), int(day%int64(n))
+	if n == 2 {
+		cycle = 0
+	}
+	order := shuffledPoolOrder(n, chatID, cycle)
+	if n > 2 && order[0] == shuffledPoolOrder(n, chatID, cycle-1)[n-1] {
+		order[0], order[1] = order[1], order[0]
+	}
+	return order[pos]
+}
+
+// shuffledPoolOrder 按对话和轮次打乱的模板池下标，相同参数的顺序固定
+func shuffledPoolOrder(n int, chatID int64, cycle int64) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	r := quoteRand("chat_rotation#"+strconv.FormatInt(chatID, 10), strconv.FormatInt(cycle, 10))
+	r.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+	return order
+}
+
+// sameBJTDay 判断两个时间是否在北京时间的同一天
+func sameBJTDay(a, b time.Time) bool {
+	loc := util.GetBJTLocation()
+	ay, am, ad := a.In(loc).Date()
+	by, bm, bd := b.In(loc).Date()
+	return ay == by && am == bm && ad == bd
+}
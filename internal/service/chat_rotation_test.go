@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupChatRotationTestService(t *testing.T) (*SendChatService, *gorm.DB) {
+	_ = util.InitSnowflake(0, 1)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.SendChat{}, &model.SendChatTemplate{}, &model.OfficialTemplate{}, &model.UserTemplate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	service := NewSendChatService(repository.NewSendChatRepository(db)).WithTemplatePool(
+		NewOfficialTemplateService(repository.NewOfficialTemplateRepository(db)),
+		NewUserTemplateService(repository.NewUserTemplateRepository(db)),
+	)
+	return service, db
+}
+
+func TestSendChatService_SetRotation(t *testing.T) {
+	service, db := setupChatRotationTestService(t)
+	ctx := context.Background()
+	db.Create(&model.SendChat{ID: 1, ChatID: "-100123"})
+	db.Create(&model.OfficialTemplate{ID: 10, TemplateContent: "官方{exam}", Active: true})
+	db.Create(&model.UserTemplate{ID: 20, UserID: 7, TemplateContent: "管理员{exam}"})
+	db.Create(&model.UserTemplate{ID: 21, UserID: 8, TemplateContent: "他人{exam}"})
+
+	refs := []ChatTemplateRef{
+		{Source: model.ChatTemplateSourceUser, TemplateID: 20},
+		{Source: model.ChatTemplateSourceOfficial, TemplateID: 10},
+	}
+	rotation, err := service.SetRotation(ctx, 1, 7, model.RotationRoundRobin, refs)
+	if err != nil {
+		t.Fatalf("SetRotation() error = %v", err)
+	}
+	if rotation.AdminUserID != 7 || rotation.Strategy != model.RotationRoundRobin || len(rotation.Templates) != 2 {
+		t.Fatalf("SetRotation() = %+v", rotation)
+	}
+	if rotation.Templates[0].TemplateID != 20 || rotation.Templates[1].TemplateID != 10 {
+		t.Errorf("Pool order = %+v, want refs order", rotation.Templates)
+	}
+
+	tests := []struct {
+		name        string
+		adminUserID int64
+		strategy    string
+		refs        []ChatTemplateRef
+	}{
+		{"invalid strategy", 7, "daily", refs},
+		{"empty pool", 7, model.RotationRandom, nil},
+		{"duplicate", 7, model.RotationRandom, []ChatTemplateRef{refs[1], refs[1]}},
+		{"missing official", 7, model.RotationRandom, []ChatTemplateRef{{Source: model.ChatTemplateSourceOfficial, TemplateID: 99}}},
+		{"other user's template", 7, model.RotationRandom, []ChatTemplateRef{{Source: model.ChatTemplateSourceUser, TemplateID: 21}}},
+		{"user template without admin", 0, model.RotationRandom, []ChatTemplateRef{refs[0]}},
+		{"invalid source", 7, model.RotationRandom, []ChatTemplateRef{{Source: "gallery", TemplateID: 10}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SetRotation(ctx, 1, tt.adminUserID, tt.strategy, tt.refs)
+			var rotationErr *ChatRotationError
+			if !errors.As(err, &rotationErr) {
+				t.Errorf("SetRotation() error = %v, want *ChatRotationError", err)
+			}
+		})
+	}
+
+	tooMany := make([]ChatTemplateRef, MaxChatTemplatePoolSize+1)
+	for i := range tooMany {
+		tooMany[i] = ChatTemplateRef{Source: model.ChatTemplateSourceOfficial, TemplateID: int64(100 + i)}
+	}
+	var rotationErr *ChatRotationError
+	if _, err := service.SetRotation(ctx, 1, 7, model.RotationRandom, tooMany); !errors.As(err, &rotationErr) {
+		t.Errorf("SetRotation() oversized pool error = %v, want *ChatRotationError", err)
+	}
+
+	// 失败的设置不应改动已有模板池
+	if rotation, _ := service.GetRotation(ctx, 1); len(rotation.Templates) != 2 {
+		t.Errorf("Pool changed by failed SetRotation: %+v", rotation.Templates)
+	}
+
+	// 停止轮换并清空模板池
+	rotation, err = service.SetRotation(ctx, 1, 0, "", nil)
+	if err != nil || rotation.Strategy != "" || len(rotation.Templates) != 0 {
+		t.Errorf("SetRotation() clear = %+v, %v", rotation, err)
+	}
+
+	if _, err := service.SetRotation(ctx, 999, 7, "", nil); !errors.Is(err, ErrSendChatNotFound) {
+		t.Errorf("SetRotation() missing chat error = %v, want ErrSendChatNotFound", err)
+	}
+	if _, err := service.GetRotation(ctx, 999); !errors.Is(err, ErrSendChatNotFound) {
+		t.Errorf("GetRotation() missing chat error = %v, want ErrSendChatNotFound", err)
+	}
+}
+
+func TestSendChatService_RotateTemplate(t *testing.T) {
+	service, db := setupChatRotationTestService(t)
+	ctx := context.Background()
+	db.Create(&model.SendChat{ID: 1, ChatID: "-100123"})
+	db.Create(&model.OfficialTemplate{ID: 10, TemplateContent: "A", Active: true})
+	db.Create(&model.OfficialTemplate{ID: 11, TemplateContent: "B", Active: true})
+
+	refs := []ChatTemplateRef{
+		{Source: model.ChatTemplateSourceOfficial, TemplateID: 10},
+		{Source: model.ChatTemplateSourceOfficial, TemplateID: 11},
+	}
+	if _, err := service.SetRotation(ctx, 1, 0, model.RotationRoundRobin, refs); err != nil {
+		t.Fatalf("SetRotation() error = %v", err)
+	}
+	pools, err := service.GetAllTemplatePools(ctx)
+	if err != nil || len(pools[1]) != 2 {
+		t.Fatalf("GetAllTemplatePools() = %v, %v", pools, err)
+	}
+
+	day1 := time.Date(2026, 5, 1, 8, 0, 0, 0, util.GetBJTLocation())
+	rotate := func(now time.Time) int64 {
+		chat, _ := service.GetByID(ctx, 1)
+		entry, err := service.RotateTemplate(ctx, chat, pools[1], now)
+		if err != nil || entry == nil {
+			t.Fatalf("RotateTemplate(%v) = %v, %v", now, entry, err)
+		}
+		return entry.TemplateID
+	}
+
+	if got := rotate(day1); got != 10 {
+		t.Errorf("Day 1 template = %d, want 10", got)
+	}
+	// 同一天再次推送沿用当天的模板
+	if got := rotate(day1.Add(3 * time.Hour)); got != 10 {
+		t.Errorf("Day 1 again template = %d, want 10", got)
+	}
+	if got := rotate(day1.AddDate(0, 0, 1)); got != 11 {
+		t.Errorf("Day 2 template = %d, want 11", got)
+	}
+	if got := rotate(day1.AddDate(0, 0, 2)); got != 10 {
+		t.Errorf("Day 3 template = %d, want 10", got)
+	}
+
+	// 未启用轮换时不选取
+	if entry, err := service.RotateTemplate(ctx, &model.SendChat{ID: 1}, pools[1], day1); entry != nil || err != nil {
+		t.Errorf("RotateTemplate() without strategy = %v, %v, want nil", entry, err)
+	}
+}
+
+func TestPickPoolEntry(t *testing.T) {
+	pool := []model.SendChatTemplate{{ID: 1}, {ID: 2}, {ID: 3}}
+	monday := time.Date(2026, 5, 4, 8, 0, 0, 0, util.GetBJTLocation())
+
+	tests := []struct {
+		name     string
+		strategy string
+		last     int64
+		now      time.Time
+		want     int64
+	}{
+		{"round robin first", model.RotationRoundRobin, 0, monday, 1},
+		{"round robin next", model.RotationRoundRobin, 1, monday, 2},
+		{"round robin wraps", model.RotationRoundRobin, 3, monday, 1},
+		{"weekday monday", model.RotationWeekday, 0, monday, 1},
+		{"weekday wednesday", model.RotationWeekday, 0, monday.AddDate(0, 0, 2), 3},
+		{"weekday thursday wraps", model.RotationWeekday, 0, monday.AddDate(0, 0, 3), 1},
+		{"weekday sunday", model.RotationWeekday, 0, monday.AddDate(0, 0, 6), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PickPoolEntry(tt.strategy, 1, pool, tt.last, tt.now)
+			if got == nil || got.ID != tt.want {
+				t.Errorf("PickPoolEntry() = %+v, want ID %d", got, tt.want)
+			}
+		})
+	}
+
+	// 只有一个模板时随机轮换仍可选取
+	if got := PickPoolEntry(model.RotationRandom, 1, pool[:1], 1, monday); got == nil || got.ID != 1 {
+		t.Errorf("PickPoolEntry() single = %+v, want ID 1", got)
+	}
+	if got := PickPoolEntry(model.RotationRoundRobin, 1, nil, 0, monday); got != nil {
+		t.Errorf("PickPoolEntry() empty pool = %+v, want nil", got)
+	}
+}
+
+func TestPickPoolEntry_RandomWithoutRepetition(t *testing.T) {
+	start := time.Date(2026, 5, 4, 8, 0, 0, 0, util.GetBJTLocation())
+
+	for n := 2; n <= 7; n++ {
+		pool := make([]model.SendChatTemplate, n)
+		for i := range pool {
+			pool[i].ID = int64(i + 1)
+		}
+
+		// 每轮从日期序号为 n 的倍数的一天开始
+		first := start
+		for quoteDay(first)%int64(n) != 0 {
+			first = first.AddDate(0, 0, 1)
+		}
+
+		for _, chatID := range []int64{1, 2, 42} {
+			var previous int64
+			for day := 0; day < 10*n; day += n {
+				// 每轮连续 n 天使用 n 个不同的模板
+				seen := make(map[int64]bool, n)
+				for offset := 0; offset < n; offset++ {
+					now := first.AddDate(0, 0, day+offset)
+					got := PickPoolEntry(model.RotationRandom, chatID, pool, 0, now)
+					if got == nil || seen[got.ID] {
+						t.Fatalf("n=%d chat=%d day %d: PickPoolEntry() = %+v repeats within %d days", n, chatID, day+offset, got, n)
+					}
+					seen[got.ID] = true
+
+					// 相邻两天（包括两轮交界处）不重复
+					if got.ID == previous {
+						t.Fatalf("n=%d chat=%d day %d: same template %d as previous day", n, chatID, day+offset, got.ID)
+					}
+					previous = got.ID
+
+					// 同一天结果相同
+					if again := PickPoolEntry(model.RotationRandom, chatID, pool, 0, now.Add(time.Hour)); again.ID != got.ID {
+						t.Errorf("n=%d chat=%d day %d: PickPoolEntry() not stable within a day", n, chatID, day+offset)
+					}
+				}
+			}
+		}
+	}
+}
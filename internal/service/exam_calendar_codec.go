@@ -0,0 +1,374 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// CalendarFormat 考试日历文件格式
+type CalendarFormat string
+
+const (
+	// CalendarFormatJSON JSON 格式
+	CalendarFormatJSON CalendarFormat = "json"
+
+	// CalendarFormatYAML YAML 格式
+	CalendarFormatYAML CalendarFormat = "yaml"
+
+	// CalendarFormatCSV CSV 格式（首行为表头）
+	CalendarFormatCSV CalendarFormat = "csv"
+
+	// ExamCalendarVersion 当前考试日历文件版本
+	ExamCalendarVersion = 1
+
+	// calendarTimeLayout 日历文件中的时间格式（北京时间）
+	calendarTimeLayout = "2006-01-02 15:04:05"
+)
+
+// calendarCSVHeader CSV 表头，列顺序与 ExamCalendarEntry 字段一致
+var calendarCSVHeader = []string{
+	"year", "kind", "desc", "short_desc",
+	"begin_date", "end_date", "year_begin_date", "year_end_date", "region",
+}
+
+// calendarCSVOptionalColumns CSV 中可省略的列（兼容早期导出的文件）
+var calendarCSVOptionalColumns = map[string]bool{
+	"region": true,
+}
+
+// ExamCalendar 考试日历文件
+type ExamCalendar struct {
+	Version int                 `json:"version" yaml:"version"`
+	Exams   []ExamCalendarEntry `json:"exams" yaml:"exams"`
+}
+
+// ExamCalendarEntry 考试日历条目
+// 时间字段均为北京时间，格式为 2006-01-02 15:04:05；Region 为空表示全国统一考试
+type ExamCalendarEntry struct {
+	Year          int    `json:"year" yaml:"year"`
+	Kind          string `json:"kind" yaml:"kind"`
+	Desc          string `json:"desc" yaml:"desc"`
+	ShortDesc     string `json:"short_desc" yaml:"short_desc"`
+	BeginDate     string `json:"begin_date" yaml:"begin_date"`
+	EndDate       string `json:"end_date" yaml:"end_date"`
+	YearBeginDate string `json:"year_begin_date" yaml:"year_begin_date"`
+	YearEndDate   string `json:"year_end_date" yaml:"year_end_date"`
+	Region        string `json:"region,omitempty" yaml:"region,omitempty"`
+}
+
+// ParseCalendarFormat 解析格式名称（不区分大小写，yml 视为 yaml）
+func ParseCalendarFormat(name string) (CalendarFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "json":
+		return CalendarFormatJSON, nil
+	case "yaml", "yml":
+		return CalendarFormatYAML, nil
+	case "csv":
+		return CalendarFormatCSV, nil
+	default:
+		return "", fmt.Errorf("不支持的日历格式: %s（可选 json / yaml / csv）", name)
+	}
+}
+
+// CalendarFormatFromPath 根据文件扩展名推断格式
+func CalendarFormatFromPath(path string) (CalendarFormat, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("无法从文件名推断日历格式: %s", path)
+	}
+	return ParseCalendarFormat(ext)
+}
+
+// ContentType 返回格式对应的 HTTP Content-Type
+func (f CalendarFormat) ContentType() string {
+	switch f {
+	case CalendarFormatYAML:
+		return "application/yaml; charset=utf-8"
+	case CalendarFormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// DecodeExamCalendar 按指定格式读取考试日历
+func DecodeExamCalendar(r io.Reader, format CalendarFormat) (*ExamCalendar, error) {
+	var cal ExamCalendar
+
+	switch format {
+	case CalendarFormatJSON:
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cal); err != nil {
+			return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+		}
+	case CalendarFormatYAML:
+		if err := yaml.NewDecoder(r, yaml.DisallowUnknownField()).Decode(&cal); err != nil {
+			return nil, fmt.Errorf("解析 YAML 失败: %w", err)
+		}
+	case CalendarFormatCSV:
+		entries, err := decodeCalendarCSV(r)
+		if err != nil {
+			return nil, err
+		}
+		cal.Version = ExamCalendarVersion
+		cal.Exams = entries
+	default:
+		return nil, fmt.Errorf("不支持的日历格式: %s", format)
+	}
+
+	if cal.Version == 0 {
+		cal.Version = ExamCalendarVersion
+	}
+	if cal.Version > ExamCalendarVersion {
+		return nil, fmt.Errorf("不支持的日历版本: %d（当前支持 %d）", cal.Version, ExamCalendarVersion)
+	}
+
+	return &cal, nil
+}
+
+// EncodeExamCalendar 按指定格式写出考试日历
+func EncodeExamCalendar(w io.Writer, cal *ExamCalendar, format CalendarFormat) error {
+	switch format {
+	case CalendarFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(cal)
+	case CalendarFormatYAML:
+		data, err := yaml.Marshal(cal)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case CalendarFormatCSV:
+		return encodeCalendarCSV(w, cal.Exams)
+	default:
+		return fmt.Errorf("不支持的日历格式: %s", format)
+	}
+}
+
+// decodeCalendarCSV 读取 CSV，表头列可任意顺序，但必须包含全部列
+func decodeCalendarCSV(r io.Reader) ([]ExamCalendarEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("CSV 内容为空")
+		}
+		return nil, fmt.Errorf("读取 CSV 表头失败: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// 去除 Excel 导出时可能带有的 UTF-8 BOM
+		name = strings.TrimPrefix(strings.TrimSpace(name), "\ufeff")
+		columns[strings.ToLower(name)] = i
+	}
+	for _, name := range calendarCSVHeader {
+		if _, ok := columns[name]; !ok && !calendarCSVOptionalColumns[name] {
+			return nil, fmt.Errorf("CSV 缺少列: %s", name)
+		}
+	}
+
+	var entries []ExamCalendarEntry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 CSV 第 %d 行失败: %w", line, err)
+		}
+
+		year, err := strconv.Atoi(strings.TrimSpace(record[columns["year"]]))
+		if err != nil {
+			return nil, fmt.Errorf("CSV 第 %d 行年份无效: %s", line, record[columns["year"]])
+		}
+
+		entry := ExamCalendarEntry{
+			Year:          year,
+			Kind:          record[columns["kind"]],
+			Desc:          record[columns["desc"]],
+			ShortDesc:     record[columns["short_desc"]],
+			BeginDate:     record[columns["begin_date"]],
+			EndDate:       record[columns["end_date"]],
+			YearBeginDate: record[columns["year_begin_date"]],
+			YearEndDate:   record[columns["year_end_date"]],
+		}
+		if idx, ok := columns["region"]; ok {
+			entry.Region = strings.TrimSpace(record[idx])
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// encodeCalendarCSV 写出 CSV（含表头）
+func encodeCalendarCSV(w io.Writer, entries []ExamCalendarEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(calendarCSVHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		record := []string{
+			strconv.Itoa(entry.Year),
+			entry.Kind,
+			entry.Desc,
+			entry.ShortDesc,
+			entry.BeginDate,
+			entry.EndDate,
+			entry.YearBeginDate,
+			entry.YearEndDate,
+			entry.Region,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Validate 校验日历内容：年份范围、时间格式、时间先后、地区以及年份+类型+地区唯一
+func (c *ExamCalendar) Validate() error {
+	seen := make(map[examCalendarKey]int, len(c.Exams))
+
+	for i := range c.Exams {
+		entry := &c.Exams[i]
+		if entry.Kind == "" {
+			entry.Kind = constant.ExamKindGaokao
+		}
+
+		if _, err := entry.toModel(); err != nil {
+			return fmt.Errorf("第 %d 条（%s）: %w", i+1, entry.key(), err)
+		}
+
+		key := entry.key()
+		if prev, ok := seen[key]; ok {
+			return fmt.Errorf("第 %d 条与第 %d 条重复（%s）", i+1, prev, key)
+		}
+		seen[key] = i + 1
+	}
+
+	return nil
+}
+
+// examCalendarKey 考试日历唯一键（年份 + 类型 + 地区）
+type examCalendarKey struct {
+	year   int
+	kind   string
+	region string
+}
+
+// String 以 年份/类型[/地区] 形式输出
+func (k examCalendarKey) String() string {
+	if k.region == "" {
+		return fmt.Sprintf("%d/%s", k.year, k.kind)
+	}
+	return fmt.Sprintf("%d/%s/%s", k.year, k.kind, k.region)
+}
+
+func (e *ExamCalendarEntry) key() examCalendarKey {
+	return examCalendarKey{year: e.Year, kind: e.Kind, region: e.Region}
+}
+
+// toModel 将条目转换为考试实体（不含 ID），同时完成字段校验
+func (e *ExamCalendarEntry) toModel() (model.ExamDate, error) {
+	if e.Year < constant.MinExamYear || e.Year > constant.MaxExamYear {
+		return model.ExamDate{}, fmt.Errorf("年份必须在 %d-%d 范围内", constant.MinExamYear, constant.MaxExamYear)
+	}
+	if strings.TrimSpace(e.Desc) == "" {
+		return model.ExamDate{}, fmt.Errorf("desc 不能为空")
+	}
+	if e.Region != "" && !constant.IsValidProvince(e.Region) {
+		return model.ExamDate{}, fmt.Errorf("region 无效: %s", e.Region)
+	}
+
+	begin, err := parseCalendarTime("begin_date", e.BeginDate)
+	if err != nil {
+		return model.ExamDate{}, err
+	}
+	end, err := parseCalendarTime("end_date", e.EndDate)
+	if err != nil {
+		return model.ExamDate{}, err
+	}
+	yearBegin, err := parseCalendarTime("year_begin_date", e.YearBeginDate)
+	if err != nil {
+		return model.ExamDate{}, err
+	}
+	yearEnd, err := parseCalendarTime("year_end_date", e.YearEndDate)
+	if err != nil {
+		return model.ExamDate{}, err
+	}
+
+	if !end.After(begin) {
+		return model.ExamDate{}, fmt.Errorf("end_date 必须晚于 begin_date")
+	}
+	if !yearEnd.After(yearBegin) {
+		return model.ExamDate{}, fmt.Errorf("year_end_date 必须晚于 year_begin_date")
+	}
+
+	return model.ExamDate{
+		ExamYear:          e.Year,
+		ExamKind:          e.Kind,
+		Region:            e.Region,
+		ExamDesc:          e.Desc,
+		ShortDesc:         e.ShortDesc,
+		ExamBeginDate:     begin,
+		ExamEndDate:       end,
+		ExamYearBeginDate: yearBegin,
+		ExamYearEndDate:   yearEnd,
+	}, nil
+}
+
+// newExamCalendarEntry 由考试实体生成日历条目（时间统一转换为北京时间）
+func newExamCalendarEntry(exam *model.ExamDate) ExamCalendarEntry {
+	kind := exam.ExamKind
+	if kind == "" {
+		kind = constant.ExamKindGaokao
+	}
+
+	return ExamCalendarEntry{
+		Year:          exam.ExamYear,
+		Kind:          kind,
+		Desc:          exam.ExamDesc,
+		ShortDesc:     exam.ShortDesc,
+		BeginDate:     formatCalendarTime(exam.ExamBeginDate),
+		EndDate:       formatCalendarTime(exam.ExamEndDate),
+		YearBeginDate: formatCalendarTime(exam.ExamYearBeginDate),
+		YearEndDate:   formatCalendarTime(exam.ExamYearEndDate),
+		Region:        exam.Region,
+	}
+}
+
+// parseCalendarTime 按北京时间解析日历时间
+func parseCalendarTime(field, value string) (time.Time, error) {
+	t, err := time.ParseInLocation(calendarTimeLayout, strings.TrimSpace(value), util.GetBJTLocation())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s 格式无效（应为 %s）: %q", field, calendarTimeLayout, value)
+	}
+	return t, nil
+}
+
+// formatCalendarTime 以北京时间格式化日历时间
+func formatCalendarTime(t time.Time) string {
+	return t.In(util.GetBJTLocation()).Format(calendarTimeLayout)
+}
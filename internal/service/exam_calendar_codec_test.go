@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func sampleExamCalendar() *ExamCalendar {
+	return &ExamCalendar{
+		Version: ExamCalendarVersion,
+		Exams: []ExamCalendarEntry{
+			{
+				Year:          2026,
+				Kind:          "gaokao",
+				Desc:          "2026年普通高等学校招生全国统一考试",
+				ShortDesc:     "2026年高考",
+				BeginDate:     "2026-06-07 09:00:00",
+				EndDate:       "2026-06-10 17:00:00",
+				YearBeginDate: "2025-06-10 00:00:00",
+				YearEndDate:   "2026-06-10 17:00:00",
+			},
+		},
+	}
+}
+
+func TestParseCalendarFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    CalendarFormat
+		wantErr bool
+	}{
+		{"json", CalendarFormatJSON, false},
+		{"YAML", CalendarFormatYAML, false},
+		{"yml", CalendarFormatYAML, false},
+		{" csv ", CalendarFormatCSV, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCalendarFormat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCalendarFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseCalendarFormat(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCalendarFormatFromPath(t *testing.T) {
+	if got, err := CalendarFormatFromPath("exams/calendar.yml"); err != nil || got != CalendarFormatYAML {
+		t.Errorf("CalendarFormatFromPath(.yml) = %s, %v", got, err)
+	}
+	if _, err := CalendarFormatFromPath("calendar"); err == nil {
+		t.Error("Expected error for path without extension")
+	}
+}
+
+func TestExamCalendar_RoundTrip(t *testing.T) {
+	for _, format := range []CalendarFormat{CalendarFormatJSON, CalendarFormatYAML, CalendarFormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeExamCalendar(&buf, sampleExamCalendar(), format); err != nil {
+				t.Fatalf("EncodeExamCalendar() error = %v", err)
+			}
+
+			cal, err := DecodeExamCalendar(&buf, format)
+			if err != nil {
+				t.Fatalf("DecodeExamCalendar() error = %v", err)
+			}
+
+			if len(cal.Exams) != 1 {
+				t.Fatalf("Expected 1 exam, got %d", len(cal.Exams))
+			}
+			if cal.Exams[0] != sampleExamCalendar().Exams[0] {
+				t.Errorf("Round trip mismatch: got %+v", cal.Exams[0])
+			}
+			if cal.Version != ExamCalendarVersion {
+				t.Errorf("Version = %d, want %d", cal.Version, ExamCalendarVersion)
+			}
+		})
+	}
+}
+
+func TestDecodeExamCalendar_UnknownField(t *testing.T) {
+	input := `{"version":1,"exams":[{"year":2026,"unknown":1}]}`
+	if _, err := DecodeExamCalendar(strings.NewReader(input), CalendarFormatJSON); err == nil {
+		t.Error("Expected error for unknown JSON field")
+	}
+}
+
+func TestDecodeExamCalendar_UnsupportedVersion(t *testing.T) {
+	input := "version: 99\nexams: []\n"
+	if _, err := DecodeExamCalendar(strings.NewReader(input), CalendarFormatYAML); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+}
+
+func TestDecodeExamCalendar_CSVMissingColumn(t *testing.T) {
+	input := "year,kind,desc\n2026,gaokao,高考\n"
+	_, err := DecodeExamCalendar(strings.NewReader(input), CalendarFormatCSV)
+	if err == nil || !strings.Contains(err.Error(), "short_desc") {
+		t.Errorf("Expected missing column error, got %v", err)
+	}
+}
+
+func TestDecodeExamCalendar_CSVInvalidYear(t *testing.T) {
+	input := strings.Join(calendarCSVHeader, ",") + "\nabc,gaokao,高考,高考,a,b,c,d\n"
+	if _, err := DecodeExamCalendar(strings.NewReader(input), CalendarFormatCSV); err == nil {
+		t.Error("Expected error for invalid year")
+	}
+}
+
+func TestDecodeExamCalendar_CSVWithBOM(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+	if err := EncodeExamCalendar(&buf, sampleExamCalendar(), CalendarFormatCSV); err != nil {
+		t.Fatalf("EncodeExamCalendar() error = %v", err)
+	}
+
+	cal, err := DecodeExamCalendar(&buf, CalendarFormatCSV)
+	if err != nil {
+		t.Fatalf("DecodeExamCalendar() error = %v", err)
+	}
+	if len(cal.Exams) != 1 || cal.Exams[0].Year != 2026 {
+		t.Errorf("Unexpected result: %+v", cal.Exams)
+	}
+}
+
+func TestExamCalendar_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*ExamCalendar)
+		errMsg string
+	}{
+		{"valid", func(*ExamCalendar) {}, ""},
+		{"year out of range", func(c *ExamCalendar) { c.Exams[0].Year = 2000 }, "年份"},
+		{"empty desc", func(c *ExamCalendar) { c.Exams[0].Desc = " " }, "desc"},
+		{"bad time", func(c *ExamCalendar) { c.Exams[0].BeginDate = "2026/06/07" }, "begin_date"},
+		{"end before begin", func(c *ExamCalendar) { c.Exams[0].EndDate = "2026-06-01 00:00:00" }, "end_date"},
+		{"year end before begin", func(c *ExamCalendar) { c.Exams[0].YearEndDate = "2025-01-01 00:00:00" }, "year_end_date"},
+		{"invalid region", func(c *ExamCalendar) { c.Exams[0].Region = "火星" }, "region"},
+		{"duplicate", func(c *ExamCalendar) { c.Exams = append(c.Exams, c.Exams[0]) }, "重复"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := sampleExamCalendar()
+			tt.modify(cal)
+			err := cal.Validate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestExamCalendar_Validate_DefaultKind(t *testing.T) {
+	cal := sampleExamCalendar()
+	cal.Exams[0].Kind = ""
+
+	if err := cal.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if cal.Exams[0].Kind != "gaokao" {
+		t.Errorf("Kind = %s, want gaokao", cal.Exams[0].Kind)
+	}
+}
+
+func TestExamCalendar_Validate_RegionKey(t *testing.T) {
+	cal := sampleExamCalendar()
+	regional := cal.Exams[0]
+	regional.Region = "北京"
+	cal.Exams = append(cal.Exams, regional)
+
+	// 同年同类型但地区不同不视为重复
+	if err := cal.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestDecodeExamCalendar_CSVWithoutRegion(t *testing.T) {
+	header := strings.Join(calendarCSVHeader[:len(calendarCSVHeader)-1], ",")
+	input := header + "\n2026,gaokao,高考,高考,2026-06-07 09:00:00,2026-06-10 17:00:00,2025-06-10 00:00:00,2026-06-10 17:00:00\n"
+
+	cal, err := DecodeExamCalendar(strings.NewReader(input), CalendarFormatCSV)
+	if err != nil {
+		t.Fatalf("DecodeExamCalendar() error = %v", err)
+	}
+	if len(cal.Exams) != 1 || cal.Exams[0].Region != "" {
+		t.Errorf("Unexpected result: %+v", cal.Exams)
+	}
+}
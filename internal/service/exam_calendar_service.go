@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+const (
+	// CalendarActionCreate 导入时新建考试
+	CalendarActionCreate = "create"
+
+	// CalendarActionUpdate 导入时更新已有考试
+	CalendarActionUpdate = "update"
+
+	// CalendarActionUnchanged 导入内容与数据库一致
+	CalendarActionUnchanged = "unchanged"
+)
+
+// ExamCalendarFieldChange 单个字段的变更
+type ExamCalendarFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ExamCalendarChange 单条考试的变更
+type ExamCalendarChange struct {
+	Action string                    `json:"action"`
+	Year   int                       `json:"year"`
+	Kind   string                    `json:"kind"`
+	Region string                    `json:"region,omitempty"`
+	Fields []ExamCalendarFieldChange `json:"fields,omitempty"`
+}
+
+// ExamCalendarDiff 导入文件与数据库的差异
+// Missing 为数据库中存在但导入文件中没有的考试，导入时不会删除它们
+type ExamCalendarDiff struct {
+	Changes []ExamCalendarChange `json:"changes"`
+	Missing []ExamCalendarEntry  `json:"missing,omitempty"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Applied bool                 `json:"applied"`
+}
+
+// HasChanges 是否存在需要写入的变更
+func (d *ExamCalendarDiff) HasChanges() bool {
+	return d.Created > 0 || d.Updated > 0
+}
+
+// String 以文本形式输出差异（用于命令行）
+func (d *ExamCalendarDiff) String() string {
+	var sb strings.Builder
+
+	for _, change := range d.Changes {
+		key := examCalendarKey{year: change.Year, kind: change.Kind, region: change.Region}
+		switch change.Action {
+		case CalendarActionCreate:
+			fmt.Fprintf(&sb, "+ %s\n", key)
+		case CalendarActionUpdate:
+			fmt.Fprintf(&sb, "~ %s\n", key)
+			for _, field := range change.Fields {
+				fmt.Fprintf(&sb, "    %s: %q -> %q\n", field.Field, field.Old, field.New)
+			}
+		}
+	}
+	for _, entry := range d.Missing {
+		fmt.Fprintf(&sb, "? %s（导入文件中不存在，保持不变）\n", entry.key())
+	}
+
+	fmt.Fprintf(&sb, "新增 %d 条，更新 %d 条，未变更 %d 条",
+		d.Created, d.Updated, len(d.Changes)-d.Created-d.Updated)
+	if d.Applied {
+		sb.WriteString("（已写入）")
+	} else if d.HasChanges() {
+		sb.WriteString("（未写入）")
+	}
+
+	return sb.String()
+}
+
+// ExamCalendarService 考试日历导入导出服务
+type ExamCalendarService struct {
+	repo repository.ExamDateRepository
+}
+
+// NewExamCalendarService 创建考试日历导入导出服务
+func NewExamCalendarService(repo repository.ExamDateRepository) *ExamCalendarService {
+	return &ExamCalendarService{repo: repo}
+}
+
+// Export 导出全部未删除的考试
+func (s *ExamCalendarService) Export(ctx context.Context) (*ExamCalendar, error) {
+	exams, err := s.repo.GetAll(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &ExamCalendar{
+		Version: ExamCalendarVersion,
+		Exams:   make([]ExamCalendarEntry, 0, len(exams)),
+	}
+	for i := range exams {
+		cal.Exams = append(cal.Exams, newExamCalendarEntry(&exams[i]))
+	}
+
+	return cal, nil
+}
+
+// Import 以年份+类型+地区为键导入考试日历
+// dryRun 为 true 时仅计算差异不写入；否则在单个事务中完成全部新增和更新
+func (s *ExamCalendarService) Import(ctx context.Context, cal *ExamCalendar, dryRun bool) (*ExamCalendarDiff, error) {
+	if err := cal.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[examCalendarKey]*model.ExamDate, len(existing))
+	for i := range existing {
+		exam := &existing[i]
+		if exam.ExamKind == "" {
+			exam.ExamKind = constant.ExamKindGaokao
+		}
+		byKey[examCalendarKey{year: exam.ExamYear, kind: exam.ExamKind, region: exam.Region}] = exam
+	}
+
+	diff := &ExamCalendarDiff{Changes: make([]ExamCalendarChange, 0, len(cal.Exams))}
+	var pending []model.ExamDate
+	imported := make(map[examCalendarKey]bool, len(cal.Exams))
+
+	for i := range cal.Exams {
+		entry := &cal.Exams[i]
+		key := entry.key()
+		imported[key] = true
+
+		// Validate 已保证条目合法，这里不会返回错误
+		target, _ := entry.toModel()
+		change := ExamCalendarChange{Year: entry.Year, Kind: entry.Kind, Region: entry.Region}
+
+		current, ok := byKey[key]
+		if !ok {
+			change.Action = CalendarActionCreate
+			diff.Created++
+			pending = append(pending, target)
+			diff.Changes = append(diff.Changes, change)
+			continue
+		}
+
+		change.Fields = diffExamCalendarFields(current, newExamCalendarEntry(&target))
+		if len(change.Fields) == 0 {
+			change.Action = CalendarActionUnchanged
+		} else {
+			change.Action = CalendarActionUpdate
+			diff.Updated++
+			target.ID = current.ID
+			pending = append(pending, target)
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	for i := range existing {
+		exam := &existing[i]
+		if exam.IsDelete || imported[examCalendarKey{year: exam.ExamYear, kind: exam.ExamKind, region: exam.Region}] {
+			continue
+		}
+		diff.Missing = append(diff.Missing, newExamCalendarEntry(exam))
+	}
+
+	if dryRun || len(pending) == 0 {
+		return diff, nil
+	}
+
+	if err := s.repo.SaveAll(ctx, pending); err != nil {
+		return nil, err
+	}
+	diff.Applied = true
+
+	return diff, nil
+}
+
+// diffExamCalendarFields 比较数据库记录与导入条目的字段差异
+func diffExamCalendarFields(current *model.ExamDate, target ExamCalendarEntry) []ExamCalendarFieldChange {
+	old := newExamCalendarEntry(current)
+
+	var fields []ExamCalendarFieldChange
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			fields = append(fields, ExamCalendarFieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	add("desc", old.Desc, target.Desc)
+	add("short_desc", old.ShortDesc, target.ShortDesc)
+	add("begin_date", old.BeginDate, target.BeginDate)
+	add("end_date", old.EndDate, target.EndDate)
+	add("year_begin_date", old.YearBeginDate, target.YearBeginDate)
+	add("year_end_date", old.YearEndDate, target.YearEndDate)
+	if current.IsDelete {
+		add("is_delete", "true", "false")
+	}
+
+	return fields
+}
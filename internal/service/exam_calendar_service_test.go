@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupExamCalendarTestService(t *testing.T) (*ExamCalendarService, *gorm.DB) {
+	db := setupExamDateTestDB(t)
+	repo := repository.NewExamDateRepository(db)
+	return NewExamCalendarService(repo), db
+}
+
+func createCalendarTestExam(db *gorm.DB, id uint, year int, desc string, deleted bool) {
+	loc := util.GetBJTLocation()
+	db.Create(&model.ExamDate{
+		ID:                id,
+		ExamYear:          year,
+		ExamKind:          "gaokao",
+		ExamDesc:          desc,
+		ShortDesc:         "高考",
+		ExamBeginDate:     time.Date(year, 6, 7, 9, 0, 0, 0, loc),
+		ExamEndDate:       time.Date(year, 6, 10, 17, 0, 0, 0, loc),
+		ExamYearBeginDate: time.Date(year-1, 6, 10, 0, 0, 0, 0, loc),
+		ExamYearEndDate:   time.Date(year, 6, 10, 17, 0, 0, 0, loc),
+		IsDelete:          deleted,
+	})
+}
+
+func TestExamCalendarService_Export(t *testing.T) {
+	service, db := setupExamCalendarTestService(t)
+	createCalendarTestExam(db, 1, 2026, "2026年高考", false)
+	createCalendarTestExam(db, 2, 2025, "2025年高考", true)
+
+	cal, err := service.Export(context.Background())
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if len(cal.Exams) != 1 {
+		t.Fatalf("Expected 1 exam, got %d", len(cal.Exams))
+	}
+	entry := cal.Exams[0]
+	if entry.Year != 2026 || entry.Kind != "gaokao" {
+		t.Errorf("Unexpected entry key: %d/%s", entry.Year, entry.Kind)
+	}
+	if entry.BeginDate != "2026-06-07 09:00:00" {
+		t.Errorf("BeginDate = %s, want 2026-06-07 09:00:00", entry.BeginDate)
+	}
+}
+
+func TestExamCalendarService_Import_DryRun(t *testing.T) {
+	service, db := setupExamCalendarTestService(t)
+	createCalendarTestExam(db, 1, 2026, "旧描述", false)
+	createCalendarTestExam(db, 2, 2024, "2024年高考", false)
+
+	cal := sampleExamCalendar()
+	next := cal.Exams[0]
+	next.Year = 2027
+	next.BeginDate = "2027-06-07 09:00:00"
+	next.EndDate = "2027-06-10 17:00:00"
+	next.YearBeginDate = "2026-06-10 00:00:00"
+	next.YearEndDate = "2027-06-10 17:00:00"
+	cal.Exams = append(cal.Exams, next)
+
+	diff, err := service.Import(context.Background(), cal, true)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if diff.Created != 1 || diff.Updated != 1 {
+		t.Errorf("Created = %d, Updated = %d, want 1, 1", diff.Created, diff.Updated)
+	}
+	if diff.Applied {
+		t.Error("Dry run should not apply changes")
+	}
+	if len(diff.Missing) != 1 || diff.Missing[0].Year != 2024 {
+		t.Errorf("Expected 2024 to be reported as missing, got %+v", diff.Missing)
+	}
+
+	var count int64
+	db.Model(&model.ExamDate{}).Count(&count)
+	if count != 2 {
+		t.Errorf("Dry run should not create rows, got %d", count)
+	}
+
+	var exam model.ExamDate
+	db.First(&exam, 1)
+	if exam.ExamDesc != "旧描述" {
+		t.Errorf("Dry run should not update rows, got %s", exam.ExamDesc)
+	}
+}
+
+func TestExamCalendarService_Import_Apply(t *testing.T) {
+	service, db := setupExamCalendarTestService(t)
+	createCalendarTestExam(db, 1, 2026, "旧描述", true)
+
+	diff, err := service.Import(context.Background(), sampleExamCalendar(), false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if !diff.Applied || diff.Updated != 1 {
+		t.Errorf("Expected 1 applied update, got %+v", diff)
+	}
+
+	var fields []string
+	for _, field := range diff.Changes[0].Fields {
+		fields = append(fields, field.Field)
+	}
+	if !strings.Contains(strings.Join(fields, ","), "is_delete") {
+		t.Errorf("Expected is_delete change for restored exam, got %v", fields)
+	}
+
+	var exam model.ExamDate
+	db.First(&exam, 1)
+	if exam.ExamDesc != sampleExamCalendar().Exams[0].Desc {
+		t.Errorf("ExamDesc = %s, want imported desc", exam.ExamDesc)
+	}
+	if exam.IsDelete {
+		t.Error("Expected imported exam to be restored")
+	}
+}
+
+func TestExamCalendarService_Import_Unchanged(t *testing.T) {
+	service, _ := setupExamCalendarTestService(t)
+
+	if _, err := service.Import(context.Background(), sampleExamCalendar(), false); err != nil {
+		t.Fatalf("First Import() error = %v", err)
+	}
+
+	diff, err := service.Import(context.Background(), sampleExamCalendar(), false)
+	if err != nil {
+		t.Fatalf("Second Import() error = %v", err)
+	}
+	if diff.HasChanges() || diff.Applied {
+		t.Errorf("Expected no changes on re-import, got %+v", diff)
+	}
+	if diff.Changes[0].Action != CalendarActionUnchanged {
+		t.Errorf("Action = %s, want %s", diff.Changes[0].Action, CalendarActionUnchanged)
+	}
+}
+
+func TestExamCalendarService_Import_Invalid(t *testing.T) {
+	service, _ := setupExamCalendarTestService(t)
+
+	cal := sampleExamCalendar()
+	cal.Exams[0].BeginDate = "invalid"
+
+	if _, err := service.Import(context.Background(), cal, true); err == nil {
+		t.Error("Expected validation error")
+	}
+}
+
+func TestExamCalendarDiff_String(t *testing.T) {
+	diff := &ExamCalendarDiff{
+		Changes: []ExamCalendarChange{
+			{Action: CalendarActionCreate, Year: 2027, Kind: "gaokao"},
+			{Action: CalendarActionUpdate, Year: 2026, Kind: "gaokao", Fields: []ExamCalendarFieldChange{
+				{Field: "desc", Old: "旧", New: "新"},
+			}},
+			{Action: CalendarActionUnchanged, Year: 2025, Kind: "gaokao"},
+		},
+		Missing: []ExamCalendarEntry{{Year: 2024, Kind: "gaokao"}},
+		Created: 1,
+		Updated: 1,
+	}
+
+	out := diff.String()
+	for _, want := range []string{"+ 2027/gaokao", "~ 2026/gaokao", `desc: "旧" -> "新"`, "? 2024/gaokao", "新增 1 条，更新 1 条，未变更 1 条", "未写入"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("String() missing %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -1,45 +1,195 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
 	"github.com/herbertgao/gaokao_bot/internal/repository"
 	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+var (
+	// ErrCountdownArgUnrecognized 倒计时参数无法识别（或找不到对应考试）
+	ErrCountdownArgUnrecognized = errors.New("countdown argument unrecognized")
+
+	// ErrCountdownDatePassed 倒计时目标日期已过
+	ErrCountdownDatePassed = errors.New("countdown target date passed")
 )
 
 // ExamDateService 考试日期服务
 type ExamDateService struct {
-	repo *repository.ExamDateRepository
+	repo repository.ExamDateRepository
 }
 
 // NewExamDateService 创建考试日期服务
-func NewExamDateService(repo *repository.ExamDateRepository) *ExamDateService {
+func NewExamDateService(repo repository.ExamDateRepository) *ExamDateService {
 	return &ExamDateService{repo: repo}
 }
 
-// GetExamsInRange 获取时间范围内的考试
-func (s *ExamDateService) GetExamsInRange(now time.Time) ([]model.ExamDate, error) {
-	return s.repo.GetExamsInRange(now)
+// ExamFilter 考试过滤条件，零值字段不参与过滤
+type ExamFilter struct {
+	// Year 考试年份
+	Year int
+	// Kind 考试类型，如 gaokao
+	Kind string
+	// Region 考试地区；nil 表示不限地区，指向空字符串表示仅全国统一考试
+	Region *string
+}
+
+// nationwideRegion 全国统一考试的地区（空字符串）
+var nationwideRegion = ""
+
+// NationwideExams 仅包含全国统一考试的过滤条件
+// 推送、/d 和 Inline Query 不区分用户所在地区，默认不展示地方考试
+func NationwideExams() ExamFilter {
+	region := nationwideRegion
+	return ExamFilter{Region: &region}
+}
+
+// query 转换为仓储查询条件
+func (f ExamFilter) query() repository.ExamQuery {
+	return repository.ExamQuery{
+		Year:   f.Year,
+		Kind:   f.Kind,
+		Region: f.Region,
+	}
+}
+
+// Find 按条件获取考试，按开始时间排序
+func (s *ExamDateService) Find(ctx context.Context, filter ExamFilter) ([]model.ExamDate, error) {
+	return s.repo.Find(ctx, filter.query())
+}
+
+// GetExamsInRange 获取考试年范围包含 now 的全国统一考试，按开始时间排序
+func (s *ExamDateService) GetExamsInRange(ctx context.Context, now time.Time) ([]model.ExamDate, error) {
+	return s.GetActive(ctx, NationwideExams(), now)
+}
+
+// GetExamByYear 按年份获取考试，按开始时间排序
+func (s *ExamDateService) GetExamByYear(ctx context.Context, year int) ([]model.ExamDate, error) {
+	return s.repo.GetExamByYear(ctx, year)
+}
+
+// GetNextUpcoming 获取下一场尚未开始的考试，没有时返回 nil
+func (s *ExamDateService) GetNextUpcoming(ctx context.Context, filter ExamFilter, now time.Time) (*model.ExamDate, error) {
+	q := filter.query()
+	q.BeginAfter = now
+	q.Limit = 1
+	return s.findOne(ctx, q)
+}
+
+// GetActive 获取考试年范围包含 now 的考试（倒计时推送的对象），按开始时间排序
+func (s *ExamDateService) GetActive(ctx context.Context, filter ExamFilter, now time.Time) ([]model.ExamDate, error) {
+	q := filter.query()
+	q.ActiveAt = now
+	return s.repo.Find(ctx, q)
 }
 
-// GetExamByYear 按年份获取考试
-func (s *ExamDateService) GetExamByYear(year int) ([]model.ExamDate, error) {
-	return s.repo.GetExamByYear(year)
+// GetRunning 获取正在进行中的考试，按开始时间排序
+func (s *ExamDateService) GetRunning(ctx context.Context, filter ExamFilter, now time.Time) ([]model.ExamDate, error) {
+	q := filter.query()
+	q.BeginNotAfter = now
+	q.EndAfter = now
+	return s.repo.Find(ctx, q)
+}
+
+// GetLastEnded 获取最近一场已结束的考试，没有时返回 nil
+func (s *ExamDateService) GetLastEnded(ctx context.Context, filter ExamFilter, now time.Time) (*model.ExamDate, error) {
+	q := filter.query()
+	q.EndNotAfter = now
+	q.OrderByEndDesc = true
+	q.Limit = 1
+	return s.findOne(ctx, q)
 }
 
 // GetNextExamDate 获取下一个高考日期
-func (s *ExamDateService) GetNextExamDate() (*model.ExamDate, error) {
-	now := util.NowBJT()
-	exams, err := s.repo.GetExamsInRange(now)
+func (s *ExamDateService) GetNextExamDate(ctx context.Context) (*model.ExamDate, error) {
+	return s.GetNextUpcoming(ctx, ExamFilter{Kind: constant.ExamKindGaokao}, util.NowBJT())
+}
+
+// findOne 返回查询结果中的第一场考试，没有时返回 nil
+func (s *ExamDateService) findOne(ctx context.Context, q repository.ExamQuery) (*model.ExamDate, error) {
+	exams, err := s.repo.Find(ctx, q)
 	if err != nil {
 		return nil, err
 	}
-
 	if len(exams) == 0 {
 		return nil, nil
 	}
-
-	// 返回第一个（最近的）考试
 	return &exams[0], nil
 }
+
+// FindUpcomingByName 按考试简称查找最近一场未结束的考试
+// 优先完全匹配简称或考试类型，其次匹配包含该名称的简称；未找到时返回 nil
+func (s *ExamDateService) FindUpcomingByName(ctx context.Context, name string, now time.Time) (*model.ExamDate, error) {
+	exams, err := s.repo.GetEndingAfter(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range exams {
+		if exams[i].ShortDesc == name || exams[i].ExamKind == name {
+			return &exams[i], nil
+		}
+	}
+	for i := range exams {
+		if strings.Contains(exams[i].ShortDesc, name) {
+			return &exams[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ResolveCountdownArg 将 /d、Guest、Inline Query 共用的参数解析为倒计时目标，结果按开始时间排序
+// 无参数时返回当前时间范围内的考试，没有时回退到最近结束的考试；无参数和按年份查询时仅包含全国统一考试，
+// 地方考试需按简称查询
+// 参数无法识别或找不到考试时返回 ErrCountdownArgUnrecognized，日期已过时返回 ErrCountdownDatePassed
+func (s *ExamDateService) ResolveCountdownArg(ctx context.Context, text string, now time.Time) ([]model.ExamDate, error) {
+	arg := util.ParseCountdownArg(text, now)
+
+	switch arg.Kind {
+	case util.CountdownArgNone:
+		exams, err := s.GetActive(ctx, NationwideExams(), now)
+		if err != nil || len(exams) > 0 {
+			return exams, err
+		}
+		// 两个考试年之间的空档期，回退到最近结束的考试（展示其考后时间线）
+		exam, err := s.GetLastEnded(ctx, NationwideExams(), now)
+		if err != nil || exam == nil {
+			return nil, err
+		}
+		return []model.ExamDate{*exam}, nil
+	case util.CountdownArgYear:
+		filter := NationwideExams()
+		filter.Year = arg.Year
+		exams, err := s.Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(exams) == 0 {
+			return nil, ErrCountdownArgUnrecognized
+		}
+		return exams, nil
+	case util.CountdownArgDate:
+		if !arg.Date.After(now) {
+			return nil, ErrCountdownDatePassed
+		}
+		return []model.ExamDate{util.NewDateTargetExam(arg.Date)}, nil
+	case util.CountdownArgName:
+		exam, err := s.FindUpcomingByName(ctx, arg.Name, now)
+		if err != nil {
+			return nil, err
+		}
+		if exam == nil {
+			return nil, ErrCountdownArgUnrecognized
+		}
+		return []model.ExamDate{*exam}, nil
+	default:
+		return nil, ErrCountdownArgUnrecognized
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -50,7 +52,7 @@ func TestExamDateService_GetExamsInRange(t *testing.T) {
 		IsDelete:          false,
 	})
 
-	result, err := service.GetExamsInRange(now)
+	result, err := service.GetExamsInRange(context.Background(), now)
 	if err != nil {
 		t.Errorf("GetExamsInRange() error = %v", err)
 	}
@@ -78,7 +80,7 @@ func TestExamDateService_GetExamByYear(t *testing.T) {
 		IsDelete:          false,
 	})
 
-	result, err := service.GetExamByYear(year)
+	result, err := service.GetExamByYear(context.Background(), year)
 	if err != nil {
 		t.Errorf("GetExamByYear() error = %v", err)
 	}
@@ -110,7 +112,7 @@ func TestExamDateService_GetNextExamDate(t *testing.T) {
 		IsDelete:          false,
 	})
 
-	result, err := service.GetNextExamDate()
+	result, err := service.GetNextExamDate(context.Background())
 	if err != nil {
 		t.Errorf("GetNextExamDate() error = %v", err)
 	}
@@ -128,7 +130,7 @@ func TestExamDateService_GetNextExamDate(t *testing.T) {
 func TestExamDateService_GetNextExamDate_NoExams(t *testing.T) {
 	service, _ := setupExamDateTestService(t)
 
-	result, err := service.GetNextExamDate()
+	result, err := service.GetNextExamDate(context.Background())
 	if err != nil {
 		t.Errorf("GetNextExamDate() error = %v", err)
 	}
@@ -137,3 +139,161 @@ func TestExamDateService_GetNextExamDate_NoExams(t *testing.T) {
 		t.Error("Expected nil when no exams, got exam")
 	}
 }
+
+func TestExamDateService_FindUpcomingByName(t *testing.T) {
+	service, db := setupExamDateTestService(t)
+	now := util.NowBJT()
+
+	createUpcomingExam(db, 1, "gaokao", "2027年高考", now.AddDate(1, 0, 0))
+	createUpcomingExam(db, 2, "gaokao", "2026年高考", now.AddDate(0, 3, 0))
+	createUpcomingExam(db, 3, "zhongkao", "中考", now.AddDate(0, 4, 0))
+
+	tests := []struct {
+		name   string
+		wantID uint
+	}{
+		{"中考", 3},
+		{"zhongkao", 3},
+		{"高考", 2},
+		{"2027年高考", 1},
+		{"研究生考试", 0},
+	}
+
+	for _, tt := range tests {
+		exam, err := service.FindUpcomingByName(context.Background(), tt.name, now)
+		if err != nil {
+			t.Fatalf("FindUpcomingByName(%q) error = %v", tt.name, err)
+		}
+		var gotID uint
+		if exam != nil {
+			gotID = exam.ID
+		}
+		if gotID != tt.wantID {
+			t.Errorf("FindUpcomingByName(%q) = %d, want %d", tt.name, gotID, tt.wantID)
+		}
+	}
+}
+
+func TestExamDateService_ResolveCountdownArg(t *testing.T) {
+	service, db := setupExamDateTestService(t)
+	now := util.NowBJT()
+
+	createUpcomingExam(db, 1, "gaokao", "高考", now.AddDate(0, 3, 0))
+
+	exams, err := service.ResolveCountdownArg(context.Background(), "高考", now)
+	if err != nil || len(exams) != 1 || exams[0].ID != 1 {
+		t.Errorf("ResolveCountdownArg(高考) = %v, %v", exams, err)
+	}
+
+	exams, err = service.ResolveCountdownArg(context.Background(), "10天后", now)
+	if err != nil || len(exams) != 1 {
+		t.Fatalf("ResolveCountdownArg(10天后) = %v, %v", exams, err)
+	}
+	if want := util.StartOfDay(now).AddDate(0, 0, 10); !exams[0].ExamBeginDate.Equal(want) {
+		t.Errorf("ExamBeginDate = %v, want %v", exams[0].ExamBeginDate, want)
+	}
+
+	if _, err := service.ResolveCountdownArg(context.Background(), "2020-01-01", now); !errors.Is(err, ErrCountdownDatePassed) {
+		t.Errorf("ResolveCountdownArg(past date) error = %v, want ErrCountdownDatePassed", err)
+	}
+
+	for _, arg := range []string{"2019", "不存在的考试", "2026-13-01"} {
+		if _, err := service.ResolveCountdownArg(context.Background(), arg, now); !errors.Is(err, ErrCountdownArgUnrecognized) {
+			t.Errorf("ResolveCountdownArg(%q) error = %v, want ErrCountdownArgUnrecognized", arg, err)
+		}
+	}
+}
+
+// createUpcomingExam 创建测试考试
+// 考试年份按 ID 区分：各用例的开始时间相对当前时间计算，可能落在同一年，违反（年份、类型、地区）唯一索引
+func createUpcomingExam(db *gorm.DB, id uint, kind, shortDesc string, begin time.Time) {
+	db.Create(&model.ExamDate{
+		ID:                id,
+		ExamYear:          3000 + int(id),
+		ExamKind:          kind,
+		ExamDesc:          shortDesc,
+		ShortDesc:         shortDesc,
+		ExamBeginDate:     begin,
+		ExamEndDate:       begin.AddDate(0, 0, 3),
+		ExamYearBeginDate: begin.AddDate(-1, 0, 0),
+		ExamYearEndDate:   begin.AddDate(0, 0, 3),
+	})
+}
+
+func TestExamDateService_QueryByTime(t *testing.T) {
+	service, db := setupExamDateTestService(t)
+	now := util.NowBJT()
+
+	// 故意乱序插入：已结束、进行中（开始于 1 天前）、两场未开始
+	createUpcomingExam(db, 1, "gaokao", "远期高考", now.AddDate(1, 0, 0))
+	createUpcomingExam(db, 2, "zhongkao", "已结束中考", now.AddDate(0, -1, 0))
+	createUpcomingExam(db, 3, "gaokao", "近期高考", now.AddDate(0, 2, 0))
+	createUpcomingExam(db, 4, "gaokao", "进行中高考", now.AddDate(0, 0, -1))
+
+	next, err := service.GetNextUpcoming(context.Background(), ExamFilter{}, now)
+	if err != nil || next == nil || next.ID != 3 {
+		t.Errorf("GetNextUpcoming() = %v, %v, want exam 3", next, err)
+	}
+
+	next, err = service.GetNextUpcoming(context.Background(), ExamFilter{Kind: "zhongkao"}, now)
+	if err != nil || next != nil {
+		t.Errorf("GetNextUpcoming(zhongkao) = %v, %v, want nil", next, err)
+	}
+
+	running, err := service.GetRunning(context.Background(), ExamFilter{}, now)
+	if err != nil || len(running) != 1 || running[0].ID != 4 {
+		t.Errorf("GetRunning() = %v, %v, want [exam 4]", running, err)
+	}
+
+	last, err := service.GetLastEnded(context.Background(), ExamFilter{}, now)
+	if err != nil || last == nil || last.ID != 2 {
+		t.Errorf("GetLastEnded() = %v, %v, want exam 2", last, err)
+	}
+
+	exams, err := service.Find(context.Background(), ExamFilter{Kind: "gaokao"})
+	if err != nil || len(exams) != 3 || exams[0].ID != 4 || exams[1].ID != 3 || exams[2].ID != 1 {
+		t.Errorf("Find(gaokao) = %v, %v, want exams ordered [4 3 1]", exams, err)
+	}
+}
+
+func TestExamDateService_ResolveCountdownArg_FallbackToLastEnded(t *testing.T) {
+	service, db := setupExamDateTestService(t)
+	now := util.NowBJT()
+
+	// 考试年范围均已结束
+	createUpcomingExam(db, 1, "gaokao", "去年高考", now.AddDate(-1, 0, 0))
+	createUpcomingExam(db, 2, "gaokao", "上月高考", now.AddDate(0, -1, 0))
+
+	exams, err := service.ResolveCountdownArg(context.Background(), "", now)
+	if err != nil || len(exams) != 1 || exams[0].ID != 2 {
+		t.Errorf("ResolveCountdownArg(\"\") = %v, %v, want [exam 2]", exams, err)
+	}
+}
+
+func TestExamDateService_NationwideExamsOnly(t *testing.T) {
+	service, db := setupExamDateTestService(t)
+	now := util.NowBJT()
+
+	createUpcomingExam(db, 1, "gaokao", "高考", now.AddDate(0, 2, 0))
+	createUpcomingExam(db, 2, "xuekao", "北京学考", now.AddDate(0, 1, 0))
+	db.Model(&model.ExamDate{}).Where("id = ?", 2).Update("region", "北京")
+
+	// 推送使用的考试列表不包含地方考试
+	exams, err := service.GetActive(context.Background(), NationwideExams(), now)
+	if err != nil || len(exams) != 1 || exams[0].ID != 1 {
+		t.Errorf("GetActive(nationwide) = %v, %v, want [exam 1]", exams, err)
+	}
+	if exams, _ := service.GetActive(context.Background(), ExamFilter{}, now); len(exams) != 2 {
+		t.Errorf("GetActive() = %v, want both exams", exams)
+	}
+
+	// /d 与 Inline Query 无参数时同样不包含地方考试，按简称仍可查询
+	exams, err = service.ResolveCountdownArg(context.Background(), "", now)
+	if err != nil || len(exams) != 1 || exams[0].ID != 1 {
+		t.Errorf("ResolveCountdownArg(\"\") = %v, %v, want [exam 1]", exams, err)
+	}
+	exams, err = service.ResolveCountdownArg(context.Background(), "北京学考", now)
+	if err != nil || len(exams) != 1 || exams[0].ID != 2 {
+		t.Errorf("ResolveCountdownArg(北京学考) = %v, %v, want [exam 2]", exams, err)
+	}
+}
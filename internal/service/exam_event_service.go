@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// ExamEventUpcomingDays 无参数查询时展示的考后事件范围（天）
+const ExamEventUpcomingDays = 90
+
+// ExamTimeline 单场考试的考后时间线
+type ExamTimeline struct {
+	Title  string
+	Events []model.ExamEvent
+}
+
+// examEventKey 事件所属考试的键（年份 + 类型 + 地区）
+type examEventKey struct {
+	year   int
+	kind   string
+	region string
+}
+
+// ExamEventService 考后时间线事件服务
+type ExamEventService struct {
+	repo     repository.ExamEventRepository
+	examRepo repository.ExamDateRepository
+}
+
+// NewExamEventService 创建考后时间线事件服务
+// examRepo 用于查找事件所属考试的名称
+func NewExamEventService(repo repository.ExamEventRepository, examRepo repository.ExamDateRepository) *ExamEventService {
+	return &ExamEventService{repo: repo, examRepo: examRepo}
+}
+
+// GetTimelines 获取考试列表中已结束考试的时间线
+// includeUpcoming 为 true 时追加近期（ExamEventUpcomingDays 天内）有事件的其他考试
+func (s *ExamEventService) GetTimelines(ctx context.Context, exams []model.ExamDate, includeUpcoming bool, now time.Time) ([]ExamTimeline, error) {
+	var timelines []ExamTimeline
+	seen := make(map[examEventKey]bool)
+
+	for i := range exams {
+		exam := &exams[i]
+		if !util.IsExpiredExam(exam, now) {
+			continue
+		}
+
+		key := newExamEventKey(exam.ExamYear, exam.ExamKind, exam.Region)
+		seen[key] = true
+
+		events, err := s.GetByExam(ctx, exam)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			timelines = append(timelines, ExamTimeline{Title: exam.ShortDesc, Events: events})
+		}
+	}
+
+	if !includeUpcoming {
+		return timelines, nil
+	}
+
+	upcoming, err := s.GetUpcoming(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped, err := s.groupTimelines(ctx, upcoming, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(timelines, grouped...), nil
+}
+
+// GetTimelinesOnDay 获取 now 当天的事件，按所属考试分组（用于定时提醒）
+func (s *ExamEventService) GetTimelinesOnDay(ctx context.Context, now time.Time) ([]ExamTimeline, error) {
+	events, err := s.GetOnDay(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.groupTimelines(ctx, events, nil)
+}
+
+// groupTimelines 按所属考试分组事件，保持事件时间顺序，跳过 skip 中的考试
+func (s *ExamEventService) groupTimelines(ctx context.Context, events []model.ExamEvent, skip map[examEventKey]bool) ([]ExamTimeline, error) {
+	var keys []examEventKey
+	groups := make(map[examEventKey][]model.ExamEvent)
+	for _, event := range events {
+		key := newExamEventKey(event.ExamYear, event.ExamKind, event.Region)
+		if skip[key] {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], event)
+	}
+
+	timelines := make([]ExamTimeline, 0, len(keys))
+	for _, key := range keys {
+		title, err := s.examTitle(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		timelines = append(timelines, ExamTimeline{Title: title, Events: groups[key]})
+	}
+
+	return timelines, nil
+}
+
+// examTitle 查找事件所属考试的简称，找不到时使用「年份 + 地区 + 类型」
+func (s *ExamEventService) examTitle(ctx context.Context, key examEventKey) (string, error) {
+	exams, err := s.examRepo.GetExamByYear(ctx, key.year)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range exams {
+		if newExamEventKey(exams[i].ExamYear, exams[i].ExamKind, exams[i].Region) == key {
+			return exams[i].ShortDesc, nil
+		}
+	}
+
+	kindName := key.kind
+	if kindName == constant.ExamKindGaokao {
+		kindName = "高考"
+	}
+	return fmt.Sprintf("%d年%s%s", key.year, key.region, kindName), nil
+}
+
+// newExamEventKey 构造事件所属考试的键，类型为空时视为高考
+func newExamEventKey(year int, kind, region string) examEventKey {
+	if kind == "" {
+		kind = constant.ExamKindGaokao
+	}
+	return examEventKey{year: year, kind: kind, region: region}
+}
+
+// GetByExam 获取考试对应的全部事件
+func (s *ExamEventService) GetByExam(ctx context.Context, exam *model.ExamDate) ([]model.ExamEvent, error) {
+	kind := exam.ExamKind
+	if kind == "" {
+		kind = constant.ExamKindGaokao
+	}
+	return s.repo.GetByExam(ctx, exam.ExamYear, kind, exam.Region)
+}
+
+// GetByYear 获取指定年份的全部事件
+func (s *ExamEventService) GetByYear(ctx context.Context, year int) ([]model.ExamEvent, error) {
+	return s.repo.GetByYear(ctx, year)
+}
+
+// GetUpcoming 获取从今天起 ExamEventUpcomingDays 天内的事件
+func (s *ExamEventService) GetUpcoming(ctx context.Context, now time.Time) ([]model.ExamEvent, error) {
+	from := util.StartOfDay(now)
+	return s.repo.GetBetween(ctx, from, from.AddDate(0, 0, ExamEventUpcomingDays))
+}
+
+// GetOnDay 获取 now 当天的事件
+func (s *ExamEventService) GetOnDay(ctx context.Context, now time.Time) ([]model.ExamEvent, error) {
+	from := util.StartOfDay(now)
+	return s.repo.GetBetween(ctx, from, from.AddDate(0, 0, 1))
+}
+
+// GetByID 根据ID获取事件
+func (s *ExamEventService) GetByID(ctx context.Context, id uint) (*model.ExamEvent, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Create 创建事件
+func (s *ExamEventService) Create(ctx context.Context, event *model.ExamEvent) error {
+	return s.repo.Create(ctx, event)
+}
+
+// Delete 删除事件
+func (s *ExamEventService) Delete(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupExamEventTestService(t *testing.T) (*ExamEventService, *gorm.DB) {
+	db := setupExamDateTestDB(t)
+	if err := db.AutoMigrate(&model.ExamEvent{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	service := NewExamEventService(
+		repository.NewExamEventRepository(db),
+		repository.NewExamDateRepository(db),
+	)
+	return service, db
+}
+
+// seedTimelineData 插入 2026 年已结束的高考及其考后事件，2027 年高考尚未开始
+func seedTimelineData(db *gorm.DB) {
+	loc := util.GetBJTLocation()
+	db.Create(&model.ExamDate{
+		ID: 1, ExamYear: 2026, ExamKind: "gaokao", ExamDesc: "2026年高考", ShortDesc: "2026年高考",
+		ExamBeginDate:     time.Date(2026, 6, 7, 9, 0, 0, 0, loc),
+		ExamEndDate:       time.Date(2026, 6, 10, 17, 0, 0, 0, loc),
+		ExamYearBeginDate: time.Date(2025, 6, 10, 17, 0, 0, 0, loc),
+		ExamYearEndDate:   time.Date(2026, 6, 10, 17, 0, 0, 0, loc),
+	})
+	db.Create(&model.ExamDate{
+		ID: 2, ExamYear: 2027, ExamKind: "gaokao", ExamDesc: "2027年高考", ShortDesc: "2027年高考",
+		ExamBeginDate:     time.Date(2027, 6, 7, 9, 0, 0, 0, loc),
+		ExamEndDate:       time.Date(2027, 6, 10, 17, 0, 0, 0, loc),
+		ExamYearBeginDate: time.Date(2026, 6, 10, 17, 0, 0, 0, loc),
+		ExamYearEndDate:   time.Date(2027, 6, 10, 17, 0, 0, 0, loc),
+	})
+	db.Create(&model.ExamEvent{ID: 1, ExamYear: 2026, ExamKind: "gaokao", EventType: "score_release", EventName: "成绩公布", EventDate: time.Date(2026, 6, 25, 12, 0, 0, 0, loc)})
+	db.Create(&model.ExamEvent{ID: 2, ExamYear: 2026, ExamKind: "gaokao", EventType: "application_end", EventName: "志愿填报截止", EventDate: time.Date(2026, 7, 5, 17, 0, 0, 0, loc)})
+	db.Create(&model.ExamEvent{ID: 3, ExamYear: 2026, ExamKind: "gaokao", Region: "北京", EventType: "score_release", EventName: "成绩公布", EventDate: time.Date(2026, 6, 25, 10, 0, 0, 0, loc)})
+}
+
+func TestExamEventService_GetTimelines_ExpiredExam(t *testing.T) {
+	service, db := setupExamEventTestService(t)
+	seedTimelineData(db)
+
+	var exams []model.ExamDate
+	db.Order("id").Find(&exams)
+
+	now := time.Date(2026, 6, 20, 10, 0, 0, 0, util.GetBJTLocation())
+	timelines, err := service.GetTimelines(context.Background(), exams, false, now)
+	if err != nil {
+		t.Fatalf("GetTimelines() error = %v", err)
+	}
+
+	// 仅已结束的 2026 年全国高考，不含未开始的 2027 年高考和北京地区事件
+	if len(timelines) != 1 {
+		t.Fatalf("Expected 1 timeline, got %d", len(timelines))
+	}
+	if timelines[0].Title != "2026年高考" || len(timelines[0].Events) != 2 {
+		t.Errorf("Unexpected timeline: %+v", timelines[0])
+	}
+}
+
+func TestExamEventService_GetTimelines_Upcoming(t *testing.T) {
+	service, db := setupExamEventTestService(t)
+	seedTimelineData(db)
+
+	now := time.Date(2026, 6, 20, 10, 0, 0, 0, util.GetBJTLocation())
+
+	// 当前时间范围内只有 2027 年高考，2026 年的事件通过 includeUpcoming 展示
+	var current []model.ExamDate
+	db.Where("id = ?", 2).Find(&current)
+
+	timelines, err := service.GetTimelines(context.Background(), current, true, now)
+	if err != nil {
+		t.Fatalf("GetTimelines() error = %v", err)
+	}
+	if len(timelines) != 2 {
+		t.Fatalf("Expected 2 timelines, got %d", len(timelines))
+	}
+
+	// 北京事件时间更早，排在前面；没有对应考试记录时使用默认标题
+	if timelines[0].Title != "2026年北京高考" {
+		t.Errorf("timelines[0].Title = %s, want 2026年北京高考", timelines[0].Title)
+	}
+	if timelines[1].Title != "2026年高考" || len(timelines[1].Events) != 2 {
+		t.Errorf("Unexpected timeline: %+v", timelines[1])
+	}
+}
+
+func TestExamEventService_GetTimelinesOnDay(t *testing.T) {
+	service, db := setupExamEventTestService(t)
+	seedTimelineData(db)
+
+	now := time.Date(2026, 6, 25, 9, 0, 0, 0, util.GetBJTLocation())
+	timelines, err := service.GetTimelinesOnDay(context.Background(), now)
+	if err != nil {
+		t.Fatalf("GetTimelinesOnDay() error = %v", err)
+	}
+	if len(timelines) != 2 {
+		t.Fatalf("Expected 2 timelines, got %d", len(timelines))
+	}
+	for _, timeline := range timelines {
+		if len(timeline.Events) != 1 || timeline.Events[0].EventType != "score_release" {
+			t.Errorf("Unexpected timeline: %+v", timeline)
+		}
+	}
+}
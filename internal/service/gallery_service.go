@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+const (
+	// DefaultGalleryPageSize 模板广场默认每页条数
+	DefaultGalleryPageSize = 20
+	// MaxGalleryPageSize 模板广场每页最大条数
+	MaxGalleryPageSize = 50
+)
+
+// ErrGalleryNotFound 广场条目不存在或未公开
+var ErrGalleryNotFound = errors.New("gallery template not found")
+
+// GalleryItem 模板广场条目，附带当前用户是否已点赞
+type GalleryItem struct {
+	model.GalleryTemplate
+	Liked bool `json:"liked"`
+}
+
+// GalleryPage 模板广场分页结果
+type GalleryPage struct {
+	Items    []GalleryItem `json:"items"`
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+}
+
+// GalleryService 模板广场服务
+// 用户发布的模板需管理员审核通过后才会公开展示；点赞、复制仅对已公开的条目生效
+type GalleryService struct {
+	repo            repository.GalleryRepository
+	templateService *UserTemplateService
+}
+
+// NewGalleryService 创建模板广场服务
+func NewGalleryService(repo repository.GalleryRepository, templateService *UserTemplateService) *GalleryService {
+	return &GalleryService{
+		repo:            repo,
+		templateService: templateService,
+	}
+}
+
+// Publish 将模板发布到广场，提交后进入待审核状态
+// 模板已发布时更新快照；名称和内容均未变化时保持原状态，避免重复审核
+func (s *GalleryService) Publish(ctx context.Context, template *model.UserTemplate) (*model.GalleryTemplate, error) {
+	if err := validateTemplateInput(template.TemplateName, template.TemplateContent); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByTemplateID(ctx, template.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if existing.TemplateName == template.TemplateName && existing.TemplateContent == template.TemplateContent {
+			return existing, nil
+		}
+		existing.TemplateName = template.TemplateName
+		existing.TemplateContent = template.TemplateContent
+		existing.Status = model.GalleryStatusPending
+		if err := s.repo.UpdateContent(ctx, existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	id, err := util.GenerateID()
+	if err != nil {
+		return nil, err
+	}
+
+	item := &model.GalleryTemplate{
+		ID:              id,
+		TemplateID:      template.ID,
+		UserID:          template.UserID,
+		TemplateName:    template.TemplateName,
+		TemplateContent: template.TemplateContent,
+		Status:          model.GalleryStatusPending,
+	}
+	if err := s.repo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Unpublish 从广场撤下条目
+func (s *GalleryService) Unpublish(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// GetByID 根据ID获取条目（包含未公开的条目）
+func (s *GalleryService) GetByID(ctx context.Context, id int64) (*model.GalleryTemplate, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Browse 分页浏览已公开的条目，userID 不为 0 时标记其已点赞的条目
+func (s *GalleryService) Browse(ctx context.Context, keyword, sort string, page, pageSize int, userID int64) (*GalleryPage, error) {
+	return s.list(ctx, repository.GalleryQuery{
+		Status:  model.GalleryStatusApproved,
+		Keyword: keyword,
+		Sort:    sort,
+	}, page, pageSize, userID)
+}
+
+// ListByStatus 按审核状态分页查询条目（管理员审核队列），最新提交的在前
+func (s *GalleryService) ListByStatus(ctx context.Context, status string, page, pageSize int) (*GalleryPage, error) {
+	return s.list(ctx, repository.GalleryQuery{
+		Status: status,
+		Sort:   repository.GallerySortLatest,
+	}, page, pageSize, 0)
+}
+
+// ListByUser 获取用户发布的全部条目（包含待审核和已隐藏的条目）
+func (s *GalleryService) ListByUser(ctx context.Context, userID int64) ([]model.GalleryTemplate, error) {
+	return s.repo.Find(ctx, repository.GalleryQuery{
+		UserID: userID,
+		Sort:   repository.GallerySortLatest,
+	})
+}
+
+// Search 按关键词搜索已公开的条目，按热度排序（用于内联查询）
+func (s *GalleryService) Search(ctx context.Context, keyword string, limit int) ([]model.GalleryTemplate, error) {
+	return s.repo.Find(ctx, repository.GalleryQuery{
+		Status:  model.GalleryStatusApproved,
+		Keyword: keyword,
+		Sort:    repository.GallerySortPopular,
+		Limit:   limit,
+	})
+}
+
+// Like 点赞已公开的条目，重复点赞不计数
+func (s *GalleryService) Like(ctx context.Context, id, userID int64) (*model.GalleryTemplate, error) {
+	if _, err := s.getApproved(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.Like(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// Unlike 取消点赞
+func (s *GalleryService) Unlike(ctx context.Context, id, userID int64) (*model.GalleryTemplate, error) {
+	if _, err := s.getApproved(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.Unlike(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// Copy 将已公开的条目复制到用户名下，受 MaxTemplatesPerUser 限制
+// 超出限制时返回 repository.ErrTemplateLimitExceeded，复制自己发布的条目时返回 ErrCopyOwnTemplate
+func (s *GalleryService) Copy(ctx context.Context, id, userID int64) (*model.UserTemplate, error) {
+	item, err := s.getApproved(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.UserID == userID {
+		return nil, ErrCopyOwnTemplate
+	}
+
+	templateID, err := util.GenerateID()
+	if err != nil {
+		return nil, err
+	}
+
+	// 复制到自己名下等同于新建模板，引用的变量须由复制者定义，并按当前规则重新审核
+	if err := s.templateService.CheckVariables(ctx, userID, item.TemplateContent); err != nil {
+		return nil, err
+	}
+	name, content, err := s.templateService.ModerateInput(ctx, userID, 0, item.TemplateName, item.TemplateContent)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &model.UserTemplate{
+		ID:              templateID,
+		UserID:          userID,
+		TemplateName:    name,
+		TemplateContent: content,
+	}
+	if err := s.templateService.CreateWithLimit(ctx, template, MaxTemplatesPerUser); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.IncrementCopyCount(ctx, id); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Approve 审核通过条目
+func (s *GalleryService) Approve(ctx context.Context, id int64) (*model.GalleryTemplate, error) {
+	return s.setStatus(ctx, id, model.GalleryStatusApproved)
+}
+
+// Hide 隐藏条目，已公开的条目随即从广场下架
+func (s *GalleryService) Hide(ctx context.Context, id int64) (*model.GalleryTemplate, error) {
+	return s.setStatus(ctx, id, model.GalleryStatusHidden)
+}
+
+// setStatus 更新条目审核状态，条目不存在时返回 ErrGalleryNotFound
+func (s *GalleryService) setStatus(ctx context.Context, id int64, status string) (*model.GalleryTemplate, error) {
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrGalleryNotFound
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+	item.Status = status
+	return item, nil
+}
+
+// getApproved 获取已公开的条目，不存在或未公开时返回 ErrGalleryNotFound
+func (s *GalleryService) getApproved(ctx context.Context, id int64) (*model.GalleryTemplate, error) {
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.Status != model.GalleryStatusApproved {
+		return nil, ErrGalleryNotFound
+	}
+	return item, nil
+}
+
+// list 分页查询条目，page 从 1 开始，pageSize 超出范围时使用默认值或上限
+func (s *GalleryService) list(ctx context.Context, q repository.GalleryQuery, page, pageSize int, userID int64) (*GalleryPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultGalleryPageSize
+	}
+	if pageSize > MaxGalleryPageSize {
+		pageSize = MaxGalleryPageSize
+	}
+
+	total, err := s.repo.Count(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	q.Offset = (page - 1) * pageSize
+	q.Limit = pageSize
+	items, err := s.repo.Find(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	liked := map[int64]bool{}
+	if userID != 0 && len(items) > 0 {
+		ids := make([]int64, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		if liked, err = s.repo.GetLikedIDs(ctx, userID, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &GalleryPage{
+		Items:    make([]GalleryItem, len(items)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for i, item := range items {
+		result.Items[i] = GalleryItem{GalleryTemplate: item, Liked: liked[item.ID]}
+	}
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupGalleryTestService(t *testing.T) (*GalleryService, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.GalleryTemplate{}, &model.GalleryLike{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	templateService := NewUserTemplateService(repository.NewUserTemplateRepository(db))
+	return NewGalleryService(repository.NewGalleryRepository(db), templateService), db
+}
+
+func TestGalleryService_Publish(t *testing.T) {
+	service, _ := setupGalleryTestService(t)
+	ctx := context.Background()
+
+	template := &model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板", TemplateContent: "距离{exam}还有{time}"}
+	item, err := service.Publish(ctx, template)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if item.Status != model.GalleryStatusPending || item.TemplateID != 1 || item.UserID != 123 {
+		t.Errorf("Publish() = %+v", item)
+	}
+
+	// 内容未变化时保持审核结果
+	if _, err := service.Approve(ctx, item.ID); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	again, err := service.Publish(ctx, template)
+	if err != nil || again.ID != item.ID || again.Status != model.GalleryStatusApproved {
+		t.Errorf("Publish() unchanged = %+v, %v, want approved", again, err)
+	}
+
+	// 内容变化后重新进入审核
+	template.TemplateContent = "还有{time}就到{exam}了"
+	changed, err := service.Publish(ctx, template)
+	if err != nil || changed.ID != item.ID || changed.Status != model.GalleryStatusPending || changed.TemplateContent != template.TemplateContent {
+		t.Errorf("Publish() changed = %+v, %v, want pending", changed, err)
+	}
+
+	if _, err := service.Publish(ctx, &model.UserTemplate{ID: 2, UserID: 123, TemplateContent: "没有变量"}); err == nil {
+		t.Error("Publish() should reject invalid template")
+	}
+}
+
+func TestGalleryService_BrowseAndLike(t *testing.T) {
+	service, _ := setupGalleryTestService(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for i := int64(1); i <= 3; i++ {
+		item, err := service.Publish(ctx, &model.UserTemplate{ID: i, UserID: 100 + i, TemplateContent: "距离{exam}还有{time}"})
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+	_, _ = service.Approve(ctx, ids[0])
+	_, _ = service.Approve(ctx, ids[1])
+
+	// 待审核条目不公开，无法点赞
+	if _, err := service.Like(ctx, ids[2], 42); !errors.Is(err, ErrGalleryNotFound) {
+		t.Errorf("Like() pending error = %v, want ErrGalleryNotFound", err)
+	}
+
+	liked, err := service.Like(ctx, ids[1], 42)
+	if err != nil || liked.LikeCount != 1 {
+		t.Fatalf("Like() = %+v, %v", liked, err)
+	}
+
+	page, err := service.Browse(ctx, "", repository.GallerySortPopular, 1, 1, 42)
+	if err != nil {
+		t.Fatalf("Browse() error = %v", err)
+	}
+	if page.Total != 2 || page.PageSize != 1 || len(page.Items) != 1 || page.Items[0].ID != ids[1] || !page.Items[0].Liked {
+		t.Errorf("Browse() = %+v", page)
+	}
+
+	page, _ = service.Browse(ctx, "", repository.GallerySortPopular, 2, 1, 42)
+	if len(page.Items) != 1 || page.Items[0].ID != ids[0] || page.Items[0].Liked {
+		t.Errorf("Browse() page 2 = %+v", page)
+	}
+
+	// 分页参数越界时使用默认值和上限
+	page, _ = service.Browse(ctx, "", "", 0, 1000, 0)
+	if page.Page != 1 || page.PageSize != MaxGalleryPageSize {
+		t.Errorf("Browse() = page %d size %d", page.Page, page.PageSize)
+	}
+
+	unliked, err := service.Unlike(ctx, ids[1], 42)
+	if err != nil || unliked.LikeCount != 0 {
+		t.Errorf("Unlike() = %+v, %v", unliked, err)
+	}
+
+	queue, err := service.ListByStatus(ctx, model.GalleryStatusPending, 1, 0)
+	if err != nil || queue.Total != 1 || queue.Items[0].ID != ids[2] {
+		t.Errorf("ListByStatus() = %+v, %v", queue, err)
+	}
+
+	// 隐藏后从广场下架
+	if _, err := service.Hide(ctx, ids[0]); err != nil {
+		t.Fatalf("Hide() error = %v", err)
+	}
+	if found, _ := service.Search(ctx, "", 10); len(found) != 1 || found[0].ID != ids[1] {
+		t.Errorf("Search() after hide = %+v", found)
+	}
+	if _, err := service.Hide(ctx, 999); !errors.Is(err, ErrGalleryNotFound) {
+		t.Errorf("Hide() missing error = %v, want ErrGalleryNotFound", err)
+	}
+
+	mine, err := service.ListByUser(ctx, 103)
+	if err != nil || len(mine) != 1 || mine[0].Status != model.GalleryStatusPending {
+		t.Errorf("ListByUser() = %+v, %v", mine, err)
+	}
+}
+
+func TestGalleryService_Copy(t *testing.T) {
+	service, db := setupGalleryTestService(t)
+	ctx := context.Background()
+
+	template := &model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板", TemplateContent: "距离{exam}还有{time}"}
+	db.Create(template)
+	item, _ := service.Publish(ctx, template)
+
+	if _, err := service.Copy(ctx, item.ID, 456); !errors.Is(err, ErrGalleryNotFound) {
+		t.Errorf("Copy() pending error = %v, want ErrGalleryNotFound", err)
+	}
+	_, _ = service.Approve(ctx, item.ID)
+
+	if _, err := service.Copy(ctx, item.ID, 123); !errors.Is(err, ErrCopyOwnTemplate) {
+		t.Errorf("Copy() own error = %v, want ErrCopyOwnTemplate", err)
+	}
+
+	copied, err := service.Copy(ctx, item.ID, 456)
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if copied.UserID != 456 || copied.TemplateName != "模板" || copied.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("Copy() = %+v", copied)
+	}
+	if got, _ := service.GetByID(ctx, item.ID); got.CopyCount != 1 {
+		t.Errorf("CopyCount = %d, want 1", got.CopyCount)
+	}
+
+	for i := 1; i < MaxTemplatesPerUser; i++ {
+		db.Create(&model.UserTemplate{ID: int64(100 + i), UserID: 456, TemplateContent: "{exam}{time}"})
+	}
+	if _, err := service.Copy(ctx, item.ID, 456); !errors.Is(err, repository.ErrTemplateLimitExceeded) {
+		t.Errorf("Copy() over limit error = %v, want ErrTemplateLimitExceeded", err)
+	}
+
+	if err := service.Unpublish(ctx, item.ID); err != nil {
+		t.Fatalf("Unpublish() error = %v", err)
+	}
+	if got, _ := service.GetByID(ctx, item.ID); got != nil {
+		t.Errorf("GetByID() after unpublish = %+v", got)
+	}
+}
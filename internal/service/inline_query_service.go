@@ -1,68 +1,115 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
 	"github.com/herbertgao/gaokao_bot/internal/util"
-	"github.com/herbertgao/gaokao_bot/pkg/constant"
 	"github.com/mymmrac/telego"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// GalleryInlinePrefix 内联查询中搜索模板广场的前缀，如 "#加油"
+	GalleryInlinePrefix = "#"
+
+	// GalleryInlineLimit 内联查询返回的模板广场结果数量上限
+	GalleryInlineLimit = 10
+)
+
 // InlineQueryService 内联查询服务
 type InlineQueryService struct {
 	examDateService     *ExamDateService
+	examEventService    *ExamEventService
 	userTemplateService *UserTemplateService
+	galleryService      *GalleryService
+	officialService     *OfficialTemplateService
+	variableService     *UserVariableService
+	quoteService        *QuoteService
 	logger              *logrus.Logger
 }
 
 // NewInlineQueryService 创建内联查询服务
+// examEventService 为 nil 时不返回考后时间线结果
 func NewInlineQueryService(
 	examDateService *ExamDateService,
+	examEventService *ExamEventService,
 	userTemplateService *UserTemplateService,
 	logger *logrus.Logger,
 ) *InlineQueryService {
 	return &InlineQueryService{
 		examDateService:     examDateService,
+		examEventService:    examEventService,
 		userTemplateService: userTemplateService,
 		logger:              logger,
 	}
 }
 
+// WithGallery 启用模板广场搜索：以 GalleryInlinePrefix 开头的查询返回已公开的广场模板
+func (s *InlineQueryService) WithGallery(galleryService *GalleryService) *InlineQueryService {
+	s.galleryService = galleryService
+	return s
+}
+
+// WithOfficialTemplates 启用官方模板：每场考试在默认模板之后附带当前生效的官方模板
+func (s *InlineQueryService) WithOfficialTemplates(officialService *OfficialTemplateService) *InlineQueryService {
+	s.officialService = officialService
+	return s
+}
+
+// WithUserVariables 启用用户自定义变量：渲染结果时将 {var:名称} 替换为查询用户定义的值
+func (s *InlineQueryService) WithUserVariables(variableService *UserVariableService) *InlineQueryService {
+	s.variableService = variableService
+	return s
+}
+
+// WithQuotes 启用励志语录：将 {quote} 替换为查询用户的今日语录
+func (s *InlineQueryService) WithQuotes(quoteService *QuoteService) *InlineQueryService {
+	s.quoteService = quoteService
+	return s
+}
+
 // GetInlineQueryResults 获取内联查询结果
-func (s *InlineQueryService) GetInlineQueryResults(query *telego.InlineQuery) []telego.InlineQueryResult {
+func (s *InlineQueryService) GetInlineQueryResults(ctx context.Context, query *telego.InlineQuery) []telego.InlineQueryResult {
 	now := util.NowBJT()
 
-	var examList []model.ExamDate
-	var err error
+	rc := s.getRenderContext(ctx, query.From.ID, now)
 
-	// 解析年份
-	if query.Query != "" {
-		if y, err := strconv.Atoi(query.Query); err == nil && y >= constant.MinExamYear && y <= constant.MaxExamYear {
-			examList, err = s.examDateService.GetExamByYear(y)
-			if err != nil {
-				s.logger.Errorf("按年份 %d 查询考试失败: %v", y, err)
-				return []telego.InlineQueryResult{}
-			}
-		}
-	} else {
-		// 没有参数时，获取当前时间范围内的所有考试
-		examList, err = s.examDateService.GetExamsInRange(now)
+	if keyword, ok := strings.CutPrefix(query.Query, GalleryInlinePrefix); ok && s.galleryService != nil {
+		return s.getGalleryResults(ctx, strings.TrimSpace(keyword), rc, now)
+	}
+
+	// 与 /d 共用参数语法，无法识别时不返回结果
+	examList, err := s.examDateService.ResolveCountdownArg(ctx, query.Query, now)
+	if errors.Is(err, ErrCountdownArgUnrecognized) || errors.Is(err, ErrCountdownDatePassed) {
+		return []telego.InlineQueryResult{}
+	}
+	if err != nil {
+		s.logger.Errorf("查询考试失败（参数: %q）: %v", query.Query, err)
+		return []telego.InlineQueryResult{}
+	}
+
+	// 已结束考试的考后时间线；无参数时附带近期有安排的考试
+	var timelines []ExamTimeline
+	if s.examEventService != nil {
+		timelines, err = s.examEventService.GetTimelines(ctx, examList, query.Query == "", now)
 		if err != nil {
-			s.logger.Errorf("查询时间范围内的考试失败: %v", err)
+			s.logger.Errorf("查询考后时间线失败: %v", err)
 			return []telego.InlineQueryResult{}
 		}
 	}
 
 	// 如果没有找到任何考试
-	if len(examList) == 0 {
+	if len(examList) == 0 && len(timelines) == 0 {
 		return []telego.InlineQueryResult{}
 	}
 
-	// 获取默认模板
-	defaultTemplate, err := s.userTemplateService.GetDefaultTemplate()
+	// 获取默认模板（设置了个人默认模板时以其为首个结果）
+	defaultTemplate, err := s.userTemplateService.GetDefaultTemplateForUser(ctx, query.From.ID)
 	if err != nil {
 		s.logger.Errorf("获取默认模板失败: %v", err)
 		return []telego.InlineQueryResult{}
@@ -71,7 +118,16 @@ func (s *InlineQueryService) GetInlineQueryResults(query *telego.InlineQuery) []
 	// 获取用户自定义模板
 	var userTemplates []model.UserTemplate
 	if query.From.ID != 0 {
-		userTemplates, _ = s.userTemplateService.GetByUserID(query.From.ID)
+		userTemplates, _ = s.userTemplateService.GetByUserID(ctx, query.From.ID)
+	}
+
+	// 获取各考试生效的官方模板，失败时仅忽略官方模板
+	var officialTemplates [][]model.OfficialTemplate
+	if s.officialService != nil {
+		officialTemplates, err = s.officialService.GetActiveByExam(ctx, examList, now)
+		if err != nil {
+			s.logger.Errorf("获取官方模板失败: %v", err)
+		}
 	}
 
 	results := []telego.InlineQueryResult{}
@@ -83,7 +139,10 @@ func (s *InlineQueryService) GetInlineQueryResults(query *telego.InlineQuery) []
 		// 默认模板结果
 		if defaultTemplate != nil {
 			defaultTitle := fmt.Sprintf("查看%s倒计时", examDesc)
-			defaultMessage := util.GetCountDownString(&exam, defaultTemplate.TemplateContent, now)
+			if defaultTemplate.TemplateName != "" {
+				defaultTitle = fmt.Sprintf("%s (%s)", defaultTitle, defaultTemplate.TemplateName)
+			}
+			defaultMessage := renderCountdown(&exam, defaultTemplate.TemplateContent, rc, now)
 			result := &telego.InlineQueryResultArticle{
 				Type:  telego.ResultTypeArticle,
 				ID:    fmt.Sprintf("default_%d", idx),
@@ -95,13 +154,35 @@ func (s *InlineQueryService) GetInlineQueryResults(query *telego.InlineQuery) []
 			results = append(results, result)
 		}
 
-		// 用户自定义模板结果
+		// 官方模板结果
+		if idx < len(officialTemplates) {
+			for _, template := range officialTemplates[idx] {
+				name := template.TemplateName
+				if name == "" {
+					name = "官方模板"
+				}
+				result := &telego.InlineQueryResultArticle{
+					Type:  telego.ResultTypeArticle,
+					ID:    fmt.Sprintf("official_%d_%d", idx, template.ID),
+					Title: fmt.Sprintf("查看%s倒计时 (%s)", examDesc, name),
+					InputMessageContent: &telego.InputTextMessageContent{
+						MessageText: renderCountdown(&exam, template.TemplateContent, rc, now),
+					},
+				}
+				results = append(results, result)
+			}
+		}
+
+		// 用户自定义模板结果（个人默认模板已作为首个结果，不再重复）
 		for tidx, template := range userTemplates {
+			if template.IsDefault {
+				continue
+			}
 			title := fmt.Sprintf("查看%s倒计时", examDesc)
 			if template.TemplateName != "" {
 				title = fmt.Sprintf("%s (%s)", title, template.TemplateName)
 			}
-			message := util.GetCountDownString(&exam, template.TemplateContent, now)
+			message := renderCountdown(&exam, template.TemplateContent, rc, now)
 			result := &telego.InlineQueryResultArticle{
 				Type:  telego.ResultTypeArticle,
 				ID:    fmt.Sprintf("user_%d_%d", idx, tidx),
@@ -114,5 +195,74 @@ func (s *InlineQueryService) GetInlineQueryResults(query *telego.InlineQuery) []
 		}
 	}
 
+	// 考后时间线结果
+	for idx, timeline := range timelines {
+		result := &telego.InlineQueryResultArticle{
+			Type:  telego.ResultTypeArticle,
+			ID:    fmt.Sprintf("timeline_%d", idx),
+			Title: fmt.Sprintf("查看%s后续安排", timeline.Title),
+			InputMessageContent: &telego.InputTextMessageContent{
+				MessageText: util.FormatExamTimeline(timeline.Title, timeline.Events, now),
+			},
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// getGalleryResults 搜索模板广场，使用最近的考试渲染各模板
+func (s *InlineQueryService) getGalleryResults(ctx context.Context, keyword string, rc renderContext, now time.Time) []telego.InlineQueryResult {
+	examList, err := s.examDateService.ResolveCountdownArg(ctx, "", now)
+	if err != nil {
+		s.logger.Errorf("查询考试失败: %v", err)
+		return []telego.InlineQueryResult{}
+	}
+	if len(examList) == 0 {
+		return []telego.InlineQueryResult{}
+	}
+	exam := &examList[0]
+
+	items, err := s.galleryService.Search(ctx, keyword, GalleryInlineLimit)
+	if err != nil {
+		s.logger.Errorf("搜索模板广场失败（关键词: %q）: %v", keyword, err)
+		return []telego.InlineQueryResult{}
+	}
+
+	results := []telego.InlineQueryResult{}
+	for _, item := range items {
+		title := "模板广场"
+		if item.TemplateName != "" {
+			title = fmt.Sprintf("%s：%s", title, item.TemplateName)
+		}
+		result := &telego.InlineQueryResultArticle{
+			Type:        telego.ResultTypeArticle,
+			ID:          fmt.Sprintf("gallery_%d", item.ID),
+			Title:       title,
+			Description: fmt.Sprintf("%s（%d 赞）", item.TemplateContent, item.LikeCount),
+			InputMessageContent: &telego.InputTextMessageContent{
+				MessageText: renderCountdown(exam, item.TemplateContent, rc, now),
+			},
+		}
+		results = append(results, result)
+	}
 	return results
 }
+
+// getRenderContext 获取查询用户的自定义变量和今日语录
+// 未启用或获取失败时为空（自定义变量原样显示，{quote} 替换为空）
+func (s *InlineQueryService) getRenderContext(ctx context.Context, userID int64, now time.Time) renderContext {
+	var rc renderContext
+	var err error
+	if s.variableService != nil {
+		if rc.variables, err = s.variableService.GetValues(ctx, userID); err != nil {
+			s.logger.Errorf("获取用户变量失败: %v", err)
+		}
+	}
+	if s.quoteService != nil {
+		if rc.quote, err = s.quoteService.DailyQuote(ctx, UserQuoteKey(userID), now); err != nil {
+			s.logger.Errorf("获取今日语录失败: %v", err)
+		}
+	}
+	return rc
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,7 +40,7 @@ func setupInlineQueryTestService(t *testing.T) (*InlineQueryService, *gorm.DB) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	inlineQueryService := NewInlineQueryService(examDateService, userTemplateService, logger)
+	inlineQueryService := NewInlineQueryService(examDateService, nil, userTemplateService, logger)
 
 	return inlineQueryService, db
 }
@@ -75,7 +77,7 @@ func TestInlineQueryService_GetInlineQueryResults_NoQuery(t *testing.T) {
 		From:  telego.User{ID: 123},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	if len(results) == 0 {
 		t.Error("Expected at least one result")
@@ -113,7 +115,7 @@ func TestInlineQueryService_GetInlineQueryResults_WithYear(t *testing.T) {
 		From:  telego.User{ID: 123},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	if len(results) == 0 {
 		t.Error("Expected at least one result")
@@ -129,7 +131,7 @@ func TestInlineQueryService_GetInlineQueryResults_InvalidYear(t *testing.T) {
 		From:  telego.User{ID: 123},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	if len(results) != 0 {
 		t.Errorf("Expected 0 results, got %d", len(results))
@@ -145,7 +147,7 @@ func TestInlineQueryService_GetInlineQueryResults_NonNumericQuery(t *testing.T)
 		From:  telego.User{ID: 123},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	if len(results) != 0 {
 		t.Errorf("Expected 0 results, got %d", len(results))
@@ -161,7 +163,7 @@ func TestInlineQueryService_GetInlineQueryResults_NoExams(t *testing.T) {
 		From:  telego.User{ID: 123},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	if len(results) != 0 {
 		t.Errorf("Expected 0 results, got %d", len(results))
@@ -209,7 +211,7 @@ func TestInlineQueryService_GetInlineQueryResults_WithUserTemplates(t *testing.T
 		From:  telego.User{ID: userID},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	// 应该有2个结果：默认模板 + 用户模板
 	if len(results) != 2 {
@@ -262,7 +264,7 @@ func TestInlineQueryService_GetInlineQueryResults_MultipleExams(t *testing.T) {
 		From:  telego.User{ID: 123},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	// 应该有2个结果（每个考试一个）
 	if len(results) != 2 {
@@ -297,7 +299,7 @@ func TestInlineQueryService_GetInlineQueryResults_NoDefaultTemplate(t *testing.T
 		From:  telego.User{ID: 123},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	// 没有默认模板，应该没有结果
 	if len(results) != 0 {
@@ -353,10 +355,252 @@ func TestInlineQueryService_GetInlineQueryResults_WithMultipleUserTemplates(t *t
 		From:  telego.User{ID: userID},
 	}
 
-	results := service.GetInlineQueryResults(query)
+	results := service.GetInlineQueryResults(context.Background(), query)
 
 	// 应该有3个结果：默认模板 + 2个用户模板
 	if len(results) != 3 {
 		t.Errorf("Expected 3 results (default + 2 user templates), got %d", len(results))
 	}
 }
+
+func TestInlineQueryService_GetInlineQueryResults_DateQuery(t *testing.T) {
+	service, db := setupInlineQueryTestService(t)
+
+	// 插入默认模板
+	db.Create(&model.UserTemplate{
+		ID:              1,
+		UserID:          0,
+		TemplateContent: "距离{exam}还有{time}",
+	})
+
+	query := &telego.InlineQuery{
+		ID:    "test",
+		Query: "100天后",
+		From:  telego.User{ID: 123},
+	}
+
+	results := service.GetInlineQueryResults(context.Background(), query)
+
+	if len(results) == 0 {
+		t.Fatal("Expected results for relative date query")
+	}
+	article, ok := results[0].(*telego.InlineQueryResultArticle)
+	if !ok {
+		t.Fatalf("Expected InlineQueryResultArticle, got %T", results[0])
+	}
+	wantDate := util.FormatChineseDate(util.StartOfDay(util.NowBJT()).AddDate(0, 0, 100))
+	if article.Title != "查看"+wantDate+"倒计时" {
+		t.Errorf("Title = %s, want 查看%s倒计时", article.Title, wantDate)
+	}
+}
+
+func TestInlineQueryService_GetInlineQueryResults_Timeline(t *testing.T) {
+	eventService, db := setupExamEventTestService(t)
+	if err := db.AutoMigrate(&model.UserTemplate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	seedTimelineData(db)
+	db.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateContent: "距离{exam}还有{time}"})
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	service := NewInlineQueryService(
+		NewExamDateService(repository.NewExamDateRepository(db)),
+		eventService,
+		NewUserTemplateService(repository.NewUserTemplateRepository(db)),
+		logger,
+	)
+
+	results := service.GetInlineQueryResults(context.Background(), &telego.InlineQuery{ID: "test", Query: "2026", From: telego.User{ID: 123}})
+
+	var found bool
+	for _, result := range results {
+		article, ok := result.(*telego.InlineQueryResultArticle)
+		if ok && article.Title == "查看2026年高考后续安排" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected timeline result, got %d results", len(results))
+	}
+}
+
+func TestInlineQueryService_GetInlineQueryResults_ContextCanceled(t *testing.T) {
+	service, db := setupInlineQueryTestService(t)
+
+	now := time.Now()
+	futureDate := now.AddDate(1, 0, 0)
+
+	db.Create(&model.ExamDate{
+		ID:                1,
+		ExamYear:          futureDate.Year(),
+		ExamDesc:          "高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     futureDate,
+		ExamEndDate:       futureDate.AddDate(0, 0, 3),
+		ExamYearBeginDate: now,
+		ExamYearEndDate:   futureDate.AddDate(0, 0, 3),
+	})
+
+	// 超过应答时限后查询被取消，不返回结果
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := service.GetInlineQueryResults(ctx, &telego.InlineQuery{ID: "test", From: telego.User{ID: 123}})
+	if len(results) != 0 {
+		t.Errorf("Expected no results for canceled context, got %d", len(results))
+	}
+}
+
+func TestInlineQueryService_GetInlineQueryResults_Gallery(t *testing.T) {
+	service, db := setupInlineQueryTestService(t)
+	if err := db.AutoMigrate(&model.GalleryTemplate{}, &model.GalleryLike{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	service.WithGallery(NewGalleryService(repository.NewGalleryRepository(db), service.userTemplateService))
+
+	now := time.Now()
+	futureDate := now.AddDate(1, 0, 0)
+
+	db.Create(&model.ExamDate{
+		ID:                1,
+		ExamYear:          futureDate.Year(),
+		ExamDesc:          "高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     futureDate,
+		ExamEndDate:       futureDate.AddDate(0, 0, 3),
+		ExamYearBeginDate: now,
+		ExamYearEndDate:   futureDate.AddDate(0, 0, 3),
+	})
+	db.Create(&model.GalleryTemplate{ID: 1, TemplateID: 11, UserID: 100, TemplateName: "加油", TemplateContent: "加油！距离{exam}还有{time}", Status: model.GalleryStatusApproved})
+	db.Create(&model.GalleryTemplate{ID: 2, TemplateID: 12, UserID: 100, TemplateContent: "{exam}还有{time}", Status: model.GalleryStatusApproved})
+	db.Create(&model.GalleryTemplate{ID: 3, TemplateID: 13, UserID: 100, TemplateContent: "加油{exam}{time}", Status: model.GalleryStatusPending})
+
+	results := service.GetInlineQueryResults(context.Background(), &telego.InlineQuery{ID: "test", Query: "#", From: telego.User{ID: 123}})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 gallery results, got %d", len(results))
+	}
+
+	results = service.GetInlineQueryResults(context.Background(), &telego.InlineQuery{ID: "test", Query: "# 加油", From: telego.User{ID: 123}})
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 gallery result, got %d", len(results))
+	}
+	article := results[0].(*telego.InlineQueryResultArticle)
+	message := article.InputMessageContent.(*telego.InputTextMessageContent).MessageText
+	if article.ID != "gallery_1" || article.Title != "模板广场：加油" || !strings.HasPrefix(message, "加油！距离高考还有") {
+		t.Errorf("Unexpected gallery result: %s %s %q", article.ID, article.Title, message)
+	}
+}
+
+func TestInlineQueryService_GetInlineQueryResults_PersonalDefault(t *testing.T) {
+	service, db := setupInlineQueryTestService(t)
+
+	now := time.Now()
+	futureDate := now.AddDate(1, 0, 0)
+	userID := int64(456)
+
+	db.Create(&model.ExamDate{
+		ID:                1,
+		ExamYear:          futureDate.Year(),
+		ExamDesc:          "高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     futureDate,
+		ExamEndDate:       futureDate.AddDate(0, 0, 3),
+		ExamYearBeginDate: now,
+		ExamYearEndDate:   futureDate.AddDate(0, 0, 3),
+	})
+	db.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: userID, TemplateName: "模板1", TemplateContent: "倒计时1：{exam} - {time}"})
+	db.Create(&model.UserTemplate{ID: 3, UserID: userID, TemplateName: "我的默认", TemplateContent: "我的：{exam} - {time}", IsDefault: true})
+
+	results := service.GetInlineQueryResults(context.Background(), &telego.InlineQuery{ID: "test", From: telego.User{ID: userID}})
+
+	// 个人默认模板替代全局默认模板作为首个结果，且不重复出现
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results (personal default + 1 user template), got %d", len(results))
+	}
+	article := results[0].(*telego.InlineQueryResultArticle)
+	message := article.InputMessageContent.(*telego.InputTextMessageContent).MessageText
+	if !strings.HasPrefix(message, "我的：高考") || !strings.HasSuffix(article.Title, "(我的默认)") {
+		t.Errorf("Unexpected first result: %s %q", article.Title, message)
+	}
+}
+
+func TestInlineQueryService_GetInlineQueryResults_OfficialTemplates(t *testing.T) {
+	service, db := setupInlineQueryTestService(t)
+	if err := db.AutoMigrate(&model.OfficialTemplate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	service.WithOfficialTemplates(NewOfficialTemplateService(repository.NewOfficialTemplateRepository(db)))
+
+	now := time.Now()
+	futureDate := now.AddDate(0, 0, 50)
+
+	db.Create(&model.ExamDate{
+		ID:                1,
+		ExamYear:          futureDate.Year(),
+		ExamDesc:          "高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     futureDate,
+		ExamEndDate:       futureDate.AddDate(0, 0, 3),
+		ExamYearBeginDate: now.AddDate(-1, 0, 0),
+		ExamYearEndDate:   futureDate.AddDate(0, 0, 3),
+	})
+	db.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.OfficialTemplate{ID: 10, TemplateName: "百日冲刺", TemplateContent: "{exam}百日冲刺：{time}", SortOrder: 1, Active: true, DaysBeforeExam: 100})
+	db.Create(&model.OfficialTemplate{ID: 11, TemplateContent: "官方：{exam} {time}", SortOrder: 2, Active: true})
+	db.Create(&model.OfficialTemplate{ID: 12, TemplateName: "未启用", TemplateContent: "{exam}{time}"})
+	db.Create(&model.OfficialTemplate{ID: 13, TemplateName: "三十天", TemplateContent: "{exam}{time}", Active: true, DaysBeforeExam: 30})
+
+	results := service.GetInlineQueryResults(context.Background(), &telego.InlineQuery{ID: "test", From: telego.User{ID: 123}})
+
+	// 默认模板之后依次为生效的官方模板
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results (default + 2 official), got %d", len(results))
+	}
+	first := results[1].(*telego.InlineQueryResultArticle)
+	message := first.InputMessageContent.(*telego.InputTextMessageContent).MessageText
+	if first.ID != "official_0_10" || first.Title != "查看高考倒计时 (百日冲刺)" || !strings.HasPrefix(message, "高考百日冲刺：") {
+		t.Errorf("Unexpected official result: %s %s %q", first.ID, first.Title, message)
+	}
+	if second := results[2].(*telego.InlineQueryResultArticle); second.Title != "查看高考倒计时 (官方模板)" {
+		t.Errorf("Unexpected unnamed official result title: %s", second.Title)
+	}
+}
+
+func TestInlineQueryService_GetInlineQueryResults_UserVariables(t *testing.T) {
+	service, db := setupInlineQueryTestService(t)
+	if err := db.AutoMigrate(&model.UserVariable{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	service.WithUserVariables(NewUserVariableService(repository.NewUserVariableRepository(db)))
+
+	now := time.Now()
+	futureDate := now.AddDate(1, 0, 0)
+	userID := int64(456)
+
+	db.Create(&model.ExamDate{
+		ID:                1,
+		ExamYear:          futureDate.Year(),
+		ExamDesc:          "高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     futureDate,
+		ExamEndDate:       futureDate.AddDate(0, 0, 3),
+		ExamYearBeginDate: now,
+		ExamYearEndDate:   futureDate.AddDate(0, 0, 3),
+	})
+	db.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: userID, TemplateContent: "{var:name}，距离{exam}还有{time}，目标{var:goal}"})
+	db.Create(&model.UserVariable{ID: 1, UserID: userID, VarName: "name", VarValue: "小明"})
+
+	results := service.GetInlineQueryResults(context.Background(), &telego.InlineQuery{ID: "test", From: telego.User{ID: userID}})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	// 已定义的变量被替换，未定义的变量原样显示
+	message := results[1].(*telego.InlineQueryResultArticle).InputMessageContent.(*telego.InputTextMessageContent).MessageText
+	if !strings.HasPrefix(message, "小明，距离高考还有") || !strings.HasSuffix(message, "目标{var:goal}") {
+		t.Errorf("Unexpected message: %q", message)
+	}
+}
@@ -1,84 +1,105 @@
 package service
 
 import (
-	"strconv"
+	"context"
+	"errors"
 	"strings"
 	"time"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
 	"github.com/herbertgao/gaokao_bot/internal/util"
-	"github.com/herbertgao/gaokao_bot/pkg/constant"
 	"github.com/mymmrac/telego"
 	"github.com/sirupsen/logrus"
 )
 
-// msgArgUnrecognized 参数无法识别时的提示文案
-const msgArgUnrecognized = "参数暂时无法识别。"
+const (
+	// msgArgUnrecognized 参数无法识别时的提示文案
+	msgArgUnrecognized = "参数暂时无法识别。"
+
+	// msgDatePassed 目标日期已过时的提示文案
+	msgDatePassed = "目标日期已经过去了。"
+)
 
 // MessageService 消息处理服务
 type MessageService struct {
 	examDateService     *ExamDateService
+	examEventService    *ExamEventService
 	userTemplateService *UserTemplateService
+	variableService     *UserVariableService
+	quoteService        *QuoteService
 	logger              *logrus.Logger
 }
 
 // NewMessageService 创建消息处理服务
+// examEventService 为 nil 时不展示考后时间线
 func NewMessageService(
 	examDateService *ExamDateService,
+	examEventService *ExamEventService,
 	userTemplateService *UserTemplateService,
 	logger *logrus.Logger,
 ) *MessageService {
 	return &MessageService{
 		examDateService:     examDateService,
+		examEventService:    examEventService,
 		userTemplateService: userTemplateService,
 		logger:              logger,
 	}
 }
 
-// GetCountDownMessage 获取倒计时消息
-func (s *MessageService) GetCountDownMessage(msg *telego.Message) (string, error) {
-	return s.BuildCountdownText(util.GetTextByMessage(msg), util.NowBJT())
+// WithUserVariables 启用用户自定义变量：使用个人默认模板时将 {var:名称} 替换为用户定义的值
+func (s *MessageService) WithUserVariables(variableService *UserVariableService) *MessageService {
+	s.variableService = variableService
+	return s
+}
+
+// WithQuotes 启用励志语录：将 {quote} 替换为发送者的今日语录
+func (s *MessageService) WithQuotes(quoteService *QuoteService) *MessageService {
+	s.quoteService = quoteService
+	return s
+}
+
+// GetCountDownMessage 获取倒计时消息，发送者设置了个人默认模板时使用个人默认模板
+func (s *MessageService) GetCountDownMessage(ctx context.Context, msg *telego.Message) (string, error) {
+	return s.BuildCountdownTextForUser(ctx, messageUserID(msg), util.GetTextByMessage(msg), util.NowBJT())
 }
 
 // BuildCountdownText 根据已提取的参数文本生成倒计时消息
-// arg 为空时输出当前时间范围内的倒计时，arg 为合法考试年份时按年份查询，
-// 其余情况返回「参数暂时无法识别。」。使用默认模板，多个考试拼接为单条文本。
-func (s *MessageService) BuildCountdownText(arg string, now time.Time) (string, error) {
-	text := arg
-
-	var examList []model.ExamDate
-	var err error
-
-	// 如果有参数且是数字，按年份查询
-	if text != "" {
-		if y, err := strconv.Atoi(text); err == nil && y >= constant.MinExamYear && y <= constant.MaxExamYear {
-			examList, err = s.examDateService.GetExamByYear(y)
-			if err != nil {
-				s.logger.Errorf("按年份 %d 查询考试失败: %v", y, err)
-				return "查询考试信息失败", err
-			}
-			if len(examList) == 0 {
-				return msgArgUnrecognized, nil
-			}
-		} else {
-			return msgArgUnrecognized, nil
-		}
-	} else {
-		// 没有参数时，获取当前时间范围内的所有考试
-		examList, err = s.examDateService.GetExamsInRange(now)
-		if err != nil {
-			s.logger.Errorf("查询时间范围内的考试失败: %v", err)
-			return "查询考试信息失败", err
-		}
+// arg 支持空参数（当前时间范围内的考试）、考试年份、具体日期（2026-06-07、6月7日）、
+// 相对日期（100天后）和考试简称，无法识别时返回「参数暂时无法识别。」。
+// 使用默认模板，考试按开始时间排序，多个考试时每个考试一行输出为列表。
+func (s *MessageService) BuildCountdownText(ctx context.Context, arg string, now time.Time) (string, error) {
+	return s.BuildCountdownTextForUser(ctx, 0, arg, now)
+}
+
+// BuildCountdownTextForUser 与 BuildCountdownText 相同，但使用用户的个人默认模板（未设置时使用全局默认）
+// userID 为 0 时使用全局默认模板
+func (s *MessageService) BuildCountdownTextForUser(ctx context.Context, userID int64, arg string, now time.Time) (string, error) {
+	examList, err := s.examDateService.ResolveCountdownArg(ctx, arg, now)
+	if errors.Is(err, ErrCountdownArgUnrecognized) {
+		return msgArgUnrecognized, nil
+	}
+	if errors.Is(err, ErrCountdownDatePassed) {
+		return msgDatePassed, nil
+	}
+	if err != nil {
+		s.logger.Errorf("查询考试失败（参数: %q）: %v", arg, err)
+		return "查询考试信息失败", err
+	}
+
+	// 已结束考试的考后时间线；无参数时附带近期有安排的考试
+	timelines, err := s.getTimelines(ctx, examList, arg == "", now)
+	if err != nil {
+		s.logger.Errorf("查询考后时间线失败: %v", err)
+		return "查询考试信息失败", err
 	}
 
 	// 如果没有找到任何考试
-	if len(examList) == 0 {
+	if len(examList) == 0 && len(timelines) == 0 {
 		return "数据库中没有可用的信息，请联系开发者。", nil
 	}
 
-	// 获取默认模板
-	template, err := s.userTemplateService.GetDefaultTemplate()
+	// 获取默认模板（优先使用个人默认模板）
+	template, err := s.userTemplateService.GetDefaultTemplateForUser(ctx, userID)
 	if err != nil {
 		s.logger.Errorf("获取默认模板失败: %v", err)
 		return "获取模板失败", err
@@ -89,12 +110,50 @@ func (s *MessageService) BuildCountdownText(arg string, now time.Time) (string,
 		templateContent = template.TemplateContent
 	}
 
+	// 获取用户自定义变量和今日语录，失败时变量原样显示、语录为空
+	var rc renderContext
+	if s.variableService != nil {
+		if rc.variables, err = s.variableService.GetValues(ctx, userID); err != nil {
+			s.logger.Errorf("获取用户变量失败: %v", err)
+		}
+	}
+	if s.quoteService != nil {
+		if rc.quote, err = s.quoteService.DailyQuote(ctx, UserQuoteKey(userID), now); err != nil {
+			s.logger.Errorf("获取今日语录失败: %v", err)
+		}
+	}
+
 	// 生成倒计时消息（循环处理所有考试）
-	var sb strings.Builder
+	messages := make([]string, 0, len(examList))
 	for _, exam := range examList {
-		message := util.GetCountDownString(&exam, templateContent, now)
-		sb.WriteString(message)
+		messages = append(messages, renderCountdown(&exam, templateContent, rc, now))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(util.FormatCountdownList(messages))
+
+	for _, timeline := range timelines {
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(util.FormatExamTimeline(timeline.Title, timeline.Events, now))
 	}
 
 	return sb.String(), nil
 }
+
+// getTimelines 获取考后时间线，未配置事件服务时返回空
+func (s *MessageService) getTimelines(ctx context.Context, examList []model.ExamDate, includeUpcoming bool, now time.Time) ([]ExamTimeline, error) {
+	if s.examEventService == nil {
+		return nil, nil
+	}
+	return s.examEventService.GetTimelines(ctx, examList, includeUpcoming, now)
+}
+
+// messageUserID 返回消息发送者的用户 ID，无发送者（如频道消息）时返回 0
+func messageUserID(msg *telego.Message) int64 {
+	if msg == nil || msg.From == nil {
+		return 0
+	}
+	return msg.From.ID
+}
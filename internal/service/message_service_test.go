@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,7 +40,7 @@ func setupMessageTestService(t *testing.T) (*MessageService, *gorm.DB) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	messageService := NewMessageService(examDateService, userTemplateService, logger)
+	messageService := NewMessageService(examDateService, nil, userTemplateService, logger)
 
 	return messageService, db
 }
@@ -67,7 +69,7 @@ func TestMessageService_GetCountDownMessage_NoParams(t *testing.T) {
 		Text: "",
 	}
 
-	result, err := service.GetCountDownMessage(msg)
+	result, err := service.GetCountDownMessage(context.Background(), msg)
 	if err != nil {
 		t.Errorf("GetCountDownMessage() error = %v", err)
 	}
@@ -100,7 +102,7 @@ func TestMessageService_GetCountDownMessage_WithYear(t *testing.T) {
 		Text: "2026",
 	}
 
-	result, err := service.GetCountDownMessage(msg)
+	result, err := service.GetCountDownMessage(context.Background(), msg)
 	if err != nil {
 		t.Errorf("GetCountDownMessage() error = %v", err)
 	}
@@ -118,7 +120,7 @@ func TestMessageService_GetCountDownMessage_InvalidYear(t *testing.T) {
 		Text: "2017", // 小于2018
 	}
 
-	result, err := service.GetCountDownMessage(msg)
+	result, err := service.GetCountDownMessage(context.Background(), msg)
 	if err != nil {
 		t.Errorf("GetCountDownMessage() should not error for invalid year, got %v", err)
 	}
@@ -136,7 +138,7 @@ func TestMessageService_GetCountDownMessage_NonNumericText(t *testing.T) {
 		Text: "hello",
 	}
 
-	result, err := service.GetCountDownMessage(msg)
+	result, err := service.GetCountDownMessage(context.Background(), msg)
 	if err != nil {
 		t.Errorf("GetCountDownMessage() should not error for non-numeric text, got %v", err)
 	}
@@ -154,7 +156,7 @@ func TestMessageService_GetCountDownMessage_YearNotFound(t *testing.T) {
 		Text: "2099",
 	}
 
-	result, err := service.GetCountDownMessage(msg)
+	result, err := service.GetCountDownMessage(context.Background(), msg)
 	if err != nil {
 		t.Errorf("GetCountDownMessage() error = %v", err)
 	}
@@ -172,7 +174,7 @@ func TestMessageService_GetCountDownMessage_NoExamsInRange(t *testing.T) {
 		Text: "",
 	}
 
-	result, err := service.GetCountDownMessage(msg)
+	result, err := service.GetCountDownMessage(context.Background(), msg)
 	if err != nil {
 		t.Errorf("GetCountDownMessage() error = %v", err)
 	}
@@ -212,7 +214,7 @@ func TestMessageService_GetCountDownMessage_WithCustomTemplate(t *testing.T) {
 		Text: "",
 	}
 
-	result, err := service.GetCountDownMessage(msg)
+	result, err := service.GetCountDownMessage(context.Background(), msg)
 	if err != nil {
 		t.Errorf("GetCountDownMessage() error = %v", err)
 	}
@@ -258,7 +260,7 @@ func TestMessageService_GetCountDownMessage_MultipleExams(t *testing.T) {
 		Text: "",
 	}
 
-	result, err := service.GetCountDownMessage(msg)
+	result, err := service.GetCountDownMessage(context.Background(), msg)
 	if err != nil {
 		t.Errorf("GetCountDownMessage() error = %v", err)
 	}
@@ -285,7 +287,7 @@ func TestMessageService_BuildCountdownText_NoArg(t *testing.T) {
 		IsDelete:          false,
 	})
 
-	result, err := service.BuildCountdownText("", now)
+	result, err := service.BuildCountdownText(context.Background(), "", now)
 	if err != nil {
 		t.Errorf("BuildCountdownText() error = %v", err)
 	}
@@ -310,7 +312,7 @@ func TestMessageService_BuildCountdownText_WithYear(t *testing.T) {
 		IsDelete:          false,
 	})
 
-	result, err := service.BuildCountdownText("2026", util.NowBJT())
+	result, err := service.BuildCountdownText(context.Background(), "2026", util.NowBJT())
 	if err != nil {
 		t.Errorf("BuildCountdownText() error = %v", err)
 	}
@@ -323,7 +325,7 @@ func TestMessageService_BuildCountdownText_InvalidArg(t *testing.T) {
 	service, _ := setupMessageTestService(t)
 
 	for _, arg := range []string{"2017", "hello", "2099"} {
-		result, err := service.BuildCountdownText(arg, util.NowBJT())
+		result, err := service.BuildCountdownText(context.Background(), arg, util.NowBJT())
 		if err != nil {
 			t.Errorf("BuildCountdownText(%q) should not error, got %v", arg, err)
 		}
@@ -336,7 +338,7 @@ func TestMessageService_BuildCountdownText_InvalidArg(t *testing.T) {
 func TestMessageService_BuildCountdownText_NoData(t *testing.T) {
 	service, _ := setupMessageTestService(t)
 
-	result, err := service.BuildCountdownText("", time.Now())
+	result, err := service.BuildCountdownText(context.Background(), "", time.Now())
 	if err != nil {
 		t.Errorf("BuildCountdownText() error = %v", err)
 	}
@@ -344,3 +346,175 @@ func TestMessageService_BuildCountdownText_NoData(t *testing.T) {
 		t.Errorf("BuildCountdownText() = %q, want '数据库中没有可用的信息，请联系开发者。'", result)
 	}
 }
+
+func TestMessageService_BuildCountdownText_DateArgs(t *testing.T) {
+	service, db := setupMessageTestService(t)
+
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, loc)
+	db.Create(&model.ExamDate{
+		ID:                1,
+		ExamYear:          2026,
+		ExamDesc:          "2026年高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     time.Date(2026, 6, 7, 9, 0, 0, 0, loc),
+		ExamEndDate:       time.Date(2026, 6, 10, 17, 0, 0, 0, loc),
+		ExamYearBeginDate: time.Date(2025, 6, 10, 17, 0, 0, 0, loc),
+		ExamYearEndDate:   time.Date(2026, 6, 10, 17, 0, 0, 0, loc),
+	})
+
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"2026-03-03", "现在距离2026年3月3日还有1天14小时"},
+		{"3月3日", "现在距离2026年3月3日还有1天14小时"},
+		{"2天后", "现在距离2026年3月3日还有1天14小时"},
+		{"高考", "现在距离2026年高考还有97天23小时"},
+		{"2026-01-01", "目标日期已经过去了。"},
+		{"2026-02-30", "参数暂时无法识别。"},
+	}
+
+	for _, tt := range tests {
+		result, err := service.BuildCountdownText(context.Background(), tt.arg, now)
+		if err != nil {
+			t.Errorf("BuildCountdownText(%q) error = %v", tt.arg, err)
+		}
+		if result != tt.want {
+			t.Errorf("BuildCountdownText(%q) = %q, want %q", tt.arg, result, tt.want)
+		}
+	}
+}
+
+func TestMessageService_BuildCountdownText_WithTimeline(t *testing.T) {
+	eventService, db := setupExamEventTestService(t)
+	if err := db.AutoMigrate(&model.UserTemplate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	seedTimelineData(db)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	service := NewMessageService(
+		NewExamDateService(repository.NewExamDateRepository(db)),
+		eventService,
+		NewUserTemplateService(repository.NewUserTemplateRepository(db)),
+		logger,
+	)
+
+	now := time.Date(2026, 6, 20, 10, 0, 0, 0, util.GetBJTLocation())
+
+	// 无参数：2027 年倒计时 + 近期考后安排
+	result, err := service.BuildCountdownText(context.Background(), "", now)
+	if err != nil {
+		t.Fatalf("BuildCountdownText() error = %v", err)
+	}
+	if !strings.HasPrefix(result, "现在距离2027年高考还有") {
+		t.Errorf("Expected countdown first, got %q", result)
+	}
+	if !strings.Contains(result, "\n\n2026年高考后续安排：\n· 成绩公布 6月25日 12:00（还有5天2小时）") {
+		t.Errorf("Expected timeline in result, got %q", result)
+	}
+
+	// 按年份查询已结束的考试：结束提示 + 该考试的时间线
+	result, err = service.BuildCountdownText(context.Background(), "2026", now)
+	if err != nil {
+		t.Fatalf("BuildCountdownText() error = %v", err)
+	}
+	if !strings.HasPrefix(result, "2026年高考已经结束了。\n\n2026年高考后续安排：") {
+		t.Errorf("Expected ended exam with timeline, got %q", result)
+	}
+	if strings.Contains(result, "北京") {
+		t.Errorf("Expected no upcoming timelines for year query, got %q", result)
+	}
+}
+
+func TestMessageService_BuildCountdownText_MultipleExamsList(t *testing.T) {
+	service, db := setupMessageTestService(t)
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, loc)
+
+	// 故意先插入较晚的考试，验证按开始时间排序
+	for _, exam := range []model.ExamDate{
+		{ID: 1, ExamYear: 2026, ExamKind: "zhongkao", ExamDesc: "中考", ShortDesc: "中考", ExamBeginDate: time.Date(2026, 6, 24, 9, 0, 0, 0, loc)},
+		{ID: 2, ExamYear: 2026, ExamKind: "gaokao", ExamDesc: "高考", ShortDesc: "高考", ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc)},
+	} {
+		exam.ExamEndDate = exam.ExamBeginDate.AddDate(0, 0, 3)
+		exam.ExamYearBeginDate = now.AddDate(0, -1, 0)
+		exam.ExamYearEndDate = exam.ExamEndDate
+		db.Create(&exam)
+	}
+	db.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateName: "默认", TemplateContent: "{exam}"})
+
+	result, err := service.BuildCountdownText(context.Background(), "", now)
+	if err != nil {
+		t.Fatalf("BuildCountdownText() error = %v", err)
+	}
+
+	if want := "· 高考\n· 中考"; result != want {
+		t.Errorf("BuildCountdownText() = %q, want %q", result, want)
+	}
+}
+
+func TestMessageService_GetCountDownMessage_PersonalDefault(t *testing.T) {
+	service, db := setupMessageTestService(t)
+
+	now := time.Now()
+	futureDate := now.AddDate(1, 0, 0)
+
+	db.Create(&model.ExamDate{
+		ID:                1,
+		ExamYear:          futureDate.Year(),
+		ExamDesc:          "高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     futureDate,
+		ExamEndDate:       futureDate.AddDate(0, 0, 3),
+		ExamYearBeginDate: now,
+		ExamYearEndDate:   futureDate.AddDate(0, 0, 3),
+	})
+	db.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: 123, TemplateContent: "我的：{exam} - {time}", IsDefault: true})
+
+	// 设置了个人默认模板的用户使用个人模板
+	result, err := service.GetCountDownMessage(context.Background(), &telego.Message{From: &telego.User{ID: 123}})
+	if err != nil || !strings.HasPrefix(result, "我的：高考") {
+		t.Errorf("GetCountDownMessage() = %q, %v, want personal template", result, err)
+	}
+
+	// 其他用户仍使用全局默认模板
+	result, err = service.GetCountDownMessage(context.Background(), &telego.Message{From: &telego.User{ID: 456}})
+	if err != nil || !strings.HasPrefix(result, "距离高考还有") {
+		t.Errorf("GetCountDownMessage() = %q, %v, want global template", result, err)
+	}
+}
+
+func TestMessageService_BuildCountdownTextForUser_UserVariables(t *testing.T) {
+	service, db := setupMessageTestService(t)
+	if err := db.AutoMigrate(&model.UserVariable{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	service.WithUserVariables(NewUserVariableService(repository.NewUserVariableRepository(db)))
+
+	now := time.Now()
+	futureDate := now.AddDate(1, 0, 0)
+	userID := int64(456)
+
+	db.Create(&model.ExamDate{
+		ID:                1,
+		ExamYear:          futureDate.Year(),
+		ExamDesc:          "高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     futureDate,
+		ExamEndDate:       futureDate.AddDate(0, 0, 3),
+		ExamYearBeginDate: now,
+		ExamYearEndDate:   futureDate.AddDate(0, 0, 3),
+	})
+	db.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: userID, TemplateContent: "{var:name}，距离{exam}还有{time}", IsDefault: true})
+	db.Create(&model.UserVariable{ID: 1, UserID: userID, VarName: "name", VarValue: "小明"})
+
+	text, err := service.BuildCountdownTextForUser(context.Background(), userID, "", now)
+	if err != nil || !strings.HasPrefix(text, "小明，距离高考还有") {
+		t.Errorf("BuildCountdownTextForUser() = %q, %v", text, err)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+// MaxOfficialTemplateDaysBeforeExam 官方模板“距考试天数”限制的上限
+const MaxOfficialTemplateDaysBeforeExam = 366
+
+var (
+	// ErrOfficialTemplateNotFound 官方模板不存在
+	ErrOfficialTemplateNotFound = errors.New("official template not found")
+	// ErrSendChatNotFound 推送对话不存在
+	ErrSendChatNotFound = errors.New("send chat not found")
+)
+
+// OfficialTemplateService 官方模板服务
+type OfficialTemplateService struct {
+	repo repository.OfficialTemplateRepository
+}
+
+// NewOfficialTemplateService 创建官方模板服务
+func NewOfficialTemplateService(repo repository.OfficialTemplateRepository) *OfficialTemplateService {
+	return &OfficialTemplateService{repo: repo}
+}
+
+// List 获取全部官方模板（包含未生效的），按排序位置排列
+func (s *OfficialTemplateService) List(ctx context.Context) ([]model.OfficialTemplate, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// GetByID 根据 ID 获取官方模板，不存在时返回 nil
+func (s *OfficialTemplateService) GetByID(ctx context.Context, id int64) (*model.OfficialTemplate, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Create 创建官方模板并分配 ID，调用前应通过 ValidateOfficialTemplate 校验
+func (s *OfficialTemplateService) Create(ctx context.Context, template *model.OfficialTemplate) error {
+	id, err := util.GenerateID()
+	if err != nil {
+		return err
+	}
+	template.ID = id
+	return s.repo.Create(ctx, template)
+}
+
+// Update 更新官方模板，模板不存在时返回 ErrOfficialTemplateNotFound
+func (s *OfficialTemplateService) Update(ctx context.Context, template *model.OfficialTemplate) error {
+	existing, err := s.repo.GetByID(ctx, template.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrOfficialTemplateNotFound
+	}
+	template.CreatedAt = existing.CreatedAt
+	return s.repo.Update(ctx, template)
+}
+
+// Delete 删除官方模板
+// 选用该模板的推送对话会在推送时回退到默认模板
+func (s *OfficialTemplateService) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// GetActiveByExam 获取各考试在 now 时生效的官方模板，结果与 exams 一一对应
+func (s *OfficialTemplateService) GetActiveByExam(ctx context.Context, exams []model.ExamDate, now time.Time) ([][]model.OfficialTemplate, error) {
+	templates, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]model.OfficialTemplate, len(exams))
+	for i := range exams {
+		for _, template := range templates {
+			if IsOfficialTemplateActive(&template, &exams[i], now) {
+				result[i] = append(result[i], template)
+			}
+		}
+	}
+	return result, nil
+}
+
+// IsOfficialTemplateActive 判断官方模板在 now 时对指定考试是否生效：
+// 已启用、在生效时间范围内，且（设置了天数限制时）考试尚未开始并在限制天数之内
+func IsOfficialTemplateActive(template *model.OfficialTemplate, exam *model.ExamDate, now time.Time) bool {
+	if !template.Active {
+		return false
+	}
+	if template.StartAt != nil && now.Before(*template.StartAt) {
+		return false
+	}
+	if template.EndAt != nil && !now.Before(*template.EndAt) {
+		return false
+	}
+	if template.DaysBeforeExam > 0 {
+		remaining := exam.ExamBeginDate.Sub(now)
+		if remaining <= 0 || remaining > time.Duration(template.DaysBeforeExam)*24*time.Hour {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateOfficialTemplate 校验官方模板的名称、内容和生效条件，返回面向管理员的提示
+func ValidateOfficialTemplate(template *model.OfficialTemplate) error {
+	if err := ValidateTemplateName(template.TemplateName); err != nil {
+		return err
+	}
+	if err := ValidateTemplateContent(template.TemplateContent); err != nil {
+		return err
+	}
+	if template.StartAt != nil && template.EndAt != nil && !template.StartAt.Before(*template.EndAt) {
+		return fmt.Errorf("结束时间必须晚于开始时间")
+	}
+	if template.DaysBeforeExam < 0 || template.DaysBeforeExam > MaxOfficialTemplateDaysBeforeExam {
+		return fmt.Errorf("距考试天数必须在 0-%d 之间", MaxOfficialTemplateDaysBeforeExam)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupOfficialTemplateTestService(t *testing.T) (*OfficialTemplateService, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.OfficialTemplate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return NewOfficialTemplateService(repository.NewOfficialTemplateRepository(db)), db
+}
+
+func TestOfficialTemplateService_CRUD(t *testing.T) {
+	service, _ := setupOfficialTemplateTestService(t)
+	ctx := context.Background()
+
+	template := &model.OfficialTemplate{TemplateName: "常规", TemplateContent: "距离{exam}还有{time}", Active: true}
+	if err := service.Create(ctx, template); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if template.ID == 0 {
+		t.Error("Create() should assign an ID")
+	}
+
+	updated := &model.OfficialTemplate{ID: template.ID, TemplateName: "常规（新）", TemplateContent: "{exam}：{time}"}
+	if err := service.Update(ctx, updated); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := service.GetByID(ctx, template.ID)
+	if err != nil || got == nil || got.TemplateName != "常规（新）" || got.Active || got.CreatedAt.IsZero() {
+		t.Errorf("GetByID() after update = %+v, %v", got, err)
+	}
+
+	if err := service.Update(ctx, &model.OfficialTemplate{ID: 999}); !errors.Is(err, ErrOfficialTemplateNotFound) {
+		t.Errorf("Update() missing template error = %v, want ErrOfficialTemplateNotFound", err)
+	}
+
+	if err := service.Delete(ctx, template.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if templates, _ := service.List(ctx); len(templates) != 0 {
+		t.Errorf("List() after delete = %+v", templates)
+	}
+}
+
+func TestOfficialTemplateService_GetActiveByExam(t *testing.T) {
+	service, db := setupOfficialTemplateTestService(t)
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, loc)
+
+	db.Create(&model.OfficialTemplate{ID: 1, TemplateContent: "常规", SortOrder: 2, Active: true})
+	db.Create(&model.OfficialTemplate{ID: 2, TemplateContent: "百日冲刺", SortOrder: 1, Active: true, DaysBeforeExam: 100})
+
+	exams := []model.ExamDate{
+		{ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc)},
+		{ExamBeginDate: time.Date(2027, 6, 7, 9, 0, 0, 0, loc)},
+	}
+	active, err := service.GetActiveByExam(context.Background(), exams, now)
+	if err != nil {
+		t.Fatalf("GetActiveByExam() error = %v", err)
+	}
+	if len(active) != 2 || len(active[0]) != 2 || active[0][0].ID != 2 || len(active[1]) != 1 || active[1][0].ID != 1 {
+		t.Errorf("GetActiveByExam() = %+v", active)
+	}
+}
+
+func TestIsOfficialTemplateActive(t *testing.T) {
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, loc)
+	exam := &model.ExamDate{ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc)}
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		template model.OfficialTemplate
+		want     bool
+	}{
+		{"已启用且不限条件", model.OfficialTemplate{Active: true}, true},
+		{"未启用", model.OfficialTemplate{}, false},
+		{"尚未开始", model.OfficialTemplate{Active: true, StartAt: &after}, false},
+		{"开始时间当刻", model.OfficialTemplate{Active: true, StartAt: &now}, true},
+		{"已经结束", model.OfficialTemplate{Active: true, EndAt: &before}, false},
+		{"结束时间当刻", model.OfficialTemplate{Active: true, EndAt: &now}, false},
+		{"在时间范围内", model.OfficialTemplate{Active: true, StartAt: &before, EndAt: &after}, true},
+		{"距考试 98 天，限 100 天", model.OfficialTemplate{Active: true, DaysBeforeExam: 100}, true},
+		{"距考试 98 天，限 30 天", model.OfficialTemplate{Active: true, DaysBeforeExam: 30}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOfficialTemplateActive(&tt.template, exam, now); got != tt.want {
+				t.Errorf("IsOfficialTemplateActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 考试开始后不再生效
+	if IsOfficialTemplateActive(&model.OfficialTemplate{Active: true, DaysBeforeExam: 100}, exam, exam.ExamBeginDate) {
+		t.Error("IsOfficialTemplateActive() should be false once the exam has begun")
+	}
+}
+
+func TestValidateOfficialTemplate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	valid := model.OfficialTemplate{TemplateName: "百日冲刺", TemplateContent: "距离{exam}还有{time}", StartAt: &start, EndAt: &end, DaysBeforeExam: 100}
+	if err := ValidateOfficialTemplate(&valid); err != nil {
+		t.Errorf("ValidateOfficialTemplate() error = %v", err)
+	}
+
+	invalid := []model.OfficialTemplate{
+		{TemplateContent: "缺少变量"},
+		{TemplateContent: "距离{exam}还有{time}", StartAt: &end, EndAt: &start},
+		{TemplateContent: "距离{exam}还有{time}", StartAt: &start, EndAt: &start},
+		{TemplateContent: "距离{exam}还有{time}", DaysBeforeExam: -1},
+		{TemplateContent: "距离{exam}还有{time}", DaysBeforeExam: MaxOfficialTemplateDaysBeforeExam + 1},
+	}
+	for i, template := range invalid {
+		if err := ValidateOfficialTemplate(&template); err == nil {
+			t.Errorf("ValidateOfficialTemplate(invalid[%d]) should fail", i)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+// 语录字段长度限制
+const (
+	MaxQuoteContentLength = 200
+	MaxQuoteSourceLength  = 60
+	MaxQuoteTagsLength    = 100
+)
+
+var (
+	// ErrQuoteNotFound 语录不存在
+	ErrQuoteNotFound = errors.New("quote not found")
+	// ErrQuoteImportInvalid 导入文件中存在未通过校验的行，详见 QuoteImportResult.Errors
+	ErrQuoteImportInvalid = errors.New("quote import contains invalid lines")
+)
+
+// QuoteImportError 单行语录的导入错误，Line 为行号（从 1 开始）
+type QuoteImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// QuoteImportResult 语录导入结果
+type QuoteImportResult struct {
+	// Imported 新建的语录数量
+	Imported int `json:"imported"`
+	// Skipped 与已有语录（或文件中靠前的行）内容相同而跳过的数量
+	Skipped int `json:"skipped"`
+	// Errors 未通过校验的行，不为空时不导入任何语录
+	Errors []QuoteImportError `json:"errors"`
+}
+
+// QuoteService 励志语录服务
+type QuoteService struct {
+	repo repository.QuoteRepository
+	// window 同一对话在多少天内不重复出现同一条语录
+	window int
+}
+
+// NewQuoteService 创建励志语录服务，window 为不重复天数，不大于 0 时不限制
+func NewQuoteService(repo repository.QuoteRepository, window int) *QuoteService {
+	return &QuoteService{repo: repo, window: window}
+}
+
+// List 获取语录，按 ID 排列；tag 不为空时只返回带有该标签的语录
+func (s *QuoteService) List(ctx context.Context, tag string) ([]model.Quote, error) {
+	quotes, err := s.repo.GetAll(ctx)
+	if err != nil || tag == "" {
+		return quotes, err
+	}
+
+	filtered := []model.Quote{}
+	for _, quote := range quotes {
+		for _, t := range strings.Split(quote.Tags, ",") {
+			if t == tag {
+				filtered = append(filtered, quote)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// Create 创建语录并分配 ID，调用前应通过 ValidateQuote 校验
+func (s *QuoteService) Create(ctx context.Context, quote *model.Quote) error {
+	id, err := util.GenerateID()
+	if err != nil {
+		return err
+	}
+	quote.ID = id
+	return s.repo.Create(ctx, quote)
+}
+
+// Update 更新语录，语录不存在时返回 ErrQuoteNotFound
+func (s *QuoteService) Update(ctx context.Context, quote *model.Quote) error {
+	existing, err := s.repo.GetByID(ctx, quote.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrQuoteNotFound
+	}
+	quote.CreatedAt = existing.CreatedAt
+	return s.repo.Update(ctx, quote)
+}
+
+// Delete 删除语录
+func (s *QuoteService) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Import 从文本导入语录，全部导入或全部不导入
+// 每行一条，格式为「内容|出处|标签1,标签2」，出处和标签可省略；空行和 # 开头的行忽略。
+// 任一行未通过校验时返回 ErrQuoteImportInvalid 及逐行错误
+func (s *QuoteService) Import(ctx context.Context, text string) (*QuoteImportResult, error) {
+	existing, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, quote := range existing {
+		seen[quote.Content] = true
+	}
+
+	result := &QuoteImportResult{Errors: []QuoteImportError{}}
+	var quotes []*model.Quote
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+
+		quote := ParseQuoteLine(raw)
+		if err := ValidateQuote(quote); err != nil {
+			result.Errors = append(result.Errors, QuoteImportError{Line: line, Error: err.Error()})
+			continue
+		}
+		if seen[quote.Content] {
+			result.Skipped++
+			continue
+		}
+		seen[quote.Content] = true
+
+		id, err := util.GenerateID()
+		if err != nil {
+			return nil, err
+		}
+		quote.ID = id
+		quotes = append(quotes, quote)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result.Errors) > 0 {
+		return result, ErrQuoteImportInvalid
+	}
+
+	if err := s.repo.CreateBatch(ctx, quotes); err != nil {
+		return nil, err
+	}
+	result.Imported = len(quotes)
+	return result, nil
+}
+
+// PickForDay 选取 key（对话或用户 ID）在 now 所在日期（北京时间）的语录，语录库为空时返回 nil
+// 同一天对同一 key 的结果相同；语录库不变时，同一 key 在不重复天数内不会出现相同的语录
+func (s *QuoteService) PickForDay(ctx context.Context, key string, now time.Time) (*model.Quote, error) {
+	quotes, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pickQuote(quotes, key, quoteDay(now), s.window), nil
+}
+
+// DailyQuote 与 PickForDay 相同，但返回格式化后的语录文本，语录库为空时返回空字符串
+func (s *QuoteService) DailyQuote(ctx context.Context, key string, now time.Time) (string, error) {
+	quote, err := s.PickForDay(ctx, key, now)
+	if err != nil || quote == nil {
+		return "", err
+	}
+	return util.FormatQuote(quote), nil
+}
+
+// UserQuoteKey 返回用户选取语录使用的 key
+// 与私聊推送的对话 ID 相同，同一用户在 Inline Query、/d 和私聊推送中看到的今日语录一致
+func UserQuoteKey(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
+
+// pickQuote 按日期轮换选取语录
+// 每个 key 以 n 天（n 为语录数量）为一轮，每轮按 key 和轮次打乱顺序，轮内每条语录出现一次。
+// 为避免相邻两轮的交界处重复，语录按 key 固定分为前后两组，每轮先依次使用前一组再使用后一组：
+// 前一组大小取 window（不超过 n/2），语录数量不少于 2×window 时任意两次出现的间隔都大于 window 天
+func pickQuote(quotes []model.Quote, key string, day int64, window int) *model.Quote {
+	n := int64(len(quotes))
+	if n == 0 {
+		return nil
+	}
+
+	head := int64(window)
+	if head > n/2 {
+		head = n / 2
+	}
+	if head < 0 {
+		head = 0
+	}
+
+	// 分组只与 key 有关，保证各轮的前一组相同
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	quoteRand(key, "group").Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+	cycle, pos := day/n, day%n
+	group := order[:head]
+	if pos >= head {
+		group, pos = order[head:], pos-head
+	}
+
+	shuffled := append([]int(nil), group...)
+	quoteRand(key, strconv.FormatInt(cycle, 10)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return &quotes[shuffled[pos]]
+}
+
+// quoteRand 创建以 key 和 salt 为种子的随机数生成器，相同参数的序列固定
+func quoteRand(key, salt string) *rand.Rand {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key + "#" + salt))
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+// quoteDay 返回 now 所在日期（北京时间）距 1970-01-01 的天数
+func quoteDay(now time.Time) int64 {
+	t := now.In(util.GetBJTLocation())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// ParseQuoteLine 解析「内容|出处|标签1,标签2」格式的一行语录，出处和标签可省略
+func ParseQuoteLine(line string) *model.Quote {
+	parts := strings.SplitN(line, "|", 3)
+	quote := &model.Quote{Content: strings.TrimSpace(parts[0])}
+	if len(parts) > 1 {
+		quote.Source = strings.TrimSpace(parts[1])
+	}
+	if len(parts) > 2 {
+		quote.Tags = NormalizeQuoteTags(parts[2])
+	}
+	return quote
+}
+
+// NormalizeQuoteTags 规范化逗号分隔的标签：支持中文逗号，去除空白和重复的标签
+func NormalizeQuoteTags(tags string) string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(strings.ReplaceAll(tags, "，", ","), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return strings.Join(result, ",")
+}
+
+// ValidateQuote 校验语录的内容、出处和标签长度，返回面向管理员的提示
+func ValidateQuote(quote *model.Quote) error {
+	if quote.Content == "" {
+		return fmt.Errorf("语录内容不能为空")
+	}
+	if charCount := utf8.RuneCountInString(quote.Content); charCount > MaxQuoteContentLength {
+		return fmt.Errorf("语录内容不能超过 %d 字符（当前 %d 字符）", MaxQuoteContentLength, charCount)
+	}
+	if charCount := utf8.RuneCountInString(quote.Source); charCount > MaxQuoteSourceLength {
+		return fmt.Errorf("出处不能超过 %d 字符（当前 %d 字符）", MaxQuoteSourceLength, charCount)
+	}
+	if charCount := utf8.RuneCountInString(quote.Tags); charCount > MaxQuoteTagsLength {
+		return fmt.Errorf("标签不能超过 %d 字符（当前 %d 字符）", MaxQuoteTagsLength, charCount)
+	}
+	return nil
+}
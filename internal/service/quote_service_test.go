@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository/memory"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+func newTestQuotes(n int) []model.Quote {
+	quotes := make([]model.Quote, n)
+	for i := range quotes {
+		quotes[i] = model.Quote{ID: int64(i + 1), Content: "语录" + strconv.Itoa(i+1)}
+	}
+	return quotes
+}
+
+func TestPickQuote_NoRepeatWithinWindow(t *testing.T) {
+	tests := []struct {
+		n      int
+		window int
+		// gap 同一语录两次出现的最小间隔（天）
+		gap int64
+	}{
+		{n: 10, window: 3, gap: 4},
+		{n: 60, window: 30, gap: 31},
+		{n: 7, window: 30, gap: 4},
+		{n: 5, window: 0, gap: 1},
+	}
+	for _, tt := range tests {
+		quotes := newTestQuotes(tt.n)
+		for _, key := range []string{"123", "-100123"} {
+			last := make(map[int64]int64)
+			counts := make(map[int64]int)
+			start := int64(tt.n * 400) // 从一轮的开始计数
+			for day := start; day < start+int64(tt.n*5); day++ {
+				quote := pickQuote(quotes, key, day, tt.window)
+				if prev, ok := last[quote.ID]; ok && day-prev < tt.gap {
+					t.Fatalf("n=%d window=%d key=%s: quote %d repeated after %d days", tt.n, tt.window, key, quote.ID, day-prev)
+				}
+				last[quote.ID] = day
+				counts[quote.ID]++
+			}
+			// 每轮每条语录恰好出现一次
+			for _, quote := range quotes {
+				if counts[quote.ID] != 5 {
+					t.Errorf("n=%d window=%d key=%s: quote %d shown %d times, want 5", tt.n, tt.window, key, quote.ID, counts[quote.ID])
+				}
+			}
+		}
+	}
+
+	if pickQuote(nil, "123", 20000, 30) != nil {
+		t.Error("pickQuote() with empty library should return nil")
+	}
+}
+
+func TestQuoteService_PickForDay(t *testing.T) {
+	repo := memory.NewQuoteRepository()
+	service := NewQuoteService(repo, 30)
+	ctx := context.Background()
+	loc := util.GetBJTLocation()
+
+	if text, err := service.DailyQuote(ctx, "123", time.Now()); err != nil || text != "" {
+		t.Errorf("DailyQuote() with empty library = %q, %v", text, err)
+	}
+
+	for i := 1; i <= 40; i++ {
+		_ = repo.Create(ctx, &model.Quote{ID: int64(i), Content: "语录" + strconv.Itoa(i), Source: "出处"})
+	}
+
+	// 同一天（北京时间）结果相同
+	morning, _ := service.PickForDay(ctx, "123", time.Date(2026, 3, 1, 0, 5, 0, 0, loc))
+	evening, _ := service.PickForDay(ctx, "123", time.Date(2026, 3, 1, 23, 55, 0, 0, loc))
+	if morning == nil || evening == nil || morning.ID != evening.ID {
+		t.Errorf("PickForDay() same day = %+v, %+v", morning, evening)
+	}
+
+	// 不同对话的顺序不同
+	differs := false
+	for day := 1; day <= 10 && !differs; day++ {
+		now := time.Date(2026, 3, day, 9, 0, 0, 0, loc)
+		a, _ := service.PickForDay(ctx, "123", now)
+		b, _ := service.PickForDay(ctx, "-100123", now)
+		differs = a.ID != b.ID
+	}
+	if !differs {
+		t.Error("PickForDay() should differ between chats")
+	}
+
+	text, err := service.DailyQuote(ctx, "123", time.Date(2026, 3, 1, 9, 0, 0, 0, loc))
+	if err != nil || text != util.FormatQuote(morning) {
+		t.Errorf("DailyQuote() = %q, %v, want %q", text, err, util.FormatQuote(morning))
+	}
+}
+
+func TestQuoteService_CRUD(t *testing.T) {
+	_ = util.InitSnowflake(0, 1)
+	service := NewQuoteService(memory.NewQuoteRepository(), 30)
+	ctx := context.Background()
+
+	quote := &model.Quote{Content: "天道酬勤", Tags: "勤奋,坚持"}
+	if err := service.Create(ctx, quote); err != nil || quote.ID == 0 {
+		t.Fatalf("Create() = %+v, %v", quote, err)
+	}
+	_ = service.Create(ctx, &model.Quote{Content: "不积跬步，无以至千里", Tags: "坚持到底"})
+
+	if quotes, _ := service.List(ctx, "坚持"); len(quotes) != 1 || quotes[0].ID != quote.ID {
+		t.Errorf("List(坚持) = %+v", quotes)
+	}
+	if quotes, _ := service.List(ctx, ""); len(quotes) != 2 {
+		t.Errorf("List() = %+v, want 2 quotes", quotes)
+	}
+
+	if err := service.Update(ctx, &model.Quote{ID: 42, Content: "不存在"}); !errors.Is(err, ErrQuoteNotFound) {
+		t.Errorf("Update() missing quote error = %v, want ErrQuoteNotFound", err)
+	}
+	if err := service.Update(ctx, &model.Quote{ID: quote.ID, Content: "天道酬勤", Source: "谚语"}); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+
+	if err := service.Delete(ctx, quote.ID); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if quotes, _ := service.List(ctx, ""); len(quotes) != 1 {
+		t.Errorf("List() after delete = %+v, want 1 quote", quotes)
+	}
+}
+
+func TestQuoteService_Import(t *testing.T) {
+	_ = util.InitSnowflake(0, 1)
+	repo := memory.NewQuoteRepository()
+	service := NewQuoteService(repo, 30)
+	ctx := context.Background()
+	_ = repo.Create(ctx, &model.Quote{ID: 1, Content: "天道酬勤"})
+
+	result, err := service.Import(ctx, "第一行\n|只有出处\n"+strings.Repeat("长", MaxQuoteContentLength+1))
+	if !errors.Is(err, ErrQuoteImportInvalid) || len(result.Errors) != 2 || result.Errors[0].Line != 2 || result.Errors[1].Line != 3 {
+		t.Fatalf("Import() invalid = %+v, %v", result, err)
+	}
+	if quotes, _ := repo.GetAll(ctx); len(quotes) != 1 {
+		t.Errorf("quotes after invalid import = %d, want 1", len(quotes))
+	}
+
+	result, err = service.Import(ctx, "# 注释\n不积跬步，无以至千里|荀子|坚持，积累\n天道酬勤\n不积跬步，无以至千里\n\n")
+	if err != nil || result.Imported != 1 || result.Skipped != 2 {
+		t.Fatalf("Import() = %+v, %v", result, err)
+	}
+	quotes, _ := repo.GetAll(ctx)
+	if len(quotes) != 2 || quotes[1].Source != "荀子" || quotes[1].Tags != "坚持,积累" {
+		t.Errorf("quotes after import = %+v", quotes)
+	}
+}
+
+func TestParseQuoteLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want model.Quote
+	}{
+		{"天道酬勤", model.Quote{Content: "天道酬勤"}},
+		{"不积跬步，无以至千里 | 荀子", model.Quote{Content: "不积跬步，无以至千里", Source: "荀子"}},
+		{"天道酬勤||勤奋， 坚持,勤奋", model.Quote{Content: "天道酬勤", Tags: "勤奋,坚持"}},
+	}
+	for _, tt := range tests {
+		if got := ParseQuoteLine(tt.line); *got != tt.want {
+			t.Errorf("ParseQuoteLine(%q) = %+v, want %+v", tt.line, *got, tt.want)
+		}
+	}
+}
+
+func TestValidateQuote(t *testing.T) {
+	tests := []struct {
+		quote   model.Quote
+		wantErr bool
+	}{
+		{model.Quote{Content: "天道酬勤"}, false},
+		{model.Quote{Content: ""}, true},
+		{model.Quote{Content: strings.Repeat("长", MaxQuoteContentLength)}, false},
+		{model.Quote{Content: strings.Repeat("长", MaxQuoteContentLength+1)}, true},
+		{model.Quote{Content: "天道酬勤", Source: strings.Repeat("长", MaxQuoteSourceLength+1)}, true},
+		{model.Quote{Content: "天道酬勤", Tags: strings.Repeat("长", MaxQuoteTagsLength+1)}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateQuote(&tt.quote); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateQuote(%+v) error = %v, wantErr %v", tt.quote, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTemplatePreviewService_Quote(t *testing.T) {
+	quotes := memory.NewQuoteRepository()
+	_ = quotes.Create(context.Background(), &model.Quote{ID: 1, Content: "天道酬勤"})
+	service := NewTemplatePreviewService(NewExamDateService(memory.NewExamDateRepository())).
+		WithQuotes(NewQuoteService(quotes, 30))
+
+	preview, err := service.PreviewForUser(context.Background(), 123, "", "距离{exam}还有{time}\n{quote}", 0, time.Now())
+	if err != nil || preview == nil {
+		t.Fatalf("PreviewForUser() = %+v, %v", preview, err)
+	}
+	if !preview.Valid || !strings.HasSuffix(preview.States.Current, "\n天道酬勤") {
+		t.Errorf("preview = %+v", preview)
+	}
+}
@@ -1,31 +1,53 @@
 package service
 
 import (
+	"context"
+
 	"github.com/herbertgao/gaokao_bot/internal/model"
 	"github.com/herbertgao/gaokao_bot/internal/repository"
 )
 
 // SendChatService 发送对话服务
 type SendChatService struct {
-	repo *repository.SendChatRepository
+	repo                repository.SendChatRepository
+	officialService     *OfficialTemplateService
+	userTemplateService *UserTemplateService
 }
 
 // NewSendChatService 创建发送对话服务
-func NewSendChatService(repo *repository.SendChatRepository) *SendChatService {
+func NewSendChatService(repo repository.SendChatRepository) *SendChatService {
 	return &SendChatService{repo: repo}
 }
 
 // GetAll 获取所有发送对话
-func (s *SendChatService) GetAll() ([]model.SendChat, error) {
-	return s.repo.GetAll()
+func (s *SendChatService) GetAll(ctx context.Context) ([]model.SendChat, error) {
+	return s.repo.GetAll(ctx)
 }
 
 // Create 创建发送对话
-func (s *SendChatService) Create(chat *model.SendChat) error {
-	return s.repo.Create(chat)
+func (s *SendChatService) Create(ctx context.Context, chat *model.SendChat) error {
+	return s.repo.Create(ctx, chat)
 }
 
 // Delete 删除发送对话
-func (s *SendChatService) Delete(id int64) error {
-	return s.repo.Delete(id)
+func (s *SendChatService) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// GetByID 根据 ID 获取发送对话，不存在时返回 nil
+func (s *SendChatService) GetByID(ctx context.Context, id int64) (*model.SendChat, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// SetTemplate 设置发送对话选用的官方模板，templateID 为 0 时恢复使用默认模板
+// 对话不存在时返回 ErrSendChatNotFound；模板是否存在由调用方检查
+func (s *SendChatService) SetTemplate(ctx context.Context, id, templateID int64) error {
+	chat, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if chat == nil {
+		return ErrSendChatNotFound
+	}
+	return s.repo.UpdateTemplate(ctx, id, templateID)
 }
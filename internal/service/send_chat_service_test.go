@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
@@ -15,7 +17,7 @@ func setupSendChatTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&model.SendChat{}); err != nil {
+	if err := db.AutoMigrate(&model.SendChat{}, &model.SendChatTemplate{}); err != nil {
 		t.Fatalf("Failed to migrate: %v", err)
 	}
 
@@ -37,7 +39,7 @@ func TestSendChatService_Create(t *testing.T) {
 		ChatID: "123456789",
 	}
 
-	err := service.Create(chat)
+	err := service.Create(context.Background(), chat)
 	if err != nil {
 		t.Errorf("Create() error = %v", err)
 	}
@@ -51,7 +53,7 @@ func TestSendChatService_GetAll(t *testing.T) {
 	db.Create(&model.SendChat{ID: 2, ChatID: "222"})
 	db.Create(&model.SendChat{ID: 3, ChatID: "333"})
 
-	result, err := service.GetAll()
+	result, err := service.GetAll(context.Background())
 	if err != nil {
 		t.Errorf("GetAll() error = %v", err)
 	}
@@ -64,7 +66,7 @@ func TestSendChatService_GetAll(t *testing.T) {
 func TestSendChatService_GetAll_Empty(t *testing.T) {
 	service, _ := setupSendChatTestService(t)
 
-	result, err := service.GetAll()
+	result, err := service.GetAll(context.Background())
 	if err != nil {
 		t.Errorf("GetAll() error = %v", err)
 	}
@@ -80,7 +82,7 @@ func TestSendChatService_Delete(t *testing.T) {
 	// 插入测试数据
 	db.Create(&model.SendChat{ID: 1, ChatID: "123"})
 
-	err := service.Delete(1)
+	err := service.Delete(context.Background(), 1)
 	if err != nil {
 		t.Errorf("Delete() error = %v", err)
 	}
@@ -97,8 +99,24 @@ func TestSendChatService_Delete_NotFound(t *testing.T) {
 	service, _ := setupSendChatTestService(t)
 
 	// 删除不存在的记录不应该报错
-	err := service.Delete(999)
+	err := service.Delete(context.Background(), 999)
 	if err != nil {
 		t.Errorf("Delete() error = %v", err)
 	}
 }
+
+func TestSendChatService_SetTemplate(t *testing.T) {
+	service, db := setupSendChatTestService(t)
+	db.Create(&model.SendChat{ID: 1, ChatID: "123"})
+
+	if err := service.SetTemplate(context.Background(), 1, 42); err != nil {
+		t.Fatalf("SetTemplate() error = %v", err)
+	}
+	if chat, _ := service.GetByID(context.Background(), 1); chat == nil || chat.TemplateID != 42 {
+		t.Errorf("GetByID() after SetTemplate = %+v", chat)
+	}
+
+	if err := service.SetTemplate(context.Background(), 999, 42); !errors.Is(err, ErrSendChatNotFound) {
+		t.Errorf("SetTemplate() missing chat error = %v, want ErrSendChatNotFound", err)
+	}
+}
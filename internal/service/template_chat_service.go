@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// TemplateChatTimeout 对话式创建、编辑模板时等待用户输入的时长
+const TemplateChatTimeout = 10 * time.Minute
+
+// templateUsage 模板输入格式说明
+const templateUsage = "格式：【名称】内容，名称可省略；内容需包含 {exam} 和 {time}，如：\n【我的模板】距离{exam}还有{time}"
+
+// templateChatState 等待用户输入的对话状态
+type templateChatState struct {
+	// templateID 待编辑的模板 ID，0 表示新建
+	templateID int64
+	expiresAt  time.Time
+}
+
+// TemplateChatService 私聊中的模板管理（无需 Mini App）
+// 支持 /newtemplate、/templates、/edit_<id>、/rm_<id> 与 /cancel；
+// 命令未附带内容时进入对话，用户的下一条消息作为模板内容
+type TemplateChatService struct {
+	templateService *UserTemplateService
+	shareService    *TemplateShareService
+	previewService  *TemplatePreviewService
+	now             func() time.Time
+
+	mu      sync.Mutex
+	pending map[int64]templateChatState
+}
+
+// NewTemplateChatService 创建私聊模板管理服务
+func NewTemplateChatService(templateService *UserTemplateService) *TemplateChatService {
+	return &TemplateChatService{
+		templateService: templateService,
+		now:             time.Now,
+		pending:         make(map[int64]templateChatState),
+	}
+}
+
+// WithSharing 启用分享链接（/start tpl_<分享码>）与 /copy_<分享码>，previewService 为 nil 时不渲染预览
+func (s *TemplateChatService) WithSharing(shareService *TemplateShareService, previewService *TemplatePreviewService) *TemplateChatService {
+	s.shareService = shareService
+	s.previewService = previewService
+	return s
+}
+
+// Start 处理 /start，payload 为分享链接的参数（tpl_<分享码>）时预览分享的模板
+func (s *TemplateChatService) Start(ctx context.Context, userID int64, payload string) (string, error) {
+	code, ok := strings.CutPrefix(payload, ShareStartPrefix)
+	if !ok || s.shareService == nil {
+		return "欢迎使用高考倒计时 Bot！\n\n" +
+			"/d 查看高考倒计时\n" +
+			"/templates 管理自定义模板\n" +
+			"/calendar 订阅考试日历", nil
+	}
+
+	template, err := s.shareService.GetByCode(ctx, code)
+	if errors.Is(err, ErrShareCodeNotFound) {
+		return "分享链接无效或已失效。", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "收到分享的模板：\n%s\n", formatTemplateInput(template))
+	if s.previewService != nil {
+		preview, err := s.previewService.PreviewForUser(ctx, userID, template.TemplateName, template.TemplateContent, 0, s.now())
+		if err != nil {
+			return "", err
+		}
+		if preview != nil {
+			fmt.Fprintf(&b, "\n预览：\n%s\n", preview.States.Upcoming)
+		}
+	}
+	if template.UserID == userID {
+		b.WriteString("\n这是你分享的模板。")
+	} else {
+		fmt.Fprintf(&b, "\n发送 /%s%s 复制到你的模板。", constant.CopyTemplateCommandPrefix, code)
+	}
+	return b.String(), nil
+}
+
+// CopySharedTemplate 处理 /copy_<分享码>，将分享的模板复制到用户名下
+func (s *TemplateChatService) CopySharedTemplate(ctx context.Context, userID int64, code string) (string, error) {
+	if s.shareService == nil {
+		return "模板分享暂未开放。", nil
+	}
+
+	template, err := s.shareService.Copy(ctx, code, userID)
+	switch {
+	case errors.Is(err, ErrShareCodeNotFound):
+		return "分享链接无效或已失效。", nil
+	case errors.Is(err, ErrCopyOwnTemplate):
+		return "这是你分享的模板，无需复制。", nil
+	case errors.Is(err, repository.ErrTemplateLimitExceeded):
+		return fmt.Sprintf("模板数量已达上限（最多 %d 个），请先删除不用的模板。发送 /templates 查看。", MaxTemplatesPerUser), nil
+	case isModerationError(err), isUndefinedVariableError(err):
+		return err.Error(), nil
+	case err != nil:
+		return "", err
+	}
+	return fmt.Sprintf("已复制模板：%s\n\n发送 /templates 查看全部模板。", formatTemplateInput(template)), nil
+}
+
+// NewTemplate 处理 /newtemplate，input 为命令后的文本，为空时等待用户发送内容
+func (s *TemplateChatService) NewTemplate(ctx context.Context, userID int64, input string) (string, error) {
+	if strings.TrimSpace(input) == "" {
+		s.setPending(userID, 0)
+		return "请发送新模板，" + templateUsage + "\n\n发送 /cancel 取消。", nil
+	}
+
+	s.clearPending(userID)
+	return s.create(ctx, userID, input)
+}
+
+// ListTemplates 处理 /templates，列出用户的模板及编辑、删除链接
+func (s *TemplateChatService) ListTemplates(ctx context.Context, userID int64) (string, error) {
+	templates, err := s.templateService.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(templates) == 0 {
+		return "你还没有自定义模板。\n\n发送 /newtemplate 创建，" + templateUsage, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "你的模板（%d/%d）：\n", len(templates), MaxTemplatesPerUser)
+	for i, template := range templates {
+		name := template.TemplateName
+		if name == "" {
+			name = "未命名"
+		}
+		fmt.Fprintf(&b, "\n%d. %s\n%s\n编辑 /%s%d  删除 /%s%d\n",
+			i+1, name, template.TemplateContent,
+			constant.EditTemplateCommandPrefix, template.ID, constant.DeleteTemplateCommandPrefix, template.ID)
+	}
+	b.WriteString("\n发送 /newtemplate 创建新模板。")
+	return b.String(), nil
+}
+
+// EditTemplate 处理 /edit_<id>，input 为命令后的文本，为空时等待用户发送新内容
+func (s *TemplateChatService) EditTemplate(ctx context.Context, userID, templateID int64, input string) (string, error) {
+	template, err := s.getOwnTemplate(ctx, userID, templateID)
+	if err != nil || template == nil {
+		return "模板不存在，发送 /templates 查看你的模板。", err
+	}
+
+	if strings.TrimSpace(input) == "" {
+		s.setPending(userID, templateID)
+		return fmt.Sprintf("当前内容：\n%s\n\n请发送修改后的模板，%s\n\n发送 /cancel 取消。",
+			formatTemplateInput(template), templateUsage), nil
+	}
+
+	s.clearPending(userID)
+	return s.update(ctx, template, input)
+}
+
+// DeleteTemplate 处理 /rm_<id>
+func (s *TemplateChatService) DeleteTemplate(ctx context.Context, userID, templateID int64) (string, error) {
+	template, err := s.getOwnTemplate(ctx, userID, templateID)
+	if err != nil || template == nil {
+		return "模板不存在，发送 /templates 查看你的模板。", err
+	}
+
+	if err := s.templateService.Delete(ctx, template.ID); err != nil {
+		return "", err
+	}
+	s.clearPending(userID)
+	return fmt.Sprintf("已删除模板：%s", formatTemplateInput(template)), nil
+}
+
+// Cancel 处理 /cancel，结束正在进行的创建或编辑
+func (s *TemplateChatService) Cancel(userID int64) string {
+	if !s.clearPending(userID) {
+		return "当前没有进行中的操作。"
+	}
+	return "已取消。"
+}
+
+// HandleText 处理私聊中的普通消息，存在进行中的创建或编辑时作为模板内容
+// 没有进行中的对话时返回 handled 为 false
+func (s *TemplateChatService) HandleText(ctx context.Context, userID int64, text string) (response string, handled bool, err error) {
+	state, ok := s.getPending(userID)
+	if !ok {
+		return "", false, nil
+	}
+
+	if state.templateID == 0 {
+		response, err = s.create(ctx, userID, text)
+	} else {
+		var template *model.UserTemplate
+		template, err = s.getOwnTemplate(ctx, userID, state.templateID)
+		if err == nil && template == nil {
+			s.clearPending(userID)
+			return "模板不存在，发送 /templates 查看你的模板。", true, nil
+		}
+		if err == nil {
+			response, err = s.update(ctx, template, text)
+		}
+	}
+	return response, true, err
+}
+
+// create 校验输入并创建模板，成功后结束对话；校验失败时保留对话以便重新发送
+func (s *TemplateChatService) create(ctx context.Context, userID int64, input string) (string, error) {
+	name, content := ParseTemplateInput(input)
+	if err := validateTemplateInput(name, content); err != nil {
+		return s.retryMessage(userID, err), nil
+	}
+	if err := s.templateService.CheckVariables(ctx, userID, content); err != nil {
+		if isUndefinedVariableError(err) {
+			return s.retryMessage(userID, err), nil
+		}
+		return "", err
+	}
+	name, content, err := s.templateService.ModerateInput(ctx, userID, 0, name, content)
+	if err != nil {
+		if isModerationError(err) {
+			return s.retryMessage(userID, err), nil
+		}
+		return "", err
+	}
+
+	id, err := util.GenerateID()
+	if err != nil {
+		return "", err
+	}
+
+	template := &model.UserTemplate{
+		ID:              id,
+		UserID:          userID,
+		TemplateName:    name,
+		TemplateContent: content,
+	}
+	if err := s.templateService.CreateWithLimit(ctx, template, MaxTemplatesPerUser); err != nil {
+		if errors.Is(err, repository.ErrTemplateLimitExceeded) {
+			s.clearPending(userID)
+			return fmt.Sprintf("模板数量已达上限（最多 %d 个），请先删除不用的模板。发送 /templates 查看。", MaxTemplatesPerUser), nil
+		}
+		return "", err
+	}
+
+	s.clearPending(userID)
+	return fmt.Sprintf("已创建模板：%s\n\n发送 /templates 查看全部模板。", formatTemplateInput(template)), nil
+}
+
+// update 校验输入并更新模板，成功后结束对话；校验失败时保留对话以便重新发送
+func (s *TemplateChatService) update(ctx context.Context, template *model.UserTemplate, input string) (string, error) {
+	name, content := ParseTemplateInput(input)
+	if err := validateTemplateInput(name, content); err != nil {
+		return s.retryMessage(template.UserID, err), nil
+	}
+	if err := s.templateService.CheckVariables(ctx, template.UserID, content); err != nil {
+		if isUndefinedVariableError(err) {
+			return s.retryMessage(template.UserID, err), nil
+		}
+		return "", err
+	}
+	name, content, err := s.templateService.ModerateInput(ctx, template.UserID, template.ID, name, content)
+	if err != nil {
+		if isModerationError(err) {
+			return s.retryMessage(template.UserID, err), nil
+		}
+		return "", err
+	}
+
+	template.TemplateName = name
+	template.TemplateContent = content
+	if err := s.templateService.Update(ctx, template); err != nil {
+		return "", err
+	}
+
+	s.clearPending(template.UserID)
+	return fmt.Sprintf("已更新模板：%s", formatTemplateInput(template)), nil
+}
+
+// retryMessage 输入无效时的提示，对话进行中时提示可重新发送
+func (s *TemplateChatService) retryMessage(userID int64, err error) string {
+	if _, ok := s.getPending(userID); ok {
+		return fmt.Sprintf("%v\n\n请重新发送，或发送 /cancel 取消。", err)
+	}
+	return fmt.Sprintf("%v\n\n%s", err, templateUsage)
+}
+
+// getOwnTemplate 获取属于该用户的模板，不存在或不属于该用户时返回 nil
+func (s *TemplateChatService) getOwnTemplate(ctx context.Context, userID, templateID int64) (*model.UserTemplate, error) {
+	template, err := s.templateService.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil || template.UserID != userID {
+		return nil, nil
+	}
+	return template, nil
+}
+
+// setPending 记录等待输入的对话
+func (s *TemplateChatService) setPending(userID, templateID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	// 顺带清理已过期的对话，避免长期占用内存
+	for id, state := range s.pending {
+		if !now.Before(state.expiresAt) {
+			delete(s.pending, id)
+		}
+	}
+	s.pending[userID] = templateChatState{templateID: templateID, expiresAt: now.Add(TemplateChatTimeout)}
+}
+
+// getPending 获取未过期的对话
+func (s *TemplateChatService) getPending(userID int64) (templateChatState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.pending[userID]
+	if !ok {
+		return templateChatState{}, false
+	}
+	if !s.now().Before(state.expiresAt) {
+		delete(s.pending, userID)
+		return templateChatState{}, false
+	}
+	return state, true
+}
+
+// clearPending 结束对话，返回是否存在未过期的对话
+func (s *TemplateChatService) clearPending(userID int64) bool {
+	_, ok := s.getPending(userID)
+
+	s.mu.Lock()
+	delete(s.pending, userID)
+	s.mu.Unlock()
+
+	return ok
+}
+
+// ParseTemplateInput 解析「【名称】内容」格式的模板输入，名称可省略
+func ParseTemplateInput(input string) (name, content string) {
+	name = strings.TrimSpace(util.ExtractTemplateName(input))
+	content = input
+	if name != "" {
+		content = strings.Replace(content, "【"+util.ExtractTemplateName(input)+"】", "", 1)
+	}
+	return name, strings.TrimSpace(content)
+}
+
+// validateTemplateInput 使用与 Mini App API 相同的规则校验模板
+func validateTemplateInput(name, content string) error {
+	if err := ValidateTemplateContent(content); err != nil {
+		return err
+	}
+	if name != "" {
+		return ValidateTemplateName(name)
+	}
+	return nil
+}
+
+// formatTemplateInput 将模板格式化为「【名称】内容」
+func formatTemplateInput(template *model.UserTemplate) string {
+	if template.TemplateName == "" {
+		return template.TemplateContent
+	}
+	return "【" + template.TemplateName + "】" + template.TemplateContent
+}
@@ -0,0 +1,441 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func setupTemplateChatTestService(t *testing.T) (*TemplateChatService, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	templateService := NewUserTemplateService(repository.NewUserTemplateRepository(db))
+	return NewTemplateChatService(templateService), db
+}
+
+func countUserTemplates(t *testing.T, db *gorm.DB, userID int64) int64 {
+	var count int64
+	if err := db.Model(&model.UserTemplate{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		t.Fatalf("count templates: %v", err)
+	}
+	return count
+}
+
+func TestParseTemplateInput(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantName    string
+		wantContent string
+	}{
+		{"【我的模板】距离{exam}还有{time}", "我的模板", "距离{exam}还有{time}"},
+		{"  距离{exam}还有{time}  ", "", "距离{exam}还有{time}"},
+		{"距离{exam}【加油】还有{time}", "加油", "距离{exam}还有{time}"},
+		{"【 空格 】\n{exam} {time}", "空格", "{exam} {time}"},
+	}
+
+	for _, tt := range tests {
+		name, content := ParseTemplateInput(tt.input)
+		if name != tt.wantName || content != tt.wantContent {
+			t.Errorf("ParseTemplateInput(%q) = %q, %q, want %q, %q", tt.input, name, content, tt.wantName, tt.wantContent)
+		}
+	}
+}
+
+func TestTemplateChatService_NewTemplateWithInput(t *testing.T) {
+	service, db := setupTemplateChatTestService(t)
+	ctx := context.Background()
+
+	text, err := service.NewTemplate(ctx, 123, "【我的模板】距离{exam}还有{time}")
+	if err != nil {
+		t.Fatalf("NewTemplate() error = %v", err)
+	}
+	if !strings.Contains(text, "已创建模板：【我的模板】距离{exam}还有{time}") {
+		t.Errorf("unexpected response: %s", text)
+	}
+
+	var template model.UserTemplate
+	if err := db.Where("user_id = ?", 123).First(&template).Error; err != nil {
+		t.Fatalf("template not created: %v", err)
+	}
+	if template.TemplateName != "我的模板" || template.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("created template = %+v", template)
+	}
+}
+
+func TestTemplateChatService_NewTemplateInvalid(t *testing.T) {
+	service, db := setupTemplateChatTestService(t)
+
+	text, err := service.NewTemplate(context.Background(), 123, "没有占位符")
+	if err != nil {
+		t.Fatalf("NewTemplate() error = %v", err)
+	}
+	if !strings.Contains(text, "{exam}") {
+		t.Errorf("expected validation message, got %s", text)
+	}
+	if count := countUserTemplates(t, db, 123); count != 0 {
+		t.Errorf("expected no template, got %d", count)
+	}
+	if _, handled, _ := service.HandleText(context.Background(), 123, "{exam}{time}"); handled {
+		t.Error("invalid inline input should not start a conversation")
+	}
+}
+
+func TestTemplateChatService_NewTemplateConversation(t *testing.T) {
+	service, db := setupTemplateChatTestService(t)
+	ctx := context.Background()
+
+	if _, handled, _ := service.HandleText(ctx, 123, "你好"); handled {
+		t.Fatal("HandleText() handled message without conversation")
+	}
+
+	text, err := service.NewTemplate(ctx, 123, "")
+	if err != nil || !strings.Contains(text, "请发送新模板") {
+		t.Fatalf("NewTemplate() = %q, %v", text, err)
+	}
+
+	// 校验失败时保留对话
+	text, handled, err := service.HandleText(ctx, 123, "没有占位符")
+	if err != nil || !handled || !strings.Contains(text, "请重新发送") {
+		t.Fatalf("HandleText() = %q, %v, %v", text, handled, err)
+	}
+
+	text, handled, err = service.HandleText(ctx, 123, "距离{exam}还有{time}")
+	if err != nil || !handled || !strings.Contains(text, "已创建模板") {
+		t.Fatalf("HandleText() = %q, %v, %v", text, handled, err)
+	}
+	if count := countUserTemplates(t, db, 123); count != 1 {
+		t.Errorf("expected 1 template, got %d", count)
+	}
+
+	// 创建成功后对话结束
+	if _, handled, _ := service.HandleText(ctx, 123, "距离{exam}还有{time}"); handled {
+		t.Error("conversation should end after template created")
+	}
+}
+
+func TestTemplateChatService_ConversationExpires(t *testing.T) {
+	service, _ := setupTemplateChatTestService(t)
+	ctx := context.Background()
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	if _, err := service.NewTemplate(ctx, 123, ""); err != nil {
+		t.Fatalf("NewTemplate() error = %v", err)
+	}
+
+	now = now.Add(TemplateChatTimeout)
+	if _, handled, _ := service.HandleText(ctx, 123, "距离{exam}还有{time}"); handled {
+		t.Error("expired conversation should not handle message")
+	}
+}
+
+func TestTemplateChatService_Cancel(t *testing.T) {
+	service, _ := setupTemplateChatTestService(t)
+	ctx := context.Background()
+
+	if text := service.Cancel(123); text != "当前没有进行中的操作。" {
+		t.Errorf("Cancel() = %q", text)
+	}
+
+	if _, err := service.NewTemplate(ctx, 123, ""); err != nil {
+		t.Fatalf("NewTemplate() error = %v", err)
+	}
+	if text := service.Cancel(123); text != "已取消。" {
+		t.Errorf("Cancel() = %q", text)
+	}
+	if _, handled, _ := service.HandleText(ctx, 123, "距离{exam}还有{time}"); handled {
+		t.Error("cancelled conversation should not handle message")
+	}
+}
+
+func TestTemplateChatService_Limit(t *testing.T) {
+	service, db := setupTemplateChatTestService(t)
+
+	for i := 1; i <= MaxTemplatesPerUser; i++ {
+		db.Create(&model.UserTemplate{ID: int64(i), UserID: 123, TemplateContent: "{exam}{time}"})
+	}
+
+	text, err := service.NewTemplate(context.Background(), 123, "距离{exam}还有{time}")
+	if err != nil {
+		t.Fatalf("NewTemplate() error = %v", err)
+	}
+	if !strings.Contains(text, fmt.Sprintf("最多 %d 个", MaxTemplatesPerUser)) {
+		t.Errorf("expected limit message, got %s", text)
+	}
+	if count := countUserTemplates(t, db, 123); count != MaxTemplatesPerUser {
+		t.Errorf("expected %d templates, got %d", MaxTemplatesPerUser, count)
+	}
+}
+
+func TestTemplateChatService_ListTemplates(t *testing.T) {
+	service, db := setupTemplateChatTestService(t)
+	ctx := context.Background()
+
+	text, err := service.ListTemplates(ctx, 123)
+	if err != nil || !strings.Contains(text, "/newtemplate") {
+		t.Fatalf("ListTemplates() = %q, %v", text, err)
+	}
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板一", TemplateContent: "距离{exam}还有{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: 123, TemplateContent: "{exam}：{time}"})
+	db.Create(&model.UserTemplate{ID: 3, UserID: 456, TemplateName: "别人的", TemplateContent: "{exam}{time}"})
+
+	text, err = service.ListTemplates(ctx, 123)
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	for _, want := range []string{
+		fmt.Sprintf("你的模板（2/%d）", MaxTemplatesPerUser),
+		"模板一", "未命名", "/edit_1", "/rm_1", "/edit_2", "/rm_2",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("ListTemplates() missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "别人的") || strings.Contains(text, "/rm_3") {
+		t.Errorf("ListTemplates() shows other user's template:\n%s", text)
+	}
+}
+
+func TestTemplateChatService_EditTemplate(t *testing.T) {
+	service, db := setupTemplateChatTestService(t)
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateName: "旧名称", TemplateContent: "距离{exam}还有{time}"})
+
+	text, err := service.EditTemplate(ctx, 123, 1, "【新名称】{exam}只剩{time}")
+	if err != nil || !strings.Contains(text, "已更新模板：【新名称】{exam}只剩{time}") {
+		t.Fatalf("EditTemplate() = %q, %v", text, err)
+	}
+
+	// 不带内容时进入对话
+	text, err = service.EditTemplate(ctx, 123, 1, "")
+	if err != nil || !strings.Contains(text, "当前内容：\n【新名称】{exam}只剩{time}") {
+		t.Fatalf("EditTemplate() = %q, %v", text, err)
+	}
+	text, handled, err := service.HandleText(ctx, 123, "{exam}倒计时{time}")
+	if err != nil || !handled || !strings.Contains(text, "已更新模板") {
+		t.Fatalf("HandleText() = %q, %v, %v", text, handled, err)
+	}
+
+	var template model.UserTemplate
+	db.First(&template, 1)
+	if template.TemplateName != "" || template.TemplateContent != "{exam}倒计时{time}" {
+		t.Errorf("updated template = %+v", template)
+	}
+}
+
+func TestTemplateChatService_OtherUsersTemplate(t *testing.T) {
+	service, db := setupTemplateChatTestService(t)
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 456, TemplateContent: "距离{exam}还有{time}"})
+
+	for _, call := range []func() (string, error){
+		func() (string, error) { return service.EditTemplate(ctx, 123, 1, "{exam}{time}") },
+		func() (string, error) { return service.DeleteTemplate(ctx, 123, 1) },
+		func() (string, error) { return service.DeleteTemplate(ctx, 123, 999) },
+	} {
+		text, err := call()
+		if err != nil || !strings.Contains(text, "模板不存在") {
+			t.Errorf("got %q, %v, want not found", text, err)
+		}
+	}
+
+	var template model.UserTemplate
+	db.First(&template, 1)
+	if template.UserID != 456 || template.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("other user's template modified: %+v", template)
+	}
+}
+
+func TestTemplateChatService_DeleteTemplate(t *testing.T) {
+	service, db := setupTemplateChatTestService(t)
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板一", TemplateContent: "距离{exam}还有{time}"})
+
+	text, err := service.DeleteTemplate(context.Background(), 123, 1)
+	if err != nil || text != "已删除模板：【模板一】距离{exam}还有{time}" {
+		t.Fatalf("DeleteTemplate() = %q, %v", text, err)
+	}
+	if count := countUserTemplates(t, db, 123); count != 0 {
+		t.Errorf("expected template deleted, got %d", count)
+	}
+}
+
+func TestGetTemplateChatMessage(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	chatService, db := setupTemplateChatTestService(t)
+	db.Create(&model.UserTemplate{ID: 7, UserID: 123, TemplateContent: "距离{exam}还有{time}"})
+	service := NewBotService(nil, nil, nil, nil, chatService, logger, "")
+	ctx := context.Background()
+	privateMsg := &telego.Message{Chat: telego.Chat{ID: 123, Type: telego.ChatTypePrivate}}
+
+	tests := []struct {
+		cmd   string
+		input string
+		want  string
+	}{
+		{"templates", "", "/rm_7"},
+		{"edit_7", "", "当前内容"},
+		{"cancel", "", "已取消。"},
+		{"newtemplate", "{exam}：{time}", "已创建模板"},
+		{"rm_7", "", "已删除模板"},
+		{"rm_abc", "", "模板不存在"},
+		{"start", "", "欢迎"},
+		{"copy_zzzzzzzz", "", "模板分享暂未开放。"},
+	}
+	for _, tt := range tests {
+		text, err := service.getTemplateChatMessage(ctx, privateMsg, tt.cmd, tt.input)
+		if err != nil || !strings.Contains(text, tt.want) {
+			t.Errorf("getTemplateChatMessage(%q) = %q, %v, want contains %q", tt.cmd, text, err, tt.want)
+		}
+	}
+
+	disabled := NewBotService(nil, nil, nil, nil, nil, logger, "")
+	text, err := disabled.getTemplateChatMessage(ctx, privateMsg, "templates", "")
+	if err != nil || text != "模板管理暂未开放。" {
+		t.Errorf("getTemplateChatMessage() = %q, %v", text, err)
+	}
+}
+
+func TestIsTemplateChatCommand(t *testing.T) {
+	for cmd, want := range map[string]bool{
+		"newtemplate": true,
+		"templates":   true,
+		"cancel":      true,
+		"rm_123":      true,
+		"edit_123":    true,
+		"start":       true,
+		"copy_abc":    true,
+		"template":    false,
+		"countdown":   false,
+	} {
+		if got := isTemplateChatCommand(cmd); got != want {
+			t.Errorf("isTemplateChatCommand(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
+
+func TestTemplateChatService_StartAndCopy(t *testing.T) {
+	chatService, db := setupTemplateChatTestService(t)
+	shareService := NewTemplateShareService(chatService.templateService, "gaokao_bot")
+	chatService.WithSharing(shareService, nil)
+	ctx := context.Background()
+
+	template := &model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板一", TemplateContent: "距离{exam}还有{time}"}
+	db.Create(template)
+	code, err := shareService.Share(ctx, template)
+	if err != nil {
+		t.Fatalf("Share() error = %v", err)
+	}
+
+	text, err := chatService.Start(ctx, 456, "")
+	if err != nil || !strings.Contains(text, "欢迎") {
+		t.Errorf("Start() = %q, %v, want welcome", text, err)
+	}
+
+	text, err = chatService.Start(ctx, 456, "tpl_"+code)
+	if err != nil || !strings.Contains(text, "【模板一】距离{exam}还有{time}") || !strings.Contains(text, "/copy_"+code) {
+		t.Errorf("Start() = %q, %v, want preview with copy link", text, err)
+	}
+
+	text, _ = chatService.Start(ctx, 123, "tpl_"+code)
+	if strings.Contains(text, "/copy_") {
+		t.Errorf("Start() for owner = %q, want no copy link", text)
+	}
+
+	text, _ = chatService.Start(ctx, 456, "tpl_zzzzzzzz")
+	if text != "分享链接无效或已失效。" {
+		t.Errorf("Start() with unknown code = %q", text)
+	}
+
+	text, err = chatService.CopySharedTemplate(ctx, 456, code)
+	if err != nil || !strings.Contains(text, "已复制模板") {
+		t.Fatalf("CopySharedTemplate() = %q, %v", text, err)
+	}
+	if count := countUserTemplates(t, db, 456); count != 1 {
+		t.Errorf("expected 1 copied template, got %d", count)
+	}
+
+	text, _ = chatService.CopySharedTemplate(ctx, 123, code)
+	if !strings.Contains(text, "无需复制") {
+		t.Errorf("CopySharedTemplate() own template = %q", text)
+	}
+}
+
+func TestTemplateChatService_StartWithPreview(t *testing.T) {
+	chatService, db := setupTemplateChatTestService(t)
+	if err := db.AutoMigrate(&model.ExamDate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	shareService := NewTemplateShareService(chatService.templateService, "gaokao_bot")
+	previewService := NewTemplatePreviewService(NewExamDateService(repository.NewExamDateRepository(db)))
+	chatService.WithSharing(shareService, previewService)
+	ctx := context.Background()
+
+	template := &model.UserTemplate{ID: 1, UserID: 123, TemplateContent: "还有{time}到{exam}"}
+	db.Create(template)
+	code, _ := shareService.Share(ctx, template)
+
+	// 没有考试数据时使用示例考试渲染
+	text, err := chatService.Start(ctx, 456, "tpl_"+code)
+	if err != nil || !strings.Contains(text, "预览：\n还有") || !strings.Contains(text, "普通高等学校招生全国统一考试") {
+		t.Errorf("Start() = %q, %v, want rendered preview", text, err)
+	}
+}
+
+func TestTemplateChatService_Moderation(t *testing.T) {
+	templateService, _, db := setupModerationTestService(t)
+	service := NewTemplateChatService(templateService)
+	ctx := context.Background()
+
+	// 未通过审核时保留对话，可重新发送
+	text, err := service.NewTemplate(ctx, 123, "")
+	if err != nil {
+		t.Fatalf("NewTemplate() error = %v", err)
+	}
+	text, handled, err := service.HandleText(ctx, 123, "傻瓜 距离{exam}还有{time}")
+	if err != nil || !handled {
+		t.Fatalf("HandleText() = %q, %v, %v", text, handled, err)
+	}
+	if !strings.Contains(text, "未通过审核") || !strings.Contains(text, "请重新发送") {
+		t.Errorf("unexpected response: %s", text)
+	}
+	if count := countUserTemplates(t, db, 123); count != 0 {
+		t.Errorf("expected no template, got %d", count)
+	}
+
+	if _, _, err := service.HandleText(ctx, 123, "距离{exam}还有{time}"); err != nil {
+		t.Fatalf("HandleText() error = %v", err)
+	}
+	if count := countUserTemplates(t, db, 123); count != 1 {
+		t.Errorf("expected 1 template, got %d", count)
+	}
+
+	// 修改模板同样经过审核
+	var template model.UserTemplate
+	db.Where("user_id = ?", 123).First(&template)
+	text, err = service.EditTemplate(ctx, 123, template.ID, "见 https://evil.com {exam}{time}")
+	if err != nil || !strings.Contains(text, "不允许包含链接") {
+		t.Errorf("EditTemplate() = %q, %v", text, err)
+	}
+	db.First(&template, template.ID)
+	if template.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("template should not change, got %+v", template)
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/moderation"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+// maxModerationViolationsLength 审核记录中违规项的最大长度（字符数），与数据库字段一致
+const maxModerationViolationsLength = 1024
+
+var (
+	// ErrModerationRecordNotFound 审核记录不存在
+	ErrModerationRecordNotFound = errors.New("moderation record not found")
+	// ErrModerationRecordReviewed 审核记录已复核，不能重复处理
+	ErrModerationRecordReviewed = errors.New("moderation record already reviewed")
+	// ErrModerationTemplateGone 审核记录对应的模板已被删除，无法应用修改
+	ErrModerationTemplateGone = errors.New("moderated template no longer exists")
+)
+
+// ModerationError 模板内容未通过审核
+type ModerationError struct {
+	// Subject 未通过审核的内容，为空时为「模板内容」
+	Subject string
+	// Reasons 面向用户的原因，不包含命中的具体内容
+	Reasons []string
+}
+
+// Error 返回面向用户的提示
+func (e *ModerationError) Error() string {
+	subject := e.Subject
+	if subject == "" {
+		subject = "模板内容"
+	}
+	return fmt.Sprintf("%s未通过审核：%s", subject, strings.Join(e.Reasons, "；"))
+}
+
+// WithModeration 启用内容审核：创建和修改模板前由 pipeline 清理并检查名称和内容，
+// 未通过的提交保存到 records 供管理员复核
+func (s *UserTemplateService) WithModeration(pipeline *moderation.Pipeline, records repository.ModerationRecordRepository) *UserTemplateService {
+	s.moderation = pipeline
+	s.moderationRecords = records
+	return s
+}
+
+// ModerateInput 审核用户提交的模板名称和内容，应在格式校验通过后调用
+// 返回清理不可见字符后的名称和内容；未通过时记录到复核列表并返回 *ModerationError。
+// templateID 为修改的模板 ID，创建模板时为 0；未启用内容审核时原样返回
+func (s *UserTemplateService) ModerateInput(ctx context.Context, userID, templateID int64, name, content string) (string, string, error) {
+	name, content, violations := s.moderate(name, content)
+	if len(violations) == 0 {
+		return name, content, nil
+	}
+
+	if err := s.recordModeration(ctx, userID, templateID, name, content, violations); err != nil {
+		return "", "", err
+	}
+	return "", "", newModerationError(violations)
+}
+
+// CheckModeration 与 ModerateInput 相同，但未通过时不记录到复核列表
+// 用于整体提交的场景（如批量导入）：任一条目未通过时整体拒绝，复核通过单个条目没有意义
+func (s *UserTemplateService) CheckModeration(name, content string) (string, string, error) {
+	name, content, violations := s.moderate(name, content)
+	if len(violations) > 0 {
+		return "", "", newModerationError(violations)
+	}
+	return name, content, nil
+}
+
+// moderate 清理并检查名称和内容，返回清理后的名称和内容以及命中的违规项；未启用内容审核时原样返回
+func (s *UserTemplateService) moderate(name, content string) (string, string, []moderation.Violation) {
+	if s.moderation == nil {
+		return name, content, nil
+	}
+
+	nameResult := s.moderation.Moderate(name)
+	contentResult := s.moderation.Moderate(content)
+	violations := append(nameResult.Violations, contentResult.Violations...)
+	return nameResult.Text, contentResult.Text, violations
+}
+
+// newModerationError 由违规项生成面向用户的错误，相同原因只保留一条
+func newModerationError(violations []moderation.Violation) *ModerationError {
+	var reasons []string
+	seen := make(map[string]bool, len(violations))
+	for _, v := range violations {
+		if !seen[v.Reason] {
+			seen[v.Reason] = true
+			reasons = append(reasons, v.Reason)
+		}
+	}
+	return &ModerationError{Reasons: reasons}
+}
+
+// recordModeration 保存未通过审核的提交
+func (s *UserTemplateService) recordModeration(ctx context.Context, userID, templateID int64, name, content string, violations []moderation.Violation) error {
+	id, err := util.GenerateID()
+	if err != nil {
+		return err
+	}
+
+	action := model.ModerationActionCreate
+	if templateID != 0 {
+		action = model.ModerationActionUpdate
+	}
+
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.Rule + ": " + v.Detail
+	}
+	details := strings.Join(lines, "\n")
+	if utf8.RuneCountInString(details) > maxModerationViolationsLength {
+		details = string([]rune(details)[:maxModerationViolationsLength])
+	}
+
+	return s.moderationRecords.Create(ctx, &model.ModerationRecord{
+		ID:              id,
+		UserID:          userID,
+		TemplateID:      templateID,
+		Action:          action,
+		TemplateName:    name,
+		TemplateContent: content,
+		Violations:      details,
+		Status:          model.ModerationStatusPending,
+	})
+}
+
+// ModerationPage 审核记录分页结果
+type ModerationPage struct {
+	Items    []model.ModerationRecord `json:"items"`
+	Total    int64                    `json:"total"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"page_size"`
+}
+
+// TemplateModerationService 模板内容审核复核服务
+// 管理员复核未通过审核的提交：确认为误判时按用户提交的内容创建或修改模板，否则驳回
+type TemplateModerationService struct {
+	repo            repository.ModerationRecordRepository
+	templateService *UserTemplateService
+	now             func() time.Time
+}
+
+// NewTemplateModerationService 创建模板内容审核复核服务
+func NewTemplateModerationService(repo repository.ModerationRecordRepository, templateService *UserTemplateService) *TemplateModerationService {
+	return &TemplateModerationService{
+		repo:            repo,
+		templateService: templateService,
+		now:             time.Now,
+	}
+}
+
+// ListByStatus 按状态分页查询审核记录，最新提交的在前
+func (s *TemplateModerationService) ListByStatus(ctx context.Context, status string, page, pageSize int) (*ModerationPage, error) {
+	total, err := s.repo.Count(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.Find(ctx, status, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = []model.ModerationRecord{}
+	}
+
+	return &ModerationPage{
+		Items:    records,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// Approve 复核通过：按记录中的内容为用户创建模板或修改原模板，不再经过内容审核
+// 创建时受 MaxTemplatesPerUser 限制，超出时返回 repository.ErrTemplateLimitExceeded；
+// 原模板已删除时返回 ErrModerationTemplateGone，记录保持待复核状态
+func (s *TemplateModerationService) Approve(ctx context.Context, id int64) (*model.ModerationRecord, error) {
+	record, err := s.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, record); err != nil {
+		return nil, err
+	}
+	return s.setStatus(ctx, record, model.ModerationStatusApproved)
+}
+
+// Dismiss 驳回提交，不修改用户的模板
+func (s *TemplateModerationService) Dismiss(ctx context.Context, id int64) (*model.ModerationRecord, error) {
+	record, err := s.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.setStatus(ctx, record, model.ModerationStatusDismissed)
+}
+
+// apply 按记录中的内容创建或修改模板，用户在提交后删除了引用的变量时返回 *UndefinedVariableError
+func (s *TemplateModerationService) apply(ctx context.Context, record *model.ModerationRecord) error {
+	if err := s.templateService.CheckVariables(ctx, record.UserID, record.TemplateContent); err != nil {
+		return err
+	}
+
+	if record.TemplateID == 0 {
+		id, err := util.GenerateID()
+		if err != nil {
+			return err
+		}
+		return s.templateService.CreateWithLimit(ctx, &model.UserTemplate{
+			ID:              id,
+			UserID:          record.UserID,
+			TemplateName:    record.TemplateName,
+			TemplateContent: record.TemplateContent,
+		}, MaxTemplatesPerUser)
+	}
+
+	template, err := s.templateService.GetByID(ctx, record.TemplateID)
+	if err != nil {
+		return err
+	}
+	if template == nil || template.UserID != record.UserID {
+		return ErrModerationTemplateGone
+	}
+
+	template.TemplateName = record.TemplateName
+	template.TemplateContent = record.TemplateContent
+	return s.templateService.Update(ctx, template)
+}
+
+// getPending 获取待复核的记录
+func (s *TemplateModerationService) getPending(ctx context.Context, id int64) (*model.ModerationRecord, error) {
+	record, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrModerationRecordNotFound
+	}
+	if record.Status != model.ModerationStatusPending {
+		return nil, ErrModerationRecordReviewed
+	}
+	return record, nil
+}
+
+// setStatus 更新记录状态并返回更新后的记录
+func (s *TemplateModerationService) setStatus(ctx context.Context, record *model.ModerationRecord, status string) (*model.ModerationRecord, error) {
+	reviewedAt := s.now()
+	if err := s.repo.UpdateStatus(ctx, record.ID, status, reviewedAt); err != nil {
+		return nil, err
+	}
+	record.Status = status
+	record.ReviewedAt = &reviewedAt
+	return record, nil
+}
+
+// isModerationError 是否为内容审核未通过的错误
+func isModerationError(err error) bool {
+	var moderationErr *ModerationError
+	return errors.As(err, &moderationErr)
+}
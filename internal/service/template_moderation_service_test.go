@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/moderation"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupModerationTestService(t *testing.T) (*UserTemplateService, *TemplateModerationService, *gorm.DB) {
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.ModerationRecord{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	records := repository.NewModerationRecordRepository(db)
+	pipeline := moderation.New(moderation.Options{BannedWords: []string{"傻瓜|shagua"}})
+	templateService := NewUserTemplateService(repository.NewUserTemplateRepository(db)).
+		WithModeration(pipeline, records)
+	return templateService, NewTemplateModerationService(records, templateService), db
+}
+
+func TestUserTemplateService_ModerateInput(t *testing.T) {
+	service, moderationService, _ := setupModerationTestService(t)
+	ctx := context.Background()
+
+	// 通过时返回清理不可见字符后的内容
+	name, content, err := service.ModerateInput(ctx, 123, 0, "简\u200b洁", "距离{exam}\u202e还有{time}")
+	if err != nil {
+		t.Fatalf("ModerateInput() error = %v", err)
+	}
+	if name != "简洁" || content != "距离{exam}还有{time}" {
+		t.Errorf("ModerateInput() = %q, %q", name, content)
+	}
+
+	// 未通过时返回面向用户的原因，并记录命中内容供管理员复核
+	_, _, err = service.ModerateInput(ctx, 123, 42, "", "sha gua 访问 https://evil.com 距离{exam}还有{time}")
+	var moderationErr *ModerationError
+	if !errors.As(err, &moderationErr) || len(moderationErr.Reasons) != 2 {
+		t.Fatalf("ModerateInput() error = %v, want ModerationError with 2 reasons", err)
+	}
+	if strings.Contains(err.Error(), "傻瓜") || strings.Contains(err.Error(), "evil.com") {
+		t.Errorf("ModerationError should not expose matched content: %v", err)
+	}
+
+	page, err := moderationService.ListByStatus(ctx, model.ModerationStatusPending, 1, 10)
+	if err != nil || page.Total != 1 {
+		t.Fatalf("ListByStatus() = %+v, %v", page, err)
+	}
+	record := page.Items[0]
+	if record.UserID != 123 || record.TemplateID != 42 || record.Action != model.ModerationActionUpdate {
+		t.Errorf("record = %+v", record)
+	}
+	if !strings.Contains(record.Violations, "banned_word: 傻瓜") || !strings.Contains(record.Violations, "url: https://evil.com") {
+		t.Errorf("record.Violations = %q", record.Violations)
+	}
+}
+
+func TestUserTemplateService_ModerateInputDisabled(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	name, content, err := service.ModerateInput(context.Background(), 123, 0, "名称", "访问 https://evil.com {exam}{time}")
+	if err != nil || name != "名称" || content != "访问 https://evil.com {exam}{time}" {
+		t.Errorf("ModerateInput() = %q, %q, %v, want input unchanged", name, content, err)
+	}
+}
+
+func TestTemplateModerationService_ApproveCreate(t *testing.T) {
+	service, moderationService, _ := setupModerationTestService(t)
+	ctx := context.Background()
+
+	_, _, _ = service.ModerateInput(ctx, 123, 0, "误判", "shagua 也是 {exam} 还有 {time}")
+	page, _ := moderationService.ListByStatus(ctx, model.ModerationStatusPending, 1, 10)
+	if len(page.Items) != 1 {
+		t.Fatalf("ListByStatus() = %+v", page)
+	}
+
+	record, err := moderationService.Approve(ctx, page.Items[0].ID)
+	if err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if record.Status != model.ModerationStatusApproved || record.ReviewedAt == nil {
+		t.Errorf("Approve() = %+v", record)
+	}
+
+	templates, _ := service.GetByUserID(ctx, 123)
+	if len(templates) != 1 || templates[0].TemplateName != "误判" || templates[0].TemplateContent != "shagua 也是 {exam} 还有 {time}" {
+		t.Errorf("templates after approve = %+v", templates)
+	}
+
+	// 已复核的记录不能重复处理
+	if _, err := moderationService.Approve(ctx, record.ID); !errors.Is(err, ErrModerationRecordReviewed) {
+		t.Errorf("Approve() again error = %v, want ErrModerationRecordReviewed", err)
+	}
+	if _, err := moderationService.Dismiss(ctx, 999); !errors.Is(err, ErrModerationRecordNotFound) {
+		t.Errorf("Dismiss() missing error = %v, want ErrModerationRecordNotFound", err)
+	}
+}
+
+func TestTemplateModerationService_ApproveUpdate(t *testing.T) {
+	service, moderationService, db := setupModerationTestService(t)
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateContent: "距离{exam}还有{time}"})
+	_, _, _ = service.ModerateInput(ctx, 123, 1, "", "傻瓜 {exam} {time}")
+	_, _, _ = service.ModerateInput(ctx, 123, 2, "", "傻瓜 {exam} {time}")
+	page, _ := moderationService.ListByStatus(ctx, model.ModerationStatusPending, 1, 10)
+	if len(page.Items) != 2 {
+		t.Fatalf("ListByStatus() = %+v", page)
+	}
+
+	// 最新的在前：模板 2 不存在，无法应用修改，记录保持待复核
+	if _, err := moderationService.Approve(ctx, page.Items[0].ID); !errors.Is(err, ErrModerationTemplateGone) {
+		t.Errorf("Approve() gone error = %v, want ErrModerationTemplateGone", err)
+	}
+	if record, _ := moderationService.Dismiss(ctx, page.Items[0].ID); record == nil || record.Status != model.ModerationStatusDismissed {
+		t.Errorf("Dismiss() = %+v", record)
+	}
+
+	if _, err := moderationService.Approve(ctx, page.Items[1].ID); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	template, _ := service.GetByID(ctx, 1)
+	if template.TemplateContent != "傻瓜 {exam} {time}" {
+		t.Errorf("template after approve = %+v", template)
+	}
+
+	if page, _ := moderationService.ListByStatus(ctx, model.ModerationStatusPending, 1, 10); page.Total != 0 || page.Items == nil {
+		t.Errorf("ListByStatus() after review = %+v, want empty", page)
+	}
+}
+
+func TestUserTemplateService_CopyAndRestoreModeration(t *testing.T) {
+	templateService, moderationService, db := setupModerationTestService(t)
+	if err := db.AutoMigrate(&model.GalleryTemplate{}, &model.GalleryLike{}, &model.TemplateRevision{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	templateService.WithRevisions(repository.NewTemplateRevisionRepository(db))
+	galleryService := NewGalleryService(repository.NewGalleryRepository(db), templateService)
+	shareService := NewTemplateShareService(templateService, "gaokao_bot")
+	ctx := context.Background()
+
+	// 模板在审核规则启用前写入，复制时按当前规则重新审核
+	template := &model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板", TemplateContent: "傻瓜距离{exam}还有{time}"}
+	db.Create(template)
+
+	item, _ := galleryService.Publish(ctx, template)
+	_, _ = galleryService.Approve(ctx, item.ID)
+	if _, err := galleryService.Copy(ctx, item.ID, 456); !isModerationError(err) {
+		t.Errorf("GalleryService.Copy() error = %v, want ModerationError", err)
+	}
+
+	code, _ := shareService.Share(ctx, template)
+	if _, err := shareService.Copy(ctx, code, 456); !isModerationError(err) {
+		t.Errorf("TemplateShareService.Copy() error = %v, want ModerationError", err)
+	}
+	if count, _ := templateService.CountByUserID(ctx, 456); count != 0 {
+		t.Errorf("CountByUserID() = %d, want 0", count)
+	}
+
+	// 恢复修订版本按修改模板重新审核，未通过时模板保持不变
+	template.TemplateContent = "距离{exam}还有{time}"
+	if err := templateService.Update(ctx, template); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	revisions, _ := templateService.GetRevisions(ctx, 1)
+	if len(revisions) != 1 {
+		t.Fatalf("GetRevisions() = %d revisions, want 1", len(revisions))
+	}
+	if err := templateService.RestoreRevision(ctx, template, revisions[0].ID); !isModerationError(err) {
+		t.Errorf("RestoreRevision() error = %v, want ModerationError", err)
+	}
+	if current, _ := templateService.GetByID(ctx, 1); current.TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("TemplateContent after rejected restore = %q", current.TemplateContent)
+	}
+
+	page, err := moderationService.ListByStatus(ctx, model.ModerationStatusPending, 1, 10)
+	if err != nil || page.Total != 3 {
+		t.Fatalf("ListByStatus() = %+v, %v, want 3 records", page, err)
+	}
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// TemplateVariables 模板支持的内置变量，与 util.GetCountDownString、util.ApplyCalendar 和 util.ApplyQuote 的替换保持一致
+var TemplateVariables = []string{
+	"{exam}", "{exam_s}", "{exam_year}", "{time}", util.QuotePlaceholder,
+	util.LunarDatePlaceholder, util.SolarTermPlaceholder, util.WeekdayPlaceholder,
+}
+
+// templateVariableRegex 匹配模板中的变量
+var templateVariableRegex = regexp.MustCompile(`\{[^{}\s]*\}`)
+
+// previewUpcomingLead 考试已开始或结束时，「倒计时」状态预览使用的开考前时长
+const previewUpcomingLead = 100 * 24 * time.Hour
+
+// 模板校验问题代码
+const (
+	TemplateIssueEmpty           = "empty"
+	TemplateIssueTooLong         = "too_long"
+	TemplateIssueMissingVariable = "missing_variable"
+	TemplateIssueUnknownVariable = "unknown_variable"
+	// TemplateIssueUndefinedVariable 引用了用户未定义的自定义变量
+	TemplateIssueUndefinedVariable = "undefined_variable"
+)
+
+// TemplateIssue 模板校验问题
+type TemplateIssue struct {
+	// Field 出现问题的字段：template_content 或 template_name
+	Field string `json:"field"`
+	// Code 问题代码，见 TemplateIssue* 常量
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PreviewExam 预览使用的考试
+type PreviewExam struct {
+	ExamYear  int       `json:"exam_year"`
+	ExamDesc  string    `json:"exam_desc"`
+	ShortDesc string    `json:"short_desc"`
+	BeginDate time.Time `json:"begin_date"`
+	EndDate   time.Time `json:"end_date"`
+	// Sample 为 true 表示没有考试数据，使用示例考试
+	Sample bool `json:"sample"`
+}
+
+// TemplatePreviewStates 模板在各状态下的渲染结果
+type TemplatePreviewStates struct {
+	// Current 当前时间的渲染结果
+	Current string `json:"current"`
+	// Upcoming 考试开始前（倒计时）
+	Upcoming string `json:"upcoming"`
+	// InProgress 考试进行中
+	InProgress string `json:"in_progress"`
+	// Ended 考试结束后
+	Ended string `json:"ended"`
+}
+
+// TemplatePreview 模板预览结果
+type TemplatePreview struct {
+	// Valid 模板能否保存，为 false 时 Errors 列出原因
+	Valid  bool            `json:"valid"`
+	Errors []TemplateIssue `json:"errors"`
+	// Warnings 不影响保存的问题，如无法识别的变量会原样输出
+	Warnings []TemplateIssue       `json:"warnings"`
+	Exam     PreviewExam           `json:"exam"`
+	States   TemplatePreviewStates `json:"states"`
+}
+
+// TemplatePreviewService 模板预览服务，使用与 Bot 相同的渲染逻辑
+type TemplatePreviewService struct {
+	examDateService *ExamDateService
+	variableService *UserVariableService
+	quoteService    *QuoteService
+}
+
+// NewTemplatePreviewService 创建模板预览服务
+func NewTemplatePreviewService(examDateService *ExamDateService) *TemplatePreviewService {
+	return &TemplatePreviewService{examDateService: examDateService}
+}
+
+// WithUserVariables 启用用户自定义变量：预览时替换用户定义的 {var:名称}，并将未定义的变量列为错误
+func (s *TemplatePreviewService) WithUserVariables(variableService *UserVariableService) *TemplatePreviewService {
+	s.variableService = variableService
+	return s
+}
+
+// WithQuotes 启用励志语录：预览时将 {quote} 替换为用户的今日语录
+func (s *TemplatePreviewService) WithQuotes(quoteService *QuoteService) *TemplatePreviewService {
+	s.quoteService = quoteService
+	return s
+}
+
+// Preview 校验模板并渲染各状态下的文本
+// year 为 0 时使用下一场（或正在进行、最近结束的）高考；指定年份没有考试时返回 nil
+func (s *TemplatePreviewService) Preview(ctx context.Context, name, content string, year int, now time.Time) (*TemplatePreview, error) {
+	return s.PreviewForUser(ctx, 0, name, content, year, now)
+}
+
+// PreviewForUser 与 Preview 相同，但使用用户的自定义变量渲染，userID 为 0 时不替换自定义变量
+func (s *TemplatePreviewService) PreviewForUser(ctx context.Context, userID int64, name, content string, year int, now time.Time) (*TemplatePreview, error) {
+	exam, err := s.previewExam(ctx, year, now)
+	if err != nil {
+		return nil, err
+	}
+	if exam == nil {
+		return nil, nil
+	}
+
+	errs, warnings := ValidateTemplate(name, content)
+
+	var rc renderContext
+	if s.variableService != nil && userID != 0 {
+		if rc.variables, err = s.variableService.GetValues(ctx, userID); err != nil {
+			return nil, err
+		}
+		for _, ref := range util.UserVariableRefs(content) {
+			if _, ok := rc.variables[ref]; !ok && ValidateVariableName(ref) == nil {
+				errs = append(errs, TemplateIssue{
+					Field:   "template_content",
+					Code:    TemplateIssueUndefinedVariable,
+					Message: fmt.Sprintf("未定义的变量 {%s%s}，请先添加变量", util.UserVariablePrefix, ref),
+				})
+			}
+		}
+	}
+	if s.quoteService != nil {
+		if rc.quote, err = s.quoteService.DailyQuote(ctx, UserQuoteKey(userID), now); err != nil {
+			return nil, err
+		}
+	}
+
+	preview := &TemplatePreview{
+		Valid:    len(errs) == 0,
+		Errors:   errs,
+		Warnings: warnings,
+		Exam: PreviewExam{
+			ExamYear:  exam.ExamYear,
+			ExamDesc:  exam.ExamDesc,
+			ShortDesc: exam.ShortDesc,
+			BeginDate: exam.ExamBeginDate,
+			EndDate:   exam.ExamEndDate,
+			Sample:    exam.ID == 0,
+		},
+	}
+
+	upcomingAt := now
+	if !exam.ExamBeginDate.After(now) {
+		upcomingAt = exam.ExamBeginDate.Add(-previewUpcomingLead)
+	}
+	preview.States = TemplatePreviewStates{
+		Current:    renderCountdown(exam, content, rc, now),
+		Upcoming:   renderCountdown(exam, content, rc, upcomingAt),
+		InProgress: renderCountdown(exam, content, rc, exam.ExamBeginDate.Add(exam.ExamEndDate.Sub(exam.ExamBeginDate)/2)),
+		Ended:      renderCountdown(exam, content, rc, exam.ExamEndDate.Add(time.Second)),
+	}
+	return preview, nil
+}
+
+// previewExam 选择预览使用的考试
+func (s *TemplatePreviewService) previewExam(ctx context.Context, year int, now time.Time) (*model.ExamDate, error) {
+	filter := ExamFilter{Kind: constant.ExamKindGaokao}
+	if year != 0 {
+		filter.Year = year
+		exams, err := s.examDateService.Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(exams) == 0 {
+			// 该年份没有高考时使用其他类型的考试
+			if exams, err = s.examDateService.Find(ctx, ExamFilter{Year: year}); err != nil {
+				return nil, err
+			}
+		}
+		if len(exams) == 0 {
+			return nil, nil
+		}
+		return &exams[0], nil
+	}
+
+	exam, err := s.examDateService.GetNextUpcoming(ctx, filter, now)
+	if err != nil || exam != nil {
+		return exam, err
+	}
+	running, err := s.examDateService.GetRunning(ctx, filter, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(running) > 0 {
+		return &running[0], nil
+	}
+	exam, err = s.examDateService.GetLastEnded(ctx, filter, now)
+	if err != nil || exam != nil {
+		return exam, err
+	}
+	return sampleExam(now), nil
+}
+
+// sampleExam 没有考试数据时使用的示例考试（下一个 6 月 7 日开考）
+func sampleExam(now time.Time) *model.ExamDate {
+	loc := util.GetBJTLocation()
+	year := now.In(loc).Year()
+	if !now.Before(time.Date(year, 6, 7, 9, 0, 0, 0, loc)) {
+		year++
+	}
+	return &model.ExamDate{
+		ExamYear:      year,
+		ExamKind:      constant.ExamKindGaokao,
+		ExamDesc:      fmt.Sprintf("%d年普通高等学校招生全国统一考试", year),
+		ShortDesc:     fmt.Sprintf("%d年高考", year),
+		ExamBeginDate: time.Date(year, 6, 7, 9, 0, 0, 0, loc),
+		ExamEndDate:   time.Date(year, 6, 10, 17, 0, 0, 0, loc),
+	}
+}
+
+// ValidateTemplate 列出模板的全部问题
+// errors 与 ValidateTemplateContent、ValidateTemplateName 的规则一致，存在时无法保存；
+// warnings 为无法识别的变量，渲染时原样输出（是否定义了引用的自定义变量由 PreviewForUser 检查）
+func ValidateTemplate(name, content string) (errs, warnings []TemplateIssue) {
+	errs = []TemplateIssue{}
+	warnings = []TemplateIssue{}
+
+	if content == "" {
+		errs = append(errs, TemplateIssue{Field: "template_content", Code: TemplateIssueEmpty, Message: "模板内容不能为空"})
+	} else if charCount := utf8.RuneCountInString(content); charCount > MaxTemplateContentLength {
+		errs = append(errs, TemplateIssue{
+			Field:   "template_content",
+			Code:    TemplateIssueTooLong,
+			Message: fmt.Sprintf("模板内容不能超过 %d 字符（当前 %d 字符）", MaxTemplateContentLength, charCount),
+		})
+	}
+
+	for _, variable := range []string{"{exam}", "{time}"} {
+		if !strings.Contains(content, variable) {
+			errs = append(errs, TemplateIssue{
+				Field:   "template_content",
+				Code:    TemplateIssueMissingVariable,
+				Message: fmt.Sprintf("模板必须包含 %s 变量", variable),
+			})
+		}
+	}
+
+	if err := ValidateTemplateName(name); err != nil {
+		errs = append(errs, TemplateIssue{Field: "template_name", Code: TemplateIssueTooLong, Message: err.Error()})
+	}
+
+	seen := make(map[string]bool)
+	for _, variable := range templateVariableRegex.FindAllString(content, -1) {
+		if seen[variable] || isTemplateVariable(variable) {
+			continue
+		}
+		seen[variable] = true
+		warnings = append(warnings, TemplateIssue{
+			Field:   "template_content",
+			Code:    TemplateIssueUnknownVariable,
+			Message: fmt.Sprintf("无法识别的变量 %s，将原样显示", variable),
+		})
+	}
+	return errs, warnings
+}
+
+// isTemplateVariable 是否为支持的模板变量，名称有效的 {var:名称} 视为用户自定义变量
+func isTemplateVariable(variable string) bool {
+	if name, ok := strings.CutPrefix(strings.Trim(variable, "{}"), util.UserVariablePrefix); ok {
+		return ValidateVariableName(name) == nil
+	}
+	for _, v := range TemplateVariables {
+		if v == variable {
+			return true
+		}
+	}
+	return false
+}
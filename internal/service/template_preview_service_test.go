@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+	"gorm.io/gorm"
+)
+
+func setupTemplatePreviewTestService(t *testing.T) (*TemplatePreviewService, *gorm.DB) {
+	db := setupExamDateTestDB(t)
+	examDateService := NewExamDateService(repository.NewExamDateRepository(db))
+	return NewTemplatePreviewService(examDateService), db
+}
+
+func createPreviewTestExam(db *gorm.DB, id uint, year int, kind string) {
+	loc := util.GetBJTLocation()
+	end := time.Date(year, 6, 10, 17, 0, 0, 0, loc)
+	db.Create(&model.ExamDate{
+		ID:                id,
+		ExamYear:          year,
+		ExamKind:          kind,
+		ExamDesc:          "高考",
+		ShortDesc:         "高考",
+		ExamBeginDate:     time.Date(year, 6, 7, 9, 0, 0, 0, loc),
+		ExamEndDate:       end,
+		ExamYearBeginDate: end.AddDate(-1, 0, 0),
+		ExamYearEndDate:   end,
+	})
+}
+
+func TestTemplatePreviewService_Preview(t *testing.T) {
+	service, db := setupTemplatePreviewTestService(t)
+	createPreviewTestExam(db, 1, 2026, constant.ExamKindGaokao)
+
+	now := time.Date(2026, 6, 6, 9, 0, 0, 0, util.GetBJTLocation())
+	preview, err := service.Preview(context.Background(), "", "距离{exam}还有{time}", 0, now)
+	if err != nil || preview == nil {
+		t.Fatalf("Preview() = %v, %v", preview, err)
+	}
+
+	if !preview.Valid || len(preview.Errors) != 0 || len(preview.Warnings) != 0 {
+		t.Errorf("expected valid template, got %+v", preview)
+	}
+	if preview.Exam.ExamYear != 2026 || preview.Exam.Sample {
+		t.Errorf("Exam = %+v", preview.Exam)
+	}
+
+	want := TemplatePreviewStates{
+		Current:    "距离高考还有1天",
+		Upcoming:   "距离高考还有1天",
+		InProgress: "高考正在进行中！",
+		Ended:      "高考已经结束了。",
+	}
+	if preview.States != want {
+		t.Errorf("States = %+v, want %+v", preview.States, want)
+	}
+}
+
+func TestTemplatePreviewService_PreviewCalendar(t *testing.T) {
+	service, db := setupTemplatePreviewTestService(t)
+	createPreviewTestExam(db, 1, 2026, constant.ExamKindGaokao)
+
+	// 2026-05-21 为农历四月初五、星期四、小满
+	now := time.Date(2026, 5, 21, 9, 0, 0, 0, util.GetBJTLocation())
+	preview, err := service.Preview(context.Background(), "", "今天是农历{lunar_date} {weekday} {solar_term}，距离{exam}还有{time}", 0, now)
+	if err != nil || preview == nil {
+		t.Fatalf("Preview() = %v, %v", preview, err)
+	}
+	if len(preview.Warnings) != 0 {
+		t.Errorf("Warnings = %+v, want none", preview.Warnings)
+	}
+	if want := "今天是农历四月初五 星期四 小满，距离高考还有17天"; preview.States.Current != want {
+		t.Errorf("Current = %q, want %q", preview.States.Current, want)
+	}
+}
+
+func TestTemplatePreviewService_PreviewAfterExam(t *testing.T) {
+	service, db := setupTemplatePreviewTestService(t)
+	createPreviewTestExam(db, 1, 2026, constant.ExamKindGaokao)
+
+	// 考试已结束时使用最近结束的考试，倒计时状态按开考前 100 天渲染
+	now := time.Date(2026, 7, 1, 0, 0, 0, 0, util.GetBJTLocation())
+	preview, err := service.Preview(context.Background(), "", "距离{exam}还有{time}", 0, now)
+	if err != nil || preview == nil {
+		t.Fatalf("Preview() = %v, %v", preview, err)
+	}
+
+	if preview.States.Current != "高考已经结束了。" {
+		t.Errorf("Current = %q", preview.States.Current)
+	}
+	if preview.States.Upcoming != "距离高考还有100天" {
+		t.Errorf("Upcoming = %q", preview.States.Upcoming)
+	}
+}
+
+func TestTemplatePreviewService_PreviewByYear(t *testing.T) {
+	service, db := setupTemplatePreviewTestService(t)
+	createPreviewTestExam(db, 1, 2026, constant.ExamKindGaokao)
+	createPreviewTestExam(db, 2, 2027, "zhongkao")
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, util.GetBJTLocation())
+	ctx := context.Background()
+
+	preview, err := service.Preview(ctx, "", "{exam_year}{exam}{time}", 2027, now)
+	if err != nil || preview == nil || preview.Exam.ExamYear != 2027 {
+		t.Fatalf("Preview(2027) = %+v, %v", preview, err)
+	}
+	if !strings.HasPrefix(preview.States.Upcoming, "2027高考") {
+		t.Errorf("Upcoming = %q", preview.States.Upcoming)
+	}
+
+	preview, err = service.Preview(ctx, "", "{exam}{time}", 2030, now)
+	if err != nil || preview != nil {
+		t.Errorf("Preview(2030) = %+v, %v, want nil", preview, err)
+	}
+}
+
+func TestTemplatePreviewService_SampleExam(t *testing.T) {
+	service, _ := setupTemplatePreviewTestService(t)
+
+	now := time.Date(2026, 6, 8, 0, 0, 0, 0, util.GetBJTLocation())
+	preview, err := service.Preview(context.Background(), "", "{exam}{time}", 0, now)
+	if err != nil || preview == nil {
+		t.Fatalf("Preview() = %v, %v", preview, err)
+	}
+	if !preview.Exam.Sample || preview.Exam.ExamYear != 2027 {
+		t.Errorf("Exam = %+v, want sample exam of 2027", preview.Exam)
+	}
+	if !strings.Contains(preview.States.Current, "2027年普通高等学校招生全国统一考试") {
+		t.Errorf("Current = %q", preview.States.Current)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		tplName      string
+		content      string
+		wantErrors   []string
+		wantWarnings []string
+	}{
+		{name: "valid", content: "距离{exam}还有{time}"},
+		{name: "empty", content: "", wantErrors: []string{TemplateIssueEmpty, TemplateIssueMissingVariable, TemplateIssueMissingVariable}},
+		{name: "missing time", content: "{exam}", wantErrors: []string{TemplateIssueMissingVariable}},
+		{name: "too long", content: "{exam}{time}" + strings.Repeat("字", MaxTemplateContentLength), wantErrors: []string{TemplateIssueTooLong}},
+		{name: "long name", tplName: strings.Repeat("名", MaxTemplateNameLength+1), content: "{exam}{time}", wantErrors: []string{TemplateIssueTooLong}},
+		{name: "unknown variable", content: "{exam}{time}{days}{days}", wantWarnings: []string{TemplateIssueUnknownVariable}},
+		{name: "calendar variables", content: "农历{lunar_date} {weekday} {solar_term}，距离{exam}还有{time}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, warnings := ValidateTemplate(tt.tplName, tt.content)
+			if got := issueCodes(errs); strings.Join(got, ",") != strings.Join(tt.wantErrors, ",") {
+				t.Errorf("errors = %v, want %v", got, tt.wantErrors)
+			}
+			if got := issueCodes(warnings); strings.Join(got, ",") != strings.Join(tt.wantWarnings, ",") {
+				t.Errorf("warnings = %v, want %v", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+// TestValidateTemplate_ConsistentWithSave 预览的校验结果与保存时的校验一致
+func TestValidateTemplate_ConsistentWithSave(t *testing.T) {
+	for _, content := range []string{"", "{exam}", "{time}", "{exam}{time}", strings.Repeat("字", MaxTemplateContentLength+1)} {
+		errs, _ := ValidateTemplate("", content)
+		if (len(errs) == 0) != (ValidateTemplateContent(content) == nil) {
+			t.Errorf("ValidateTemplate(%q) = %v, inconsistent with ValidateTemplateContent", content, errs)
+		}
+	}
+}
+
+func issueCodes(issues []TemplateIssue) []string {
+	codes := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
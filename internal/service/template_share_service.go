@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+const (
+	// ShareCodeLength 分享码长度
+	ShareCodeLength = 8
+
+	// ShareStartPrefix 分享链接中 /start 参数的前缀，如 t.me/<bot>?start=tpl_<code>
+	ShareStartPrefix = "tpl_"
+
+	// shareCodeAlphabet 分享码字符集，去除易混淆的 0、1、i、l、o，可直接用于命令链接
+	shareCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+	// shareCodeAttempts 分享码冲突时的最大重试次数
+	shareCodeAttempts = 5
+)
+
+var (
+	// ErrShareCodeNotFound 分享码不存在或已撤销
+	ErrShareCodeNotFound = errors.New("share code not found")
+	// ErrCopyOwnTemplate 复制自己分享的模板
+	ErrCopyOwnTemplate = errors.New("cannot copy own template")
+)
+
+// TemplateShareService 模板分享服务
+// 每个模板最多一个分享码，撤销后旧链接失效，重新分享时生成新的分享码
+type TemplateShareService struct {
+	templateService *UserTemplateService
+	botUsername     string
+}
+
+// NewTemplateShareService 创建模板分享服务，botUsername 用于生成 t.me 分享链接
+func NewTemplateShareService(templateService *UserTemplateService, botUsername string) *TemplateShareService {
+	return &TemplateShareService{
+		templateService: templateService,
+		botUsername:     strings.TrimPrefix(botUsername, "@"),
+	}
+}
+
+// Share 为模板生成分享码，已分享时返回现有分享码
+func (s *TemplateShareService) Share(ctx context.Context, template *model.UserTemplate) (string, error) {
+	if template.ShareCode != nil {
+		return *template.ShareCode, nil
+	}
+
+	for i := 0; i < shareCodeAttempts; i++ {
+		code, err := generateShareCode()
+		if err != nil {
+			return "", err
+		}
+
+		existing, err := s.templateService.GetByShareCode(ctx, code)
+		if err != nil {
+			return "", err
+		}
+		if existing != nil {
+			continue
+		}
+
+		if err := s.templateService.UpdateShareCode(ctx, template.ID, &code); err != nil {
+			return "", err
+		}
+		template.ShareCode = &code
+		return code, nil
+	}
+	return "", fmt.Errorf("生成分享码失败：连续 %d 次冲突", shareCodeAttempts)
+}
+
+// Revoke 撤销模板的分享码
+func (s *TemplateShareService) Revoke(ctx context.Context, template *model.UserTemplate) error {
+	if template.ShareCode == nil {
+		return nil
+	}
+	if err := s.templateService.UpdateShareCode(ctx, template.ID, nil); err != nil {
+		return err
+	}
+	template.ShareCode = nil
+	return nil
+}
+
+// GetByCode 根据分享码获取模板，不存在时返回 ErrShareCodeNotFound
+func (s *TemplateShareService) GetByCode(ctx context.Context, code string) (*model.UserTemplate, error) {
+	if !IsValidShareCode(code) {
+		return nil, ErrShareCodeNotFound
+	}
+
+	template, err := s.templateService.GetByShareCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrShareCodeNotFound
+	}
+	return template, nil
+}
+
+// Copy 将分享的模板复制到用户名下，受 MaxTemplatesPerUser 限制
+// 超出限制时返回 repository.ErrTemplateLimitExceeded
+func (s *TemplateShareService) Copy(ctx context.Context, code string, userID int64) (*model.UserTemplate, error) {
+	shared, err := s.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if shared.UserID == userID {
+		return nil, ErrCopyOwnTemplate
+	}
+
+	id, err := util.GenerateID()
+	if err != nil {
+		return nil, err
+	}
+
+	// 复制到自己名下等同于新建模板，引用的变量须由复制者定义，并按当前规则重新审核
+	if err := s.templateService.CheckVariables(ctx, userID, shared.TemplateContent); err != nil {
+		return nil, err
+	}
+	name, content, err := s.templateService.ModerateInput(ctx, userID, 0, shared.TemplateName, shared.TemplateContent)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &model.UserTemplate{
+		ID:              id,
+		UserID:          userID,
+		TemplateName:    name,
+		TemplateContent: content,
+	}
+	if err := s.templateService.CreateWithLimit(ctx, template, MaxTemplatesPerUser); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Link 返回分享码的 Bot 深度链接，未配置 Bot 用户名时返回空字符串
+func (s *TemplateShareService) Link(code string) string {
+	if s.botUsername == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", s.botUsername, ShareStartPrefix, code)
+}
+
+// IsValidShareCode 检查分享码格式
+func IsValidShareCode(code string) bool {
+	if len(code) != ShareCodeLength {
+		return false
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(shareCodeAlphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// generateShareCode 使用加密随机数生成分享码，避免被枚举
+func generateShareCode() (string, error) {
+	max := big.NewInt(int64(len(shareCodeAlphabet)))
+	code := make([]byte, ShareCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shareCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
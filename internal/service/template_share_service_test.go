@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupTemplateShareTestService(t *testing.T) (*TemplateShareService, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	templateService := NewUserTemplateService(repository.NewUserTemplateRepository(db))
+	return NewTemplateShareService(templateService, "@gaokao_bot"), db
+}
+
+func TestTemplateShareService_ShareAndRevoke(t *testing.T) {
+	service, db := setupTemplateShareTestService(t)
+	ctx := context.Background()
+
+	template := &model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板", TemplateContent: "距离{exam}还有{time}"}
+	db.Create(template)
+
+	code, err := service.Share(ctx, template)
+	if err != nil {
+		t.Fatalf("Share() error = %v", err)
+	}
+	if !IsValidShareCode(code) {
+		t.Errorf("Share() = %q, invalid share code", code)
+	}
+
+	// 重复分享返回同一分享码
+	if again, err := service.Share(ctx, template); err != nil || again != code {
+		t.Errorf("Share() again = %q, %v, want %q", again, err, code)
+	}
+
+	shared, err := service.GetByCode(ctx, code)
+	if err != nil || shared.ID != 1 {
+		t.Fatalf("GetByCode() = %+v, %v", shared, err)
+	}
+
+	if err := service.Revoke(ctx, template); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := service.GetByCode(ctx, code); !errors.Is(err, ErrShareCodeNotFound) {
+		t.Errorf("GetByCode() after revoke error = %v, want ErrShareCodeNotFound", err)
+	}
+
+	// 重新分享生成新的分享码
+	newCode, err := service.Share(ctx, template)
+	if err != nil || newCode == code {
+		t.Errorf("Share() after revoke = %q, %v, want new code", newCode, err)
+	}
+}
+
+func TestTemplateShareService_GetByCode_Invalid(t *testing.T) {
+	service, _ := setupTemplateShareTestService(t)
+
+	for _, code := range []string{"", "abc", "ABCDEFGH", "abcdefg0", "abcdefghi"} {
+		if _, err := service.GetByCode(context.Background(), code); !errors.Is(err, ErrShareCodeNotFound) {
+			t.Errorf("GetByCode(%q) error = %v, want ErrShareCodeNotFound", code, err)
+		}
+	}
+}
+
+func TestTemplateShareService_Copy(t *testing.T) {
+	service, db := setupTemplateShareTestService(t)
+	ctx := context.Background()
+
+	template := &model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板", TemplateContent: "距离{exam}还有{time}"}
+	db.Create(template)
+	code, _ := service.Share(ctx, template)
+
+	if _, err := service.Copy(ctx, code, 123); !errors.Is(err, ErrCopyOwnTemplate) {
+		t.Errorf("Copy() own template error = %v, want ErrCopyOwnTemplate", err)
+	}
+
+	copied, err := service.Copy(ctx, code, 456)
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if copied.UserID != 456 || copied.ID == 1 || copied.TemplateName != "模板" ||
+		copied.TemplateContent != "距离{exam}还有{time}" || copied.ShareCode != nil {
+		t.Errorf("Copy() = %+v", copied)
+	}
+
+	for i := 1; i < MaxTemplatesPerUser; i++ {
+		db.Create(&model.UserTemplate{ID: int64(100 + i), UserID: 456, TemplateContent: "{exam}{time}"})
+	}
+	if _, err := service.Copy(ctx, code, 456); !errors.Is(err, repository.ErrTemplateLimitExceeded) {
+		t.Errorf("Copy() over limit error = %v, want ErrTemplateLimitExceeded", err)
+	}
+}
+
+func TestTemplateShareService_Link(t *testing.T) {
+	service, _ := setupTemplateShareTestService(t)
+	if got, want := service.Link("abcdefgh"), "https://t.me/gaokao_bot?start=tpl_abcdefgh"; got != want {
+		t.Errorf("Link() = %q, want %q", got, want)
+	}
+
+	if got := NewTemplateShareService(nil, "").Link("abcdefgh"); got != "" {
+		t.Errorf("Link() without bot username = %q, want empty", got)
+	}
+}
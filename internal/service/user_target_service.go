@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+)
+
+// UserTargetService 用户自定义目标服务
+type UserTargetService struct {
+	repo repository.UserTargetRepository
+}
+
+// NewUserTargetService 创建用户自定义目标服务
+func NewUserTargetService(repo repository.UserTargetRepository) *UserTargetService {
+	return &UserTargetService{repo: repo}
+}
+
+// GetByUserID 根据用户ID获取目标列表
+func (s *UserTargetService) GetByUserID(ctx context.Context, userID int64) ([]model.UserTarget, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// GetByID 根据ID获取目标
+func (s *UserTargetService) GetByID(ctx context.Context, id int64) (*model.UserTarget, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Delete 删除目标
+func (s *UserTargetService) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// CreateWithLimit 在事务中原子地检查数量限制并创建目标
+func (s *UserTargetService) CreateWithLimit(ctx context.Context, target *model.UserTarget, maxLimit int64) error {
+	return s.repo.CreateWithLimit(ctx, target, maxLimit)
+}
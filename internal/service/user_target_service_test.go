@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupUserTargetTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&model.UserTarget{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+func setupUserTargetTestService(t *testing.T) (*UserTargetService, *gorm.DB) {
+	db := setupUserTargetTestDB(t)
+	return NewUserTargetService(repository.NewUserTargetRepository(db)), db
+}
+
+func TestUserTargetService_CRUD(t *testing.T) {
+	service, _ := setupUserTargetTestService(t)
+
+	target := &model.UserTarget{ID: 1, UserID: 123, TargetName: "一模", TargetDate: time.Now().AddDate(0, 1, 0)}
+	if err := service.CreateWithLimit(context.Background(), target, 10); err != nil {
+		t.Fatalf("CreateWithLimit() error = %v", err)
+	}
+
+	targets, err := service.GetByUserID(context.Background(), 123)
+	if err != nil || len(targets) != 1 {
+		t.Fatalf("GetByUserID() = %v, %v", targets, err)
+	}
+
+	got, err := service.GetByID(context.Background(), 1)
+	if err != nil || got == nil || got.TargetName != "一模" {
+		t.Fatalf("GetByID() = %+v, %v", got, err)
+	}
+
+	if err := service.Delete(context.Background(), 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	got, err = service.GetByID(context.Background(), 1)
+	if err != nil || got != nil {
+		t.Errorf("Expected target deleted, got %+v, %v", got, err)
+	}
+}
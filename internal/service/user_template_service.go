@@ -1,57 +1,282 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/moderation"
 	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+const (
+	// MaxTemplatesPerUser 每个用户最多可创建的模板数量
+	// 限制为 10 个以防止滥用和控制数据库存储
+	MaxTemplatesPerUser = 10
+
+	// MaxTemplateContentLength 模板内容最大长度（字符数）
+	// 限制为 140 字符以确保：
+	// 1. 适合 Telegram 消息显示
+	// 2. 防止过长文本影响用户体验
+	// 3. 控制数据库字段大小
+	MaxTemplateContentLength = 140
+
+	// MaxTemplateNameLength 模板名称最大长度（字符数）
+	// 限制为 20 字符以保持名称简洁易读
+	MaxTemplateNameLength = 20
+
+	// MaxRevisionsPerTemplate 每个模板最多保留的修订记录数量，超出时删除最早的记录
+	MaxRevisionsPerTemplate = 20
+)
+
+var (
+	// ErrTemplateOrderMismatch 排序列表与用户的模板不一致（缺少、重复或包含他人的模板）
+	ErrTemplateOrderMismatch = errors.New("template order mismatch")
+	// ErrRevisionNotFound 修订记录不存在或不属于该模板
+	ErrRevisionNotFound = errors.New("template revision not found")
 )
 
 // UserTemplateService 用户模板服务
 type UserTemplateService struct {
-	repo *repository.UserTemplateRepository
+	repo      repository.UserTemplateRepository
+	revisions repository.TemplateRevisionRepository
+
+	moderation        *moderation.Pipeline
+	moderationRecords repository.ModerationRecordRepository
+
+	variables *UserVariableService
 }
 
 // NewUserTemplateService 创建用户模板服务
-func NewUserTemplateService(repo *repository.UserTemplateRepository) *UserTemplateService {
+func NewUserTemplateService(repo repository.UserTemplateRepository) *UserTemplateService {
 	return &UserTemplateService{repo: repo}
 }
 
+// WithRevisions 启用修订历史：修改模板前保存修改前的版本，删除模板时一并删除
+func (s *UserTemplateService) WithRevisions(revisions repository.TemplateRevisionRepository) *UserTemplateService {
+	s.revisions = revisions
+	return s
+}
+
 // GetByUserID 根据用户ID获取模板列表
-func (s *UserTemplateService) GetByUserID(userID int64) ([]model.UserTemplate, error) {
-	return s.repo.GetByUserID(userID)
+func (s *UserTemplateService) GetByUserID(ctx context.Context, userID int64) ([]model.UserTemplate, error) {
+	return s.repo.GetByUserID(ctx, userID)
 }
 
 // GetDefaultTemplate 获取默认模板
-func (s *UserTemplateService) GetDefaultTemplate() (*model.UserTemplate, error) {
-	return s.repo.GetDefaultTemplate()
+func (s *UserTemplateService) GetDefaultTemplate(ctx context.Context) (*model.UserTemplate, error) {
+	return s.repo.GetDefaultTemplate(ctx)
 }
 
 // Create 创建模板
-func (s *UserTemplateService) Create(template *model.UserTemplate) error {
-	return s.repo.Create(template)
+func (s *UserTemplateService) Create(ctx context.Context, template *model.UserTemplate) error {
+	return s.repo.Create(ctx, template)
 }
 
 // Update 更新模板
-func (s *UserTemplateService) Update(template *model.UserTemplate) error {
-	return s.repo.Update(template)
+// 启用修订历史时，名称或内容发生变化前先保存修改前的版本，保存失败则不更新
+func (s *UserTemplateService) Update(ctx context.Context, template *model.UserTemplate) error {
+	if s.revisions != nil {
+		if err := s.recordRevision(ctx, template); err != nil {
+			return err
+		}
+	}
+	return s.repo.Update(ctx, template)
 }
 
 // Delete 删除模板
-func (s *UserTemplateService) Delete(id int64) error {
-	return s.repo.Delete(id)
+func (s *UserTemplateService) Delete(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if s.revisions != nil {
+		return s.revisions.DeleteByTemplateID(ctx, id)
+	}
+	return nil
+}
+
+// GetRevisions 获取模板的修订记录，最新的在前；未启用修订历史时返回空列表
+func (s *UserTemplateService) GetRevisions(ctx context.Context, templateID int64) ([]model.TemplateRevision, error) {
+	if s.revisions == nil {
+		return []model.TemplateRevision{}, nil
+	}
+	revisions, err := s.revisions.GetByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if revisions == nil {
+		revisions = []model.TemplateRevision{}
+	}
+	return revisions, nil
+}
+
+// RestoreRevision 将模板恢复为指定修订记录的名称和内容
+// 恢复本身也是一次修改，恢复前的版本会记入修订历史，因此恢复操作可以撤销
+// 修订记录不存在或不属于该模板时返回 ErrRevisionNotFound
+func (s *UserTemplateService) RestoreRevision(ctx context.Context, template *model.UserTemplate, revisionID int64) error {
+	if s.revisions == nil {
+		return ErrRevisionNotFound
+	}
+
+	revision, err := s.revisions.GetByID(ctx, revisionID)
+	if err != nil {
+		return err
+	}
+	if revision == nil || revision.TemplateID != template.ID {
+		return ErrRevisionNotFound
+	}
+
+	// 修订版本引用的变量可能已删除，且可能早于当前的审核规则，恢复时按修改模板重新校验
+	if err := s.CheckVariables(ctx, template.UserID, revision.TemplateContent); err != nil {
+		return err
+	}
+	name, content, err := s.ModerateInput(ctx, template.UserID, template.ID, revision.TemplateName, revision.TemplateContent)
+	if err != nil {
+		return err
+	}
+
+	template.TemplateName = name
+	template.TemplateContent = content
+	return s.Update(ctx, template)
+}
+
+// recordRevision 名称或内容发生变化时保存模板修改前的版本
+func (s *UserTemplateService) recordRevision(ctx context.Context, template *model.UserTemplate) error {
+	current, err := s.repo.GetByID(ctx, template.ID)
+	if err != nil {
+		return err
+	}
+	if current == nil || (current.TemplateName == template.TemplateName && current.TemplateContent == template.TemplateContent) {
+		return nil
+	}
+
+	id, err := util.GenerateID()
+	if err != nil {
+		return err
+	}
+
+	return s.revisions.Create(ctx, &model.TemplateRevision{
+		ID:              id,
+		TemplateID:      current.ID,
+		UserID:          current.UserID,
+		TemplateName:    current.TemplateName,
+		TemplateContent: current.TemplateContent,
+	}, MaxRevisionsPerTemplate)
 }
 
 // GetByID 根据ID获取模板
-func (s *UserTemplateService) GetByID(id int64) (*model.UserTemplate, error) {
-	return s.repo.GetByID(id)
+func (s *UserTemplateService) GetByID(ctx context.Context, id int64) (*model.UserTemplate, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
 // CountByUserID 统计用户的模板数量
-func (s *UserTemplateService) CountByUserID(userID int64) (int64, error) {
-	return s.repo.CountByUserID(userID)
+func (s *UserTemplateService) CountByUserID(ctx context.Context, userID int64) (int64, error) {
+	return s.repo.CountByUserID(ctx, userID)
 }
 
 // CreateWithLimit 在事务中原子地检查数量限制并创建模板
 // 使用数据库锁防止并发创建时超过限制
-func (s *UserTemplateService) CreateWithLimit(template *model.UserTemplate, maxLimit int64) error {
-	return s.repo.CreateWithLimit(template, maxLimit)
+func (s *UserTemplateService) CreateWithLimit(ctx context.Context, template *model.UserTemplate, maxLimit int64) error {
+	return s.repo.CreateWithLimit(ctx, template, maxLimit)
+}
+
+// GetByShareCode 根据分享码获取模板
+func (s *UserTemplateService) GetByShareCode(ctx context.Context, code string) (*model.UserTemplate, error) {
+	return s.repo.GetByShareCode(ctx, code)
+}
+
+// UpdateShareCode 更新模板的分享码，code 为 nil 时取消分享
+func (s *UserTemplateService) UpdateShareCode(ctx context.Context, id int64, code *string) error {
+	return s.repo.UpdateShareCode(ctx, id, code)
+}
+
+// GetDefaultTemplateForUser 获取用户生效的默认模板：设置了个人默认模板时使用个人默认，否则使用全局默认
+func (s *UserTemplateService) GetDefaultTemplateForUser(ctx context.Context, userID int64) (*model.UserTemplate, error) {
+	if userID != 0 {
+		templates, err := s.repo.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range templates {
+			if templates[i].IsDefault {
+				return &templates[i], nil
+			}
+		}
+	}
+	return s.repo.GetDefaultTemplate(ctx)
+}
+
+// Reorder 按 ids 的顺序重新排列用户的模板
+// ids 必须恰好包含用户的全部模板，否则返回 ErrTemplateOrderMismatch
+func (s *UserTemplateService) Reorder(ctx context.Context, userID int64, ids []int64) error {
+	templates, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(ids) != len(templates) {
+		return ErrTemplateOrderMismatch
+	}
+
+	owned := make(map[int64]bool, len(templates))
+	for _, template := range templates {
+		owned[template.ID] = true
+	}
+	for _, id := range ids {
+		if !owned[id] {
+			return ErrTemplateOrderMismatch
+		}
+		// 删除已匹配的 ID，重复出现时视为不一致
+		delete(owned, id)
+	}
+
+	return s.repo.UpdateSortOrders(ctx, userID, ids)
+}
+
+// SetFavorite 设置模板的收藏状态
+func (s *UserTemplateService) SetFavorite(ctx context.Context, id int64, favorite bool) error {
+	return s.repo.UpdateFavorite(ctx, id, favorite)
+}
+
+// SetUserDefault 将模板设为用户的个人默认模板，templateID 为 0 时恢复使用全局默认模板
+func (s *UserTemplateService) SetUserDefault(ctx context.Context, userID, templateID int64) error {
+	return s.repo.SetUserDefault(ctx, userID, templateID)
+}
+
+// ValidateTemplateContent 验证模板内容（Mini App API 与聊天命令共用）
+func ValidateTemplateContent(content string) error {
+	if content == "" {
+		return fmt.Errorf("模板内容不能为空")
+	}
+
+	// 使用 utf8.RuneCountInString 正确计算字符数（而不是字节数）
+	// 对于中文字符，每个字符占 3 字节，使用 len() 会导致错误计数
+	charCount := utf8.RuneCountInString(content)
+	if charCount > MaxTemplateContentLength {
+		return fmt.Errorf("模板内容不能超过 %d 字符（当前 %d 字符）", MaxTemplateContentLength, charCount)
+	}
+
+	// 必须包含 {exam} 和 {time}
+	if !strings.Contains(content, "{exam}") {
+		return fmt.Errorf("模板必须包含 {exam} 变量")
+	}
+
+	if !strings.Contains(content, "{time}") {
+		return fmt.Errorf("模板必须包含 {time} 变量")
+	}
+
+	return nil
+}
+
+// ValidateTemplateName 验证模板名称（Mini App API 与聊天命令共用）
+func ValidateTemplateName(name string) error {
+	// 使用 utf8.RuneCountInString 正确计算字符数（而不是字节数）
+	charCount := utf8.RuneCountInString(name)
+	if charCount > MaxTemplateNameLength {
+		return fmt.Errorf("模板标题不能超过 %d 字符（当前 %d 字符）", MaxTemplateNameLength, charCount)
+	}
+	return nil
 }
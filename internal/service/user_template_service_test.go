@@ -1,10 +1,14 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
 	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -39,7 +43,7 @@ func TestUserTemplateService_Create(t *testing.T) {
 		TemplateContent: "距离{exam}还有{time}",
 	}
 
-	err := service.Create(template)
+	err := service.Create(context.Background(), template)
 	if err != nil {
 		t.Errorf("Create() error = %v", err)
 	}
@@ -55,7 +59,7 @@ func TestUserTemplateService_GetByID(t *testing.T) {
 		TemplateContent: "距离{exam}还有{time}",
 	})
 
-	result, err := service.GetByID(1)
+	result, err := service.GetByID(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetByID() error = %v", err)
 	}
@@ -88,7 +92,7 @@ func TestUserTemplateService_GetByUserID(t *testing.T) {
 		TemplateContent: "{exam}倒计时{time}",
 	})
 
-	result, err := service.GetByUserID(userID)
+	result, err := service.GetByUserID(context.Background(), userID)
 	if err != nil {
 		t.Errorf("GetByUserID() error = %v", err)
 	}
@@ -110,12 +114,12 @@ func TestUserTemplateService_Update(t *testing.T) {
 	db.Create(template)
 
 	template.TemplateName = "新模板"
-	err := service.Update(template)
+	err := service.Update(context.Background(), template)
 	if err != nil {
 		t.Errorf("Update() error = %v", err)
 	}
 
-	updated, _ := service.GetByID(1)
+	updated, _ := service.GetByID(context.Background(), 1)
 	if updated.TemplateName != "新模板" {
 		t.Errorf("TemplateName = %s, want %s", updated.TemplateName, "新模板")
 	}
@@ -131,12 +135,12 @@ func TestUserTemplateService_Delete(t *testing.T) {
 		TemplateContent: "距离{exam}还有{time}",
 	})
 
-	err := service.Delete(1)
+	err := service.Delete(context.Background(), 1)
 	if err != nil {
 		t.Errorf("Delete() error = %v", err)
 	}
 
-	result, _ := service.GetByID(1)
+	result, _ := service.GetByID(context.Background(), 1)
 	if result != nil {
 		t.Error("Template should be deleted")
 	}
@@ -156,7 +160,7 @@ func TestUserTemplateService_CountByUserID(t *testing.T) {
 		})
 	}
 
-	count, err := service.CountByUserID(userID)
+	count, err := service.CountByUserID(context.Background(), userID)
 	if err != nil {
 		t.Errorf("CountByUserID() error = %v", err)
 	}
@@ -176,7 +180,7 @@ func TestUserTemplateService_GetDefaultTemplate(t *testing.T) {
 		TemplateContent: "距离{exam}还有{time}",
 	})
 
-	result, err := service.GetDefaultTemplate()
+	result, err := service.GetDefaultTemplate(context.Background())
 	if err != nil {
 		t.Errorf("GetDefaultTemplate() error = %v", err)
 	}
@@ -190,3 +194,126 @@ func TestUserTemplateService_GetDefaultTemplate(t *testing.T) {
 		t.Errorf("Expected UserID = 0, got %d", result.UserID)
 	}
 }
+
+func TestUserTemplateService_PersonalDefault(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 0, TemplateContent: "默认{exam}{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: 123, TemplateContent: "个人{exam}{time}"})
+	db.Create(&model.UserTemplate{ID: 3, UserID: 123, TemplateContent: "其他{exam}{time}"})
+
+	if result, _ := service.GetDefaultTemplateForUser(ctx, 123); result == nil || result.ID != 1 {
+		t.Errorf("GetDefaultTemplateForUser() without personal default = %+v, want global default", result)
+	}
+
+	if err := service.SetUserDefault(ctx, 123, 2); err != nil {
+		t.Fatalf("SetUserDefault() error = %v", err)
+	}
+	if result, _ := service.GetDefaultTemplateForUser(ctx, 123); result == nil || result.ID != 2 {
+		t.Errorf("GetDefaultTemplateForUser() = %+v, want template 2", result)
+	}
+	if result, _ := service.GetDefaultTemplateForUser(ctx, 456); result == nil || result.ID != 1 {
+		t.Errorf("GetDefaultTemplateForUser() other user = %+v, want global default", result)
+	}
+}
+
+func TestUserTemplateService_Reorder(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateContent: "{exam}{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: 123, TemplateContent: "{exam}{time}"})
+	db.Create(&model.UserTemplate{ID: 3, UserID: 456, TemplateContent: "{exam}{time}"})
+
+	for _, ids := range [][]int64{{1}, {1, 3}, {1, 1}, {1, 2, 3}} {
+		if err := service.Reorder(ctx, 123, ids); !errors.Is(err, ErrTemplateOrderMismatch) {
+			t.Errorf("Reorder(%v) error = %v, want ErrTemplateOrderMismatch", ids, err)
+		}
+	}
+
+	if err := service.Reorder(ctx, 123, []int64{2, 1}); err != nil {
+		t.Fatalf("Reorder() error = %v", err)
+	}
+	if templates, _ := service.GetByUserID(ctx, 123); templates[0].ID != 2 || templates[1].ID != 1 {
+		t.Errorf("GetByUserID() after reorder = %+v", templates)
+	}
+
+	// 收藏后排在最前
+	if err := service.SetFavorite(ctx, 1, true); err != nil {
+		t.Fatalf("SetFavorite() error = %v", err)
+	}
+	if templates, _ := service.GetByUserID(ctx, 123); templates[0].ID != 1 {
+		t.Errorf("GetByUserID() after favorite = %+v", templates)
+	}
+}
+
+func TestUserTemplateService_Revisions(t *testing.T) {
+	_ = util.InitSnowflake(0, 1)
+	service, db := setupTestService(t)
+	if err := db.AutoMigrate(&model.TemplateRevision{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	service.WithRevisions(repository.NewTemplateRevisionRepository(db))
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateContent: "版本0{exam}{time}"})
+	db.Create(&model.UserTemplate{ID: 2, UserID: 123, TemplateContent: "{exam}{time}"})
+
+	// 每次修改保存修改前的版本，最多保留 MaxRevisionsPerTemplate 条
+	for i := 1; i <= MaxRevisionsPerTemplate+2; i++ {
+		template, _ := service.GetByID(ctx, 1)
+		template.TemplateContent = fmt.Sprintf("版本%d{exam}{time}", i)
+		if err := service.Update(ctx, template); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	revisions, err := service.GetRevisions(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetRevisions() error = %v", err)
+	}
+	if len(revisions) != MaxRevisionsPerTemplate || revisions[0].TemplateContent != fmt.Sprintf("版本%d{exam}{time}", MaxRevisionsPerTemplate+1) {
+		t.Fatalf("GetRevisions() = %d revisions, newest %+v", len(revisions), revisions[0])
+	}
+
+	// 内容未变化时不记录
+	template, _ := service.GetByID(ctx, 1)
+	_ = service.Update(ctx, template)
+	if again, _ := service.GetRevisions(ctx, 1); again[0].ID != revisions[0].ID {
+		t.Error("Update() without changes should not record a revision")
+	}
+
+	// 不能恢复其他模板的修订记录
+	other, _ := service.GetByID(ctx, 2)
+	if err := service.RestoreRevision(ctx, other, revisions[0].ID); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("RestoreRevision() other template error = %v, want ErrRevisionNotFound", err)
+	}
+
+	if err := service.RestoreRevision(ctx, template, revisions[len(revisions)-1].ID); err != nil {
+		t.Fatalf("RestoreRevision() error = %v", err)
+	}
+	if restored, _ := service.GetByID(ctx, 1); restored.TemplateContent != "版本2{exam}{time}" {
+		t.Errorf("TemplateContent after restore = %q, want 版本2{exam}{time}", restored.TemplateContent)
+	}
+
+	if err := service.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if revisions, _ := service.GetRevisions(ctx, 1); len(revisions) != 0 {
+		t.Errorf("GetRevisions() after delete = %d, want 0", len(revisions))
+	}
+}
+
+func TestUserTemplateService_RevisionsDisabled(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateContent: "{exam}{time}"})
+
+	if revisions, err := service.GetRevisions(ctx, 1); err != nil || revisions == nil || len(revisions) != 0 {
+		t.Errorf("GetRevisions() = %v, %v, want empty list", revisions, err)
+	}
+	template, _ := service.GetByID(ctx, 1)
+	if err := service.RestoreRevision(ctx, template, 1); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("RestoreRevision() error = %v, want ErrRevisionNotFound", err)
+	}
+}
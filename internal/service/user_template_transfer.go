@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+// TemplateExportVersion 当前模板导出文件版本
+const TemplateExportVersion = 1
+
+// ErrTemplateImportInvalid 导入文件中存在未通过校验的模板，详见 TemplateImportResult.Errors
+var ErrTemplateImportInvalid = errors.New("template import contains invalid entries")
+
+// TemplateExport 模板导出文件，与 Bot 实例无关，可导入到其他环境
+type TemplateExport struct {
+	Version    int                   `json:"version"`
+	ExportedAt time.Time             `json:"exported_at"`
+	Templates  []TemplateExportEntry `json:"templates"`
+}
+
+// TemplateExportEntry 导出的模板，按用户的模板顺序排列
+type TemplateExportEntry struct {
+	Name     string `json:"name"`
+	Content  string `json:"content"`
+	Favorite bool   `json:"favorite,omitempty"`
+}
+
+// TemplateImportError 单个模板的导入错误，Index 为模板在文件中的下标（从 0 开始）
+type TemplateImportError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// TemplateImportResult 模板导入结果
+type TemplateImportResult struct {
+	// Imported 新建的模板
+	Imported []model.UserTemplate `json:"imported"`
+	// Skipped 与已有模板（或文件中靠前的模板）名称和内容均相同而跳过的数量
+	Skipped int `json:"skipped"`
+	// Errors 未通过校验的模板，不为空时不导入任何模板
+	Errors []TemplateImportError `json:"errors"`
+}
+
+// Validate 检查导出文件版本
+func (e *TemplateExport) Validate() error {
+	if e.Version < 1 || e.Version > TemplateExportVersion {
+		return fmt.Errorf("不支持的模板文件版本: %d", e.Version)
+	}
+	return nil
+}
+
+// Export 导出用户的全部模板
+func (s *UserTemplateService) Export(ctx context.Context, userID int64) (*TemplateExport, error) {
+	templates, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &TemplateExport{
+		Version:    TemplateExportVersion,
+		ExportedAt: time.Now(),
+		Templates:  make([]TemplateExportEntry, len(templates)),
+	}
+	for i, template := range templates {
+		export.Templates[i] = TemplateExportEntry{
+			Name:     template.TemplateName,
+			Content:  template.TemplateContent,
+			Favorite: template.IsFavorite,
+		}
+	}
+	return export, nil
+}
+
+// Import 将导出文件中的模板导入到用户名下，全部导入或全部不导入
+// 任一模板未通过校验或内容审核时返回 ErrTemplateImportInvalid 及逐条错误，不产生任何写入（包括审核记录）；
+// 导入后超过 MaxTemplatesPerUser 时返回 repository.ErrTemplateLimitExceeded
+func (s *UserTemplateService) Import(ctx context.Context, userID int64, export *TemplateExport) (*TemplateImportResult, error) {
+	existing, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[[2]string]bool, len(existing)+len(export.Templates))
+	for _, template := range existing {
+		seen[[2]string{template.TemplateName, template.TemplateContent}] = true
+	}
+
+	result := &TemplateImportResult{
+		Imported: []model.UserTemplate{},
+		Errors:   []TemplateImportError{},
+	}
+	var templates []*model.UserTemplate
+	for i, entry := range export.Templates {
+		if err := validateTemplateInput(entry.Name, entry.Content); err != nil {
+			result.Errors = append(result.Errors, TemplateImportError{Index: i, Error: err.Error()})
+			continue
+		}
+		if err := s.CheckVariables(ctx, userID, entry.Content); err != nil {
+			if !isUndefinedVariableError(err) {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, TemplateImportError{Index: i, Error: err.Error()})
+			continue
+		}
+		// 导入整体通过或整体拒绝，未通过审核的条目不记录到复核列表，避免复核通过后创建未导入成功的模板
+		name, content, err := s.CheckModeration(entry.Name, entry.Content)
+		if err != nil {
+			if !isModerationError(err) {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, TemplateImportError{Index: i, Error: err.Error()})
+			continue
+		}
+
+		key := [2]string{name, content}
+		if seen[key] {
+			result.Skipped++
+			continue
+		}
+		seen[key] = true
+
+		id, err := util.GenerateID()
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, &model.UserTemplate{
+			ID:              id,
+			UserID:          userID,
+			TemplateName:    name,
+			TemplateContent: content,
+			IsFavorite:      entry.Favorite,
+		})
+	}
+
+	if len(result.Errors) > 0 {
+		return result, ErrTemplateImportInvalid
+	}
+
+	if err := s.repo.CreateBatchWithLimit(ctx, userID, templates, MaxTemplatesPerUser); err != nil {
+		return nil, err
+	}
+
+	for _, template := range templates {
+		result.Imported = append(result.Imported, *template)
+	}
+	return result, nil
+}
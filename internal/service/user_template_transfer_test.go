@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+func TestUserTemplateService_ExportImport(t *testing.T) {
+	_ = util.InitSnowflake(0, 1)
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	db.Create(&model.UserTemplate{ID: 1, UserID: 123, TemplateName: "简洁", TemplateContent: "距离{exam}还有{time}", SortOrder: 2})
+	db.Create(&model.UserTemplate{ID: 2, UserID: 123, TemplateContent: "{exam}倒计时{time}", SortOrder: 1, IsFavorite: true})
+
+	export, err := service.Export(ctx, 123)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if export.Version != TemplateExportVersion || len(export.Templates) != 2 || !export.Templates[0].Favorite || export.Templates[1].Name != "简洁" {
+		t.Fatalf("Export() = %+v", export)
+	}
+
+	// 导入到其他用户
+	result, err := service.Import(ctx, 456, export)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if len(result.Imported) != 2 || result.Skipped != 0 || len(result.Errors) != 0 {
+		t.Errorf("Import() = %+v", result)
+	}
+	templates, _ := service.GetByUserID(ctx, 456)
+	if len(templates) != 2 || templates[0].TemplateContent != "{exam}倒计时{time}" || !templates[0].IsFavorite {
+		t.Errorf("GetByUserID() after import = %+v", templates)
+	}
+
+	// 重复导入时跳过相同的模板
+	result, err = service.Import(ctx, 456, export)
+	if err != nil || len(result.Imported) != 0 || result.Skipped != 2 {
+		t.Errorf("Import() again = %+v, %v, want 2 skipped", result, err)
+	}
+}
+
+func TestUserTemplateService_ImportInvalid(t *testing.T) {
+	_ = util.InitSnowflake(0, 1)
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	export := &TemplateExport{Version: TemplateExportVersion, Templates: []TemplateExportEntry{
+		{Content: "距离{exam}还有{time}"},
+		{Content: "缺少变量"},
+		{Name: "这是一个非常非常非常非常非常非常长的模板名称", Content: "{exam}{time}"},
+	}}
+	result, err := service.Import(ctx, 123, export)
+	if !errors.Is(err, ErrTemplateImportInvalid) {
+		t.Fatalf("Import() error = %v, want ErrTemplateImportInvalid", err)
+	}
+	if len(result.Errors) != 2 || result.Errors[0].Index != 1 || result.Errors[1].Index != 2 {
+		t.Errorf("Import() errors = %+v", result.Errors)
+	}
+	if count, _ := service.CountByUserID(ctx, 123); count != 0 {
+		t.Errorf("CountByUserID() after invalid import = %d, want 0", count)
+	}
+
+	// 超过数量限制时全部不导入
+	export.Templates = nil
+	for i := 0; i <= MaxTemplatesPerUser; i++ {
+		export.Templates = append(export.Templates, TemplateExportEntry{Name: string(rune('a' + i)), Content: "{exam}{time}"})
+	}
+	if _, err := service.Import(ctx, 123, export); !errors.Is(err, repository.ErrTemplateLimitExceeded) {
+		t.Errorf("Import() over limit error = %v, want ErrTemplateLimitExceeded", err)
+	}
+	if count, _ := service.CountByUserID(ctx, 123); count != 0 {
+		t.Errorf("CountByUserID() after rejected import = %d, want 0", count)
+	}
+}
+
+func TestTemplateExport_Validate(t *testing.T) {
+	for _, version := range []int{0, TemplateExportVersion + 1} {
+		if err := (&TemplateExport{Version: version}).Validate(); err == nil {
+			t.Errorf("Validate() version %d should fail", version)
+		}
+	}
+	if err := (&TemplateExport{Version: TemplateExportVersion}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestUserTemplateService_ImportModeration(t *testing.T) {
+	service, moderationService, _ := setupModerationTestService(t)
+	ctx := context.Background()
+
+	export := &TemplateExport{Version: TemplateExportVersion, Templates: []TemplateExportEntry{
+		{Content: "距离{exam}\u200b还有{time}"},
+		{Content: "@someone_else {exam}{time}"},
+	}}
+	result, err := service.Import(ctx, 123, export)
+	if !errors.Is(err, ErrTemplateImportInvalid) {
+		t.Fatalf("Import() error = %v, want ErrTemplateImportInvalid", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Index != 1 || !strings.Contains(result.Errors[0].Error, "未通过审核") {
+		t.Errorf("Import() errors = %+v", result.Errors)
+	}
+	// 被拒绝的导入不产生审核记录
+	if page, err := moderationService.ListByStatus(ctx, model.ModerationStatusPending, 1, 10); err != nil || page.Total != 0 {
+		t.Errorf("ListByStatus() = %+v, %v, want no records for rejected import", page, err)
+	}
+
+	export.Templates = export.Templates[:1]
+	result, err = service.Import(ctx, 123, export)
+	if err != nil || len(result.Imported) != 1 || result.Imported[0].TemplateContent != "距离{exam}还有{time}" {
+		t.Errorf("Import() = %+v, %v, want sanitized template", result, err)
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/moderation"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+)
+
+const (
+	// MaxVariablesPerUser 每个用户最多可定义的变量数量
+	MaxVariablesPerUser = 20
+
+	// MaxVariableNameLength 变量名称最大长度（字符数）
+	MaxVariableNameLength = 20
+
+	// MaxVariableValueLength 变量值最大长度（字符数）
+	MaxVariableValueLength = 60
+)
+
+// variableNameRegex 变量名称只能包含字母（含中文）、数字和下划线
+var variableNameRegex = regexp.MustCompile(`^[\p{L}\p{N}_]+$`)
+
+// UndefinedVariableError 模板引用了用户未定义的变量
+type UndefinedVariableError struct {
+	Names []string
+}
+
+// Error 返回面向用户的提示
+func (e *UndefinedVariableError) Error() string {
+	refs := make([]string, 0, len(e.Names))
+	for _, name := range e.Names {
+		refs = append(refs, "{"+util.UserVariablePrefix+name+"}")
+	}
+	return fmt.Sprintf("模板引用了未定义的变量 %s，请先添加变量", strings.Join(refs, "、"))
+}
+
+// UserVariableService 用户自定义模板变量服务
+type UserVariableService struct {
+	repo       repository.UserVariableRepository
+	moderation *moderation.Pipeline
+}
+
+// NewUserVariableService 创建用户自定义模板变量服务
+func NewUserVariableService(repo repository.UserVariableRepository) *UserVariableService {
+	return &UserVariableService{repo: repo}
+}
+
+// WithModeration 启用内容审核：保存变量前由 pipeline 检查名称和值，变量值会渲染到模板中，未通过时直接拒绝
+func (s *UserVariableService) WithModeration(pipeline *moderation.Pipeline) *UserVariableService {
+	s.moderation = pipeline
+	return s
+}
+
+// GetByUserID 获取用户的全部变量，按名称排序
+func (s *UserVariableService) GetByUserID(ctx context.Context, userID int64) ([]model.UserVariable, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// GetValues 获取用户的变量名称到值的映射，userID 为 0 时返回空
+func (s *UserVariableService) GetValues(ctx context.Context, userID int64) (map[string]string, error) {
+	if userID == 0 {
+		return nil, nil
+	}
+	variables, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(variables))
+	for _, variable := range variables {
+		values[variable.VarName] = variable.VarValue
+	}
+	return values, nil
+}
+
+// Set 新增或修改用户的变量，调用前应通过 ValidateVariableName 校验名称；变量值保存前清理不可见字符并重新校验
+// 启用内容审核时名称或值未通过审核返回 *ModerationError，新增时超过 MaxVariablesPerUser 返回 repository.ErrVariableLimitExceeded
+func (s *UserVariableService) Set(ctx context.Context, userID int64, name, value string) (*model.UserVariable, error) {
+	value = SanitizeVariableValue(value)
+	if err := ValidateVariableValue(value); err != nil {
+		return nil, err
+	}
+	if s.moderation != nil {
+		nameResult := s.moderation.Moderate(name)
+		valueResult := s.moderation.Moderate(value)
+		if violations := append(nameResult.Violations, valueResult.Violations...); len(violations) > 0 {
+			err := newModerationError(violations)
+			err.Subject = "变量"
+			return nil, err
+		}
+	}
+
+	id, err := util.GenerateID()
+	if err != nil {
+		return nil, err
+	}
+
+	variable := &model.UserVariable{
+		ID:       id,
+		UserID:   userID,
+		VarName:  name,
+		VarValue: value,
+	}
+	if err := s.repo.SaveWithLimit(ctx, variable, MaxVariablesPerUser); err != nil {
+		return nil, err
+	}
+	return variable, nil
+}
+
+// Delete 删除用户的变量，引用该变量的模板渲染时原样显示 {var:名称}
+func (s *UserVariableService) Delete(ctx context.Context, userID int64, name string) error {
+	return s.repo.Delete(ctx, userID, name)
+}
+
+// CheckReferences 检查模板引用的变量是否均已定义，存在未定义的变量时返回 *UndefinedVariableError
+func (s *UserVariableService) CheckReferences(ctx context.Context, userID int64, content string) error {
+	refs := util.UserVariableRefs(content)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	values, err := s.GetValues(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, name := range refs {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return &UndefinedVariableError{Names: missing}
+	}
+	return nil
+}
+
+// renderContext 按用户渲染模板时替换的内容
+type renderContext struct {
+	// variables 用户自定义变量，未定义的变量原样显示
+	variables map[string]string
+	// quote 今日语录，为空时 {quote} 替换为空
+	quote string
+}
+
+// renderCountdown 渲染倒计时模板并替换日历变量、用户自定义变量和今日语录
+func renderCountdown(exam *model.ExamDate, content string, rc renderContext, now time.Time) string {
+	text := util.ApplyCalendar(util.GetCountDownString(exam, content, now), now)
+	text = util.ApplyUserVariables(text, rc.variables)
+	return util.ApplyQuote(text, rc.quote)
+}
+
+// WithVariables 启用用户自定义变量：保存模板前检查引用的变量均已定义
+func (s *UserTemplateService) WithVariables(variables *UserVariableService) *UserTemplateService {
+	s.variables = variables
+	return s
+}
+
+// CheckVariables 检查模板引用的变量是否均已定义，存在未定义的变量时返回 *UndefinedVariableError
+// 应在格式校验通过后、内容审核之前调用；未启用自定义变量时不检查
+func (s *UserTemplateService) CheckVariables(ctx context.Context, userID int64, content string) error {
+	if s.variables == nil {
+		return nil
+	}
+	return s.variables.CheckReferences(ctx, userID, content)
+}
+
+// isUndefinedVariableError 是否为引用了未定义变量的错误
+func isUndefinedVariableError(err error) bool {
+	var variableErr *UndefinedVariableError
+	return errors.As(err, &variableErr)
+}
+
+// ValidateVariableName 验证变量名称
+func ValidateVariableName(name string) error {
+	if name == "" {
+		return fmt.Errorf("变量名称不能为空")
+	}
+
+	if charCount := utf8.RuneCountInString(name); charCount > MaxVariableNameLength {
+		return fmt.Errorf("变量名称不能超过 %d 字符（当前 %d 字符）", MaxVariableNameLength, charCount)
+	}
+
+	if !variableNameRegex.MatchString(name) {
+		return fmt.Errorf("变量名称只能包含文字、数字和下划线")
+	}
+
+	return nil
+}
+
+// SanitizeVariableValue 移除变量值中的不可见字符和首尾空白
+func SanitizeVariableValue(value string) string {
+	return strings.TrimSpace(moderation.Sanitize(value))
+}
+
+// ValidateVariableValue 验证变量值
+func ValidateVariableValue(value string) error {
+	if value == "" {
+		return fmt.Errorf("变量值不能为空")
+	}
+
+	if charCount := utf8.RuneCountInString(value); charCount > MaxVariableValueLength {
+		return fmt.Errorf("变量值不能超过 %d 字符（当前 %d 字符）", MaxVariableValueLength, charCount)
+	}
+
+	return nil
+}
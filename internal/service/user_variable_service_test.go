@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/moderation"
+	"github.com/herbertgao/gaokao_bot/internal/repository"
+	"github.com/herbertgao/gaokao_bot/internal/util"
+	"gorm.io/gorm"
+)
+
+func setupUserVariableTestService(t *testing.T) (*UserVariableService, *gorm.DB) {
+	// 初始化 Snowflake（如果未初始化）
+	_ = util.InitSnowflake(0, 1)
+
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&model.UserVariable{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return NewUserVariableService(repository.NewUserVariableRepository(db)), db
+}
+
+func TestUserVariableService_SetAndGetValues(t *testing.T) {
+	service, _ := setupUserVariableTestService(t)
+	ctx := context.Background()
+
+	if _, err := service.Set(ctx, 123, "name", "小明"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	variable, err := service.Set(ctx, 123, "name", "小刚")
+	if err != nil || variable.ID == 0 || variable.VarValue != "小刚" {
+		t.Fatalf("Set() update = %+v, %v", variable, err)
+	}
+
+	values, err := service.GetValues(ctx, 123)
+	if err != nil || len(values) != 1 || values["name"] != "小刚" {
+		t.Errorf("GetValues() = %v, %v", values, err)
+	}
+	if values, _ := service.GetValues(ctx, 0); values != nil {
+		t.Errorf("GetValues(0) = %v, want nil", values)
+	}
+
+	for i := 1; i < MaxVariablesPerUser; i++ {
+		if _, err := service.Set(ctx, 123, "v"+strings.Repeat("x", i), "值"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if _, err := service.Set(ctx, 123, "extra", "值"); !errors.Is(err, repository.ErrVariableLimitExceeded) {
+		t.Errorf("Set() over limit error = %v, want ErrVariableLimitExceeded", err)
+	}
+
+	if err := service.Delete(ctx, 123, "name"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if values, _ := service.GetValues(ctx, 123); values["name"] != "" {
+		t.Errorf("GetValues() after delete = %v", values)
+	}
+}
+
+func TestUserVariableService_SetModeration(t *testing.T) {
+	service, _ := setupUserVariableTestService(t)
+	service.WithModeration(moderation.New(moderation.Options{BannedWords: []string{"傻瓜|shagua"}}))
+	ctx := context.Background()
+
+	// 保存前清理不可见字符
+	variable, err := service.Set(ctx, 123, "name", " 小\u200b明\u202e ")
+	if err != nil || variable.VarValue != "小明" {
+		t.Fatalf("Set() = %+v, %v, want sanitized value", variable, err)
+	}
+	if _, err := service.Set(ctx, 123, "empty", "\u200b"); err == nil {
+		t.Error("Set() with only invisible characters should fail")
+	}
+
+	// 名称或值未通过审核时拒绝保存，提示不包含命中的内容
+	for _, tc := range []struct{ name, value string }{{"name", "sha gua"}, {"傻瓜", "小明"}} {
+		_, err := service.Set(ctx, 123, tc.name, tc.value)
+		var moderationErr *ModerationError
+		if !errors.As(err, &moderationErr) || !strings.HasPrefix(err.Error(), "变量未通过审核") || strings.Contains(err.Error(), "傻瓜") {
+			t.Errorf("Set(%q, %q) error = %v, want ModerationError", tc.name, tc.value, err)
+		}
+	}
+	if values, _ := service.GetValues(ctx, 123); len(values) != 1 || values["name"] != "小明" {
+		t.Errorf("GetValues() = %v, want only the sanitized value", values)
+	}
+}
+
+func TestUserVariableService_CheckReferences(t *testing.T) {
+	service, _ := setupUserVariableTestService(t)
+	ctx := context.Background()
+	_, _ = service.Set(ctx, 123, "name", "小明")
+
+	if err := service.CheckReferences(ctx, 123, "{var:name}，距离{exam}还有{time}"); err != nil {
+		t.Errorf("CheckReferences() error = %v", err)
+	}
+
+	err := service.CheckReferences(ctx, 123, "{var:name}{var:goal}{var:school}{var:goal}")
+	var variableErr *UndefinedVariableError
+	if !errors.As(err, &variableErr) || len(variableErr.Names) != 2 {
+		t.Fatalf("CheckReferences() error = %v, want UndefinedVariableError", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "{var:goal}、{var:school}") {
+		t.Errorf("Error() = %q", msg)
+	}
+
+	// 其他用户的变量不可引用
+	if err := service.CheckReferences(ctx, 456, "{var:name}"); !isUndefinedVariableError(err) {
+		t.Errorf("CheckReferences() for another user error = %v", err)
+	}
+}
+
+func TestUserTemplateService_CheckVariables(t *testing.T) {
+	variableService, db := setupUserVariableTestService(t)
+	templateService := NewUserTemplateService(repository.NewUserTemplateRepository(db))
+
+	// 未启用自定义变量时不检查
+	if err := templateService.CheckVariables(context.Background(), 123, "{var:name}"); err != nil {
+		t.Errorf("CheckVariables() without variables = %v", err)
+	}
+
+	templateService.WithVariables(variableService)
+	if err := templateService.CheckVariables(context.Background(), 123, "{var:name}"); !isUndefinedVariableError(err) {
+		t.Errorf("CheckVariables() error = %v, want UndefinedVariableError", err)
+	}
+}
+
+func TestValidateVariableName(t *testing.T) {
+	for _, name := range []string{"name", "目标", "goal_2", strings.Repeat("长", MaxVariableNameLength)} {
+		if err := ValidateVariableName(name); err != nil {
+			t.Errorf("ValidateVariableName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "my name", "a:b", "{x}", "a-b", strings.Repeat("长", MaxVariableNameLength+1)} {
+		if err := ValidateVariableName(name); err == nil {
+			t.Errorf("ValidateVariableName(%q) should fail", name)
+		}
+	}
+}
+
+func TestValidateVariableValue(t *testing.T) {
+	if err := ValidateVariableValue("清华大学"); err != nil {
+		t.Errorf("ValidateVariableValue() error = %v", err)
+	}
+	for _, value := range []string{"", strings.Repeat("长", MaxVariableValueLength+1)} {
+		if err := ValidateVariableValue(value); err == nil {
+			t.Errorf("ValidateVariableValue(%q) should fail", value)
+		}
+	}
+}
+
+func TestTemplateChatService_UndefinedVariable(t *testing.T) {
+	variableService, db := setupUserVariableTestService(t)
+	templateService := NewUserTemplateService(repository.NewUserTemplateRepository(db)).WithVariables(variableService)
+	service := NewTemplateChatService(templateService)
+	ctx := context.Background()
+
+	response, err := service.NewTemplate(ctx, 123, "{var:name}，距离{exam}还有{time}")
+	if err != nil || !strings.Contains(response, "{var:name}") {
+		t.Errorf("NewTemplate() = %q, %v, want undefined variable message", response, err)
+	}
+	if count := countUserTemplates(t, db, 123); count != 0 {
+		t.Errorf("templates = %d, want 0", count)
+	}
+
+	_, _ = variableService.Set(ctx, 123, "name", "小明")
+	if _, err := service.NewTemplate(ctx, 123, "{var:name}，距离{exam}还有{time}"); err != nil {
+		t.Fatalf("NewTemplate() error = %v", err)
+	}
+	if count := countUserTemplates(t, db, 123); count != 1 {
+		t.Errorf("templates = %d, want 1", count)
+	}
+}
+
+func TestUserTemplateService_ImportUndefinedVariable(t *testing.T) {
+	variableService, db := setupUserVariableTestService(t)
+	service := NewUserTemplateService(repository.NewUserTemplateRepository(db)).WithVariables(variableService)
+	_, _ = variableService.Set(context.Background(), 123, "name", "小明")
+
+	export := &TemplateExport{Version: TemplateExportVersion, Templates: []TemplateExportEntry{
+		{Content: "{var:name}距离{exam}还有{time}"},
+		{Content: "{var:goal}距离{exam}还有{time}"},
+	}}
+	result, err := service.Import(context.Background(), 123, export)
+	if !errors.Is(err, ErrTemplateImportInvalid) {
+		t.Fatalf("Import() error = %v, want ErrTemplateImportInvalid", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Index != 1 || !strings.Contains(result.Errors[0].Error, "{var:goal}") {
+		t.Errorf("Import() errors = %+v", result.Errors)
+	}
+}
+
+func TestUserTemplateService_CopyRestoreApproveUndefinedVariable(t *testing.T) {
+	templateService, moderationService, db := setupModerationTestService(t)
+	if err := db.AutoMigrate(&model.UserVariable{}, &model.GalleryTemplate{}, &model.GalleryLike{}, &model.TemplateRevision{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	variableService := NewUserVariableService(repository.NewUserVariableRepository(db))
+	templateService.WithVariables(variableService).WithRevisions(repository.NewTemplateRevisionRepository(db))
+	galleryService := NewGalleryService(repository.NewGalleryRepository(db), templateService)
+	shareService := NewTemplateShareService(templateService, "gaokao_bot")
+	ctx := context.Background()
+
+	if _, err := variableService.Set(ctx, 123, "name", "小明"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	template := &model.UserTemplate{ID: 1, UserID: 123, TemplateName: "模板", TemplateContent: "{var:name}距离{exam}还有{time}"}
+	db.Create(template)
+
+	// 复制者未定义模板引用的变量
+	item, _ := galleryService.Publish(ctx, template)
+	_, _ = galleryService.Approve(ctx, item.ID)
+	if _, err := galleryService.Copy(ctx, item.ID, 456); !isUndefinedVariableError(err) {
+		t.Errorf("GalleryService.Copy() error = %v, want UndefinedVariableError", err)
+	}
+	code, _ := shareService.Share(ctx, template)
+	if _, err := shareService.Copy(ctx, code, 456); !isUndefinedVariableError(err) {
+		t.Errorf("TemplateShareService.Copy() error = %v, want UndefinedVariableError", err)
+	}
+
+	// 修订版本引用的变量已删除
+	template.TemplateContent = "距离{exam}还有{time}"
+	if err := templateService.Update(ctx, template); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	_, _, _ = templateService.ModerateInput(ctx, 123, 0, "", "傻瓜{var:name}{exam}{time}")
+	if err := variableService.Delete(ctx, 123, "name"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	revisions, _ := templateService.GetRevisions(ctx, 1)
+	if err := templateService.RestoreRevision(ctx, template, revisions[0].ID); !isUndefinedVariableError(err) {
+		t.Errorf("RestoreRevision() error = %v, want UndefinedVariableError", err)
+	}
+
+	// 复核通过时变量已删除，不创建模板，记录保持待复核
+	page, _ := moderationService.ListByStatus(ctx, model.ModerationStatusPending, 1, 10)
+	if page.Total != 1 {
+		t.Fatalf("ListByStatus() total = %d, want 1", page.Total)
+	}
+	if _, err := moderationService.Approve(ctx, page.Items[0].ID); !isUndefinedVariableError(err) {
+		t.Errorf("Approve() error = %v, want UndefinedVariableError", err)
+	}
+	if count, _ := templateService.CountByUserID(ctx, 123); count != 1 {
+		t.Errorf("CountByUserID() = %d, want 1", count)
+	}
+	if count, _ := templateService.CountByUserID(ctx, 456); count != 0 {
+		t.Errorf("CountByUserID(456) = %d, want 0", count)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
@@ -16,7 +17,7 @@ import (
 )
 
 const (
-	// DefaultContextTimeout 默认上下文超时时间
+	// DefaultContextTimeout 单条消息发送的超时时间
 	DefaultContextTimeout = 10 * time.Second
 )
 
@@ -25,30 +26,83 @@ type DailySendTask struct {
 	cron                *cron.Cron
 	bot                 *telego.Bot
 	examDateService     *service.ExamDateService
+	examEventService    *service.ExamEventService
 	userTemplateService *service.UserTemplateService
 	sendChatService     *service.SendChatService
+	officialService     *service.OfficialTemplateService
+	variableService     *service.UserVariableService
+	quoteService        *service.QuoteService
+	appendQuote         bool // 是否在推送末尾附带今日语录
+	appendCalendar      bool // 是否在推送开头附带日历行
+	rotateTemplates     bool // 是否按推送对话的模板池每日轮换模板
 	logger              *logrus.Logger
+	timeout             time.Duration
+	ctx                 context.Context    // 停止任务时取消，中断正在执行的查询和推送
+	cancel              context.CancelFunc // 用于取消 ctx
 }
 
 // NewDailySendTask 创建每日发送任务
+// examEventService 为 nil 时不推送考后事件提醒；timeout 为单次执行的超时，不大于 0 时不限
 func NewDailySendTask(
 	bot *telego.Bot,
 	examDateService *service.ExamDateService,
+	examEventService *service.ExamEventService,
 	userTemplateService *service.UserTemplateService,
 	sendChatService *service.SendChatService,
 	logger *logrus.Logger,
+	timeout time.Duration,
 ) *DailySendTask {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &DailySendTask{
 		// 使用北京时区初始化 cron，确保定时任务与 shouldSend() 的时区判断一致
 		cron:                cron.New(cron.WithSeconds(), cron.WithLocation(util.GetBJTLocation())),
 		bot:                 bot,
 		examDateService:     examDateService,
+		examEventService:    examEventService,
 		userTemplateService: userTemplateService,
 		sendChatService:     sendChatService,
 		logger:              logger,
+		timeout:             timeout,
+		ctx:                 ctx,
+		cancel:              cancel,
 	}
 }
 
+// WithOfficialTemplates 启用官方模板：选用了官方模板的推送对话在模板生效期间使用该模板，
+// 模板被删除或未生效时回退到默认模板
+func (t *DailySendTask) WithOfficialTemplates(officialService *service.OfficialTemplateService) *DailySendTask {
+	t.officialService = officialService
+	return t
+}
+
+// WithUserVariables 启用用户自定义变量：推送到私聊（对话 ID 即用户 ID）时将 {var:名称} 替换为该用户定义的值
+func (t *DailySendTask) WithUserVariables(variableService *service.UserVariableService) *DailySendTask {
+	t.variableService = variableService
+	return t
+}
+
+// WithQuotes 启用励志语录：将 {quote} 替换为各对话的今日语录，appendDaily 为 true 时在推送末尾附带今日语录
+// 模板中已使用 {quote} 时不再重复附带
+func (t *DailySendTask) WithQuotes(quoteService *service.QuoteService, appendDaily bool) *DailySendTask {
+	t.quoteService = quoteService
+	t.appendQuote = appendDaily
+	return t
+}
+
+// WithCalendar appendDaily 为 true 时在推送开头附带当天的农历日期、星期和节气
+// 模板中已使用 {lunar_date}、{solar_term} 或 {weekday} 时不再重复附带
+func (t *DailySendTask) WithCalendar(appendDaily bool) *DailySendTask {
+	t.appendCalendar = appendDaily
+	return t
+}
+
+// WithTemplateRotation 启用模板轮换：设置了模板池和轮换策略的推送对话每天从模板池中选取一个模板，
+// 选中的模板被删除或官方模板对考试未生效时回退到对话原有的模板
+func (t *DailySendTask) WithTemplateRotation() *DailySendTask {
+	t.rotateTemplates = true
+	return t
+}
+
 // Start 启动定时任务
 func (t *DailySendTask) Start(cronExpr string) error {
 	_, err := t.cron.AddFunc(cronExpr, t.execute)
@@ -61,9 +115,10 @@ func (t *DailySendTask) Start(cronExpr string) error {
 	return nil
 }
 
-// Stop 停止定时任务
+// Stop 停止定时任务，取消正在执行的任务并等待其退出
 func (t *DailySendTask) Stop() {
-	t.cron.Stop()
+	t.cancel()
+	<-t.cron.Stop().Done()
 	t.logger.Info("每日发送任务已停止")
 }
 
@@ -72,8 +127,16 @@ func (t *DailySendTask) execute() {
 	// 获取当前时间（用于判断是否发送）
 	now := util.NowBJT()
 
-	// 获取符合条件的考试
-	exams, err := t.examDateService.GetExamsInRange(now)
+	ctx, cancel := t.executeContext()
+	defer cancel()
+
+	// 考后事件提醒：每天早上推送当天的事件
+	if t.examEventService != nil && isMorningSendTime(now) {
+		t.sendEventReminders(ctx, now)
+	}
+
+	// 获取考试年范围包含当前时间的全国统一考试（推送对话不区分地区）
+	exams, err := t.examDateService.GetActive(ctx, service.NationwideExams(), now)
 	if err != nil {
 		t.logger.Errorf("获取考试列表失败: %v", err)
 		return
@@ -83,65 +146,279 @@ func (t *DailySendTask) execute() {
 		return
 	}
 
+	// 获取默认模板
+	template, err := t.userTemplateService.GetDefaultTemplate(ctx)
+	if err != nil {
+		t.logger.Errorf("获取默认模板失败: %v", err)
+		return
+	}
+
+	templateContent := "现在距离{exam}还有{time}"
+	if template != nil {
+		templateContent = template.TemplateContent
+	}
+
+	// 时间标准化：仅用于倒计时显示，防止出现"3天23小时59分59秒"等情况
+	normalizedNow := util.NormalizeToMinute(now)
+
+	// 同一时刻触发的多个考试按开始时间排序，合并为一条列表消息
+	var sendExams []model.ExamDate
 	for _, exam := range exams {
 		if !t.shouldSend(exam, now) {
 			continue
 		}
 
-		// 获取默认模板
-		template, err := t.userTemplateService.GetDefaultTemplate()
-		if err != nil {
-			t.logger.Errorf("获取默认模板失败: %v", err)
-			continue
+		if util.IsExamBeginTime(&exam, now) {
+			t.logger.Infof("开考推送已触发: exam=%s now=%v begin=%v offset=%v",
+				exam.ExamDesc, now, exam.ExamBeginDate, now.Sub(exam.ExamBeginDate))
 		}
 
-		templateContent := "现在距离{exam}还有{time}"
-		if template != nil {
-			templateContent = template.TemplateContent
+		sendExams = append(sendExams, exam)
+	}
+
+	if len(sendExams) == 0 {
+		return
+	}
+
+	if t.officialService == nil && t.variableService == nil && t.quoteService == nil && !t.rotateTemplates {
+		t.broadcast(ctx, t.applyCalendar(t.buildListMessage(sendExams, now, normalizedNow, templateContent), now))
+		return
+	}
+
+	chats, err := t.sendChatService.GetAll(ctx)
+	if err != nil {
+		t.logger.Errorf("获取聊天列表失败: %v", err)
+		return
+	}
+
+	// 获取官方模板失败时全部使用默认模板
+	var officialTemplates []model.OfficialTemplate
+	if t.officialService != nil {
+		if officialTemplates, err = t.officialService.List(ctx); err != nil {
+			t.logger.Errorf("获取官方模板失败: %v", err)
 		}
+	}
 
-		// 时间标准化：仅用于倒计时显示，防止出现"3天23小时59分59秒"等情况
-		normalizedNow := util.NormalizeToMinute(now)
+	// 获取模板池失败时不轮换
+	var pools map[int64][]model.SendChatTemplate
+	if t.rotateTemplates {
+		if pools, err = t.sendChatService.GetAllTemplatePools(ctx); err != nil {
+			t.logger.Errorf("获取模板池失败: %v", err)
+		}
+	}
 
-		if util.IsExamBeginTime(&exam, now) {
-			t.logger.Infof("开考推送已触发: exam=%s now=%v begin=%v offset=%v",
-				exam.ExamDesc, now, exam.ExamBeginDate, now.Sub(exam.ExamBeginDate))
+	for _, group := range t.groupChatMessages(ctx, chats, sendExams, now, normalizedNow, templateContent, officialTemplates, pools) {
+		t.sendTo(ctx, group.chats, group.message)
+	}
+}
+
+// chatMessage 内容相同的推送消息及其目标对话
+type chatMessage struct {
+	message string
+	chats   []model.SendChat
+}
+
+// groupChatMessages 按各对话选用（或当天轮换到）的模板、日历、用户的自定义变量和今日语录生成推送消息，
+// 内容相同的对话合并为一组；分组按首个对话的顺序排列
+func (t *DailySendTask) groupChatMessages(
+	ctx context.Context,
+	chats []model.SendChat,
+	exams []model.ExamDate,
+	now, normalizedNow time.Time,
+	defaultContent string,
+	officialTemplates []model.OfficialTemplate,
+	pools map[int64][]model.SendChatTemplate,
+) []chatMessage {
+	templates := make(map[int64]*model.OfficialTemplate, len(officialTemplates))
+	for i := range officialTemplates {
+		templates[officialTemplates[i].ID] = &officialTemplates[i]
+	}
+
+	var groups []chatMessage
+	index := make(map[string]int)
+	for _, chat := range chats {
+		// 轮换到的模板对某场考试未生效时，回退到对话自己选用的模板
+		rotated := t.rotationTemplate(ctx, &chat, pools[chat.ID], templates, now)
+		message := t.applyCalendar(t.buildListMessage(exams, now, normalizedNow, defaultContent, rotated, templates[chat.TemplateID]), now)
+		message = util.ApplyUserVariables(message, t.chatVariables(ctx, chat))
+		message = t.applyQuote(ctx, message, chat, now)
+		i, ok := index[message]
+		if !ok {
+			i = len(groups)
+			index[message] = i
+			groups = append(groups, chatMessage{message: message})
 		}
+		groups[i].chats = append(groups[i].chats, chat)
+	}
+	return groups
+}
 
-		message := t.buildMessage(&exam, now, normalizedNow, templateContent)
+// rotationTemplate 获取对话当天从模板池轮换到的模板，个人模板视为始终生效的官方模板
+// 未启用轮换、模板池为空或选中的模板已被删除时返回 nil
+func (t *DailySendTask) rotationTemplate(
+	ctx context.Context,
+	chat *model.SendChat,
+	pool []model.SendChatTemplate,
+	officialTemplates map[int64]*model.OfficialTemplate,
+	now time.Time,
+) *model.OfficialTemplate {
+	if !t.rotateTemplates {
+		return nil
+	}
+	entry, err := t.sendChatService.RotateTemplate(ctx, chat, pool, now)
+	if err != nil {
+		t.logger.Errorf("记录对话 %s 的模板轮换失败: %v", chat.ChatID, err)
+	}
+	if entry == nil {
+		return nil
+	}
 
-		// 获取发送目标
-		chats, err := t.sendChatService.GetAll()
+	switch entry.Source {
+	case model.ChatTemplateSourceOfficial:
+		return officialTemplates[entry.TemplateID]
+	case model.ChatTemplateSourceUser:
+		template, err := t.userTemplateService.GetByID(ctx, entry.TemplateID)
 		if err != nil {
-			t.logger.Errorf("获取聊天列表失败: %v", err)
-			continue
+			t.logger.Errorf("获取对话 %s 轮换的个人模板失败: %v", chat.ChatID, err)
+			return nil
 		}
+		if template == nil {
+			return nil
+		}
+		return &model.OfficialTemplate{ID: template.ID, TemplateContent: template.TemplateContent, Active: true}
+	}
+	return nil
+}
 
-		// 发送消息
-		for _, chat := range chats {
-			chatID, err := strconv.ParseInt(chat.ChatID, 10, 64)
-			if err != nil {
-				t.logger.Errorf("无效的聊天ID %s: %v", chat.ChatID, err)
-				continue
-			}
+// chatVariables 获取对话所属用户的自定义变量：私聊为对话用户，群组和频道为设置的对话管理员；
+// 无所属用户或获取失败时返回空（变量原样显示）
+func (t *DailySendTask) chatVariables(ctx context.Context, chat model.SendChat) map[string]string {
+	if t.variableService == nil {
+		return nil
+	}
+	userID, err := strconv.ParseInt(chat.ChatID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	if userID <= 0 {
+		userID = chat.AdminUserID
+	}
+	if userID <= 0 {
+		return nil
+	}
+	values, err := t.variableService.GetValues(ctx, userID)
+	if err != nil {
+		t.logger.Errorf("获取对话 %s 的用户变量失败: %v", chat.ChatID, err)
+		return nil
+	}
+	return values
+}
+
+// applyCalendar 将日历变量替换为当天的农历日期、节气和星期，并按配置在开头附带日历行
+// 应在用户自定义变量之前调用，变量值中的日历变量不会被替换
+func (t *DailySendTask) applyCalendar(message string, now time.Time) string {
+	if !t.appendCalendar || util.HasCalendarPlaceholder(message) {
+		return util.ApplyCalendar(message, now)
+	}
+	return util.FormatCalendarLine(now) + "\n" + message
+}
 
-			// 使用带超时的 context 防止 API 调用挂起
-			ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
-			sentMsg, err := t.bot.SendMessage(ctx, telegoutil.Message(
-				telegoutil.ID(chatID),
-				message,
-			))
-			cancel()
-
-			if err != nil {
-				t.logger.Errorf("发送消息到聊天 %s 失败: %v", chat.ChatID, err)
-			} else if t.logger.Level >= logrus.DebugLevel {
-				// Debug 模式下打印发送的消息
-				t.logger.Debugf("[Telegram] -> Sent daily task message to Chat %d (MsgID: %d)",
-					chatID,
-					sentMsg.MessageID)
+// applyQuote 将 {quote} 替换为对话的今日语录，并按配置在末尾附带语录；获取失败时 {quote} 替换为空
+func (t *DailySendTask) applyQuote(ctx context.Context, message string, chat model.SendChat, now time.Time) string {
+	if t.quoteService == nil {
+		return message
+	}
+	quote, err := t.quoteService.DailyQuote(ctx, chat.ChatID, now)
+	if err != nil {
+		t.logger.Errorf("获取对话 %s 的今日语录失败: %v", chat.ChatID, err)
+	}
+	if !t.appendQuote || quote == "" || strings.Contains(message, util.QuotePlaceholder) {
+		return util.ApplyQuote(message, quote)
+	}
+	return message + "\n\n" + quote
+}
+
+// buildListMessage 生成多个考试的推送消息
+// 每场考试按顺序使用 officials 中第一个对其生效的官方模板（跳过 nil），均未生效时使用默认模板
+func (t *DailySendTask) buildListMessage(exams []model.ExamDate, now, normalizedNow time.Time, defaultContent string, officials ...*model.OfficialTemplate) string {
+	messages := make([]string, 0, len(exams))
+	for i := range exams {
+		content := defaultContent
+		for _, official := range officials {
+			if official != nil && service.IsOfficialTemplateActive(official, &exams[i], now) {
+				content = official.TemplateContent
+				break
 			}
 		}
+		messages = append(messages, t.buildMessage(&exams[i], now, normalizedNow, content))
+	}
+	return util.FormatCountdownList(messages)
+}
+
+// executeContext 创建单次执行的 context，任务停止时取消
+func (t *DailySendTask) executeContext() (context.Context, context.CancelFunc) {
+	if t.timeout <= 0 {
+		return context.WithCancel(t.ctx)
+	}
+	return context.WithTimeout(t.ctx, t.timeout)
+}
+
+// sendEventReminders 推送当天的考后事件
+func (t *DailySendTask) sendEventReminders(ctx context.Context, now time.Time) {
+	timelines, err := t.examEventService.GetTimelinesOnDay(ctx, now)
+	if err != nil {
+		t.logger.Errorf("获取考后事件失败: %v", err)
+		return
+	}
+
+	for _, timeline := range timelines {
+		t.broadcast(ctx, "今日提醒\n"+util.FormatExamTimeline(timeline.Title, timeline.Events, now))
+	}
+}
+
+// broadcast 发送消息到所有推送对话
+func (t *DailySendTask) broadcast(ctx context.Context, message string) {
+	// 获取发送目标
+	chats, err := t.sendChatService.GetAll(ctx)
+	if err != nil {
+		t.logger.Errorf("获取聊天列表失败: %v", err)
+		return
+	}
+
+	t.sendTo(ctx, chats, message)
+}
+
+// sendTo 发送消息到指定的推送对话
+func (t *DailySendTask) sendTo(ctx context.Context, chats []model.SendChat, message string) {
+	for _, chat := range chats {
+		// 任务已停止或本次执行超时，放弃剩余的推送
+		if ctx.Err() != nil {
+			t.logger.Errorf("推送中断，剩余对话未发送: %v", ctx.Err())
+			return
+		}
+
+		chatID, err := strconv.ParseInt(chat.ChatID, 10, 64)
+		if err != nil {
+			t.logger.Errorf("无效的聊天ID %s: %v", chat.ChatID, err)
+			continue
+		}
+
+		// 使用带超时的 context 防止 API 调用挂起
+		sendCtx, cancel := context.WithTimeout(ctx, DefaultContextTimeout)
+		sentMsg, err := t.bot.SendMessage(sendCtx, telegoutil.Message(
+			telegoutil.ID(chatID),
+			message,
+		))
+		cancel()
+
+		if err != nil {
+			t.logger.Errorf("发送消息到聊天 %s 失败: %v", chat.ChatID, err)
+		} else if t.logger.Level >= logrus.DebugLevel {
+			// Debug 模式下打印发送的消息
+			t.logger.Debugf("[Telegram] -> Sent daily task message to Chat %d (MsgID: %d)",
+				chatID,
+				sentMsg.MessageID)
+		}
 	}
 }
 
@@ -167,10 +444,15 @@ func (t *DailySendTask) shouldSend(exam model.ExamDate, now time.Time) bool {
 	}
 
 	// 距离考试 > 1 天，仅在 9:00 发送
-	// 允许 1 分钟的时间窗口（9:00-9:01），防止 cron 延迟导致错过发送
-	if hours > 24 && now.Hour() == 9 && now.Minute() <= 1 {
+	if hours > 24 && isMorningSendTime(now) {
 		return true
 	}
 
 	return false
 }
+
+// isMorningSendTime 是否为每日 9:00 推送时刻
+// 允许 1 分钟的时间窗口（9:00-9:01），防止 cron 延迟导致错过发送
+func isMorningSendTime(now time.Time) bool {
+	return now.Hour() == 9 && now.Minute() <= 1
+}
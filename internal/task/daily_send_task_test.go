@@ -1,11 +1,15 @@
 package task
 
 import (
+	"context"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/internal/repository/memory"
+	"github.com/herbertgao/gaokao_bot/internal/service"
 	"github.com/herbertgao/gaokao_bot/internal/util"
 	"github.com/sirupsen/logrus"
 )
@@ -14,7 +18,7 @@ func TestNewDailySendTask(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	task := NewDailySendTask(nil, nil, nil, nil, logger)
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0)
 
 	if task == nil {
 		t.Fatal("NewDailySendTask() returned nil")
@@ -33,7 +37,7 @@ func TestDailySendTask_ShouldSend(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	task := NewDailySendTask(nil, nil, nil, nil, logger)
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0)
 
 	// 使用北京时区（与生产代码保持一致）
 	bjtZone := util.GetBJTLocation()
@@ -162,7 +166,7 @@ func TestDailySendTask_BuildMessage(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	task := NewDailySendTask(nil, nil, nil, nil, logger)
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0)
 	bjtZone := util.GetBJTLocation()
 
 	examBegin := time.Date(2025, 6, 7, 9, 0, 0, 0, bjtZone)
@@ -220,7 +224,7 @@ func TestDailySendTask_Stop(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	task := NewDailySendTask(nil, nil, nil, nil, logger)
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0)
 
 	// 测试 Stop 不会 panic
 	task.Stop()
@@ -229,11 +233,40 @@ func TestDailySendTask_Stop(t *testing.T) {
 	task.Stop()
 }
 
+func TestDailySendTask_ExecuteContext(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, time.Minute)
+
+	ctx, cancel := task.executeContext()
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("Deadline = %v, %v, want within 1 minute", deadline, ok)
+	}
+
+	// 停止任务时取消正在执行的 context
+	task.Stop()
+	if ctx.Err() == nil {
+		t.Error("Expected execute context to be canceled after Stop()")
+	}
+
+	// 未配置超时时不设置截止时间
+	task = NewDailySendTask(nil, nil, nil, nil, nil, logger, 0)
+	ctx, cancel = task.executeContext()
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no deadline when timeout is 0")
+	}
+}
+
 func TestDailySendTask_StartInvalidCron(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	task := NewDailySendTask(nil, nil, nil, nil, logger)
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0)
 
 	// 使用无效的 cron 表达式
 	err := task.Start("invalid cron expression")
@@ -246,7 +279,7 @@ func TestDailySendTask_StartAndStop(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	task := NewDailySendTask(nil, nil, nil, nil, logger)
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0)
 
 	// 使用有效但不会立即触发的 cron 表达式（每年1月1日0:00）
 	// 格式: 秒 分 时 日 月 周
@@ -384,3 +417,294 @@ func TestCountdownWithTimeNormalization(t *testing.T) {
 		})
 	}
 }
+
+func TestIsMorningSendTime(t *testing.T) {
+	bjtZone := util.GetBJTLocation()
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"9:00", time.Date(2026, 6, 25, 9, 0, 0, 0, bjtZone), true},
+		{"9:01", time.Date(2026, 6, 25, 9, 1, 30, 0, bjtZone), true},
+		{"9:02", time.Date(2026, 6, 25, 9, 2, 0, 0, bjtZone), false},
+		{"10:00", time.Date(2026, 6, 25, 10, 0, 0, 0, bjtZone), false},
+	}
+
+	for _, tt := range tests {
+		if got := isMorningSendTime(tt.now); got != tt.want {
+			t.Errorf("isMorningSendTime(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDailySendTask_GroupChatMessages(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0)
+
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, loc)
+	exams := []model.ExamDate{
+		{ExamDesc: "2026年高考", ShortDesc: "2026年高考", ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc), ExamEndDate: time.Date(2026, 6, 10, 17, 0, 0, 0, loc)},
+		{ExamDesc: "2027年高考", ShortDesc: "2027年高考", ExamBeginDate: time.Date(2027, 6, 7, 9, 0, 0, 0, loc), ExamEndDate: time.Date(2027, 6, 10, 17, 0, 0, 0, loc)},
+	}
+	officialTemplates := []model.OfficialTemplate{
+		{ID: 10, TemplateContent: "{exam}百日冲刺：{time}", Active: true, DaysBeforeExam: 100},
+		{ID: 11, TemplateContent: "未启用{exam}{time}"},
+	}
+	chats := []model.SendChat{
+		{ID: 1, ChatID: "-1"},
+		{ID: 2, ChatID: "-2", TemplateID: 10},
+		{ID: 3, ChatID: "-3", TemplateID: 11}, // 未启用，回退到默认模板
+		{ID: 4, ChatID: "-4", TemplateID: 99}, // 已删除，回退到默认模板
+		{ID: 5, ChatID: "-5", TemplateID: 10},
+	}
+
+	groups := task.groupChatMessages(context.Background(), chats, exams, now, now, "距离{exam}还有{time}", officialTemplates, nil)
+	if len(groups) != 2 {
+		t.Fatalf("groupChatMessages() returned %d groups, want 2", len(groups))
+	}
+
+	if ids := sendChatIDs(groups[0].chats); ids != "1,3,4" {
+		t.Errorf("default group chats = %s, want 1,3,4", ids)
+	}
+	if strings.Contains(groups[0].message, "百日冲刺") {
+		t.Errorf("default group message = %q", groups[0].message)
+	}
+
+	// 官方模板只对 100 天内的考试生效，其余考试仍使用默认模板
+	if ids := sendChatIDs(groups[1].chats); ids != "2,5" {
+		t.Errorf("official group chats = %s, want 2,5", ids)
+	}
+	if !strings.Contains(groups[1].message, "2026年高考百日冲刺") || !strings.Contains(groups[1].message, "距离2027年高考还有") {
+		t.Errorf("official group message = %q", groups[1].message)
+	}
+}
+
+func sendChatIDs(chats []model.SendChat) string {
+	ids := make([]string, 0, len(chats))
+	for _, chat := range chats {
+		ids = append(ids, strconv.FormatInt(chat.ID, 10))
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestDailySendTask_GroupChatMessages_UserVariables(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	variables := memory.NewUserVariableRepository()
+	_ = variables.SaveWithLimit(context.Background(), &model.UserVariable{ID: 1, UserID: 123, VarName: "name", VarValue: "小明"}, 10)
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0).
+		WithUserVariables(service.NewUserVariableService(variables))
+
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, loc)
+	exams := []model.ExamDate{
+		{ExamDesc: "2026年高考", ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc), ExamEndDate: time.Date(2026, 6, 10, 17, 0, 0, 0, loc)},
+	}
+	officialTemplates := []model.OfficialTemplate{
+		{ID: 10, TemplateContent: "{var:name}，距离{exam}还有{time}", Active: true},
+	}
+	chats := []model.SendChat{
+		{ID: 1, ChatID: "123", TemplateID: 10},
+		{ID: 2, ChatID: "-100123", TemplateID: 10},
+		{ID: 3, ChatID: "456", TemplateID: 10},
+	}
+
+	// 私聊使用该用户的变量，群组和未定义变量的用户原样显示
+	groups := task.groupChatMessages(context.Background(), chats, exams, now, now, "距离{exam}还有{time}", officialTemplates, nil)
+	if len(groups) != 2 {
+		t.Fatalf("groupChatMessages() returned %d groups, want 2", len(groups))
+	}
+	if ids := sendChatIDs(groups[0].chats); ids != "1" || !strings.HasPrefix(groups[0].message, "小明，距离2026年高考还有") {
+		t.Errorf("private chat group = %s %q", ids, groups[0].message)
+	}
+	if ids := sendChatIDs(groups[1].chats); ids != "2,3" || !strings.HasPrefix(groups[1].message, "{var:name}，") {
+		t.Errorf("other chats group = %s %q", ids, groups[1].message)
+	}
+}
+
+func TestDailySendTask_GroupChatMessages_Quotes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	quotes := memory.NewQuoteRepository()
+	_ = quotes.Create(context.Background(), &model.Quote{ID: 1, Content: "天道酬勤"})
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0).
+		WithQuotes(service.NewQuoteService(quotes, 30), true)
+
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, loc)
+	exams := []model.ExamDate{
+		{ExamDesc: "2026年高考", ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc), ExamEndDate: time.Date(2026, 6, 10, 17, 0, 0, 0, loc)},
+	}
+	officialTemplates := []model.OfficialTemplate{
+		{ID: 10, TemplateContent: "{quote}\n距离{exam}还有{time}", Active: true},
+	}
+	chats := []model.SendChat{
+		{ID: 1, ChatID: "-100123"},
+		{ID: 2, ChatID: "-100456", TemplateID: 10},
+	}
+
+	// 默认模板在末尾附带语录，已使用 {quote} 的模板不再重复附带
+	groups := task.groupChatMessages(context.Background(), chats, exams, now, now, "距离{exam}还有{time}", officialTemplates, nil)
+	if len(groups) != 2 {
+		t.Fatalf("groupChatMessages() returned %d groups, want 2", len(groups))
+	}
+	if !strings.HasPrefix(groups[0].message, "距离2026年高考还有") || !strings.HasSuffix(groups[0].message, "\n\n天道酬勤") {
+		t.Errorf("default template message = %q", groups[0].message)
+	}
+	if !strings.HasPrefix(groups[1].message, "天道酬勤\n距离2026年高考还有") || strings.Count(groups[1].message, "天道酬勤") != 1 {
+		t.Errorf("official template message = %q", groups[1].message)
+	}
+
+	// 未开启附带时只替换 {quote}
+	task.WithQuotes(service.NewQuoteService(quotes, 30), false)
+	groups = task.groupChatMessages(context.Background(), chats, exams, now, now, "距离{exam}还有{time}", officialTemplates, nil)
+	if len(groups) != 2 || strings.Contains(groups[0].message, "天道酬勤") || !strings.HasPrefix(groups[1].message, "天道酬勤\n") {
+		t.Errorf("groups without append = %+v", groups)
+	}
+}
+
+func TestDailySendTask_GroupChatMessages_Rotation(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	chatRepo := memory.NewSendChatRepository()
+	templates := memory.NewUserTemplateRepository()
+	variables := memory.NewUserVariableRepository()
+	_ = chatRepo.Create(ctx, &model.SendChat{ID: 1, ChatID: "-100123", TemplateID: 11})
+	_ = chatRepo.Create(ctx, &model.SendChat{ID: 2, ChatID: "-100456", TemplateID: 11})
+	_ = templates.Create(ctx, &model.UserTemplate{ID: 20, UserID: 7, TemplateContent: "{var:name}：{exam}还有{time}"})
+	_ = variables.SaveWithLimit(ctx, &model.UserVariable{ID: 1, UserID: 7, VarName: "name", VarValue: "三班"}, 10)
+	_ = chatRepo.UpdateRotation(ctx, 1, 7, model.RotationRoundRobin, []*model.SendChatTemplate{
+		{ID: 101, SendChatID: 1, Source: model.ChatTemplateSourceOfficial, TemplateID: 10},
+		{ID: 102, SendChatID: 1, Source: model.ChatTemplateSourceUser, TemplateID: 20},
+		{ID: 103, SendChatID: 1, Source: model.ChatTemplateSourceOfficial, TemplateID: 99}, // 已删除
+	})
+
+	sendChatService := service.NewSendChatService(chatRepo)
+	task := NewDailySendTask(nil, nil, nil, service.NewUserTemplateService(templates), sendChatService, logger, 0).
+		WithUserVariables(service.NewUserVariableService(variables)).
+		WithTemplateRotation()
+
+	loc := util.GetBJTLocation()
+	exams := []model.ExamDate{
+		{ExamDesc: "2026年高考", ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc), ExamEndDate: time.Date(2026, 6, 10, 17, 0, 0, 0, loc)},
+	}
+	officialTemplates := []model.OfficialTemplate{
+		{ID: 10, TemplateContent: "轮换{exam}还有{time}", Active: true},
+		{ID: 11, TemplateContent: "原有{exam}还有{time}", Active: true},
+	}
+
+	messageOn := func(day int) string {
+		now := time.Date(2026, 3, day, 9, 0, 0, 0, loc)
+		chats, _ := sendChatService.GetAll(ctx)
+		pools, _ := sendChatService.GetAllTemplatePools(ctx)
+		groups := task.groupChatMessages(ctx, chats, exams, now, now, "距离{exam}还有{time}", officialTemplates, pools)
+		// 未设置模板池的对话不受影响
+		last := groups[len(groups)-1]
+		if !strings.HasSuffix(sendChatIDs(last.chats), "2") || !strings.HasPrefix(last.message, "原有") {
+			t.Errorf("day %d: chat without pool = %s %q", day, sendChatIDs(last.chats), last.message)
+		}
+		return groups[0].message
+	}
+
+	if got := messageOn(1); !strings.HasPrefix(got, "轮换2026年高考") {
+		t.Errorf("day 1 message = %q", got)
+	}
+	// 个人模板使用对话管理员的变量
+	if got := messageOn(2); !strings.HasPrefix(got, "三班：2026年高考") {
+		t.Errorf("day 2 message = %q", got)
+	}
+	// 模板已删除时回退到对话原有的模板
+	if got := messageOn(3); !strings.HasPrefix(got, "原有2026年高考") {
+		t.Errorf("day 3 message = %q", got)
+	}
+	if got := messageOn(4); !strings.HasPrefix(got, "轮换2026年高考") {
+		t.Errorf("day 4 message = %q", got)
+	}
+}
+
+func TestDailySendTask_GroupChatMessages_RotationInactive(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	chatRepo := memory.NewSendChatRepository()
+	_ = chatRepo.Create(ctx, &model.SendChat{ID: 1, ChatID: "-100123", TemplateID: 11})
+	_ = chatRepo.UpdateRotation(ctx, 1, 7, model.RotationRoundRobin, []*model.SendChatTemplate{
+		{ID: 101, SendChatID: 1, Source: model.ChatTemplateSourceOfficial, TemplateID: 12},
+	})
+
+	sendChatService := service.NewSendChatService(chatRepo)
+	task := NewDailySendTask(nil, nil, nil, nil, sendChatService, logger, 0).WithTemplateRotation()
+
+	loc := util.GetBJTLocation()
+	exams := []model.ExamDate{
+		{ExamDesc: "2026年中考", ExamBeginDate: time.Date(2026, 3, 8, 9, 0, 0, 0, loc), ExamEndDate: time.Date(2026, 3, 9, 17, 0, 0, 0, loc)},
+		{ExamDesc: "2026年高考", ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc), ExamEndDate: time.Date(2026, 6, 10, 17, 0, 0, 0, loc)},
+	}
+	officialTemplates := []model.OfficialTemplate{
+		{ID: 11, TemplateContent: "原有{exam}还有{time}", Active: true},
+		{ID: 12, TemplateContent: "冲刺{exam}还有{time}", Active: true, DaysBeforeExam: 10},
+	}
+
+	// 轮换到的模板只对临近的考试生效，其余考试回退到对话自己选用的模板而不是默认模板
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, loc)
+	chats, _ := sendChatService.GetAll(ctx)
+	pools, _ := sendChatService.GetAllTemplatePools(ctx)
+	groups := task.groupChatMessages(ctx, chats, exams, now, now, "距离{exam}还有{time}", officialTemplates, pools)
+	if len(groups) != 1 {
+		t.Fatalf("groupChatMessages() returned %d groups, want 1", len(groups))
+	}
+	message := groups[0].message
+	if !strings.Contains(message, "冲刺2026年中考") || !strings.Contains(message, "原有2026年高考") || strings.Contains(message, "距离") {
+		t.Errorf("message = %q", message)
+	}
+}
+
+func TestDailySendTask_GroupChatMessages_Calendar(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	task := NewDailySendTask(nil, nil, nil, nil, nil, logger, 0).WithCalendar(true)
+
+	// 2026-05-21 为农历四月初五、星期四、小满
+	loc := util.GetBJTLocation()
+	now := time.Date(2026, 5, 21, 9, 0, 0, 0, loc)
+	exams := []model.ExamDate{
+		{ExamDesc: "2026年高考", ExamBeginDate: time.Date(2026, 6, 7, 9, 0, 0, 0, loc), ExamEndDate: time.Date(2026, 6, 10, 17, 0, 0, 0, loc)},
+	}
+	officialTemplates := []model.OfficialTemplate{
+		{ID: 10, TemplateContent: "{lunar_date}{solar_term}，距离{exam}还有{time}", Active: true},
+	}
+	chats := []model.SendChat{
+		{ID: 1, ChatID: "-100123"},
+		{ID: 2, ChatID: "-100456", TemplateID: 10},
+	}
+
+	// 默认模板在开头附带日历行，已使用日历变量的模板只替换变量
+	groups := task.groupChatMessages(context.Background(), chats, exams, now, now, "距离{exam}还有{time}", officialTemplates, nil)
+	if len(groups) != 2 {
+		t.Fatalf("groupChatMessages() returned %d groups, want 2", len(groups))
+	}
+	if want := "今天是农历四月初五 星期四 小满\n距离2026年高考还有17天"; groups[0].message != want {
+		t.Errorf("default template message = %q, want %q", groups[0].message, want)
+	}
+	if want := "四月初五小满，距离2026年高考还有17天"; groups[1].message != want {
+		t.Errorf("official template message = %q, want %q", groups[1].message, want)
+	}
+
+	// 未开启附带时只替换日历变量
+	task.WithCalendar(false)
+	groups = task.groupChatMessages(context.Background(), chats, exams, now, now, "距离{exam}还有{time}", officialTemplates, nil)
+	if len(groups) != 2 || groups[0].message != "距离2026年高考还有17天" || !strings.HasPrefix(groups[1].message, "四月初五小满，") {
+		t.Errorf("groups without append = %+v", groups)
+	}
+}
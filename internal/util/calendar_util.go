@@ -0,0 +1,66 @@
+package util
+
+import (
+	"strings"
+	"time"
+)
+
+// 模板中的日历变量，按推送或查询时的日期（东八区）替换
+const (
+	// LunarDatePlaceholder 农历日期，如「四月廿一」
+	LunarDatePlaceholder = "{lunar_date}"
+	// SolarTermPlaceholder 节气，当天交节时为节气名称，否则替换为空
+	SolarTermPlaceholder = "{solar_term}"
+	// WeekdayPlaceholder 星期，如「星期三」
+	WeekdayPlaceholder = "{weekday}"
+)
+
+// weekdayNames 星期名称，按 time.Weekday 排列
+var weekdayNames = [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"}
+
+// FormatWeekday 返回 day 所在日期（东八区）的星期，如「星期三」
+func FormatWeekday(day time.Time) string {
+	return weekdayNames[day.In(chinaStandardTime).Weekday()]
+}
+
+// FormatLunarDate 返回 day 所在日期（东八区）的农历日期，如「四月廿一」，超出农历数据范围时返回空字符串
+func FormatLunarDate(day time.Time) string {
+	lunar, ok := SolarToLunar(day)
+	if !ok {
+		return ""
+	}
+	return lunar.String()
+}
+
+// ApplyCalendar 将 {lunar_date}、{solar_term} 和 {weekday} 替换为 day 所在日期的农历日期、节气和星期
+// 应在 GetCountDownString 之后、用户自定义变量之前调用，与其他内置变量一致
+func ApplyCalendar(text string, day time.Time) string {
+	// 农历和节气的计算量较大，仅在使用时计算
+	if strings.Contains(text, LunarDatePlaceholder) {
+		text = strings.ReplaceAll(text, LunarDatePlaceholder, FormatLunarDate(day))
+	}
+	if strings.Contains(text, SolarTermPlaceholder) {
+		text = strings.ReplaceAll(text, SolarTermPlaceholder, SolarTermOn(day))
+	}
+	return strings.ReplaceAll(text, WeekdayPlaceholder, FormatWeekday(day))
+}
+
+// HasCalendarPlaceholder 判断文本是否使用了日历变量
+func HasCalendarPlaceholder(text string) bool {
+	return strings.Contains(text, LunarDatePlaceholder) ||
+		strings.Contains(text, SolarTermPlaceholder) ||
+		strings.Contains(text, WeekdayPlaceholder)
+}
+
+// FormatCalendarLine 生成每日推送附带的日历行，如「今天是农历四月廿一 星期三 小满」，当天没有节气时省略节气
+func FormatCalendarLine(day time.Time) string {
+	parts := make([]string, 0, 3)
+	if lunar := FormatLunarDate(day); lunar != "" {
+		parts = append(parts, "农历"+lunar)
+	}
+	parts = append(parts, FormatWeekday(day))
+	if term := SolarTermOn(day); term != "" {
+		parts = append(parts, term)
+	}
+	return "今天是" + strings.Join(parts, " ")
+}
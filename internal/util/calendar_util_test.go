@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyCalendar(t *testing.T) {
+	// 2026-05-21 为农历四月初五、星期四、小满
+	day := time.Date(2026, 5, 21, 9, 0, 0, 0, GetBJTLocation())
+	tests := []struct {
+		text string
+		day  time.Time
+		want string
+	}{
+		{"今天是农历{lunar_date} {weekday} {solar_term}", day, "今天是农历四月初五 星期四 小满"},
+		{"{solar_term}", day.AddDate(0, 0, 1), ""},
+		{"{weekday}{weekday}", day, "星期四星期四"},
+		{"距离高考还有100天", day, "距离高考还有100天"},
+		// 超出农历数据范围时替换为空
+		{"{lunar_date}", time.Date(2102, 1, 1, 0, 0, 0, 0, GetBJTLocation()), ""},
+	}
+	for _, tt := range tests {
+		if got := ApplyCalendar(tt.text, tt.day); got != tt.want {
+			t.Errorf("ApplyCalendar(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestHasCalendarPlaceholder(t *testing.T) {
+	for text, want := range map[string]bool{
+		"{lunar_date}": true,
+		"{solar_term}": true,
+		"{weekday}":    true,
+		"{exam}{time}": false,
+	} {
+		if got := HasCalendarPlaceholder(text); got != want {
+			t.Errorf("HasCalendarPlaceholder(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestFormatCalendarLine(t *testing.T) {
+	day := time.Date(2026, 5, 21, 9, 0, 0, 0, GetBJTLocation())
+	if got, want := FormatCalendarLine(day), "今天是农历四月初五 星期四 小满"; got != want {
+		t.Errorf("FormatCalendarLine() = %q, want %q", got, want)
+	}
+	if got, want := FormatCalendarLine(day.AddDate(0, 0, 1)), "今天是农历四月初六 星期五"; got != want {
+		t.Errorf("FormatCalendarLine() = %q, want %q", got, want)
+	}
+}
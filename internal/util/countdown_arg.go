@@ -0,0 +1,125 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+	"github.com/herbertgao/gaokao_bot/pkg/constant"
+)
+
+// CountdownArgKind 倒计时参数类型
+type CountdownArgKind int
+
+const (
+	// CountdownArgNone 无参数：当前时间范围内的考试
+	CountdownArgNone CountdownArgKind = iota
+	// CountdownArgYear 考试年份，如 2026
+	CountdownArgYear
+	// CountdownArgDate 具体日期，如 2026-06-07、6月7日、100天后
+	CountdownArgDate
+	// CountdownArgName 考试简称，如 高考
+	CountdownArgName
+	// CountdownArgInvalid 无法识别的参数
+	CountdownArgInvalid
+)
+
+// MaxRelativeDays 相对日期（N天后）允许的最大天数
+const MaxRelativeDays = 3650
+
+// CountdownArg 解析后的倒计时参数
+type CountdownArg struct {
+	Kind CountdownArgKind
+	Year int
+	Date time.Time
+	Name string
+}
+
+var (
+	// fullDateRegex 完整日期：2026-06-07、2026/6/7、2026.6.7、2026年6月7日
+	fullDateRegex = regexp.MustCompile(`^(\d{4})\s*(?:[-/.]|年)\s*(\d{1,2})\s*(?:[-/.]|月)\s*(\d{1,2})\s*[日号]?$`)
+	// monthDayRegex 不含年份的日期：6月7日、6月7号
+	monthDayRegex = regexp.MustCompile(`^(\d{1,2})\s*月\s*(\d{1,2})\s*[日号]?$`)
+	// relativeDaysRegex 相对日期：100天后、100 天以后
+	relativeDaysRegex = regexp.MustCompile(`^(\d{1,5})\s*天(?:以)?后$`)
+	// digitsRegex 纯数字
+	digitsRegex = regexp.MustCompile(`^\d+$`)
+)
+
+// ParseCountdownArg 解析 /d、Guest、Inline Query 共用的倒计时参数
+// 日期按北京时间当天 0 点计算；不含年份的日期若今年已过则取明年
+func ParseCountdownArg(text string, now time.Time) CountdownArg {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return CountdownArg{Kind: CountdownArgNone}
+	}
+
+	now = now.In(GetBJTLocation())
+
+	if digitsRegex.MatchString(text) {
+		y, err := strconv.Atoi(text)
+		if err == nil && y >= constant.MinExamYear && y <= constant.MaxExamYear {
+			return CountdownArg{Kind: CountdownArgYear, Year: y}
+		}
+		return CountdownArg{Kind: CountdownArgInvalid}
+	}
+
+	if m := fullDateRegex.FindStringSubmatch(text); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		return newDateArg(year, m[2], m[3])
+	}
+
+	if m := monthDayRegex.FindStringSubmatch(text); m != nil {
+		arg := newDateArg(now.Year(), m[1], m[2])
+		if arg.Kind == CountdownArgDate && !arg.Date.After(now) {
+			arg = newDateArg(now.Year()+1, m[1], m[2])
+		}
+		return arg
+	}
+
+	if m := relativeDaysRegex.FindStringSubmatch(text); m != nil {
+		days, _ := strconv.Atoi(m[1])
+		if days <= 0 || days > MaxRelativeDays {
+			return CountdownArg{Kind: CountdownArgInvalid}
+		}
+		return CountdownArg{Kind: CountdownArgDate, Date: StartOfDay(now).AddDate(0, 0, days)}
+	}
+
+	return CountdownArg{Kind: CountdownArgName, Name: text}
+}
+
+// newDateArg 由年月日构造日期参数，日期不存在（如 2 月 30 日）时返回无效参数
+func newDateArg(year int, monthText, dayText string) CountdownArg {
+	month, _ := strconv.Atoi(monthText)
+	day, _ := strconv.Atoi(dayText)
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, GetBJTLocation())
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return CountdownArg{Kind: CountdownArgInvalid}
+	}
+
+	return CountdownArg{Kind: CountdownArgDate, Date: date}
+}
+
+// FormatChineseDate 格式化为中文日期，如 2026年6月7日
+func FormatChineseDate(t time.Time) string {
+	return fmt.Sprintf("%d年%d月%d日", t.Year(), int(t.Month()), t.Day())
+}
+
+// NewDateTargetExam 将任意日期包装为考试，以便复用模板与 GetCountDownString
+// 考试名称为中文日期，开始和结束时间均为当天 0 点
+func NewDateTargetExam(date time.Time) model.ExamDate {
+	desc := FormatChineseDate(date)
+	return model.ExamDate{
+		ExamYear:          date.Year(),
+		ExamDesc:          desc,
+		ShortDesc:         desc,
+		ExamBeginDate:     date,
+		ExamEndDate:       date,
+		ExamYearBeginDate: date,
+		ExamYearEndDate:   date,
+	}
+}
@@ -0,0 +1,82 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCountdownArg(t *testing.T) {
+	loc := GetBJTLocation()
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, loc)
+
+	tests := []struct {
+		input    string
+		wantKind CountdownArgKind
+		wantYear int
+		wantDate time.Time
+		wantName string
+	}{
+		{"", CountdownArgNone, 0, time.Time{}, ""},
+		{"  ", CountdownArgNone, 0, time.Time{}, ""},
+		{"2026", CountdownArgYear, 2026, time.Time{}, ""},
+		{"2017", CountdownArgInvalid, 0, time.Time{}, ""},
+		{"20999", CountdownArgInvalid, 0, time.Time{}, ""},
+		{"2026-06-07", CountdownArgDate, 0, time.Date(2026, 6, 7, 0, 0, 0, 0, loc), ""},
+		{"2026/6/7", CountdownArgDate, 0, time.Date(2026, 6, 7, 0, 0, 0, 0, loc), ""},
+		{"2026.6.7", CountdownArgDate, 0, time.Date(2026, 6, 7, 0, 0, 0, 0, loc), ""},
+		{"2026年6月7日", CountdownArgDate, 0, time.Date(2026, 6, 7, 0, 0, 0, 0, loc), ""},
+		{"2026-02-30", CountdownArgInvalid, 0, time.Time{}, ""},
+		{"6月7日", CountdownArgDate, 0, time.Date(2026, 6, 7, 0, 0, 0, 0, loc), ""},
+		{"6月7号", CountdownArgDate, 0, time.Date(2026, 6, 7, 0, 0, 0, 0, loc), ""},
+		{"1月1日", CountdownArgDate, 0, time.Date(2027, 1, 1, 0, 0, 0, 0, loc), ""},
+		{"3月1日", CountdownArgDate, 0, time.Date(2027, 3, 1, 0, 0, 0, 0, loc), ""},
+		{"13月1日", CountdownArgInvalid, 0, time.Time{}, ""},
+		{"100天后", CountdownArgDate, 0, time.Date(2026, 6, 9, 0, 0, 0, 0, loc), ""},
+		{"1 天以后", CountdownArgDate, 0, time.Date(2026, 3, 2, 0, 0, 0, 0, loc), ""},
+		{"0天后", CountdownArgInvalid, 0, time.Time{}, ""},
+		{"99999天后", CountdownArgInvalid, 0, time.Time{}, ""},
+		{"高考", CountdownArgName, 0, time.Time{}, "高考"},
+		{" 中考 ", CountdownArgName, 0, time.Time{}, "中考"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := ParseCountdownArg(tt.input, now)
+			if got.Kind != tt.wantKind {
+				t.Fatalf("ParseCountdownArg(%q).Kind = %v, want %v", tt.input, got.Kind, tt.wantKind)
+			}
+			if got.Year != tt.wantYear {
+				t.Errorf("ParseCountdownArg(%q).Year = %d, want %d", tt.input, got.Year, tt.wantYear)
+			}
+			if !got.Date.Equal(tt.wantDate) {
+				t.Errorf("ParseCountdownArg(%q).Date = %v, want %v", tt.input, got.Date, tt.wantDate)
+			}
+			if got.Name != tt.wantName {
+				t.Errorf("ParseCountdownArg(%q).Name = %q, want %q", tt.input, got.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestFormatChineseDate(t *testing.T) {
+	date := time.Date(2026, 6, 7, 0, 0, 0, 0, GetBJTLocation())
+	if got := FormatChineseDate(date); got != "2026年6月7日" {
+		t.Errorf("FormatChineseDate() = %s, want 2026年6月7日", got)
+	}
+}
+
+func TestNewDateTargetExam(t *testing.T) {
+	loc := GetBJTLocation()
+	date := time.Date(2026, 6, 7, 0, 0, 0, 0, loc)
+	now := time.Date(2026, 6, 5, 0, 0, 0, 0, loc)
+
+	exam := NewDateTargetExam(date)
+	if exam.ExamDesc != "2026年6月7日" || exam.ExamYear != 2026 {
+		t.Errorf("NewDateTargetExam() = %+v", exam)
+	}
+
+	got := GetCountDownString(&exam, "距离{exam}还有{time}", now)
+	if got != "距离2026年6月7日还有2天" {
+		t.Errorf("GetCountDownString() = %s, want 距离2026年6月7日还有2天", got)
+	}
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+// FormatExamTimeline 生成考后时间线文本
+// 每个事件一行，未到的事件显示剩余时间，已过的事件标记为已过
+//
+//	2026年高考后续安排：
+//	· 成绩公布 6月25日 12:00（还有3天2小时）
+//	· 志愿填报截止 7月5日 17:00（已过）
+func FormatExamTimeline(title string, events []model.ExamEvent, now time.Time) string {
+	if len(events) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s后续安排：", title)
+	for i := range events {
+		event := &events[i]
+		sb.WriteString("\n· ")
+		sb.WriteString(event.EventName)
+		sb.WriteString(" ")
+		sb.WriteString(formatEventDate(event.EventDate))
+
+		remaining := event.EventDate.Sub(now)
+		switch {
+		case remaining >= time.Minute:
+			fmt.Fprintf(&sb, "（还有%s）", FormatDuration(remaining.Truncate(time.Minute)))
+		case remaining > 0:
+			sb.WriteString("（即将开始）")
+		default:
+			sb.WriteString("（已过）")
+		}
+	}
+
+	return sb.String()
+}
+
+// formatEventDate 格式化事件时间，0 点的事件只显示日期
+func formatEventDate(t time.Time) string {
+	t = t.In(GetBJTLocation())
+	date := fmt.Sprintf("%d月%d日", int(t.Month()), t.Day())
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return date
+	}
+	return date + " " + t.Format("15:04")
+}
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestFormatExamTimeline(t *testing.T) {
+	loc := GetBJTLocation()
+	now := time.Date(2026, 6, 22, 9, 58, 30, 0, loc)
+	events := []model.ExamEvent{
+		{EventName: "成绩公布", EventDate: time.Date(2026, 6, 25, 12, 0, 0, 0, loc)},
+		{EventName: "志愿填报开始", EventDate: time.Date(2026, 6, 27, 0, 0, 0, 0, loc)},
+		{EventName: "模拟填报", EventDate: time.Date(2026, 6, 22, 9, 59, 0, 0, loc)},
+		{EventName: "报名确认", EventDate: time.Date(2026, 6, 20, 17, 0, 0, 0, loc)},
+	}
+
+	got := FormatExamTimeline("2026年高考", events, now)
+	want := "2026年高考后续安排：" +
+		"\n· 成绩公布 6月25日 12:00（还有3天2小时1分钟）" +
+		"\n· 志愿填报开始 6月27日（还有4天14小时1分钟）" +
+		"\n· 模拟填报 6月22日 09:59（即将开始）" +
+		"\n· 报名确认 6月20日 17:00（已过）"
+	if got != want {
+		t.Errorf("FormatExamTimeline() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatExamTimeline_Empty(t *testing.T) {
+	if got := FormatExamTimeline("2026年高考", nil, time.Now()); got != "" {
+		t.Errorf("FormatExamTimeline() = %q, want empty", got)
+	}
+}
@@ -53,4 +53,22 @@ func GetCountDownTime(exam *model.ExamDate, now time.Time) string {
 
 	duration := exam.ExamBeginDate.Sub(now)
 	return FormatDuration(duration)
-}
\ No newline at end of file
+}
+
+// FormatCountdownList 将多条倒计时拼接为列表，每条一行并以「· 」开头
+// 只有一条时原样返回，保持单个考试的输出不变
+func FormatCountdownList(messages []string) string {
+	if len(messages) == 1 {
+		return messages[0]
+	}
+
+	var sb strings.Builder
+	for i, message := range messages {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("· ")
+		sb.WriteString(message)
+	}
+	return sb.String()
+}
@@ -477,3 +477,23 @@ func TestCountDownScenarios(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatCountdownList(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		want     string
+	}{
+		{"empty", nil, ""},
+		{"single", []string{"距离高考还有1天"}, "距离高考还有1天"},
+		{"multiple", []string{"距离高考还有1天", "距离中考还有2天"}, "· 距离高考还有1天\n· 距离中考还有2天"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatCountdownList(tt.messages); got != tt.want {
+				t.Errorf("FormatCountdownList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// icalLineLimit RFC 5545 规定每行最多 75 个八位字节（不含 CRLF）
+	icalLineLimit = 75
+
+	// icalTimeLayout UTC 时间格式
+	icalTimeLayout = "20060102T150405Z"
+
+	// icalProdID 日历产品标识
+	icalProdID = "-//gaokao_bot//Exam Countdown//ZH"
+)
+
+// ICalEvent 日历事件
+type ICalEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	// Alarms 提醒时间（相对于开始时间提前的时长）
+	Alarms []time.Duration
+}
+
+// BuildICalendar 生成 RFC 5545 格式的日历（VCALENDAR）
+// name 为日历名称，now 用于 DTSTAMP
+func BuildICalendar(name string, events []ICalEvent, now time.Time) string {
+	var sb strings.Builder
+
+	writeICalLine(&sb, "BEGIN:VCALENDAR")
+	writeICalLine(&sb, "VERSION:2.0")
+	writeICalLine(&sb, "PRODID:"+icalProdID)
+	writeICalLine(&sb, "CALSCALE:GREGORIAN")
+	writeICalLine(&sb, "METHOD:PUBLISH")
+	writeICalLine(&sb, "X-WR-CALNAME:"+EscapeICalText(name))
+	writeICalLine(&sb, "X-WR-TIMEZONE:Asia/Shanghai")
+
+	stamp := formatICalTime(now)
+	for _, event := range events {
+		writeICalLine(&sb, "BEGIN:VEVENT")
+		writeICalLine(&sb, "UID:"+event.UID)
+		writeICalLine(&sb, "DTSTAMP:"+stamp)
+		writeICalLine(&sb, "DTSTART:"+formatICalTime(event.Start))
+		writeICalLine(&sb, "DTEND:"+formatICalTime(event.End))
+		writeICalLine(&sb, "SUMMARY:"+EscapeICalText(event.Summary))
+		if event.Description != "" {
+			writeICalLine(&sb, "DESCRIPTION:"+EscapeICalText(event.Description))
+		}
+		for _, before := range event.Alarms {
+			writeICalLine(&sb, "BEGIN:VALARM")
+			writeICalLine(&sb, "ACTION:DISPLAY")
+			writeICalLine(&sb, "TRIGGER:-"+FormatICalDuration(before))
+			writeICalLine(&sb, "DESCRIPTION:"+EscapeICalText(event.Summary))
+			writeICalLine(&sb, "END:VALARM")
+		}
+		writeICalLine(&sb, "END:VEVENT")
+	}
+
+	writeICalLine(&sb, "END:VCALENDAR")
+
+	return sb.String()
+}
+
+// EscapeICalText 按 RFC 5545 转义 TEXT 值中的反斜杠、分号、逗号和换行
+func EscapeICalText(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(s)
+}
+
+// FormatICalDuration 将时长格式化为 RFC 5545 DURATION（如 P1D、PT1H30M）
+// 负数按绝对值处理
+func FormatICalDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	totalSeconds := int64(d / time.Second)
+	days := totalSeconds / 86400
+	hours := (totalSeconds % 86400) / 3600
+	minutes := (totalSeconds % 3600) / 60
+	seconds := totalSeconds % 60
+
+	var sb strings.Builder
+	sb.WriteString("P")
+	if days > 0 {
+		fmt.Fprintf(&sb, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 || days == 0 {
+		sb.WriteString("T")
+		if hours > 0 {
+			fmt.Fprintf(&sb, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&sb, "%dM", minutes)
+		}
+		if seconds > 0 || (hours == 0 && minutes == 0) {
+			fmt.Fprintf(&sb, "%dS", seconds)
+		}
+	}
+
+	return sb.String()
+}
+
+// formatICalTime 格式化为 UTC 时间
+func formatICalTime(t time.Time) string {
+	return t.UTC().Format(icalTimeLayout)
+}
+
+// writeICalLine 写入一行内容，超过 75 个八位字节时按 RFC 5545 折行（续行以空格开头）
+// 折行位置不会拆开多字节 UTF-8 字符
+func writeICalLine(sb *strings.Builder, line string) {
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// 续行首个空格占用 1 个八位字节
+		limit = icalLineLimit - 1
+	}
+	sb.WriteString(line)
+	sb.WriteString("\r\n")
+}
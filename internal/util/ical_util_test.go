@@ -0,0 +1,123 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscapeICalText(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"高考", "高考"},
+		{"a,b;c", `a\,b\;c`},
+		{`C:\path`, `C:\\path`},
+		{"第一行\n第二行", `第一行\n第二行`},
+		{"windows\r\nline", `windows\nline`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeICalText(tt.input); got != tt.want {
+			t.Errorf("EscapeICalText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFormatICalDuration(t *testing.T) {
+	tests := []struct {
+		input time.Duration
+		want  string
+	}{
+		{24 * time.Hour, "P1D"},
+		{time.Hour, "PT1H"},
+		{90 * time.Minute, "PT1H30M"},
+		{7*24*time.Hour + 2*time.Hour, "P7DT2H"},
+		{0, "PT0S"},
+		{-time.Hour, "PT1H"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatICalDuration(tt.input); got != tt.want {
+			t.Errorf("FormatICalDuration(%v) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBuildICalendar(t *testing.T) {
+	loc := GetBJTLocation()
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, loc)
+	events := []ICalEvent{
+		{
+			UID:         "exam-1@gaokao_bot",
+			Summary:     "2026年高考",
+			Description: "祝考试顺利",
+			Start:       time.Date(2026, 6, 7, 9, 0, 0, 0, loc),
+			End:         time.Date(2026, 6, 10, 17, 0, 0, 0, loc),
+			Alarms:      []time.Duration{24 * time.Hour},
+		},
+	}
+
+	cal := BuildICalendar("高考倒计时", events, now)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"VERSION:2.0\r\n",
+		"X-WR-CALNAME:高考倒计时\r\n",
+		"UID:exam-1@gaokao_bot\r\n",
+		"DTSTAMP:20260101T000000Z\r\n",
+		"DTSTART:20260607T010000Z\r\n",
+		"DTEND:20260610T090000Z\r\n",
+		"SUMMARY:2026年高考\r\n",
+		"DESCRIPTION:祝考试顺利\r\n",
+		"BEGIN:VALARM\r\n",
+		"TRIGGER:-P1D\r\n",
+		"END:VEVENT\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(cal, want) {
+			t.Errorf("BuildICalendar() missing %q", want)
+		}
+	}
+}
+
+func TestBuildICalendar_NoEvents(t *testing.T) {
+	cal := BuildICalendar("empty", nil, time.Now())
+	if strings.Contains(cal, "BEGIN:VEVENT") {
+		t.Error("Expected no VEVENT for empty calendar")
+	}
+	if !strings.HasSuffix(cal, "END:VCALENDAR\r\n") {
+		t.Error("Expected calendar to end with END:VCALENDAR")
+	}
+}
+
+func TestWriteICalLine_Folding(t *testing.T) {
+	var sb strings.Builder
+	line := "SUMMARY:" + strings.Repeat("高考加油", 20)
+	writeICalLine(&sb, line)
+
+	folded := sb.String()
+	parts := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+	if len(parts) < 2 {
+		t.Fatalf("Expected line to be folded, got %d parts", len(parts))
+	}
+
+	var unfolded strings.Builder
+	for i, part := range parts {
+		if len(part) > icalLineLimit {
+			t.Errorf("Part %d has %d octets, exceeds %d", i, len(part), icalLineLimit)
+		}
+		if i > 0 {
+			if !strings.HasPrefix(part, " ") {
+				t.Errorf("Continuation line %d should start with a space", i)
+			}
+			part = part[1:]
+		}
+		unfolded.WriteString(part)
+	}
+
+	if unfolded.String() != line {
+		t.Error("Unfolded content does not match original line")
+	}
+}
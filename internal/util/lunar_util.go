@@ -0,0 +1,138 @@
+package util
+
+import (
+	"time"
+)
+
+// 农历数据支持的范围：1900 年正月初一（公历 1900-01-31）至 2100 年腊月
+const (
+	MinLunarYear = 1900
+	MaxLunarYear = 2100
+)
+
+// lunarBaseDate 农历 1900 年正月初一对应的公历日期
+var lunarBaseDate = time.Date(1900, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+// lunarYearInfo 1900-2100 年的农历数据（香港天文台公布的农历与公历对照），每年一项：
+//   - 第 0-3 位：闰月月份，0 表示无闰月
+//   - 第 4-15 位：正月至腊月的大小月，从高位到低位依次为正月到腊月，1 为大月（30 天），0 为小月（29 天）
+//   - 第 16 位：闰月为大月时为 1
+var lunarYearInfo = [MaxLunarYear - MinLunarYear + 1]uint32{
+	0x04bd8, 0x04ae0, 0x0a570, 0x054d5, 0x0d260, 0x0d950, 0x16554, 0x056a0, 0x09ad0, 0x055d2, // 1900-1909
+	0x04ae0, 0x0a5b6, 0x0a4d0, 0x0d250, 0x1d255, 0x0b540, 0x0d6a0, 0x0ada2, 0x095b0, 0x14977, // 1910-1919
+	0x04970, 0x0a4b0, 0x0b4b5, 0x06a50, 0x06d40, 0x1ab54, 0x02b60, 0x09570, 0x052f2, 0x04970, // 1920-1929
+	0x06566, 0x0d4a0, 0x0ea50, 0x16a95, 0x05ad0, 0x02b60, 0x186e3, 0x092e0, 0x1c8d7, 0x0c950, // 1930-1939
+	0x0d4a0, 0x1d8a6, 0x0b550, 0x056a0, 0x1a5b4, 0x025d0, 0x092d0, 0x0d2b2, 0x0a950, 0x0b557, // 1940-1949
+	0x06ca0, 0x0b550, 0x15355, 0x04da0, 0x0a5b0, 0x14573, 0x052b0, 0x0a9a8, 0x0e950, 0x06aa0, // 1950-1959
+	0x0aea6, 0x0ab50, 0x04b60, 0x0aae4, 0x0a570, 0x05260, 0x0f263, 0x0d950, 0x05b57, 0x056a0, // 1960-1969
+	0x096d0, 0x04dd5, 0x04ad0, 0x0a4d0, 0x0d4d4, 0x0d250, 0x0d558, 0x0b540, 0x0b6a0, 0x195a6, // 1970-1979
+	0x095b0, 0x049b0, 0x0a974, 0x0a4b0, 0x0b27a, 0x06a50, 0x06d40, 0x0af46, 0x0ab60, 0x09570, // 1980-1989
+	0x04af5, 0x04970, 0x064b0, 0x074a3, 0x0ea50, 0x06b58, 0x05ac0, 0x0ab60, 0x096d5, 0x092e0, // 1990-1999
+	0x0c960, 0x0d954, 0x0d4a0, 0x0da50, 0x07552, 0x056a0, 0x0abb7, 0x025d0, 0x092d0, 0x0cab5, // 2000-2009
+	0x0a950, 0x0b4a0, 0x0baa4, 0x0ad50, 0x055d9, 0x04ba0, 0x0a5b0, 0x15176, 0x052b0, 0x0a930, // 2010-2019
+	0x07954, 0x06aa0, 0x0ad50, 0x05b52, 0x04b60, 0x0a6e6, 0x0a4e0, 0x0d260, 0x0ea65, 0x0d530, // 2020-2029
+	0x05aa0, 0x076a3, 0x096d0, 0x04afb, 0x04ad0, 0x0a4d0, 0x1d0b6, 0x0d250, 0x0d520, 0x0dd45, // 2030-2039
+	0x0b5a0, 0x056d0, 0x055b2, 0x049b0, 0x0a577, 0x0a4b0, 0x0aa50, 0x1b255, 0x06d20, 0x0ada0, // 2040-2049
+	0x14b63, 0x09370, 0x049f8, 0x04970, 0x064b0, 0x168a6, 0x0ea50, 0x06b20, 0x1a6c4, 0x0aae0, // 2050-2059
+	0x092e0, 0x0d2e3, 0x0c960, 0x0d557, 0x0d4a0, 0x0da50, 0x05d55, 0x056a0, 0x0a6d0, 0x055d4, // 2060-2069
+	0x052d0, 0x0a9b8, 0x0a950, 0x0b4a0, 0x0b6a6, 0x0ad50, 0x055a0, 0x0aba4, 0x0a5b0, 0x052b0, // 2070-2079
+	0x0b273, 0x06930, 0x07337, 0x06aa0, 0x0ad50, 0x14b55, 0x04b60, 0x0a570, 0x054e4, 0x0d160, // 2080-2089
+	0x0e968, 0x0d520, 0x0daa0, 0x16aa6, 0x056d0, 0x04ae0, 0x0a9d4, 0x0a2d0, 0x0d150, 0x0f252, // 2090-2099
+	0x0d520, // 2100
+}
+
+// lunarMonthNames 农历月份名称
+var lunarMonthNames = [12]string{"正月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "冬月", "腊月"}
+
+// lunarDayNames 农历日期名称
+var lunarDayNames = [30]string{
+	"初一", "初二", "初三", "初四", "初五", "初六", "初七", "初八", "初九", "初十",
+	"十一", "十二", "十三", "十四", "十五", "十六", "十七", "十八", "十九", "二十",
+	"廿一", "廿二", "廿三", "廿四", "廿五", "廿六", "廿七", "廿八", "廿九", "三十",
+}
+
+// LunarDate 农历日期
+type LunarDate struct {
+	Year  int // 农历年（正月初一所在的公历年）
+	Month int // 月份，1-12
+	Day   int // 日期，1-30
+	Leap  bool
+}
+
+// String 格式化为「四月廿一」，闰月为「闰四月廿一」；零值返回空字符串
+func (d LunarDate) String() string {
+	if d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > 30 {
+		return ""
+	}
+	month := lunarMonthNames[d.Month-1]
+	if d.Leap {
+		month = "闰" + month
+	}
+	return month + lunarDayNames[d.Day-1]
+}
+
+// SolarToLunar 将 day 所在日期（东八区）转换为农历日期，超出 1900-2100 年农历范围时返回 false
+func SolarToLunar(day time.Time) (LunarDate, bool) {
+	year, month, date := day.In(chinaStandardTime).Date()
+	offset := int(time.Date(year, month, date, 0, 0, 0, 0, time.UTC).Sub(lunarBaseDate).Hours() / 24)
+	if offset < 0 {
+		return LunarDate{}, false
+	}
+
+	for lunarYear := MinLunarYear; lunarYear <= MaxLunarYear; lunarYear++ {
+		if days := lunarYearDays(lunarYear); offset >= days {
+			offset -= days
+			continue
+		}
+
+		leapMonth := lunarLeapMonth(lunarYear)
+		for m := 1; m <= 12; m++ {
+			if days := lunarMonthDays(lunarYear, m); offset >= days {
+				offset -= days
+			} else {
+				return LunarDate{Year: lunarYear, Month: m, Day: offset + 1}, true
+			}
+			if m == leapMonth {
+				if days := lunarLeapMonthDays(lunarYear); offset >= days {
+					offset -= days
+				} else {
+					return LunarDate{Year: lunarYear, Month: m, Day: offset + 1, Leap: true}, true
+				}
+			}
+		}
+	}
+	return LunarDate{}, false
+}
+
+// lunarYearDays 返回农历 year 年的天数
+func lunarYearDays(year int) int {
+	days := lunarLeapMonthDays(year)
+	for m := 1; m <= 12; m++ {
+		days += lunarMonthDays(year, m)
+	}
+	return days
+}
+
+// lunarMonthDays 返回农历 year 年 month 月（非闰月）的天数
+func lunarMonthDays(year, month int) int {
+	if lunarYearInfo[year-MinLunarYear]&(0x10000>>month) != 0 {
+		return 30
+	}
+	return 29
+}
+
+// lunarLeapMonth 返回农历 year 年的闰月月份，无闰月时返回 0
+func lunarLeapMonth(year int) int {
+	return int(lunarYearInfo[year-MinLunarYear] & 0xf)
+}
+
+// lunarLeapMonthDays 返回农历 year 年闰月的天数，无闰月时返回 0
+func lunarLeapMonthDays(year int) int {
+	if lunarLeapMonth(year) == 0 {
+		return 0
+	}
+	if lunarYearInfo[year-MinLunarYear]&0x10000 != 0 {
+		return 30
+	}
+	return 29
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSolarToLunar(t *testing.T) {
+	tests := []struct {
+		date string
+		want LunarDate
+		text string
+	}{
+		{"1900-01-31", LunarDate{Year: 1900, Month: 1, Day: 1}, "正月初一"},
+		{"1949-01-29", LunarDate{Year: 1949, Month: 1, Day: 1}, "正月初一"},
+		{"2000-02-05", LunarDate{Year: 2000, Month: 1, Day: 1}, "正月初一"},
+		{"2020-05-23", LunarDate{Year: 2020, Month: 4, Day: 1, Leap: true}, "闰四月初一"},
+		{"2025-01-28", LunarDate{Year: 2024, Month: 12, Day: 29}, "腊月廿九"},
+		{"2026-02-17", LunarDate{Year: 2026, Month: 1, Day: 1}, "正月初一"},
+		{"2026-09-25", LunarDate{Year: 2026, Month: 8, Day: 15}, "八月十五"},
+		// 2033 年闰十一月
+		{"2033-12-22", LunarDate{Year: 2033, Month: 11, Day: 1, Leap: true}, "闰冬月初一"},
+		{"2100-02-09", LunarDate{Year: 2100, Month: 1, Day: 1}, "正月初一"},
+		{"2101-01-28", LunarDate{Year: 2100, Month: 12, Day: 29}, "腊月廿九"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			day, _ := time.ParseInLocation("2006-01-02", tt.date, chinaStandardTime)
+			got, ok := SolarToLunar(day)
+			if !ok || got != tt.want {
+				t.Fatalf("SolarToLunar(%s) = %+v, %v, want %+v", tt.date, got, ok, tt.want)
+			}
+			if got.String() != tt.text {
+				t.Errorf("String() = %q, want %q", got.String(), tt.text)
+			}
+		})
+	}
+}
+
+func TestSolarToLunar_TimeZone(t *testing.T) {
+	// 按东八区日期转换：UTC 2026-02-16 16:00 为北京时间正月初一 0 点
+	got, ok := SolarToLunar(time.Date(2026, 2, 16, 16, 0, 0, 0, time.UTC))
+	if !ok || got != (LunarDate{Year: 2026, Month: 1, Day: 1}) {
+		t.Errorf("SolarToLunar() = %+v, %v, want 2026 正月初一", got, ok)
+	}
+}
+
+func TestSolarToLunar_OutOfRange(t *testing.T) {
+	for _, date := range []string{"1900-01-30", "2101-01-29"} {
+		day, _ := time.ParseInLocation("2006-01-02", date, chinaStandardTime)
+		if got, ok := SolarToLunar(day); ok {
+			t.Errorf("SolarToLunar(%s) = %+v, want out of range", date, got)
+		}
+	}
+	if got := (LunarDate{}).String(); got != "" {
+		t.Errorf("zero LunarDate String() = %q, want empty", got)
+	}
+}
+
+// TestLunarYearInfo 每年 12 或 13 个月，天数在 353-385 之间，且农历年首尾相接
+func TestLunarYearInfo(t *testing.T) {
+	day := lunarBaseDate
+	for year := MinLunarYear; year <= MaxLunarYear; year++ {
+		got, ok := SolarToLunar(day.Add(-8 * time.Hour))
+		if !ok || got != (LunarDate{Year: year, Month: 1, Day: 1}) {
+			t.Fatalf("year %d begins at %s, SolarToLunar() = %+v, %v", year, day.Format("2006-01-02"), got, ok)
+		}
+
+		days := lunarYearDays(year)
+		if leap := lunarLeapMonth(year); leap > 12 || (leap == 0 && (days < 353 || days > 355)) || (leap != 0 && (days < 383 || days > 385)) {
+			t.Errorf("year %d: leap month %d, %d days", year, leap, days)
+		}
+		day = day.AddDate(0, 0, days)
+	}
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+// QuotePlaceholder 模板中的励志语录变量
+const QuotePlaceholder = "{quote}"
+
+// FormatQuote 格式化语录，有出处时附在内容之后，如「不积跬步，无以至千里 ——荀子」
+func FormatQuote(quote *model.Quote) string {
+	if quote.Source == "" {
+		return quote.Content
+	}
+	return quote.Content + " ——" + quote.Source
+}
+
+// ApplyQuote 将 {quote} 替换为语录文本，quote 为空（语录库为空或未启用）时替换为空
+// 应在其他变量替换之后调用，语录中的 {exam}、{var:名称} 等不会被再次替换
+func ApplyQuote(text, quote string) string {
+	return strings.ReplaceAll(text, QuotePlaceholder, quote)
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/herbertgao/gaokao_bot/internal/model"
+)
+
+func TestFormatQuote(t *testing.T) {
+	tests := []struct {
+		quote model.Quote
+		want  string
+	}{
+		{model.Quote{Content: "天道酬勤"}, "天道酬勤"},
+		{model.Quote{Content: "不积跬步，无以至千里", Source: "荀子"}, "不积跬步，无以至千里 ——荀子"},
+	}
+	for _, tt := range tests {
+		if got := FormatQuote(&tt.quote); got != tt.want {
+			t.Errorf("FormatQuote(%+v) = %q, want %q", tt.quote, got, tt.want)
+		}
+	}
+}
+
+func TestApplyQuote(t *testing.T) {
+	tests := []struct {
+		text  string
+		quote string
+		want  string
+	}{
+		{"距离高考还有100天\n{quote}", "天道酬勤", "距离高考还有100天\n天道酬勤"},
+		{"距离高考还有100天{quote}", "", "距离高考还有100天"},
+		{"距离高考还有100天", "天道酬勤", "距离高考还有100天"},
+		// 语录中的变量不会被再次替换
+		{"{quote}", "{exam}{quote}", "{exam}{quote}"},
+	}
+	for _, tt := range tests {
+		if got := ApplyQuote(tt.text, tt.quote); got != tt.want {
+			t.Errorf("ApplyQuote(%q, %q) = %q, want %q", tt.text, tt.quote, got, tt.want)
+		}
+	}
+}
@@ -3,9 +3,10 @@ package util
 import "regexp"
 
 var (
-	commandRegex      = regexp.MustCompile(`^/[a-zA-Z]+`)
-	templateNameRegex = regexp.MustCompile(`【(.+?)】`)
-	templateIDRegex   = regexp.MustCompile(`/rm_(\d+)`)
+	commandRegex        = regexp.MustCompile(`^/[a-zA-Z]+`)
+	templateNameRegex   = regexp.MustCompile(`【(.+?)】`)
+	templateIDRegex     = regexp.MustCompile(`/rm_(\d+)`)
+	templateEditIDRegex = regexp.MustCompile(`/edit_(\d+)`)
 )
 
 // IsMatchCommand 检查是否匹配命令格式
@@ -31,6 +32,15 @@ func ExtractTemplateID(text string) string {
 	return ""
 }
 
+// ExtractEditTemplateID 提取待编辑的模板 ID（/edit_123）
+func ExtractEditTemplateID(text string) string {
+	matches := templateEditIDRegex.FindStringSubmatch(text)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
+
 // Get 通用正则匹配和分组提取
 func Get(pattern, text string, group int) string {
 	re := regexp.MustCompile(pattern)
@@ -221,6 +221,28 @@ func TestExtractTemplateID(t *testing.T) {
 	}
 }
 
+func TestExtractEditTemplateID(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "Simple template ID", text: "/edit_123", want: "123"},
+		{name: "With bot username", text: "/edit_456@gaokao_bot", want: "456"},
+		{name: "Delete command", text: "/rm_123", want: ""},
+		{name: "No ID", text: "/edit_", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractEditTemplateID(tt.text)
+			if got != tt.want {
+				t.Errorf("ExtractEditTemplateID(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGet(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,306 @@
+package util
+
+import (
+	"math"
+	"time"
+)
+
+// chinaStandardTime 农历和节气使用的东八区标准时间，不受历史上的地方时和夏令时影响
+var chinaStandardTime = time.FixedZone("CST", 8*3600)
+
+// SolarTermNames 二十四节气名称，从春分（太阳视黄经 0°）开始，每 15° 一个
+var SolarTermNames = [24]string{
+	"春分", "清明", "谷雨", "立夏", "小满", "芒种",
+	"夏至", "小暑", "大暑", "立秋", "处暑", "白露",
+	"秋分", "寒露", "霜降", "立冬", "小雪", "大雪",
+	"冬至", "小寒", "大寒", "立春", "雨水", "惊蛰",
+}
+
+// SolarTermOn 返回 day 所在日期（东八区）交节的节气名称，当天没有节气时返回空字符串
+// 通过比较当天 0 点和次日 0 点的太阳视黄经判断当天是否跨过节气
+func SolarTermOn(day time.Time) string {
+	index := solarTermIndexOn(day)
+	if index < 0 {
+		return ""
+	}
+	return SolarTermNames[index]
+}
+
+// solarTermIndexOn 返回 day 所在日期（东八区）交节的节气在 SolarTermNames 中的下标，没有节气时返回 -1
+func solarTermIndexOn(day time.Time) int {
+	start := StartOfDay(day.In(chinaStandardTime))
+	begin := sunApparentLongitude(start)
+	end := sunApparentLongitude(start.AddDate(0, 0, 1))
+	if end < begin {
+		end += 360 // 跨过春分点
+	}
+	next := math.Floor(begin/15) + 1
+	if next*15 > end {
+		return -1
+	}
+	return int(next) % 24
+}
+
+// SolarTermTime 返回 year 年（公历）中节气 index（SolarTermNames 下标）的交节时刻（东八区）
+func SolarTermTime(year, index int) time.Time {
+	// 春分约在 3 月 20 日，之后每个节气约 15.2 天
+	estimate := time.Date(year, time.March, 20, 12, 0, 0, 0, chinaStandardTime).
+		Add(time.Duration(float64(index) * 15.2184 * float64(24*time.Hour)))
+	if index >= 19 {
+		estimate = estimate.AddDate(-1, 0, 0) // 小寒至惊蛰在年初
+	}
+
+	target := float64(index) * 15
+	for i := 0; i < 10; i++ {
+		diff := math.Remainder(target-sunApparentLongitude(estimate), 360)
+		if math.Abs(diff) < 1e-7 {
+			break
+		}
+		// 太阳每天约移动 360/365.2422 度
+		estimate = estimate.Add(time.Duration(diff * 365.2422 / 360 * float64(24*time.Hour)))
+	}
+	return estimate.In(chinaStandardTime).Round(time.Second)
+}
+
+// sunApparentLongitude 计算 t 时刻太阳的视黄经（度，0-360）
+// 地球日心黄经采用截断的 VSOP87 理论（Meeus《天文算法》附录），
+// 并计入 FK5 修正、章动和光行差，精度约 1 角秒，对应交节时刻误差约 1 分钟
+func sunApparentLongitude(t time.Time) float64 {
+	tau := (julianEphemerisDay(t) - 2451545.0) / 365250 // 自 J2000 起的儒略千年数
+
+	var longitude, tauPower float64 = 0, 1
+	for _, series := range earthLongitudeSeries {
+		var sum float64
+		for _, term := range series {
+			sum += term[0] * math.Cos(term[1]+term[2]*tau)
+		}
+		longitude += sum * tauPower
+		tauPower *= tau
+	}
+	var radius float64
+	tauPower = 1
+	for _, series := range earthRadiusSeries {
+		var sum float64
+		for _, term := range series {
+			sum += term[0] * math.Cos(term[1]+term[2]*tau)
+		}
+		radius += sum * tauPower
+		tauPower *= tau
+	}
+	radius /= 1e8
+
+	// 地心黄经 = 地球日心黄经 + 180°
+	degrees := longitude/1e8*180/math.Pi + 180
+	// 转换到 FK5 系统
+	degrees -= 0.09033 / 3600
+
+	T := tau * 10 // 儒略世纪数
+	omega := degToRad(125.04452 - 1934.136261*T)
+	sunMean := degToRad(280.4665 + 36000.7698*T)
+	moonMean := degToRad(218.3165 + 481267.8813*T)
+	nutation := -17.20*math.Sin(omega) - 1.32*math.Sin(2*sunMean) - 0.23*math.Sin(2*moonMean) + 0.21*math.Sin(2*omega)
+	aberration := -20.4898 / radius
+
+	degrees += (nutation + aberration) / 3600
+	return math.Mod(math.Mod(degrees, 360)+360, 360)
+}
+
+// julianEphemerisDay 返回 t 对应的儒略历书日（力学时），已计入 ΔT
+func julianEphemerisDay(t time.Time) float64 {
+	jd := float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+	return jd + deltaT(t)/86400
+}
+
+// deltaT 估算 t 所在年份的 ΔT（力学时与世界时之差，秒）
+// 采用 Espenak 和 Meeus 的多项式拟合，适用于 1900-2150 年
+func deltaT(t time.Time) float64 {
+	y := float64(t.Year()) + (float64(t.YearDay())-0.5)/365.25
+	switch {
+	case y < 1920:
+		u := y - 1900
+		return -2.79 + 1.494119*u - 0.0598939*u*u + 0.0061966*u*u*u - 0.000197*u*u*u*u
+	case y < 1941:
+		u := y - 1920
+		return 21.20 + 0.84493*u - 0.076100*u*u + 0.0020936*u*u*u
+	case y < 1961:
+		u := y - 1950
+		return 29.07 + 0.407*u - u*u/233 + u*u*u/2547
+	case y < 1986:
+		u := y - 1975
+		return 45.45 + 1.067*u - u*u/260 - u*u*u/718
+	case y < 2005:
+		u := y - 2000
+		return 63.86 + 0.3345*u - 0.060374*u*u + 0.0017275*u*u*u + 0.000651814*u*u*u*u + 0.00002373599*u*u*u*u*u
+	case y < 2050:
+		u := y - 2000
+		return 62.92 + 0.32217*u + 0.005589*u*u
+	default:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-y)
+	}
+}
+
+func degToRad(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// earthLongitudeSeries 地球日心黄经的 VSOP87 级数 L0-L5，每项为 {A, B, C}，值为 A·cos(B + C·τ)（1e-8 弧度）
+var earthLongitudeSeries = [][][3]float64{
+	{
+		{175347046, 0, 0},
+		{3341656, 4.6692568, 6283.0758500},
+		{34894, 4.62610, 12566.15170},
+		{3497, 2.7441, 5753.3849},
+		{3418, 2.8289, 3.5231},
+		{3136, 3.6277, 77713.7715},
+		{2676, 4.4181, 7860.4194},
+		{2343, 6.1352, 3930.2097},
+		{1324, 0.7425, 11506.7698},
+		{1273, 2.0371, 529.6910},
+		{1199, 1.1096, 1577.3435},
+		{990, 5.233, 5884.927},
+		{902, 2.045, 26.298},
+		{857, 3.508, 398.149},
+		{780, 1.179, 5223.694},
+		{753, 2.533, 5507.553},
+		{505, 4.583, 18849.228},
+		{492, 4.205, 775.523},
+		{357, 2.920, 0.067},
+		{317, 5.849, 11790.629},
+		{284, 1.899, 796.298},
+		{271, 0.315, 10977.079},
+		{243, 0.345, 5486.778},
+		{206, 4.806, 2544.314},
+		{205, 1.869, 5573.143},
+		{202, 2.458, 6069.777},
+		{156, 0.833, 213.299},
+		{132, 3.411, 2942.463},
+		{126, 1.083, 20.775},
+		{115, 0.645, 0.980},
+		{103, 0.636, 4694.003},
+		{102, 0.976, 15720.839},
+		{102, 4.267, 7.114},
+		{99, 6.21, 2146.17},
+		{98, 0.68, 155.42},
+		{86, 5.98, 161000.69},
+		{85, 1.30, 6275.96},
+		{85, 3.67, 71430.70},
+		{80, 1.81, 17260.15},
+		{79, 3.04, 12036.46},
+		{75, 1.76, 5088.63},
+		{74, 3.50, 3154.69},
+		{74, 4.68, 801.82},
+		{70, 0.83, 9437.76},
+		{62, 3.98, 8827.39},
+		{61, 1.82, 7084.90},
+		{57, 2.78, 6286.60},
+		{56, 4.39, 14143.50},
+		{56, 3.47, 6279.55},
+		{52, 0.19, 12139.55},
+		{52, 1.33, 1748.02},
+		{51, 0.28, 5856.48},
+		{49, 0.49, 1194.45},
+		{41, 5.37, 8429.24},
+		{41, 2.40, 19651.05},
+		{39, 6.17, 10447.39},
+		{37, 6.04, 10213.29},
+		{37, 2.57, 1059.38},
+		{36, 1.71, 2352.87},
+		{36, 1.78, 6812.77},
+		{33, 0.59, 17789.85},
+		{30, 0.44, 83996.85},
+		{30, 2.74, 1349.87},
+		{25, 3.16, 4690.48},
+	},
+	{
+		{628331966747, 0, 0},
+		{206059, 2.678235, 6283.075850},
+		{4303, 2.6351, 12566.1517},
+		{425, 1.590, 3.523},
+		{119, 5.796, 26.298},
+		{109, 2.966, 1577.344},
+		{93, 2.59, 18849.23},
+		{72, 1.14, 529.69},
+		{68, 1.87, 398.15},
+		{67, 4.41, 5507.55},
+		{59, 2.89, 5223.69},
+		{56, 2.17, 155.42},
+		{45, 0.40, 796.30},
+		{36, 0.47, 775.52},
+		{29, 2.65, 7.11},
+		{21, 5.34, 0.98},
+		{19, 1.85, 5486.78},
+		{19, 4.97, 213.30},
+		{17, 2.99, 6275.96},
+		{16, 0.03, 2544.31},
+		{16, 1.43, 2146.17},
+		{15, 1.21, 10977.08},
+		{12, 2.83, 1748.02},
+		{12, 3.26, 5088.63},
+		{12, 5.27, 1194.45},
+		{12, 2.08, 4694.00},
+		{11, 0.77, 553.57},
+		{10, 1.30, 6286.60},
+		{10, 4.24, 1349.87},
+		{9, 2.70, 242.73},
+		{9, 5.64, 951.72},
+		{8, 5.30, 2352.87},
+		{6, 2.65, 9437.76},
+		{6, 4.67, 4690.48},
+	},
+	{
+		{52919, 0, 0},
+		{8720, 1.0721, 6283.0758},
+		{309, 0.867, 12566.152},
+		{27, 0.05, 3.52},
+		{16, 5.19, 26.30},
+		{16, 3.68, 155.42},
+		{10, 0.76, 18849.23},
+		{9, 2.06, 77713.77},
+		{7, 0.83, 775.52},
+		{5, 4.66, 1577.34},
+		{4, 1.03, 7.11},
+		{4, 3.44, 5573.14},
+		{3, 5.14, 796.30},
+		{3, 6.05, 5507.55},
+		{3, 1.19, 242.73},
+		{3, 6.12, 529.69},
+		{3, 0.31, 398.15},
+		{3, 2.28, 553.57},
+		{2, 4.38, 5223.69},
+		{2, 3.75, 0.98},
+	},
+	{
+		{289, 5.844, 6283.076},
+		{35, 0, 0},
+		{17, 5.49, 12566.15},
+		{3, 5.20, 155.42},
+		{1, 4.72, 3.52},
+		{1, 5.30, 18849.23},
+		{1, 5.97, 242.73},
+	},
+	{
+		{114, 3.142, 0},
+		{8, 4.13, 6283.08},
+		{1, 3.84, 12566.15},
+	},
+	{
+		{1, 3.14, 0},
+	},
+}
+
+// earthRadiusSeries 日地距离的 VSOP87 级数 R0-R1 主要项（1e-8 天文单位），仅用于光行差
+var earthRadiusSeries = [][][3]float64{
+	{
+		{100013989, 0, 0},
+		{1670700, 3.0984635, 6283.0758500},
+		{13956, 3.05525, 12566.15170},
+		{3084, 5.1985, 77713.7715},
+		{1628, 1.1739, 5753.3849},
+		{1576, 2.8469, 7860.4194},
+	},
+	{
+		{103019, 1.107490, 6283.075850},
+		{1721, 1.0644, 12566.1517},
+	},
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSolarTermTime(t *testing.T) {
+	tests := []struct {
+		year  int
+		index int
+		want  time.Time
+	}{
+		{2000, 0, time.Date(2000, 3, 20, 15, 35, 0, 0, chinaStandardTime)},   // 春分
+		{2000, 18, time.Date(2000, 12, 21, 21, 37, 0, 0, chinaStandardTime)}, // 冬至
+		{2024, 0, time.Date(2024, 3, 20, 11, 6, 0, 0, chinaStandardTime)},    // 春分
+		{2024, 18, time.Date(2024, 12, 21, 17, 21, 0, 0, chinaStandardTime)}, // 冬至
+		{2025, 21, time.Date(2025, 2, 3, 22, 10, 0, 0, chinaStandardTime)},   // 立春
+		{2026, 0, time.Date(2026, 3, 20, 22, 46, 0, 0, chinaStandardTime)},   // 春分
+		{1962, 6, time.Date(1962, 6, 22, 5, 24, 0, 0, chinaStandardTime)},    // 夏至
+	}
+	for _, tt := range tests {
+		got := SolarTermTime(tt.year, tt.index)
+		// 与天文台公布的时刻（精确到分钟）相差不超过 2 分钟
+		if diff := got.Sub(tt.want); math.Abs(diff.Minutes()) > 2 {
+			t.Errorf("SolarTermTime(%d, %s) = %v, want %v", tt.year, SolarTermNames[tt.index], got, tt.want)
+		}
+	}
+}
+
+func TestSolarTermOn(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2023-04-20", "谷雨"},
+		{"2024-12-21", "冬至"},
+		{"2025-02-03", "立春"},
+		{"2026-03-20", "春分"},
+		{"2026-05-21", "小满"},
+		{"2026-03-21", ""},
+		{"2026-05-08", ""},
+	}
+	for _, tt := range tests {
+		day, _ := time.ParseInLocation("2006-01-02", tt.date, chinaStandardTime)
+		if got := SolarTermOn(day.Add(12 * time.Hour)); got != tt.want {
+			t.Errorf("SolarTermOn(%s) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
+
+// TestSolarTermOn_EveryYear 1900-2100 年每年恰好 24 个节气，且按顺序出现在各自的交节日
+func TestSolarTermOn_EveryYear(t *testing.T) {
+	for year := MinLunarYear; year <= MaxLunarYear; year += 20 {
+		count := 0
+		for day := time.Date(year, 1, 1, 0, 0, 0, 0, chinaStandardTime); day.Year() == year; day = day.AddDate(0, 0, 1) {
+			if SolarTermOn(day) != "" {
+				count++
+			}
+		}
+		if count != 24 {
+			t.Errorf("year %d has %d solar terms, want 24", year, count)
+		}
+
+		for index := range SolarTermNames {
+			at := SolarTermTime(year, index)
+			if got := SolarTermOn(at); got != SolarTermNames[index] {
+				t.Errorf("SolarTermOn(%v) = %q, want %q", at, got, SolarTermNames[index])
+			}
+		}
+	}
+}
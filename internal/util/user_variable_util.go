@@ -0,0 +1,37 @@
+package util
+
+import "regexp"
+
+// UserVariablePrefix 用户自定义变量的前缀，模板中以 {var:名称} 引用
+const UserVariablePrefix = "var:"
+
+// userVariableRegex 匹配模板中的用户自定义变量，捕获变量名称
+var userVariableRegex = regexp.MustCompile(`\{var:([^{}\s]*)\}`)
+
+// UserVariableRefs 返回模板引用的用户自定义变量名称，按首次出现的顺序去重
+func UserVariableRefs(content string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range userVariableRegex.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// ApplyUserVariables 将 {var:名称} 替换为用户定义的值，未定义的变量原样保留
+// 应在内置变量替换之后调用，变量值中的 {exam}、{time} 等不会被再次替换
+func ApplyUserVariables(text string, values map[string]string) string {
+	if len(values) == 0 {
+		return text
+	}
+	return userVariableRegex.ReplaceAllStringFunc(text, func(match string) string {
+		name := userVariableRegex.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserVariableRefs(t *testing.T) {
+	got := UserVariableRefs("{var:name}，距离{exam}还有{time}，目标{var:goal}，{var:name}加油{var:}")
+	want := []string{"name", "goal", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UserVariableRefs() = %q, want %q", got, want)
+	}
+
+	if got := UserVariableRefs("距离{exam}还有{time}"); len(got) != 0 {
+		t.Errorf("UserVariableRefs() without variables = %q", got)
+	}
+}
+
+func TestApplyUserVariables(t *testing.T) {
+	values := map[string]string{"name": "小明", "goal": "{time}"}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"{var:name}，还有 100 天", "小明，还有 100 天"},
+		// 变量值原样输出，不会再次替换
+		{"目标{var:goal}", "目标{time}"},
+		// 未定义的变量原样保留
+		{"{var:school}{var:name}", "{var:school}小明"},
+		{"{var:name }", "{var:name }"},
+	}
+	for _, tt := range tests {
+		if got := ApplyUserVariables(tt.text, values); got != tt.want {
+			t.Errorf("ApplyUserVariables(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	if got := ApplyUserVariables("{var:name}", nil); got != "{var:name}" {
+		t.Errorf("ApplyUserVariables() with no values = %q", got)
+	}
+}
@@ -12,4 +12,28 @@ const (
 
 	// TemplateCommand 模板配置命令
 	TemplateCommand = "template"
+
+	// CalendarCommand 日历订阅命令
+	CalendarCommand = "calendar"
+
+	// NewTemplateCommand 创建模板命令
+	NewTemplateCommand = "newtemplate"
+
+	// TemplatesCommand 模板列表命令
+	TemplatesCommand = "templates"
+
+	// CancelCommand 取消当前对话命令
+	CancelCommand = "cancel"
+
+	// DeleteTemplateCommandPrefix 删除模板命令前缀，如 /rm_123
+	DeleteTemplateCommandPrefix = "rm_"
+
+	// EditTemplateCommandPrefix 编辑模板命令前缀，如 /edit_123
+	EditTemplateCommandPrefix = "edit_"
+
+	// StartCommand 开始命令，分享链接通过 /start tpl_<分享码> 打开
+	StartCommand = "start"
+
+	// CopyTemplateCommandPrefix 复制分享模板命令前缀，如 /copy_<分享码>
+	CopyTemplateCommandPrefix = "copy_"
 )
\ No newline at end of file
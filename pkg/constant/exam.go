@@ -7,3 +7,31 @@ const (
 	// MaxExamYear 支持的最大考试年份（预计最远规划年份）
 	MaxExamYear = 2100
 )
+
+const (
+	// ExamKindGaokao 考试类型：普通高考
+	// 与年份共同构成考试日历的唯一键
+	ExamKindGaokao = "gaokao"
+)
+
+const (
+	// ExamEventScoreRelease 考后事件：成绩公布
+	ExamEventScoreRelease = "score_release"
+
+	// ExamEventApplicationStart 考后事件：志愿填报开始
+	ExamEventApplicationStart = "application_start"
+
+	// ExamEventApplicationEnd 考后事件：志愿填报截止
+	ExamEventApplicationEnd = "application_end"
+
+	// ExamEventAdmissionQuery 考后事件：录取查询
+	ExamEventAdmissionQuery = "admission_query"
+)
+
+// ExamEventNames 考后事件类型的默认名称
+var ExamEventNames = map[string]string{
+	ExamEventScoreRelease:     "成绩公布",
+	ExamEventApplicationStart: "志愿填报开始",
+	ExamEventApplicationEnd:   "志愿填报截止",
+	ExamEventAdmissionQuery:   "录取查询",
+}
@@ -0,0 +1,22 @@
+package constant
+
+// Provinces 支持的省级行政区简称（用于考试地区与日历订阅）
+// 考试记录的地区为空表示全国统一安排
+var Provinces = []string{
+	"北京", "天津", "河北", "山西", "内蒙古",
+	"辽宁", "吉林", "黑龙江",
+	"上海", "江苏", "浙江", "安徽", "福建", "江西", "山东",
+	"河南", "湖北", "湖南", "广东", "广西", "海南",
+	"重庆", "四川", "贵州", "云南", "西藏",
+	"陕西", "甘肃", "青海", "宁夏", "新疆",
+}
+
+// IsValidProvince 判断是否为支持的省级行政区简称
+func IsValidProvince(name string) bool {
+	for _, p := range Provinces {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}